@@ -82,6 +82,8 @@ type AlertNode struct {
 
 	levelResets  []stateful.Expression
 	lrScopePools []stateful.ScopePool
+
+	states groupSnapshotter[alertGroupState, *alertState]
 }
 
 // Create a new  AlertNode which caches the most recent item and exposes it over the HTTP API.
@@ -649,14 +651,14 @@ func newAlertNode(et *ExecutingTask, n *pipeline.AlertNode, d NodeDiagnostic) (a
 		}
 
 		an.levels[alert.Info] = statefulExpression
-		an.scopePools[alert.Info] = stateful.NewScopePool(ast.FindReferenceVariables(n.Info.Expression))
+		an.scopePools[alert.Info] = et.tm.newScopePool(ast.FindReferenceVariables(n.Info.Expression))
 		if n.InfoReset != nil {
 			lstatefulExpression, lexpressionCompileError := stateful.NewExpression(n.InfoReset.Expression)
 			if lexpressionCompileError != nil {
 				return nil, fmt.Errorf("Failed to compile stateful expression for infoReset: %s", lexpressionCompileError)
 			}
 			an.levelResets[alert.Info] = lstatefulExpression
-			an.lrScopePools[alert.Info] = stateful.NewScopePool(ast.FindReferenceVariables(n.InfoReset.Expression))
+			an.lrScopePools[alert.Info] = et.tm.newScopePool(ast.FindReferenceVariables(n.InfoReset.Expression))
 		}
 	}
 
@@ -666,14 +668,14 @@ func newAlertNode(et *ExecutingTask, n *pipeline.AlertNode, d NodeDiagnostic) (a
 			return nil, fmt.Errorf("Failed to compile stateful expression for warn: %s", expressionCompileError)
 		}
 		an.levels[alert.Warning] = statefulExpression
-		an.scopePools[alert.Warning] = stateful.NewScopePool(ast.FindReferenceVariables(n.Warn.Expression))
+		an.scopePools[alert.Warning] = et.tm.newScopePool(ast.FindReferenceVariables(n.Warn.Expression))
 		if n.WarnReset != nil {
 			lstatefulExpression, lexpressionCompileError := stateful.NewExpression(n.WarnReset.Expression)
 			if lexpressionCompileError != nil {
 				return nil, fmt.Errorf("Failed to compile stateful expression for warnReset: %s", lexpressionCompileError)
 			}
 			an.levelResets[alert.Warning] = lstatefulExpression
-			an.lrScopePools[alert.Warning] = stateful.NewScopePool(ast.FindReferenceVariables(n.WarnReset.Expression))
+			an.lrScopePools[alert.Warning] = et.tm.newScopePool(ast.FindReferenceVariables(n.WarnReset.Expression))
 		}
 	}
 
@@ -683,14 +685,14 @@ func newAlertNode(et *ExecutingTask, n *pipeline.AlertNode, d NodeDiagnostic) (a
 			return nil, fmt.Errorf("Failed to compile stateful expression for crit: %s", expressionCompileError)
 		}
 		an.levels[alert.Critical] = statefulExpression
-		an.scopePools[alert.Critical] = stateful.NewScopePool(ast.FindReferenceVariables(n.Crit.Expression))
+		an.scopePools[alert.Critical] = et.tm.newScopePool(ast.FindReferenceVariables(n.Crit.Expression))
 		if n.CritReset != nil {
 			lstatefulExpression, lexpressionCompileError := stateful.NewExpression(n.CritReset.Expression)
 			if lexpressionCompileError != nil {
 				return nil, fmt.Errorf("Failed to compile stateful expression for critReset: %s", lexpressionCompileError)
 			}
 			an.levelResets[alert.Critical] = lstatefulExpression
-			an.lrScopePools[alert.Critical] = stateful.NewScopePool(ast.FindReferenceVariables(n.CritReset.Expression))
+			an.lrScopePools[alert.Critical] = et.tm.newScopePool(ast.FindReferenceVariables(n.CritReset.Expression))
 		}
 	}
 
@@ -709,7 +711,13 @@ func newAlertNode(et *ExecutingTask, n *pipeline.AlertNode, d NodeDiagnostic) (a
 	return
 }
 
-func (n *AlertNode) runAlert([]byte) error {
+func (n *AlertNode) runAlert(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore alert state", err)
+		}
+	}
+
 	// Register delete hook
 	if n.hasAnonTopic() {
 		n.et.tm.registerDeleteHookForTask(n.et.Task.ID, deleteAlertHook(n.anonTopic))
@@ -779,11 +787,19 @@ func (n *AlertNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.R
 		n.outs,
 		edge.NewTimedForwardReceiver(
 			n.timer,
-			state,
+			n.states.newGroup(group.ID, state),
 		),
 	), nil
 }
 
+func (n *AlertNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *AlertNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
 func (n *AlertNode) restoreEventState(id string, t time.Time, tags models.Tags) *alertState {
 	state := n.newAlertState(tags)
 	currentLevel, triggered := n.restoreEvent(id)
@@ -806,7 +822,10 @@ func (n *AlertNode) newAlertState(tags models.Tags) *alertState {
 
 		inhibitor := alert.NewInhibitor(in.Category, tagset)
 		inhibitors[i] = inhibitor
-		n.et.tm.AlertService.AddInhibitor(inhibitor)
+		// Shadow tasks must not inhibit the alerts of other tasks.
+		if !n.et.shadow() {
+			n.et.tm.AlertService.AddInhibitor(inhibitor)
+		}
 	}
 	return &alertState{
 		history:    make([]alert.Level, n.a.History),
@@ -864,11 +883,12 @@ func deleteAlertHook(anonTopic string) deleteHook {
 	}
 }
 
+// Shadow tasks never send events to topics, so neither their handlers nor topic handlers are invoked.
 func (n *AlertNode) hasAnonTopic() bool {
-	return len(n.handlers) > 0
+	return len(n.handlers) > 0 && !n.et.shadow()
 }
 func (n *AlertNode) hasTopic() bool {
-	return n.topic != ""
+	return n.topic != "" && !n.et.shadow()
 }
 
 func (n *AlertNode) handleEvent(event alert.Event) {
@@ -891,6 +911,17 @@ func (n *AlertNode) handleEvent(event alert.Event) {
 	}
 	n.diag.AlertTriggered(event.State.Level, event.State.ID, event.State.Message, event.Data.Result.Series[0])
 
+	if n.et.shadow() || n.et.tm.IsShadowed(n.et.Task.ID) {
+		n.et.recordShadow(ShadowRecord{
+			Type:    ShadowAlert,
+			Node:    n.Name(),
+			Time:    event.State.Time,
+			ID:      event.State.ID,
+			Level:   event.State.Level,
+			Message: event.State.Message,
+		})
+	}
+
 	// If we have anon handlers, emit event to the anonTopic
 	if n.hasAnonTopic() {
 		event.Topic = n.anonTopic
@@ -1007,6 +1038,49 @@ type alertState struct {
 	inhibitors []*alert.Inhibitor
 }
 
+// alertGroupState is the snapshotted state of an alertState.
+type alertGroupState struct {
+	History        []alert.Level
+	Idx            int
+	Flapping       bool
+	Changed        bool
+	FirstTriggered time.Time
+	LastTriggered  time.Time
+	Expired        bool
+}
+
+func (a *alertState) snapshot() alertGroupState {
+	return alertGroupState{
+		History:        append([]alert.Level(nil), a.history...),
+		Idx:            a.idx,
+		Flapping:       a.flapping,
+		Changed:        a.changed,
+		FirstTriggered: a.firstTriggered,
+		LastTriggered:  a.lastTriggered,
+		Expired:        a.expired,
+	}
+}
+
+func (a *alertState) restore(state alertGroupState) {
+	// The history size is part of the task definition, if it changed the snapshot is stale.
+	if len(state.History) != len(a.history) {
+		return
+	}
+	copy(a.history, state.History)
+	a.idx = state.Idx
+	a.flapping = state.Flapping
+	a.changed = state.Changed
+	a.firstTriggered = state.FirstTriggered
+	a.lastTriggered = state.LastTriggered
+	a.expired = state.Expired
+
+	// Update inhibitor state
+	inhibited := a.history[a.idx] != alert.OK
+	for _, in := range a.inhibitors {
+		in.Set(inhibited)
+	}
+}
+
 func (a *alertState) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
 	return nil, a.buffer.BeginBatch(begin)
 }
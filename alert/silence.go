@@ -0,0 +1,141 @@
+package alert
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// MatcherType determines which part of an event a Matcher is compared with.
+type MatcherType int
+
+const (
+	// MatchTag matches the value of a tag of the event.
+	MatchTag MatcherType = iota
+	// MatchID matches the ID of the event.
+	MatchID
+	// MatchLevel matches the level of the event.
+	MatchLevel
+)
+
+// Matcher matches an event by its ID, level or one of its tags.
+type Matcher struct {
+	Type MatcherType
+	// Tag is the name of the tag to match, only used for MatchTag.
+	Tag   string
+	Value string
+
+	re *regexp.Regexp
+}
+
+// NewMatcher creates a matcher, if regex is true value is an anchored regular expression.
+func NewMatcher(typ MatcherType, tag, value string, regex bool) (Matcher, error) {
+	m := Matcher{
+		Type:  typ,
+		Tag:   tag,
+		Value: value,
+	}
+	switch typ {
+	case MatchTag:
+		if tag == "" {
+			return Matcher{}, fmt.Errorf("tag matcher must specify a tag")
+		}
+	case MatchID:
+	case MatchLevel:
+		if !regex {
+			if _, err := ParseLevel(value); err != nil {
+				return Matcher{}, err
+			}
+		}
+	default:
+		return Matcher{}, fmt.Errorf("unknown matcher type %d", typ)
+	}
+	if regex {
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return Matcher{}, err
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// Match reports whether the event matches.
+func (m Matcher) Match(e Event) bool {
+	var v string
+	switch m.Type {
+	case MatchTag:
+		var ok bool
+		v, ok = e.Data.Tags[m.Tag]
+		if !ok {
+			return false
+		}
+	case MatchID:
+		v = e.State.ID
+	case MatchLevel:
+		v = e.State.Level.String()
+		if m.re == nil {
+			l, _ := ParseLevel(m.Value)
+			return l == e.State.Level
+		}
+	}
+	if m.re != nil {
+		return m.re.MatchString(v)
+	}
+	return v == m.Value
+}
+
+// Silence suppresses sending matching events of a topic to its handlers until it expires.
+// Silenced events still update the state of the topic.
+type Silence struct {
+	ID string
+	// Matchers must all match for an event to be silenced.
+	Matchers []Matcher
+	Expires  time.Time
+}
+
+// Active reports whether the silence has not yet expired at time now.
+func (s Silence) Active(now time.Time) bool {
+	return now.Before(s.Expires)
+}
+
+// Match reports whether the event is silenced.
+func (s Silence) Match(e Event) bool {
+	for _, m := range s.Matchers {
+		if !m.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Acknowledgement suppresses sending an event to handlers while the event remains at the acknowledged level.
+// Once the level of the event changes the event is sent to handlers as usual.
+type Acknowledgement struct {
+	Level Level
+	// Expires is optional, a zero value means the acknowledgement never expires.
+	Expires time.Time
+}
+
+// Active reports whether the acknowledgement has not yet expired at time now.
+func (a Acknowledgement) Active(now time.Time) bool {
+	return a.Expires.IsZero() || now.Before(a.Expires)
+}
+
+// suppressions are the silences and acknowledgements of a single topic.
+type suppressions struct {
+	silences map[string]Silence
+	acks     map[string]Acknowledgement
+}
+
+func (s *suppressions) suppressed(e Event, now time.Time) bool {
+	if ack, ok := s.acks[e.State.ID]; ok && ack.Level == e.State.Level && ack.Active(now) {
+		return true
+	}
+	for _, silence := range s.silences {
+		if silence.Active(now) && silence.Match(e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package alert_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/alert"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	event := alert.Event{
+		State: alert.EventState{
+			ID:    "cpu:host=serverA",
+			Level: alert.Warning,
+		},
+		Data: alert.EventData{
+			Tags: map[string]string{"host": "serverA"},
+		},
+	}
+	testCases := []struct {
+		name  string
+		typ   alert.MatcherType
+		tag   string
+		value string
+		regex bool
+		want  bool
+	}{
+		{name: "tag", typ: alert.MatchTag, tag: "host", value: "serverA", want: true},
+		{name: "tag mismatch", typ: alert.MatchTag, tag: "host", value: "serverB", want: false},
+		{name: "missing tag", typ: alert.MatchTag, tag: "region", value: "", want: false},
+		{name: "tag regex", typ: alert.MatchTag, tag: "host", value: "server.*", regex: true, want: true},
+		{name: "regex is anchored", typ: alert.MatchTag, tag: "host", value: "server", regex: true, want: false},
+		{name: "id", typ: alert.MatchID, value: "cpu:host=serverA", want: true},
+		{name: "id regex", typ: alert.MatchID, value: "cpu:.*", regex: true, want: true},
+		{name: "level", typ: alert.MatchLevel, value: "warning", want: true},
+		{name: "level mismatch", typ: alert.MatchLevel, value: "CRITICAL", want: false},
+		{name: "level regex", typ: alert.MatchLevel, value: "WARNING|CRITICAL", regex: true, want: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := alert.NewMatcher(tc.typ, tc.tag, tc.value, tc.regex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Match(event); got != tc.want {
+				t.Errorf("unexpected match result: got %v exp %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcher_Invalid(t *testing.T) {
+	if _, err := alert.NewMatcher(alert.MatchTag, "", "x", false); err == nil {
+		t.Error("expected error for tag matcher without tag")
+	}
+	if _, err := alert.NewMatcher(alert.MatchLevel, "", "BAD", false); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if _, err := alert.NewMatcher(alert.MatchID, "", "(", true); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHandler) Handle(event alert.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event.State.ID+":"+event.State.Level.String())
+}
+
+func TestTopics_Suppressions(t *testing.T) {
+	topics := alert.NewTopics(0)
+	h := new(recordingHandler)
+	topics.RegisterHandler("test", h)
+
+	host, err := alert.NewMatcher(alert.MatchTag, "host", "serverA", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics.SetSilence("test", alert.Silence{
+		ID:       "silence",
+		Matchers: []alert.Matcher{host},
+		Expires:  time.Now().Add(time.Hour),
+	})
+	topics.SetSilence("test", alert.Silence{
+		ID:       "expired",
+		Matchers: []alert.Matcher{host},
+		Expires:  time.Now().Add(-time.Hour),
+	})
+	topics.Acknowledge("test", "acked", alert.Acknowledgement{Level: alert.Critical})
+
+	collect := func(id, host string, level alert.Level) {
+		err := topics.Collect(alert.Event{
+			Topic: "test",
+			State: alert.EventState{ID: id, Level: level},
+			Data:  alert.EventData{Tags: map[string]string{"host": host}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect("silenced", "serverA", alert.Critical)
+	collect("other", "serverB", alert.Critical)
+	collect("acked", "serverB", alert.Critical)
+	collect("acked", "serverB", alert.OK)
+
+	topics.RemoveSilence("test", "silence")
+	collect("silenced", "serverA", alert.OK)
+
+	// Suppressed events still update the state of the topic.
+	if state, ok := topics.EventState("test", "acked"); !ok || state.Level != alert.OK {
+		t.Errorf("unexpected state of suppressed event: %v %v", state, ok)
+	}
+
+	// Close the topics to wait for all events to be handled.
+	topics.Close()
+	exp := []string{"other:CRITICAL", "acked:OK", "silenced:OK"}
+	if len(h.events) != len(exp) {
+		t.Fatalf("unexpected handled events: got %v exp %v", h.events, exp)
+	}
+	for i := range exp {
+		if h.events[i] != exp[i] {
+			t.Errorf("unexpected handled event %d: got %s exp %s", i, h.events[i], exp[i])
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"path"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/influxdata/kapacitor/expvar"
 	"github.com/influxdata/kapacitor/server/vars"
@@ -20,6 +21,11 @@ type Topics struct {
 	mu              sync.RWMutex
 	eventBufferSize int
 	topics          map[string]*Topic
+
+	// Silences and acknowledgements by topic,
+	// these outlive the topics themselves since topics are closed and restored with their tasks.
+	suppressMu   sync.RWMutex
+	suppressions map[string]*suppressions
 }
 
 // NewTopics creates a new Topics struct with a minimum bufferSize of 500.
@@ -30,6 +36,7 @@ func NewTopics(bufferSize int) *Topics {
 	s := &Topics{
 		eventBufferSize: bufferSize,
 		topics:          make(map[string]*Topic),
+		suppressions:    make(map[string]*suppressions),
 	}
 	return s
 }
@@ -187,6 +194,80 @@ func (s *Topics) TopicState(pattern string, minLevel Level) map[string]TopicStat
 	return res
 }
 
+// SetSilence adds the silence to the topic, replacing any silence with the same ID.
+func (s *Topics) SetSilence(topic string, silence Silence) {
+	s.suppressMu.Lock()
+	defer s.suppressMu.Unlock()
+	s.ensureSuppressions(topic).silences[silence.ID] = silence
+}
+
+// RemoveSilence removes the silence from the topic.
+func (s *Topics) RemoveSilence(topic, id string) {
+	s.suppressMu.Lock()
+	defer s.suppressMu.Unlock()
+	if sp, ok := s.suppressions[topic]; ok {
+		delete(sp.silences, id)
+	}
+}
+
+// Acknowledge acknowledges the event of the topic, replacing any previous acknowledgement.
+func (s *Topics) Acknowledge(topic, event string, ack Acknowledgement) {
+	s.suppressMu.Lock()
+	defer s.suppressMu.Unlock()
+	s.ensureSuppressions(topic).acks[event] = ack
+}
+
+// Unacknowledge removes the acknowledgement of the event of the topic.
+func (s *Topics) Unacknowledge(topic, event string) {
+	s.suppressMu.Lock()
+	defer s.suppressMu.Unlock()
+	if sp, ok := s.suppressions[topic]; ok {
+		delete(sp.acks, event)
+	}
+}
+
+// Acknowledgement returns the acknowledgement of the event of the topic, and if it exists or not.
+func (s *Topics) Acknowledgement(topic, event string) (Acknowledgement, bool) {
+	s.suppressMu.RLock()
+	defer s.suppressMu.RUnlock()
+	if sp, ok := s.suppressions[topic]; ok {
+		ack, ok := sp.acks[event]
+		return ack, ok
+	}
+	return Acknowledgement{}, false
+}
+
+// DeleteSuppressions removes all silences and acknowledgements of the topic.
+func (s *Topics) DeleteSuppressions(topic string) {
+	s.suppressMu.Lock()
+	defer s.suppressMu.Unlock()
+	delete(s.suppressions, topic)
+}
+
+// ensureSuppressions returns the suppressions of the topic, caller must have the write lock.
+func (s *Topics) ensureSuppressions(topic string) *suppressions {
+	sp, ok := s.suppressions[topic]
+	if !ok {
+		sp = &suppressions{
+			silences: make(map[string]Silence),
+			acks:     make(map[string]Acknowledgement),
+		}
+		s.suppressions[topic] = sp
+	}
+	return sp
+}
+
+// suppressed reports whether the event is silenced or acknowledged.
+func (s *Topics) suppressed(event Event) bool {
+	s.suppressMu.RLock()
+	defer s.suppressMu.RUnlock()
+	sp, ok := s.suppressions[event.Topic]
+	if !ok {
+		return false
+	}
+	return sp.suppressed(event, time.Now())
+}
+
 func PatternMatch(pattern, id string) bool {
 	if pattern == "" {
 		return true
@@ -202,8 +283,12 @@ type Topic struct {
 	events       map[string]*EventState
 	sorted       []*EventState
 
-	collected *expvar.Int
-	statsKey  string
+	collected  *expvar.Int
+	suppressed *expvar.Int
+	statsKey   string
+
+	// isSuppressed reports whether an event should not be sent to the handlers.
+	isSuppressed func(Event) bool
 
 	handlers []*bufHandler
 }
@@ -213,12 +298,15 @@ func (s *Topics) newTopic(id string) *Topic {
 		id:           id,
 		events:       make(map[string]*EventState),
 		collected:    new(expvar.Int),
+		suppressed:   new(expvar.Int),
 		bufferLength: s.eventBufferSize,
+		isSuppressed: s.suppressed,
 	}
 	statsKey, statsMap := vars.NewStatistic("topics", map[string]string{
 		"id": id,
 	})
 	statsMap.Set("collected", t.collected)
+	statsMap.Set("suppressed", t.suppressed)
 	t.statsKey = statsKey
 	return t
 }
@@ -326,6 +414,12 @@ func (t *Topic) collect(event Event) error {
 
 	t.collected.Add(1)
 
+	// Silenced and acknowledged events are recorded but not handled.
+	if t.isSuppressed != nil && t.isSuppressed(event) {
+		t.suppressed.Add(1)
+		return nil
+	}
+
 	return t.handleEvent(event)
 }
 
@@ -442,7 +536,11 @@ func (h *bufHandler) Handle(event Event) error {
 	case h.events <- event:
 		return nil
 	default:
-		return fmt.Errorf("failed to deliver event %q to handler", event.State.ID)
+		err := fmt.Errorf("failed to deliver event %q to handler", event.State.ID)
+		if d, ok := h.h.(DropHandler); ok {
+			d.Dropped(event, err)
+		}
+		return err
 	}
 }
 
@@ -35,6 +35,12 @@ func (e Event) PreviousState() EventState {
 	return e.previousState
 }
 
+// SetPreviousState sets the state of the event before it was collected.
+// It is used to restore events that were stored after they were collected.
+func (e *Event) SetPreviousState(state EventState) {
+	e.previousState = state
+}
+
 func (e Event) TemplateData() TemplateData {
 	return TemplateData{
 		ID:       e.State.ID,
@@ -56,6 +62,22 @@ type Handler interface {
 	Handle(event Event)
 }
 
+// FallibleHandler is a Handler that reports whether it failed to take action on an event,
+// so that the event can be retried.
+type FallibleHandler interface {
+	Handler
+	// TryHandle is responsible for taking action on the event and returns an error if it failed.
+	TryHandle(event Event) error
+}
+
+// DropHandler is a Handler that is notified of the events it was not sent
+// because its buffer of events was full.
+type DropHandler interface {
+	Handler
+	// Dropped is called with each event that was dropped and the reason it was dropped.
+	Dropped(event Event, err error)
+}
+
 type EventState struct {
 	ID       string
 	Message  string
@@ -0,0 +1,429 @@
+package kapacitor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/influxdata/influxdb/query/neldermead"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/pipeline"
+)
+
+const (
+	// Scale factor of the median absolute deviation so that it estimates
+	// the standard deviation of normally distributed data.
+	madScale = 1.4826
+	// Minimum number of previous forecast residuals before Holt-Winters forecasts are scored.
+	minAnomalyResiduals = 3
+)
+
+type AnomalyNode struct {
+	node
+	a *pipeline.AnomalyNode
+
+	states groupSnapshotter[anomalyState, *anomalyGroup]
+}
+
+// Create a new anomaly node.
+func newAnomalyNode(et *ExecutingTask, n *pipeline.AnomalyNode, d NodeDiagnostic) (*AnomalyNode, error) {
+	an := &AnomalyNode{
+		node: node{Node: n, et: et, diag: d},
+		a:    n,
+	}
+	an.node.runF = an.runAnomaly
+	return an, nil
+}
+
+func (n *AnomalyNode) runAnomaly(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore anomaly state", err)
+		}
+	}
+	consumer := edge.NewGroupedConsumer(
+		n.ins[0],
+		n,
+	)
+	n.statMap.Set(statCardinalityGauge, consumer.CardinalityVar())
+	return consumer.Consume()
+}
+
+func (n *AnomalyNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.Receiver, error) {
+	return edge.NewReceiverFromForwardReceiverWithStats(
+		n.outs,
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, n.newGroup())),
+	), nil
+}
+
+func (n *AnomalyNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *AnomalyNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
+func (n *AnomalyNode) newGroup() *anomalyGroup {
+	return &anomalyGroup{
+		n: n,
+	}
+}
+
+type anomalyGroup struct {
+	n *AnomalyNode
+	// Previous values of the field, oldest first.
+	window []float64
+	// Residuals of the previous Holt-Winters forecasts, oldest first.
+	residuals []float64
+	// Holt-Winters model of the seasonal detector, nil until it is fit to a full window.
+	model *holtWinters
+	// Number of values applied to the model since it was last fit.
+	sinceFit int
+}
+
+type anomalyState struct {
+	Window    []float64
+	Residuals []float64
+	Model     *holtWinters
+	SinceFit  int
+}
+
+func (g *anomalyGroup) snapshot() anomalyState {
+	return anomalyState{
+		Window:    append([]float64(nil), g.window...),
+		Residuals: append([]float64(nil), g.residuals...),
+		Model:     g.model.clone(),
+		SinceFit:  g.sinceFit,
+	}
+}
+
+func (g *anomalyGroup) restore(state anomalyState) {
+	g.window = state.Window
+	g.residuals = state.Residuals
+	g.model = state.Model
+	g.sinceFit = state.SinceFit
+}
+
+func (g *anomalyGroup) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
+	return begin, nil
+}
+
+func (g *anomalyGroup) BatchPoint(bp edge.BatchPointMessage) (edge.Message, error) {
+	np := bp.ShallowCopy()
+	if !g.doAnomaly(bp, np) {
+		return nil, nil
+	}
+	return np, nil
+}
+
+func (g *anomalyGroup) EndBatch(end edge.EndBatchMessage) (edge.Message, error) {
+	return end, nil
+}
+
+func (g *anomalyGroup) Point(p edge.PointMessage) (edge.Message, error) {
+	np := p.ShallowCopy()
+	if !g.doAnomaly(p, np) {
+		return nil, nil
+	}
+	return np, nil
+}
+
+// doAnomaly scores the field of p against the baseline of the group and sets the results on n.
+// Reports whether the point should be emitted.
+func (g *anomalyGroup) doAnomaly(p edge.FieldsTagsTimeGetter, n edge.FieldsTagsTimeSetter) bool {
+	a := g.n.a
+	value, ok := numToFloat(p.Fields()[a.Field])
+	if !ok {
+		g.n.diag.Error("cannot perform anomaly detection",
+			errors.New("field is missing or the wrong type"),
+			keyvalue.KV("field", a.Field),
+			keyvalue.KV("type", fmt.Sprintf("%T", p.Fields()[a.Field])),
+		)
+		return false
+	}
+
+	score, lower, upper := g.score(value)
+	g.window = appendBounded(g.window, value, int(a.Window))
+	if g.model != nil {
+		g.model.update(value)
+		g.sinceFit++
+	}
+
+	fields := n.Fields().Copy()
+	fields[a.ScoreField] = score
+	fields[a.LowerField] = lower
+	fields[a.UpperField] = upper
+	fields[a.AnomalyField] = math.Abs(score) > a.Threshold
+	n.SetFields(fields)
+	return true
+}
+
+// score returns the score of the value and the band of expected values
+// given the current window of previous values.
+func (g *anomalyGroup) score(value float64) (score, lower, upper float64) {
+	a := g.n.a
+	if len(g.window) < int(a.Window) {
+		// Not enough data to establish a baseline.
+		return 0, value, value
+	}
+	var baseline, scale float64
+	switch a.Detector {
+	case pipeline.AnomalyMAD:
+		baseline, scale = medianAbsoluteDeviation(g.window)
+	case pipeline.AnomalySeasonal:
+		forecast, ok := g.forecast()
+		if !ok {
+			return 0, value, value
+		}
+		residuals := g.residuals
+		g.residuals = appendBounded(g.residuals, value-forecast, int(a.Window))
+		if len(residuals) < minAnomalyResiduals {
+			return 0, value, value
+		}
+		baseline, scale = forecast, rootMeanSquare(residuals)
+	default:
+		baseline, scale = meanStddev(g.window)
+	}
+	lower = baseline - a.Threshold*scale
+	upper = baseline + a.Threshold*scale
+	diff := value - baseline
+	switch {
+	case diff == 0:
+		score = 0
+	case scale == 0:
+		// Any deviation from a constant baseline is an anomaly,
+		// use the largest finite score so the field can still be written.
+		score = math.Copysign(math.MaxFloat64, diff)
+	default:
+		score = diff / scale
+	}
+	return score, lower, upper
+}
+
+// forecast returns the Holt-Winters forecast of the next value.
+// Fitting the model is expensive, so it is only refit to the window once per window of values,
+// in between the fitted model is updated with each value.
+func (g *anomalyGroup) forecast() (float64, bool) {
+	a := g.n.a
+	if g.model == nil || g.sinceFit >= int(a.Window) {
+		g.model = fitHoltWinters(g.window, int(a.Seasonality))
+		g.sinceFit = 0
+	}
+	forecast := g.model.forecast()
+	if math.IsNaN(forecast) || math.IsInf(forecast, 0) {
+		// The model is refit once the window is replaced, not with every value.
+		return 0, false
+	}
+	return forecast, true
+}
+
+func (g *anomalyGroup) Barrier(b edge.BarrierMessage) (edge.Message, error) {
+	return b, nil
+}
+func (g *anomalyGroup) DeleteGroup(d edge.DeleteGroupMessage) (edge.Message, error) {
+	return d, nil
+}
+func (g *anomalyGroup) Done() {}
+
+// appendBounded appends v to values, dropping the oldest values so that at most size values are kept.
+func appendBounded(values []float64, v float64, size int) []float64 {
+	values = append(values, v)
+	if len(values) > size {
+		// Copy so the backing array does not grow indefinitely.
+		values = append(values[:0:0], values[len(values)-size:]...)
+	}
+	return values
+}
+
+func meanStddev(values []float64) (mean, stddev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(values)))
+}
+
+func median(sorted []float64) float64 {
+	l := len(sorted)
+	if l%2 == 0 {
+		return (sorted[l/2-1] + sorted[l/2]) / 2
+	}
+	return sorted[l/2]
+}
+
+// medianAbsoluteDeviation returns the median of the values and their scaled median absolute deviation.
+func medianAbsoluteDeviation(values []float64) (float64, float64) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	m := median(sorted)
+	for i, v := range values {
+		sorted[i] = math.Abs(v - m)
+	}
+	sort.Float64s(sorted)
+	return m, madScale * median(sorted)
+}
+
+func rootMeanSquare(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// Starting guesses of the Holt-Winters smoothing parameters,
+// each parameter is guessed at every step from the lower guess up to but not including one.
+const (
+	hwGuessLower = 0.3
+	hwGuessStep  = 0.4
+	// Weight of the first value used to guess the initial state of non seasonal data.
+	hwWeight = 0.5
+	// Epsilon of the minimization of the sum of squared errors.
+	hwEpsilon = 1.0e-4
+)
+
+// holtWinters is a damped multiplicative Holt-Winters model,
+// it uses the same recursion as the InfluxQL holt_winters function.
+// The values are treated as regularly spaced.
+type holtWinters struct {
+	// Smoothing parameters of the level, trend and season and the damping of the trend.
+	Alpha, Beta, Gamma, Phi float64
+	Level, Trend            float64
+	// Seasonal factors, the factor of the t-th value is Season[t%len(Season)].
+	// Empty if the data is not seasonal.
+	Season []float64
+	// Number of values applied to the model.
+	T int
+}
+
+// fitHoltWinters returns the model that minimizes the sum of squared errors of the one step forecasts of the values.
+// The parameters are optimized with the Nelder-Mead method starting from a grid of guesses.
+// Seasonal factors are multiplicative, so data with values that are not positive is modelled without a season.
+func fitHoltWinters(values []float64, seasonality int) *holtWinters {
+	m := seasonality
+	if m < 2 || !allPositive(values) {
+		m = 0
+	}
+	// Parameters are alpha, beta, gamma, phi, the initial level, trend and seasonal factors.
+	params := make([]float64, 6+m)
+	if m > 0 {
+		for i := 0; i < m; i++ {
+			params[4] += values[i] / float64(m)
+		}
+		for i := 0; i < m && m+i < len(values); i++ {
+			params[5] += (values[m+i] - values[i]) / float64(m*m)
+		}
+		for i := 0; i < m; i++ {
+			params[6+i] = values[i] / params[4]
+		}
+	} else {
+		params[4] = hwWeight * values[0]
+		params[5] = hwWeight * (values[1] - values[0])
+	}
+
+	sse := func(params []float64) float64 {
+		hw := newHoltWinters(params)
+		var sse float64
+		for _, v := range values {
+			diff := hw.forecast() - v
+			if math.IsNaN(diff) {
+				return math.Inf(1)
+			}
+			sse += diff * diff
+			hw.update(v)
+		}
+		return sse
+	}
+	optim := neldermead.New()
+	minSSE := math.Inf(1)
+	var best []float64
+	for alpha := hwGuessLower; alpha < 1; alpha += hwGuessStep {
+		for beta := hwGuessLower; beta < 1; beta += hwGuessStep {
+			for gamma := hwGuessLower; gamma < 1; gamma += hwGuessStep {
+				for phi := hwGuessLower; phi < 1; phi += hwGuessStep {
+					params[0], params[1], params[2], params[3] = alpha, beta, gamma, phi
+					e, p := optim.Optimize(sse, params, hwEpsilon, 1)
+					if e < minSSE || best == nil {
+						minSSE, best = e, p
+					}
+				}
+			}
+		}
+	}
+
+	hw := newHoltWinters(best)
+	for _, v := range values {
+		hw.update(v)
+	}
+	return hw
+}
+
+func allPositive(values []float64) bool {
+	for _, v := range values {
+		if !(v > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// newHoltWinters returns the model of the parameters before any values are applied.
+// The smoothing parameters are constrained to [0, 1].
+func newHoltWinters(params []float64) *holtWinters {
+	constrain := func(x float64) float64 {
+		return math.Max(0, math.Min(1, x))
+	}
+	return &holtWinters{
+		Alpha:  constrain(params[0]),
+		Beta:   constrain(params[1]),
+		Gamma:  constrain(params[2]),
+		Phi:    constrain(params[3]),
+		Level:  params[4],
+		Trend:  params[5],
+		Season: append([]float64(nil), params[6:]...),
+	}
+}
+
+func (hw *holtWinters) clone() *holtWinters {
+	if hw == nil {
+		return nil
+	}
+	c := *hw
+	c.Season = append([]float64(nil), hw.Season...)
+	return &c
+}
+
+func (hw *holtWinters) season() float64 {
+	if len(hw.Season) == 0 {
+		return 1
+	}
+	return hw.Season[hw.T%len(hw.Season)]
+}
+
+// forecast returns the forecast of the next value.
+func (hw *holtWinters) forecast() float64 {
+	return (hw.Level + hw.Phi*hw.Trend) * hw.season()
+}
+
+// update applies the next value to the model.
+// A zero seasonal factor or level leaves the value or factor as is instead of dividing by zero.
+func (hw *holtWinters) update(v float64) {
+	s := hw.season()
+	level, damped := hw.Level, hw.Phi*hw.Trend
+	deseasonalized := v
+	if s != 0 {
+		deseasonalized = v / s
+	}
+	hw.Level = hw.Alpha*deseasonalized + (1-hw.Alpha)*(level+damped)
+	hw.Trend = hw.Beta*(hw.Level-level) + (1-hw.Beta)*damped
+	if len(hw.Season) > 0 && level+damped != 0 {
+		hw.Season[hw.T%len(hw.Season)] = hw.Gamma*(v/(level+damped)) + (1-hw.Gamma)*s
+	}
+	hw.T++
+}
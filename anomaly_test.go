@@ -0,0 +1,208 @@
+package kapacitor
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnomaly(t *testing.T) {
+	group := snapshotTestGroup()
+	newNode := func(t *testing.T, configure func(*pipeline.AnomalyNode)) *AnomalyNode {
+		t.Helper()
+		stream := &pipeline.StreamNode{}
+		pipeline.CreatePipelineSources(stream)
+		pn := stream.From().Anomaly("value")
+		configure(pn)
+		n, err := newAnomalyNode(nil, pn, nil)
+		require.NoError(t, err)
+		return n
+	}
+	point := func(i int, v float64) edge.PointMessage {
+		return edge.NewPointMessage(
+			"cpu", "db", "rp",
+			group.Dimensions,
+			models.Fields{"value": v},
+			group.Tags,
+			time.Unix(int64(i), 0).UTC(),
+		)
+	}
+	// feed sends the values through the group g and returns the fields of the emitted points.
+	feed := func(t *testing.T, g edge.ForwardReceiver, values ...float64) []models.Fields {
+		t.Helper()
+		fields := make([]models.Fields, len(values))
+		for i, v := range values {
+			m, err := g.Point(point(i, v))
+			require.NoError(t, err)
+			p, ok := m.(edge.PointMessage)
+			require.True(t, ok, "unexpected message %v", m)
+			fields[i] = p.Fields()
+		}
+		return fields
+	}
+
+	t.Run("zscore", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Zscore(3)
+			a.Window = 5
+		})
+		g := n.states.newGroup(group.ID, n.newGroup())
+		fields := feed(t, g, 10, 11, 9, 10, 10, 10.2, 20)
+		assert.Equal(t, models.Fields{
+			"value":      10.0,
+			"score":      0.0,
+			"lower":      10.0,
+			"upper":      10.0,
+			"is_anomaly": false,
+		}, fields[0])
+		// The window 10, 11, 9, 10, 10 has a mean of 10 and a standard deviation of sqrt(0.4).
+		stddev := math.Sqrt(0.4)
+		assert.InDelta(t, 0.2/stddev, fields[5]["score"], 1e-9)
+		assert.InDelta(t, 10-3*stddev, fields[5]["lower"], 1e-9)
+		assert.InDelta(t, 10+3*stddev, fields[5]["upper"], 1e-9)
+		assert.Equal(t, false, fields[5]["is_anomaly"])
+		assert.Equal(t, true, fields[6]["is_anomaly"])
+	})
+	t.Run("mad is robust to outliers", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Mad(3)
+			a.Window = 5
+			a.AnomalyField = "anomalous"
+		})
+		g := n.states.newGroup(group.ID, n.newGroup())
+		fields := feed(t, g, 10, 10, 11, 9, 100, 12, 20)
+		assert.InDelta(t, 2/madScale, fields[5]["score"], 0.001)
+		assert.Equal(t, false, fields[5]["anomalous"])
+		// The window is now 10, 11, 9, 100, 12 with a median of 11.
+		assert.InDelta(t, 9/madScale, fields[6]["score"], 0.001)
+		assert.Equal(t, true, fields[6]["anomalous"])
+	})
+	t.Run("constant baseline", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Window = 3
+		})
+		g := n.states.newGroup(group.ID, n.newGroup())
+		fields := feed(t, g, 5, 5, 5, 5, 4)
+		assert.Equal(t, 0.0, fields[3]["score"])
+		assert.Equal(t, false, fields[3]["is_anomaly"])
+		assert.Less(t, fields[4]["score"], -1e300)
+		assert.Equal(t, true, fields[4]["is_anomaly"])
+	})
+	t.Run("seasonal follows the trend", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Seasonal(4, 0)
+			a.Window = 20
+		})
+		g := n.states.newGroup(group.ID, n.newGroup())
+		values := make([]float64, 40)
+		for i := range values {
+			values[i] = 2 * float64(i)
+			if i%2 == 0 {
+				values[i] += 0.5
+			}
+		}
+		fields := feed(t, g, values...)
+		for i := 0; i < 20+minAnomalyResiduals; i++ {
+			assert.Equal(t, 0.0, fields[i]["score"], "point %d", i)
+		}
+		for i := 20 + minAnomalyResiduals; i < len(values); i++ {
+			assert.Equal(t, false, fields[i]["is_anomaly"], "point %d", i)
+		}
+		fields = feed(t, g, 120)
+		assert.Equal(t, true, fields[0]["is_anomaly"])
+		assert.Greater(t, fields[0]["score"], 0.0)
+	})
+	t.Run("seasonal model is refit once per window", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Seasonal(4, 4)
+			a.Window = 12
+		})
+		ag := n.newGroup()
+		g := n.states.newGroup(group.ID, ag)
+		season := []float64{10, 20, 15, 5}
+		values := make([]float64, 13)
+		for i := range values {
+			values[i] = season[i%4]
+		}
+		feed(t, g, values...)
+		model := ag.model
+		require.NotNil(t, model)
+		assert.Equal(t, 1, ag.sinceFit)
+
+		values = values[:11]
+		for i := range values {
+			values[i] = season[(i+13)%4]
+		}
+		fields := feed(t, g, values...)
+		assert.Same(t, model, ag.model, "model refit before a window of values")
+		for i := range fields {
+			assert.Equal(t, false, fields[i]["is_anomaly"], "point %d", i)
+		}
+		feed(t, g, season[0])
+		assert.NotSame(t, model, ag.model, "model not refit after a window of values")
+		assert.Equal(t, 1, ag.sinceFit)
+	})
+	t.Run("seasonal model of counts with zeros", func(t *testing.T) {
+		n := newNode(t, func(a *pipeline.AnomalyNode) {
+			a.Seasonal(4, 4)
+			a.Window = 12
+		})
+		ag := n.newGroup()
+		g := n.states.newGroup(group.ID, ag)
+		// The first season averages zero, a multiplicative season would divide by zero.
+		season := []float64{0, 0, 0, 0, 3, 0, 1, 0}
+		values := make([]float64, 24)
+		for i := range values {
+			values[i] = season[i%len(season)]
+		}
+		fields := feed(t, g, values[:13]...)
+		model := ag.model
+		require.NotNil(t, model)
+		assert.Empty(t, model.Season, "data with zeros must be modelled without a season")
+		fields = append(fields, feed(t, g, values[13:]...)...)
+		assert.Same(t, model, ag.model, "model refit before a window of values")
+		for i, f := range fields[12:] {
+			for _, name := range []string{"score", "lower", "upper"} {
+				v := f[name].(float64)
+				assert.False(t, math.IsNaN(v) || math.IsInf(v, 0), "point %d field %s is %v", i+12, name, v)
+			}
+		}
+	})
+}
+
+func TestAnomaly_SnapshotRestore(t *testing.T) {
+	group := snapshotTestGroup()
+	stream := &pipeline.StreamNode{}
+	pipeline.CreatePipelineSources(stream)
+	pn := stream.From().Anomaly("value").Seasonal(3, 0)
+	pn.Window = 10
+	n, err := newAnomalyNode(nil, pn, nil)
+	require.NoError(t, err)
+
+	g := n.states.newGroup(group.ID, n.newGroup())
+	for i := 0; i < 20; i++ {
+		_, err := g.Point(snapshotTestPoint(group, i*i%7))
+		require.NoError(t, err)
+	}
+	data, err := n.snapshot()
+	require.NoError(t, err)
+	expected, err := g.Point(snapshotTestPoint(group, 3))
+	require.NoError(t, err)
+
+	restored, err := newAnomalyNode(nil, pn, nil)
+	require.NoError(t, err)
+	require.NoError(t, restored.restore(data))
+	g = restored.states.newGroup(group.ID, restored.newGroup())
+	got, err := g.Point(snapshotTestPoint(group, 3))
+	require.NoError(t, err)
+
+	require.NotNil(t, expected)
+	assert.Equal(t, expected.(edge.PointMessage).Fields(), got.(edge.PointMessage).Fields())
+	assert.NotEqual(t, 0.0, got.(edge.PointMessage).Fields()["score"])
+}
@@ -89,7 +89,7 @@ func newAutoscaleNode(
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid replicas expression")
 	}
-	replicasScopePool := stateful.NewScopePool(ast.FindReferenceVariables(replicas.Expression))
+	replicasScopePool := et.tm.newScopePool(ast.FindReferenceVariables(replicas.Expression))
 	kn := &AutoscaleNode{
 		node:              node{Node: n, et: et, diag: d},
 		resourceStates:    make(map[string]resourceState),
@@ -249,7 +249,7 @@ func (n *AutoscaleNode) handlePoint(streamName string, dims models.Dimensions, p
 	}
 
 	// We have a valid event to apply
-	if err := n.applyEvent(e); err != nil {
+	if err := n.applyEvent(e, t); err != nil {
 		return nil, errors.Wrap(err, "failed to apply scaling event")
 	}
 
@@ -282,7 +282,18 @@ func (n *AutoscaleNode) handlePoint(streamName string, dims models.Dimensions, p
 	), nil
 }
 
-func (n *AutoscaleNode) applyEvent(e event) error {
+func (n *AutoscaleNode) applyEvent(e event, t time.Time) error {
+	if n.et.shadow() {
+		n.et.recordShadow(ShadowRecord{
+			Type:     ShadowScale,
+			Node:     n.Name(),
+			Time:     t,
+			ID:       e.ID.ID(),
+			Message:  fmt.Sprintf("replicas changed from %d to %d", e.Old, e.New),
+			Replicas: e.New,
+		})
+		return nil
+	}
 	n.diag.SettingReplicas(e.New, e.Old, e.ID.ID())
 	err := n.a.SetReplicas(e.ID, e.New)
 	return errors.Wrapf(err, "failed to set new replica count for %q", e.ID)
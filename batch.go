@@ -77,8 +77,10 @@ func (n *BatchNode) DBRPs() ([]DBRP, error) {
 			}
 		case *FluxQueryNode:
 			// flux queries don't really have DBRPs
+		case *SQLQueryNode:
+			// SQL queries don't query InfluxDB
 		default:
-			panic("BatchNode shouldn't be followed by anything except QueryNode or QueryFluxNode")
+			panic("BatchNode shouldn't be followed by anything except QueryNode, QueryFluxNode or QuerySQLNode")
 		}
 	}
 	return dbrps, nil
@@ -95,8 +97,10 @@ func (n *BatchNode) Start() {
 			b.Start()
 		case *FluxQueryNode:
 			b.Start()
+		case *SQLQueryNode:
+			b.Start()
 		default:
-			panic("BatchNode shouldn't be followed by anything except QueryNode or QueryFluxNode")
+			panic("BatchNode shouldn't be followed by anything except QueryNode, QueryFluxNode or QuerySQLNode")
 		}
 	}
 }
@@ -108,8 +112,10 @@ func (n *BatchNode) Abort() {
 			b.Abort()
 		case *FluxQueryNode:
 			b.Abort()
+		case *SQLQueryNode:
+			b.Abort()
 		default:
-			panic("BatchNode shouldn't be followed by anything except QueryNode or QueryFluxNode")
+			panic("BatchNode shouldn't be followed by anything except QueryNode, QueryFluxNode or QuerySQLNode")
 		}
 	}
 }
@@ -146,8 +152,11 @@ func (n *BatchNode) Queries(start, stop time.Time) ([]BatchQueries, error) {
 				Cluster:     qn.Cluster(),
 			}
 
+		case *SQLQueryNode:
+			// SQL queries cannot be recorded, they have no InfluxDB queries.
+
 		default:
-			panic("BatchNode shouldn't be followed by anything except QueryNode or QueryFluxNode")
+			panic("BatchNode shouldn't be followed by anything except QueryNode, QueryFluxNode or QuerySQLNode")
 		}
 
 	}
@@ -165,11 +174,15 @@ func (n *BatchNode) collectedCount() (count int64) {
 	return
 }
 
-type QueryNode struct {
+// batchQueryFunc runs the query scheduled at now and returns the batches it produced.
+type batchQueryFunc func(now time.Time) ([]edge.BufferedBatchMessage, error)
+
+// batchQueryNode runs a query on the schedule of a batch node and emits the batches it produces.
+// The query nodes embed it and plug in their query through connectF.
+type batchQueryNode struct {
 	node
-	b        *pipeline.QueryNode
-	query    *Query
 	ticker   ticker
+	connectF func() (batchQueryFunc, error)
 	queryMu  sync.Mutex
 	queryErr chan error
 	closing  chan struct{}
@@ -177,19 +190,159 @@ type QueryNode struct {
 
 	batchesQueried *expvar.Int
 	pointsQueried  *expvar.Int
-	byName         bool
+}
+
+// schedule sets up the ticker of the node and the connect function called once the node starts.
+func (n *batchQueryNode) schedule(every time.Duration, cron string, align bool, connect func() (batchQueryFunc, error)) error {
+	n.closing = make(chan struct{})
+	n.aborting = make(chan struct{})
+	n.connectF = connect
+	n.node.runF = n.runBatch
+	n.node.stopF = n.stopBatch
+
+	if every != 0 && cron != "" {
+		return errors.New("must not set both 'every' and 'cron' properties")
+	}
+	switch {
+	case every > 0:
+		n.ticker = newTimeTicker(every, align)
+	case cron != "":
+		var err error
+		n.ticker, err = newCronTicker(cron)
+		if err != nil {
+			return err
+		}
+	case every < 0:
+		return errors.New("'every' duration must be non-negative")
+	default:
+		return errors.New("must define one of 'every' or 'cron'")
+	}
+	return nil
+}
+
+func (n *batchQueryNode) Start() {
+	n.queryMu.Lock()
+	defer n.queryMu.Unlock()
+	n.queryErr = make(chan error, 1)
+	go func() {
+		n.queryErr <- n.doQuery(n.ins[0])
+	}()
+}
+
+func (n *batchQueryNode) Abort() {
+	close(n.aborting)
+}
+
+// Run the query on each tick and collect batches on batch collector.
+func (n *batchQueryNode) doQuery(in edge.Edge) error {
+	defer in.Close()
+	n.batchesQueried = &expvar.Int{}
+	n.pointsQueried = &expvar.Int{}
+
+	n.statMap.Set(statsBatchesQueried, n.batchesQueried)
+	n.statMap.Set(statsPointsQueried, n.pointsQueried)
+
+	query, err := n.connectF()
+	if err != nil {
+		return err
+	}
+	tickC := n.ticker.Start()
+	for {
+		select {
+		case <-n.closing:
+			return nil
+		case <-n.aborting:
+			return errors.New("batch doQuery aborted")
+		case now := <-tickC:
+			n.timer.Start()
+			batches, err := query(now)
+			if err != nil {
+				n.diag.Error("error executing query", err)
+				n.timer.Stop()
+				break
+			}
+
+			// Collect batches
+			for _, bch := range batches {
+				n.batchesQueried.Add(1)
+				n.pointsQueried.Add(int64(len(bch.Points())))
+
+				n.timer.Pause()
+				if err := in.Collect(bch); err != nil {
+					return err
+				}
+				n.timer.Resume()
+			}
+			n.timer.Stop()
+		}
+	}
+}
+
+func (n *batchQueryNode) runBatch([]byte) error {
+	errC := make(chan error, 1)
+	go func() {
+		defer func() {
+			err := recover()
+			if err != nil {
+				errC <- fmt.Errorf("%v", err)
+			}
+		}()
+		for bt, ok := n.ins[0].Emit(); ok; bt, ok = n.ins[0].Emit() {
+			for _, child := range n.outs {
+				err := child.Collect(bt)
+				if err != nil {
+					errC <- err
+					return
+				}
+			}
+		}
+		errC <- nil
+	}()
+	var queryErr error
+	n.queryMu.Lock()
+	if n.queryErr != nil {
+		n.queryMu.Unlock()
+		select {
+		case queryErr = <-n.queryErr:
+		case <-n.aborting:
+			queryErr = errors.New("batch queryErr aborted")
+		}
+	} else {
+		n.queryMu.Unlock()
+	}
+
+	var err error
+	select {
+	case err = <-errC:
+	case <-n.aborting:
+		err = errors.New("batch run aborted")
+	}
+	if queryErr != nil {
+		return queryErr
+	}
+	return err
+}
+
+func (n *batchQueryNode) stopBatch() {
+	if n.ticker != nil {
+		n.ticker.Stop()
+	}
+	close(n.closing)
+}
+
+type QueryNode struct {
+	batchQueryNode
+	b      *pipeline.QueryNode
+	query  *Query
+	byName bool
 }
 
 func newQueryNode(et *ExecutingTask, n *pipeline.QueryNode, d NodeDiagnostic) (*QueryNode, error) {
 	bn := &QueryNode{
-		node:     node{Node: n, et: et, diag: d},
-		b:        n,
-		closing:  make(chan struct{}),
-		aborting: make(chan struct{}),
-		byName:   n.GroupByMeasurementFlag,
+		batchQueryNode: batchQueryNode{node: node{Node: n, et: et, diag: d}},
+		b:              n,
+		byName:         n.GroupByMeasurementFlag,
 	}
-	bn.node.runF = bn.runBatch
-	bn.node.stopF = bn.stopBatch
 
 	// Create query
 	q, err := NewQuery(n.QueryStr)
@@ -226,22 +379,8 @@ func newQueryNode(et *ExecutingTask, n *pipeline.QueryNode, d NodeDiagnostic) (*
 	}
 
 	// Determine schedule
-	if n.Every != 0 && n.Cron != "" {
-		return nil, errors.New("must not set both 'every' and 'cron' properties")
-	}
-	switch {
-	case n.Every > 0:
-		bn.ticker = newTimeTicker(n.Every, n.AlignFlag)
-	case n.Cron != "":
-		var err error
-		bn.ticker, err = newCronTicker(n.Cron)
-		if err != nil {
-			return nil, err
-		}
-	case n.Every < 0:
-		return nil, errors.New("'every' duration must must non-negative")
-	default:
-		return nil, errors.New("must define one of 'every' or 'cron'")
+	if err := bn.schedule(n.Every, n.Cron, n.AlignFlag, bn.connect); err != nil {
+		return nil, err
 	}
 
 	return bn, nil
@@ -257,19 +396,6 @@ func (n *QueryNode) DBRPs() ([]DBRP, error) {
 	return n.query.DBRPs()
 }
 
-func (n *QueryNode) Start() {
-	n.queryMu.Lock()
-	defer n.queryMu.Unlock()
-	n.queryErr = make(chan error, 1)
-	go func() {
-		n.queryErr <- n.doQuery(n.ins[0])
-	}()
-}
-
-func (n *QueryNode) Abort() {
-	close(n.aborting)
-}
-
 func (n *QueryNode) Cluster() string {
 	return n.b.Cluster
 }
@@ -304,129 +430,51 @@ func (n *QueryNode) Queries(start, stop time.Time) ([]*Query, error) {
 	return queries, nil
 }
 
-// Query InfluxDB and collect batches on batch collector.
-func (n *QueryNode) doQuery(in edge.Edge) error {
-	defer in.Close()
-	n.batchesQueried = &expvar.Int{}
-	n.pointsQueried = &expvar.Int{}
-
-	n.statMap.Set(statsBatchesQueried, n.batchesQueried)
-	n.statMap.Set(statsPointsQueried, n.pointsQueried)
-
+// connect returns the function that queries InfluxDB for the batches of a tick.
+func (n *QueryNode) connect() (batchQueryFunc, error) {
 	if n.et.tm.InfluxDBService == nil {
-		return errors.New("InfluxDB not configured, cannot query InfluxDB for batch query")
+		return nil, errors.New("InfluxDB not configured, cannot query InfluxDB for batch query")
 	}
 
 	con, err := n.et.tm.InfluxDBService.NewNamedClient(n.b.Cluster)
 	if err != nil {
-		return errors.Wrap(err, "failed to get InfluxDB client")
+		return nil, errors.Wrap(err, "failed to get InfluxDB client")
 	}
-	tickC := n.ticker.Start()
-	for {
-		select {
-		case <-n.closing:
-			return nil
-		case <-n.aborting:
-			return errors.New("batch doQuery aborted")
-		case now := <-tickC:
-			n.timer.Start()
-			// Update times for query
-			stop := now.Add(-1 * n.b.Offset)
-			n.query.SetStartTime(stop.Add(-1 * n.b.Period))
-			n.query.SetStopTime(stop)
+	return func(now time.Time) ([]edge.BufferedBatchMessage, error) {
+		// Update times for query
+		stop := now.Add(-1 * n.b.Offset)
+		n.query.SetStartTime(stop.Add(-1 * n.b.Period))
+		n.query.SetStopTime(stop)
 
-			qStr := n.query.String()
-			n.diag.StartingBatchQuery(qStr)
+		qStr := n.query.String()
+		n.diag.StartingBatchQuery(qStr)
 
-			// Execute query
-			q := influxdb.Query{
-				Command: qStr,
-			}
-			resp, err := con.Query(q)
-			if err != nil {
-				n.diag.Error("error executing query", err)
-				n.timer.Stop()
-				break
-			}
-
-			// Collect batches
-			for _, res := range resp.Results {
-				batches, err := edge.ResultToBufferedBatches(res, n.byName)
-				if err != nil {
-					n.diag.Error("failed to understand query result", err)
-					continue
-				}
-				for _, bch := range batches {
-					// Set stop time based off query bounds
-					if bch.Begin().Time().IsZero() || !n.query.IsGroupedByTime() {
-						bch.Begin().SetTime(stop)
-					}
-
-					n.batchesQueried.Add(1)
-					n.pointsQueried.Add(int64(len(bch.Points())))
-
-					n.timer.Pause()
-					if err := in.Collect(bch); err != nil {
-						return err
-					}
-					n.timer.Resume()
-				}
-			}
-			n.timer.Stop()
+		// Execute query
+		q := influxdb.Query{
+			Command: qStr,
+		}
+		resp, err := con.Query(q)
+		if err != nil {
+			return nil, err
 		}
-	}
-}
 
-func (n *QueryNode) runBatch([]byte) error {
-	errC := make(chan error, 1)
-	go func() {
-		defer func() {
-			err := recover()
+		var batches []edge.BufferedBatchMessage
+		for _, res := range resp.Results {
+			bs, err := edge.ResultToBufferedBatches(res, n.byName)
 			if err != nil {
-				errC <- fmt.Errorf("%v", err)
+				n.diag.Error("failed to understand query result", err)
+				continue
 			}
-		}()
-		for bt, ok := n.ins[0].Emit(); ok; bt, ok = n.ins[0].Emit() {
-			for _, child := range n.outs {
-				err := child.Collect(bt)
-				if err != nil {
-					errC <- err
-					return
+			for _, bch := range bs {
+				// Set stop time based off query bounds
+				if bch.Begin().Time().IsZero() || !n.query.IsGroupedByTime() {
+					bch.Begin().SetTime(stop)
 				}
+				batches = append(batches, bch)
 			}
 		}
-		errC <- nil
-	}()
-	var queryErr error
-	n.queryMu.Lock()
-	if n.queryErr != nil {
-		n.queryMu.Unlock()
-		select {
-		case queryErr = <-n.queryErr:
-		case <-n.aborting:
-			queryErr = errors.New("batch queryErr aborted")
-		}
-	} else {
-		n.queryMu.Unlock()
-	}
-
-	var err error
-	select {
-	case err = <-errC:
-	case <-n.aborting:
-		err = errors.New("batch run aborted")
-	}
-	if queryErr != nil {
-		return queryErr
-	}
-	return err
-}
-
-func (n *QueryNode) stopBatch() {
-	if n.ticker != nil {
-		n.ticker.Stop()
-	}
-	close(n.closing)
+		return batches, nil
+	}, nil
 }
 
 type ticker interface {
@@ -565,29 +613,17 @@ func (c *cronTicker) Next(now time.Time) time.Time {
 
 // FluxQueryNode is a node for making flux queries
 type FluxQueryNode struct {
-	node
-	b        *pipeline.QueryFluxNode
-	query    *QueryFlux
-	ticker   ticker
-	queryMu  sync.Mutex
-	queryErr chan error
-	closing  chan struct{}
-	aborting chan struct{}
-
-	batchesQueried *expvar.Int
-	pointsQueried  *expvar.Int
-	byName         bool
+	batchQueryNode
+	b      *pipeline.QueryFluxNode
+	query  *QueryFlux
+	byName bool
 }
 
 func newQueryFluxNode(et *ExecutingTask, n *pipeline.QueryFluxNode, d NodeDiagnostic) (*FluxQueryNode, error) {
 	bn := &FluxQueryNode{
-		node:     node{Node: n, et: et, diag: d},
-		b:        n,
-		closing:  make(chan struct{}),
-		aborting: make(chan struct{}),
+		batchQueryNode: batchQueryNode{node: node{Node: n, et: et, diag: d}},
+		b:              n,
 	}
-	bn.node.runF = bn.runBatch
-	bn.node.stopF = bn.stopBatch
 
 	// Create query
 	q, err := NewQueryFlux(n.QueryStr, n.Org, n.OrgID)
@@ -596,40 +632,13 @@ func newQueryFluxNode(et *ExecutingTask, n *pipeline.QueryFluxNode, d NodeDiagno
 	}
 	bn.query = q
 	// Determine schedule
-	if n.Every != 0 && n.Cron != "" {
-		return nil, errors.New("must not set both 'every' and 'cron' properties")
-	}
-	switch {
-	case n.Every > 0:
-		bn.ticker = newTimeTicker(n.Every, n.AlignFlag)
-	case n.Cron != "":
-		var err error
-		bn.ticker, err = newCronTicker(n.Cron)
-		if err != nil {
-			return nil, err
-		}
-	case n.Every < 0:
-		return nil, errors.New("'every' duration must must non-negative")
-	default:
-		return nil, errors.New("must define one of 'every' or 'cron'")
+	if err := bn.schedule(n.Every, n.Cron, n.AlignFlag, bn.connect); err != nil {
+		return nil, err
 	}
 
 	return bn, nil
 }
 
-func (n *FluxQueryNode) Start() {
-	n.queryMu.Lock()
-	defer n.queryMu.Unlock()
-	n.queryErr = make(chan error, 1)
-	go func() {
-		n.queryErr <- n.doQuery(n.ins[0])
-	}()
-}
-
-func (n *FluxQueryNode) Abort() {
-	close(n.aborting)
-}
-
 func (n *FluxQueryNode) Cluster() string {
 	return n.b.Cluster
 }
@@ -663,123 +672,47 @@ func (n *FluxQueryNode) Queries(start, stop time.Time) ([]*QueryFlux, error) {
 	return queries, nil
 }
 
-// Query InfluxDB and collect batches on batch collector.
-func (n *FluxQueryNode) doQuery(in edge.Edge) (err error) {
-	defer in.Close()
-	n.batchesQueried = &expvar.Int{}
-	n.pointsQueried = &expvar.Int{}
-
-	n.statMap.Set(statsBatchesQueried, n.batchesQueried)
-	n.statMap.Set(statsPointsQueried, n.pointsQueried)
-
+// connect returns the function that runs the flux query for the batches of a tick.
+func (n *FluxQueryNode) connect() (batchQueryFunc, error) {
 	if n.et.tm.InfluxDBService == nil {
-		return errors.New("InfluxDB not configured, cannot query InfluxDB for batch query")
+		return nil, errors.New("InfluxDB not configured, cannot query InfluxDB for batch query")
 	}
 
 	con, err := n.et.tm.InfluxDBService.NewNamedClient(n.b.Cluster)
 	if err != nil {
-		return errors.Wrap(err, "failed to get InfluxDB client")
-	}
-	tickC := n.ticker.Start()
-	for {
-		select {
-		case <-n.closing:
-			return nil
-		case <-n.aborting:
-			return errors.New("batch doQuery aborted")
-		case now := <-tickC:
-			n.timer.Start()
-			// Update times for query
-			n.query.Now = now.Add(-1 * n.b.Offset) //SetStartTime(stop.Add(-1 * n.b.Period))
-			n.diag.StartingBatchQuery(n.query.stmt)
-
-			// Execute query
-			resp, err := con.QueryFluxResponse(influxdb.FluxQuery{
-				Query: n.query.stmt,
-				Org:   n.query.org,
-				OrgID: n.query.orgID,
-				Now:   n.query.Now,
-			})
-			if err != nil {
-				n.diag.Error("error executing query", err)
-				n.timer.Stop()
-				break
-			}
-			//Collect batches
-			for _, res := range resp.Results {
-				batches, err := edge.ResultToBufferedBatches(res, n.byName)
-				if err != nil {
-					n.diag.Error("failed to understand query result", err)
-					continue
-				}
-				for _, bch := range batches {
-					// Set stop time based off query bounds
-					if bch.Begin().Time().IsZero() {
-						bch.Begin().SetTime(now)
-					}
-					n.batchesQueried.Add(1)
-					n.pointsQueried.Add(int64(len(bch.Points())))
-
-					n.timer.Pause()
-					if err := in.Collect(bch); err != nil {
-						return err
-					}
-					n.timer.Resume()
-				}
-			}
-			n.timer.Stop()
+		return nil, errors.Wrap(err, "failed to get InfluxDB client")
+	}
+	return func(now time.Time) ([]edge.BufferedBatchMessage, error) {
+		// Update times for query
+		n.query.Now = now.Add(-1 * n.b.Offset)
+		n.diag.StartingBatchQuery(n.query.stmt)
+
+		// Execute query
+		resp, err := con.QueryFluxResponse(influxdb.FluxQuery{
+			Query: n.query.stmt,
+			Org:   n.query.org,
+			OrgID: n.query.orgID,
+			Now:   n.query.Now,
+		})
+		if err != nil {
+			return nil, err
 		}
-	}
-}
 
-func (n *FluxQueryNode) runBatch([]byte) error {
-	errC := make(chan error, 1)
-	go func() {
-		defer func() {
-			err := recover()
+		var batches []edge.BufferedBatchMessage
+		for _, res := range resp.Results {
+			bs, err := edge.ResultToBufferedBatches(res, n.byName)
 			if err != nil {
-				errC <- fmt.Errorf("%v", err)
+				n.diag.Error("failed to understand query result", err)
+				continue
 			}
-		}()
-		for bt, ok := n.ins[0].Emit(); ok; bt, ok = n.ins[0].Emit() {
-			for _, child := range n.outs {
-				err := child.Collect(bt)
-				if err != nil {
-					errC <- err
-					return
+			for _, bch := range bs {
+				// Set stop time based off query bounds
+				if bch.Begin().Time().IsZero() {
+					bch.Begin().SetTime(now)
 				}
+				batches = append(batches, bch)
 			}
 		}
-		errC <- nil
-	}()
-	var queryErr error
-	n.queryMu.Lock()
-	if n.queryErr != nil {
-		n.queryMu.Unlock()
-		select {
-		case queryErr = <-n.queryErr:
-		case <-n.aborting:
-			queryErr = errors.New("batch queryErr aborted")
-		}
-	} else {
-		n.queryMu.Unlock()
-	}
-
-	var err error
-	select {
-	case err = <-errC:
-	case <-n.aborting:
-		err = errors.New("batch run aborted")
-	}
-	if queryErr != nil {
-		return queryErr
-	}
-	return err
-}
-
-func (n *FluxQueryNode) stopBatch() {
-	if n.ticker != nil {
-		n.ticker.Stop()
-	}
-	close(n.closing)
+		return batches, nil
+	}, nil
 }
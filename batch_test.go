@@ -0,0 +1,40 @@
+package kapacitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchQueryNode_Schedule(t *testing.T) {
+	connect := func() (batchQueryFunc, error) { return nil, nil }
+	testCases := []struct {
+		name  string
+		every time.Duration
+		cron  string
+		err   string
+	}{
+		{name: "every", every: time.Minute},
+		{name: "cron", cron: "0 * * * *"},
+		{name: "both", every: time.Minute, cron: "0 * * * *", err: "must not set both 'every' and 'cron' properties"},
+		{name: "negative every", every: -time.Minute, err: "'every' duration must be non-negative"},
+		{name: "neither", err: "must define one of 'every' or 'cron'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &batchQueryNode{}
+			err := n.schedule(tc.every, tc.cron, false, connect)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, n.ticker)
+			assert.NotNil(t, n.connectF)
+			assert.NotNil(t, n.runF)
+			assert.NotNil(t, n.stopF)
+		})
+	}
+}
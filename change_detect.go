@@ -12,6 +12,8 @@ import (
 type ChangeDetectNode struct {
 	node
 	d *pipeline.ChangeDetectNode
+
+	states groupSnapshotter[changeDetectState, *changeDetectGroup]
 }
 
 // Create a new changeDetect node.
@@ -25,7 +27,12 @@ func newChangeDetectNode(et *ExecutingTask, n *pipeline.ChangeDetectNode, d Node
 	return dn, nil
 }
 
-func (n *ChangeDetectNode) runChangeDetect([]byte) error {
+func (n *ChangeDetectNode) runChangeDetect(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore change detect state", err)
+		}
+	}
 	consumer := edge.NewGroupedConsumer(
 		n.ins[0],
 		n,
@@ -37,10 +44,18 @@ func (n *ChangeDetectNode) runChangeDetect([]byte) error {
 func (n *ChangeDetectNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.Receiver, error) {
 	return edge.NewReceiverFromForwardReceiverWithStats(
 		n.outs,
-		edge.NewTimedForwardReceiver(n.timer, n.newGroup()),
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, n.newGroup())),
 	), nil
 }
 
+func (n *ChangeDetectNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *ChangeDetectNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
 func (n *ChangeDetectNode) newGroup() *changeDetectGroup {
 	return &changeDetectGroup{
 		n: n,
@@ -52,6 +67,25 @@ type changeDetectGroup struct {
 	previous edge.FieldsTagsTimeGetter
 }
 
+type changeDetectState struct {
+	Previous *pointSnapshot
+}
+
+func (g *changeDetectGroup) snapshot() changeDetectState {
+	var state changeDetectState
+	if g.previous != nil {
+		p := newPointSnapshot(g.previous)
+		state.Previous = &p
+	}
+	return state
+}
+
+func (g *changeDetectGroup) restore(state changeDetectState) {
+	if state.Previous != nil {
+		g.previous = state.Previous.batchPoint()
+	}
+}
+
 func (g *changeDetectGroup) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
 	if s := begin.SizeHint(); s > 0 {
 		begin = begin.ShallowCopy()
@@ -31,31 +31,44 @@ const DefaultUserAgent = "KapacitorClient"
 // then use the appropriate *Link methods.
 
 const (
-	basePath          = "/kapacitor/v1"
-	basePreviewPath   = "/kapacitor/v1preview"
-	pingPath          = basePath + "/ping"
-	logLevelPath      = basePath + "/loglevel"
-	logsPath          = basePreviewPath + "/logs"
-	debugVarsPath     = basePath + "/debug/vars"
-	tasksPath         = basePath + "/tasks"
-	templatesPath     = basePath + "/templates"
-	recordingsPath    = basePath + "/recordings"
-	recordStreamPath  = basePath + "/recordings/stream"
-	recordBatchPath   = basePath + "/recordings/batch"
-	recordQueryPath   = basePath + "/recordings/query"
-	replaysPath       = basePath + "/replays"
-	replayBatchPath   = basePath + "/replays/batch"
-	replayQueryPath   = basePath + "/replays/query"
-	usersPath         = basePath + "/users"
-	configPath        = basePath + "/config"
-	serviceTestsPath  = basePath + "/service-tests"
-	alertsPath        = basePath + "/alerts"
-	topicsPath        = alertsPath + "/topics"
-	topicEventsPath   = "events"
-	topicHandlersPath = "handlers"
-	storagePath       = basePath + "/storage"
-	storesPath        = storagePath + "/stores"
-	backupPath        = storagePath + "/backup"
+	basePath              = "/kapacitor/v1"
+	basePreviewPath       = "/kapacitor/v1preview"
+	pingPath              = basePath + "/ping"
+	logLevelPath          = basePath + "/loglevel"
+	logsPath              = basePreviewPath + "/logs"
+	debugVarsPath         = basePath + "/debug/vars"
+	tasksPath             = basePath + "/tasks"
+	templatesPath         = basePath + "/templates"
+	librariesPath         = basePath + "/libraries"
+	recordingsPath        = basePath + "/recordings"
+	recordStreamPath      = basePath + "/recordings/stream"
+	recordBatchPath       = basePath + "/recordings/batch"
+	recordQueryPath       = basePath + "/recordings/query"
+	recordUploadPath      = basePath + "/recordings/upload"
+	replaysPath           = basePath + "/replays"
+	replayBatchPath       = basePath + "/replays/batch"
+	replayQueryPath       = basePath + "/replays/query"
+	replayComparePath     = basePath + "/replays/compare"
+	usersPath             = basePath + "/users"
+	configPath            = basePath + "/config"
+	serviceTestsPath      = basePath + "/service-tests"
+	alertsPath            = basePath + "/alerts"
+	topicsPath            = alertsPath + "/topics"
+	topicEventsPath       = "events"
+	topicEventAckPath     = "ack"
+	topicEventHistoryPath = "history"
+	topicHandlersPath     = "handlers"
+	deadLettersPath       = "dead-letters"
+	deadLettersReplayPath = "replay"
+	topicSilencesPath     = "silences"
+	storagePath           = basePath + "/storage"
+	storesPath            = storagePath + "/stores"
+	backupPath            = storagePath + "/backup"
+	blobsPath             = basePath + "/blobs"
+	blobTagsPath          = blobsPath + "/tags"
+	schedulesPath         = basePath + "/schedules"
+	taskTestsPath         = basePath + "/task-tests"
+	debugSessionsPath     = basePath + "/debug-sessions"
 )
 
 type UserType int
@@ -845,6 +858,8 @@ type Task struct {
 	Created        time.Time      `json:"created"`
 	Modified       time.Time      `json:"modified"`
 	LastEnabled    time.Time      `json:"last-enabled,omitempty"`
+	// ShadowOf is the ID of the task this task shadows, empty if it is not a shadow task.
+	ShadowOf string `json:"shadow-of,omitempty"`
 }
 
 // A Template plus its read-only attributes.
@@ -860,6 +875,15 @@ type Template struct {
 	Modified   time.Time `json:"modified"`
 }
 
+// A Library of TICKscript definitions plus its read-only attributes.
+type Library struct {
+	Link       Link      `json:"link"`
+	ID         string    `json:"id"`
+	TICKscript string    `json:"script"`
+	Created    time.Time `json:"created"`
+	Modified   time.Time `json:"modified"`
+}
+
 // Information about a recording.
 type Recording struct {
 	Link     Link      `json:"link"`
@@ -870,6 +894,9 @@ type Recording struct {
 	Error    string    `json:"error"`
 	Status   Status    `json:"status"`
 	Progress float64   `json:"progress"`
+	// Start and Stop are the time range of uploaded recordings.
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
 }
 
 // Information about a replay.
@@ -1043,6 +1070,10 @@ func (c *Client) TemplateLink(id string) Link {
 	return Link{Relation: Self, Href: path.Join(templatesPath, id)}
 }
 
+func (c *Client) LibraryLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(librariesPath, id)}
+}
+
 func (c *Client) ConfigSectionLink(section string) Link {
 	return Link{Relation: Self, Href: path.Join(configPath, section)}
 }
@@ -1070,12 +1101,30 @@ func (c *Client) TopicEventLink(topic, event string) Link {
 	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicEventsPath, event)}
 }
 
+func (c *Client) TopicEventAckLink(topic, event string) Link {
+	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicEventsPath, event, topicEventAckPath)}
+}
+
+func (c *Client) TopicEventHistoryLink(topic, event string) Link {
+	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicEventsPath, event, topicEventHistoryPath)}
+}
+
+func (c *Client) TopicSilencesLink(topic string) Link {
+	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicSilencesPath)}
+}
+func (c *Client) TopicSilenceLink(topic, id string) Link {
+	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicSilencesPath, id)}
+}
+
 func (c *Client) TopicHandlersLink(topic string) Link {
 	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicHandlersPath)}
 }
 func (c *Client) TopicHandlerLink(topic, id string) Link {
 	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicHandlersPath, id)}
 }
+func (c *Client) TopicHandlerDeadLettersLink(topic, id string) Link {
+	return Link{Relation: Self, Href: path.Join(topicsPath, topic, topicHandlersPath, id, deadLettersPath)}
+}
 func (c *Client) StorageLink(name string) Link {
 	return Link{Relation: Self, Href: path.Join(storesPath, name)}
 }
@@ -1088,6 +1137,9 @@ type CreateTaskOptions struct {
 	TICKscript string     `json:"script,omitempty"`
 	Status     TaskStatus `json:"status,omitempty"`
 	Vars       Vars       `json:"vars,omitempty" yaml:"vars"`
+	// ShadowOf makes the task a shadow of the task with that ID.
+	// A shadow task records its alerts and writes instead of sending them.
+	ShadowOf string `json:"shadow-of,omitempty" yaml:"shadow-of"`
 }
 
 // Create a new task.
@@ -1430,225 +1482,317 @@ func (c *Client) DeleteTemplate(link Link) error {
 	return err
 }
 
-type ListTemplatesOptions struct {
-	TemplateOptions
-	Pattern string
-	Fields  []string
-	Offset  int
-	Limit   int
+// A Revision is a recorded definition of a task or template.
+// A new revision is recorded each time the definition changes.
+type Revision struct {
+	Link       Link      `json:"link"`
+	Number     int       `json:"revision"`
+	Type       TaskType  `json:"type"`
+	TemplateID string    `json:"template-id,omitempty"`
+	DBRPs      []DBRP    `json:"dbrps,omitempty"`
+	TICKscript string    `json:"script"`
+	Vars       Vars      `json:"vars,omitempty"`
+	Author     string    `json:"author"`
+	Created    time.Time `json:"created"`
+	// RollbackOf is the number of the revision that was rolled back to, zero if the revision is not a rollback.
+	RollbackOf int `json:"rollback-of,omitempty"`
 }
 
-func (o *ListTemplatesOptions) Default() {
-	o.TemplateOptions.Default()
-	if o.Limit == 0 {
-		o.Limit = 100
-	}
+type Revisions struct {
+	Link      Link       `json:"link"`
+	Revisions []Revision `json:"revisions"`
 }
 
-func (o *ListTemplatesOptions) Values() *url.Values {
-	v := o.TemplateOptions.Values()
-	v.Set("pattern", o.Pattern)
-	for _, field := range o.Fields {
-		v.Add("fields", field)
+// RevisionDiff contains unified diffs between two revisions.
+// Each diff is empty if that part of the definition did not change.
+type RevisionDiff struct {
+	Link       Link   `json:"link"`
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+	TICKscript string `json:"script"`
+	DBRPs      string `json:"dbrps"`
+	Vars       string `json:"vars"`
+}
+
+type RevisionDiffOptions struct {
+	// From defaults to the revision before To.
+	From int
+	// To defaults to the latest revision.
+	To int
+}
+
+func (o *RevisionDiffOptions) Values() *url.Values {
+	v := &url.Values{}
+	if o.From > 0 {
+		v.Set("from", strconv.Itoa(o.From))
+	}
+	if o.To > 0 {
+		v.Set("to", strconv.Itoa(o.To))
 	}
-	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
-	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
 	return v
 }
 
-// Get templates.
-func (c *Client) ListTemplates(opt *ListTemplatesOptions) ([]Template, error) {
-	if opt == nil {
-		opt = new(ListTemplatesOptions)
+type RollbackOptions struct {
+	Revision int `json:"revision"`
+}
+
+// ListRevisions returns the revisions of a task or template, oldest first.
+func (c *Client) ListRevisions(link Link) ([]Revision, error) {
+	if link.Href == "" {
+		return nil, fmt.Errorf("invalid link %v", link)
 	}
-	opt.Default()
 
 	u := *c.url
-	u.Path = templatesPath
-	u.RawQuery = opt.Values().Encode()
+	u.Path = link.Href + "/revisions"
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Response type
-	type response struct {
-		Templates []Template `json:"templates"`
-	}
-
-	r := &response{}
-
-	_, err = c.Do(req, r, http.StatusOK)
+	r := Revisions{}
+	_, err = c.Do(req, &r, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
-	return r.Templates, nil
+	return r.Revisions, nil
 }
 
-// Get information about a recording.
-func (c *Client) Recording(link Link) (Recording, error) {
-	r := Recording{}
+// Revision returns a single revision of a task or template.
+func (c *Client) Revision(link Link, revision int) (Revision, error) {
+	r := Revision{}
 	if link.Href == "" {
 		return r, fmt.Errorf("invalid link %v", link)
 	}
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = link.Href + "/revisions/" + strconv.Itoa(revision)
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return r, err
 	}
 
-	_, err = c.Do(req, &r, http.StatusOK, http.StatusAccepted)
-	if err != nil {
-		return r, err
-	}
-	return r, nil
+	_, err = c.Do(req, &r, http.StatusOK)
+	return r, err
 }
 
-func (c *Client) RecordingLink(id string) Link {
-	return Link{Relation: Self, Href: path.Join(recordingsPath, id)}
-}
+// DiffRevisions compares two revisions of a task or template.
+// Options can be nil, in which case the latest revision is compared to the one before it.
+func (c *Client) DiffRevisions(link Link, opt *RevisionDiffOptions) (RevisionDiff, error) {
+	d := RevisionDiff{}
+	if link.Href == "" {
+		return d, fmt.Errorf("invalid link %v", link)
+	}
+	if opt == nil {
+		opt = new(RevisionDiffOptions)
+	}
 
-type RecordStreamOptions struct {
-	ID   string    `json:"id,omitempty"`
-	Task string    `json:"task"`
-	Stop time.Time `json:"stop"`
+	u := *c.url
+	u.Path = link.Href + "/revisions/diff"
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return d, err
+	}
+
+	_, err = c.Do(req, &d, http.StatusOK)
+	return d, err
 }
 
-// Record the stream for a task.
-// Returns once the recording is started.
-func (c *Client) RecordStream(opt RecordStreamOptions) (Recording, error) {
-	r := Recording{}
+func (c *Client) rollback(link Link, revision int, result interface{}) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
 
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
-	err := enc.Encode(opt)
+	err := enc.Encode(RollbackOptions{Revision: revision})
 	if err != nil {
-		return r, err
+		return err
 	}
 
 	u := *c.url
-	u.Path = recordStreamPath
+	u.Path = link.Href + "/rollback"
 
 	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return r, err
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &r, http.StatusCreated)
-	if err != nil {
-		return r, err
-	}
-	return r, nil
+	_, err = c.Do(req, result, http.StatusOK)
+	return err
 }
 
-type RecordBatchOptions struct {
-	ID    string    `json:"id,omitempty"`
-	Task  string    `json:"task"`
-	Start time.Time `json:"start"`
-	Stop  time.Time `json:"stop"`
+// RollbackTask restores the definition of a task from one of its revisions.
+// The rollback is recorded as a new revision.
+func (c *Client) RollbackTask(link Link, revision int) (Task, error) {
+	t := Task{}
+	err := c.rollback(link, revision, &t)
+	return t, err
 }
 
-// Record the batch queries for a task.
-// Returns once the recording is started.
-func (c *Client) RecordBatch(opt RecordBatchOptions) (Recording, error) {
-	r := Recording{}
+// RollbackTemplate restores the definition of a template from one of its revisions
+// and updates all tasks using the template.
+// The rollback is recorded as a new revision.
+func (c *Client) RollbackTemplate(link Link, revision int) (Template, error) {
+	t := Template{}
+	err := c.rollback(link, revision, &t)
+	return t, err
+}
 
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(opt)
-	if err != nil {
-		return r, err
-	}
+// ShadowRecord is an alert or write a shadow task recorded instead of sending it.
+// The alerts of a task are recorded as well while it has an executing shadow.
+type ShadowRecord struct {
+	// Type is one of alert, influxdb, httppost, loopback or autoscale.
+	Type string    `json:"type"`
+	Node string    `json:"node"`
+	Time time.Time `json:"time"`
+	// ID is the ID of the alert or of the scaled resource.
+	ID              string `json:"id,omitempty"`
+	Level           string `json:"level,omitempty"`
+	Message         string `json:"message,omitempty"`
+	Database        string `json:"db,omitempty"`
+	RetentionPolicy string `json:"rp,omitempty"`
+	Measurement     string `json:"measurement,omitempty"`
+	Points          int    `json:"points,omitempty"`
+	URL             string `json:"url,omitempty"`
+	Replicas        int    `json:"replicas,omitempty"`
+}
+
+type ShadowLog struct {
+	Link     Link           `json:"link"`
+	Task     string         `json:"task"`
+	ShadowOf string         `json:"shadow-of,omitempty"`
+	Records  []ShadowRecord `json:"records"`
+}
 
-	u := *c.url
-	u.Path = recordBatchPath
+type ShadowOptions struct {
+	// Start and Stop bound the time range, zero values leave the range unbounded.
+	Start time.Time
+	Stop  time.Time
+}
 
-	req, err := http.NewRequest("POST", u.String(), &buf)
-	if err != nil {
-		return r, err
+func (o *ShadowOptions) Values() *url.Values {
+	v := &url.Values{}
+	if !o.Start.IsZero() {
+		v.Set("start", o.Start.Format(time.RFC3339Nano))
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	_, err = c.Do(req, &r, http.StatusCreated)
-	if err != nil {
-		return r, err
+	if !o.Stop.IsZero() {
+		v.Set("stop", o.Stop.Format(time.RFC3339Nano))
 	}
-	return r, nil
+	return v
 }
 
-type RecordQueryOptions struct {
-	ID      string   `json:"id,omitempty"`
-	Query   string   `json:"query"`
-	Type    TaskType `json:"type"`
-	Cluster string   `json:"cluster,omitempty"`
+// ShadowAlertStatus is the outcome of comparing the alerts with the same ID of a shadow task and its primary task.
+type ShadowAlertStatus string
+
+const (
+	// ShadowAlertMatch means both tasks changed the alert to the same levels.
+	ShadowAlertMatch ShadowAlertStatus = "match"
+	// ShadowAlertDifferent means the tasks changed the alert to different levels.
+	ShadowAlertDifferent ShadowAlertStatus = "different"
+	// ShadowAlertPrimaryOnly means only the primary task triggered the alert.
+	ShadowAlertPrimaryOnly ShadowAlertStatus = "primary-only"
+	// ShadowAlertShadowOnly means only the shadow task triggered the alert.
+	ShadowAlertShadowOnly ShadowAlertStatus = "shadow-only"
+)
+
+type ShadowAlertEvent struct {
+	Time  time.Time `json:"time"`
+	Level string    `json:"level"`
 }
 
-// Record the results of a query.
-// The recordingType must be one of "stream", or "batch".
-// Returns once the recording is started.
-func (c *Client) RecordQuery(opt RecordQueryOptions) (Recording, error) {
-	r := Recording{}
+type ShadowAlertComparison struct {
+	ID      string             `json:"id"`
+	Status  ShadowAlertStatus  `json:"status"`
+	Primary []ShadowAlertEvent `json:"primary"`
+	Shadow  []ShadowAlertEvent `json:"shadow"`
+}
 
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(opt)
-	if err != nil {
-		return r, err
+type ShadowComparisonSummary struct {
+	Match       int `json:"match"`
+	Different   int `json:"different"`
+	PrimaryOnly int `json:"primary-only"`
+	ShadowOnly  int `json:"shadow-only"`
+}
+
+// ShadowComparison compares the alerts of a shadow task with those of its primary task, grouped by alert ID.
+type ShadowComparison struct {
+	Link     Link                    `json:"link"`
+	Task     string                  `json:"task"`
+	ShadowOf string                  `json:"shadow-of"`
+	Summary  ShadowComparisonSummary `json:"summary"`
+	Alerts   []ShadowAlertComparison `json:"alerts"`
+}
+
+// ShadowLog returns the records of a task, ordered by time.
+// Options can be nil, in which case all records are returned.
+func (c *Client) ShadowLog(link Link, opt *ShadowOptions) (ShadowLog, error) {
+	l := ShadowLog{}
+	if link.Href == "" {
+		return l, fmt.Errorf("invalid link %v", link)
+	}
+	if opt == nil {
+		opt = new(ShadowOptions)
 	}
 
 	u := *c.url
-	u.Path = recordQueryPath
+	u.Path = link.Href + "/shadow"
+	u.RawQuery = opt.Values().Encode()
 
-	req, err := http.NewRequest("POST", u.String(), &buf)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return r, err
+		return l, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &r, http.StatusCreated)
-	if err != nil {
-		return r, err
-	}
-	return r, nil
+	_, err = c.Do(req, &l, http.StatusOK)
+	return l, err
 }
 
-// Delete a recording.
-func (c *Client) DeleteRecording(link Link) error {
+// CompareShadow compares the alerts of a shadow task with those of the task it shadows.
+// Options can be nil, in which case all recorded alerts are compared.
+func (c *Client) CompareShadow(link Link, opt *ShadowOptions) (ShadowComparison, error) {
+	cmp := ShadowComparison{}
 	if link.Href == "" {
-		return fmt.Errorf("invalid link %v", link)
+		return cmp, fmt.Errorf("invalid link %v", link)
+	}
+	if opt == nil {
+		opt = new(ShadowOptions)
 	}
+
 	u := *c.url
-	u.Path = link.Href
+	u.Path = link.Href + "/shadow/compare"
+	u.RawQuery = opt.Values().Encode()
 
-	req, err := http.NewRequest("DELETE", u.String(), nil)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return err
+		return cmp, err
 	}
 
-	_, err = c.Do(req, nil, http.StatusNoContent)
-	return err
+	_, err = c.Do(req, &cmp, http.StatusOK)
+	return cmp, err
 }
 
-type ListRecordingsOptions struct {
+type ListTemplatesOptions struct {
+	TemplateOptions
 	Pattern string
 	Fields  []string
 	Offset  int
 	Limit   int
 }
 
-func (o *ListRecordingsOptions) Default() {
+func (o *ListTemplatesOptions) Default() {
+	o.TemplateOptions.Default()
 	if o.Limit == 0 {
 		o.Limit = 100
 	}
 }
 
-func (o *ListRecordingsOptions) Values() *url.Values {
-	v := &url.Values{}
+func (o *ListTemplatesOptions) Values() *url.Values {
+	v := o.TemplateOptions.Values()
 	v.Set("pattern", o.Pattern)
 	for _, field := range o.Fields {
 		v.Add("fields", field)
@@ -1658,24 +1802,25 @@ func (o *ListRecordingsOptions) Values() *url.Values {
 	return v
 }
 
-// Get information about recordings.
-// If rids is empty than all recordings are returned.
-func (c *Client) ListRecordings(opt *ListRecordingsOptions) ([]Recording, error) {
+// Get templates.
+func (c *Client) ListTemplates(opt *ListTemplatesOptions) ([]Template, error) {
 	if opt == nil {
-		opt = new(ListRecordingsOptions)
+		opt = new(ListTemplatesOptions)
 	}
 	opt.Default()
+
 	u := *c.url
-	u.Path = recordingsPath
+	u.Path = templatesPath
 	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	// Decode valid response
+
+	// Response type
 	type response struct {
-		Recordings []Recording `json:"recordings"`
+		Templates []Template `json:"templates"`
 	}
 
 	r := &response{}
@@ -1684,152 +1829,125 @@ func (c *Client) ListRecordings(opt *ListRecordingsOptions) ([]Recording, error)
 	if err != nil {
 		return nil, err
 	}
-	return r.Recordings, nil
-}
-
-func (c *Client) ReplayLink(id string) Link {
-	return Link{Relation: Self, Href: path.Join(replaysPath, id)}
-}
-
-type CreateReplayOptions struct {
-	ID            string `json:"id"`
-	Recording     string `json:"recording"`
-	Task          string `json:"task"`
-	RecordingTime bool   `json:"recording-time"`
-	Clock         Clock  `json:"clock"`
+	return r.Templates, nil
 }
 
-func (o *CreateReplayOptions) Default() {
+type CreateLibraryOptions struct {
+	ID         string `json:"id,omitempty"`
+	TICKscript string `json:"script,omitempty"`
 }
 
-// Replay a recording for a task.
-func (c *Client) CreateReplay(opt CreateReplayOptions) (Replay, error) {
-	r := Replay{}
-
-	opt.Default()
-
-	u := *c.url
-	u.Path = replaysPath
-
+// Create a new library.
+// Errors if the library already exists.
+func (c *Client) CreateLibrary(opt CreateLibraryOptions) (Library, error) {
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	err := enc.Encode(opt)
 	if err != nil {
-		return r, err
+		return Library{}, err
 	}
 
+	u := *c.url
+	u.Path = librariesPath
+
 	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return r, err
+		return Library{}, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &r, http.StatusCreated)
-	if err != nil {
-		return r, err
-	}
-	return r, nil
+	l := Library{}
+	_, err = c.Do(req, &l, http.StatusOK)
+	return l, err
 }
 
-type ReplayBatchOptions struct {
-	ID            string    `json:"id,omitempty"`
-	Task          string    `json:"task"`
-	Start         time.Time `json:"start"`
-	Stop          time.Time `json:"stop"`
-	RecordingTime bool      `json:"recording-time"`
-	Clock         Clock     `json:"clock"`
+type UpdateLibraryOptions struct {
+	TICKscript string `json:"script,omitempty"`
 }
 
-// Replay a query against a task.
-func (c *Client) ReplayBatch(opt ReplayBatchOptions) (Replay, error) {
-	r := Replay{}
+// Update an existing library.
+// Only fields that are not their default value will be updated.
+func (c *Client) UpdateLibrary(link Link, opt UpdateLibraryOptions) (Library, error) {
+	l := Library{}
+	if link.Href == "" {
+		return l, fmt.Errorf("invalid link %v", link)
+	}
 
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	err := enc.Encode(opt)
 	if err != nil {
-		return r, err
+		return l, err
 	}
 
 	u := *c.url
-	u.Path = replayBatchPath
+	u.Path = link.Href
 
-	req, err := http.NewRequest("POST", u.String(), &buf)
+	req, err := http.NewRequest("PATCH", u.String(), &buf)
 	if err != nil {
-		return r, err
+		return l, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &r, http.StatusCreated)
+	_, err = c.Do(req, &l, http.StatusOK)
 	if err != nil {
-		return r, err
+		return l, err
 	}
-	return r, nil
+	return l, nil
 }
 
-type ReplayQueryOptions struct {
-	ID            string `json:"id,omitempty"`
-	Task          string `json:"task"`
-	Query         string `json:"query"`
-	Cluster       string `json:"cluster,omitempty"`
-	RecordingTime bool   `json:"recording-time"`
-	Clock         Clock  `json:"clock"`
+type LibraryOptions struct {
+	ScriptFormat string
 }
 
-// Replay a query against a task.
-func (c *Client) ReplayQuery(opt ReplayQueryOptions) (Replay, error) {
-	r := Replay{}
-
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(opt)
-	if err != nil {
-		return r, err
-	}
-
-	u := *c.url
-	u.Path = replayQueryPath
-
-	req, err := http.NewRequest("POST", u.String(), &buf)
-	if err != nil {
-		return r, err
+func (o *LibraryOptions) Default() {
+	if o.ScriptFormat == "" {
+		o.ScriptFormat = "formatted"
 	}
-	req.Header.Set("Content-Type", "application/json")
+}
 
-	_, err = c.Do(req, &r, http.StatusCreated)
-	if err != nil {
-		return r, err
-	}
-	return r, nil
+func (o *LibraryOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("script-format", o.ScriptFormat)
+	return v
 }
 
-// Return the replay information
-func (c *Client) Replay(link Link) (Replay, error) {
-	r := Replay{}
+// Get information about a library.
+// Options can be nil and the default options will be used.
+// By default the TICKscript contents are formatted, use ScriptFormat="raw" to return the TICKscript unmodified.
+func (c *Client) Library(link Link, opt *LibraryOptions) (Library, error) {
+	library := Library{}
 	if link.Href == "" {
-		return r, fmt.Errorf("invalid link %v", link)
+		return library, fmt.Errorf("invalid link %v", link)
 	}
 
+	if opt == nil {
+		opt = new(LibraryOptions)
+	}
+	opt.Default()
+
 	u := *c.url
 	u.Path = link.Href
+	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return r, err
+		return library, err
 	}
 
-	_, err = c.Do(req, &r, http.StatusOK, http.StatusAccepted)
+	_, err = c.Do(req, &library, http.StatusOK)
 	if err != nil {
-		return r, err
+		return library, err
 	}
-	return r, nil
+	return library, nil
 }
 
-// Delete a replay. This will cancel a running replay.
-func (c *Client) DeleteReplay(link Link) error {
+// Delete a library.
+func (c *Client) DeleteLibrary(link Link) error {
 	if link.Href == "" {
 		return fmt.Errorf("invalid link %v", link)
 	}
+
 	u := *c.url
 	u.Path = link.Href
 
@@ -1839,54 +1957,50 @@ func (c *Client) DeleteReplay(link Link) error {
 	}
 
 	_, err = c.Do(req, nil, http.StatusNoContent)
-	if err != nil {
-		return err
-	}
-	return nil
+	return err
 }
 
-type ListReplaysOptions struct {
+type ListLibrariesOptions struct {
+	LibraryOptions
 	Pattern string
-	Fields  []string
 	Offset  int
 	Limit   int
 }
 
-func (o *ListReplaysOptions) Default() {
+func (o *ListLibrariesOptions) Default() {
+	o.LibraryOptions.Default()
 	if o.Limit == 0 {
 		o.Limit = 100
 	}
 }
 
-func (o *ListReplaysOptions) Values() *url.Values {
-	v := &url.Values{}
+func (o *ListLibrariesOptions) Values() *url.Values {
+	v := o.LibraryOptions.Values()
 	v.Set("pattern", o.Pattern)
-	for _, field := range o.Fields {
-		v.Add("fields", field)
-	}
 	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
 	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
 	return v
 }
 
-// Get information about replays.
-// If rids is empty than all replays are returned.
-func (c *Client) ListReplays(opt *ListReplaysOptions) ([]Replay, error) {
+// Get libraries.
+func (c *Client) ListLibraries(opt *ListLibrariesOptions) ([]Library, error) {
 	if opt == nil {
-		opt = new(ListReplaysOptions)
+		opt = new(ListLibrariesOptions)
 	}
 	opt.Default()
+
 	u := *c.url
-	u.Path = replaysPath
+	u.Path = librariesPath
 	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	// Decode valid response
+
+	// Response type
 	type response struct {
-		Replays []Replay `json:"replays"`
+		Libraries []Library `json:"libraries"`
 	}
 
 	r := &response{}
@@ -1895,122 +2009,662 @@ func (c *Client) ListReplays(opt *ListReplaysOptions) ([]Replay, error) {
 	if err != nil {
 		return nil, err
 	}
-	return r.Replays, nil
+	return r.Libraries, nil
 }
 
-type ConfigUpdateAction struct {
-	Set    map[string]interface{} `json:"set,omitempty"`
-	Delete []string               `json:"delete,omitempty"`
-	Add    map[string]interface{} `json:"add,omitempty"`
-	Remove []string               `json:"remove,omitempty"`
-}
-
-// ConfigUpdate performs a given ConfigUpdateAction against a given section or element.
-func (c *Client) ConfigUpdate(link Link, action ConfigUpdateAction) error {
+// Get information about a recording.
+func (c *Client) Recording(link Link) (Recording, error) {
+	r := Recording{}
 	if link.Href == "" {
-		return fmt.Errorf("invalid link %v", link)
-	}
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(action)
-	if err != nil {
-		return err
+		return r, fmt.Errorf("invalid link %v", link)
 	}
 
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("POST", u.String(), &buf)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return err
+		return r, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, nil, http.StatusNoContent)
-	return err
+	_, err = c.Do(req, &r, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
 }
 
-type ConfigSections struct {
-	Link     Link                     `json:"link"`
-	Sections map[string]ConfigSection `json:"sections"`
+func (c *Client) RecordingLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(recordingsPath, id)}
 }
 
-type ConfigSection struct {
-	Link     Link            `json:"link"`
-	Elements []ConfigElement `json:"elements"`
+type RecordStreamOptions struct {
+	ID   string    `json:"id,omitempty"`
+	Task string    `json:"task"`
+	Stop time.Time `json:"stop"`
 }
 
-type ConfigElement struct {
-	Link     Link                   `json:"link"`
-	Options  map[string]interface{} `json:"options"`
-	Redacted []string               `json:"redacted"`
-}
+// Record the stream for a task.
+// Returns once the recording is started.
+func (c *Client) RecordStream(opt RecordStreamOptions) (Recording, error) {
+	r := Recording{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
+	}
 
-// ConfigSections returns all the running configuration sections that can be modified.
-func (c *Client) ConfigSections() (ConfigSections, error) {
 	u := *c.url
-	u.Path = configPath
+	u.Path = recordStreamPath
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return ConfigSections{}, err
+		return r, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	sections := ConfigSections{}
-	_, err = c.Do(req, &sections, http.StatusOK)
+	_, err = c.Do(req, &r, http.StatusCreated)
 	if err != nil {
-		return ConfigSections{}, err
+		return r, err
 	}
-	return sections, nil
+	return r, nil
 }
 
-// ConfigSection returns the running configuration for a section.
-func (c *Client) ConfigSection(link Link) (ConfigSection, error) {
-	if link.Href == "" {
-		return ConfigSection{}, fmt.Errorf("invalid link %v", link)
+type RecordBatchOptions struct {
+	ID    string    `json:"id,omitempty"`
+	Task  string    `json:"task"`
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+}
+
+// Record the batch queries for a task.
+// Returns once the recording is started.
+func (c *Client) RecordBatch(opt RecordBatchOptions) (Recording, error) {
+	r := Recording{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
 	}
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = recordBatchPath
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return ConfigSection{}, err
+		return r, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	section := ConfigSection{}
-	_, err = c.Do(req, &section, http.StatusOK)
+	_, err = c.Do(req, &r, http.StatusCreated)
 	if err != nil {
-		return ConfigSection{}, err
+		return r, err
 	}
-	return section, nil
+	return r, nil
 }
 
-// ConfigElement returns the running configuration for a given section and element.
-func (c *Client) ConfigElement(link Link) (ConfigElement, error) {
-	if link.Href == "" {
-		return ConfigElement{}, fmt.Errorf("invalid link %v", link)
+type RecordQueryOptions struct {
+	ID      string   `json:"id,omitempty"`
+	Query   string   `json:"query"`
+	Type    TaskType `json:"type"`
+	Cluster string   `json:"cluster,omitempty"`
+}
+
+// Record the results of a query.
+// The recordingType must be one of "stream", or "batch".
+// Returns once the recording is started.
+func (c *Client) RecordQuery(opt RecordQueryOptions) (Recording, error) {
+	r := Recording{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
 	}
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = recordQueryPath
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return ConfigElement{}, err
+		return r, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	element := ConfigElement{}
-	_, err = c.Do(req, &element, http.StatusOK)
+	_, err = c.Do(req, &r, http.StatusCreated)
 	if err != nil {
-		return ConfigElement{}, err
+		return r, err
 	}
-	return element, nil
-}
-
-type ServiceTests struct {
-	Link     Link          `json:"link"`
-	Services []ServiceTest `json:"services"`
+	return r, nil
+}
+
+// Formats of uploaded recording data.
+const (
+	// Line protocol, the database and retention policy of the points must be set.
+	LineProtocolFormat = "line-protocol"
+	// Annotated CSV as returned by Flux queries.
+	// The database and retention policy of the points must be set for stream recordings.
+	CSVFormat = "csv"
+	// Newline delimited batches in the format of batch recordings.
+	BatchJSONFormat = "batch-json"
+)
+
+type UploadRecordingOptions struct {
+	ID     string
+	Format string
+	// Type of the recording, only needed for the CSV format.
+	// Line protocol is always recorded as stream and batch JSON as batch.
+	Type            TaskType
+	Database        string
+	RetentionPolicy string
+	// Precision of the line protocol timestamps, defaults to nanoseconds.
+	Precision string
+}
+
+func (o *UploadRecordingOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("id", o.ID)
+	v.Set("format", o.Format)
+	if o.Type != InvalidTask {
+		v.Set("type", o.Type.String())
+	}
+	v.Set("db", o.Database)
+	v.Set("rp", o.RetentionPolicy)
+	v.Set("precision", o.Precision)
+	return v
+}
+
+// Upload data from elsewhere as a recording.
+// The data is validated and converted, and the recording is finished once this returns.
+func (c *Client) UploadRecording(opt UploadRecordingOptions, data io.Reader) (Recording, error) {
+	r := Recording{}
+
+	u := *c.url
+	u.Path = recordUploadPath
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("POST", u.String(), data)
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	_, err = c.Do(req, &r, http.StatusCreated)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Delete a recording.
+func (c *Client) DeleteRecording(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type ListRecordingsOptions struct {
+	Pattern string
+	Fields  []string
+	Offset  int
+	Limit   int
+}
+
+func (o *ListRecordingsOptions) Default() {
+	if o.Limit == 0 {
+		o.Limit = 100
+	}
+}
+
+func (o *ListRecordingsOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("pattern", o.Pattern)
+	for _, field := range o.Fields {
+		v.Add("fields", field)
+	}
+	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
+	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
+	return v
+}
+
+// Get information about recordings.
+// If rids is empty than all recordings are returned.
+func (c *Client) ListRecordings(opt *ListRecordingsOptions) ([]Recording, error) {
+	if opt == nil {
+		opt = new(ListRecordingsOptions)
+	}
+	opt.Default()
+	u := *c.url
+	u.Path = recordingsPath
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Decode valid response
+	type response struct {
+		Recordings []Recording `json:"recordings"`
+	}
+
+	r := &response{}
+
+	_, err = c.Do(req, r, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return r.Recordings, nil
+}
+
+func (c *Client) ReplayLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(replaysPath, id)}
+}
+
+type CreateReplayOptions struct {
+	ID            string `json:"id"`
+	Recording     string `json:"recording"`
+	Task          string `json:"task"`
+	RecordingTime bool   `json:"recording-time"`
+	Clock         Clock  `json:"clock"`
+}
+
+func (o *CreateReplayOptions) Default() {
+}
+
+// Replay a recording for a task.
+func (c *Client) CreateReplay(opt CreateReplayOptions) (Replay, error) {
+	r := Replay{}
+
+	opt.Default()
+
+	u := *c.url
+	u.Path = replaysPath
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &r, http.StatusCreated)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+type ReplayBatchOptions struct {
+	ID            string    `json:"id,omitempty"`
+	Task          string    `json:"task"`
+	Start         time.Time `json:"start"`
+	Stop          time.Time `json:"stop"`
+	RecordingTime bool      `json:"recording-time"`
+	Clock         Clock     `json:"clock"`
+}
+
+// Replay a query against a task.
+func (c *Client) ReplayBatch(opt ReplayBatchOptions) (Replay, error) {
+	r := Replay{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
+	}
+
+	u := *c.url
+	u.Path = replayBatchPath
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &r, http.StatusCreated)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+type ReplayQueryOptions struct {
+	ID            string `json:"id,omitempty"`
+	Task          string `json:"task"`
+	Query         string `json:"query"`
+	Cluster       string `json:"cluster,omitempty"`
+	RecordingTime bool   `json:"recording-time"`
+	Clock         Clock  `json:"clock"`
+}
+
+// Replay a query against a task.
+func (c *Client) ReplayQuery(opt ReplayQueryOptions) (Replay, error) {
+	r := Replay{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
+	}
+
+	u := *c.url
+	u.Path = replayQueryPath
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &r, http.StatusCreated)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+type CompareReplayOptions struct {
+	Recording string `json:"recording"`
+	// Task is the ID of the current task.
+	Task string `json:"task"`
+	// TICKscript is the candidate version of the task.
+	// Both versions always use the times saved in the recording.
+	TICKscript string `json:"script"`
+}
+
+// ReplayComparison is the difference between the outputs of the current and the candidate version of a task
+// when replaying the same recording.
+type ReplayComparison struct {
+	Recording string `json:"recording"`
+	Task      string `json:"task"`
+	// Equal is true if both versions output the same alerts and writes.
+	Equal bool `json:"equal"`
+	// Alerts lists the differences of the alert events per alert ID.
+	Alerts []AlertDifference `json:"alerts"`
+	// WritesAdded are the writes only output by the candidate version.
+	WritesAdded []string `json:"writes-added"`
+	// WritesRemoved are the writes only output by the current version.
+	WritesRemoved []string `json:"writes-removed"`
+
+	CurrentStats   ExecutionStats `json:"current-stats"`
+	CandidateStats ExecutionStats `json:"candidate-stats"`
+}
+
+type AlertDifference struct {
+	ID string `json:"id"`
+	// Added are the events only output by the candidate version.
+	Added []TaskTestAlert `json:"added,omitempty"`
+	// Removed are the events only output by the current version.
+	Removed []TaskTestAlert `json:"removed,omitempty"`
+	// Changed are the events with a different level or time.
+	Changed []AlertChange `json:"changed,omitempty"`
+}
+
+type AlertChange struct {
+	Current   TaskTestAlert `json:"current"`
+	Candidate TaskTestAlert `json:"candidate"`
+}
+
+// CompareReplay replays a recording against the current and a candidate version of a task
+// and returns the difference of their outputs.
+func (c *Client) CompareReplay(opt CompareReplayOptions) (ReplayComparison, error) {
+	r := ReplayComparison{}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
+	}
+
+	u := *c.url
+	u.Path = replayComparePath
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &r, http.StatusOK)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Return the replay information
+func (c *Client) Replay(link Link) (Replay, error) {
+	r := Replay{}
+	if link.Href == "" {
+		return r, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return r, err
+	}
+
+	_, err = c.Do(req, &r, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Delete a replay. This will cancel a running replay.
+func (c *Client) DeleteReplay(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type ListReplaysOptions struct {
+	Pattern string
+	Fields  []string
+	Offset  int
+	Limit   int
+}
+
+func (o *ListReplaysOptions) Default() {
+	if o.Limit == 0 {
+		o.Limit = 100
+	}
+}
+
+func (o *ListReplaysOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("pattern", o.Pattern)
+	for _, field := range o.Fields {
+		v.Add("fields", field)
+	}
+	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
+	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
+	return v
+}
+
+// Get information about replays.
+// If rids is empty than all replays are returned.
+func (c *Client) ListReplays(opt *ListReplaysOptions) ([]Replay, error) {
+	if opt == nil {
+		opt = new(ListReplaysOptions)
+	}
+	opt.Default()
+	u := *c.url
+	u.Path = replaysPath
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Decode valid response
+	type response struct {
+		Replays []Replay `json:"replays"`
+	}
+
+	r := &response{}
+
+	_, err = c.Do(req, r, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return r.Replays, nil
+}
+
+type ConfigUpdateAction struct {
+	Set    map[string]interface{} `json:"set,omitempty"`
+	Delete []string               `json:"delete,omitempty"`
+	Add    map[string]interface{} `json:"add,omitempty"`
+	Remove []string               `json:"remove,omitempty"`
+}
+
+// ConfigUpdate performs a given ConfigUpdateAction against a given section or element.
+func (c *Client) ConfigUpdate(link Link, action ConfigUpdateAction) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(action)
+	if err != nil {
+		return err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type ConfigSections struct {
+	Link     Link                     `json:"link"`
+	Sections map[string]ConfigSection `json:"sections"`
+}
+
+type ConfigSection struct {
+	Link     Link            `json:"link"`
+	Elements []ConfigElement `json:"elements"`
+}
+
+type ConfigElement struct {
+	Link     Link                   `json:"link"`
+	Options  map[string]interface{} `json:"options"`
+	Redacted []string               `json:"redacted"`
+}
+
+// ConfigSections returns all the running configuration sections that can be modified.
+func (c *Client) ConfigSections() (ConfigSections, error) {
+	u := *c.url
+	u.Path = configPath
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return ConfigSections{}, err
+	}
+
+	sections := ConfigSections{}
+	_, err = c.Do(req, &sections, http.StatusOK)
+	if err != nil {
+		return ConfigSections{}, err
+	}
+	return sections, nil
+}
+
+// ConfigSection returns the running configuration for a section.
+func (c *Client) ConfigSection(link Link) (ConfigSection, error) {
+	if link.Href == "" {
+		return ConfigSection{}, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return ConfigSection{}, err
+	}
+
+	section := ConfigSection{}
+	_, err = c.Do(req, &section, http.StatusOK)
+	if err != nil {
+		return ConfigSection{}, err
+	}
+	return section, nil
+}
+
+// ConfigElement returns the running configuration for a given section and element.
+func (c *Client) ConfigElement(link Link) (ConfigElement, error) {
+	if link.Href == "" {
+		return ConfigElement{}, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return ConfigElement{}, err
+	}
+
+	element := ConfigElement{}
+	_, err = c.Do(req, &element, http.StatusOK)
+	if err != nil {
+		return ConfigElement{}, err
+	}
+	return element, nil
+}
+
+type ServiceTests struct {
+	Link     Link          `json:"link"`
+	Services []ServiceTest `json:"services"`
 }
 
 type ServiceTest struct {
@@ -2019,391 +2673,1355 @@ type ServiceTest struct {
 	Options ServiceTestOptions `json:"options"`
 }
 
-type ServiceTestOptions map[string]interface{}
+type ServiceTestOptions map[string]interface{}
+
+type ServiceTestResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type ListServiceTestsOptions struct {
+	Pattern string
+}
+
+func (o *ListServiceTestsOptions) Default() {
+	if o.Pattern == "" {
+		o.Pattern = "*"
+	}
+}
+
+func (o *ListServiceTestsOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("pattern", o.Pattern)
+	return v
+}
+
+// ServiceTests returns the list of services available for testing.
+func (c *Client) ListServiceTests(opt *ListServiceTestsOptions) (ServiceTests, error) {
+	if opt == nil {
+		opt = new(ListServiceTestsOptions)
+	}
+	opt.Default()
+
+	u := *c.url
+	u.Path = serviceTestsPath
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return ServiceTests{}, err
+	}
+
+	services := ServiceTests{}
+	_, err = c.Do(req, &services, http.StatusOK)
+	if err != nil {
+		return ServiceTests{}, err
+	}
+	return services, nil
+}
+
+// ServiceTest returns the options available for a service test.
+func (c *Client) ServiceTest(link Link) (ServiceTest, error) {
+	if link.Href == "" {
+		return ServiceTest{}, fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return ServiceTest{}, err
+	}
+
+	service := ServiceTest{}
+	_, err = c.Do(req, &service, http.StatusOK)
+	if err != nil {
+		return ServiceTest{}, err
+	}
+	return service, nil
+}
+
+// DoServiceTest performs a test for a service.
+func (c *Client) DoServiceTest(link Link, sto ServiceTestOptions) (ServiceTestResult, error) {
+	if link.Href == "" {
+		return ServiceTestResult{}, fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(sto)
+	if err != nil {
+		return ServiceTestResult{}, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return ServiceTestResult{}, err
+	}
+
+	r := ServiceTestResult{}
+	_, err = c.Do(req, &r, http.StatusOK)
+	if err != nil {
+		return ServiceTestResult{}, err
+	}
+	return r, nil
+}
+
+type ListTopicsOptions struct {
+	Pattern  string
+	MinLevel string
+}
+
+func (o *ListTopicsOptions) Default() {
+	if o.MinLevel == "" {
+		o.MinLevel = "OK"
+	}
+}
+
+func (o *ListTopicsOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("pattern", o.Pattern)
+	v.Set("min-level", o.MinLevel)
+	return v
+}
+
+type Topics struct {
+	Link   Link    `json:"link"`
+	Topics []Topic `json:"topics"`
+}
+
+type Topic struct {
+	Link         Link   `json:"link"`
+	ID           string `json:"id"`
+	Level        string `json:"level"`
+	Collected    int64  `json:"collected"`
+	EventsLink   Link   `json:"events-link"`
+	HandlersLink Link   `json:"handlers-link"`
+	SilencesLink Link   `json:"silences-link"`
+}
+
+func (c *Client) ListTopics(opt *ListTopicsOptions) (Topics, error) {
+	topics := Topics{}
+	if opt == nil {
+		opt = new(ListTopicsOptions)
+	}
+	opt.Default()
+
+	u := *c.url
+	u.Path = topicsPath
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return topics, err
+	}
+
+	_, err = c.Do(req, &topics, http.StatusOK)
+	if err != nil {
+		return topics, err
+	}
+	return topics, nil
+}
+
+func (c *Client) Topic(link Link) (Topic, error) {
+	var t Topic
+	if link.Href == "" {
+		return t, fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return t, err
+	}
+
+	_, err = c.Do(req, &t, http.StatusOK)
+	return t, err
+}
+
+func (c *Client) DeleteTopic(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type TopicEvents struct {
+	Link   Link         `json:"link"`
+	Topic  string       `json:"topic"`
+	Events []TopicEvent `json:"events"`
+}
+
+type TopicEvent struct {
+	Link  Link       `json:"link"`
+	ID    string     `json:"id"`
+	State EventState `json:"state"`
+	// Acknowledgement is set if the event has been acknowledged.
+	Acknowledgement *EventAcknowledgement `json:"acknowledgement,omitempty"`
+}
+
+type EventState struct {
+	Message  string    `json:"message"`
+	Details  string    `json:"details"`
+	Time     time.Time `json:"time"`
+	Duration Duration  `json:"duration"`
+	Level    string    `json:"level"`
+}
+
+// TopicEvent retrieves details for a single event of a topic
+// Errors if no event exists.
+func (c *Client) TopicEvent(link Link) (TopicEvent, error) {
+	e := TopicEvent{}
+	if link.Href == "" {
+		return e, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return e, err
+	}
+
+	_, err = c.Do(req, &e, http.StatusOK)
+	return e, err
+}
+
+type ListTopicEventsOptions struct {
+	MinLevel string
+}
+
+func (o *ListTopicEventsOptions) Default() {
+	if o.MinLevel == "" {
+		o.MinLevel = "OK"
+	}
+}
+
+func (o *ListTopicEventsOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("min-level", o.MinLevel)
+	return v
+}
+
+// ListTopicEvents returns the current state for events within a topic.
+func (c *Client) ListTopicEvents(link Link, opt *ListTopicEventsOptions) (TopicEvents, error) {
+	t := TopicEvents{}
+	if link.Href == "" {
+		return t, fmt.Errorf("invalid link %v", link)
+	}
+
+	if opt == nil {
+		opt = new(ListTopicEventsOptions)
+	}
+	opt.Default()
+
+	u := *c.url
+	u.Path = link.Href
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return t, err
+	}
+
+	_, err = c.Do(req, &t, http.StatusOK)
+	return t, err
+}
+
+type TopicEventHistory struct {
+	Link    Link                `json:"link"`
+	Topic   string              `json:"topic"`
+	ID      string              `json:"id"`
+	Entries []EventHistoryEntry `json:"entries"`
+}
+
+// EventHistoryEntry is a single state transition of an event.
+type EventHistoryEntry struct {
+	Time          time.Time `json:"time"`
+	Level         string    `json:"level"`
+	PreviousLevel string    `json:"previous-level"`
+	Message       string    `json:"message"`
+	Duration      Duration  `json:"duration"`
+}
+
+type TopicEventHistoryOptions struct {
+	// Start and Stop bound the time range of the history, zero values leave the range unbounded.
+	Start    time.Time
+	Stop     time.Time
+	MinLevel string
+}
+
+func (o *TopicEventHistoryOptions) Default() {
+	if o.MinLevel == "" {
+		o.MinLevel = "OK"
+	}
+}
+
+func (o *TopicEventHistoryOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("min-level", o.MinLevel)
+	if !o.Start.IsZero() {
+		v.Set("start", o.Start.Format(time.RFC3339Nano))
+	}
+	if !o.Stop.IsZero() {
+		v.Set("stop", o.Stop.Format(time.RFC3339Nano))
+	}
+	return v
+}
+
+// TopicEventHistory returns the recorded state transitions of an event, ordered by time.
+func (c *Client) TopicEventHistory(link Link, opt *TopicEventHistoryOptions) (TopicEventHistory, error) {
+	h := TopicEventHistory{}
+	if link.Href == "" {
+		return h, fmt.Errorf("invalid link %v", link)
+	}
+
+	if opt == nil {
+		opt = new(TopicEventHistoryOptions)
+	}
+	opt.Default()
+
+	u := *c.url
+	u.Path = link.Href
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return h, err
+	}
+
+	_, err = c.Do(req, &h, http.StatusOK)
+	return h, err
+}
+
+type EventAcknowledgement struct {
+	Link  Link   `json:"link"`
+	Topic string `json:"topic"`
+	Event string `json:"event"`
+	// Level is the level of the event that was acknowledged.
+	Level string `json:"level"`
+	// Expires is zero if the acknowledgement lasts until the level of the event changes.
+	Expires time.Time `json:"expires"`
+	Author  string    `json:"author"`
+	Comment string    `json:"comment"`
+	Created time.Time `json:"created"`
+}
+
+type AcknowledgeEventOptions struct {
+	// Expires is optional, if zero the acknowledgement lasts until the level of the event changes.
+	Expires time.Time `json:"expires"`
+	Author  string    `json:"author"`
+	Comment string    `json:"comment"`
+}
+
+// TopicEventAcknowledgement retrieves the acknowledgement of an event.
+// Errors if the event is not acknowledged.
+func (c *Client) TopicEventAcknowledgement(link Link) (EventAcknowledgement, error) {
+	a := EventAcknowledgement{}
+	if link.Href == "" {
+		return a, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return a, err
+	}
+
+	_, err = c.Do(req, &a, http.StatusOK)
+	return a, err
+}
+
+// AcknowledgeTopicEvent acknowledges an event at its current level.
+// Events are not sent to handlers while they remain at the acknowledged level.
+func (c *Client) AcknowledgeTopicEvent(link Link, opt AcknowledgeEventOptions) (EventAcknowledgement, error) {
+	a := EventAcknowledgement{}
+	if link.Href == "" {
+		return a, fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return a, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return a, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &a, http.StatusOK)
+	return a, err
+}
+
+// UnacknowledgeTopicEvent removes the acknowledgement of an event.
+func (c *Client) UnacknowledgeTopicEvent(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type TopicSilences struct {
+	Link     Link           `json:"link"`
+	Topic    string         `json:"topic"`
+	Silences []TopicSilence `json:"silences"`
+}
+
+type TopicSilence struct {
+	Link     Link             `json:"link"`
+	ID       string           `json:"id"`
+	Topic    string           `json:"topic"`
+	Matchers []SilenceMatcher `json:"matchers"`
+	Expires  time.Time        `json:"expires"`
+	Author   string           `json:"author"`
+	Comment  string           `json:"comment"`
+	Created  time.Time        `json:"created"`
+}
+
+// SilenceMatcher matches events by a tag, their ID or their level.
+type SilenceMatcher struct {
+	// Type is one of "tag", "id" or "level".
+	Type string `json:"type" yaml:"type"`
+	// Tag is the name of the tag to match, only used by tag matchers.
+	Tag   string `json:"tag,omitempty" yaml:"tag"`
+	Value string `json:"value" yaml:"value"`
+	// Regex indicates that Value is an anchored regular expression.
+	Regex bool `json:"regex,omitempty" yaml:"regex"`
+}
+
+type TopicSilenceOptions struct {
+	// ID is optional, if empty a random ID is chosen.
+	ID       string           `json:"id" yaml:"id"`
+	Matchers []SilenceMatcher `json:"matchers" yaml:"matchers"`
+	Expires  time.Time        `json:"expires" yaml:"expires"`
+	Author   string           `json:"author" yaml:"author"`
+	Comment  string           `json:"comment" yaml:"comment"`
+}
+
+// ListTopicSilences returns the unexpired silences of a topic.
+func (c *Client) ListTopicSilences(link Link) (TopicSilences, error) {
+	s := TopicSilences{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return s, err
+	}
+
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
+
+// TopicSilence retrieves a silence.
+// Errors if no silence exists.
+func (c *Client) TopicSilence(link Link) (TopicSilence, error) {
+	s := TopicSilence{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return s, err
+	}
+
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
+
+// CreateTopicSilence creates a new silence.
+// Matching events are recorded but not sent to the handlers of the topic until the silence expires.
+func (c *Client) CreateTopicSilence(link Link, opt TopicSilenceOptions) (TopicSilence, error) {
+	s := TopicSilence{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return s, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return s, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
+
+// ReplaceTopicSilence replaces an existing silence, with the new definition.
+func (c *Client) ReplaceTopicSilence(link Link, opt TopicSilenceOptions) (TopicSilence, error) {
+	s := TopicSilence{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return s, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("PUT", u.String(), &buf)
+	if err != nil {
+		return s, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
+
+// DeleteTopicSilence deletes a silence.
+func (c *Client) DeleteTopicSilence(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type TopicHandlers struct {
+	Link     Link           `json:"link"`
+	Topic    string         `json:"topic"`
+	Handlers []TopicHandler `json:"handlers"`
+}
+
+type TopicHandler struct {
+	Link    Link                   `json:"link"`
+	ID      string                 `json:"id"`
+	Kind    string                 `json:"kind"`
+	Options map[string]interface{} `json:"options"`
+	Match   string                 `json:"match"`
+}
+
+// TopicHandler retrieves an alert handler.
+// Errors if no handler exists.
+func (c *Client) TopicHandler(link Link) (TopicHandler, error) {
+	h := TopicHandler{}
+	if link.Href == "" {
+		return h, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return h, err
+	}
+
+	_, err = c.Do(req, &h, http.StatusOK)
+	return h, err
+}
+
+type TopicHandlerOptions struct {
+	Topic   string                 `json:"topic" yaml:"topic"`
+	ID      string                 `json:"id" yaml:"id"`
+	Kind    string                 `json:"kind" yaml:"kind"`
+	Options map[string]interface{} `json:"options" yaml:"options"`
+	Match   string                 `json:"match" yaml:"match"`
+}
+
+// CreateTopicHandler creates a new alert handler.
+// Errors if the handler already exists.
+func (c *Client) CreateTopicHandler(link Link, opt TopicHandlerOptions) (TopicHandler, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return TopicHandler{}, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return TopicHandler{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	h := TopicHandler{}
+	_, err = c.Do(req, &h, http.StatusOK)
+	return h, err
+}
+
+// PatchTopicHandler applies a patch operation to an existing handler.
+func (c *Client) PatchTopicHandler(link Link, patch JSONPatch) (TopicHandler, error) {
+	h := TopicHandler{}
+	if link.Href == "" {
+		return h, fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(patch)
+	if err != nil {
+		return h, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("PATCH", u.String(), &buf)
+	if err != nil {
+		return h, err
+	}
+	req.Header.Set("Content-Type", "application/json+patch")
+
+	_, err = c.Do(req, &h, http.StatusOK)
+	return h, err
+}
+
+// ReplaceTopicHandler replaces an existing handler, with the new definition.
+func (c *Client) ReplaceTopicHandler(link Link, opt TopicHandlerOptions) (TopicHandler, error) {
+	h := TopicHandler{}
+	if link.Href == "" {
+		return h, fmt.Errorf("invalid link %v", link)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return h, err
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("PUT", u.String(), &buf)
+	if err != nil {
+		return h, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &h, http.StatusOK)
+	return h, err
+}
+
+// DeleteTopicHandler deletes a handler.
+func (c *Client) DeleteTopicHandler(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type ListTopicHandlersOptions struct {
+	Pattern string
+}
+
+func (o *ListTopicHandlersOptions) Default() {}
+
+func (o *ListTopicHandlersOptions) Values() *url.Values {
+	v := &url.Values{}
+	v.Set("pattern", o.Pattern)
+	return v
+}
+
+func (c *Client) ListTopicHandlers(link Link, opt *ListTopicHandlersOptions) (TopicHandlers, error) {
+	handlers := TopicHandlers{}
+	if opt == nil {
+		opt = new(ListTopicHandlersOptions)
+	}
+	opt.Default()
+
+	u := *c.url
+	u.Path = link.Href
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return handlers, err
+	}
+
+	_, err = c.Do(req, &handlers, http.StatusOK)
+	if err != nil {
+		return handlers, err
+	}
+	return handlers, nil
+}
+
+type TopicHandlerDeadLetters struct {
+	Link        Link         `json:"link"`
+	Topic       string       `json:"topic"`
+	Handler     string       `json:"handler"`
+	DeadLetters []DeadLetter `json:"dead-letters"`
+}
+
+// DeadLetter is an event that a handler failed to handle after all of its attempts.
+type DeadLetter struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Level    string    `json:"level"`
+	Message  string    `json:"message"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+}
+
+type DeadLettersReplay struct {
+	Replayed int `json:"replayed"`
+}
+
+// TopicHandlerDeadLetters returns the events that a handler failed to handle, ordered by time.
+func (c *Client) TopicHandlerDeadLetters(link Link) (TopicHandlerDeadLetters, error) {
+	d := TopicHandlerDeadLetters{}
+	if link.Href == "" {
+		return d, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return d, err
+	}
+
+	_, err = c.Do(req, &d, http.StatusOK)
+	return d, err
+}
+
+// ReplayTopicHandlerDeadLetters passes the dead-lettered events to the handler again
+// and returns the number of events that were replayed.
+func (c *Client) ReplayTopicHandlerDeadLetters(link Link) (int, error) {
+	if link.Href == "" {
+		return 0, fmt.Errorf("invalid link %v", link)
+	}
+
+	u := *c.url
+	u.Path = path.Join(link.Href, deadLettersReplayPath)
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	r := DeadLettersReplay{}
+	_, err = c.Do(req, &r, http.StatusOK)
+	return r.Replayed, err
+}
+
+// PurgeTopicHandlerDeadLetters deletes the events that a handler failed to handle.
+func (c *Client) PurgeTopicHandlerDeadLetters(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
+}
+
+type StorageList struct {
+	Link    Link      `json:"link"`
+	Storage []Storage `json:"storage"`
+}
+
+type Storage struct {
+	Link Link   `json:"link"`
+	Name string `json:"name"`
+}
+
+type StorageAction int
+
+const (
+	_ StorageAction = iota
+	StorageRebuild
+)
+
+func (sa StorageAction) MarshalText() ([]byte, error) {
+	switch sa {
+	case StorageRebuild:
+		return []byte("rebuild"), nil
+	default:
+		return nil, fmt.Errorf("unknown StorageAction %d", sa)
+	}
+}
+
+func (sa *StorageAction) UnmarshalText(text []byte) error {
+	switch s := string(text); s {
+	case "rebuild":
+		*sa = StorageRebuild
+	default:
+		return fmt.Errorf("unknown StorageAction %s", s)
+	}
+	return nil
+}
+
+func (sa StorageAction) String() string {
+	s, err := sa.MarshalText()
+	if err != nil {
+		return err.Error()
+	}
+	return string(s)
+}
 
-type ServiceTestResult struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+type StorageActionOptions struct {
+	Action StorageAction `json:"action"`
 }
 
-type ListServiceTestsOptions struct {
-	Pattern string
+func (c *Client) ListStorage() (StorageList, error) {
+	list := StorageList{}
+	u := *c.url
+	u.Path = storesPath
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return list, err
+	}
+
+	_, err = c.Do(req, &list, http.StatusOK)
+	if err != nil {
+		return list, err
+	}
+	return list, nil
 }
 
-func (o *ListServiceTestsOptions) Default() {
-	if o.Pattern == "" {
-		o.Pattern = "*"
+func (c *Client) DoStorageAction(l Link, opt StorageActionOptions) error {
+	u := *c.url
+	u.Path = l.Href
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", u.String(), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	if err != nil {
+		return err
 	}
+	return nil
 }
 
-func (o *ListServiceTestsOptions) Values() *url.Values {
+// Backup requests a backup of all storage from Kapacitor.
+// A short read is possible, to verify that the backup was successful
+// check that the number of bytes read matches the returned size.
+func (c *Client) Backup() (int64, io.ReadCloser, error) {
+	u := *c.url
+	u.Path = backupPath
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	err = c.prepRequest(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.ContentLength, resp.Body, nil
+}
+
+type Blobs struct {
+	Link  Link   `json:"link"`
+	Blobs []Blob `json:"blobs"`
+}
+
+type Blob struct {
+	Link     Link      `json:"link"`
+	DataLink Link      `json:"data-link"`
+	ID       string    `json:"id"`
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+}
+
+type BlobTags struct {
+	Link Link      `json:"link"`
+	Tags []BlobTag `json:"tags"`
+}
+
+type BlobTag struct {
+	Link     Link           `json:"link"`
+	DataLink Link           `json:"data-link"`
+	BlobLink Link           `json:"blob-link"`
+	Name     string         `json:"name"`
+	Blob     string         `json:"blob"`
+	Modified time.Time      `json:"modified"`
+	History  []BlobTagEntry `json:"history"`
+}
+
+type BlobTagEntry struct {
+	Blob string    `json:"blob"`
+	Date time.Time `json:"date"`
+}
+
+func (c *Client) BlobLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(blobsPath, id)}
+}
+
+func (c *Client) BlobTagLink(name string) Link {
+	return Link{Relation: Self, Href: path.Join(blobTagsPath, name)}
+}
+
+type CreateBlobOptions struct {
+	// Tag is an optional tag name to associate with the new blob.
+	Tag string
+}
+
+func (o *CreateBlobOptions) Values() *url.Values {
 	v := &url.Values{}
-	v.Set("pattern", o.Pattern)
+	if o.Tag != "" {
+		v.Set("tag", o.Tag)
+	}
 	return v
 }
 
-// ServiceTests returns the list of services available for testing.
-func (c *Client) ListServiceTests(opt *ListServiceTestsOptions) (ServiceTests, error) {
+// Create a new blob from the content read from r.
+// Blobs are content addressable, creating a blob with existing content returns the existing blob.
+func (c *Client) CreateBlob(r io.Reader, opt *CreateBlobOptions) (Blob, error) {
+	b := Blob{}
 	if opt == nil {
-		opt = new(ListServiceTestsOptions)
+		opt = new(CreateBlobOptions)
 	}
-	opt.Default()
 
 	u := *c.url
-	u.Path = serviceTestsPath
+	u.Path = blobsPath
 	u.RawQuery = opt.Values().Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("POST", u.String(), r)
 	if err != nil {
-		return ServiceTests{}, err
+		return b, err
 	}
+	req.Header.Set("Content-Type", "application/octet-stream")
 
-	services := ServiceTests{}
-	_, err = c.Do(req, &services, http.StatusOK)
+	_, err = c.Do(req, &b, http.StatusOK, http.StatusCreated)
 	if err != nil {
-		return ServiceTests{}, err
+		return b, err
 	}
-	return services, nil
+	return b, nil
 }
 
-// ServiceTest returns the options available for a service test.
-func (c *Client) ServiceTest(link Link) (ServiceTest, error) {
+// Get information about a blob.
+func (c *Client) Blob(link Link) (Blob, error) {
+	b := Blob{}
 	if link.Href == "" {
-		return ServiceTest{}, fmt.Errorf("invalid link %v", link)
+		return b, fmt.Errorf("invalid link %v", link)
 	}
+
 	u := *c.url
 	u.Path = link.Href
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return ServiceTest{}, err
+		return b, err
 	}
 
-	service := ServiceTest{}
-	_, err = c.Do(req, &service, http.StatusOK)
+	_, err = c.Do(req, &b, http.StatusOK)
 	if err != nil {
-		return ServiceTest{}, err
+		return b, err
 	}
-	return service, nil
+	return b, nil
 }
 
-// DoServiceTest performs a test for a service.
-func (c *Client) DoServiceTest(link Link, sto ServiceTestOptions) (ServiceTestResult, error) {
+// BlobData returns the content of a blob.
+// The link must be the data link of either a blob or a blob tag.
+// The returned reader must be closed by the caller.
+func (c *Client) BlobData(link Link) (io.ReadCloser, error) {
 	if link.Href == "" {
-		return ServiceTestResult{}, fmt.Errorf("invalid link %v", link)
+		return nil, fmt.Errorf("invalid link %v", link)
 	}
+
 	u := *c.url
 	u.Path = link.Href
 
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(sto)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return ServiceTestResult{}, err
+		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", u.String(), &buf)
+	err = c.prepRequest(req)
 	if err != nil {
-		return ServiceTestResult{}, err
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.decodeError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete a blob.
+func (c *Client) DeleteBlob(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
 	}
+	u := *c.url
+	u.Path = link.Href
 
-	r := ServiceTestResult{}
-	_, err = c.Do(req, &r, http.StatusOK)
+	req, err := http.NewRequest("DELETE", u.String(), nil)
 	if err != nil {
-		return ServiceTestResult{}, err
+		return err
 	}
-	return r, nil
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
 }
 
-type ListTopicsOptions struct {
-	Pattern  string
-	MinLevel string
+type ListBlobsOptions struct {
+	Pattern string
+	Offset  int
+	Limit   int
 }
 
-func (o *ListTopicsOptions) Default() {
-	if o.MinLevel == "" {
-		o.MinLevel = "OK"
+func (o *ListBlobsOptions) Default() {
+	if o.Limit == 0 {
+		o.Limit = 100
 	}
 }
 
-func (o *ListTopicsOptions) Values() *url.Values {
+func (o *ListBlobsOptions) Values() *url.Values {
 	v := &url.Values{}
 	v.Set("pattern", o.Pattern)
-	v.Set("min-level", o.MinLevel)
+	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
+	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
 	return v
 }
 
-type Topics struct {
-	Link   Link    `json:"link"`
-	Topics []Topic `json:"topics"`
-}
-
-type Topic struct {
-	Link         Link   `json:"link"`
-	ID           string `json:"id"`
-	Level        string `json:"level"`
-	Collected    int64  `json:"collected"`
-	EventsLink   Link   `json:"events-link"`
-	HandlersLink Link   `json:"handlers-link"`
-}
-
-func (c *Client) ListTopics(opt *ListTopicsOptions) (Topics, error) {
-	topics := Topics{}
+// Get information about blobs, most recently created first.
+func (c *Client) ListBlobs(opt *ListBlobsOptions) (Blobs, error) {
+	blobs := Blobs{}
 	if opt == nil {
-		opt = new(ListTopicsOptions)
+		opt = new(ListBlobsOptions)
 	}
 	opt.Default()
 
 	u := *c.url
-	u.Path = topicsPath
+	u.Path = blobsPath
 	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return topics, err
+		return blobs, err
 	}
 
-	_, err = c.Do(req, &topics, http.StatusOK)
+	_, err = c.Do(req, &blobs, http.StatusOK)
 	if err != nil {
-		return topics, err
+		return blobs, err
 	}
-	return topics, nil
+	return blobs, nil
 }
 
-func (c *Client) Topic(link Link) (Topic, error) {
-	var t Topic
+type BlobTagOptions struct {
+	Blob string `json:"blob"`
+}
+
+// Tag a blob.
+// If the tag already exists it is updated to refer to the new blob and its history is preserved.
+func (c *Client) TagBlob(link Link, opt BlobTagOptions) (BlobTag, error) {
+	t := BlobTag{}
 	if link.Href == "" {
 		return t, fmt.Errorf("invalid link %v", link)
 	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return t, err
+	}
+
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("PUT", u.String(), &buf)
 	if err != nil {
 		return t, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	_, err = c.Do(req, &t, http.StatusOK)
-	return t, err
+	if err != nil {
+		return t, err
+	}
+	return t, nil
 }
 
-func (c *Client) DeleteTopic(link Link) error {
+// Get information about a blob tag, including its history.
+func (c *Client) BlobTag(link Link) (BlobTag, error) {
+	t := BlobTag{}
 	if link.Href == "" {
-		return fmt.Errorf("invalid link %v", link)
+		return t, fmt.Errorf("invalid link %v", link)
 	}
+
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("DELETE", u.String(), nil)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return err
+		return t, err
 	}
 
-	_, err = c.Do(req, nil, http.StatusNoContent)
-	return err
-}
-
-type TopicEvents struct {
-	Link   Link         `json:"link"`
-	Topic  string       `json:"topic"`
-	Events []TopicEvent `json:"events"`
-}
-
-type TopicEvent struct {
-	Link  Link       `json:"link"`
-	ID    string     `json:"id"`
-	State EventState `json:"state"`
-}
-
-type EventState struct {
-	Message  string    `json:"message"`
-	Details  string    `json:"details"`
-	Time     time.Time `json:"time"`
-	Duration Duration  `json:"duration"`
-	Level    string    `json:"level"`
+	_, err = c.Do(req, &t, http.StatusOK)
+	if err != nil {
+		return t, err
+	}
+	return t, nil
 }
-
-// TopicEvent retrieves details for a single event of a topic
-// Errors if no event exists.
-func (c *Client) TopicEvent(link Link) (TopicEvent, error) {
-	e := TopicEvent{}
+
+// Delete a blob tag.
+// The blobs the tag referred to are not deleted.
+func (c *Client) DeleteBlobTag(link Link) error {
 	if link.Href == "" {
-		return e, fmt.Errorf("invalid link %v", link)
+		return fmt.Errorf("invalid link %v", link)
 	}
-
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("DELETE", u.String(), nil)
 	if err != nil {
-		return e, err
+		return err
 	}
 
-	_, err = c.Do(req, &e, http.StatusOK)
-	return e, err
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
 }
 
-type ListTopicEventsOptions struct {
-	MinLevel string
+type ListBlobTagsOptions struct {
+	Pattern string
+	Offset  int
+	Limit   int
 }
 
-func (o *ListTopicEventsOptions) Default() {
-	if o.MinLevel == "" {
-		o.MinLevel = "OK"
+func (o *ListBlobTagsOptions) Default() {
+	if o.Limit == 0 {
+		o.Limit = 100
 	}
 }
 
-func (o *ListTopicEventsOptions) Values() *url.Values {
+func (o *ListBlobTagsOptions) Values() *url.Values {
 	v := &url.Values{}
-	v.Set("min-level", o.MinLevel)
+	v.Set("pattern", o.Pattern)
+	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
+	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
 	return v
 }
 
-// ListTopicEvents returns the current state for events within a topic.
-func (c *Client) ListTopicEvents(link Link, opt *ListTopicEventsOptions) (TopicEvents, error) {
-	t := TopicEvents{}
-	if link.Href == "" {
-		return t, fmt.Errorf("invalid link %v", link)
-	}
-
+// Get information about blob tags, sorted by name.
+func (c *Client) ListBlobTags(opt *ListBlobTagsOptions) (BlobTags, error) {
+	tags := BlobTags{}
 	if opt == nil {
-		opt = new(ListTopicEventsOptions)
+		opt = new(ListBlobTagsOptions)
 	}
 	opt.Default()
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = blobTagsPath
 	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return t, err
+		return tags, err
 	}
 
-	_, err = c.Do(req, &t, http.StatusOK)
-	return t, err
+	_, err = c.Do(req, &tags, http.StatusOK)
+	if err != nil {
+		return tags, err
+	}
+	return tags, nil
 }
 
-type TopicHandlers struct {
-	Link     Link           `json:"link"`
-	Topic    string         `json:"topic"`
-	Handlers []TopicHandler `json:"handlers"`
+type Schedules struct {
+	Link      Link       `json:"link"`
+	Schedules []Schedule `json:"schedules"`
 }
 
-type TopicHandler struct {
-	Link    Link                   `json:"link"`
-	ID      string                 `json:"id"`
-	Kind    string                 `json:"kind"`
-	Options map[string]interface{} `json:"options"`
-	Match   string                 `json:"match"`
+// A Schedule is a named calendar of weekly time ranges and excluded dates.
+type Schedule struct {
+	Link       Link                  `json:"link"`
+	ID         string                `json:"id"`
+	Timezone   string                `json:"timezone"`
+	Weekly     []ScheduleWeeklyRange `json:"weekly"`
+	Exclusions []ScheduleDateRange   `json:"exclusions"`
+	// ReadOnly is true for schedules defined in the configuration file.
+	ReadOnly bool      `json:"read-only"`
+	Created  time.Time `json:"created"`
+	Modified time.Time `json:"modified"`
 }
 
-// TopicHandler retrieves an alert handler.
-// Errors if no handler exists.
-func (c *Client) TopicHandler(link Link) (TopicHandler, error) {
-	h := TopicHandler{}
-	if link.Href == "" {
-		return h, fmt.Errorf("invalid link %v", link)
-	}
-
-	u := *c.url
-	u.Path = link.Href
+// ScheduleWeeklyRange is a time of day range, in the format HH:MM, on the given days of the week.
+// The start is inclusive and the end is exclusive, an end of 24:00 means the end of the day.
+type ScheduleWeeklyRange struct {
+	Days  []string `json:"days" yaml:"days"`
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+}
 
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return h, err
-	}
+// ScheduleDateRange is an inclusive range of dates, in the format YYYY-MM-DD.
+// An empty end means the range is the single start date.
+type ScheduleDateRange struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end,omitempty" yaml:"end"`
+}
 
-	_, err = c.Do(req, &h, http.StatusOK)
-	return h, err
+type ScheduleOptions struct {
+	ID         string                `json:"id" yaml:"id"`
+	Timezone   string                `json:"timezone,omitempty" yaml:"timezone"`
+	Weekly     []ScheduleWeeklyRange `json:"weekly" yaml:"weekly"`
+	Exclusions []ScheduleDateRange   `json:"exclusions" yaml:"exclusions"`
 }
 
-type TopicHandlerOptions struct {
-	Topic   string                 `json:"topic" yaml:"topic"`
-	ID      string                 `json:"id" yaml:"id"`
-	Kind    string                 `json:"kind" yaml:"kind"`
-	Options map[string]interface{} `json:"options" yaml:"options"`
-	Match   string                 `json:"match" yaml:"match"`
+func (c *Client) ScheduleLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(schedulesPath, id)}
 }
 
-// CreateTopicHandler creates a new alert handler.
-// Errors if the handler already exists.
-func (c *Client) CreateTopicHandler(link Link, opt TopicHandlerOptions) (TopicHandler, error) {
+// Create a new schedule.
+// Errors if the schedule already exists.
+func (c *Client) CreateSchedule(opt ScheduleOptions) (Schedule, error) {
+	s := Schedule{}
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	err := enc.Encode(opt)
 	if err != nil {
-		return TopicHandler{}, err
+		return s, err
 	}
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = schedulesPath
 
 	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return TopicHandler{}, err
+		return s, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	h := TopicHandler{}
-	_, err = c.Do(req, &h, http.StatusOK)
-	return h, err
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
 }
 
-// PatchTopicHandler applies a patch operation to an existing handler.
-func (c *Client) PatchTopicHandler(link Link, patch JSONPatch) (TopicHandler, error) {
-	h := TopicHandler{}
+// ReplaceSchedule replaces an existing schedule with the new definition.
+func (c *Client) ReplaceSchedule(link Link, opt ScheduleOptions) (Schedule, error) {
+	s := Schedule{}
 	if link.Href == "" {
-		return h, fmt.Errorf("invalid link %v", link)
+		return s, fmt.Errorf("invalid link %v", link)
 	}
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
-	err := enc.Encode(patch)
+	err := enc.Encode(opt)
 	if err != nil {
-		return h, err
+		return s, err
 	}
 
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("PATCH", u.String(), &buf)
+	req, err := http.NewRequest("PUT", u.String(), &buf)
 	if err != nil {
-		return h, err
+		return s, err
 	}
-	req.Header.Set("Content-Type", "application/json+patch")
+	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &h, http.StatusOK)
-	return h, err
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
 }
 
-// ReplaceTopicHandler replaces an existing handler, with the new definition.
-func (c *Client) ReplaceTopicHandler(link Link, opt TopicHandlerOptions) (TopicHandler, error) {
-	h := TopicHandler{}
+// Get information about a schedule.
+func (c *Client) Schedule(link Link) (Schedule, error) {
+	s := Schedule{}
 	if link.Href == "" {
-		return h, fmt.Errorf("invalid link %v", link)
-	}
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	err := enc.Encode(opt)
-	if err != nil {
-		return h, err
+		return s, fmt.Errorf("invalid link %v", link)
 	}
 
 	u := *c.url
 	u.Path = link.Href
 
-	req, err := http.NewRequest("PUT", u.String(), &buf)
+	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return h, err
+		return s, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, &h, http.StatusOK)
-	return h, err
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
 }
 
-// DeleteTopicHandler deletes a handler.
-func (c *Client) DeleteTopicHandler(link Link) error {
+// Delete a schedule.
+// Schedules defined in the configuration file cannot be deleted.
+func (c *Client) DeleteSchedule(link Link) error {
 	if link.Href == "" {
 		return fmt.Errorf("invalid link %v", link)
 	}
@@ -2419,149 +4037,318 @@ func (c *Client) DeleteTopicHandler(link Link) error {
 	return err
 }
 
-type ListTopicHandlersOptions struct {
+type ListSchedulesOptions struct {
 	Pattern string
+	Offset  int
+	Limit   int
 }
 
-func (o *ListTopicHandlersOptions) Default() {}
+func (o *ListSchedulesOptions) Default() {
+	if o.Limit == 0 {
+		o.Limit = 100
+	}
+}
 
-func (o *ListTopicHandlersOptions) Values() *url.Values {
+func (o *ListSchedulesOptions) Values() *url.Values {
 	v := &url.Values{}
 	v.Set("pattern", o.Pattern)
+	v.Set("offset", strconv.FormatInt(int64(o.Offset), 10))
+	v.Set("limit", strconv.FormatInt(int64(o.Limit), 10))
 	return v
 }
 
-func (c *Client) ListTopicHandlers(link Link, opt *ListTopicHandlersOptions) (TopicHandlers, error) {
-	handlers := TopicHandlers{}
+// Get information about schedules, sorted by ID.
+func (c *Client) ListSchedules(opt *ListSchedulesOptions) (Schedules, error) {
+	schedules := Schedules{}
 	if opt == nil {
-		opt = new(ListTopicHandlersOptions)
+		opt = new(ListSchedulesOptions)
 	}
 	opt.Default()
 
 	u := *c.url
-	u.Path = link.Href
+	u.Path = schedulesPath
 	u.RawQuery = opt.Values().Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return handlers, err
+		return schedules, err
+	}
+
+	_, err = c.Do(req, &schedules, http.StatusOK)
+	return schedules, err
+}
+
+// TaskTestOptions defines a TICKscript unit test.
+// The script runs in isolation against the input data and
+// the alerts, httpOut results and influxDBOut writes it produces are checked against the expectations.
+type TaskTestOptions struct {
+	// Name of the test, used when reporting results.
+	Name       string   `json:"name,omitempty"`
+	Type       TaskType `json:"type"`
+	DBRPs      []DBRP   `json:"dbrps"`
+	TICKscript string   `json:"script"`
+	// Precision of the timestamps of Data and of the expected writes, defaults to nanoseconds.
+	Precision string `json:"precision,omitempty"`
+	// Data is the line protocol input of a stream task, written to the first DBRP.
+	Data string `json:"data,omitempty"`
+	// Batches is the input of a batch task, a list of batches for each query of the task
+	// in the format used by batch recordings.
+	Batches [][]json.RawMessage  `json:"batches,omitempty"`
+	Expect  TaskTestExpectations `json:"expect"`
+}
+
+// TaskTestExpectations are the expected outputs of a task test.
+// Nil alerts or writes are not checked, empty ones check that nothing was output.
+type TaskTestExpectations struct {
+	// Alerts are the expected alert events in order.
+	// Only the properties that are set are compared.
+	Alerts []TaskTestAlert `json:"alerts"`
+	// HTTPOut maps httpOut endpoints to their expected final result.
+	// Only the listed endpoints are checked.
+	HTTPOut map[string]json.RawMessage `json:"http-out,omitempty"`
+	// Writes are the expected influxDBOut writes in line protocol, in any order.
+	Writes []string `json:"writes"`
+}
+
+type TaskTestAlert struct {
+	ID      string    `json:"id,omitempty"`
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time,omitempty"`
+}
+
+type TaskTestResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Error is set if the test could not run.
+	Error string `json:"error,omitempty"`
+	// Failures lists the unmet expectations.
+	Failures       []string                   `json:"failures,omitempty"`
+	Alerts         []TaskTestAlert            `json:"alerts"`
+	HTTPOut        map[string]json.RawMessage `json:"http-out"`
+	Writes         []string                   `json:"writes"`
+	ExecutionStats ExecutionStats             `json:"stats,omitempty"`
+}
+
+// RunTaskTest runs a TICKscript unit test and returns its result.
+// A failing test is not an error, check the Passed field of the result.
+func (c *Client) RunTaskTest(opt TaskTestOptions) (TaskTestResult, error) {
+	r := TaskTestResult{}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
+	if err != nil {
+		return r, err
 	}
 
-	_, err = c.Do(req, &handlers, http.StatusOK)
+	u := *c.url
+	u.Path = taskTestsPath
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return handlers, err
+		return r, err
 	}
-	return handlers, nil
-}
+	req.Header.Set("Content-Type", "application/json")
 
-type StorageList struct {
-	Link    Link      `json:"link"`
-	Storage []Storage `json:"storage"`
+	_, err = c.Do(req, &r, http.StatusOK)
+	return r, err
 }
 
-type Storage struct {
-	Link Link   `json:"link"`
-	Name string `json:"name"`
+type DebugSessions struct {
+	Link     Link           `json:"link"`
+	Sessions []DebugSession `json:"sessions"`
 }
 
-type StorageAction int
+// A DebugSession controls the delivery of messages into a node of an executing task.
+type DebugSession struct {
+	Link Link   `json:"link"`
+	ID   string `json:"id"`
+	Task string `json:"task"`
+	Node string `json:"node"`
+	// ReplayID is the ID of the replay running the task, if any.
+	ReplayID string `json:"replay-id,omitempty"`
+	// Attached is false once the task has stopped and the session no longer controls the node.
+	Attached bool `json:"attached"`
+	Paused   bool `json:"paused"`
+	// Next are the next messages of each edge into the node,
+	// starting with the message waiting to be delivered.
+	Next []DebugMessage `json:"next"`
+	// Delivered are the most recently delivered messages, oldest first.
+	Delivered []DebugMessage `json:"delivered"`
+	Created   time.Time      `json:"created"`
+}
+
+// DebugMessage is a message on an edge into the node of a debug session.
+type DebugMessage struct {
+	Edge   string                 `json:"edge"`
+	Type   string                 `json:"type"`
+	Name   string                 `json:"name,omitempty"`
+	Time   time.Time              `json:"time"`
+	Group  string                 `json:"group,omitempty"`
+	Tags   map[string]string      `json:"tags,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Points are the points of a batch.
+	Points []DebugPoint `json:"points,omitempty"`
+}
+
+// DebugPoint is a point of a batch message.
+type DebugPoint struct {
+	Time   time.Time              `json:"time"`
+	Tags   map[string]string      `json:"tags,omitempty"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+type CreateDebugSessionOptions struct {
+	ID   string `json:"id,omitempty"`
+	Task string `json:"task"`
+	// Node is the name of the node, e.g. alert2, whose incoming messages are paused.
+	Node string `json:"node"`
+	// ReplayID debugs the task of a running replay instead of the enabled task.
+	ReplayID string `json:"replay-id,omitempty"`
+	// Force debugs an enabled task.
+	// While the node is paused all tasks of the server are stalled.
+	Force bool `json:"force,omitempty"`
+}
+
+type DebugAction string
 
 const (
-	_ StorageAction = iota
-	StorageRebuild
+	// DebugStep delivers the next messages while paused.
+	DebugStep DebugAction = "step"
+	// DebugPause stops the delivery of messages.
+	DebugPause DebugAction = "pause"
+	// DebugResume delivers all messages until paused again.
+	DebugResume DebugAction = "resume"
 )
 
-func (sa StorageAction) MarshalText() ([]byte, error) {
-	switch sa {
-	case StorageRebuild:
-		return []byte("rebuild"), nil
-	default:
-		return nil, fmt.Errorf("unknown StorageAction %d", sa)
-	}
+type UpdateDebugSessionOptions struct {
+	Action DebugAction `json:"action"`
+	// Count is the number of messages to step, defaults to 1.
+	Count int `json:"count,omitempty"`
 }
 
-func (sa *StorageAction) UnmarshalText(text []byte) error {
-	switch s := string(text); s {
-	case "rebuild":
-		*sa = StorageRebuild
-	default:
-		return fmt.Errorf("unknown StorageAction %s", s)
-	}
-	return nil
+// DebugSessionOptions are the options when getting a debug session.
+type DebugSessionOptions struct {
+	// Next is the number of messages to show per edge, defaults to 1.
+	Next int
 }
 
-func (sa StorageAction) String() string {
-	s, err := sa.MarshalText()
-	if err != nil {
-		return err.Error()
+func (o *DebugSessionOptions) Values() *url.Values {
+	v := &url.Values{}
+	if o.Next > 0 {
+		v.Set("next", strconv.FormatInt(int64(o.Next), 10))
 	}
-	return string(s)
+	return v
 }
 
-type StorageActionOptions struct {
-	Action StorageAction `json:"action"`
+func (c *Client) DebugSessionLink(id string) Link {
+	return Link{Relation: Self, Href: path.Join(debugSessionsPath, id)}
 }
 
-func (c *Client) ListStorage() (StorageList, error) {
-	list := StorageList{}
-	u := *c.url
-	u.Path = storesPath
-
-	req, err := http.NewRequest("GET", u.String(), nil)
+// CreateDebugSession sets a breakpoint on the edges into a node of an executing task.
+// The session starts paused.
+func (c *Client) CreateDebugSession(opt CreateDebugSessionOptions) (DebugSession, error) {
+	s := DebugSession{}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	err := enc.Encode(opt)
 	if err != nil {
-		return list, err
+		return s, err
 	}
 
-	_, err = c.Do(req, &list, http.StatusOK)
+	u := *c.url
+	u.Path = debugSessionsPath
+
+	req, err := http.NewRequest("POST", u.String(), &buf)
 	if err != nil {
-		return list, err
+		return s, err
 	}
-	return list, nil
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.Do(req, &s, http.StatusCreated)
+	return s, err
 }
 
-func (c *Client) DoStorageAction(l Link, opt StorageActionOptions) error {
+// DebugSession returns the state of a debug session including its next messages.
+func (c *Client) DebugSession(link Link, opt *DebugSessionOptions) (DebugSession, error) {
+	s := DebugSession{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
+	if opt == nil {
+		opt = new(DebugSessionOptions)
+	}
+
 	u := *c.url
-	u.Path = l.Href
+	u.Path = link.Href
+	u.RawQuery = opt.Values().Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return s, err
+	}
+
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
 
+// UpdateDebugSession steps, pauses or resumes a debug session and returns its new state.
+func (c *Client) UpdateDebugSession(link Link, opt UpdateDebugSessionOptions) (DebugSession, error) {
+	s := DebugSession{}
+	if link.Href == "" {
+		return s, fmt.Errorf("invalid link %v", link)
+	}
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	err := enc.Encode(opt)
 	if err != nil {
-		return err
+		return s, err
 	}
-	req, err := http.NewRequest("POST", u.String(), &buf)
+
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("PATCH", u.String(), &buf)
 	if err != nil {
-		return err
+		return s, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	_, err = c.Do(req, nil, http.StatusNoContent)
+	_, err = c.Do(req, &s, http.StatusOK)
+	return s, err
+}
+
+// DeleteDebugSession removes the breakpoint of a debug session and resumes the delivery of messages.
+func (c *Client) DeleteDebugSession(link Link) error {
+	if link.Href == "" {
+		return fmt.Errorf("invalid link %v", link)
+	}
+	u := *c.url
+	u.Path = link.Href
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	_, err = c.Do(req, nil, http.StatusNoContent)
+	return err
 }
 
-// Backup requests a backup of all storage from Kapacitor.
-// A short read is possible, to verify that the backup was successful
-// check that the number of bytes read matches the returned size.
-func (c *Client) Backup() (int64, io.ReadCloser, error) {
+// ListDebugSessions returns all debug sessions, sorted by ID.
+func (c *Client) ListDebugSessions() (DebugSessions, error) {
+	sessions := DebugSessions{}
+
 	u := *c.url
-	u.Path = backupPath
+	u.Path = debugSessionsPath
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return 0, nil, err
-	}
-	err = c.prepRequest(req)
-	if err != nil {
-		return 0, nil, err
-	}
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return 0, nil, err
+		return sessions, err
 	}
-	return resp.ContentLength, resp.Body, nil
+
+	_, err = c.Do(req, &sessions, http.StatusOK)
+	return sessions, err
 }
 
 type LogLevelOptions struct {
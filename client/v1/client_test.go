@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -1235,6 +1236,206 @@ func Test_DeleteTemplate(t *testing.T) {
 	}
 }
 
+func Test_ListRevisions(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kapacitor/v1/tasks/t1/revisions" && r.Method == "GET" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"link": {"rel":"self", "href":"/kapacitor/v1/tasks/t1/revisions"},
+	"revisions": [
+		{
+			"link": {"rel":"self", "href":"/kapacitor/v1/tasks/t1/revisions/1"},
+			"revision": 1,
+			"type": "stream",
+			"dbrps": [{"db":"db","rp":"rp"}],
+			"script": "stream|from()",
+			"author": "bob",
+			"created": "2021-01-01T00:00:00Z"
+		},
+		{
+			"link": {"rel":"self", "href":"/kapacitor/v1/tasks/t1/revisions/2"},
+			"revision": 2,
+			"type": "stream",
+			"dbrps": [{"db":"db","rp":"rp"}],
+			"script": "stream|from()",
+			"author": "alice",
+			"created": "2021-01-02T00:00:00Z",
+			"rollback-of": 1
+		}
+	]
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	revisions, err := c.ListRevisions(c.TaskLink("t1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []client.Revision{
+		{
+			Link:       client.Link{Relation: client.Self, Href: "/kapacitor/v1/tasks/t1/revisions/1"},
+			Number:     1,
+			Type:       client.StreamTask,
+			DBRPs:      []client.DBRP{{Database: "db", RetentionPolicy: "rp"}},
+			TICKscript: "stream|from()",
+			Author:     "bob",
+			Created:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Link:       client.Link{Relation: client.Self, Href: "/kapacitor/v1/tasks/t1/revisions/2"},
+			Number:     2,
+			Type:       client.StreamTask,
+			DBRPs:      []client.DBRP{{Database: "db", RetentionPolicy: "rp"}},
+			TICKscript: "stream|from()",
+			Author:     "alice",
+			Created:    time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			RollbackOf: 1,
+		},
+	}
+	if !cmp.Equal(exp, revisions) {
+		t.Errorf("unexpected revisions:\n%s", cmp.Diff(exp, revisions))
+	}
+}
+
+func Test_DiffRevisions(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kapacitor/v1/templates/tmpl/revisions/diff" && r.Method == "GET" &&
+			r.URL.Query().Get("from") == "3" &&
+			r.URL.Query().Get("to") == "" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"link": {"rel":"self", "href":"/kapacitor/v1/templates/tmpl/revisions/diff?from=3"},
+	"from": 3,
+	"to": 5,
+	"script": "--- revision 3\n+++ revision 5\n@@ -1 +1 @@\n-stream|from()\n+batch|query('SELECT 1')\n",
+	"dbrps": "",
+	"vars": ""
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	diff, err := c.DiffRevisions(c.TemplateLink("tmpl"), &client.RevisionDiffOptions{From: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.RevisionDiff{
+		Link:       client.Link{Relation: client.Self, Href: "/kapacitor/v1/templates/tmpl/revisions/diff?from=3"},
+		From:       3,
+		To:         5,
+		TICKscript: "--- revision 3\n+++ revision 5\n@@ -1 +1 @@\n-stream|from()\n+batch|query('SELECT 1')\n",
+	}
+	if !cmp.Equal(exp, diff) {
+		t.Errorf("unexpected diff:\n%s", cmp.Diff(exp, diff))
+	}
+}
+
+func Test_CompareShadow(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kapacitor/v1/tasks/cpu_v2/shadow/compare" && r.Method == "GET" &&
+			r.URL.Query().Get("start") == "2021-01-01T00:00:00Z" &&
+			r.URL.Query().Get("stop") == "" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"link": {"rel":"self", "href":"/kapacitor/v1/tasks/cpu_v2/shadow/compare"},
+	"task": "cpu_v2",
+	"shadow-of": "cpu",
+	"summary": {"match": 0, "different": 1, "primary-only": 0, "shadow-only": 0},
+	"alerts": [
+		{
+			"id": "cpu:a",
+			"status": "different",
+			"primary": [{"time": "2021-01-01T00:01:00Z", "level": "WARNING"}],
+			"shadow": [{"time": "2021-01-01T00:01:00Z", "level": "CRITICAL"}]
+		}
+	]
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, err := c.CompareShadow(c.TaskLink("cpu_v2"), &client.ShadowOptions{Start: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.ShadowComparison{
+		Link:     client.Link{Relation: client.Self, Href: "/kapacitor/v1/tasks/cpu_v2/shadow/compare"},
+		Task:     "cpu_v2",
+		ShadowOf: "cpu",
+		Summary:  client.ShadowComparisonSummary{Different: 1},
+		Alerts: []client.ShadowAlertComparison{{
+			ID:      "cpu:a",
+			Status:  client.ShadowAlertDifferent,
+			Primary: []client.ShadowAlertEvent{{Time: start.Add(time.Minute), Level: "WARNING"}},
+			Shadow:  []client.ShadowAlertEvent{{Time: start.Add(time.Minute), Level: "CRITICAL"}},
+		}},
+	}
+	if !cmp.Equal(exp, got) {
+		t.Errorf("unexpected comparison:\n%s", cmp.Diff(exp, got))
+	}
+}
+
+func Test_RollbackTask(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var opt client.RollbackOptions
+		json.NewDecoder(r.Body).Decode(&opt)
+		if r.URL.Path == "/kapacitor/v1/tasks/t1/rollback" && r.Method == "POST" && opt.Revision == 2 {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"link": {"rel":"self", "href":"/kapacitor/v1/tasks/t1"},
+	"id": "t1",
+	"type":"stream",
+	"script":"stream|from()",
+	"status": "enabled",
+	"executing": true
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	task, err := c.RollbackTask(c.TaskLink("t1"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.Task{
+		Link:       client.Link{Relation: client.Self, Href: "/kapacitor/v1/tasks/t1"},
+		ID:         "t1",
+		Type:       client.StreamTask,
+		TICKscript: "stream|from()",
+		Status:     client.Enabled,
+		Executing:  true,
+	}
+	if !cmp.Equal(exp, task) {
+		t.Errorf("unexpected task:\n%s", cmp.Diff(exp, task))
+	}
+}
+
 func Test_ListTemplates(t *testing.T) {
 	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/kapacitor/v1/templates" && r.Method == "GET" &&
@@ -1458,6 +1659,61 @@ func Test_RecordQuery(t *testing.T) {
 	}
 }
 
+func Test_UploadRecording(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		q := r.URL.Query()
+		if r.URL.Path == "/kapacitor/v1/recordings/upload" && r.Method == "POST" &&
+			q.Get("id") == "incident" &&
+			q.Get("format") == "line-protocol" &&
+			q.Get("db") == "telegraf" &&
+			q.Get("rp") == "autogen" &&
+			q.Get("precision") == "s" &&
+			string(body) == "cpu value=1 0\n" {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{
+	"link": {"rel":"self", "href":"/kapacitor/v1/recordings/incident"},
+	"id": "incident",
+	"type": "stream",
+	"status": "finished",
+	"progress": 1.0,
+	"start": "1970-01-01T00:00:00Z",
+	"stop": "1970-01-01T00:00:00Z"
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v body: %s", r, string(body))
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	r, err := c.UploadRecording(client.UploadRecordingOptions{
+		ID:              "incident",
+		Format:          client.LineProtocolFormat,
+		Database:        "telegraf",
+		RetentionPolicy: "autogen",
+		Precision:       "s",
+	}, strings.NewReader("cpu value=1 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.Recording{
+		Link:     client.Link{Relation: client.Self, Href: "/kapacitor/v1/recordings/incident"},
+		ID:       "incident",
+		Type:     client.StreamTask,
+		Status:   client.Finished,
+		Progress: 1.0,
+		Start:    time.Unix(0, 0).UTC(),
+		Stop:     time.Unix(0, 0).UTC(),
+	}
+	if !cmp.Equal(exp, r) {
+		t.Errorf("unexpected recording:\ngot:\n%v\nexp:\n%v", r, exp)
+	}
+}
+
 func Test_Recording(t *testing.T) {
 	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/kapacitor/v1/recordings/rid1" && r.Method == "GET" {
@@ -2545,6 +2801,292 @@ func Test_DoServiceTest(t *testing.T) {
 	}
 }
 
+func Test_CompareReplay(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options := client.CompareReplayOptions{}
+		json.NewDecoder(r.Body).Decode(&options)
+		expOptions := client.CompareReplayOptions{
+			Recording:  "recordingid",
+			Task:       "taskname",
+			TICKscript: "stream|from()",
+		}
+		if r.URL.Path == "/kapacitor/v1/replays/compare" &&
+			r.Method == "POST" &&
+			cmp.Equal(expOptions, options) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"recording": "recordingid",
+	"task": "taskname",
+	"equal": false,
+	"alerts": [{
+		"id": "cpu:nil",
+		"changed": [{
+			"current": {"id": "cpu:nil", "level": "CRITICAL", "time": "2016-01-01T00:00:10Z"},
+			"candidate": {"id": "cpu:nil", "level": "WARNING", "time": "2016-01-01T00:00:10Z"}
+		}]
+	}],
+	"writes-added": ["cpu value=1 0"],
+	"writes-removed": null
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	comparison, err := c.CompareReplay(client.CompareReplayOptions{
+		Recording:  "recordingid",
+		Task:       "taskname",
+		TICKscript: "stream|from()",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := time.Date(2016, 1, 1, 0, 0, 10, 0, time.UTC)
+	exp := client.ReplayComparison{
+		Recording: "recordingid",
+		Task:      "taskname",
+		Alerts: []client.AlertDifference{{
+			ID: "cpu:nil",
+			Changed: []client.AlertChange{{
+				Current:   client.TaskTestAlert{ID: "cpu:nil", Level: "CRITICAL", Time: tm},
+				Candidate: client.TaskTestAlert{ID: "cpu:nil", Level: "WARNING", Time: tm},
+			}},
+		}},
+		WritesAdded: []string{"cpu value=1 0"},
+	}
+	if !cmp.Equal(exp, comparison) {
+		t.Errorf("unexpected replay comparison:\ngot:\n%v\nexp:\n%v", comparison, exp)
+	}
+}
+
+func Test_RunTaskTest(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options := client.TaskTestOptions{}
+		json.NewDecoder(r.Body).Decode(&options)
+		expOptions := client.TaskTestOptions{
+			Name:       "cpu",
+			Type:       client.StreamTask,
+			DBRPs:      []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}},
+			TICKscript: "stream|from().measurement('cpu')",
+			Precision:  "s",
+			Data:       "cpu value=1 0",
+			Expect: client.TaskTestExpectations{
+				Writes: []string{},
+			},
+		}
+		if r.URL.Path == "/kapacitor/v1/task-tests" &&
+			r.Method == "POST" &&
+			cmp.Equal(expOptions, options) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+	"name": "cpu",
+	"passed": false,
+	"failures": ["unexpected write \"cpu value=1 0\""],
+	"alerts": [],
+	"http-out": {},
+	"writes": ["cpu value=1 0"]
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tr, err := c.RunTaskTest(client.TaskTestOptions{
+		Name:       "cpu",
+		Type:       client.StreamTask,
+		DBRPs:      []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}},
+		TICKscript: "stream|from().measurement('cpu')",
+		Precision:  "s",
+		Data:       "cpu value=1 0",
+		Expect: client.TaskTestExpectations{
+			Writes: []string{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.TaskTestResult{
+		Name:     "cpu",
+		Passed:   false,
+		Failures: []string{`unexpected write "cpu value=1 0"`},
+		Alerts:   []client.TaskTestAlert{},
+		HTTPOut:  map[string]json.RawMessage{},
+		Writes:   []string{"cpu value=1 0"},
+	}
+	if !cmp.Equal(exp, tr) {
+		t.Errorf("unexpected task test result:\ngot:\n%v\nexp:\n%v", tr, exp)
+	}
+}
+
+func Test_CreateDebugSession(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options := client.CreateDebugSessionOptions{}
+		json.NewDecoder(r.Body).Decode(&options)
+		expOptions := client.CreateDebugSessionOptions{
+			Task: "taskname",
+			Node: "alert2",
+		}
+		if r.URL.Path == "/kapacitor/v1/debug-sessions" &&
+			r.Method == "POST" &&
+			cmp.Equal(expOptions, options) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{
+	"link": {"rel": "self", "href": "/kapacitor/v1/debug-sessions/sessionid"},
+	"id": "sessionid",
+	"task": "taskname",
+	"node": "alert2",
+	"attached": true,
+	"paused": true,
+	"next": [{
+		"edge": "window1->alert2",
+		"type": "buffered_batch",
+		"name": "cpu",
+		"time": "2016-01-01T00:00:10Z",
+		"group": "host=serverA",
+		"tags": {"host": "serverA"},
+		"points": [{"time": "2016-01-01T00:00:05Z", "tags": {"host": "serverA"}, "fields": {"value": 42}}]
+	}],
+	"delivered": [],
+	"created": "2016-01-01T00:00:00Z"
+}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	session, err := c.CreateDebugSession(client.CreateDebugSessionOptions{
+		Task: "taskname",
+		Node: "alert2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.DebugSession{
+		Link:     client.Link{Relation: client.Self, Href: "/kapacitor/v1/debug-sessions/sessionid"},
+		ID:       "sessionid",
+		Task:     "taskname",
+		Node:     "alert2",
+		Attached: true,
+		Paused:   true,
+		Next: []client.DebugMessage{{
+			Edge:  "window1->alert2",
+			Type:  "buffered_batch",
+			Name:  "cpu",
+			Time:  time.Date(2016, 1, 1, 0, 0, 10, 0, time.UTC),
+			Group: "host=serverA",
+			Tags:  map[string]string{"host": "serverA"},
+			Points: []client.DebugPoint{{
+				Time:   time.Date(2016, 1, 1, 0, 0, 5, 0, time.UTC),
+				Tags:   map[string]string{"host": "serverA"},
+				Fields: map[string]interface{}{"value": 42.0},
+			}},
+		}},
+		Delivered: []client.DebugMessage{},
+		Created:   time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if !cmp.Equal(exp, session) {
+		t.Errorf("unexpected debug session:\ngot:\n%v\nexp:\n%v", session, exp)
+	}
+}
+
+func Test_DebugSession(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kapacitor/v1/debug-sessions/sessionid" &&
+			r.Method == "GET" &&
+			r.URL.Query().Get("next") == "10" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"id": "sessionid", "paused": true}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	session, err := c.DebugSession(c.DebugSessionLink("sessionid"), &client.DebugSessionOptions{Next: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.DebugSession{ID: "sessionid", Paused: true}
+	if !cmp.Equal(exp, session) {
+		t.Errorf("unexpected debug session:\ngot:\n%v\nexp:\n%v", session, exp)
+	}
+}
+
+func Test_UpdateDebugSession(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options := client.UpdateDebugSessionOptions{}
+		json.NewDecoder(r.Body).Decode(&options)
+		expOptions := client.UpdateDebugSessionOptions{
+			Action: client.DebugStep,
+			Count:  3,
+		}
+		if r.URL.Path == "/kapacitor/v1/debug-sessions/sessionid" &&
+			r.Method == "PATCH" &&
+			cmp.Equal(expOptions, options) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"id": "sessionid", "paused": true}`)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	session, err := c.UpdateDebugSession(c.DebugSessionLink("sessionid"), client.UpdateDebugSessionOptions{
+		Action: client.DebugStep,
+		Count:  3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := client.DebugSession{ID: "sessionid", Paused: true}
+	if !cmp.Equal(exp, session) {
+		t.Errorf("unexpected debug session:\ngot:\n%v\nexp:\n%v", session, exp)
+	}
+}
+
+func Test_DeleteDebugSession(t *testing.T) {
+	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/kapacitor/v1/debug-sessions/sessionid" &&
+			r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "request: %v", r)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := c.DeleteDebugSession(c.DebugSessionLink("sessionid")); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func Test_Topic(t *testing.T) {
 	s, c, err := newClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.String() == "/kapacitor/v1/alerts/topics/system" &&
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/influxdata/kapacitor/client/v1"
+	"github.com/pkg/errors"
+)
+
+var (
+	debugFlags    = flag.NewFlagSet("debug", flag.ExitOnError)
+	debugNode     = debugFlags.String("node", "", "The name of the node to pause, e.g. alert2. See the DOT graph of 'kapacitor show' for node names.")
+	debugReplayID = debugFlags.String("replay-id", "", "Optional ID of a running replay to debug instead of the enabled task.")
+	debugForce    = debugFlags.Bool("force", false, "Debug the enabled task, all tasks of the server are stalled while the node is paused.")
+)
+
+func init() {
+	debugFlags.Usage = debugUsage
+}
+
+func debugUsage() {
+	var u = `Usage: kapacitor debug <task ID> -node <node name> [-replay-id <replay ID>] [-force]
+
+	Interactively inspect the messages flowing into a node of a running task.
+
+	Delivery of messages into the node is paused until stepped or resumed.
+	The messages waiting to be delivered are shown with their group, tags and fields.
+	Delivery continues normally once the debugger quits,
+	or once the session has been idle for 10 minutes.
+
+	Pausing an enabled task stalls all tasks of the server,
+	so enabled tasks can only be debugged with -force.
+
+	Commands:
+
+		step [N]      Deliver the next N messages, defaults to 1. An empty line steps once.
+		next [N]      Show the next N messages of each edge into the node, defaults to 1.
+		history       Show the most recently delivered messages.
+		pause         Pause delivery.
+		resume        Resume delivery until paused again.
+		quit          Resume delivery and exit.
+
+For example:
+
+	Debug a task while it is replaying a recording:
+
+		$ kapacitor replay -task cpu_alert -recording RECORDING_ID
+		$ kapacitor debug cpu_alert -node alert2 -replay-id REPLAY_ID
+
+	Debug the alert node of an enabled task:
+
+		$ kapacitor debug cpu_alert -node alert2 -force
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	debugFlags.PrintDefaults()
+}
+
+// parseDebugArgs parses the task ID and the flags, which may be given before or after the task ID.
+func parseDebugArgs(args []string) (string, error) {
+	var task string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		task = args[0]
+		args = args[1:]
+	}
+	debugFlags.Parse(args)
+	if task == "" && debugFlags.NArg() > 0 {
+		task = debugFlags.Arg(0)
+	}
+	if task == "" {
+		return "", errors.New("must provide task ID")
+	}
+	if *debugNode == "" {
+		return "", errors.New("must provide the name of the node to debug")
+	}
+	return task, nil
+}
+
+func doDebug(args []string) error {
+	task, err := parseDebugArgs(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		debugUsage()
+		os.Exit(2)
+	}
+
+	session, err := kCli.CreateDebugSession(client.CreateDebugSessionOptions{
+		Task:     task,
+		Node:     *debugNode,
+		ReplayID: *debugReplayID,
+		Force:    *debugForce,
+	})
+	if err != nil {
+		return err
+	}
+	// Always resume the task when exiting.
+	quit := func() error {
+		return kCli.DeleteDebugSession(session.Link)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		if err := quit(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}()
+
+	fmt.Printf("Paused messages into %s of task %s. Type 'help' for a list of commands.\n", session.Node, session.Task)
+	printDebugSession(os.Stdout, session)
+
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("(debug) ")
+		if !in.Scan() {
+			fmt.Println()
+			return quit()
+		}
+		fields := strings.Fields(in.Text())
+		cmd, n := "step", 1
+		if len(fields) > 0 {
+			cmd = fields[0]
+		}
+		if len(fields) > 1 {
+			n, err = strconv.Atoi(fields[1])
+			if err != nil || n < 1 {
+				fmt.Printf("invalid count %q\n", fields[1])
+				continue
+			}
+		}
+
+		switch cmd {
+		case "step", "s":
+			session, err = kCli.UpdateDebugSession(session.Link, client.UpdateDebugSessionOptions{Action: client.DebugStep, Count: n})
+		case "next", "n":
+			session, err = kCli.DebugSession(session.Link, &client.DebugSessionOptions{Next: n})
+		case "history", "h":
+			session, err = kCli.DebugSession(session.Link, nil)
+			if err == nil {
+				for _, m := range session.Delivered {
+					printDebugMessage(os.Stdout, m)
+				}
+			}
+			continue
+		case "pause", "p":
+			session, err = kCli.UpdateDebugSession(session.Link, client.UpdateDebugSessionOptions{Action: client.DebugPause})
+		case "resume", "r":
+			session, err = kCli.UpdateDebugSession(session.Link, client.UpdateDebugSessionOptions{Action: client.DebugResume})
+		case "quit", "q":
+			return quit()
+		case "help":
+			fmt.Println("Commands: step [N], next [N], history, pause, resume, quit")
+			continue
+		default:
+			fmt.Printf("unknown command %q, type 'help' for a list of commands\n", cmd)
+			continue
+		}
+		if err != nil {
+			quit()
+			return err
+		}
+		printDebugSession(os.Stdout, session)
+	}
+}
+
+func printDebugSession(w io.Writer, session client.DebugSession) {
+	if !session.Attached {
+		fmt.Fprintln(w, "The task has stopped, the debug session is no longer attached.")
+		return
+	}
+	if !session.Paused {
+		fmt.Fprintln(w, "Running.")
+		return
+	}
+	if len(session.Next) == 0 {
+		fmt.Fprintln(w, "Paused, waiting for the next message.")
+		return
+	}
+	for _, m := range session.Next {
+		printDebugMessage(w, m)
+	}
+}
+
+func printDebugMessage(w io.Writer, m client.DebugMessage) {
+	fmt.Fprintf(w, "%s %s", m.Edge, m.Type)
+	if m.Name != "" {
+		fmt.Fprintf(w, " %s", m.Name)
+	}
+	if !m.Time.IsZero() {
+		fmt.Fprintf(w, " %s", m.Time.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintln(w)
+	if m.Group != "" {
+		fmt.Fprintf(w, "\tgroup:  %s\n", m.Group)
+	}
+	if len(m.Tags) > 0 {
+		fmt.Fprintf(w, "\ttags:   %s\n", formatDebugTags(m.Tags))
+	}
+	if len(m.Fields) > 0 {
+		fmt.Fprintf(w, "\tfields: %s\n", formatDebugFields(m.Fields))
+	}
+	for _, p := range m.Points {
+		fmt.Fprintf(w, "\t%s %s\n", p.Time.Format(time.RFC3339Nano), formatDebugFields(p.Fields))
+	}
+}
+
+func formatDebugTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func formatDebugFields(fields map[string]interface{}) string {
+	pairs := make([]string, 0, len(fields))
+	for k, v := range fields {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -53,23 +53,35 @@ Commands:
 	record                Record the result of a query or a snapshot of the current stream data.
 	define                Create/update a task.
 	define-template       Create/update a template.
+	define-library        Create/update a library of TICKscript definitions.
+	define-schedule       Create/update a schedule.
 	define-topic-handler  Create/update an alert handler for a topic.
 	replay                Replay a recording to a task.
 	replay-live           Replay data against a task without recording it.
+	test                  Run TICKscript unit tests.
+	debug                 Pause and inspect the messages flowing into a node of a task.
+	shadow                Compare the alerts of a shadow task with those of the task it shadows.
 	watch                 Watch logs for a task.
 	logs                  Follow arbitrary Kapacitor logs.
 	enable                Enable and start running a task with live data.
 	disable               Stop running a task.
 	reload                Reload a running task with an updated task definition.
+	rollback              Restore a task or template to one of its revisions.
 	push                  Publish a task definition to another Kapacitor instance. Not implemented yet.
-	delete                Delete tasks, templates, recordings, replays, topics or topic-handlers.
-	list                  List information about tasks, templates, recordings, replays, topics, topic-handlers or service-tests.
+	delete                Delete tasks, templates, libraries, schedules, recordings, replays, topics or topic-handlers.
+	list                  List information about tasks, templates, libraries, schedules, recordings, replays, topics, topic-handlers or service-tests.
 	show                  Display detailed information about a task.
 	show-template         Display detailed information about a template.
+	show-library          Display detailed information about a library.
+	show-schedule         Display detailed information about a schedule.
 	show-topic-handler    Display detailed information about an alert handler for a topic.
 	show-topic            Display detailed information about an alert topic.
+	dead-letters          List, replay or purge the events an alert handler failed to handle.
+	silence               Silence events of an alert topic.
+	ack                   Acknowledge events of an alert topic.
 	flux                  Flux task information and management
 	backup                Backup the Kapacitor database.
+	blob                  Manage blobs and blob tags.
 	level                 Sets the logging level on the kapacitord server.
 	stats                 Display various stats about Kapacitor.
 	version               Displays the Kapacitor version info.
@@ -146,6 +158,12 @@ func main() {
 	case "define-template":
 		commandArgs = args
 		commandF = doDefineTemplate
+	case "define-library":
+		commandArgs = args
+		commandF = doDefineLibrary
+	case "define-schedule":
+		commandArgs = args
+		commandF = doDefineSchedule
 	case "define-topic-handler":
 		commandArgs = args
 		commandF = doDefineTopicHandler
@@ -160,6 +178,16 @@ func main() {
 		}
 		commandArgs = args
 		commandF = doReplayLive
+	case "test":
+		testFlags.Parse(args)
+		commandArgs = testFlags.Args()
+		commandF = doTest
+	case "debug":
+		commandArgs = args
+		commandF = doDebug
+	case "shadow":
+		commandArgs = args
+		commandF = doShadow
 	case "watch":
 		commandArgs = args
 		commandF = doWatch
@@ -175,6 +203,10 @@ func main() {
 	case "reload":
 		commandArgs = args
 		commandF = doReload
+	case "rollback":
+		rollbackFlags.Parse(args)
+		commandArgs = rollbackFlags.Args()
+		commandF = doRollback
 	case "delete":
 		commandArgs = args
 		commandF = doDelete
@@ -188,18 +220,38 @@ func main() {
 	case "show-template":
 		commandArgs = args
 		commandF = doShowTemplate
+	case "show-library":
+		commandArgs = args
+		commandF = doShowLibrary
+	case "show-schedule":
+		commandArgs = args
+		commandF = doShowSchedule
 	case "show-topic-handler":
 		commandArgs = args
 		commandF = doShowTopicHandler
 	case "show-topic":
-		commandArgs = args
+		showTopicFlags.Parse(args)
+		commandArgs = showTopicFlags.Args()
 		commandF = doShowTopic
+	case "dead-letters":
+		commandArgs = args
+		commandF = doDeadLetters
+	case "silence":
+		commandArgs = args
+		commandF = doSilence
+	case "ack":
+		ackFlags.Parse(args)
+		commandArgs = ackFlags.Args()
+		commandF = doAck
 	case "flux":
 		commandArgs = args
 		commandF = doFluxTasks(url, skipSSL)
 	case "backup":
 		commandArgs = args
 		commandF = doBackup
+	case "blob":
+		commandArgs = args
+		commandF = doBlob
 	case "level":
 		commandArgs = args
 		commandF = doLevel
@@ -232,11 +284,18 @@ func init() {
 	replayFlags.Usage = replayUsage
 	defineFlags.Usage = defineUsage
 	defineTemplateFlags.Usage = defineTemplateUsage
+	defineLibraryFlags.Usage = defineLibraryUsage
 	showFlags.Usage = showUsage
+	rollbackFlags.Usage = rollbackUsage
+	showTopicFlags.Usage = showTopicUsage
+	blobCreateFlags.Usage = blobCreateUsage
+	silenceCreateFlags.Usage = silenceCreateUsage
+	ackFlags.Usage = ackUsage
 
 	recordStreamFlags.Usage = recordStreamUsage
 	recordBatchFlags.Usage = recordBatchUsage
 	recordQueryFlags.Usage = recordQueryUsage
+	recordUploadFlags.Usage = recordUploadUsage
 
 	replayLiveBatchFlags.Usage = replayLiveBatchUsage
 	replayLiveQueryFlags.Usage = replayLiveQueryUsage
@@ -267,16 +326,28 @@ func doHelp(args []string) error {
 			defineFlags.Usage()
 		case "define-template":
 			defineTemplateFlags.Usage()
+		case "define-library":
+			defineLibraryFlags.Usage()
+		case "define-schedule":
+			defineScheduleUsage()
 		case "define-topic-handler":
 			defineTopicHandlerUsage()
 		case "replay":
 			replayFlags.Usage()
+		case "test":
+			testUsage()
+		case "debug":
+			debugUsage()
+		case "shadow":
+			shadowUsage()
 		case "enable":
 			enableUsage()
 		case "disable":
 			disableUsage()
 		case "reload":
 			reloadUsage()
+		case "rollback":
+			rollbackUsage()
 		case "delete":
 			deleteUsage()
 		case "list":
@@ -285,15 +356,27 @@ func doHelp(args []string) error {
 			showUsage()
 		case "show-template":
 			showTemplateUsage()
+		case "show-library":
+			showLibraryUsage()
+		case "show-schedule":
+			showScheduleUsage()
 		case "show-topic-handler":
 			showTopicHandlerUsage()
 		case "show-topic":
 			showTopicUsage()
+		case "dead-letters":
+			deadLettersUsage()
+		case "silence":
+			silenceUsage()
+		case "ack":
+			ackUsage()
 		case "flux":
 			app := createFluxTaskApp("", false)
 			app.Run([]string{"", "-h"})
 		case "backup":
 			backupUsage()
+		case "blob":
+			blobUsage()
 		case "watch":
 			watchUsage()
 		case "logs":
@@ -344,12 +427,21 @@ var (
 	rqCluster = recordQueryFlags.String("cluster", "", "Optional named InfluxDB cluster from configuration.")
 	rqNowait  = recordQueryFlags.Bool("no-wait", false, "Do not wait for the recording to finish.")
 	rqId      = recordQueryFlags.String("recording-id", "", "The ID to give to this recording. If not set an random ID is chosen.")
+
+	recordUploadFlags = flag.NewFlagSet("record-upload", flag.ExitOnError)
+	ruFormat          = recordUploadFlags.String("format", "", "The format of the data (line-protocol|csv|batch-json). If not set it is chosen by the file extension, .csv for csv, .json for batch-json and line-protocol otherwise.")
+	ruType            = recordUploadFlags.String("type", "stream", "The type of the recording to save from CSV data (stream|batch).")
+	ruDB              = recordUploadFlags.String("db", "", "The database of the points of stream recordings.")
+	ruRP              = recordUploadFlags.String("rp", "", "The retention policy of the points of stream recordings.")
+	ruPrecision       = recordUploadFlags.String("precision", "", "The precision of line protocol timestamps (n|u|ms|s|m|h), defaults to nanoseconds.")
+	ruId              = recordUploadFlags.String("recording-id", "", "The ID to give to this recording. If not set an random ID is chosen.")
 )
 
 func recordUsage() {
-	var u = `Usage: kapacitor record [batch|stream|query] [options]
+	var u = `Usage: kapacitor record [batch|stream|query|upload] [options]
 
-	Record the result of a InfluxDB query or a snapshot of the live data stream.
+	Record the result of a InfluxDB query or a snapshot of the live data stream,
+	or upload data exported from elsewhere as a recording.
 
 	Prints the recording ID on exit.
 
@@ -432,6 +524,44 @@ Options:
 	recordQueryFlags.PrintDefaults()
 }
 
+func recordUploadUsage() {
+	var u = `Usage: kapacitor record upload [options] <path to data file>
+
+	Upload data exported from elsewhere as a recording.
+
+	The data is validated and converted into a recording,
+	the time range of the data is saved with the recording.
+
+	Supported formats are:
+
+		line-protocol  Line protocol, recorded as stream. Blank lines and comments are skipped.
+		csv            Annotated CSV as returned by Flux queries, recorded as stream or batch.
+		               Tables with _field and _value columns are converted into points with the named field.
+		               Each table becomes a batch of a batch recording.
+		batch-json     Newline delimited batches as saved in batch recordings, recorded as batch.
+
+	Stream recordings need the database and retention policy of the points.
+
+	Prints the recording ID on exit.
+
+	See 'kapacitor help replay' for how to replay a recording.
+
+Examples:
+
+	$ kapacitor record upload -db telegraf -rp autogen -precision s -recording-id incident cpu.txt
+
+		This uploads the line protocol in cpu.txt as stream recording 'incident'.
+
+	$ kapacitor record upload -type batch cpu.csv
+
+		This uploads the annotated CSV in cpu.csv as batch recording.
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	recordUploadFlags.PrintDefaults()
+}
+
 func doRecord(args []string) error {
 	var recording client.Recording
 	var err error
@@ -526,8 +656,46 @@ func doRecord(args []string) error {
 		if err != nil {
 			return err
 		}
+	case "upload":
+		recordUploadFlags.Parse(args[1:])
+		if recordUploadFlags.NArg() != 1 {
+			recordUploadFlags.Usage()
+			return errors.New("must provide a path to a data file")
+		}
+		p := recordUploadFlags.Arg(0)
+		format := *ruFormat
+		if format == "" {
+			switch path.Ext(p) {
+			case ".csv":
+				format = client.CSVFormat
+			case ".json":
+				format = client.BatchJSONFormat
+			default:
+				format = client.LineProtocolFormat
+			}
+		}
+		var typ client.TaskType
+		if err := typ.UnmarshalText([]byte(*ruType)); err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open data file %q", p)
+		}
+		defer f.Close()
+		recording, err = kCli.UploadRecording(client.UploadRecordingOptions{
+			ID:              *ruId,
+			Format:          format,
+			Type:            typ,
+			Database:        *ruDB,
+			RetentionPolicy: *ruRP,
+			Precision:       *ruPrecision,
+		}, f)
+		if err != nil {
+			return err
+		}
 	default:
-		return fmt.Errorf("Unknown record type %q, expected 'stream', 'batch' or 'query'", args[0])
+		return fmt.Errorf("Unknown record type %q, expected 'stream', 'batch', 'query' or 'upload'", args[0])
 	}
 	if noWait {
 		return nil
@@ -558,6 +726,7 @@ var (
 	dvars       = defineFlags.String("vars", "", "Optional path to a JSON vars file")
 	dfile       = defineFlags.String("file", "", "Optional path to a YAML or JSON template task file. If id is given in the task file, it must match the Task id given on the command line.")
 	dnoReload   = defineFlags.Bool("no-reload", false, "Do not reload the task even if it is enabled")
+	dshadowOf   = defineFlags.String("shadow-of", "", "Optional ID of a task to shadow. A shadow task records its alerts and writes instead of sending them. Can only be set when the task is created.")
 	ddbrp       = make(dbrps, 0)
 )
 
@@ -651,6 +820,11 @@ For example:
 
 	NOTE: you must specify all 'dbrp' flags you desire if you wish to modify them.
 
+	Define a shadow of a task to try a new TICKscript against live data without alerting anyone.
+	Use 'kapacitor shadow' to compare its alerts with those of the task it shadows.
+
+		$ kapacitor define my_task_v2 -tick path/to/TICKscript -shadow-of my_task
+
 Options:
 
 `
@@ -745,6 +919,9 @@ func doDefine(args []string) error {
 
 	l := kCli.TaskLink(id)
 	task, _ := kCli.Task(l, nil)
+	if task.ID != "" && *dshadowOf != "" && *dshadowOf != task.ShadowOf {
+		return fmt.Errorf("cannot change the task %s shadows, delete and define it again", id)
+	}
 	var err error
 	if task.ID == "" {
 		if *dfile != "" {
@@ -770,6 +947,7 @@ func doDefine(args []string) error {
 				TICKscript: script,
 				Vars:       vars,
 				Status:     client.Disabled,
+				ShadowOf:   *dshadowOf,
 			}
 			_, err = kCli.CreateTask(o)
 			if err != nil {
@@ -919,6 +1097,145 @@ func doDefineTemplate(args []string) error {
 	return err
 }
 
+// DefineLibrary
+var (
+	defineLibraryFlags = flag.NewFlagSet("define-library", flag.ExitOnError)
+	dlTick             = defineLibraryFlags.String("tick", "", "Path to the TICKscript")
+)
+
+func defineLibraryUsage() {
+	var u = `Usage: kapacitor define-library <library ID> -tick <path to TICKscript>
+
+	Create or update a library.
+
+	A library is a TICKscript that only contains def and import statements.
+	Tasks and templates can use its definitions with an import statement:
+
+		import 'my_library'
+
+	NOTE: Running tasks use the updated library once they are reloaded.
+
+For example:
+
+		$ kapacitor define-library my_library -tick path/to/TICKscript
+
+Options:
+
+`
+	fmt.Fprintln(os.Stderr, u)
+	defineLibraryFlags.PrintDefaults()
+}
+
+func doDefineLibrary(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Must provide a library ID.")
+		defineLibraryFlags.Usage()
+		os.Exit(2)
+	}
+	defineLibraryFlags.Parse(args[1:])
+	id := args[0]
+
+	if *dlTick == "" {
+		fmt.Fprintln(os.Stderr, "Must provide a TICKscript.")
+		defineLibraryFlags.Usage()
+		os.Exit(2)
+	}
+	data, err := os.ReadFile(*dlTick)
+	if err != nil {
+		return err
+	}
+	script := string(data)
+
+	l := kCli.LibraryLink(id)
+	library, _ := kCli.Library(l, nil)
+	if library.ID == "" {
+		_, err = kCli.CreateLibrary(client.CreateLibraryOptions{
+			ID:         id,
+			TICKscript: script,
+		})
+	} else {
+		_, err = kCli.UpdateLibrary(
+			l,
+			client.UpdateLibraryOptions{
+				TICKscript: script,
+			},
+		)
+	}
+	return err
+}
+
+func defineScheduleUsage() {
+	var u = `Usage: kapacitor define-schedule <path to schedule file>
+
+	Create or update a schedule.
+
+	A schedule is defined via a JSON or YAML file.
+	Lambda expressions can check whether a time is within a schedule
+	using the inSchedule function, for example inSchedule('business-hours', "time").
+
+For example:
+
+	Define a schedule using the business-hours.yaml file:
+
+		$ kapacitor define-schedule business-hours.yaml
+
+	Where business-hours.yaml contains:
+
+		id: business-hours
+		timezone: Europe/Berlin
+		weekly:
+		  - days: [mon, tue, wed, thu, fri]
+		    start: "09:00"
+		    end: "17:00"
+		exclusions:
+		  - start: "2026-12-24"
+		    end: "2026-12-26"
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func doDefineSchedule(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must provide a path to a schedule file.")
+		defineScheduleUsage()
+		os.Exit(2)
+	}
+	p := args[0]
+	f, err := os.Open(p)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open schedule file %q", p)
+	}
+	defer f.Close()
+
+	var so client.ScheduleOptions
+	ext := path.Ext(p)
+	switch ext {
+	case ".yaml", ".yml":
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read schedule file %q", p)
+		}
+		if err := yaml.Unmarshal(data, &so); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal yaml schedule file %q", p)
+		}
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&so); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal json schedule file %q", p)
+		}
+	default:
+		return errors.New("invalid schedule file, must be JSON or YAML")
+	}
+
+	l := kCli.ScheduleLink(so.ID)
+	schedule, _ := kCli.Schedule(l)
+	if schedule.ID == "" {
+		_, err = kCli.CreateSchedule(so)
+	} else {
+		_, err = kCli.ReplaceSchedule(l, so)
+	}
+	return err
+}
+
 func defineTopicHandlerUsage() {
 	var u = `Usage: kapacitor define-topic-handler <path to handler spec file>
 
@@ -986,6 +1303,7 @@ var (
 	rrec        = replayFlags.Bool("rec-time", false, "If set, use the times saved in the recording instead of present times.")
 	rnowait     = replayFlags.Bool("no-wait", false, "Do not wait for the replay to finish.")
 	rid         = replayFlags.String("replay-id", "", "The ID to give to this replay. If not set a random ID is chosen.")
+	rcompare    = replayFlags.String("compare", "", "Optional path to a candidate TICKscript. If set, replay the recording to the task and the candidate and print the differences of their alerts and writes.")
 )
 
 func replayUsage() {
@@ -997,6 +1315,12 @@ The times of the data points will either be relative to now or the exact times
 in the recording if the '-rec-time' flag is set. In either case the relative times
 between the data points remains the same.
 
+If the '-compare' flag is set the recording is replayed to the task and to the
+candidate TICKscript in isolation, as fast as possible and with the times saved in the recording.
+Their alerts and writes are captured instead of handled and the differences are printed:
+alert events added or removed by the candidate or with a changed level or time, per alert ID,
+and writes added or removed by the candidate.
+
 See 'kapacitor help record' for how to create a replay.
 See 'kapacitor help define' for how to create a task.
 
@@ -1015,6 +1339,9 @@ func doReplay(args []string) error {
 		replayUsage()
 		return errors.New("must pass task ID")
 	}
+	if *rcompare != "" {
+		return doCompareReplay(*rtask, *rrecording, *rcompare)
+	}
 
 	clk := client.Fast
 	if *rreal {
@@ -1050,6 +1377,57 @@ func doReplay(args []string) error {
 	return nil
 }
 
+func doCompareReplay(task, recording, scriptPath string) error {
+	script, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read candidate TICKscript %q", scriptPath)
+	}
+	c, err := kCli.CompareReplay(client.CompareReplayOptions{
+		Task:       task,
+		Recording:  recording,
+		TICKscript: string(script),
+	})
+	if err != nil {
+		return err
+	}
+	if c.Equal {
+		fmt.Println("No differences.")
+		return nil
+	}
+
+	if len(c.Alerts) > 0 {
+		maxID := 5
+		for _, a := range c.Alerts {
+			if l := len(a.ID); l > maxID {
+				maxID = l
+			}
+		}
+		outFmt := fmt.Sprintf("%%-%dv%%-9v%%-21v%%v\n", maxID+1)
+		fmt.Printf(outFmt, "Alert", "Change", "Level", "Time")
+		for _, a := range c.Alerts {
+			for _, ch := range a.Changed {
+				fmt.Printf(outFmt, a.ID, "changed", ch.Current.Level+" -> "+ch.Candidate.Level, ch.Current.Time.Format(time.RFC3339Nano)+" -> "+ch.Candidate.Time.Format(time.RFC3339Nano))
+			}
+			for _, e := range a.Removed {
+				fmt.Printf(outFmt, a.ID, "removed", e.Level, e.Time.Format(time.RFC3339Nano))
+			}
+			for _, e := range a.Added {
+				fmt.Printf(outFmt, a.ID, "added", e.Level, e.Time.Format(time.RFC3339Nano))
+			}
+		}
+	}
+	if len(c.WritesAdded) > 0 || len(c.WritesRemoved) > 0 {
+		fmt.Println("Writes:")
+		for _, w := range c.WritesRemoved {
+			fmt.Println("-", w)
+		}
+		for _, w := range c.WritesAdded {
+			fmt.Println("+", w)
+		}
+	}
+	return nil
+}
+
 // Replay Live
 var (
 	replayLiveBatchFlags = flag.NewFlagSet("replay-live-batch", flag.ExitOnError)
@@ -1382,14 +1760,85 @@ func doReload(args []string) error {
 	return doEnable(args)
 }
 
+// Rollback
+var (
+	rollbackFlags    = flag.NewFlagSet("rollback", flag.ExitOnError)
+	rollbackTemplate = rollbackFlags.Bool("template", false, "Roll back a template instead of a task. All tasks using the template are updated.")
+)
+
+func rollbackUsage() {
+	var u = `Usage: kapacitor rollback [-template] [task ID] [revision]
+
+	Restore the definition of a task or template to one of its revisions.
+	The rollback is recorded as a new revision.
+
+	An enabled task is restarted with the restored definition and without its snapshot.
+	Use 'kapacitor show -revisions' to list the revisions of a task.
+
+For example:
+
+	Roll back a task to its second revision.
+
+		$ kapacitor rollback cpu_alert 2
+
+	Roll back a template and all tasks using it.
+
+		$ kapacitor rollback -template generic_mean_alert 4
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	rollbackFlags.PrintDefaults()
+}
+
+func doRollback(args []string) error {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Must specify an ID and a revision")
+		rollbackUsage()
+		os.Exit(2)
+	}
+	revision, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid revision %q", args[1])
+	}
+	link := kCli.TaskLink(args[0])
+	if *rollbackTemplate {
+		link = kCli.TemplateLink(args[0])
+	}
+	// Show what will change before rolling back.
+	revisions, err := kCli.ListRevisions(link)
+	if err != nil {
+		return err
+	}
+	if len(revisions) > 0 && revision != len(revisions) {
+		diff, err := kCli.DiffRevisions(link, &client.RevisionDiffOptions{From: len(revisions), To: revision})
+		if err != nil {
+			return err
+		}
+		printRevisionDiff(diff)
+	}
+	if *rollbackTemplate {
+		_, err = kCli.RollbackTemplate(link, revision)
+	} else {
+		_, err = kCli.RollbackTask(link, revision)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rolled back %s to revision %d\n", args[0], revision)
+	return nil
+}
+
 // Show
 var (
-	showFlags = flag.NewFlagSet("show", flag.ExitOnError)
-	sReplayId = showFlags.String("replay", "", "Optional replay ID. If set the task information is in the context of the running replay.")
+	showFlags      = flag.NewFlagSet("show", flag.ExitOnError)
+	sReplayId      = showFlags.String("replay", "", "Optional replay ID. If set the task information is in the context of the running replay.")
+	sShowRevisions = showFlags.Bool("revisions", false, "Show the revision history of the task. If a revision is given, show the revision and what changed from the previous one.")
 )
 
 func showUsage() {
 	var u = `Usage: kapacitor show [-replay] [task ID]
+       kapacitor show -revisions [task ID] [revision]
 
 	Show details about a specific task.
 
@@ -1400,6 +1849,9 @@ Options:
 }
 
 func doShow(args []string) error {
+	if *sShowRevisions {
+		return doShowRevisions(args)
+	}
 	if len(args) != 1 {
 		fmt.Fprintln(os.Stderr, "Must specify one task ID")
 		showUsage()
@@ -1417,6 +1869,9 @@ func doShow(args []string) error {
 	fmt.Println("ID:", t.ID)
 	fmt.Println("Error:", t.Error)
 	fmt.Println("Template:", t.TemplateID)
+	if t.ShadowOf != "" {
+		fmt.Println("Shadow Of:", t.ShadowOf)
+	}
 	fmt.Println("Type:", t.Type)
 	fmt.Println("Status:", t.Status)
 	fmt.Println("Executing:", t.Executing)
@@ -1452,6 +1907,77 @@ func doShow(args []string) error {
 	return nil
 }
 
+func doShowRevisions(args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Must specify one task ID and optionally a revision")
+		showUsage()
+		os.Exit(2)
+	}
+	link := kCli.TaskLink(args[0])
+	if len(args) == 1 {
+		revisions, err := kCli.ListRevisions(link)
+		if err != nil {
+			return err
+		}
+		outFmt := "%-10s%-25s%-20s%-12s\n"
+		fmt.Printf(outFmt, "Revision", "Created", "Author", "Rollback Of")
+		for _, r := range revisions {
+			author := r.Author
+			if author == "" {
+				author = "-"
+			}
+			rollbackOf := "-"
+			if r.RollbackOf > 0 {
+				rollbackOf = strconv.Itoa(r.RollbackOf)
+			}
+			fmt.Printf(outFmt, strconv.Itoa(r.Number), r.Created.Format(time.RFC822), author, rollbackOf)
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid revision %q", args[1])
+	}
+	r, err := kCli.Revision(link, n)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Revision:", r.Number)
+	fmt.Println("Author:", r.Author)
+	fmt.Println("Created:", r.Created.Format(time.RFC822))
+	if r.RollbackOf > 0 {
+		fmt.Println("Rollback Of:", r.RollbackOf)
+	}
+	fmt.Println("Template:", r.TemplateID)
+	fmt.Println("Databases Retention Policies:", r.DBRPs)
+	fmt.Printf("TICKscript:\n%s\n", r.TICKscript)
+	if n > 1 {
+		diff, err := kCli.DiffRevisions(link, &client.RevisionDiffOptions{From: n - 1, To: n})
+		if err != nil {
+			return err
+		}
+		printRevisionDiff(diff)
+	}
+	return nil
+}
+
+func printRevisionDiff(diff client.RevisionDiff) {
+	if diff.TICKscript == "" && diff.DBRPs == "" && diff.Vars == "" {
+		fmt.Printf("No changes from revision %d to %d\n", diff.From, diff.To)
+		return
+	}
+	if diff.TICKscript != "" {
+		fmt.Printf("TICKscript changes:\n%s\n", diff.TICKscript)
+	}
+	if diff.DBRPs != "" {
+		fmt.Printf("Databases Retention Policies changes:\n%s\n", diff.DBRPs)
+	}
+	if diff.Vars != "" {
+		fmt.Printf("Vars changes:\n%s\n", diff.Vars)
+	}
+}
+
 func varListToStr(list []client.Var) (string, error) {
 	values := make([]string, len(list))
 	for i := range list {
@@ -1483,35 +2009,115 @@ func showTemplateUsage() {
 	fmt.Fprintln(os.Stderr, u)
 }
 
-func doShowTemplate(args []string) error {
+// Show Library
+
+func showLibraryUsage() {
+	var u = `Usage: kapacitor show-library [library ID]
+
+	Show details about a specific library.
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func doShowLibrary(args []string) error {
 	if len(args) != 1 {
-		fmt.Fprintln(os.Stderr, "Must specify one template ID")
-		showTemplateUsage()
+		fmt.Fprintln(os.Stderr, "Must specify one library ID")
+		showLibraryUsage()
 		os.Exit(2)
 	}
 
-	t, err := kCli.Template(kCli.TemplateLink(args[0]), nil)
+	l, err := kCli.Library(kCli.LibraryLink(args[0]), nil)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("ID:", t.ID)
-	fmt.Println("Error:", t.Error)
-	fmt.Println("Type:", t.Type)
-	fmt.Println("Created:", t.Created.Format(time.RFC822))
-	fmt.Println("Modified:", t.Modified.Format(time.RFC822))
-	fmt.Printf("TICKscript:\n%s\n", t.TICKscript)
-	fmt.Println("Vars:")
-	varOutFmt := "%-30s%-10v%-40v%-40s\n"
-	fmt.Printf(varOutFmt, "Name", "Type", "Default Value", "Description")
-	vars := make([]string, 0, len(t.Vars))
-	for name := range t.Vars {
-		vars = append(vars, name)
+	fmt.Println("ID:", l.ID)
+	fmt.Println("Created:", l.Created.Format(time.RFC822))
+	fmt.Println("Modified:", l.Modified.Format(time.RFC822))
+	fmt.Printf("TICKscript:\n%s\n", l.TICKscript)
+	return nil
+}
+
+// Show Schedule
+
+func showScheduleUsage() {
+	var u = `Usage: kapacitor show-schedule [schedule ID]
+
+	Show details about a specific schedule.
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func doShowSchedule(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must specify one schedule ID")
+		showScheduleUsage()
+		os.Exit(2)
 	}
-	sort.Strings(vars)
-	for _, name := range vars {
-		v := t.Vars[name]
-		value := v.Value
+
+	s, err := kCli.Schedule(kCli.ScheduleLink(args[0]))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("ID:", s.ID)
+	fmt.Println("Timezone:", s.Timezone)
+	fmt.Println("Read Only:", s.ReadOnly)
+	if !s.ReadOnly {
+		fmt.Println("Created:", s.Created.Format(time.RFC822))
+		fmt.Println("Modified:", s.Modified.Format(time.RFC822))
+	}
+	fmt.Println("Weekly:")
+	if len(s.Weekly) == 0 {
+		fmt.Println("    every day")
+	}
+	for _, w := range s.Weekly {
+		days := "every day"
+		if len(w.Days) > 0 {
+			days = strings.Join(w.Days, ",")
+		}
+		fmt.Printf("    %s %s-%s\n", days, w.Start, w.End)
+	}
+	fmt.Println("Exclusions:")
+	for _, e := range s.Exclusions {
+		if e.End == "" {
+			fmt.Printf("    %s\n", e.Start)
+		} else {
+			fmt.Printf("    %s to %s\n", e.Start, e.End)
+		}
+	}
+	return nil
+}
+
+func doShowTemplate(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must specify one template ID")
+		showTemplateUsage()
+		os.Exit(2)
+	}
+
+	t, err := kCli.Template(kCli.TemplateLink(args[0]), nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("ID:", t.ID)
+	fmt.Println("Error:", t.Error)
+	fmt.Println("Type:", t.Type)
+	fmt.Println("Created:", t.Created.Format(time.RFC822))
+	fmt.Println("Modified:", t.Modified.Format(time.RFC822))
+	fmt.Printf("TICKscript:\n%s\n", t.TICKscript)
+	fmt.Println("Vars:")
+	varOutFmt := "%-30s%-10v%-40v%-40s\n"
+	fmt.Printf(varOutFmt, "Name", "Type", "Default Value", "Description")
+	vars := make([]string, 0, len(t.Vars))
+	for name := range t.Vars {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	for _, name := range vars {
+		v := t.Vars[name]
+		value := v.Value
 		if v.Value == nil {
 			value = "<required>"
 		}
@@ -1564,14 +2170,95 @@ func doShowTopicHandler(args []string) error {
 	return nil
 }
 
+// Dead Letters
+
+func deadLettersUsage() {
+	var u = `Usage: kapacitor dead-letters (list|replay|purge) <topic> <handler>
+
+	Manage the events that an alert handler failed to handle.
+
+	Handlers with a retry policy dead-letter the events they failed to handle
+	after all attempts, or that were dropped because the handler fell behind.
+
+Commands:
+
+	list <topic> <handler>     List the dead-lettered events of the handler.
+	replay <topic> <handler>   Pass the dead-lettered events to the handler again.
+	purge <topic> <handler>    Delete the dead-lettered events of the handler.
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func doDeadLetters(args []string) error {
+	if len(args) != 3 {
+		fmt.Fprintln(os.Stderr, "Must specify a command, topic and handler")
+		deadLettersUsage()
+		os.Exit(2)
+	}
+	link := kCli.TopicHandlerDeadLettersLink(args[1], args[2])
+	switch args[0] {
+	case "list":
+		return doDeadLettersList(link)
+	case "replay":
+		n, err := kCli.ReplayTopicHandlerDeadLetters(link)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Replayed %d events\n", n)
+	case "purge":
+		return kCli.PurgeTopicHandlerDeadLetters(link)
+	default:
+		fmt.Fprintln(os.Stderr, "Unknown dead-letters command", args[0])
+		deadLettersUsage()
+		os.Exit(2)
+	}
+	return nil
+}
+
+func doDeadLettersList(link client.Link) error {
+	d, err := kCli.TopicHandlerDeadLetters(link)
+	if err != nil {
+		return err
+	}
+	maxEvent := 5   // len("Event")
+	maxMessage := 7 // len("Message")
+	for _, l := range d.DeadLetters {
+		if n := len(l.Event); n > maxEvent {
+			maxEvent = n
+		}
+		if n := len(l.Message); n > maxMessage {
+			maxMessage = n
+		}
+	}
+	outFmt := fmt.Sprintf("%%-37v%%-%dv%%-9v%%-%dv%%-9v%%-23v%%v\n", maxEvent+1, maxMessage+1)
+	fmt.Fprintf(os.Stdout, outFmt, "ID", "Event", "Level", "Message", "Attempts", "Date", "Error")
+	for _, l := range d.DeadLetters {
+		fmt.Fprintf(os.Stdout, outFmt, l.ID, l.Event, l.Level, l.Message, l.Attempts, l.Time.Local().Format(time.RFC822), l.Error)
+	}
+	return nil
+}
+
 // Show Topic
 
+var (
+	showTopicFlags = flag.NewFlagSet("show-topic", flag.ExitOnError)
+	stHistory      = showTopicFlags.Bool("history", false, "Show the history of state transitions of the events of the topic.")
+	stSince        = showTopicFlags.Duration("since", 24*time.Hour, "How far back to show the history.")
+	stMinLevel     = showTopicFlags.String("min-level", "OK", "Only show history with at least this level.")
+)
+
 func showTopicUsage() {
-	var u = `Usage: kapacitor show-topic [topic ID]
+	var u = `Usage: kapacitor show-topic [-history] [-since duration] [-min-level level] [topic ID] [event ID...]
 
 	Show details about a specific topic.
+
+	With -history the state transitions of the listed events are shown,
+	or of all events of the topic if no events are listed.
+
+Options:
 `
 	fmt.Fprintln(os.Stderr, u)
+	showTopicFlags.PrintDefaults()
 }
 
 type topicEvents []client.TopicEvent
@@ -1581,7 +2268,7 @@ func (t topicEvents) Less(i int, j int) bool { return t[i].State.Time.Before(t[j
 func (t topicEvents) Swap(i int, j int)      { t[i], t[j] = t[j], t[i] }
 
 func doShowTopic(args []string) error {
-	if len(args) != 1 {
+	if len(args) < 1 || (len(args) > 1 && !*stHistory) {
 		fmt.Fprintln(os.Stderr, "Must specify one topic ID")
 		showTopicUsage()
 		os.Exit(2)
@@ -1627,6 +2314,311 @@ func doShowTopic(args []string) error {
 	for _, e := range te.Events {
 		fmt.Printf(outFmt, e.ID, e.State.Level, e.State.Message, e.State.Time.Local().Format(time.RFC822))
 	}
+	if !*stHistory {
+		return nil
+	}
+
+	eventIDs := args[1:]
+	if len(eventIDs) == 0 {
+		for _, e := range te.Events {
+			eventIDs = append(eventIDs, e.ID)
+		}
+	}
+	return showTopicHistory(topic.ID, eventIDs)
+}
+
+type historyEntry struct {
+	Event string
+	client.EventHistoryEntry
+}
+
+func showTopicHistory(topic string, eventIDs []string) error {
+	opts := &client.TopicEventHistoryOptions{
+		Start:    time.Now().Add(-*stSince),
+		MinLevel: *stMinLevel,
+	}
+	var entries []historyEntry
+	maxEvent := 5   // len("Event")
+	maxMessage := 7 // len("Message")
+	for _, id := range eventIDs {
+		h, err := kCli.TopicEventHistory(kCli.TopicEventHistoryLink(topic, id), opts)
+		if err != nil {
+			return err
+		}
+		for _, e := range h.Entries {
+			entries = append(entries, historyEntry{Event: id, EventHistoryEntry: e})
+			if l := len(id); l > maxEvent {
+				maxEvent = l
+			}
+			if l := len(e.Message); l > maxMessage {
+				maxMessage = l
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	outFmt := fmt.Sprintf("%%-%ds%%-9s%%-9s%%-%ds%%-23s\n", maxEvent+1, maxMessage+1)
+	fmt.Println("History:")
+	fmt.Printf(outFmt, "Event", "Level", "Previous", "Message", "Date")
+	for _, e := range entries {
+		fmt.Printf(outFmt, e.Event, e.Level, e.PreviousLevel, e.Message, e.Time.Local().Format(time.RFC822))
+	}
+	return nil
+}
+
+// Silence
+
+var (
+	silenceCreateFlags = flag.NewFlagSet("silence-create", flag.ExitOnError)
+	scID               = silenceCreateFlags.String("id", "", "The ID to give to the silence. If not set a random ID is chosen.")
+	scDuration         = silenceCreateFlags.Duration("duration", time.Hour, "How long the silence lasts.")
+	scAuthor           = silenceCreateFlags.String("author", os.Getenv("USER"), "The author of the silence.")
+	scComment          = silenceCreateFlags.String("comment", "", "A comment explaining the silence.")
+)
+
+func silenceUsage() {
+	var u = `Usage: kapacitor silence (create|list|delete) [args]
+
+	Manage silences of an alert topic.
+
+	Events of a topic that match a silence are recorded but not sent to the handlers of the topic until the silence expires.
+
+Commands:
+
+	create [options] <topic> <matcher>...   Silence events of the topic that match all matchers.
+	list <topic>                            List the active silences of the topic.
+	delete <topic> <ID>...                  Delete silences of the topic.
+
+Matchers:
+
+	id=<value>       Match the ID of the event.
+	level=<value>    Match the level of the event.
+	<tag>=<value>    Match the value of a tag of the event.
+
+	Use '=~' instead of '=' to match an anchored regular expression.
+
+For example:
+
+	Silence all events for the host serverA in the topic 'cpu' for two hours:
+
+		$ kapacitor silence create -duration 2h -comment 'maintenance' cpu host=serverA
+
+	Silence all WARNING events of web servers:
+
+		$ kapacitor silence create cpu level=WARNING 'host=~web.*'
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func silenceCreateUsage() {
+	var u = `Usage: kapacitor silence create [options] <topic> <matcher>...
+
+	Silence events of the topic that match all matchers, see 'kapacitor help silence'.
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	silenceCreateFlags.PrintDefaults()
+}
+
+func doSilence(args []string) error {
+	if len(args) == 0 {
+		silenceUsage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "create":
+		silenceCreateFlags.Parse(args[1:])
+		return doSilenceCreate(silenceCreateFlags.Args())
+	case "list":
+		return doSilenceList(args[1:])
+	case "delete":
+		return doSilenceDelete(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, "Unknown silence command", args[0])
+		silenceUsage()
+		os.Exit(2)
+	}
+	return nil
+}
+
+// parseSilenceMatcher parses a matcher of the form name=value or name=~regex.
+func parseSilenceMatcher(s string) (client.SilenceMatcher, error) {
+	var m client.SilenceMatcher
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return m, fmt.Errorf("invalid matcher %q, must be of the form name=value", s)
+	}
+	name, value := s[:i], s[i+1:]
+	if strings.HasPrefix(value, "~") {
+		m.Regex = true
+		value = value[1:]
+	}
+	m.Value = value
+	switch name {
+	case "id":
+		m.Type = "id"
+	case "level":
+		m.Type = "level"
+	default:
+		m.Type = "tag"
+		m.Tag = name
+	}
+	return m, nil
+}
+
+func doSilenceCreate(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Must specify a topic and at least one matcher")
+		silenceCreateUsage()
+		os.Exit(2)
+	}
+	matchers := make([]client.SilenceMatcher, len(args)-1)
+	for i, arg := range args[1:] {
+		m, err := parseSilenceMatcher(arg)
+		if err != nil {
+			return err
+		}
+		matchers[i] = m
+	}
+	silence, err := kCli.CreateTopicSilence(kCli.TopicSilencesLink(args[0]), client.TopicSilenceOptions{
+		ID:       *scID,
+		Matchers: matchers,
+		Expires:  time.Now().Add(*scDuration),
+		Author:   *scAuthor,
+		Comment:  *scComment,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(silence.ID)
+	return nil
+}
+
+func silenceMatchersString(matchers []client.SilenceMatcher) string {
+	strs := make([]string, len(matchers))
+	for i, m := range matchers {
+		name := m.Type
+		if m.Type == "tag" {
+			name = m.Tag
+		}
+		op := "="
+		if m.Regex {
+			op = "=~"
+		}
+		strs[i] = name + op + m.Value
+	}
+	return strings.Join(strs, " ")
+}
+
+func doSilenceList(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must specify one topic")
+		silenceUsage()
+		os.Exit(2)
+	}
+	silences, err := kCli.ListTopicSilences(kCli.TopicSilencesLink(args[0]))
+	if err != nil {
+		return err
+	}
+	maxID := 2       // len("ID")
+	maxMatchers := 8 // len("Matchers")
+	maxAuthor := 6   // len("Author")
+	for _, s := range silences.Silences {
+		if l := len(s.ID); l > maxID {
+			maxID = l
+		}
+		if l := len(silenceMatchersString(s.Matchers)); l > maxMatchers {
+			maxMatchers = l
+		}
+		if l := len(s.Author); l > maxAuthor {
+			maxAuthor = l
+		}
+	}
+	outFmt := fmt.Sprintf("%%-%dv%%-%dv%%-%dv%%-23v%%v\n", maxID+1, maxMatchers+1, maxAuthor+1)
+	fmt.Fprintf(os.Stdout, outFmt, "ID", "Matchers", "Author", "Expires", "Comment")
+	for _, s := range silences.Silences {
+		fmt.Fprintf(os.Stdout, outFmt, s.ID, silenceMatchersString(s.Matchers), s.Author, s.Expires.Local().Format(time.RFC822), s.Comment)
+	}
+	return nil
+}
+
+func doSilenceDelete(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Must specify a topic and at least one silence ID")
+		silenceUsage()
+		os.Exit(2)
+	}
+	for _, id := range args[1:] {
+		if err := kCli.DeleteTopicSilence(kCli.TopicSilenceLink(args[0], id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ack
+
+var (
+	ackFlags    = flag.NewFlagSet("ack", flag.ExitOnError)
+	ackDuration = ackFlags.Duration("duration", 0, "Optional duration after which the acknowledgement expires. By default it lasts until the level of the event changes.")
+	ackAuthor   = ackFlags.String("author", os.Getenv("USER"), "The author of the acknowledgement.")
+	ackComment  = ackFlags.String("comment", "", "A comment for the acknowledgement.")
+	ackRemove   = ackFlags.Bool("remove", false, "Remove the acknowledgement of the events instead.")
+)
+
+func ackUsage() {
+	var u = `Usage: kapacitor ack [options] <topic> <event ID>...
+
+	Acknowledge events of an alert topic at their current level.
+
+	An acknowledged event is recorded but not sent to the handlers of the topic while it remains at the acknowledged level.
+	Once the level of the event changes the acknowledgement is removed.
+
+For example:
+
+	Acknowledge the event 'cpu:host=serverA' in the topic 'cpu':
+
+		$ kapacitor ack -comment 'looking into it' cpu cpu:host=serverA
+
+	Remove the acknowledgement:
+
+		$ kapacitor ack -remove cpu cpu:host=serverA
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	ackFlags.PrintDefaults()
+}
+
+func doAck(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Must specify a topic and at least one event ID")
+		ackUsage()
+		os.Exit(2)
+	}
+	topic := args[0]
+	for _, event := range args[1:] {
+		link := kCli.TopicEventAckLink(topic, event)
+		if *ackRemove {
+			if err := kCli.UnacknowledgeTopicEvent(link); err != nil {
+				return err
+			}
+			continue
+		}
+		opt := client.AcknowledgeEventOptions{
+			Author:  *ackAuthor,
+			Comment: *ackComment,
+		}
+		if *ackDuration > 0 {
+			opt.Expires = time.Now().Add(*ackDuration)
+		}
+		if _, err := kCli.AcknowledgeTopicEvent(link, opt); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1640,9 +2632,9 @@ func doFluxTasks(url string, skipSSL bool) func([]string) error {
 // List
 
 func listUsage() {
-	var u = `Usage: kapacitor list (tasks|templates|recordings|replays|topics|topic-handlers|service-tests) [ID or pattern]...
+	var u = `Usage: kapacitor list (tasks|templates|libraries|schedules|recordings|replays|topics|topic-handlers|service-tests) [ID or pattern]...
 
-	List tasks, templates, recordings, replays, topics or handlers and their current state.
+	List tasks, templates, libraries, schedules, recordings, replays, topics or handlers and their current state.
 
 	If no ID or pattern is given then all items will be listed.
 
@@ -1674,6 +2666,18 @@ func (t TemplateList) Len() int           { return len(t) }
 func (t TemplateList) Less(i, j int) bool { return t[i].ID < t[j].ID }
 func (t TemplateList) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
 
+type LibraryList []client.Library
+
+func (l LibraryList) Len() int           { return len(l) }
+func (l LibraryList) Less(i, j int) bool { return l[i].ID < l[j].ID }
+func (l LibraryList) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+
+type ScheduleList []client.Schedule
+
+func (s ScheduleList) Len() int           { return len(s) }
+func (s ScheduleList) Less(i, j int) bool { return s[i].ID < s[j].ID }
+func (s ScheduleList) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 func doList(args []string) error {
 	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, "Must specify 'tasks', 'recordings', 'replays', 'topics', or 'topic-handlers'")
@@ -1763,6 +2767,70 @@ func doList(args []string) error {
 			sort.Strings(vars)
 			fmt.Fprintf(os.Stdout, outFmt, t.ID, t.Type, strings.Join(vars, ","))
 		}
+	case "libraries":
+		maxID := 2 // len("ID")
+		var allLibraries LibraryList
+		for _, pattern := range patterns {
+			offset := 0
+			for {
+				libraries, err := kCli.ListLibraries(&client.ListLibrariesOptions{
+					Pattern: pattern,
+					Offset:  offset,
+					Limit:   limit,
+				})
+				if err != nil {
+					return err
+				}
+				allLibraries = append(allLibraries, libraries...)
+				for _, l := range libraries {
+					if n := len(l.ID); n > maxID {
+						maxID = n
+					}
+				}
+				if len(libraries) != limit {
+					break
+				}
+				offset += limit
+			}
+		}
+		outFmt := fmt.Sprintf("%%-%ds%%v\n", maxID+1)
+		fmt.Fprintf(os.Stdout, outFmt, "ID", "Modified")
+		sort.Sort(allLibraries)
+		for _, l := range allLibraries {
+			fmt.Fprintf(os.Stdout, outFmt, l.ID, l.Modified.Format(time.RFC822))
+		}
+	case "schedules":
+		maxID := 2 // len("ID")
+		var allSchedules ScheduleList
+		for _, pattern := range patterns {
+			offset := 0
+			for {
+				schedules, err := kCli.ListSchedules(&client.ListSchedulesOptions{
+					Pattern: pattern,
+					Offset:  offset,
+					Limit:   limit,
+				})
+				if err != nil {
+					return err
+				}
+				allSchedules = append(allSchedules, schedules.Schedules...)
+				for _, s := range schedules.Schedules {
+					if n := len(s.ID); n > maxID {
+						maxID = n
+					}
+				}
+				if len(schedules.Schedules) != limit {
+					break
+				}
+				offset += limit
+			}
+		}
+		outFmt := fmt.Sprintf("%%-%ds%%-20v%%v\n", maxID+1)
+		fmt.Fprintf(os.Stdout, outFmt, "ID", "Timezone", "Read Only")
+		sort.Sort(allSchedules)
+		for _, s := range allSchedules {
+			fmt.Fprintf(os.Stdout, outFmt, s.ID, s.Timezone, s.ReadOnly)
+		}
 	case "recordings":
 		maxID := 2 // len("ID")
 		// The recordings are returned in sorted order already, no need to sort them here.
@@ -1937,7 +3005,7 @@ func doList(args []string) error {
 			fmt.Fprintf(os.Stdout, outFmt, t.ID, t.Level, t.Collected)
 		}
 	default:
-		return fmt.Errorf("cannot list '%s' did you mean 'tasks', 'recordings', 'replays', 'topics', 'topic-handlers' or 'service-tests'?", kind)
+		return fmt.Errorf("cannot list '%s' did you mean 'tasks', 'templates', 'libraries', 'schedules', 'recordings', 'replays', 'topics', 'topic-handlers' or 'service-tests'?", kind)
 	}
 	return nil
 
@@ -1945,9 +3013,9 @@ func doList(args []string) error {
 
 // Delete
 func deleteUsage() {
-	var u = `Usage: kapacitor delete (tasks|templates|recordings|replays|topics|topic-handlers) [ID or pattern]...
+	var u = `Usage: kapacitor delete (tasks|templates|libraries|schedules|recordings|replays|topics|topic-handlers) [ID or pattern]...
 
-	Delete a tasks, templates, recordings, replays, topics or handlers.
+	Delete a tasks, templates, libraries, schedules, recordings, replays, topics or handlers.
 
 	If a task is enabled it will be disabled and then deleted.
 
@@ -2030,6 +3098,48 @@ func doDelete(args []string) error {
 				}
 			}
 		}
+	case "libraries":
+		for _, pattern := range args[1:] {
+			for {
+				libraries, err := kCli.ListLibraries(&client.ListLibrariesOptions{
+					Pattern: pattern,
+					Limit:   limit,
+				})
+				if err != nil {
+					return err
+				}
+				for _, library := range libraries {
+					err := kCli.DeleteLibrary(library.Link)
+					if err != nil {
+						return err
+					}
+				}
+				if len(libraries) != limit {
+					break
+				}
+			}
+		}
+	case "schedules":
+		for _, pattern := range args[1:] {
+			for {
+				schedules, err := kCli.ListSchedules(&client.ListSchedulesOptions{
+					Pattern: pattern,
+					Limit:   limit,
+				})
+				if err != nil {
+					return err
+				}
+				for _, schedule := range schedules.Schedules {
+					err := kCli.DeleteSchedule(schedule.Link)
+					if err != nil {
+						return err
+					}
+				}
+				if len(schedules.Schedules) != limit {
+					break
+				}
+			}
+		}
 	case "recordings":
 		for _, pattern := range args[1:] {
 			for {
@@ -2106,7 +3216,7 @@ func doDelete(args []string) error {
 			}
 		}
 	default:
-		return fmt.Errorf("cannot delete '%s' did you mean 'tasks', 'templates', 'recordings', 'replays', 'topics' or 'topic-handlers'?", kind)
+		return fmt.Errorf("cannot delete '%s' did you mean 'tasks', 'templates', 'libraries', 'schedules', 'recordings', 'replays', 'topics' or 'topic-handlers'?", kind)
 	}
 	return nil
 }
@@ -2304,6 +3414,279 @@ func doServiceTest(args []string) error {
 	return nil
 }
 
+// Blob
+var (
+	blobCreateFlags = flag.NewFlagSet("blob-create", flag.ExitOnError)
+	bcTag           = blobCreateFlags.String("tag", "", "Optional tag name to associate with the new blob.")
+)
+
+func blobUsage() {
+	var u = `Usage: kapacitor blob (create|get|tag|show|list|delete) [args]
+
+	Manage the content addressable blob store.
+
+	Blobs are immutable and identified by the sha256 sum of their content.
+	Tags are named references to blobs, the history of blobs a tag referred to is preserved.
+	Wherever a blob can be referenced either its ID or a tag name may be used.
+
+Commands:
+
+	create [-tag name] <file>     Create a blob from the content of file, use '-' to read from STDIN.
+	get <ID or tag> [file]        Write the content of a blob to file or STDOUT.
+	tag <tag> <blob ID>           Tag a blob, updating any existing tag with the same name.
+	show <ID or tag>              Display details about a blob or a tag and its history.
+	list (blobs|tags) [pattern]   List blobs or tags.
+	delete (blobs|tags) <ID>...   Delete blobs or tags. Blobs referenced by a tag cannot be deleted.
+
+For example:
+
+	Store a trained model and tag it:
+
+		$ kapacitor blob create -tag my_model ./model.bin
+
+	Retrieve the latest version of the model:
+
+		$ kapacitor blob get my_model ./model.bin
+`
+	fmt.Fprintln(os.Stderr, u)
+}
+
+func blobCreateUsage() {
+	var u = `Usage: kapacitor blob create [-tag name] <file>
+
+	Create a blob from the content of file, use '-' to read from STDIN.
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	blobCreateFlags.PrintDefaults()
+}
+
+// isBlobID reports whether ref is a blob ID as opposed to a tag name.
+func isBlobID(ref string) bool {
+	if len(ref) != 64 {
+		return false
+	}
+	for _, r := range ref {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func doBlob(args []string) error {
+	if len(args) == 0 {
+		blobUsage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "create":
+		blobCreateFlags.Parse(args[1:])
+		return doBlobCreate(blobCreateFlags.Args())
+	case "get":
+		return doBlobGet(args[1:])
+	case "tag":
+		return doBlobTag(args[1:])
+	case "show":
+		return doBlobShow(args[1:])
+	case "list":
+		return doBlobList(args[1:])
+	case "delete":
+		return doBlobDelete(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, "Unknown blob command", args[0])
+		blobUsage()
+		os.Exit(2)
+	}
+	return nil
+}
+
+func doBlobCreate(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must specify one file")
+		blobCreateUsage()
+		os.Exit(2)
+	}
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to open blob file")
+		}
+		defer f.Close()
+		r = f
+	}
+	blob, err := kCli.CreateBlob(r, &client.CreateBlobOptions{Tag: *bcTag})
+	if err != nil {
+		return err
+	}
+	fmt.Println(blob.ID)
+	return nil
+}
+
+func doBlobGet(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, "Must specify a blob ID or tag and an optional output file")
+		blobUsage()
+		os.Exit(2)
+	}
+	var link client.Link
+	if ref := args[0]; isBlobID(ref) {
+		b, err := kCli.Blob(kCli.BlobLink(ref))
+		if err != nil {
+			return err
+		}
+		link = b.DataLink
+	} else {
+		t, err := kCli.BlobTag(kCli.BlobTagLink(ref))
+		if err != nil {
+			return err
+		}
+		link = t.DataLink
+	}
+	data, err := kCli.BlobData(link)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	var w io.Writer = os.Stdout
+	if len(args) == 2 && args[1] != "-" {
+		f, err := os.Create(args[1])
+		if err != nil {
+			return errors.Wrap(err, "failed to create output file")
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err = io.Copy(w, data)
+	return errors.Wrap(err, "failed to read blob data")
+}
+
+func doBlobTag(args []string) error {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Must specify a tag name and a blob ID")
+		blobUsage()
+		os.Exit(2)
+	}
+	_, err := kCli.TagBlob(kCli.BlobTagLink(args[0]), client.BlobTagOptions{Blob: args[1]})
+	return err
+}
+
+func doBlobShow(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Must specify one blob ID or tag")
+		blobUsage()
+		os.Exit(2)
+	}
+	if ref := args[0]; isBlobID(ref) {
+		b, err := kCli.Blob(kCli.BlobLink(ref))
+		if err != nil {
+			return err
+		}
+		fmt.Println("ID:", b.ID)
+		fmt.Println("Size:", humanize.Bytes(uint64(b.Size)))
+		fmt.Println("Created:", b.Created.Local().Format(time.RFC822))
+		return nil
+	}
+	t, err := kCli.BlobTag(kCli.BlobTagLink(args[0]))
+	if err != nil {
+		return err
+	}
+	fmt.Println("Tag:", t.Name)
+	fmt.Println("Blob:", t.Blob)
+	fmt.Println("Modified:", t.Modified.Local().Format(time.RFC822))
+	fmt.Println("History:")
+	outFmt := "%-66s%-23s\n"
+	fmt.Printf(outFmt, "Blob", "Date")
+	for i := len(t.History) - 1; i >= 0; i-- {
+		e := t.History[i]
+		fmt.Printf(outFmt, e.Blob, e.Date.Local().Format(time.RFC822))
+	}
+	return nil
+}
+
+func doBlobList(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, "Must specify 'blobs' or 'tags'")
+		blobUsage()
+		os.Exit(2)
+	}
+	pattern := ""
+	if len(args) == 2 {
+		pattern = args[1]
+	}
+	limit := 100
+	switch args[0] {
+	case "blobs":
+		outFmt := "%-66s%-10s%-23s\n"
+		fmt.Printf(outFmt, "ID", "Size", "Created")
+		offset := 0
+		for {
+			blobs, err := kCli.ListBlobs(&client.ListBlobsOptions{
+				Pattern: pattern,
+				Offset:  offset,
+				Limit:   limit,
+			})
+			if err != nil {
+				return err
+			}
+			for _, b := range blobs.Blobs {
+				fmt.Printf(outFmt, b.ID, humanize.Bytes(uint64(b.Size)), b.Created.Local().Format(time.RFC822))
+			}
+			if len(blobs.Blobs) != limit {
+				break
+			}
+			offset += limit
+		}
+	case "tags":
+		tags, err := kCli.ListBlobTags(&client.ListBlobTagsOptions{Pattern: pattern, Limit: -1})
+		if err != nil {
+			return err
+		}
+		maxName := 3 // len("Tag")
+		for _, t := range tags.Tags {
+			if l := len(t.Name); l > maxName {
+				maxName = l
+			}
+		}
+		outFmt := fmt.Sprintf("%%-%ds%%-66s%%-23s\n", maxName+1)
+		fmt.Printf(outFmt, "Tag", "Blob", "Modified")
+		for _, t := range tags.Tags {
+			fmt.Printf(outFmt, t.Name, t.Blob, t.Modified.Local().Format(time.RFC822))
+		}
+	default:
+		return fmt.Errorf("cannot list '%s' did you mean 'blobs' or 'tags'?", args[0])
+	}
+	return nil
+}
+
+func doBlobDelete(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Must specify 'blobs' or 'tags' and at least one ID")
+		blobUsage()
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "blobs":
+		for _, id := range args[1:] {
+			if err := kCli.DeleteBlob(kCli.BlobLink(id)); err != nil {
+				return err
+			}
+		}
+	case "tags":
+		for _, name := range args[1:] {
+			if err := kCli.DeleteBlobTag(kCli.BlobTagLink(name)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot delete '%s' did you mean 'blobs' or 'tags'?", args[0])
+	}
+	return nil
+}
+
 // Backup
 func backupUsage() {
 	var u = `Usage: kapacitor backup <output file>
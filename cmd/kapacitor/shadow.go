@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/influxdata/kapacitor/client/v1"
+	"github.com/pkg/errors"
+)
+
+var (
+	shadowFlags = flag.NewFlagSet("shadow", flag.ExitOnError)
+	shadowStart = shadowFlags.String("start", "", "Optional start of the time range in RFC3339 format.")
+	shadowStop  = shadowFlags.String("stop", "", "Optional end of the time range in RFC3339 format.")
+	shadowPast  = shadowFlags.Duration("past", 0, "Optional duration of the time range ending now, instead of -start and -stop.")
+	shadowLog   = shadowFlags.Bool("log", false, "List the alerts and writes the task recorded instead of comparing alerts.")
+)
+
+func init() {
+	shadowFlags.Usage = shadowUsage
+}
+
+func shadowUsage() {
+	var u = `Usage: kapacitor shadow <task ID> [-start <time>] [-stop <time>] [-past <duration>] [-log]
+
+	Compare the alerts of a shadow task with those of the task it shadows.
+
+	A shadow task is defined with 'kapacitor define -shadow-of'. It processes the same data
+	as the task it shadows but records its alerts, InfluxDB writes, HTTP posts, loopback
+	writes and autoscale events instead of sending them.
+	The alerts of the shadowed task are recorded while a shadow of it is executing.
+
+	Alerts are compared by ID. They match if both tasks changed them to the same levels.
+
+For example:
+
+	Compare the alerts of the past day.
+
+		$ kapacitor shadow cpu_alert_v2 -past 24h
+
+	List everything the shadow task recorded.
+
+		$ kapacitor shadow cpu_alert_v2 -log
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	shadowFlags.PrintDefaults()
+}
+
+// parseShadowArgs parses the task ID and the flags, which may be given before or after the task ID.
+func parseShadowArgs(args []string) (string, *client.ShadowOptions, error) {
+	var task string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		task = args[0]
+		args = args[1:]
+	}
+	shadowFlags.Parse(args)
+	if task == "" && shadowFlags.NArg() > 0 {
+		task = shadowFlags.Arg(0)
+	}
+	if task == "" {
+		return "", nil, errors.New("must provide task ID")
+	}
+	opt := new(client.ShadowOptions)
+	if *shadowPast > 0 {
+		opt.Start = time.Now().Add(-*shadowPast)
+	}
+	if *shadowStart != "" {
+		start, err := time.Parse(time.RFC3339Nano, *shadowStart)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "invalid start time")
+		}
+		opt.Start = start
+	}
+	if *shadowStop != "" {
+		stop, err := time.Parse(time.RFC3339Nano, *shadowStop)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "invalid stop time")
+		}
+		opt.Stop = stop
+	}
+	return task, opt, nil
+}
+
+func doShadow(args []string) error {
+	task, opt, err := parseShadowArgs(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		shadowUsage()
+		os.Exit(2)
+	}
+	link := kCli.TaskLink(task)
+
+	if *shadowLog {
+		l, err := kCli.ShadowLog(link, opt)
+		if err != nil {
+			return err
+		}
+		outFmt := "%-31s%-11s%-16s%s\n"
+		fmt.Printf(outFmt, "Time", "Type", "Node", "Details")
+		for _, r := range l.Records {
+			fmt.Printf(outFmt, r.Time.Format(time.RFC3339Nano), r.Type, r.Node, shadowRecordDetails(r))
+		}
+		return nil
+	}
+
+	c, err := kCli.CompareShadow(link, opt)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Task %s shadows %s\n", c.Task, c.ShadowOf)
+	fmt.Printf("Match: %d Different: %d Primary only: %d Shadow only: %d\n",
+		c.Summary.Match, c.Summary.Different, c.Summary.PrimaryOnly, c.Summary.ShadowOnly)
+	if len(c.Alerts) == 0 {
+		return nil
+	}
+	maxID := 2
+	for _, a := range c.Alerts {
+		if l := len(a.ID); l > maxID {
+			maxID = l
+		}
+	}
+	outFmt := fmt.Sprintf("%%-%ds%%-14s%%-30s%%s\n", maxID+1)
+	fmt.Println()
+	fmt.Printf(outFmt, "ID", "Status", "Primary", "Shadow")
+	for _, a := range c.Alerts {
+		fmt.Printf(outFmt, a.ID, a.Status, shadowLevels(a.Primary), shadowLevels(a.Shadow))
+	}
+	return nil
+}
+
+func shadowLevels(events []client.ShadowAlertEvent) string {
+	levels := make([]string, len(events))
+	for i, e := range events {
+		levels[i] = e.Level
+	}
+	return strings.Join(levels, ",")
+}
+
+func shadowRecordDetails(r client.ShadowRecord) string {
+	switch r.Type {
+	case "alert":
+		return fmt.Sprintf("%s %s %s", r.ID, r.Level, r.Message)
+	case "httppost":
+		return fmt.Sprintf("%s %d points", r.URL, r.Points)
+	case "autoscale":
+		return fmt.Sprintf("%s %s", r.ID, r.Message)
+	default:
+		return fmt.Sprintf("%q.%q %s %d points", r.Database, r.RetentionPolicy, r.Measurement, r.Points)
+	}
+}
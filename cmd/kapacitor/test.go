@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/kapacitor/client/v1"
+	"github.com/pkg/errors"
+)
+
+var (
+	testFlags = flag.NewFlagSet("test", flag.ExitOnError)
+	testJUnit = testFlags.String("junit", "", "Optional path of a file to write a JUnit XML report to.")
+)
+
+func init() {
+	testFlags.Usage = testUsage
+}
+
+func testUsage() {
+	var u = `Usage: kapacitor test [options] <path to test file>...
+
+	Run TICKscript unit tests.
+
+	Each test runs its TICKscript in isolation on the Kapacitor server.
+	The input data is replayed into the task as fast as possible and the
+	alerts, httpOut results and influxDBOut writes of the task are
+	checked against the expectations of the test. Nothing is sent to
+	alert handlers or written to InfluxDB.
+
+	A test is defined via a JSON or YAML file.
+	The TICKscript and stream data can be given inline via the script and
+	data keys, or read from the files given by the script-file and
+	data-file keys relative to the test file.
+	Stream data is line protocol with timestamps in the given precision.
+	Batch data is a list of batches for each query of the task.
+
+	Expectations that are not given are not checked,
+	an empty list expects no alerts or writes.
+
+	The command fails if any test fails.
+
+For example:
+
+	Run all tests in the tests directory and write a JUnit report:
+
+		$ kapacitor test -junit report.xml tests/*.yaml
+
+	Where tests/cpu_alert.yaml contains:
+
+		name: cpu_alert
+		type: stream
+		dbrps:
+		  - db: telegraf
+		    rp: autogen
+		script-file: ../cpu_alert.tick
+		precision: s
+		data: |
+		  cpu,host=serverA usage_idle=50 0
+		  cpu,host=serverA usage_idle=5 10
+		expect:
+		  alerts:
+		    - id: cpu:host=serverA
+		      level: CRITICAL
+		      time: 1970-01-01T00:00:10Z
+		  writes: []
+
+Options:
+`
+	fmt.Fprintln(os.Stderr, u)
+	testFlags.PrintDefaults()
+}
+
+// taskTestFile is the on disk format of a task test.
+type taskTestFile struct {
+	client.TaskTestOptions
+	ScriptFile string `json:"script-file"`
+	DataFile   string `json:"data-file"`
+}
+
+func readTaskTest(p string) (client.TaskTestOptions, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return client.TaskTestOptions{}, errors.Wrapf(err, "failed to read test file %q", p)
+	}
+	switch filepath.Ext(p) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return client.TaskTestOptions{}, fmt.Errorf("invalid test file %q, must be JSON or YAML", p)
+	}
+	var tf taskTestFile
+	// JSON is valid YAML
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return client.TaskTestOptions{}, errors.Wrapf(err, "failed to unmarshal test file %q", p)
+	}
+	opt := tf.TaskTestOptions
+	if opt.Name == "" {
+		base := filepath.Base(p)
+		opt.Name = base[:len(base)-len(filepath.Ext(base))]
+	}
+	dir := filepath.Dir(p)
+	if tf.ScriptFile != "" {
+		if opt.TICKscript != "" {
+			return client.TaskTestOptions{}, fmt.Errorf("test file %q defines both script and script-file", p)
+		}
+		script, err := os.ReadFile(filepath.Join(dir, tf.ScriptFile))
+		if err != nil {
+			return client.TaskTestOptions{}, errors.Wrapf(err, "failed to read TICKscript of test %q", opt.Name)
+		}
+		opt.TICKscript = string(script)
+	}
+	if tf.DataFile != "" {
+		if opt.Data != "" {
+			return client.TaskTestOptions{}, fmt.Errorf("test file %q defines both data and data-file", p)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, tf.DataFile))
+		if err != nil {
+			return client.TaskTestOptions{}, errors.Wrapf(err, "failed to read data of test %q", opt.Name)
+		}
+		opt.Data = string(data)
+	}
+	return opt, nil
+}
+
+func doTest(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Must provide at least one test file.")
+		testUsage()
+		os.Exit(2)
+	}
+
+	suite := junitTestSuite{
+		Name: "kapacitor",
+	}
+	for _, p := range args {
+		opt, err := readTaskTest(p)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		result, err := kCli.RunTaskTest(opt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to run test %q", opt.Name)
+		}
+		elapsed := time.Since(start)
+
+		tc := junitTestCase{
+			Name:      result.Name,
+			ClassName: p,
+			Time:      elapsed.Seconds(),
+		}
+		switch {
+		case result.Error != "":
+			fmt.Printf("ERROR %s (%v)\n\t%s\n", result.Name, elapsed, result.Error)
+			tc.Error = &junitMessage{Message: result.Error}
+			suite.Errors++
+		case !result.Passed:
+			fmt.Printf("FAIL  %s (%v)\n", result.Name, elapsed)
+			var text string
+			for _, f := range result.Failures {
+				fmt.Printf("\t%s\n", f)
+				text += f + "\n"
+			}
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%d expectations failed", len(result.Failures)),
+				Text:    text,
+			}
+			suite.Failures++
+		default:
+			fmt.Printf("PASS  %s (%v)\n", result.Name, elapsed)
+		}
+		suite.Tests++
+		suite.Time += tc.Time
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if *testJUnit != "" {
+		if err := writeJUnit(*testJUnit, suite); err != nil {
+			return err
+		}
+	}
+	if failed := suite.Failures + suite.Errors; failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, suite.Tests)
+	}
+	return nil
+}
+
+// JUnit XML report format
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      float64         `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(p string, suite junitTestSuite) error {
+	f, err := os.Create(p)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create JUnit report %q", p)
+	}
+	defer f.Close()
+	f.WriteString(xml.Header)
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return errors.Wrapf(err, "failed to write JUnit report %q", p)
+	}
+	return f.Close()
+}
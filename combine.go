@@ -38,7 +38,7 @@ func newCombineNode(et *ExecutingTask, n *pipeline.CombineNode, d NodeDiagnostic
 			return nil, fmt.Errorf("Failed to compile %v expression: %v", i, err)
 		}
 		cn.expressions[i] = statefulExpr
-		cn.scopePools[i] = stateful.NewScopePool(ast.FindReferenceVariables(lambda.Expression))
+		cn.scopePools[i] = et.tm.newScopePool(ast.FindReferenceVariables(lambda.Expression))
 	}
 	cn.node.runF = cn.runCombine
 	return cn, nil
@@ -14,6 +14,8 @@ import (
 type DerivativeNode struct {
 	node
 	d *pipeline.DerivativeNode
+
+	states groupSnapshotter[derivativeState, *derivativeGroup]
 }
 
 // Create a new derivative node.
@@ -27,7 +29,12 @@ func newDerivativeNode(et *ExecutingTask, n *pipeline.DerivativeNode, d NodeDiag
 	return dn, nil
 }
 
-func (n *DerivativeNode) runDerivative([]byte) error {
+func (n *DerivativeNode) runDerivative(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore derivative state", err)
+		}
+	}
 	consumer := edge.NewGroupedConsumer(
 		n.ins[0],
 		n,
@@ -39,10 +46,18 @@ func (n *DerivativeNode) runDerivative([]byte) error {
 func (n *DerivativeNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.Receiver, error) {
 	return edge.NewReceiverFromForwardReceiverWithStats(
 		n.outs,
-		edge.NewTimedForwardReceiver(n.timer, n.newGroup()),
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, n.newGroup())),
 	), nil
 }
 
+func (n *DerivativeNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *DerivativeNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
 func (n *DerivativeNode) newGroup() *derivativeGroup {
 	return &derivativeGroup{
 		n: n,
@@ -54,6 +69,25 @@ type derivativeGroup struct {
 	previous edge.FieldsTagsTimeGetter
 }
 
+type derivativeState struct {
+	Previous *pointSnapshot
+}
+
+func (g *derivativeGroup) snapshot() derivativeState {
+	var state derivativeState
+	if g.previous != nil {
+		p := newPointSnapshot(g.previous)
+		state.Previous = &p
+	}
+	return state
+}
+
+func (g *derivativeGroup) restore(state derivativeState) {
+	if state.Previous != nil {
+		g.previous = state.Previous.batchPoint()
+	}
+}
+
 func (g *derivativeGroup) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
 	if s := begin.SizeHint(); s > 0 {
 		begin = begin.ShallowCopy()
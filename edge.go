@@ -26,6 +26,8 @@ type EdgeDiagnostic interface {
 type Edge struct {
 	edge.StatsEdge
 
+	debug edge.DebugEdge
+
 	mu     sync.Mutex
 	closed bool
 
@@ -35,7 +37,8 @@ type Edge struct {
 }
 
 func newEdge(taskName, parentName, childName string, t pipeline.EdgeType, size int, d EdgeDiagnostic) edge.StatsEdge {
-	e := edge.NewStatsEdge(edge.NewChannelEdge(t, defaultEdgeBufferSize))
+	de := edge.NewDebugEdge(parentName+"->"+childName, edge.NewChannelEdge(t, defaultEdgeBufferSize))
+	e := edge.NewStatsEdge(de)
 	tags := map[string]string{
 		"task":   taskName,
 		"parent": parentName,
@@ -47,6 +50,7 @@ func newEdge(taskName, parentName, childName string, t pipeline.EdgeType, size i
 	sm.Set(statEmitted, e.EmittedVar())
 	return &Edge{
 		StatsEdge: e,
+		debug:     de,
 		statsKey:  key,
 		statMap:   sm,
 		diag:      d,
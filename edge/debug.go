@@ -0,0 +1,381 @@
+package edge
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/kapacitor/pipeline"
+)
+
+// maxDelivered is the number of delivered messages a breakpoint remembers.
+const maxDelivered = 100
+
+// DebugEdge is an edge on which a breakpoint can be set.
+type DebugEdge interface {
+	Edge
+	// Name returns the name of the edge.
+	Name() string
+	// SetBreakpoint sets the breakpoint that controls the delivery of the messages of the edge.
+	SetBreakpoint(b *Breakpoint)
+	// ClearBreakpoint removes the breakpoint of the edge.
+	ClearBreakpoint()
+}
+
+// EdgeMessage is a message on a named edge.
+type EdgeMessage struct {
+	Edge    string
+	Message Message
+}
+
+// BreakpointState is the state of a breakpoint.
+type BreakpointState struct {
+	Paused bool
+	// Next contains for each edge the message waiting at the breakpoint followed by the messages queued behind it.
+	Next []EdgeMessage
+	// Delivered contains the most recently delivered messages, oldest first.
+	Delivered []EdgeMessage
+}
+
+// Breakpoint controls the delivery of messages on the edges it is set on.
+// While paused a message is only delivered once it has been released by Step.
+//
+// A breakpoint starts paused.
+type Breakpoint struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	paused  bool
+	cleared bool
+	// number of messages released while paused
+	released int
+	// number of messages to read ahead per edge, including the waiting message
+	lookahead int
+
+	waiting   map[*debugEdge]Message
+	delivered []EdgeMessage
+}
+
+// NewBreakpoint creates a paused breakpoint.
+func NewBreakpoint() *Breakpoint {
+	b := &Breakpoint{
+		paused:  true,
+		waiting: make(map[*debugEdge]Message),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Pause stops the delivery of messages.
+func (b *Breakpoint) Pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paused = true
+	b.released = 0
+}
+
+// Resume delivers all messages until paused again.
+func (b *Breakpoint) Resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paused = false
+	b.released = 0
+	b.cond.Broadcast()
+}
+
+// Step releases the next n messages while paused.
+func (b *Breakpoint) Step(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.paused && n > 0 {
+		b.released += n
+		b.cond.Broadcast()
+	}
+}
+
+// Clear delivers all waiting messages and disables the breakpoint.
+// The edges of a cleared breakpoint are no longer paused.
+func (b *Breakpoint) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cleared = true
+	b.paused = false
+	b.released = 0
+	b.cond.Broadcast()
+}
+
+// State returns the state of the breakpoint including the next n messages of each edge.
+// While paused, State waits up to timeout for released messages to be delivered
+// and for the next messages to arrive.
+func (b *Breakpoint) State(n int, timeout time.Duration) BreakpointState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n < 1 {
+		n = 1
+	}
+	b.lookahead = n
+	b.cond.Broadcast()
+	// Only read ahead while the state is requested.
+	defer func() { b.lookahead = 0 }()
+	b.waitFor(timeout, func() bool {
+		if !b.paused {
+			return true
+		}
+		if b.released > 0 || len(b.waiting) == 0 {
+			return false
+		}
+		for e := range b.waiting {
+			if !e.eof() && e.queued() < n-1 {
+				return false
+			}
+		}
+		return true
+	})
+
+	edges := make([]*debugEdge, 0, len(b.waiting))
+	for e := range b.waiting {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].name < edges[j].name })
+	state := BreakpointState{
+		Paused:    b.paused,
+		Delivered: append([]EdgeMessage(nil), b.delivered...),
+	}
+	for _, e := range edges {
+		state.Next = append(state.Next, EdgeMessage{Edge: e.name, Message: b.waiting[e]})
+		for _, m := range e.peek(n - 1) {
+			state.Next = append(state.Next, EdgeMessage{Edge: e.name, Message: m})
+		}
+	}
+	return state
+}
+
+// waitFor waits until done returns true or the timeout elapses.
+// The caller must hold the lock.
+func (b *Breakpoint) waitFor(timeout time.Duration, done func() bool) {
+	expired := false
+	t := time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		expired = true
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	})
+	defer t.Stop()
+	for !expired && !done() {
+		b.cond.Wait()
+	}
+}
+
+// wait blocks the delivery of the message m of edge e until it is released.
+// Returns false if the edge was aborted.
+func (b *Breakpoint) wait(e *debugEdge, m Message) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waiting[e] = m
+	b.cond.Broadcast()
+	defer delete(b.waiting, e)
+	for b.paused && b.released == 0 && e.breakpoint() == b {
+		if e.isAborted() {
+			return false
+		}
+		if e.queued() < b.lookahead-1 && !e.eof() {
+			// Reading blocks until the next message arrives,
+			// so read ahead in the background to still wake on Step, Resume or Clear.
+			e.readAhead(b)
+		}
+		b.cond.Wait()
+	}
+	if e.isAborted() {
+		return false
+	}
+	if b.paused && b.released > 0 {
+		b.released--
+	}
+	if len(b.delivered) == maxDelivered {
+		b.delivered = append(b.delivered[:0], b.delivered[1:]...)
+	}
+	b.delivered = append(b.delivered, EdgeMessage{Edge: e.name, Message: m})
+	b.cond.Broadcast()
+	return true
+}
+
+func (b *Breakpoint) isCleared() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cleared
+}
+
+// wake wakes all edges waiting at the breakpoint.
+func (b *Breakpoint) wake() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+type debugEdge struct {
+	edge Edge
+	name string
+
+	// active is set while a breakpoint is set or messages have been read ahead.
+	active  atomic.Bool
+	aborted atomic.Bool
+
+	mu sync.Mutex
+	bp *Breakpoint
+	// messages read ahead of the waiting message
+	queue  []Message
+	closed bool
+	// reading is closed once the message being read ahead has been queued, nil if none is being read.
+	reading chan struct{}
+}
+
+// NewDebugEdge creates an edge on which a breakpoint can be set.
+// Without a breakpoint messages pass through unchanged.
+func NewDebugEdge(name string, e Edge) DebugEdge {
+	return &debugEdge{
+		edge: e,
+		name: name,
+	}
+}
+
+func (e *debugEdge) Name() string {
+	return e.name
+}
+
+func (e *debugEdge) SetBreakpoint(b *Breakpoint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.bp = b
+	e.active.Store(true)
+}
+
+func (e *debugEdge) ClearBreakpoint() {
+	e.mu.Lock()
+	b := e.bp
+	e.bp = nil
+	e.mu.Unlock()
+	if b != nil {
+		b.wake()
+	}
+}
+
+func (e *debugEdge) Collect(m Message) error {
+	return e.edge.Collect(m)
+}
+
+func (e *debugEdge) Emit() (Message, bool) {
+	if !e.active.Load() {
+		return e.edge.Emit()
+	}
+	m, ok := e.next()
+	if !ok {
+		return nil, false
+	}
+	if b := e.breakpoint(); b != nil && !b.isCleared() && !b.wait(e, m) {
+		return nil, false
+	}
+	e.mu.Lock()
+	if e.bp == nil && len(e.queue) == 0 && e.reading == nil {
+		e.active.Store(false)
+	}
+	e.mu.Unlock()
+	return m, true
+}
+
+func (e *debugEdge) Close() error {
+	return e.edge.Close()
+}
+
+func (e *debugEdge) Abort() {
+	e.aborted.Store(true)
+	e.edge.Abort()
+	if b := e.breakpoint(); b != nil {
+		b.wake()
+	}
+}
+
+func (e *debugEdge) Type() pipeline.EdgeType {
+	return e.edge.Type()
+}
+
+// next returns the first message read ahead or reads the next message from the edge.
+func (e *debugEdge) next() (Message, bool) {
+	e.mu.Lock()
+	for len(e.queue) == 0 && e.reading != nil {
+		// Wait for the message being read ahead so that messages are not reordered.
+		reading := e.reading
+		e.mu.Unlock()
+		<-reading
+		e.mu.Lock()
+	}
+	if len(e.queue) > 0 {
+		m := e.queue[0]
+		e.queue = e.queue[1:]
+		e.mu.Unlock()
+		return m, true
+	}
+	closed := e.closed
+	e.mu.Unlock()
+	if closed {
+		return nil, false
+	}
+	return e.edge.Emit()
+}
+
+// readAhead reads the next message from the edge into the queue in the background,
+// unless a message is already being read, and wakes the breakpoint once it is queued.
+func (e *debugEdge) readAhead(b *Breakpoint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.reading != nil || e.closed {
+		return
+	}
+	reading := make(chan struct{})
+	e.reading = reading
+	go func() {
+		m, ok := e.edge.Emit()
+		e.mu.Lock()
+		if ok {
+			e.queue = append(e.queue, m)
+		} else {
+			e.closed = true
+		}
+		e.reading = nil
+		e.mu.Unlock()
+		close(reading)
+		b.wake()
+	}()
+}
+
+func (e *debugEdge) breakpoint() *Breakpoint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.bp
+}
+
+func (e *debugEdge) queued() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queue)
+}
+
+func (e *debugEdge) peek(n int) []Message {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n > len(e.queue) {
+		n = len(e.queue)
+	}
+	return append([]Message(nil), e.queue[:n]...)
+}
+
+// eof reports whether the edge has no more messages to read ahead.
+func (e *debugEdge) eof() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closed
+}
+
+func (e *debugEdge) isAborted() bool {
+	return e.aborted.Load()
+}
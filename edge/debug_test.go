@@ -0,0 +1,166 @@
+package edge_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+)
+
+func debugPoint(i int) edge.PointMessage {
+	return edge.NewPointMessage(name, db, rp, models.Dimensions{}, models.Fields{"value": int64(i)}, nil, time.Unix(int64(i), 0))
+}
+
+func pointValue(m edge.Message) int64 {
+	return m.(edge.PointMessage).Fields()["value"].(int64)
+}
+
+// emitter emits the messages of an edge in the background.
+func emitter(e edge.Edge) <-chan edge.Message {
+	c := make(chan edge.Message)
+	go func() {
+		defer close(c)
+		for {
+			m, ok := e.Emit()
+			if !ok {
+				return
+			}
+			c <- m
+		}
+	}()
+	return c
+}
+
+func expectEmitted(t *testing.T, c <-chan edge.Message, value int64) {
+	t.Helper()
+	select {
+	case m, ok := <-c:
+		if !ok {
+			t.Fatalf("expected message %d, edge closed", value)
+		}
+		if got := pointValue(m); got != value {
+			t.Fatalf("unexpected message got %d exp %d", got, value)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for message %d", value)
+	}
+}
+
+func expectBlocked(t *testing.T, c <-chan edge.Message) {
+	t.Helper()
+	select {
+	case m := <-c:
+		t.Fatalf("unexpected message %v", m)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestDebugEdge_Breakpoint(t *testing.T) {
+	e := edge.NewDebugEdge("a->b", edge.NewChannelEdge(pipeline.StreamEdge, defaultEdgeBufferSize))
+	b := edge.NewBreakpoint()
+	e.SetBreakpoint(b)
+	for i := 1; i <= 5; i++ {
+		if err := e.Collect(debugPoint(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := emitter(e)
+	expectBlocked(t, c)
+
+	state := b.State(3, time.Second)
+	if !state.Paused {
+		t.Error("expected breakpoint to be paused")
+	}
+	if got, exp := len(state.Next), 3; got != exp {
+		t.Fatalf("unexpected next messages got %d exp %d", got, exp)
+	}
+	for i, em := range state.Next {
+		if em.Edge != "a->b" {
+			t.Errorf("unexpected edge name %q", em.Edge)
+		}
+		if got, exp := pointValue(em.Message), int64(i+1); got != exp {
+			t.Errorf("unexpected next message %d got %d exp %d", i, got, exp)
+		}
+	}
+
+	b.Step(2)
+	expectEmitted(t, c, 1)
+	expectEmitted(t, c, 2)
+	expectBlocked(t, c)
+
+	state = b.State(1, time.Second)
+	if got, exp := len(state.Delivered), 2; got != exp {
+		t.Fatalf("unexpected delivered messages got %d exp %d", got, exp)
+	}
+	if got, exp := pointValue(state.Next[0].Message), int64(3); got != exp {
+		t.Errorf("unexpected waiting message got %d exp %d", got, exp)
+	}
+
+	b.Resume()
+	expectEmitted(t, c, 3)
+	expectEmitted(t, c, 4)
+	expectEmitted(t, c, 5)
+
+	b.Pause()
+	e.Collect(debugPoint(6))
+	expectBlocked(t, c)
+
+	e.ClearBreakpoint()
+	b.Clear()
+	expectEmitted(t, c, 6)
+	e.Collect(debugPoint(7))
+	expectEmitted(t, c, 7)
+	e.Close()
+	if _, ok := <-c; ok {
+		t.Error("expected edge to be closed")
+	}
+}
+
+func TestDebugEdge_StepWithoutMoreInput(t *testing.T) {
+	e := edge.NewDebugEdge("a->b", edge.NewChannelEdge(pipeline.StreamEdge, defaultEdgeBufferSize))
+	b := edge.NewBreakpoint()
+	e.SetBreakpoint(b)
+	e.Collect(debugPoint(1))
+	c := emitter(e)
+	expectBlocked(t, c)
+
+	// Reading ahead waits for messages that do not arrive.
+	state := b.State(3, 10*time.Millisecond)
+	if got, exp := len(state.Next), 1; got != exp {
+		t.Fatalf("unexpected next messages got %d exp %d", got, exp)
+	}
+	b.Step(1)
+	expectEmitted(t, c, 1)
+	expectBlocked(t, c)
+
+	e.Collect(debugPoint(2))
+	e.Collect(debugPoint(3))
+	state = b.State(1, time.Second)
+	if got, exp := pointValue(state.Next[0].Message), int64(2); got != exp {
+		t.Errorf("unexpected waiting message got %d exp %d", got, exp)
+	}
+	b.Resume()
+	expectEmitted(t, c, 2)
+	expectEmitted(t, c, 3)
+}
+
+func TestDebugEdge_Abort(t *testing.T) {
+	e := edge.NewDebugEdge("a->b", edge.NewChannelEdge(pipeline.StreamEdge, defaultEdgeBufferSize))
+	b := edge.NewBreakpoint()
+	e.SetBreakpoint(b)
+	e.Collect(debugPoint(1))
+	c := emitter(e)
+	expectBlocked(t, c)
+
+	e.Abort()
+	select {
+	case _, ok := <-c:
+		if ok {
+			t.Error("expected aborted edge to not deliver the waiting message")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for aborted edge")
+	}
+}
@@ -132,6 +132,8 @@ func (m MessageType) String() string {
 		return "point"
 	case Barrier:
 		return "barrier"
+	case DeleteGroup:
+		return "delete_group"
 	default:
 		return fmt.Sprintf("unknown message type %d", int(m))
 	}
@@ -45,7 +45,7 @@ func newEvalNode(et *ExecutingTask, n *pipeline.EvalNode, d NodeDiagnostic) (*Ev
 		en.refVarList[i] = refVars
 	}
 	// Create a single pool for the combination of all expressions
-	en.scopePool = stateful.NewScopePool(ast.FindReferenceVariables(expressions...))
+	en.scopePool = et.tm.newScopePool(ast.FindReferenceVariables(expressions...))
 
 	// Create map of tags
 	if l := len(n.TagsList); l > 0 {
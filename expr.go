@@ -30,9 +30,10 @@ func fillScope(vars *stateful.Scope, referenceVariables []string, p edge.FieldsT
 	now := p.Time()
 	fields := p.Fields()
 	tags := p.Tags()
+	// The time is always set, since stateful functions like rate use it without referencing it.
+	vars.Set("time", now.Local())
 	for _, refVariableName := range referenceVariables {
 		if refVariableName == "time" {
-			vars.Set("time", now.Local())
 			continue
 		}
 
@@ -100,7 +100,7 @@ func (g *httpPostGroup) EndBatch(end edge.EndBatchMessage) (edge.Message, error)
 
 func (g *httpPostGroup) BufferedBatch(batch edge.BufferedBatchMessage) (edge.Message, error) {
 	row := batch.ToRow()
-	code := g.n.doPost(row)
+	code := g.n.doPost(row, batch.Time())
 	if g.n.c.CodeField != "" {
 		//Add code to all points
 		batch = batch.ShallowCopy()
@@ -121,7 +121,7 @@ func (g *httpPostGroup) BufferedBatch(batch edge.BufferedBatchMessage) (edge.Mes
 
 func (g *httpPostGroup) Point(p edge.PointMessage) (edge.Message, error) {
 	row := p.ToRow()
-	code := g.n.doPost(row)
+	code := g.n.doPost(row, p.Time())
 	if g.n.c.CodeField != "" {
 		//Add code to point
 		p = p.ShallowCopy()
@@ -140,7 +140,11 @@ func (g *httpPostGroup) DeleteGroup(d edge.DeleteGroupMessage) (edge.Message, er
 }
 func (g *httpPostGroup) Done() {}
 
-func (n *HTTPPostNode) doPost(row *models.Row) int {
+func (n *HTTPPostNode) doPost(row *models.Row, t time.Time) int {
+	if n.et.shadow() {
+		n.shadowPost(row, t)
+		return 0
+	}
 	resp, err := n.postRow(row)
 	if err != nil {
 		n.diag.Error("failed to POST data", err)
@@ -164,7 +168,48 @@ func (n *HTTPPostNode) doPost(row *models.Row) int {
 	return resp.StatusCode
 }
 
+// shadowPost records the request a shadow task would have made instead of making it.
+func (n *HTTPPostNode) shadowPost(row *models.Row, t time.Time) {
+	req, err := n.newRequest(row)
+	if err != nil {
+		n.diag.Error("failed to create POST request", err)
+		return
+	}
+	n.et.recordShadow(ShadowRecord{
+		Type:        ShadowPost,
+		Node:        n.Name(),
+		Time:        t,
+		Measurement: row.Name,
+		Points:      len(row.Values),
+		URL:         req.URL.String(),
+	})
+}
+
 func (n *HTTPPostNode) postRow(row *models.Row) (*http.Response, error) {
+	req, err := n.newRequest(row)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set timeout
+	if n.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), n.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	client := n.et.tm.HTTPPostClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (n *HTTPPostNode) newRequest(row *models.Row) (*http.Request, error) {
 	body := new(bytes.Buffer)
 
 	var contentType string
@@ -198,19 +243,7 @@ func (n *HTTPPostNode) postRow(row *models.Row) (*http.Response, error) {
 	for k, v := range n.c.Headers {
 		req.Header.Set(k, v)
 	}
-
-	// Set timeout
-	if n.timeout > 0 {
-		ctx, cancel := context.WithTimeout(req.Context(), n.timeout)
-		defer cancel()
-		req = req.WithContext(ctx)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+	return req, nil
 }
 
 type mappedRow struct {
@@ -23,6 +23,31 @@ func NewFluxQueryResponse(r io.Reader) (*Response, error) {
 	return &Response{Results: []Result{{Series: builder.buf}}}, nil
 }
 
+// ReadFluxTables parses a flux query response in CSV format and calls f with each table as soon as it has been read,
+// so that only one table is held in memory at a time.
+// Parsing stops at the first error returned by f.
+func ReadFluxTables(r io.Reader, f func(imodels.Row) error) error {
+	builder := responseBuilder{onTable: f}
+	err := NewFluxCSVEventParser(&stopReader{r: r, err: &builder.Err}, &builder).Parse()
+	if builder.Err != nil {
+		return builder.Err
+	}
+	return err
+}
+
+// stopReader stops reading once err is set.
+type stopReader struct {
+	r   io.Reader
+	err *error
+}
+
+func (s *stopReader) Read(p []byte) (int, error) {
+	if *s.err != nil {
+		return 0, io.EOF
+	}
+	return s.r.Read(p)
+}
+
 // queryCSVResult is the result of a flux query in CSV format
 // it assumes a csv dialect with
 // Annotations: []string{"datatype", "group"},
@@ -36,6 +61,8 @@ type responseBuilder struct {
 	Err         error
 	buf         []imodels.Row
 	seriesBuf   *imodels.Row
+	// onTable, if set, is called with each table instead of adding it to buf.
+	onTable func(imodels.Row) error
 }
 
 func (q *responseBuilder) TableStart(meta FluxTableMetaData, firstRow []string) {
@@ -63,7 +90,11 @@ func (q *responseBuilder) TableEnd() {
 		return
 	}
 	if q.seriesBuf != nil {
-		q.buf = append(q.buf, *q.seriesBuf)
+		if q.onTable != nil {
+			q.Err = q.onTable(*q.seriesBuf)
+		} else {
+			q.buf = append(q.buf, *q.seriesBuf)
+		}
 	}
 	q.seriesBuf = nil
 }
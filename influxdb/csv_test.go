@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -149,3 +150,41 @@ func Test_FluxCSV_Error(t *testing.T) {
 	}
 
 }
+
+func Test_FluxCSV_ReadTables(t *testing.T) {
+	data := `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string
+#group,false,false,true,true,false,false,true,true
+,result,table,_start,_stop,_time,_value,_field,_measurement
+,_result,0,2021-04-22T16:11:09Z,2021-04-22T16:13:09Z,2021-04-22T16:11:14Z,45,counter,reads
+,_result,0,2021-04-22T16:11:09Z,2021-04-22T16:13:09Z,2021-04-22T16:11:24Z,47,counter,reads
+,_result,1,2021-04-22T16:11:09Z,2021-04-22T16:13:09Z,2021-04-22T16:11:14Z,24,counter,writes
+`
+	var names []string
+	err := ReadFluxTables(bytes.NewBufferString(data), func(row imodels.Row) error {
+		names = append(names, row.Name)
+		if len(row.Values) == 0 {
+			t.Errorf("table %s has no values", row.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"reads", "writes"}; !cmp.Equal(names, exp) {
+		t.Fatal(cmp.Diff(names, exp))
+	}
+
+	// Parsing stops at the first error of the callback.
+	stop := errors.New("stop")
+	names = names[:0]
+	err = ReadFluxTables(bytes.NewBufferString(data), func(row imodels.Row) error {
+		names = append(names, row.Name)
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("Expected error %v, but got %v", stop, err)
+	}
+	if exp := []string{"reads"}; !cmp.Equal(names, exp) {
+		t.Fatal(cmp.Diff(names, exp))
+	}
+}
@@ -61,7 +61,7 @@ func (n *InfluxDBOutNode) runOut([]byte) error {
 	n.wb.start()
 
 	// Create the database and retention policy
-	if n.i.CreateFlag {
+	if n.i.CreateFlag && !n.et.shadow() {
 		err := func() error {
 			cli, err := n.et.tm.InfluxDBService.NewNamedClient(n.i.Cluster)
 			if err != nil {
@@ -181,6 +181,18 @@ func (n *InfluxDBOutNode) write(db, rp string, batch edge.BufferedBatchMessage)
 			Time:   p.Time(),
 		}
 	}
+	if n.et.shadow() {
+		n.et.recordShadow(ShadowRecord{
+			Type:            ShadowWrite,
+			Node:            n.Name(),
+			Time:            batch.Time(),
+			Database:        db,
+			RetentionPolicy: rp,
+			Measurement:     name,
+			Points:          len(points),
+		})
+		return nil
+	}
 	bpc := influxdb.BatchPointsConfig{
 		Database:         db,
 		RetentionPolicy:  rp,
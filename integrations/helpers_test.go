@@ -168,6 +168,9 @@ func (ts taskStore) HasSnapshot(name string) bool
 func (ts taskStore) LoadSnapshot(name string) (*kapacitor.TaskSnapshot, error) {
 	return nil, errors.New("not implemented")
 }
+func (ts taskStore) LoadLibrary(id string) (string, error) {
+	return "", errors.New("not implemented")
+}
 
 type deadman struct {
 	interval  time.Duration
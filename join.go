@@ -1,6 +1,8 @@
 package kapacitor
 
 import (
+	"bytes"
+	"encoding/gob"
 	"fmt"
 	"sync"
 	"time"
@@ -32,6 +34,9 @@ type JoinNode struct {
 
 	reported    map[int]bool
 	allReported bool
+
+	// Serializes processing of messages with snapshots of the node.
+	snapshotMu sync.Mutex
 }
 
 // Create a new JoinNode, which takes pairs from parent streams combines them into a single point.
@@ -66,7 +71,12 @@ func newJoinNode(et *ExecutingTask, n *pipeline.JoinNode, d NodeDiagnostic) (*Jo
 	return jn, nil
 }
 
-func (n *JoinNode) runJoin([]byte) error {
+func (n *JoinNode) runJoin(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore join state", err)
+		}
+	}
 	consumer := edge.NewMultiConsumerWithStats(n.ins, n)
 	valueF := func() int64 {
 		n.groupsMu.RLock()
@@ -88,6 +98,8 @@ func (n *JoinNode) Point(src int, p edge.PointMessage) error {
 }
 
 func (n *JoinNode) Barrier(src int, b edge.BarrierMessage) error {
+	n.snapshotMu.Lock()
+	defer n.snapshotMu.Unlock()
 	g := n.getOrCreateGroup(b.GroupID())
 	if err := g.Barrier(src, b.Time()); err != nil {
 		return err
@@ -98,6 +110,8 @@ func (n *JoinNode) Barrier(src int, b edge.BarrierMessage) error {
 // Delete deletes the group from the JoinNode, and resets the Low Marks for from the group from that source.
 // if deleteAll is set on the pipeline.Joinnode, then it any delete will delete
 func (n *JoinNode) Delete(src int, d edge.DeleteGroupMessage) error {
+	n.snapshotMu.Lock()
+	defer n.snapshotMu.Unlock()
 	groupID := d.GroupID()
 	n.groupsMu.Lock()
 	delete(n.groups, groupID)
@@ -115,6 +129,8 @@ func (n *JoinNode) Delete(src int, d edge.DeleteGroupMessage) error {
 }
 
 func (n *JoinNode) Finish() error {
+	n.snapshotMu.Lock()
+	defer n.snapshotMu.Unlock()
 	// No more points are coming signal all groups to finish up.
 	for _, group := range n.groups {
 		if err := group.Finish(); err != nil {
@@ -124,6 +140,120 @@ func (n *JoinNode) Finish() error {
 	return nil
 }
 
+// joinState is the snapshotted state of a JoinNode.
+type joinState struct {
+	Groups          map[models.GroupID]joinGroupState
+	LowMarks        []joinLowMark
+	MatchBuffers    map[models.GroupID][]srcPointSnapshot
+	SpecificBuffers map[models.GroupID][]srcPointSnapshot
+	Reported        []int
+}
+
+type joinGroupState struct {
+	Sets       []joinsetState
+	Head       []time.Time
+	OldestTime time.Time
+}
+
+type joinsetState struct {
+	Name   string
+	Time   time.Time
+	Values map[int]messageSnapshot
+}
+
+type joinLowMark struct {
+	Src     int
+	GroupID models.GroupID
+	Time    time.Time
+}
+
+type srcPointSnapshot struct {
+	Src int
+	Msg messageSnapshot
+}
+
+func (n *JoinNode) snapshot() ([]byte, error) {
+	n.snapshotMu.Lock()
+	state := joinState{
+		Groups:          make(map[models.GroupID]joinGroupState, len(n.groups)),
+		LowMarks:        make([]joinLowMark, 0, len(n.lowMarks)),
+		MatchBuffers:    snapshotJoinBuffers(n.matchGroupsBuffer),
+		SpecificBuffers: snapshotJoinBuffers(n.specificGroupsBuffer),
+		Reported:        make([]int, 0, len(n.reported)),
+	}
+	for id, g := range n.groups {
+		state.Groups[id] = g.snapshot()
+	}
+	for sg, t := range n.lowMarks {
+		state.LowMarks = append(state.LowMarks, joinLowMark{
+			Src:     sg.src,
+			GroupID: sg.groupId,
+			Time:    t,
+		})
+	}
+	for src := range n.reported {
+		state.Reported = append(state.Reported, src)
+	}
+	n.snapshotMu.Unlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(state)
+	return buf.Bytes(), err
+}
+
+func (n *JoinNode) restore(snapshot []byte) error {
+	var state joinState
+	if err := gob.NewDecoder(bytes.NewReader(snapshot)).Decode(&state); err != nil {
+		return err
+	}
+	n.snapshotMu.Lock()
+	defer n.snapshotMu.Unlock()
+	for id, gs := range state.Groups {
+		// The number of parents is part of the task definition, if it changed the state is stale.
+		if len(gs.Head) != len(n.ins) {
+			continue
+		}
+		g := n.getOrCreateGroup(id)
+		g.restore(gs)
+	}
+	for _, lm := range state.LowMarks {
+		n.lowMarks[srcGroup{src: lm.Src, groupId: lm.GroupID}] = lm.Time
+	}
+	for id, points := range state.MatchBuffers {
+		buf := n.getOrCreateMatchGroup(id)
+		for _, p := range points {
+			buf.Enqueue(srcPoint{Src: p.Src, Msg: p.Msg.message()})
+		}
+	}
+	for id, points := range state.SpecificBuffers {
+		buf := n.getOrCreateSpecificGroup(id)
+		for _, p := range points {
+			buf.Enqueue(srcPoint{Src: p.Src, Msg: p.Msg.message()})
+		}
+	}
+	for _, src := range state.Reported {
+		n.reported[src] = true
+	}
+	n.allReported = len(n.reported) == len(n.ins)
+	return nil
+}
+
+func snapshotJoinBuffers(buffers map[models.GroupID]*CircularQueue[srcPoint]) map[models.GroupID][]srcPointSnapshot {
+	snapshots := make(map[models.GroupID][]srcPointSnapshot, len(buffers))
+	for id, buf := range buffers {
+		points := make([]srcPointSnapshot, buf.Len)
+		for i := range points {
+			p := buf.Peek(i)
+			points[i] = srcPointSnapshot{
+				Src: p.Src,
+				Msg: newMessageSnapshot(p.Msg),
+			}
+		}
+		snapshots[id] = points
+	}
+	return snapshots
+}
+
 type messageMeta interface {
 	edge.Message
 	edge.PointMeta
@@ -135,6 +265,8 @@ type srcPoint struct {
 }
 
 func (n *JoinNode) doMessage(src int, m messageMeta) error {
+	n.snapshotMu.Lock()
+	defer n.snapshotMu.Unlock()
 	n.timer.Start()
 	defer n.timer.Stop()
 	if len(n.j.Dimensions) > 0 {
@@ -355,6 +487,49 @@ type joinGroup struct {
 	oldestTime time.Time
 }
 
+func (g *joinGroup) snapshot() joinGroupState {
+	state := joinGroupState{
+		Head:       append([]time.Time(nil), g.head...),
+		OldestTime: g.oldestTime,
+	}
+	for _, sets := range g.sets {
+		for i := 0; i < sets.Len; i++ {
+			set := sets.Peek(i)
+			ss := joinsetState{
+				Name:   set.name,
+				Time:   set.time,
+				Values: make(map[int]messageSnapshot, set.size),
+			}
+			for src, v := range set.values {
+				if v != nil {
+					ss.Values[src] = newMessageSnapshot(v)
+				}
+			}
+			state.Sets = append(state.Sets, ss)
+		}
+	}
+	return state
+}
+
+func (g *joinGroup) restore(state joinGroupState) {
+	copy(g.head, state.Head)
+	g.oldestTime = state.OldestTime
+	for _, ss := range state.Sets {
+		set := g.newJoinset(ss.Time)
+		set.name = ss.Name
+		for src, v := range ss.Values {
+			if src < set.expected {
+				set.Set(src, v.message())
+			}
+		}
+		if sets := g.sets[ss.Time]; sets != nil {
+			sets.Enqueue(set)
+		} else {
+			g.sets[ss.Time] = NewCircularQueue[*joinset](set)
+		}
+	}
+}
+
 func (g *joinGroup) Finish() error {
 	return g.emitAll()
 }
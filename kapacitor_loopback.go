@@ -68,7 +68,7 @@ func (n *KapacitorLoopbackNode) Point(p edge.PointMessage) error {
 	}
 
 	n.timer.Pause()
-	err := n.et.tm.WriteKapacitorPoint(p)
+	err := n.writePoint(p)
 	n.timer.Resume()
 
 	if err != nil {
@@ -104,7 +104,7 @@ func (n *KapacitorLoopbackNode) BatchPoint(bp edge.BatchPointMessage) error {
 	)
 
 	n.timer.Pause()
-	err := n.et.tm.WriteKapacitorPoint(p)
+	err := n.writePoint(p)
 	n.timer.Resume()
 
 	if err != nil {
@@ -114,6 +114,24 @@ func (n *KapacitorLoopbackNode) BatchPoint(bp edge.BatchPointMessage) error {
 	}
 	return nil
 }
+
+// writePoint writes the point back into Kapacitor, shadow tasks record it instead.
+func (n *KapacitorLoopbackNode) writePoint(p edge.PointMessage) error {
+	if n.et.shadow() {
+		n.et.recordShadow(ShadowRecord{
+			Type:            ShadowLoopback,
+			Node:            n.Name(),
+			Time:            p.Time(),
+			Database:        p.Database(),
+			RetentionPolicy: p.RetentionPolicy(),
+			Measurement:     p.Name(),
+			Points:          1,
+		})
+		return nil
+	}
+	return n.et.tm.WriteKapacitorPoint(p)
+}
+
 func (n *KapacitorLoopbackNode) EndBatch(edge.EndBatchMessage) error {
 	return nil
 }
@@ -0,0 +1,188 @@
+package kapacitor
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sync"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/expvar"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/pkg/errors"
+)
+
+const (
+	// The source index of the stream.
+	lookupJoinStream = 0
+	// The source index of the lookup stream.
+	lookupJoinLookup = 1
+)
+
+type LookupJoinNode struct {
+	node
+	j *pipeline.LookupJoinNode
+
+	mu sync.RWMutex
+	// The most recent lookup point per lookup key.
+	lookups map[models.GroupID]edge.PointMessage
+}
+
+// Create a new LookupJoinNode, which joins points from the stream with the most recent point of the lookup stream.
+func newLookupJoinNode(et *ExecutingTask, n *pipeline.LookupJoinNode, d NodeDiagnostic) (*LookupJoinNode, error) {
+	ln := &LookupJoinNode{
+		j:       n,
+		node:    node{Node: n, et: et, diag: d},
+		lookups: make(map[models.GroupID]edge.PointMessage),
+	}
+	ln.node.runF = ln.runLookupJoin
+	return ln, nil
+}
+
+func (n *LookupJoinNode) runLookupJoin(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore lookupJoin state", err)
+		}
+	}
+	consumer := edge.NewMultiConsumerWithStats(n.ins, n)
+	valueF := func() int64 {
+		n.mu.RLock()
+		l := len(n.lookups)
+		n.mu.RUnlock()
+		return int64(l)
+	}
+	n.statMap.Set(statCardinalityGauge, expvar.NewIntFuncGauge(valueF))
+
+	return consumer.Consume()
+}
+
+// lookupKey returns the key under which lookup points are stored and looked up.
+// The measurement name is not part of the key, since the streams usually have different names.
+func (n *LookupJoinNode) lookupKey(tags models.Tags, dims models.Dimensions) models.GroupID {
+	tagNames := n.j.Dimensions
+	if len(tagNames) == 0 {
+		tagNames = dims.TagNames
+	}
+	return models.ToGroupID("", tags, models.Dimensions{TagNames: tagNames})
+}
+
+func (n *LookupJoinNode) BufferedBatch(src int, batch edge.BufferedBatchMessage) error {
+	return errors.New("lookupJoin does not support batch data")
+}
+
+func (n *LookupJoinNode) Point(src int, p edge.PointMessage) error {
+	key := n.lookupKey(p.Tags(), p.Dimensions())
+	if src == lookupJoinLookup {
+		n.mu.Lock()
+		n.lookups[key] = p
+		n.mu.Unlock()
+		return nil
+	}
+
+	n.mu.RLock()
+	lookup, ok := n.lookups[key]
+	n.mu.RUnlock()
+	if ok && n.j.MaxStaleness > 0 && p.Time().Sub(lookup.Time()) > n.j.MaxStaleness {
+		ok = false
+	}
+	if !ok {
+		if n.j.InnerFlag {
+			return nil
+		}
+		lookup = nil
+	}
+	return edge.Forward(n.outs, n.join(p, lookup))
+}
+
+// join prefixes the fields of the point and the lookup point and merges them into a new point.
+// The lookup point may be nil.
+func (n *LookupJoinNode) join(p, lookup edge.PointMessage) edge.PointMessage {
+	name, lookupName := n.j.Names[lookupJoinStream]+n.j.Delimiter, n.j.Names[lookupJoinLookup]+n.j.Delimiter
+	fields := make(models.Fields, len(p.Fields()))
+	for k, v := range p.Fields() {
+		fields[name+k] = v
+	}
+	tags := p.Tags()
+	if lookup != nil {
+		for k, v := range lookup.Fields() {
+			fields[lookupName+k] = v
+		}
+		tags = tags.Copy()
+		for k, v := range lookup.Tags() {
+			if _, ok := tags[k]; !ok {
+				tags[k] = v
+			}
+		}
+	}
+	return edge.NewPointMessage(
+		p.Name(), p.Database(), p.RetentionPolicy(),
+		p.Dimensions(),
+		fields,
+		tags,
+		p.Time(),
+	)
+}
+
+func (n *LookupJoinNode) Barrier(src int, b edge.BarrierMessage) error {
+	// Only the stream determines the groups of the output.
+	if src != lookupJoinStream {
+		return nil
+	}
+	return edge.Forward(n.outs, b)
+}
+
+// Delete forwards deletes of groups of the stream,
+// and forgets the lookup point of deleted groups of the lookup stream.
+func (n *LookupJoinNode) Delete(src int, d edge.DeleteGroupMessage) error {
+	if src != lookupJoinStream {
+		info := d.GroupInfo()
+		key := n.lookupKey(info.Tags, info.Dimensions)
+		n.mu.Lock()
+		delete(n.lookups, key)
+		n.mu.Unlock()
+		return nil
+	}
+	return edge.Forward(n.outs, d)
+}
+
+func (n *LookupJoinNode) Finish() error {
+	return nil
+}
+
+// lookupJoinState is the snapshotted state of a LookupJoinNode.
+type lookupJoinState struct {
+	Lookups map[models.GroupID]messageSnapshot
+}
+
+func (n *LookupJoinNode) snapshot() ([]byte, error) {
+	n.mu.RLock()
+	state := lookupJoinState{
+		Lookups: make(map[models.GroupID]messageSnapshot, len(n.lookups)),
+	}
+	for key, p := range n.lookups {
+		state.Lookups[key] = newMessageSnapshot(p)
+	}
+	n.mu.RUnlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(state)
+	return buf.Bytes(), err
+}
+
+func (n *LookupJoinNode) restore(snapshot []byte) error {
+	var state lookupJoinState
+	if err := gob.NewDecoder(bytes.NewReader(snapshot)).Decode(&state); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for key, s := range state.Lookups {
+		p, ok := s.message().(edge.PointMessage)
+		if !ok {
+			continue
+		}
+		n.lookups[key] = p
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package kapacitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupJoin(t *testing.T) {
+	newNode := func(t *testing.T, configure func(*pipeline.LookupJoinNode)) (*LookupJoinNode, edge.Edge) {
+		t.Helper()
+		stream1, stream2 := &pipeline.StreamNode{}, &pipeline.StreamNode{}
+		pipeline.CreatePipelineSources(stream1, stream2)
+		pn := stream1.From().LookupJoin(stream2.From())
+		pn.As("floor", "building").On("building")
+		if configure != nil {
+			configure(pn)
+		}
+		n, err := newLookupJoinNode(nil, pn, nil)
+		require.NoError(t, err)
+		out := edge.NewChannelEdge(pipeline.StreamEdge, 10)
+		n.outs = []edge.StatsEdge{edge.NewStatsEdge(out)}
+		return n, out
+	}
+	floor := func(sec int64) edge.PointMessage {
+		tags := models.Tags{"building": "b1", "floor": "f1"}
+		return edge.NewPointMessage(
+			"floor_power", "db", "rp",
+			models.Dimensions{TagNames: []string{"building", "floor"}},
+			models.Fields{"kwh": 1.0},
+			tags,
+			time.Unix(sec, 0),
+		)
+	}
+	building := func(sec int64, area float64) edge.PointMessage {
+		return edge.NewPointMessage(
+			"building_config", "db", "rp",
+			models.Dimensions{TagNames: []string{"building"}},
+			models.Fields{"area": area},
+			models.Tags{"building": "b1", "region": "west"},
+			time.Unix(sec, 0),
+		)
+	}
+	emitted := func(t *testing.T, out edge.Edge) edge.PointMessage {
+		t.Helper()
+		out.Close()
+		m, ok := out.Emit()
+		require.True(t, ok, "expected an emitted point")
+		p, ok := m.(edge.PointMessage)
+		require.True(t, ok, "unexpected message %v", m)
+		return p
+	}
+
+	t.Run("joins the most recent lookup point", func(t *testing.T) {
+		n, out := newNode(t, nil)
+		require.NoError(t, n.Point(lookupJoinLookup, building(0, 100)))
+		require.NoError(t, n.Point(lookupJoinLookup, building(5, 200)))
+		require.NoError(t, n.Point(lookupJoinStream, floor(10)))
+		p := emitted(t, out)
+		assert.Equal(t, models.Fields{"floor.kwh": 1.0, "building.area": 200.0}, p.Fields())
+		assert.Equal(t, models.Tags{"building": "b1", "floor": "f1", "region": "west"}, p.Tags())
+		assert.Equal(t, "floor_power", p.Name())
+		assert.Equal(t, time.Unix(10, 0), p.Time())
+	})
+	t.Run("outer without lookup point", func(t *testing.T) {
+		n, out := newNode(t, nil)
+		require.NoError(t, n.Point(lookupJoinStream, floor(10)))
+		p := emitted(t, out)
+		assert.Equal(t, models.Fields{"floor.kwh": 1.0}, p.Fields())
+	})
+	t.Run("inner drops stale lookup point", func(t *testing.T) {
+		n, out := newNode(t, func(j *pipeline.LookupJoinNode) {
+			j.Inner()
+			j.MaxStaleness = 5 * time.Second
+		})
+		require.NoError(t, n.Point(lookupJoinLookup, building(0, 100)))
+		require.NoError(t, n.Point(lookupJoinStream, floor(10)))
+		out.Close()
+		_, ok := out.Emit()
+		assert.False(t, ok, "expected no emitted point")
+	})
+	t.Run("delete forgets lookup point", func(t *testing.T) {
+		n, out := newNode(t, nil)
+		b := building(0, 100)
+		require.NoError(t, n.Point(lookupJoinLookup, b))
+		require.NoError(t, n.Delete(lookupJoinLookup, edge.NewDeleteGroupMessage(b.GroupInfo())))
+		require.NoError(t, n.Point(lookupJoinStream, floor(10)))
+		p := emitted(t, out)
+		assert.Equal(t, models.Fields{"floor.kwh": 1.0}, p.Fields())
+	})
+	t.Run("snapshot restore", func(t *testing.T) {
+		n, _ := newNode(t, nil)
+		require.NoError(t, n.Point(lookupJoinLookup, building(0, 100)))
+		data, err := n.snapshot()
+		require.NoError(t, err)
+
+		restored, out := newNode(t, nil)
+		require.NoError(t, restored.restore(data))
+		require.NoError(t, restored.Point(lookupJoinStream, floor(10)))
+		p := emitted(t, out)
+		assert.Equal(t, models.Fields{"floor.kwh": 1.0, "building.area": 100.0}, p.Fields())
+	})
+}
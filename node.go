@@ -70,6 +70,7 @@ type Node interface {
 	pipeline.Node
 
 	addParentEdge(edge.StatsEdge)
+	parentEdges() []edge.StatsEdge
 
 	init(quiet bool)
 
@@ -135,6 +136,10 @@ func (n *node) addParentEdge(e edge.StatsEdge) {
 	n.ins = append(n.ins, e)
 }
 
+func (n *node) parentEdges() []edge.StatsEdge {
+	return n.ins
+}
+
 func (n *node) abortParentEdges() {
 	for _, in := range n.ins {
 		in.Abort()
@@ -0,0 +1,217 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// Detect anomalies using the z-score of the rolling window.
+	AnomalyZScore = "zscore"
+	// Detect anomalies using the median absolute deviation of the rolling window.
+	AnomalyMAD = "mad"
+	// Detect anomalies using the residual of a Holt-Winters forecast of the rolling window.
+	AnomalySeasonal = "seasonal"
+)
+
+const (
+	defaultAnomalyThreshold = 3.0
+	defaultAnomalyWindow    = 100
+)
+
+// Score each point of a stream or batch against a rolling baseline of the previous
+// values of a field, per group, and mark points that deviate from the baseline as anomalies.
+//
+// The detector used to compute the baseline is selected with one of the
+// zscore, mad or seasonal properties, the default is zscore with a threshold of 3.
+//
+//   - zscore: the baseline is the mean of the window and the score is the number of standard deviations from the mean.
+//   - mad: the baseline is the median of the window and the score is the number of median absolute deviations from the median.
+//     The median absolute deviation is scaled by 1.4826 so that it is comparable to a standard deviation.
+//     This detector is robust to the outliers in the window.
+//   - seasonal: the baseline is a one step Holt-Winters forecast of the window and the score is
+//     the number of standard deviations of the previous forecast residuals from the forecast.
+//     The forecast assumes the points arrive at a regular interval.
+//     This detector handles trends and seasonality but is considerably more expensive.
+//     Seasonality is multiplicative, a window with values that are not positive, such as counts with zeros,
+//     is modelled without seasonality.
+//
+// Each point is emitted with the following fields added:
+//
+//   - score: the signed number of deviations of the value from the baseline.
+//   - lower: the lower bound of the band of expected values.
+//   - upper: the upper bound of the band of expected values.
+//   - is_anomaly: true if the absolute score is greater than the threshold.
+//
+// Until the window is full, and for seasonal until a few forecasts have been made,
+// points are emitted with a score of 0, both bounds equal to the value and is_anomaly false.
+//
+// Example:
+//
+//	stream
+//	    |from()
+//	        .measurement('requests')
+//	        .groupBy('host')
+//	    |anomaly('value')
+//	        .mad(3.0)
+//	        .window(200)
+//	    |alert()
+//	        .crit(lambda: "is_anomaly")
+type AnomalyNode struct {
+	chainnode `json:"-"`
+
+	// The field to check for anomalies.
+	// tick:ignore
+	Field string `json:"field"`
+
+	// The detector used to compute the baseline.
+	// tick:ignore
+	Detector string `tick:"Zscore" json:"detector"`
+
+	// tick:ignore
+	_ string `tick:"Mad"`
+
+	// tick:ignore
+	_ string `tick:"Seasonal"`
+
+	// The number of deviations from the baseline beyond which a value is an anomaly.
+	// tick:ignore
+	Threshold float64 `json:"threshold"`
+
+	// The number of points in the season of the seasonal detector,
+	// zero or one means the data is not seasonal.
+	// tick:ignore
+	Seasonality int64 `json:"seasonality"`
+
+	// The number of previous points per group used to compute the baseline.
+	// Default: 100
+	Window int64 `json:"window"`
+
+	// The name of the score field.
+	// Default: score
+	ScoreField string `json:"scoreField"`
+
+	// The name of the lower bound field.
+	// Default: lower
+	LowerField string `json:"lowerField"`
+
+	// The name of the upper bound field.
+	// Default: upper
+	UpperField string `json:"upperField"`
+
+	// The name of the anomaly field.
+	// Default: is_anomaly
+	AnomalyField string `json:"anomalyField"`
+}
+
+func newAnomalyNode(wants EdgeType, field string) *AnomalyNode {
+	return &AnomalyNode{
+		chainnode:    newBasicChainNode("anomaly", wants, wants),
+		Field:        field,
+		Detector:     AnomalyZScore,
+		Threshold:    defaultAnomalyThreshold,
+		Window:       defaultAnomalyWindow,
+		ScoreField:   "score",
+		LowerField:   "lower",
+		UpperField:   "upper",
+		AnomalyField: "is_anomaly",
+	}
+}
+
+// MarshalJSON converts AnomalyNode to JSON
+// tick:ignore
+func (n *AnomalyNode) MarshalJSON() ([]byte, error) {
+	type Alias AnomalyNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+	}{
+		TypeOf: TypeOf{
+			Type: "anomaly",
+			ID:   n.ID(),
+		},
+		Alias: (*Alias)(n),
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON converts JSON to an AnomalyNode
+// tick:ignore
+func (n *AnomalyNode) UnmarshalJSON(data []byte) error {
+	type Alias AnomalyNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+	}{
+		Alias: (*Alias)(n),
+	}
+	err := json.Unmarshal(data, raw)
+	if err != nil {
+		return err
+	}
+	if raw.Type != "anomaly" {
+		return fmt.Errorf("error unmarshaling node %d of type %s as AnomalyNode", raw.ID, raw.Type)
+	}
+
+	n.setID(raw.ID)
+	return nil
+}
+
+// Detect anomalies using the z-score of the window,
+// a value is an anomaly if it is more than threshold standard deviations from the mean.
+// tick:property
+func (n *AnomalyNode) Zscore(threshold float64) *AnomalyNode {
+	n.Detector = AnomalyZScore
+	n.Threshold = threshold
+	return n
+}
+
+// Detect anomalies using the median absolute deviation of the window,
+// a value is an anomaly if it is more than threshold scaled median absolute deviations from the median.
+// tick:property
+func (n *AnomalyNode) Mad(threshold float64) *AnomalyNode {
+	n.Detector = AnomalyMAD
+	n.Threshold = threshold
+	return n
+}
+
+// Detect anomalies using the residual of a Holt-Winters forecast of the window,
+// a value is an anomaly if it is more than threshold standard deviations of the forecast residuals from the forecast.
+// The seasonality is the number of points in a season, use 0 for data that is not seasonal.
+// The window should contain at least two seasons.
+// The forecast model is fit to the window once per window of points and updated with each point in between.
+// tick:property
+func (n *AnomalyNode) Seasonal(threshold float64, seasonality int64) *AnomalyNode {
+	n.Detector = AnomalySeasonal
+	n.Threshold = threshold
+	n.Seasonality = seasonality
+	return n
+}
+
+func (n *AnomalyNode) validate() error {
+	if n.Field == "" {
+		return errors.New("must specify a field")
+	}
+	switch n.Detector {
+	case AnomalyZScore, AnomalyMAD, AnomalySeasonal:
+	default:
+		return fmt.Errorf("unknown detector %q", n.Detector)
+	}
+	if n.Threshold <= 0 {
+		return errors.New("threshold must be greater than zero")
+	}
+	if n.Window < 3 {
+		return errors.New("window must contain at least 3 points")
+	}
+	if n.Seasonality < 0 {
+		return errors.New("seasonality must not be negative")
+	}
+	if n.Detector == AnomalySeasonal && n.Seasonality > 1 && n.Window < 2*n.Seasonality {
+		return errors.New("window must contain at least two seasons")
+	}
+	if n.ScoreField == "" || n.LowerField == "" || n.UpperField == "" || n.AnomalyField == "" {
+		return errors.New("field names must not be empty")
+	}
+	return nil
+}
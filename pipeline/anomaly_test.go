@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/influxdata/kapacitor/tick/stateful"
+)
+
+func TestTICK_To_Pipeline_Anomaly(t *testing.T) {
+	var tickScript = `
+stream
+	|from()
+	|anomaly('value')
+		.mad(3.0)
+		.window(200)
+	|alert()
+		.crit(lambda: "is_anomaly")
+`
+	p, err := CreatePipeline(tickScript, StreamEdge, stateful.NewScope(), deadman{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, ok := p.sources[0].Children()[0].Children()[0].(*AnomalyNode)
+	if !ok {
+		t.Fatalf("unexpected node type: exp AnomalyNode got %T", p.sources[0].Children()[0].Children()[0])
+	}
+	if a.Field != "value" || a.Detector != AnomalyMAD || a.Threshold != 3.0 || a.Window != 200 {
+		t.Errorf("unexpected anomaly node: %+v", a)
+	}
+}
+
+func TestAnomalyNode_MarshalJSON(t *testing.T) {
+	stream := &StreamNode{}
+	CreatePipelineSources(stream)
+	a := stream.From().Anomaly("value").Seasonal(2.5, 24)
+	a.Window = 48
+	a.setID(2)
+
+	want := `{"typeOf":"anomaly","id":"2","field":"value","detector":"seasonal","threshold":2.5,"seasonality":24,"window":48,"scoreField":"score","lowerField":"lower","upperField":"upper","anomalyField":"is_anomaly"}`
+	MarshalTestHelper(t, a, false, want)
+}
+
+func TestPipeline_Unmarshal_Anomaly(t *testing.T) {
+	stream := &StreamNode{}
+	p := CreatePipelineSources(stream)
+	stream.From().Anomaly("value").Mad(3.5).ScoreField = "s"
+
+	data, err := p.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Pipeline{}
+	if err := got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	var a *AnomalyNode
+	for _, n := range got.sorted {
+		if n, ok := n.(*AnomalyNode); ok {
+			a = n
+		}
+	}
+	if a == nil {
+		t.Fatal("missing anomaly node")
+	}
+	if a.Field != "value" || a.Detector != AnomalyMAD || a.Threshold != 3.5 || a.ScoreField != "s" || a.Window != defaultAnomalyWindow {
+		t.Errorf("unexpected anomaly node: %+v", a)
+	}
+}
+
+func TestAnomalyNode_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		configure func(*AnomalyNode)
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			field:     "value",
+			configure: func(a *AnomalyNode) {},
+		},
+		{
+			name:      "missing field",
+			configure: func(a *AnomalyNode) {},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown detector",
+			field:     "value",
+			configure: func(a *AnomalyNode) { a.Detector = "iforest" },
+			wantErr:   true,
+		},
+		{
+			name:      "zero threshold",
+			field:     "value",
+			configure: func(a *AnomalyNode) { a.Mad(0) },
+			wantErr:   true,
+		},
+		{
+			name:      "small window",
+			field:     "value",
+			configure: func(a *AnomalyNode) { a.Window = 2 },
+			wantErr:   true,
+		},
+		{
+			name:      "negative seasonality",
+			field:     "value",
+			configure: func(a *AnomalyNode) { a.Seasonal(3, -1) },
+			wantErr:   true,
+		},
+		{
+			name:  "window shorter than two seasons",
+			field: "value",
+			configure: func(a *AnomalyNode) {
+				a.Seasonal(3, 60)
+				a.Window = 100
+			},
+			wantErr: true,
+		},
+		{
+			name:      "empty field name",
+			field:     "value",
+			configure: func(a *AnomalyNode) { a.AnomalyField = "" },
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &StreamNode{}
+			CreatePipelineSources(stream)
+			a := stream.From().Anomaly(tt.field)
+			tt.configure(a)
+			if err := a.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
@@ -103,6 +104,15 @@ func (b *BatchNode) QueryFlux(q string) *QueryFluxNode {
 	return n
 }
 
+// The SQL query to execute against a configured relational database.
+// The `{{start}}` and `{{stop}}` placeholders are bound to the time bounds of each query.
+func (b *BatchNode) QuerySQL(q string) *QuerySQLNode {
+	n := newQuerySQLNode()
+	n.QueryStr = q
+	b.linkChild(n)
+	return n
+}
+
 // Do not add the source batch node to the dot output
 // since its not really an edge.
 // tick:ignore
@@ -509,3 +519,199 @@ func (n *QueryFluxNode) Align() *QueryFluxNode {
 	n.AlignFlag = true
 	return n
 }
+
+// A QuerySQLNode defines a source and a schedule for
+// processing batch data. The data is queried from
+// a relational database configured in the `[[sql]]` section
+// and then passed into the data pipeline.
+//
+// Each row of the result becomes a point.
+// The time column sets the time of the point, the tag columns set its tags
+// and all other non NULL columns become fields.
+// Rows are grouped into one batch per set of tag values.
+//
+// Example:
+// batch
+//
+//	|querySQL('''
+//	    SELECT created_at, region, count(*) AS orders
+//	    FROM orders
+//	    WHERE created_at >= {{start}} AND created_at < {{stop}}
+//	    GROUP BY created_at, region
+//	''')
+//	    .connection('shop')
+//	    .period(1h)
+//	    .every(10m)
+//	    .timeColumn('created_at')
+//	    .tags('region')
+//	    .measurement('orders')
+//	...
+//
+// In the above example the database is queried every 10 minutes for the orders of the last hour.
+type QuerySQLNode struct {
+	chainnode `json:"-"`
+
+	// The query text
+	//tick:ignore
+	QueryStr string `json:"queryStr"`
+
+	// The period or length of time that will be queried.
+	// It sets the time between the {{start}} and {{stop}} placeholders of the query.
+	Period time.Duration `json:"period"`
+
+	// How often to query the database.
+	//
+	// The Every property is mutually exclusive with the Cron property.
+	Every time.Duration `json:"every"`
+
+	// Align start and end times with the Every value
+	// Does not apply if Cron is used.
+	// tick:ignore
+	AlignFlag bool `tick:"Align" json:"align"`
+
+	// Define a schedule using a cron syntax.
+	//
+	// The specific cron implementation is documented here:
+	// https://github.com/gorhill/cronexpr#implementation
+	//
+	// The Cron property is mutually exclusive with the Every property.
+	Cron string `json:"cron"`
+
+	// How far back in time to query from the current time
+	Offset time.Duration `json:"offset"`
+
+	// The name of a configured SQL connection.
+	// If empty the default connection will be used.
+	Connection string `json:"connection"`
+
+	// The column containing the time of each row.
+	// If the result has no such column the stop time of the query is used.
+	// Default: time
+	TimeColumn string `json:"timeColumn"`
+
+	// The columns that become tags.
+	// tick:ignore
+	TagColumns []string `tick:"Tags" json:"tags"`
+
+	// The measurement name of the resulting batches.
+	// Default: sql
+	Measurement string `json:"measurement"`
+}
+
+func newQuerySQLNode() *QuerySQLNode {
+	return &QuerySQLNode{
+		chainnode:   newBasicChainNode("querySQL", BatchEdge, BatchEdge),
+		TimeColumn:  "time",
+		Measurement: "sql",
+	}
+}
+
+// MarshalJSON converts QuerySQLNode to JSON
+// tick:ignore
+func (n *QuerySQLNode) MarshalJSON() ([]byte, error) {
+	type Alias QuerySQLNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		Period string `json:"period"`
+		Every  string `json:"every"`
+		Offset string `json:"offset"`
+	}{
+		TypeOf: TypeOf{
+			Type: "querySQL",
+			ID:   n.ID(),
+		},
+		Alias:  (*Alias)(n),
+		Period: influxql.FormatDuration(n.Period),
+		Every:  influxql.FormatDuration(n.Every),
+		Offset: influxql.FormatDuration(n.Offset),
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON converts JSON to an QuerySQLNode
+// tick:ignore
+func (n *QuerySQLNode) UnmarshalJSON(data []byte) error {
+	type Alias QuerySQLNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		Period string `json:"period"`
+		Every  string `json:"every"`
+		Offset string `json:"offset"`
+	}{
+		Alias: (*Alias)(n),
+	}
+	err := json.Unmarshal(data, raw)
+	if err != nil {
+		return err
+	}
+	if raw.Type != "querySQL" {
+		return fmt.Errorf("error unmarshaling node %d of type %s as QuerySQLNode", raw.ID, raw.Type)
+	}
+
+	n.Period, err = influxql.ParseDuration(raw.Period)
+	if err != nil {
+		return err
+	}
+
+	n.Every, err = influxql.ParseDuration(raw.Every)
+	if err != nil {
+		return err
+	}
+
+	n.Offset, err = influxql.ParseDuration(raw.Offset)
+	if err != nil {
+		return err
+	}
+
+	n.setID(raw.ID)
+	return nil
+}
+
+//tick:ignore
+func (n *QuerySQLNode) ChainMethods() map[string]reflect.Value {
+	return map[string]reflect.Value{}
+}
+
+// Align start and stop times for queries with even boundaries of the QuerySQLNode.Every property.
+// Does not apply if using the QuerySQLNode.Cron property.
+// tick:property
+func (n *QuerySQLNode) Align() *QuerySQLNode {
+	n.AlignFlag = true
+	return n
+}
+
+// The columns of the result that become tags.
+// tick:property
+func (n *QuerySQLNode) Tags(columns ...string) *QuerySQLNode {
+	n.TagColumns = columns
+	return n
+}
+
+func (n *QuerySQLNode) validate() error {
+	if n.QueryStr == "" {
+		return errors.New("must provide a query")
+	}
+	if n.Every != 0 && n.Cron != "" {
+		return errors.New("must not set both 'every' and 'cron' properties")
+	}
+	if n.Every < 0 || n.Period < 0 || n.Offset < 0 {
+		return errors.New("'period', 'every' and 'offset' must not be negative")
+	}
+	if n.Every == 0 && n.Cron == "" {
+		return errors.New("must define one of 'every' or 'cron'")
+	}
+	if n.Measurement == "" {
+		return errors.New("must provide a measurement")
+	}
+	for _, c := range n.TagColumns {
+		if c == "" {
+			return errors.New("tag columns must not be empty")
+		}
+		if c == n.TimeColumn {
+			return fmt.Errorf("column %q cannot be both the time column and a tag", c)
+		}
+	}
+	return nil
+}
@@ -1 +1,103 @@
 package pipeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/tick/stateful"
+)
+
+func TestTICK_To_Pipeline_QuerySQL(t *testing.T) {
+	var tickScript = `
+batch
+	|querySQL('SELECT created_at, region, total FROM orders WHERE created_at >= {{start}} AND created_at < {{stop}}')
+		.connection('shop')
+		.period(1h)
+		.every(10m)
+		.align()
+		.timeColumn('created_at')
+		.tags('region', 'plan')
+		.measurement('orders')
+	|count('total')
+`
+	p, err := CreatePipeline(tickScript, BatchEdge, stateful.NewScope(), deadman{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, ok := p.sources[0].Children()[0].(*QuerySQLNode)
+	if !ok {
+		t.Fatalf("unexpected node type: exp QuerySQLNode got %T", p.sources[0].Children()[0])
+	}
+	if q.Connection != "shop" || q.Period != time.Hour || q.Every != 10*time.Minute || !q.AlignFlag {
+		t.Errorf("unexpected querySQL node: %+v", q)
+	}
+	if q.TimeColumn != "created_at" || q.Measurement != "orders" || !reflect.DeepEqual(q.TagColumns, []string{"region", "plan"}) {
+		t.Errorf("unexpected querySQL node: %+v", q)
+	}
+}
+
+func TestQuerySQLNode_MarshalJSON(t *testing.T) {
+	batch := &BatchNode{}
+	CreatePipelineSources(batch)
+	q := batch.QuerySQL("SELECT * FROM orders").Tags("region")
+	q.Period = time.Hour
+	q.Every = time.Minute
+	q.setID(1)
+
+	want := `{"typeOf":"querySQL","id":"1","queryStr":"SELECT * FROM orders","align":false,"cron":"","connection":"","timeColumn":"time","tags":["region"],"measurement":"sql","period":"1h","every":"1m","offset":"0s"}`
+	MarshalTestHelper(t, q, false, want)
+}
+
+func TestQuerySQLNode_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*QuerySQLNode)
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			configure: func(q *QuerySQLNode) { q.Every = time.Minute },
+		},
+		{
+			name:      "no schedule",
+			configure: func(q *QuerySQLNode) {},
+			wantErr:   true,
+		},
+		{
+			name: "every and cron",
+			configure: func(q *QuerySQLNode) {
+				q.Every = time.Minute
+				q.Cron = "* * * * *"
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative period",
+			configure: func(q *QuerySQLNode) {
+				q.Every = time.Minute
+				q.Period = -time.Minute
+			},
+			wantErr: true,
+		},
+		{
+			name: "time column as tag",
+			configure: func(q *QuerySQLNode) {
+				q.Every = time.Minute
+				q.Tags("time")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := &BatchNode{}
+			CreatePipelineSources(batch)
+			q := batch.QuerySQL("SELECT * FROM orders")
+			tt.configure(q)
+			if err := q.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
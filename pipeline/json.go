@@ -176,6 +176,7 @@ func init() {
 		"from":      unmarshalFrom,
 		"query":     unmarshalQuery,
 		"queryFlux": unmarshalQueryFlux,
+		"querySQL":  unmarshalQuerySQL,
 	}
 
 	// Add default construction of chain nodes
@@ -198,6 +199,8 @@ func init() {
 		"eval":              func(parent chainnodeAlias) Node { return parent.Eval() },
 		"derivative":        func(parent chainnodeAlias) Node { return parent.Derivative("") },
 		"changeDetect":      func(parent chainnodeAlias) Node { return parent.ChangeDetect("") },
+		"anomaly":           func(parent chainnodeAlias) Node { return parent.Anomaly("") },
+		"quantiles":         func(parent chainnodeAlias) Node { return parent.Quantiles("") },
 		"delete":            func(parent chainnodeAlias) Node { return parent.Delete() },
 		"default":           func(parent chainnodeAlias) Node { return parent.Default() },
 		"combine":           func(parent chainnodeAlias) Node { return parent.Combine(nil) },
@@ -205,8 +208,9 @@ func init() {
 	}
 
 	multiParents = map[string]func(chainnodeAlias, []Node) Node{
-		"union": func(parent chainnodeAlias, nodes []Node) Node { return parent.Union(nodes...) },
-		"join":  func(parent chainnodeAlias, nodes []Node) Node { return parent.Join(nodes...) },
+		"union":      func(parent chainnodeAlias, nodes []Node) Node { return parent.Union(nodes...) },
+		"join":       func(parent chainnodeAlias, nodes []Node) Node { return parent.Join(nodes...) },
+		"lookupJoin": func(parent chainnodeAlias, nodes []Node) Node { return parent.LookupJoin(nodes[0]) },
 	}
 
 	influxFunctions = map[string]func(chainnodeAlias, string) *InfluxQLNode{
@@ -346,6 +350,9 @@ func (p *Pipeline) unmarshalNode(data []byte, typ TypeOf, parents []Node) (Node,
 		if len(parents) < 2 {
 			return nil, fmt.Errorf("expected more than one parent for node %d but received %d", typ.ID, len(parents))
 		}
+		if typ.Type == "lookupJoin" && len(parents) != 2 {
+			return nil, fmt.Errorf("expected two parents for node %d but received %d", typ.ID, len(parents))
+		}
 		parent := parents[0]
 		chainParent, ok := isChainNode(parent)
 		if !ok {
@@ -420,6 +427,16 @@ func unmarshalQueryFlux(data []byte, source Node) (Node, error) {
 	return child, err
 }
 
+func unmarshalQuerySQL(data []byte, source Node) (Node, error) {
+	batch, ok := source.(*BatchNode)
+	if !ok {
+		return nil, fmt.Errorf("parent of query node must be a BatchNode but is %T", source)
+	}
+	child := batch.QuerySQL("")
+	err := json.Unmarshal(data, child)
+	return child, err
+}
+
 func unmarshalWhere(data []byte, parents []Node, typ TypeOf) (Node, error) {
 	if len(parents) != 1 {
 		return nil, fmt.Errorf("expected one parent for node %d but found %d", typ.ID, len(parents))
@@ -527,6 +544,7 @@ func isChainNode(node Node) (chainnodeAlias, bool) {
 // chainnodeAlias is used to check for the presence of a chain node
 type chainnodeAlias interface {
 	Alert() *AlertNode
+	Anomaly(string) *AnomalyNode
 	Bottom(int64, string, ...string) *InfluxQLNode
 	Children() []Node
 	Combine(...*ast.LambdaNode) *CombineNode
@@ -555,6 +573,7 @@ type chainnodeAlias interface {
 	KapacitorLoopback() *KapacitorLoopbackNode
 	Last(string) *InfluxQLNode
 	Log() *LogNode
+	LookupJoin(Node) *LookupJoinNode
 	Max(string) *InfluxQLNode
 	Mean(string) *InfluxQLNode
 	Median(string) *InfluxQLNode
@@ -565,6 +584,7 @@ type chainnodeAlias interface {
 	Parents() []Node
 	Percentile(string, float64) *InfluxQLNode
 	Provides() EdgeType
+	Quantiles(string, ...float64) *QuantilesNode
 	Sample(interface{}) *SampleNode
 	SetName(string)
 	Shift(time.Duration) *ShiftNode
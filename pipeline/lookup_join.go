@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxql"
+)
+
+// Joins each data point of a stream with the most recent data point of
+// the same group from a slowly changing lookup stream.
+// Unlike the join node, points are emitted as soon as they arrive,
+// without waiting for a point with a matching timestamp from the lookup stream.
+//
+// Aliases are used to prefix all fields from the respective nodes.
+// The tags of the lookup point are added to the joined point,
+// unless the point already has a tag with the same name.
+//
+// Example:
+//
+//	var deploys = stream
+//	    |from()
+//	        .measurement('deploys')
+//	        .groupBy('service')
+//	stream
+//	    |from()
+//	        .measurement('requests')
+//	        .groupBy('service')
+//	    |lookupJoin(deploys)
+//	        .as('requests', 'deploy')
+//	        // Do not use deploys that are older than a day.
+//	        .maxStaleness(24h)
+//	    |eval(lambda: "requests.errors" / "requests.total")
+//	        .as('error_rate')
+//	        .keep('error_rate', 'deploy.version')
+//	    ...
+//
+// In the above example each requests point is joined with the last deploy of its service.
+type LookupJoinNode struct {
+	chainnode `json:"-"`
+	// The alias names of the stream and the lookup stream.
+	// tick:ignore
+	Names []string `tick:"As" json:"as"`
+
+	// The dimensions on which to look up points.
+	// tick:ignore
+	Dimensions []string `tick:"On" json:"on"`
+
+	// The delimiter for the field name prefixes.
+	// Can be the empty string.
+	Delimiter string `json:"delimiter"`
+
+	// The maximum age of a lookup point, relative to the time of the point it is joined with.
+	// Older lookup points are not joined.
+	// If zero, lookup points never become stale.
+	MaxStaleness time.Duration `json:"maxStaleness"`
+
+	// Whether to drop the points without a lookup point, instead of emitting them without the lookup fields.
+	// tick:ignore
+	InnerFlag bool `tick:"Inner" json:"inner"`
+}
+
+func newLookupJoinNode(e EdgeType, parents []Node) *LookupJoinNode {
+	j := &LookupJoinNode{
+		chainnode: newBasicChainNode("lookupJoin", e, e),
+		Delimiter: defaultJoinDelimiter,
+	}
+	for _, n := range parents {
+		n.linkChild(j)
+	}
+	return j
+}
+
+// MarshalJSON converts LookupJoinNode to JSON
+// tick:ignore
+func (n *LookupJoinNode) MarshalJSON() ([]byte, error) {
+	type Alias LookupJoinNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		MaxStaleness string `json:"maxStaleness"`
+	}{
+		TypeOf: TypeOf{
+			Type: "lookupJoin",
+			ID:   n.ID(),
+		},
+		Alias:        (*Alias)(n),
+		MaxStaleness: influxql.FormatDuration(n.MaxStaleness),
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON converts JSON to an LookupJoinNode
+// tick:ignore
+func (n *LookupJoinNode) UnmarshalJSON(data []byte) error {
+	type Alias LookupJoinNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		MaxStaleness string `json:"maxStaleness"`
+	}{
+		Alias: (*Alias)(n),
+	}
+	err := json.Unmarshal(data, raw)
+	if err != nil {
+		return err
+	}
+	if raw.Type != "lookupJoin" {
+		return fmt.Errorf("error unmarshaling node %d of type %s as LookupJoinNode", raw.ID, raw.Type)
+	}
+	n.MaxStaleness, err = influxql.ParseDuration(raw.MaxStaleness)
+	if err != nil {
+		return err
+	}
+	n.setID(raw.ID)
+	return nil
+}
+
+// Prefix names for all fields from the stream and the lookup stream.
+// Each field from the parent nodes will be prefixed with the provided name and a '.'.
+// See the example above.
+//
+// The names cannot have a dot '.' character.
+//
+// tick:property
+func (j *LookupJoinNode) As(name, lookupName string) *LookupJoinNode {
+	j.Names = []string{name, lookupName}
+	return j
+}
+
+// Look up points on a subset of the group by dimensions of the stream.
+// By default points are looked up on all group by dimensions of the stream.
+//
+// Example:
+//
+//	var building = stream
+//	    |from()
+//	        .measurement('building_config')
+//	        .groupBy('building')
+//	stream
+//	    |from()
+//	        .measurement('floor_power')
+//	        .groupBy('building', 'floor')
+//	    |lookupJoin(building)
+//	        .as('floor', 'building')
+//	        .on('building')
+//	    ...
+//
+// tick:property
+func (j *LookupJoinNode) On(dims ...string) *LookupJoinNode {
+	j.Dimensions = dims
+	return j
+}
+
+// Drop the points of the stream without a lookup point that is not stale, inner join.
+// By default such points are emitted without the fields of the lookup stream.
+// tick:property
+func (j *LookupJoinNode) Inner() *LookupJoinNode {
+	j.InnerFlag = true
+	return j
+}
+
+func (j *LookupJoinNode) validate() error {
+	if j.Wants() != StreamEdge {
+		return fmt.Errorf("lookupJoin only supports stream data")
+	}
+	if len(j.Parents()) != 2 {
+		return fmt.Errorf("lookupJoin requires exactly one lookup stream")
+	}
+	if len(j.Names) != 2 {
+		return fmt.Errorf("a call to lookupJoin.as() is required to specify the output stream prefixes.")
+	}
+	for _, name := range j.Names {
+		if len(name) == 0 {
+			return fmt.Errorf("must provide a prefix name for the lookupJoin node, see .as() property method")
+		}
+		if j.Delimiter != "" && strings.Contains(name, j.Delimiter) {
+			return fmt.Errorf("cannot use name %s as field prefix, it contains the delimiter %q", name, j.Delimiter)
+		}
+	}
+	if j.Names[0] == j.Names[1] {
+		return fmt.Errorf("cannot use the same prefix name see .as() property method")
+	}
+	if j.MaxStaleness < 0 {
+		return fmt.Errorf("maxStaleness must not be negative")
+	}
+	return nil
+}
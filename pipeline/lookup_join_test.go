@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookupJoinNode_MarshalJSON(t *testing.T) {
+	stream1, stream2 := &StreamNode{}, &StreamNode{}
+	CreatePipelineSources(stream1, stream2)
+	j := stream1.From().LookupJoin(stream2.From())
+	j.As("floor", "building").On("building").Inner()
+	j.MaxStaleness = time.Hour
+	j.setID(4)
+
+	want := `{"typeOf":"lookupJoin","id":"4","as":["floor","building"],"on":["building"],"delimiter":".","inner":true,"maxStaleness":"1h"}`
+	MarshalTestHelper(t, j, false, want)
+}
+
+func TestPipeline_Unmarshal_LookupJoin(t *testing.T) {
+	stream1, stream2 := &StreamNode{}, &StreamNode{}
+	p := CreatePipelineSources(stream1, stream2)
+	j := stream1.From().LookupJoin(stream2.From())
+	j.As("floor", "building")
+	j.MaxStaleness = time.Minute
+
+	data, err := p.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Pipeline{}
+	if err := got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	var lj *LookupJoinNode
+	for _, n := range got.sorted {
+		if n, ok := n.(*LookupJoinNode); ok {
+			lj = n
+		}
+	}
+	if lj == nil {
+		t.Fatal("missing lookupJoin node")
+	}
+	if len(lj.Parents()) != 2 {
+		t.Errorf("unexpected number of parents: %d", len(lj.Parents()))
+	}
+	if lj.MaxStaleness != time.Minute || lj.Names[0] != "floor" || lj.Names[1] != "building" {
+		t.Errorf("unexpected lookupJoin node: %+v", lj)
+	}
+}
+
+func TestLookupJoinNode_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*LookupJoinNode)
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			configure: func(j *LookupJoinNode) { j.As("floor", "building") },
+		},
+		{
+			name:      "missing as",
+			configure: func(j *LookupJoinNode) {},
+			wantErr:   true,
+		},
+		{
+			name:      "same names",
+			configure: func(j *LookupJoinNode) { j.As("a", "a") },
+			wantErr:   true,
+		},
+		{
+			name:      "name contains delimiter",
+			configure: func(j *LookupJoinNode) { j.As("a.b", "c") },
+			wantErr:   true,
+		},
+		{
+			name: "negative maxStaleness",
+			configure: func(j *LookupJoinNode) {
+				j.As("a", "b")
+				j.MaxStaleness = -time.Second
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream1, stream2 := &StreamNode{}, &StreamNode{}
+			CreatePipelineSources(stream1, stream2)
+			j := stream1.From().LookupJoin(stream2.From())
+			tt.configure(j)
+			if err := j.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -390,6 +390,11 @@ func (n *chainnode) Join(others ...Node) *JoinNode {
 	return j
 }
 
+// Join each point of this node with the most recent point of the same group from the lookup node.
+func (n *chainnode) LookupJoin(lookup Node) *LookupJoinNode {
+	return newLookupJoinNode(n.provides, []Node{n, lookup})
+}
+
 // Combine this node with itself. The data are combined on timestamp.
 func (n *chainnode) Combine(expressions ...*ast.LambdaNode) *CombineNode {
 	c := newCombineNode(n.provides, expressions)
@@ -462,6 +467,20 @@ func (n *chainnode) Derivative(field string) *DerivativeNode {
 	return s
 }
 
+// Create a new node that computes approximate quantiles of a field using mergeable sketches.
+func (n *chainnode) Quantiles(field string, quantiles ...float64) *QuantilesNode {
+	q := newQuantilesNode(n.Provides(), field, quantiles)
+	n.linkChild(q)
+	return q
+}
+
+// Create a new node that scores the field of each point against a rolling baseline to detect anomalies.
+func (n *chainnode) Anomaly(field string) *AnomalyNode {
+	s := newAnomalyNode(n.Provides(), field)
+	n.linkChild(s)
+	return s
+}
+
 // Create a new node that only emits new points if different from the previous point
 func (n *chainnode) ChangeDetect(fields ...string) *ChangeDetectNode {
 	s := newChangeDetectNode(n.Provides(), fields)
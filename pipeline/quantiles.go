@@ -0,0 +1,208 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxql"
+)
+
+const defaultQuantilesCompression = 100
+
+// Compute approximate quantiles of a field using a mergeable t-digest sketch per group.
+// Unlike the percentile and median functions the points are not buffered,
+// each point is added to the sketch as it arrives so the memory used per group is bounded
+// by the compression of the sketch regardless of the number of points.
+//
+// For batch data one point is emitted per batch, with the time of the batch.
+// For stream data a point is emitted at the end of each period, with the start time of the period,
+// once a point of the next period arrives.
+// If no period is set, a point with the quantiles of all the data seen so far is emitted for each point.
+//
+// The emitted points contain a field for each quantile, named after the quantile by default:
+// p50, p90, p99 and p999 for 0.5, 0.9, 0.99 and 0.999.
+//
+// The sketch itself can be emitted as a base64 encoded string field with the sketchField property.
+// Downstream, a quantiles node with the merge property merges those sketches instead of adding values,
+// for example to compute quantiles across groups or over longer periods.
+//
+// Example:
+//
+//	stream
+//	    |from()
+//	        .measurement('requests')
+//	        .groupBy('host')
+//	    |quantiles('latency', 0.5, 0.9, 0.99)
+//	        .period(1m)
+//	        .sketchField('sketch')
+//	    // Compute the quantiles across all hosts from the per host sketches.
+//	    |groupBy()
+//	    |quantiles('sketch', 0.5, 0.9, 0.99)
+//	        .merge()
+//	        .period(1m)
+//	    |influxDBOut()
+//	        .database('mydb')
+//	        .measurement('latency_quantiles')
+type QuantilesNode struct {
+	chainnode `json:"-"`
+
+	// The field to compute quantiles of, or the field containing the sketches to merge.
+	// tick:ignore
+	Field string `json:"field"`
+
+	// The quantiles to compute, between 0 and 1.
+	// tick:ignore
+	Quantiles []float64 `json:"quantiles"`
+
+	// The names of the quantile fields, one per quantile.
+	// tick:ignore
+	Names []string `tick:"As" json:"as"`
+
+	// The compression of the t-digest sketches, larger values are more accurate but use more memory.
+	// Default: 100
+	Compression float64 `json:"compression"`
+
+	// The period over which quantiles of stream data are computed.
+	// If zero, the quantiles of all the data seen so far are emitted with each point.
+	// A period is emitted by the first point or barrier past its end.
+	Period time.Duration `json:"period"`
+
+	// The name of the field in which to emit the serialized sketch.
+	// If empty, the sketch is not emitted.
+	SketchField string `json:"sketchField"`
+
+	// Whether the field contains serialized sketches to merge.
+	// tick:ignore
+	MergeFlag bool `tick:"Merge" json:"merge"`
+}
+
+func newQuantilesNode(wants EdgeType, field string, quantiles []float64) *QuantilesNode {
+	return &QuantilesNode{
+		chainnode:   newBasicChainNode("quantiles", wants, StreamEdge),
+		Field:       field,
+		Quantiles:   quantiles,
+		Names:       quantileNames(quantiles),
+		Compression: defaultQuantilesCompression,
+	}
+}
+
+// quantileNames returns the default field names of the quantiles.
+func quantileNames(quantiles []float64) []string {
+	names := make([]string, len(quantiles))
+	for i, q := range quantiles {
+		s := strconv.FormatFloat(q, 'f', -1, 64)
+		switch {
+		case strings.HasPrefix(s, "0."):
+			s = strings.TrimPrefix(s, "0.")
+			if len(s) == 1 {
+				s += "0"
+			}
+		case s == "1":
+			s = "100"
+		}
+		names[i] = "p" + s
+	}
+	return names
+}
+
+// MarshalJSON converts QuantilesNode to JSON
+// tick:ignore
+func (n *QuantilesNode) MarshalJSON() ([]byte, error) {
+	type Alias QuantilesNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		Period string `json:"period"`
+	}{
+		TypeOf: TypeOf{
+			Type: "quantiles",
+			ID:   n.ID(),
+		},
+		Alias:  (*Alias)(n),
+		Period: influxql.FormatDuration(n.Period),
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON converts JSON to an QuantilesNode
+// tick:ignore
+func (n *QuantilesNode) UnmarshalJSON(data []byte) error {
+	type Alias QuantilesNode
+	var raw = &struct {
+		TypeOf
+		*Alias
+		Period string `json:"period"`
+	}{
+		Alias: (*Alias)(n),
+	}
+	err := json.Unmarshal(data, raw)
+	if err != nil {
+		return err
+	}
+	if raw.Type != "quantiles" {
+		return fmt.Errorf("error unmarshaling node %d of type %s as QuantilesNode", raw.ID, raw.Type)
+	}
+	n.Period, err = influxql.ParseDuration(raw.Period)
+	if err != nil {
+		return err
+	}
+	n.setID(raw.ID)
+	return nil
+}
+
+// The names of the quantile fields, one per quantile.
+// tick:property
+func (n *QuantilesNode) As(names ...string) *QuantilesNode {
+	n.Names = names
+	return n
+}
+
+// Merge the serialized sketches contained in the field, as emitted by the sketchField property
+// of another quantiles node, instead of adding the values of the field.
+// The sketches are merged with the compression of this node.
+// tick:property
+func (n *QuantilesNode) Merge() *QuantilesNode {
+	n.MergeFlag = true
+	return n
+}
+
+func (n *QuantilesNode) validate() error {
+	if n.Field == "" {
+		return errors.New("must specify a field")
+	}
+	if len(n.Quantiles) == 0 {
+		return errors.New("must specify at least one quantile")
+	}
+	for _, q := range n.Quantiles {
+		if q < 0 || q > 1 {
+			return fmt.Errorf("quantile %v must be between 0 and 1", q)
+		}
+	}
+	if len(n.Names) != len(n.Quantiles) {
+		return fmt.Errorf("must provide one name per quantile, got %d names for %d quantiles", len(n.Names), len(n.Quantiles))
+	}
+	names := make(map[string]bool, len(n.Names))
+	for _, name := range n.Names {
+		if name == "" {
+			return errors.New("quantile names must not be empty")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate quantile name %q", name)
+		}
+		names[name] = true
+	}
+	if names[n.SketchField] {
+		return fmt.Errorf("sketchField %q is also a quantile name", n.SketchField)
+	}
+	if n.Compression <= 0 {
+		return errors.New("compression must be greater than zero")
+	}
+	if n.Period < 0 {
+		return errors.New("period must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package pipeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/tick/stateful"
+)
+
+func TestTICK_To_Pipeline_Quantiles(t *testing.T) {
+	var tickScript = `
+stream
+	|from()
+	|quantiles('latency', 0.5, 0.9, 0.99, 0.999)
+		.period(1m)
+		.sketchField('sketch')
+	|groupBy()
+	|quantiles('sketch', 0.5)
+		.as('median')
+		.merge()
+`
+	p, err := CreatePipeline(tickScript, StreamEdge, stateful.NewScope(), deadman{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, ok := p.sources[0].Children()[0].Children()[0].(*QuantilesNode)
+	if !ok {
+		t.Fatalf("unexpected node type: exp QuantilesNode got %T", p.sources[0].Children()[0].Children()[0])
+	}
+	if exp := []string{"p50", "p90", "p99", "p999"}; !reflect.DeepEqual(q.Names, exp) {
+		t.Errorf("unexpected names: exp %v got %v", exp, q.Names)
+	}
+	if q.Period != time.Minute || q.SketchField != "sketch" || q.MergeFlag || q.Provides() != StreamEdge {
+		t.Errorf("unexpected quantiles node: %+v", q)
+	}
+	merge, ok := q.Children()[0].Children()[0].(*QuantilesNode)
+	if !ok {
+		t.Fatalf("unexpected node type: exp QuantilesNode got %T", q.Children()[0].Children()[0])
+	}
+	if merge.Field != "sketch" || !merge.MergeFlag || !reflect.DeepEqual(merge.Names, []string{"median"}) {
+		t.Errorf("unexpected quantiles node: %+v", merge)
+	}
+}
+
+func TestQuantilesNode_MarshalJSON(t *testing.T) {
+	stream := &StreamNode{}
+	CreatePipelineSources(stream)
+	q := stream.From().Quantiles("latency", 0.05, 1).Merge()
+	q.Period = time.Minute
+	q.setID(2)
+
+	want := `{"typeOf":"quantiles","id":"2","field":"latency","quantiles":[0.05,1],"as":["p05","p100"],"compression":100,"sketchField":"","merge":true,"period":"1m"}`
+	MarshalTestHelper(t, q, false, want)
+}
+
+func TestQuantilesNode_validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantiles []float64
+		configure func(*QuantilesNode)
+		wantErr   bool
+	}{
+		{
+			name:      "valid",
+			quantiles: []float64{0, 0.5, 1},
+			configure: func(q *QuantilesNode) {},
+		},
+		{
+			name:      "no quantiles",
+			configure: func(q *QuantilesNode) {},
+			wantErr:   true,
+		},
+		{
+			name:      "quantile out of range",
+			quantiles: []float64{1.5},
+			configure: func(q *QuantilesNode) {},
+			wantErr:   true,
+		},
+		{
+			name:      "missing names",
+			quantiles: []float64{0.5, 0.9},
+			configure: func(q *QuantilesNode) { q.As("median") },
+			wantErr:   true,
+		},
+		{
+			name:      "duplicate names",
+			quantiles: []float64{0.5, 0.9},
+			configure: func(q *QuantilesNode) { q.As("a", "a") },
+			wantErr:   true,
+		},
+		{
+			name:      "sketch field conflicts",
+			quantiles: []float64{0.5},
+			configure: func(q *QuantilesNode) { q.SketchField = "p50" },
+			wantErr:   true,
+		},
+		{
+			name:      "zero compression",
+			quantiles: []float64{0.5},
+			configure: func(q *QuantilesNode) { q.Compression = 0 },
+			wantErr:   true,
+		},
+		{
+			name:      "negative period",
+			quantiles: []float64{0.5},
+			configure: func(q *QuantilesNode) { q.Period = -time.Second },
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &StreamNode{}
+			CreatePipelineSources(stream)
+			q := stream.From().Quantiles("value", tt.quantiles...)
+			tt.configure(q)
+			if err := q.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package tick
+
+import (
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/kapacitor/tick/ast"
+)
+
+// AnomalyNode converts the Anomaly pipeline node into the TICKScript AST
+type AnomalyNode struct {
+	Function
+}
+
+// NewAnomaly creates an Anomaly function builder
+func NewAnomaly(parents []ast.Node) *AnomalyNode {
+	return &AnomalyNode{
+		Function{
+			Parents: parents,
+		},
+	}
+}
+
+// Build creates an Anomaly ast.Node
+func (n *AnomalyNode) Build(a *pipeline.AnomalyNode) (ast.Node, error) {
+	n.Pipe("anomaly", a.Field)
+	switch a.Detector {
+	case pipeline.AnomalyMAD:
+		n.Dot("mad", a.Threshold)
+	case pipeline.AnomalySeasonal:
+		n.DotZeroValueOK("seasonal", a.Threshold, a.Seasonality)
+	default:
+		n.Dot("zscore", a.Threshold)
+	}
+	n.Dot("window", a.Window).
+		Dot("scoreField", a.ScoreField).
+		Dot("lowerField", a.LowerField).
+		Dot("upperField", a.UpperField).
+		Dot("anomalyField", a.AnomalyField)
+	return n.prev, n.err
+}
@@ -0,0 +1,40 @@
+package tick_test
+
+import (
+	"testing"
+)
+
+func TestAnomaly(t *testing.T) {
+	pipe, _, from := StreamFrom()
+	from.Anomaly("value").Mad(3.5)
+
+	want := `stream
+    |from()
+    |anomaly('value')
+        .mad(3.5)
+        .window(100)
+        .scoreField('score')
+        .lowerField('lower')
+        .upperField('upper')
+        .anomalyField('is_anomaly')
+`
+	PipelineTickTestHelper(t, pipe, want)
+}
+
+func TestAnomalySeasonal(t *testing.T) {
+	pipe, _, from := StreamFrom()
+	a := from.Anomaly("value").Seasonal(3.0, 0)
+	a.Window = 50
+
+	want := `stream
+    |from()
+    |anomaly('value')
+        .seasonal(3.0, 0)
+        .window(50)
+        .scoreField('score')
+        .lowerField('lower')
+        .upperField('upper')
+        .anomalyField('is_anomaly')
+`
+	PipelineTickTestHelper(t, pipe, want)
+}
@@ -86,6 +86,8 @@ func (a *AST) Create(n pipeline.Node, parents []ast.Node) (ast.Node, error) {
 		return NewUnion(parents).Build(node)
 	case *pipeline.JoinNode:
 		return NewJoin(parents).Build(node)
+	case *pipeline.LookupJoinNode:
+		return NewLookupJoin(parents).Build(node)
 	case *pipeline.AlertNode:
 		return NewAlert(parents).Build(node)
 	case *pipeline.BarrierNode:
@@ -100,6 +102,10 @@ func (a *AST) Create(n pipeline.Node, parents []ast.Node) (ast.Node, error) {
 		return NewDerivative(parents).Build(node)
 	case *pipeline.ChangeDetectNode:
 		return NewChangeDetect(parents).Build(node)
+	case *pipeline.AnomalyNode:
+		return NewAnomaly(parents).Build(node)
+	case *pipeline.QuantilesNode:
+		return NewQuantiles(parents).Build(node)
 	case *pipeline.Ec2AutoscaleNode:
 		return NewEc2Autoscale(parents).Build(node)
 	case *pipeline.EvalNode:
@@ -128,6 +134,8 @@ func (a *AST) Create(n pipeline.Node, parents []ast.Node) (ast.Node, error) {
 		return NewQuery(parents).Build(node)
 	case *pipeline.QueryFluxNode:
 		return NewQueryFlux(parents).Build(node)
+	case *pipeline.QuerySQLNode:
+		return NewQuerySQL(parents).Build(node)
 	case *pipeline.SampleNode:
 		return NewSample(parents).Build(node)
 	case *pipeline.ShiftNode:
@@ -0,0 +1,31 @@
+package tick
+
+import (
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/kapacitor/tick/ast"
+)
+
+// LookupJoinNode converts the LookupJoinNode pipeline node into the TICKScript AST
+type LookupJoinNode struct {
+	Function
+}
+
+// NewLookupJoin creates a LookupJoinNode function builder
+func NewLookupJoin(parents []ast.Node) *LookupJoinNode {
+	return &LookupJoinNode{
+		Function{
+			Parents: parents,
+		},
+	}
+}
+
+// Build creates a LookupJoinNode ast.Node
+func (n *LookupJoinNode) Build(j *pipeline.LookupJoinNode) (ast.Node, error) {
+	n.Pipe("lookupJoin", n.Parents[1]).
+		Dot("as", args(j.Names)...).
+		Dot("on", args(j.Dimensions)...).
+		Dot("delimiter", j.Delimiter).
+		Dot("maxStaleness", j.MaxStaleness).
+		DotIf("inner", j.InnerFlag)
+	return n.prev, n.err
+}
@@ -0,0 +1,44 @@
+package tick_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/pipeline"
+)
+
+func TestLookupJoin(t *testing.T) {
+	stream1 := &pipeline.StreamNode{}
+	stream2 := &pipeline.StreamNode{}
+	pipe := pipeline.CreatePipelineSources(stream1, stream2)
+
+	from1 := stream1.From()
+	from1.Measurement = "floor_power"
+	from1.GroupBy("building", "floor")
+
+	from2 := stream2.From()
+	from2.Measurement = "building_config"
+	from2.GroupBy("building")
+
+	join := from1.LookupJoin(from2)
+	join.As("floor", "building").On("building").Inner()
+	join.MaxStaleness = time.Hour
+
+	want := `var from3 = stream
+    |from()
+        .measurement('building_config')
+        .groupBy('building')
+
+stream
+    |from()
+        .measurement('floor_power')
+        .groupBy('building', 'floor')
+    |lookupJoin(from3)
+        .as('floor', 'building')
+        .on('building')
+        .delimiter('.')
+        .maxStaleness(1h)
+        .inner()
+`
+	PipelineTickTestHelper(t, pipe, want)
+}
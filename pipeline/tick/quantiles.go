@@ -0,0 +1,36 @@
+package tick
+
+import (
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/kapacitor/tick/ast"
+)
+
+// QuantilesNode converts the Quantiles pipeline node into the TICKScript AST
+type QuantilesNode struct {
+	Function
+}
+
+// NewQuantiles creates a Quantiles function builder
+func NewQuantiles(parents []ast.Node) *QuantilesNode {
+	return &QuantilesNode{
+		Function{
+			Parents: parents,
+		},
+	}
+}
+
+// Build creates a Quantiles ast.Node
+func (n *QuantilesNode) Build(q *pipeline.QuantilesNode) (ast.Node, error) {
+	quantiles := make([]interface{}, 0, len(q.Quantiles)+1)
+	quantiles = append(quantiles, q.Field)
+	for _, v := range q.Quantiles {
+		quantiles = append(quantiles, v)
+	}
+	n.PipeZeroValueOK("quantiles", quantiles...).
+		Dot("as", args(q.Names)...).
+		Dot("compression", q.Compression).
+		Dot("period", q.Period).
+		Dot("sketchField", q.SketchField).
+		DotIf("merge", q.MergeFlag)
+	return n.prev, n.err
+}
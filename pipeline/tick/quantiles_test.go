@@ -0,0 +1,29 @@
+package tick_test
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuantiles(t *testing.T) {
+	pipe, _, from := StreamFrom()
+	q := from.Quantiles("latency", 0.5, 0.99)
+	q.Period = time.Minute
+	q.SketchField = "sketch"
+	q.GroupBy().Quantiles("sketch", 0.99).As("max").Merge()
+
+	want := `stream
+    |from()
+    |quantiles('latency', 0.5, 0.99)
+        .as('p50', 'p99')
+        .compression(100.0)
+        .period(1m)
+        .sketchField('sketch')
+    |groupBy()
+    |quantiles('sketch', 0.99)
+        .as('max')
+        .compression(100.0)
+        .merge()
+`
+	PipelineTickTestHelper(t, pipe, want)
+}
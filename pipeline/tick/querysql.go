@@ -0,0 +1,35 @@
+package tick
+
+import (
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/kapacitor/tick/ast"
+)
+
+// QuerySQLNode converts the QuerySQLNode pipeline node into the TICKScript AST
+type QuerySQLNode struct {
+	Function
+}
+
+// NewQuerySQL creates a QuerySQLNode function builder
+func NewQuerySQL(parents []ast.Node) *QuerySQLNode {
+	return &QuerySQLNode{
+		Function{
+			Parents: parents,
+		},
+	}
+}
+
+// Build creates a QuerySQLNode ast.Node
+func (n *QuerySQLNode) Build(q *pipeline.QuerySQLNode) (ast.Node, error) {
+	n.Pipe("querySQL", q.QueryStr).
+		Dot("period", q.Period).
+		Dot("every", q.Every).
+		DotIf("align", q.AlignFlag).
+		Dot("cron", q.Cron).
+		Dot("offset", q.Offset).
+		Dot("connection", q.Connection).
+		Dot("timeColumn", q.TimeColumn).
+		Dot("tags", args(q.TagColumns)...).
+		Dot("measurement", q.Measurement)
+	return n.prev, n.err
+}
@@ -0,0 +1,33 @@
+package tick_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/pipeline"
+)
+
+func TestQuerySQL(t *testing.T) {
+	batch := &pipeline.BatchNode{}
+	pipe := pipeline.CreatePipelineSources(batch)
+	query := batch.QuerySQL(`SELECT created_at, region, total FROM orders WHERE created_at >= {{start}}`).
+		Tags("region")
+	query.Period = time.Hour
+	query.Every = 10 * time.Minute
+	query.Offset = time.Minute
+	query.Connection = "shop"
+	query.TimeColumn = "created_at"
+	query.Measurement = "orders"
+
+	want := `batch
+    |querySQL('SELECT created_at, region, total FROM orders WHERE created_at >= {{start}}')
+        .period(1h)
+        .every(10m)
+        .offset(1m)
+        .connection('shop')
+        .timeColumn('created_at')
+        .tags('region')
+        .measurement('orders')
+`
+	PipelineTickTestHelper(t, pipe, want)
+}
@@ -15,6 +15,8 @@ func TestWindowNode(t *testing.T) {
 		fillPeriod  bool
 		periodCount int64
 		everyCount  int64
+		gap         time.Duration
+		maxDuration time.Duration
 	}
 	tests := []struct {
 		name string
@@ -50,6 +52,19 @@ func TestWindowNode(t *testing.T) {
     |window()
         .periodCount(10)
         .everyCount(15)
+`,
+		},
+		{
+			name: "session window with gap and max duration",
+			args: args{
+				gap:         5 * time.Minute,
+				maxDuration: time.Hour,
+			},
+			want: `stream
+    |from()
+    |window()
+        .gap(5m)
+        .maxDuration(1h)
 `,
 		},
 	}
@@ -65,6 +80,8 @@ func TestWindowNode(t *testing.T) {
 			w.FillPeriodFlag = tt.args.fillPeriod
 			w.PeriodCount = tt.args.periodCount
 			w.EveryCount = tt.args.everyCount
+			w.Gap = tt.args.gap
+			w.MaxDuration = tt.args.maxDuration
 
 			got, err := PipelineTick(pipe)
 			if err != nil {
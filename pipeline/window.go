@@ -32,6 +32,27 @@ import (
 // new data and `5 minutes` of the previous period's data.
 //
 // NOTE: Because no `align` property is defined, the `window` edge is defined relative to the first data point.
+//
+// Session windows are defined by the `gap` property instead of a period.
+// A session of a group lasts while points keep arriving, and ends once no point
+// has arrived for the `gap` duration. The `maxDuration` property ends sessions that
+// last longer than the duration, even if points keep arriving.
+//
+// Example:
+//
+//	stream
+//	    |from()
+//	        .measurement('activity')
+//	        .groupBy('device')
+//	    |window()
+//	        .gap(5m)
+//	        .maxDuration(1h)
+//	    |count('value')
+//
+// This example counts the points of each activity session of a device.
+// The time of an emitted session is the time of its last point, and each point of the session
+// has the `session_start` and `session_end` fields with the times of the first and last point
+// of the session as nanoseconds since the epoch.
 type WindowNode struct {
 	chainnode `json:"-"`
 	// The period, or length in time, of the window.
@@ -51,6 +72,12 @@ type WindowNode struct {
 	// EveryCount determines how often the window is emitted based on the count of points.
 	// A value of 1 means that every new point will emit the window.
 	EveryCount int64 `json:"everyCount"`
+
+	// Gap is the duration without points after which a session window ends.
+	Gap time.Duration `json:"gap"`
+	// MaxDuration is the maximum length of a session window.
+	// If equal to zero, sessions only end after a gap.
+	MaxDuration time.Duration `json:"maxDuration"`
 }
 
 func newWindowNode() *WindowNode {
@@ -66,16 +93,20 @@ func (n *WindowNode) MarshalJSON() ([]byte, error) {
 	var raw = &struct {
 		TypeOf
 		*Alias
-		Period string `json:"period"`
-		Every  string `json:"every"`
+		Period      string `json:"period"`
+		Every       string `json:"every"`
+		Gap         string `json:"gap"`
+		MaxDuration string `json:"maxDuration"`
 	}{
 		TypeOf: TypeOf{
 			Type: "window",
 			ID:   n.ID(),
 		},
-		Alias:  (*Alias)(n),
-		Period: influxql.FormatDuration(n.Period),
-		Every:  influxql.FormatDuration(n.Every),
+		Alias:       (*Alias)(n),
+		Period:      influxql.FormatDuration(n.Period),
+		Every:       influxql.FormatDuration(n.Every),
+		Gap:         influxql.FormatDuration(n.Gap),
+		MaxDuration: influxql.FormatDuration(n.MaxDuration),
 	}
 	return json.Marshal(raw)
 }
@@ -87,8 +118,10 @@ func (n *WindowNode) UnmarshalJSON(data []byte) error {
 	var raw = &struct {
 		TypeOf
 		*Alias
-		Period string `json:"period"`
-		Every  string `json:"every"`
+		Period      string `json:"period"`
+		Every       string `json:"every"`
+		Gap         string `json:"gap"`
+		MaxDuration string `json:"maxDuration"`
 	}{
 		Alias: (*Alias)(n),
 	}
@@ -110,6 +143,20 @@ func (n *WindowNode) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// Session windows were added later, older tasks do not have them.
+	if raw.Gap != "" {
+		n.Gap, err = influxql.ParseDuration(raw.Gap)
+		if err != nil {
+			return err
+		}
+	}
+	if raw.MaxDuration != "" {
+		n.MaxDuration, err = influxql.ParseDuration(raw.MaxDuration)
+		if err != nil {
+			return err
+		}
+	}
+
 	n.setID(raw.ID)
 	return nil
 }
@@ -144,5 +191,17 @@ func (w *WindowNode) validate() error {
 	if w.PeriodCount != 0 && w.EveryCount <= 0 {
 		return errors.New("everyCount must be greater than zero")
 	}
+	if w.Gap < 0 {
+		return errors.New("gap must not be negative")
+	}
+	if w.MaxDuration < 0 {
+		return errors.New("maxDuration must not be negative")
+	}
+	if w.MaxDuration != 0 && w.Gap == 0 {
+		return errors.New("maxDuration can only be used with gap")
+	}
+	if w.Gap != 0 && (w.Period != 0 || w.Every != 0 || w.PeriodCount != 0 || w.EveryCount != 0 || w.AlignFlag || w.FillPeriodFlag) {
+		return errors.New("cannot specify gap with period, every, periodCount, everyCount, align or fillPeriod")
+	}
 	return nil
 }
@@ -15,6 +15,8 @@ func TestWindowNode_MarshalJSON(t *testing.T) {
 		FillPeriodFlag bool
 		PeriodCount    int64
 		EveryCount     int64
+		Gap            time.Duration
+		MaxDuration    time.Duration
 	}
 	tests := []struct {
 		name    string
@@ -32,7 +34,7 @@ func TestWindowNode_MarshalJSON(t *testing.T) {
 				PeriodCount:    1,
 				EveryCount:     2,
 			},
-			want: `{"typeOf":"window","id":"0","align":true,"fillPeriod":true,"periodCount":1,"everyCount":2,"period":"1h","every":"1m"}`,
+			want: `{"typeOf":"window","id":"0","align":true,"fillPeriod":true,"periodCount":1,"everyCount":2,"period":"1h","every":"1m","gap":"0s","maxDuration":"0s"}`,
 		},
 		{
 			name: "only period and every",
@@ -40,7 +42,15 @@ func TestWindowNode_MarshalJSON(t *testing.T) {
 				Period: time.Hour,
 				Every:  time.Minute,
 			},
-			want: `{"typeOf":"window","id":"0","align":false,"fillPeriod":false,"periodCount":0,"everyCount":0,"period":"1h","every":"1m"}`,
+			want: `{"typeOf":"window","id":"0","align":false,"fillPeriod":false,"periodCount":0,"everyCount":0,"period":"1h","every":"1m","gap":"0s","maxDuration":"0s"}`,
+		},
+		{
+			name: "session window",
+			fields: fields{
+				Gap:         5 * time.Minute,
+				MaxDuration: time.Hour,
+			},
+			want: `{"typeOf":"window","id":"0","align":false,"fillPeriod":false,"periodCount":0,"everyCount":0,"period":"0s","every":"0s","gap":"5m","maxDuration":"1h"}`,
 		},
 	}
 	for _, tt := range tests {
@@ -52,6 +62,8 @@ func TestWindowNode_MarshalJSON(t *testing.T) {
 			w.FillPeriodFlag = tt.fields.FillPeriodFlag
 			w.PeriodCount = tt.fields.PeriodCount
 			w.EveryCount = tt.fields.EveryCount
+			w.Gap = tt.fields.Gap
+			w.MaxDuration = tt.fields.MaxDuration
 			MarshalTestHelper(t, w, tt.wantErr, tt.want)
 		})
 	}
@@ -84,6 +96,14 @@ func TestWindowNode_UnmarshalJSON(t *testing.T) {
 				Every:  time.Minute,
 			},
 		},
+		{
+			name:  "session window",
+			input: `{"typeOf":"window","id":"0","period":"0s","every":"0s","align":false,"fillPeriod":false,"periodCount":0,"everyCount":0,"gap":"5m","maxDuration":"1h"}`,
+			want: &WindowNode{
+				Gap:         5 * time.Minute,
+				MaxDuration: time.Hour,
+			},
+		},
 		{
 			name:  "set id correctly",
 			input: `{"typeOf":"window","id":"5","period":"1h","every":"1m","align":false,"fillPeriod":false,"periodCount":0,"everyCount":0}`,
@@ -0,0 +1,275 @@
+package kapacitor
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/tdigest"
+)
+
+type QuantilesNode struct {
+	node
+	q *pipeline.QuantilesNode
+
+	states groupSnapshotter[quantilesState, *quantilesGroup]
+}
+
+// Create a new quantiles node.
+func newQuantilesNode(et *ExecutingTask, n *pipeline.QuantilesNode, d NodeDiagnostic) (*QuantilesNode, error) {
+	qn := &QuantilesNode{
+		node: node{Node: n, et: et, diag: d},
+		q:    n,
+	}
+	qn.node.runF = qn.runQuantiles
+	return qn, nil
+}
+
+func (n *QuantilesNode) runQuantiles(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore quantiles state", err)
+		}
+	}
+	consumer := edge.NewGroupedConsumer(
+		n.ins[0],
+		n,
+	)
+	n.statMap.Set(statCardinalityGauge, consumer.CardinalityVar())
+	return consumer.Consume()
+}
+
+func (n *QuantilesNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.Receiver, error) {
+	return edge.NewReceiverFromForwardReceiverWithStats(
+		n.outs,
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, n.newGroup(group))),
+	), nil
+}
+
+func (n *QuantilesNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *QuantilesNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
+func (n *QuantilesNode) newGroup(group edge.GroupInfo) *quantilesGroup {
+	return &quantilesGroup{
+		n:      n,
+		group:  group,
+		sketch: tdigest.NewWithCompression(n.q.Compression),
+	}
+}
+
+type quantilesGroup struct {
+	n     *QuantilesNode
+	group edge.GroupInfo
+
+	name string
+	// Start of the current period for stream data, or the time of the current batch.
+	time   time.Time
+	sketch *tdigest.TDigest
+}
+
+type quantilesState struct {
+	Name   string
+	Time   time.Time
+	Sketch string
+}
+
+func (g *quantilesGroup) snapshot() quantilesState {
+	state := quantilesState{
+		Name: g.name,
+		Time: g.time,
+	}
+	if g.sketch.Count() > 0 {
+		state.Sketch = marshalSketch(g.sketch)
+	}
+	return state
+}
+
+func (g *quantilesGroup) restore(state quantilesState) {
+	g.name = state.Name
+	g.time = state.Time
+	g.sketch.Reset()
+	if state.Sketch == "" {
+		return
+	}
+	centroids, err := unmarshalSketch(state.Sketch)
+	if err != nil {
+		g.n.diag.Error("failed to restore quantiles sketch", err)
+		return
+	}
+	g.sketch.AddCentroidList(centroids)
+}
+
+func (g *quantilesGroup) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
+	g.name = begin.Name()
+	g.time = begin.Time()
+	g.sketch.Reset()
+	return nil, nil
+}
+
+func (g *quantilesGroup) BatchPoint(bp edge.BatchPointMessage) (edge.Message, error) {
+	g.add(bp)
+	return nil, nil
+}
+
+func (g *quantilesGroup) EndBatch(end edge.EndBatchMessage) (edge.Message, error) {
+	if g.sketch.Count() == 0 {
+		return nil, nil
+	}
+	return g.emit(), nil
+}
+
+func (g *quantilesGroup) Point(p edge.PointMessage) (edge.Message, error) {
+	period := g.n.q.Period
+	if period == 0 {
+		if !g.add(p) {
+			return nil, nil
+		}
+		g.name = p.Name()
+		g.time = p.Time()
+		return g.emit(), nil
+	}
+
+	var msg edge.Message
+	// Points older than the current period are added to the current period.
+	if start := p.Time().Truncate(period); start.After(g.time) {
+		if g.sketch.Count() > 0 {
+			msg = g.emit()
+			g.sketch.Reset()
+		}
+		g.time = start
+	}
+	g.name = p.Name()
+	g.add(p)
+	return msg, nil
+}
+
+// add adds the value of the field of p to the sketch, or merges the sketch it contains.
+// Reports whether the sketch was modified.
+func (g *quantilesGroup) add(p edge.FieldsTagsTimeGetter) bool {
+	field := g.n.q.Field
+	value := p.Fields()[field]
+	if g.n.q.MergeFlag {
+		s, ok := value.(string)
+		if !ok {
+			g.error(errors.New("field is missing or is not a sketch"), value)
+			return false
+		}
+		centroids, err := unmarshalSketch(s)
+		if err != nil {
+			g.error(err, value)
+			return false
+		}
+		g.sketch.AddCentroidList(centroids)
+		return true
+	}
+	f, ok := numToFloat(value)
+	if !ok {
+		g.error(errors.New("field is missing or the wrong type"), value)
+		return false
+	}
+	g.sketch.Add(f, 1)
+	return true
+}
+
+func (g *quantilesGroup) error(err error, value interface{}) {
+	g.n.diag.Error("cannot compute quantiles", err,
+		keyvalue.KV("field", g.n.q.Field),
+		keyvalue.KV("type", fmt.Sprintf("%T", value)),
+	)
+}
+
+func (g *quantilesGroup) emit() edge.PointMessage {
+	q := g.n.q
+	fields := make(models.Fields, len(q.Quantiles)+1)
+	for i, quantile := range q.Quantiles {
+		fields[q.Names[i]] = g.sketch.Quantile(quantile)
+	}
+	if q.SketchField != "" {
+		fields[q.SketchField] = marshalSketch(g.sketch)
+	}
+	return edge.NewPointMessage(
+		g.name, "", "",
+		g.group.Dimensions,
+		fields,
+		g.group.Tags,
+		g.time,
+	)
+}
+
+// Barrier emits the current period once the barrier is past its end,
+// so that the last period of an idle group is not held back until its next point.
+func (g *quantilesGroup) Barrier(b edge.BarrierMessage) (edge.Message, error) {
+	period := g.n.q.Period
+	if period == 0 || g.sketch.Count() == 0 || b.Time().Before(g.time.Add(period)) {
+		return b, nil
+	}
+	msg := g.emit()
+	g.sketch.Reset()
+	g.time = b.Time().Truncate(period)
+	return msg, nil
+}
+func (g *quantilesGroup) DeleteGroup(d edge.DeleteGroupMessage) (edge.Message, error) {
+	return d, nil
+}
+func (g *quantilesGroup) Done() {}
+
+// Version of the serialized sketch format.
+const sketchVersion = 1
+
+// marshalSketch serializes the centroids of the sketch as a base64 encoded string.
+//
+// The format is a version byte, the compression as a float64, the number of centroids as a uint32
+// followed by the mean and weight of each centroid as float64s, all little endian.
+// The compression is informational, sketches are merged with the compression of the node reading them.
+func marshalSketch(s *tdigest.TDigest) string {
+	centroids := s.Centroids(nil)
+	buf := make([]byte, 13+16*len(centroids))
+	buf[0] = sketchVersion
+	binary.LittleEndian.PutUint64(buf[1:], math.Float64bits(s.Compression))
+	binary.LittleEndian.PutUint32(buf[9:], uint32(len(centroids)))
+	for i, c := range centroids {
+		binary.LittleEndian.PutUint64(buf[13+16*i:], math.Float64bits(c.Mean))
+		binary.LittleEndian.PutUint64(buf[21+16*i:], math.Float64bits(c.Weight))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// unmarshalSketch decodes the centroids of a sketch serialized by marshalSketch.
+// The serialized compression is ignored since sketches come from untrusted point data
+// and the compression determines the memory a digest allocates.
+func unmarshalSketch(s string) (tdigest.CentroidList, error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sketch: %v", err)
+	}
+	if len(buf) < 13 {
+		return nil, errors.New("invalid sketch: too short")
+	}
+	if buf[0] != sketchVersion {
+		return nil, fmt.Errorf("invalid sketch: unsupported version %d", buf[0])
+	}
+	l := binary.LittleEndian.Uint32(buf[9:])
+	if uint64(len(buf)) != 13+16*uint64(l) {
+		return nil, errors.New("invalid sketch: length does not match the number of centroids")
+	}
+	centroids := make(tdigest.CentroidList, l)
+	for i := range centroids {
+		centroids[i] = tdigest.Centroid{
+			Mean:   math.Float64frombits(binary.LittleEndian.Uint64(buf[13+16*i:])),
+			Weight: math.Float64frombits(binary.LittleEndian.Uint64(buf[21+16*i:])),
+		}
+	}
+	return centroids, nil
+}
@@ -0,0 +1,213 @@
+package kapacitor
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/influxdata/tdigest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantiles(t *testing.T) {
+	group := snapshotTestGroup()
+	newGroup := func(t *testing.T, configure func(*pipeline.QuantilesNode)) edge.ForwardReceiver {
+		t.Helper()
+		stream := &pipeline.StreamNode{}
+		pipeline.CreatePipelineSources(stream)
+		pn := stream.From().Quantiles("value", 0.5, 0.99)
+		configure(pn)
+		n, err := newQuantilesNode(nil, pn, nil)
+		require.NoError(t, err)
+		return n.states.newGroup(group.ID, n.newGroup(group))
+	}
+	point := func(sec int64, fields models.Fields) edge.PointMessage {
+		return edge.NewPointMessage(
+			"cpu", "db", "rp",
+			group.Dimensions,
+			fields,
+			group.Tags,
+			time.Unix(sec, 0).UTC(),
+		)
+	}
+
+	t.Run("stream period", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.Period = time.Minute
+		})
+		for i := 1; i <= 100; i++ {
+			m, err := g.Point(point(60+int64(i%60), models.Fields{"value": float64(i)}))
+			require.NoError(t, err)
+			require.Nil(t, m)
+		}
+		m, err := g.Point(point(125, models.Fields{"value": 1.0}))
+		require.NoError(t, err)
+		p, ok := m.(edge.PointMessage)
+		require.True(t, ok, "unexpected message %v", m)
+		assert.Equal(t, time.Unix(60, 0).UTC(), p.Time())
+		assert.Equal(t, "cpu", p.Name())
+		assert.Equal(t, group.Tags, p.Tags())
+		assert.InDelta(t, 50.5, p.Fields()["p50"], 0.5)
+		assert.InDelta(t, 99.5, p.Fields()["p99"], 0.5)
+		assert.Len(t, p.Fields(), 2)
+	})
+	t.Run("stream without period", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.As("median", "max")
+		})
+		var p edge.PointMessage
+		for i, v := range []float64{3, 1, 2} {
+			m, err := g.Point(point(int64(i), models.Fields{"value": v}))
+			require.NoError(t, err)
+			p = m.(edge.PointMessage)
+			assert.Equal(t, time.Unix(int64(i), 0).UTC(), p.Time())
+		}
+		assert.Equal(t, 2.0, p.Fields()["median"])
+	})
+	t.Run("batch", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.SketchField = "sketch"
+		})
+		tmax := time.Unix(100, 0).UTC()
+		for i := 0; i < 2; i++ {
+			m, err := g.BeginBatch(edge.NewBeginBatchMessage("cpu", group.Tags, false, tmax, 10))
+			require.NoError(t, err)
+			require.Nil(t, m)
+			for j := 1; j <= 10; j++ {
+				m, err := g.BatchPoint(edge.NewBatchPointMessage(models.Fields{"value": float64(j * (i + 1))}, group.Tags, tmax))
+				require.NoError(t, err)
+				require.Nil(t, m)
+			}
+			m, err = g.EndBatch(edge.NewEndBatchMessage())
+			require.NoError(t, err)
+			p := m.(edge.PointMessage)
+			assert.Equal(t, tmax, p.Time())
+			// Each batch is computed independently.
+			assert.InDelta(t, 5.5*float64(i+1), p.Fields()["p50"], 0.001)
+			centroids, err := unmarshalSketch(p.Fields()["sketch"].(string))
+			require.NoError(t, err)
+			sketch := tdigest.New()
+			sketch.AddCentroidList(centroids)
+			assert.Equal(t, 10.0, sketch.Count())
+		}
+	})
+	t.Run("merge", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.Merge()
+		})
+		low, high := tdigest.NewWithCompression(100), tdigest.NewWithCompression(100)
+		for i := 1; i <= 50; i++ {
+			low.Add(float64(i), 1)
+			high.Add(float64(i+50), 1)
+		}
+		_, err := g.Point(point(0, models.Fields{"value": marshalSketch(low)}))
+		require.NoError(t, err)
+		m, err := g.Point(point(0, models.Fields{"value": marshalSketch(high)}))
+		require.NoError(t, err)
+		assert.InDelta(t, 50.5, m.(edge.PointMessage).Fields()["p50"], 0.5)
+	})
+	t.Run("merge hostile compression", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.Merge()
+		})
+		s := tdigest.NewWithCompression(100)
+		for i := 1; i <= 100; i++ {
+			s.Add(float64(i), 1)
+		}
+		// A sketch claiming a huge compression must not size the digest it is merged into.
+		buf, err := base64.StdEncoding.DecodeString(marshalSketch(s))
+		require.NoError(t, err)
+		binary.LittleEndian.PutUint64(buf[1:], math.Float64bits(1e10))
+		m, err := g.Point(point(0, models.Fields{"value": base64.StdEncoding.EncodeToString(buf)}))
+		require.NoError(t, err)
+		assert.InDelta(t, 50.5, m.(edge.PointMessage).Fields()["p50"], 0.5)
+	})
+	t.Run("barrier flushes period", func(t *testing.T) {
+		g := newGroup(t, func(q *pipeline.QuantilesNode) {
+			q.Period = time.Minute
+		})
+		for i := 1; i <= 10; i++ {
+			_, err := g.Point(point(60+int64(i), models.Fields{"value": float64(i)}))
+			require.NoError(t, err)
+		}
+		b := edge.NewBarrierMessage(group, time.Unix(100, 0).UTC())
+		m, err := g.Barrier(b)
+		require.NoError(t, err)
+		assert.Equal(t, b, m, "barrier within the period must be forwarded")
+
+		m, err = g.Barrier(edge.NewBarrierMessage(group, time.Unix(125, 0).UTC()))
+		require.NoError(t, err)
+		p, ok := m.(edge.PointMessage)
+		require.True(t, ok, "unexpected message %v", m)
+		assert.Equal(t, time.Unix(60, 0).UTC(), p.Time())
+		assert.InDelta(t, 5.5, p.Fields()["p50"], 0.5)
+
+		// The emitted period is not emitted again.
+		b = edge.NewBarrierMessage(group, time.Unix(200, 0).UTC())
+		m, err = g.Barrier(b)
+		require.NoError(t, err)
+		assert.Equal(t, b, m)
+	})
+}
+
+func TestQuantiles_SnapshotRestore(t *testing.T) {
+	group := snapshotTestGroup()
+	stream := &pipeline.StreamNode{}
+	pipeline.CreatePipelineSources(stream)
+	pn := stream.From().Quantiles("value", 0.5, 0.9)
+	pn.Period = time.Hour
+	newNode := func() *QuantilesNode {
+		n, err := newQuantilesNode(nil, pn, nil)
+		require.NoError(t, err)
+		return n
+	}
+
+	n := newNode()
+	g := n.states.newGroup(group.ID, n.newGroup(group))
+	for i := 0; i < 20; i++ {
+		_, err := g.Point(snapshotTestPoint(group, i))
+		require.NoError(t, err)
+	}
+	data, err := n.snapshot()
+	require.NoError(t, err)
+	next := snapshotTestPoint(group, 3600)
+	expected, err := g.Point(next)
+	require.NoError(t, err)
+
+	restored := newNode()
+	require.NoError(t, restored.restore(data))
+	g = restored.states.newGroup(group.ID, restored.newGroup(group))
+	got, err := g.Point(next)
+	require.NoError(t, err)
+
+	require.NotNil(t, expected)
+	assert.Equal(t, expected, got)
+}
+
+func TestUnmarshalSketch(t *testing.T) {
+	s := tdigest.NewWithCompression(50)
+	for i := 0; i < 1000; i++ {
+		s.Add(float64(i), 1)
+	}
+	got, err := unmarshalSketch(marshalSketch(s))
+	require.NoError(t, err)
+	assert.Equal(t, s.Centroids(nil), got)
+
+	for _, invalid := range []string{
+		"not base64!",
+		"",
+		// Version 2
+		"AgAAAAAAAFlAAAAAAA==",
+		// Two centroids without data
+		"AQAAAAAAAFlAAgAAAA==",
+	} {
+		_, err := unmarshalSketch(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/influxdata/kapacitor/services/auth"
 	"github.com/influxdata/kapacitor/services/azure"
 	"github.com/influxdata/kapacitor/services/bigpanda"
+	"github.com/influxdata/kapacitor/services/blobstore"
 	"github.com/influxdata/kapacitor/services/config"
 	"github.com/influxdata/kapacitor/services/consul"
 	"github.com/influxdata/kapacitor/services/deadman"
@@ -35,6 +36,7 @@ import (
 	"github.com/influxdata/kapacitor/services/influxdb"
 	"github.com/influxdata/kapacitor/services/k8s"
 	"github.com/influxdata/kapacitor/services/kafka"
+	"github.com/influxdata/kapacitor/services/kafka_consumer"
 	"github.com/influxdata/kapacitor/services/load"
 	"github.com/influxdata/kapacitor/services/marathon"
 	"github.com/influxdata/kapacitor/services/mqtt"
@@ -46,6 +48,7 @@ import (
 	"github.com/influxdata/kapacitor/services/pushover"
 	"github.com/influxdata/kapacitor/services/replay"
 	"github.com/influxdata/kapacitor/services/reporting"
+	"github.com/influxdata/kapacitor/services/schedule"
 	"github.com/influxdata/kapacitor/services/scraper"
 	"github.com/influxdata/kapacitor/services/sensu"
 	"github.com/influxdata/kapacitor/services/serverset"
@@ -53,6 +56,7 @@ import (
 	"github.com/influxdata/kapacitor/services/slack"
 	"github.com/influxdata/kapacitor/services/smtp"
 	"github.com/influxdata/kapacitor/services/snmptrap"
+	sqlservice "github.com/influxdata/kapacitor/services/sql"
 	"github.com/influxdata/kapacitor/services/static_discovery"
 	"github.com/influxdata/kapacitor/services/stats"
 	"github.com/influxdata/kapacitor/services/storage"
@@ -73,18 +77,21 @@ import (
 
 // Config represents the configuration format for the kapacitord binary.
 type Config struct {
-	Alert          alert.Config      `toml:"alert"`
-	Auth           auth.Config       `toml:"auth"`
-	HTTP           httpd.Config      `toml:"http"`
-	Replay         replay.Config     `toml:"replay"`
-	Storage        storage.Config    `toml:"storage"`
-	Task           task_store.Config `toml:"task"`
-	FluxTask       task.Config       `toml:"fluxtask"`
-	Load           load.Config       `toml:"load"`
-	InfluxDB       []influxdb.Config `toml:"influxdb" override:"influxdb,element-key=name"`
-	Logging        diagnostic.Config `toml:"logging"`
-	ConfigOverride config.Config     `toml:"config-override"`
-	TLS            tlsconfig.Config  `toml:"tls"`
+	Alert          alert.Config       `toml:"alert"`
+	Auth           auth.Config        `toml:"auth"`
+	HTTP           httpd.Config       `toml:"http"`
+	Replay         replay.Config      `toml:"replay"`
+	Storage        storage.Config     `toml:"storage"`
+	BlobStore      blobstore.Config   `toml:"blobstore"`
+	Task           task_store.Config  `toml:"task"`
+	FluxTask       task.Config        `toml:"fluxtask"`
+	Load           load.Config        `toml:"load"`
+	Schedules      schedule.Configs   `toml:"schedule"`
+	SQL            sqlservice.Configs `toml:"sql"`
+	InfluxDB       []influxdb.Config  `toml:"influxdb" override:"influxdb,element-key=name"`
+	Logging        diagnostic.Config  `toml:"logging"`
+	ConfigOverride config.Config      `toml:"config-override"`
+	TLS            tlsconfig.Config   `toml:"tls"`
 
 	// Input services
 	Graphite []graphite.Config `toml:"graphite"`
@@ -92,6 +99,8 @@ type Config struct {
 	OpenTSDB opentsdb.Config   `toml:"opentsdb"`
 	UDP      []udp.Config      `toml:"udp"`
 
+	KafkaConsumer []kafka_consumer.Config `toml:"kafka-consumer"`
+
 	// Alert handlers
 	Alerta     alerta.Config     `toml:"alerta" override:"alerta"`
 	BigPanda   bigpanda.Config   `toml:"bigpanda" override:"bigpanda"`
@@ -158,6 +167,7 @@ func NewConfig() *Config {
 	c.Auth = auth.NewDisabledConfig()
 	c.HTTP = httpd.NewConfig()
 	c.Storage = storage.NewConfig()
+	c.BlobStore = blobstore.NewConfig()
 	c.Replay = replay.NewConfig()
 	c.Task = task_store.NewConfig()
 	c.FluxTask = task.NewConfig()
@@ -242,6 +252,9 @@ func (c *Config) Validate() error {
 	if err := c.Storage.Validate(); err != nil {
 		return errors.Wrap(err, "storage")
 	}
+	if err := c.BlobStore.Validate(); err != nil {
+		return errors.Wrap(err, "blobstore")
+	}
 	if err := c.HTTP.Validate(); err != nil {
 		return errors.Wrap(err, "http")
 	}
@@ -257,6 +270,15 @@ func (c *Config) Validate() error {
 	if err := c.Load.Validate(); err != nil {
 		return err
 	}
+	if err := c.Alert.Validate(); err != nil {
+		return errors.Wrap(err, "alert")
+	}
+	if err := c.Schedules.Validate(); err != nil {
+		return errors.Wrap(err, "schedule")
+	}
+	if err := c.SQL.Validate(); err != nil {
+		return errors.Wrap(err, "sql")
+	}
 	// Validate the set of InfluxDB configs.
 	// All names should be unique.
 	names := make(map[string]bool, len(c.InfluxDB))
@@ -293,6 +315,20 @@ func (c *Config) Validate() error {
 			return errors.Wrap(err, "graphite")
 		}
 	}
+	consumerIDs := make(map[string]bool, len(c.KafkaConsumer))
+	for i := range c.KafkaConsumer {
+		c.KafkaConsumer[i].ApplyConditionalDefaults()
+		k := c.KafkaConsumer[i]
+		if err := k.Validate(); err != nil {
+			return errors.Wrap(err, "kafka-consumer")
+		}
+		if k.Enabled {
+			if consumerIDs[k.ID] {
+				return fmt.Errorf("kafka-consumer: duplicate id %q", k.ID)
+			}
+			consumerIDs[k.ID] = true
+		}
+	}
 
 	// Validate alert handlers
 	if err := c.Alerta.Validate(); err != nil {
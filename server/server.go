@@ -31,9 +31,11 @@ import (
 	authservice "github.com/influxdata/kapacitor/services/auth"
 	"github.com/influxdata/kapacitor/services/azure"
 	"github.com/influxdata/kapacitor/services/bigpanda"
+	"github.com/influxdata/kapacitor/services/blobstore"
 	"github.com/influxdata/kapacitor/services/config"
 	"github.com/influxdata/kapacitor/services/consul"
 	"github.com/influxdata/kapacitor/services/deadman"
+	"github.com/influxdata/kapacitor/services/debugsession"
 	"github.com/influxdata/kapacitor/services/diagnostic"
 	"github.com/influxdata/kapacitor/services/discord"
 	"github.com/influxdata/kapacitor/services/dns"
@@ -47,6 +49,7 @@ import (
 	"github.com/influxdata/kapacitor/services/influxdb"
 	"github.com/influxdata/kapacitor/services/k8s"
 	"github.com/influxdata/kapacitor/services/kafka"
+	"github.com/influxdata/kapacitor/services/kafka_consumer"
 	"github.com/influxdata/kapacitor/services/load"
 	"github.com/influxdata/kapacitor/services/marathon"
 	"github.com/influxdata/kapacitor/services/mqtt"
@@ -59,6 +62,7 @@ import (
 	"github.com/influxdata/kapacitor/services/pushover"
 	"github.com/influxdata/kapacitor/services/replay"
 	"github.com/influxdata/kapacitor/services/reporting"
+	"github.com/influxdata/kapacitor/services/schedule"
 	"github.com/influxdata/kapacitor/services/scraper"
 	"github.com/influxdata/kapacitor/services/sensu"
 	"github.com/influxdata/kapacitor/services/serverset"
@@ -68,12 +72,14 @@ import (
 	"github.com/influxdata/kapacitor/services/slack"
 	"github.com/influxdata/kapacitor/services/smtp"
 	"github.com/influxdata/kapacitor/services/snmptrap"
+	sqlservice "github.com/influxdata/kapacitor/services/sql"
 	"github.com/influxdata/kapacitor/services/static_discovery"
 	"github.com/influxdata/kapacitor/services/stats"
 	"github.com/influxdata/kapacitor/services/storage"
 	"github.com/influxdata/kapacitor/services/swarm"
 	"github.com/influxdata/kapacitor/services/talk"
 	"github.com/influxdata/kapacitor/services/task_store"
+	"github.com/influxdata/kapacitor/services/tasktest"
 	"github.com/influxdata/kapacitor/services/teams"
 	"github.com/influxdata/kapacitor/services/telegram"
 	"github.com/influxdata/kapacitor/services/triton"
@@ -135,9 +141,14 @@ type Server struct {
 	AuthService           auth.Interface
 	HTTPDService          *httpd.Service
 	StorageService        *storage.Service
+	BlobStoreService      *blobstore.Service
+	ScheduleService       *schedule.Service
+	SQLService            *sqlservice.Service
 	AlertService          *alert.Service
 	TaskStore             *task_store.Service
 	ReplayService         *replay.Service
+	TaskTestService       *tasktest.Service
+	DebugSessionService   *debugsession.Service
 	SessionService        *diagnostic.SessionService
 	InfluxDBService       *influxdb.Service
 	ConfigOverrideService *config.Service
@@ -251,7 +262,10 @@ func New(c *Config, buildInfo BuildInfo, diagService *diagnostic.Service, disabl
 
 	s.appendConfigOverrideService()
 	s.appendTesterService()
+	s.appendBlobStoreService()
+	s.appendScheduleService()
 	s.appendSideloadService()
+	s.appendSQLService()
 
 	// Init alert service
 	s.initAlertService()
@@ -312,6 +326,8 @@ func New(c *Config, buildInfo BuildInfo, diagService *diagnostic.Service, disabl
 	// Append these after InfluxDB because they depend on it
 	s.appendTaskStoreService()
 	s.appendReplayService()
+	s.appendTaskTestService()
+	s.appendDebugSessionService()
 	s.appendSessionService()
 
 	// Append third-party integrations
@@ -320,6 +336,7 @@ func New(c *Config, buildInfo BuildInfo, diagService *diagnostic.Service, disabl
 		return nil, errors.Wrap(err, "collectd service")
 	}
 	s.appendUDPServices()
+	s.appendKafkaConsumerServices()
 	if err := s.appendOpenTSDBService(); err != nil {
 		return nil, errors.Wrap(err, "opentsdb service")
 	}
@@ -395,6 +412,16 @@ func (s *Server) appendStorageService() {
 	s.AppendService("storage", srv)
 }
 
+func (s *Server) appendBlobStoreService() {
+	d := s.DiagService.NewBlobStoreHandler()
+	srv := blobstore.NewService(s.config.BlobStore, d)
+	srv.StorageService = s.StorageService
+	srv.HTTPDService = s.HTTPDService
+
+	s.BlobStoreService = srv
+	s.AppendService("blobstore", srv)
+}
+
 func (s *Server) appendConfigOverrideService() {
 	d := s.DiagService.NewConfigOverrideHandler()
 	srv := config.NewService(s.config.ConfigOverride, s.config, d, s.configUpdates)
@@ -413,6 +440,7 @@ func (s *Server) initAlertService() {
 	srv.HTTPDService = s.HTTPDService
 	srv.StorageService = s.StorageService
 	srv.PersistTopics = s.config.Alert.PersistTopics
+	srv.HistoryRetention = time.Duration(s.config.Alert.HistoryRetention)
 	s.AlertService = srv
 	s.TaskMaster.AlertService = srv
 }
@@ -548,6 +576,7 @@ func (s *Server) appendTaskStoreService() {
 
 	s.TaskStore = srv
 	s.TaskMaster.TaskStore = srv
+	s.TaskMaster.ShadowLog = srv
 	s.AppendService("task_store", srv)
 }
 
@@ -572,6 +601,24 @@ func (s *Server) appendReplayService() {
 	s.AppendService("replay", srv)
 }
 
+func (s *Server) appendTaskTestService() {
+	srv := tasktest.NewService()
+	srv.HTTPDService = s.HTTPDService
+	srv.TaskMaster = s.TaskMaster
+
+	s.TaskTestService = srv
+	s.AppendService("tasktest", srv)
+}
+
+func (s *Server) appendDebugSessionService() {
+	srv := debugsession.NewService()
+	srv.HTTPDService = s.HTTPDService
+	srv.TaskMasterLookup = s.TaskMasterLookup
+
+	s.DebugSessionService = srv
+	s.AppendService("debugsession", srv)
+}
+
 func (s *Server) appendK8sService() error {
 	c := s.config.Kubernetes
 	d := s.DiagService.NewK8sHandler()
@@ -611,6 +658,25 @@ func (s *Server) appendEC2Service() error {
 	s.AppendService("ec2", srv)
 	return nil
 }
+func (s *Server) appendScheduleService() {
+	d := s.DiagService.NewScheduleHandler()
+	srv := schedule.NewService(s.config.Schedules, d)
+	srv.StorageService = s.StorageService
+	srv.HTTPDService = s.HTTPDService
+
+	s.ScheduleService = srv
+	s.TaskMaster.ScheduleLookup = srv
+	s.AppendService("schedule", srv)
+}
+
+func (s *Server) appendSQLService() {
+	srv := sqlservice.NewService(s.config.SQL)
+
+	s.SQLService = srv
+	s.TaskMaster.SQLService = srv
+	s.AppendService("sql", srv)
+}
+
 func (s *Server) appendDeadmanService() {
 	d := s.DiagService.NewDeadmanHandler()
 	srv := deadman.NewService(s.config.Deadman, d)
@@ -622,6 +688,7 @@ func (s *Server) appendDeadmanService() {
 func (s *Server) appendUDFService() {
 	d := s.DiagService.NewUDFServiceHandler()
 	srv := udf.NewService(s.config.UDF, d)
+	srv.BlobStoreService = s.BlobStoreService
 
 	s.TaskMaster.UDFService = srv
 	s.AppendService("udf", srv)
@@ -660,6 +727,7 @@ func (s *Server) appendMQTTService() error {
 	if err != nil {
 		return err
 	}
+	srv.PointsWriter = s.TaskMaster
 
 	s.TaskMaster.MQTTService = srv
 	s.AlertService.MQTTService = srv
@@ -952,6 +1020,18 @@ func (s *Server) appendUDPServices() {
 	}
 }
 
+func (s *Server) appendKafkaConsumerServices() {
+	for _, c := range s.config.KafkaConsumer {
+		if !c.Enabled {
+			continue
+		}
+		d := s.DiagService.NewKafkaConsumerHandler()
+		srv := kafka_consumer.NewService(c, d)
+		srv.PointsWriter = s.TaskMaster
+		s.AppendService("kafka_consumer_"+c.ID, srv)
+	}
+}
+
 func (s *Server) appendStatsService() {
 	c := s.config.Stats
 	if c.Enabled {
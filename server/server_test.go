@@ -1918,6 +1918,90 @@ func TestServer_DeleteTemplate(t *testing.T) {
 	}
 }
 
+func TestServer_Library(t *testing.T) {
+	s, cli := OpenDefaultServer(t)
+	defer s.Close()
+
+	id := "testLibraryID"
+	libTick := `def logged() =
+    |log()
+`
+	library, err := cli.CreateLibrary(client.CreateLibraryOptions{
+		ID:         id,
+		TICKscript: libTick,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	li, err := cli.Library(library.Link, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if li.ID != id {
+		t.Fatalf("unexpected id got %s exp %s", li.ID, id)
+	}
+	if li.TICKscript != libTick {
+		t.Fatalf("unexpected TICKscript got\n%s\nexp\n%s\n", li.TICKscript, libTick)
+	}
+
+	// Libraries may only contain definitions
+	if _, err := cli.UpdateLibrary(library.Link, client.UpdateLibraryOptions{TICKscript: "var x = 5"}); err == nil {
+		t.Fatal("expected error updating library with a var declaration")
+	}
+
+	tick := `import 'testLibraryID'
+
+stream
+    |from()
+        .measurement('test')
+    @logged()
+`
+	task, err := cli.CreateTask(client.CreateTaskOptions{
+		ID:         "testTaskID",
+		Type:       client.StreamTask,
+		DBRPs:      []client.DBRP{{Database: "mydb", RetentionPolicy: "myrp"}},
+		TICKscript: tick,
+		Status:     client.Disabled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ti, err := cli.Task(task.Link, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Error != "" {
+		t.Fatal(ti.Error)
+	}
+	dot := "digraph testTaskID {\nstream0 -> from1;\nfrom1 -> log2;\n}"
+	if ti.Dot != dot {
+		t.Fatalf("unexpected dot\ngot\n%s\nexp\n%s\n", ti.Dot, dot)
+	}
+
+	libraries, err := cli.ListLibraries(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(libraries) != 1 || libraries[0].ID != id {
+		t.Fatalf("unexpected libraries %v", libraries)
+	}
+
+	// Libraries imported by tasks cannot be deleted
+	if err := cli.DeleteLibrary(library.Link); err == nil {
+		t.Fatal("expected error deleting library imported by a task")
+	}
+	if err := cli.DeleteTask(task.Link); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.DeleteLibrary(library.Link); err != nil {
+		t.Fatal(err)
+	}
+	if li, err := cli.Library(library.Link, nil); err == nil {
+		t.Fatal("unexpected library:", li)
+	}
+}
+
 func TestServer_CreateTaskFromTemplate(t *testing.T) {
 	s, cli := OpenDefaultServer(t)
 	defer s.Close()
@@ -13281,6 +13365,7 @@ func TestServer_AlertTopic(t *testing.T) {
 		Collected:    0,
 		EventsLink:   client.Link{Relation: "events", Href: "/kapacitor/v1/alerts/topics/misc/events"},
 		HandlersLink: client.Link{Relation: "handlers", Href: "/kapacitor/v1/alerts/topics/misc/handlers"},
+		SilencesLink: client.Link{Relation: "silences", Href: "/kapacitor/v1/alerts/topics/misc/silences"},
 	}
 	topic, err := cli.Topic(cli.TopicLink("misc"))
 	if err != nil {
@@ -13324,6 +13409,7 @@ func TestServer_AlertListTopics(t *testing.T) {
 				Level:        "OK",
 				EventsLink:   client.Link{Relation: "events", Href: "/kapacitor/v1/alerts/topics/misc/events"},
 				HandlersLink: client.Link{Relation: "handlers", Href: "/kapacitor/v1/alerts/topics/misc/handlers"},
+				SilencesLink: client.Link{Relation: "silences", Href: "/kapacitor/v1/alerts/topics/misc/silences"},
 			},
 			{
 				Link:         client.Link{Relation: client.Self, Href: "/kapacitor/v1/alerts/topics/system"},
@@ -13331,6 +13417,7 @@ func TestServer_AlertListTopics(t *testing.T) {
 				Level:        "OK",
 				EventsLink:   client.Link{Relation: "events", Href: "/kapacitor/v1/alerts/topics/system/events"},
 				HandlersLink: client.Link{Relation: "handlers", Href: "/kapacitor/v1/alerts/topics/system/handlers"},
+				SilencesLink: client.Link{Relation: "silences", Href: "/kapacitor/v1/alerts/topics/system/silences"},
 			},
 			{
 				Link:         client.Link{Relation: client.Self, Href: "/kapacitor/v1/alerts/topics/test"},
@@ -13338,6 +13425,7 @@ func TestServer_AlertListTopics(t *testing.T) {
 				Level:        "OK",
 				EventsLink:   client.Link{Relation: "events", Href: "/kapacitor/v1/alerts/topics/test/events"},
 				HandlersLink: client.Link{Relation: "handlers", Href: "/kapacitor/v1/alerts/topics/test/handlers"},
+				SilencesLink: client.Link{Relation: "silences", Href: "/kapacitor/v1/alerts/topics/test/silences"},
 			},
 		},
 	}
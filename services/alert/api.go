@@ -8,11 +8,13 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/influxdata/kapacitor/alert"
 	client "github.com/influxdata/kapacitor/client/v1"
 	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/uuid"
 )
 
 const (
@@ -25,22 +27,37 @@ const (
 	topicsBasePathAnchored = httpd.BasePath + topicsPathAnchored
 
 	topicEventsPath           = "events"
+	topicEventAckPath         = "ack"
+	topicEventHistoryPath     = "history"
 	topicHandlersPath         = "handlers"
 	topicHandlersPathAnchored = topicHandlersPath + "/"
-
-	eventsPattern   = "*/" + topicEventsPath
-	eventPattern    = "*/" + topicEventsPath + "/*"
-	handlersPattern = "*/" + topicHandlersPath
-	handlerPattern  = "*/" + topicHandlersPath + "/*"
+	topicSilencesPath         = "silences"
+	deadLettersPath           = "dead-letters"
+	deadLettersReplayPath     = "replay"
+
+	eventsPattern       = "*/" + topicEventsPath
+	eventPattern        = "*/" + topicEventsPath + "/*"
+	eventAckPattern     = "*/" + topicEventsPath + "/*/" + topicEventAckPath
+	eventHistoryPattern = "*/" + topicEventsPath + "/*/" + topicEventHistoryPath
+	handlersPattern     = "*/" + topicHandlersPath
+	handlerPattern      = "*/" + topicHandlersPath + "/*"
+	silencesPattern     = "*/" + topicSilencesPath
+	silencePattern      = "*/" + topicSilencesPath + "/*"
+	deadLettersPattern  = "*/" + topicHandlersPath + "/*/" + deadLettersPath
+	replayPattern       = "*/" + topicHandlersPath + "/*/" + deadLettersPath + "/" + deadLettersReplayPath
 
 	eventsRelation   = "events"
 	handlersRelation = "handlers"
+	silencesRelation = "silences"
 )
 
 type apiServer struct {
 	Registrar    HandlerSpecRegistrar
 	Topics       Topics
 	Persister    TopicPersister
+	Silencer     Silencer
+	Historian    Historian
+	DeadLetters  DeadLetterer
 	routes       []httpd.Route
 	HTTPDService interface {
 		AddRoutes([]httpd.Route) error
@@ -182,11 +199,24 @@ func (s *apiServer) handleRouteTopicGet(w http.ResponseWriter, r *http.Request)
 	case pathMatch(eventPattern, p):
 		event := s.eventIDFromPath(p)
 		s.handleGetEvent(id, event, w, r)
+	case pathMatch(eventAckPattern, p):
+		event := s.eventIDFromPath(path.Dir(p))
+		s.handleGetAcknowledgement(id, event, w, r)
+	case pathMatch(eventHistoryPattern, p):
+		event := s.eventIDFromPath(path.Dir(p))
+		s.handleGetEventHistory(id, event, w, r)
 	case pathMatch(handlersPattern, p):
 		s.handleListHandlers(id, w, r)
 	case pathMatch(handlerPattern, p):
 		handler, _ := s.handlerIDFromPath(p)
 		s.handleGetHandler(id, handler, w, r)
+	case pathMatch(deadLettersPattern, p):
+		handler, _ := s.handlerIDFromPath(path.Dir(p))
+		s.handleListDeadLetters(id, handler, w, r)
+	case pathMatch(silencesPattern, p):
+		s.handleListSilences(id, w, r)
+	case pathMatch(silencePattern, p):
+		s.handleGetSilence(id, path.Base(p), w, r)
 	default:
 		s.handleGetTopic(id, w, r)
 	}
@@ -195,12 +225,27 @@ func (s *apiServer) handleRouteTopicGet(w http.ResponseWriter, r *http.Request)
 func (s *apiServer) handleRouteTopicPost(w http.ResponseWriter, r *http.Request) {
 	p := strings.TrimPrefix(r.URL.Path, topicsBasePathAnchored)
 	topic := s.topicIDFromPath(p)
-	s.handleCreateHandler(topic, w, r)
+	switch {
+	case pathMatch(silencesPattern, p):
+		s.handleCreateSilence(topic, w, r)
+	case pathMatch(eventAckPattern, p):
+		event := s.eventIDFromPath(path.Dir(p))
+		s.handleAcknowledgeEvent(topic, event, w, r)
+	case pathMatch(replayPattern, p):
+		handler, _ := s.handlerIDFromPath(path.Dir(path.Dir(p)))
+		s.handleReplayDeadLetters(topic, handler, w, r)
+	default:
+		s.handleCreateHandler(topic, w, r)
+	}
 }
 
 func (s *apiServer) handleRouteTopicPut(w http.ResponseWriter, r *http.Request) {
 	p := strings.TrimPrefix(r.URL.Path, topicsBasePathAnchored)
 	topic := s.topicIDFromPath(p)
+	if pathMatch(silencePattern, p) {
+		s.handlePutSilence(topic, path.Base(p), w, r)
+		return
+	}
 	handler, _ := s.handlerIDFromPath(p)
 	s.handlePutHandler(topic, handler, w, r)
 }
@@ -213,6 +258,19 @@ func (s *apiServer) handleRouteTopicPatch(w http.ResponseWriter, r *http.Request
 func (s *apiServer) handleRouteTopicDelete(w http.ResponseWriter, r *http.Request) {
 	p := strings.TrimPrefix(r.URL.Path, topicsBasePathAnchored)
 	topic := s.topicIDFromPath(p)
+	switch {
+	case pathMatch(silencePattern, p):
+		s.handleDeleteSilence(topic, path.Base(p), w, r)
+		return
+	case pathMatch(eventAckPattern, p):
+		event := s.eventIDFromPath(path.Dir(p))
+		s.handleUnacknowledgeEvent(topic, event, w, r)
+		return
+	case pathMatch(deadLettersPattern, p):
+		handler, _ := s.handlerIDFromPath(path.Dir(p))
+		s.handlePurgeDeadLetters(topic, handler, w, r)
+		return
+	}
 	handler, ok := s.handlerIDFromPath(p)
 	if !ok {
 		// We only have a topic path
@@ -232,6 +290,21 @@ func (s *apiServer) topicEventsLink(id string, r client.Relation) client.Link {
 func (s *apiServer) topicEventLink(topic, event string) client.Link {
 	return client.Link{Relation: client.Self, Href: path.Join(topicsBasePath, topic, topicEventsPath, event)}
 }
+func (s *apiServer) topicEventAckLink(topic, event string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(topicsBasePath, topic, topicEventsPath, event, topicEventAckPath)}
+}
+func (s *apiServer) topicEventHistoryLink(topic, event string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(topicsBasePath, topic, topicEventsPath, event, topicEventHistoryPath)}
+}
+func (s *apiServer) topicHandlerDeadLettersLink(topic, handler string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(topicsBasePath, topic, topicHandlersPath, handler, deadLettersPath)}
+}
+func (s *apiServer) topicSilencesLink(id string, r client.Relation) client.Link {
+	return client.Link{Relation: r, Href: path.Join(topicsBasePath, id, topicSilencesPath)}
+}
+func (s *apiServer) topicSilenceLink(topic, id string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(topicsBasePath, topic, topicSilencesPath, id)}
+}
 func (s *apiServer) topicHandlersLink(id string, r client.Relation) client.Link {
 	return client.Link{Relation: r, Href: path.Join(topicsBasePath, id, topicHandlersPath)}
 }
@@ -247,6 +320,7 @@ func (s *apiServer) createClientTopic(topic string, state alert.TopicState) clie
 		Collected:    state.Collected,
 		EventsLink:   s.topicEventsLink(topic, eventsRelation),
 		HandlersLink: s.topicHandlersLink(topic, handlersRelation),
+		SilencesLink: s.topicSilencesLink(topic, silencesRelation),
 	}
 }
 
@@ -305,10 +379,16 @@ func (s *apiServer) handleListEvents(topic string, w http.ResponseWriter, r *htt
 		Events: make([]client.TopicEvent, 0, len(events)),
 	}
 	for id, state := range events {
+		ack, err := s.eventAcknowledgement(topic, id)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("failed to get event acknowledgement: %s", err.Error()), true, http.StatusInternalServerError)
+			return
+		}
 		res.Events = append(res.Events, client.TopicEvent{
-			Link:  s.topicEventLink(topic, id),
-			ID:    id,
-			State: s.convertEventStateToClient(state),
+			Link:            s.topicEventLink(topic, id),
+			ID:              id,
+			State:           s.convertEventStateToClient(state),
+			Acknowledgement: ack,
 		})
 	}
 	w.WriteHeader(http.StatusOK)
@@ -325,15 +405,116 @@ func (s *apiServer) handleGetEvent(topic, eventID string, w http.ResponseWriter,
 		httpd.HttpError(w, fmt.Sprintf("unknown event %q in topic %q", eventID, topic), true, http.StatusNotFound)
 		return
 	}
+	ack, err := s.eventAcknowledgement(topic, eventID)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get event acknowledgement: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
 	event := client.TopicEvent{
-		Link:  s.topicEventLink(topic, eventID),
-		ID:    eventID,
-		State: s.convertEventStateToClient(state),
+		Link:            s.topicEventLink(topic, eventID),
+		ID:              eventID,
+		State:           s.convertEventStateToClient(state),
+		Acknowledgement: ack,
 	}
 	w.WriteHeader(http.StatusOK)
 	w.Write(httpd.MarshalJSON(event, true))
 }
 
+func (s *apiServer) handleGetEventHistory(topic, eventID string, w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	minLevel, err := alert.ParseLevel(q.Get("min-level"))
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	var start, stop time.Time
+	if str := q.Get("start"); str != "" {
+		start, err = time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid start time: %s", err.Error()), true, http.StatusBadRequest)
+			return
+		}
+	}
+	if str := q.Get("stop"); str != "" {
+		stop, err = time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid stop time: %s", err.Error()), true, http.StatusBadRequest)
+			return
+		}
+	}
+	history, err := s.Historian.EventHistory(topic, eventID, start, stop, minLevel)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get event history: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	res := client.TopicEventHistory{
+		Link:    s.topicEventHistoryLink(topic, eventID),
+		Topic:   topic,
+		ID:      eventID,
+		Entries: make([]client.EventHistoryEntry, len(history)),
+	}
+	for i, h := range history {
+		res.Entries[i] = client.EventHistoryEntry{
+			Time:          h.Time,
+			Level:         h.Level.String(),
+			PreviousLevel: h.PreviousLevel.String(),
+			Message:       h.Message,
+			Duration:      client.Duration(h.Duration),
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(res, true))
+}
+
+func (s *apiServer) handleListDeadLetters(topic, handler string, w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := s.DeadLetters.DeadLetters(topic, handler)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get dead letters: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	res := client.TopicHandlerDeadLetters{
+		Link:        s.topicHandlerDeadLettersLink(topic, handler),
+		Topic:       topic,
+		Handler:     handler,
+		DeadLetters: make([]client.DeadLetter, len(deadLetters)),
+	}
+	for i, d := range deadLetters {
+		res.DeadLetters[i] = client.DeadLetter{
+			ID:       d.ID,
+			Time:     d.Time,
+			Event:    d.Event.State.ID,
+			Level:    d.Event.State.Level.String(),
+			Message:  d.Event.State.Message,
+			Error:    d.Error,
+			Attempts: d.Attempts,
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(res, true))
+}
+
+func (s *apiServer) handleReplayDeadLetters(topic, handler string, w http.ResponseWriter, r *http.Request) {
+	n, err := s.DeadLetters.ReplayDeadLetters(topic, handler)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if err == ErrNoRetryPolicy {
+			code = http.StatusBadRequest
+		}
+		httpd.HttpError(w, fmt.Sprintf("failed to replay dead letters of handler %q: %s", handler, err.Error()), true, code)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(client.DeadLettersReplay{Replayed: n}, true))
+}
+
+func (s *apiServer) handlePurgeDeadLetters(topic, handler string, w http.ResponseWriter, r *http.Request) {
+	if err := s.DeadLetters.PurgeDeadLetters(topic, handler); err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to purge dead letters of handler %q: %s", handler, err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *apiServer) handleListHandlers(topic string, w http.ResponseWriter, r *http.Request) {
 	pattern := r.URL.Query().Get("pattern")
 	if err := validatePattern(pattern); err != nil {
@@ -508,3 +689,234 @@ func (s *apiServer) handleGetHandler(topic, handler string, w http.ResponseWrite
 	w.WriteHeader(http.StatusOK)
 	w.Write(httpd.MarshalJSON(h, true))
 }
+
+func (s *apiServer) convertAcknowledgement(ack Acknowledgement) client.EventAcknowledgement {
+	return client.EventAcknowledgement{
+		Link:    s.topicEventAckLink(ack.Topic, ack.Event),
+		Topic:   ack.Topic,
+		Event:   ack.Event,
+		Level:   ack.Level.String(),
+		Expires: ack.Expires,
+		Author:  ack.Author,
+		Comment: ack.Comment,
+		Created: ack.Created,
+	}
+}
+
+// eventAcknowledgement returns the acknowledgement of the event or nil if the event is not acknowledged.
+func (s *apiServer) eventAcknowledgement(topic, event string) (*client.EventAcknowledgement, error) {
+	ack, ok, err := s.Silencer.Acknowledgement(topic, event)
+	if err != nil || !ok {
+		return nil, err
+	}
+	ca := s.convertAcknowledgement(ack)
+	return &ca, nil
+}
+
+func (s *apiServer) handleGetAcknowledgement(topic, event string, w http.ResponseWriter, r *http.Request) {
+	ack, ok, err := s.Silencer.Acknowledgement(topic, event)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get acknowledgement: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("event %q in topic %q is not acknowledged", event, topic), true, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertAcknowledgement(ack), true))
+}
+
+func (s *apiServer) handleAcknowledgeEvent(topic, event string, w http.ResponseWriter, r *http.Request) {
+	opts := client.AcknowledgeEventOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+		httpd.HttpError(w, fmt.Sprint("invalid acknowledgement json: ", err.Error()), true, http.StatusBadRequest)
+		return
+	}
+	state, ok, err := s.Topics.EventState(topic, event)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get event state: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("unknown event %q in topic %q", event, topic), true, http.StatusNotFound)
+		return
+	}
+	if state.Level == alert.OK {
+		httpd.HttpError(w, fmt.Sprintf("cannot acknowledge event %q in topic %q, the event is OK", event, topic), true, http.StatusBadRequest)
+		return
+	}
+	now := time.Now().UTC()
+	if !opts.Expires.IsZero() && !opts.Expires.After(now) {
+		httpd.HttpError(w, "acknowledgement expiry must be in the future", true, http.StatusBadRequest)
+		return
+	}
+	ack := Acknowledgement{
+		Topic:   topic,
+		Event:   event,
+		Level:   state.Level,
+		Expires: opts.Expires,
+		Author:  opts.Author,
+		Comment: opts.Comment,
+		Created: now,
+	}
+	if err := s.Silencer.Acknowledge(ack); err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to acknowledge event: ", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertAcknowledgement(ack), true))
+}
+
+func (s *apiServer) handleUnacknowledgeEvent(topic, event string, w http.ResponseWriter, r *http.Request) {
+	if err := s.Silencer.Unacknowledge(topic, event); err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to unacknowledge event: ", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) convertSilence(silence Silence) client.TopicSilence {
+	matchers := make([]client.SilenceMatcher, len(silence.Matchers))
+	for i, m := range silence.Matchers {
+		matchers[i] = client.SilenceMatcher(m)
+	}
+	return client.TopicSilence{
+		Link:     s.topicSilenceLink(silence.Topic, silence.ID),
+		ID:       silence.ID,
+		Topic:    silence.Topic,
+		Matchers: matchers,
+		Expires:  silence.Expires,
+		Author:   silence.Author,
+		Comment:  silence.Comment,
+		Created:  silence.Created,
+	}
+}
+
+func (s *apiServer) silenceFromJSON(topic string, r io.Reader) (Silence, error) {
+	opts := client.TopicSilenceOptions{}
+	if err := json.NewDecoder(r).Decode(&opts); err != nil {
+		return Silence{}, err
+	}
+	matchers := make([]SilenceMatcher, len(opts.Matchers))
+	for i, m := range opts.Matchers {
+		matchers[i] = SilenceMatcher(m)
+	}
+	return Silence{
+		ID:       opts.ID,
+		Topic:    topic,
+		Matchers: matchers,
+		Expires:  opts.Expires,
+		Author:   opts.Author,
+		Comment:  opts.Comment,
+		Created:  time.Now().UTC(),
+	}, nil
+}
+
+type sortedSilences []client.TopicSilence
+
+func (s sortedSilences) Len() int               { return len(s) }
+func (s sortedSilences) Less(i int, j int) bool { return s[i].ID < s[j].ID }
+func (s sortedSilences) Swap(i int, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (s *apiServer) handleListSilences(topic string, w http.ResponseWriter, r *http.Request) {
+	silences, err := s.Silencer.Silences(topic)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to get silences: ", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	list := make([]client.TopicSilence, len(silences))
+	for i, silence := range silences {
+		list[i] = s.convertSilence(silence)
+	}
+	sort.Sort(sortedSilences(list))
+	ts := client.TopicSilences{
+		Link:     s.topicSilencesLink(topic, client.Self),
+		Topic:    topic,
+		Silences: list,
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(ts, true))
+}
+
+func (s *apiServer) handleGetSilence(topic, id string, w http.ResponseWriter, r *http.Request) {
+	silence, ok, err := s.Silencer.Silence(topic, id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get silence %q: %v", id, err), true, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("unknown silence: %q", id), true, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertSilence(silence), true))
+}
+
+func (s *apiServer) handleCreateSilence(topic string, w http.ResponseWriter, r *http.Request) {
+	silence, err := s.silenceFromJSON(topic, r.Body)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid silence json: ", err.Error()), true, http.StatusBadRequest)
+		return
+	}
+	if silence.ID == "" {
+		silence.ID = uuid.New().String()
+	}
+	if _, ok, err := s.Silencer.Silence(topic, silence.ID); err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get silence %q: %v", silence.ID, err), true, http.StatusInternalServerError)
+		return
+	} else if ok {
+		httpd.HttpError(w, fmt.Sprintf("silence %q already exists", silence.ID), true, http.StatusBadRequest)
+		return
+	}
+	s.putSilence(silence, w)
+}
+
+func (s *apiServer) handlePutSilence(topic, id string, w http.ResponseWriter, r *http.Request) {
+	old, ok, err := s.Silencer.Silence(topic, id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get silence %q: %v", id, err), true, http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("unknown silence: %q", id), true, http.StatusNotFound)
+		return
+	}
+	silence, err := s.silenceFromJSON(topic, r.Body)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid silence json: ", err.Error()), true, http.StatusBadRequest)
+		return
+	}
+	if silence.ID != "" && silence.ID != id {
+		httpd.HttpError(w, "cannot change the ID of a silence", true, http.StatusBadRequest)
+		return
+	}
+	silence.ID = id
+	silence.Created = old.Created
+	s.putSilence(silence, w)
+}
+
+func (s *apiServer) putSilence(silence Silence, w http.ResponseWriter) {
+	if err := silence.Validate(); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid silence: ", err.Error()), true, http.StatusBadRequest)
+		return
+	}
+	if !silence.Expires.After(time.Now()) {
+		httpd.HttpError(w, "silence expiry must be in the future", true, http.StatusBadRequest)
+		return
+	}
+	if err := s.Silencer.PutSilence(silence); err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to save silence: ", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertSilence(silence), true))
+}
+
+func (s *apiServer) handleDeleteSilence(topic, id string, w http.ResponseWriter, r *http.Request) {
+	if err := s.Silencer.DeleteSilence(topic, id); err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to delete silence: ", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package alert
 
 import (
+	"errors"
 	"time"
 
 	"github.com/influxdata/influxdb/toml"
@@ -9,21 +10,29 @@ import (
 
 const (
 	DefaultShutdownTimeout = toml.Duration(time.Second * 10)
+	// DefaultHistoryRetention is how long the state transitions of events are kept.
+	DefaultHistoryRetention = toml.Duration(7 * 24 * time.Hour)
 )
 
 type Config struct {
 	// Whether we persist the alert topics to BoltDB or not
 	PersistTopics     bool `toml:"persist-topics"`
 	TopicBufferLength int  `toml:"topic-buffer-length"`
+	// How long to keep the history of state transitions of events, zero disables the history.
+	HistoryRetention toml.Duration `toml:"history-retention"`
 }
 
 func NewConfig() Config {
 	return Config{
 		PersistTopics:     true,
 		TopicBufferLength: alert.DefaultEventBufferSize,
+		HistoryRetention:  DefaultHistoryRetention,
 	}
 }
 
 func (c Config) Validate() error {
+	if c.HistoryRetention < 0 {
+		return errors.New("history-retention must not be negative")
+	}
 	return nil
 }
@@ -3,10 +3,12 @@ package alert
 //go:generate easyjson dao.go
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"path"
 	"regexp"
+	"sort"
 	"time"
 
 	"github.com/mailru/easyjson/jlexer"
@@ -14,6 +16,7 @@ import (
 	"github.com/influxdata/kapacitor/alert"
 	"github.com/influxdata/kapacitor/services/storage"
 	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
 )
 
 var (
@@ -374,3 +377,588 @@ func (kv *topicStateKV) DeleteMultiple(keys []string) error {
 	}
 	return nil
 }
+
+var (
+	ErrNoSilenceExists         = errors.New("no silence exists")
+	ErrNoAcknowledgementExists = errors.New("no acknowledgement exists")
+)
+
+const (
+	silenceVersion         = 1
+	acknowledgementVersion = 1
+)
+
+// Matcher types of a SilenceMatcher.
+const (
+	MatcherTypeTag   = "tag"
+	MatcherTypeID    = "id"
+	MatcherTypeLevel = "level"
+)
+
+// SilenceMatcher matches events by a tag, their ID or their level.
+type SilenceMatcher struct {
+	// Type is one of "tag", "id" or "level".
+	Type string `json:"type"`
+	// Tag is the name of the tag to match, only used by tag matchers.
+	Tag   string `json:"tag,omitempty"`
+	Value string `json:"value"`
+	// Regex indicates that Value is an anchored regular expression.
+	Regex bool `json:"regex,omitempty"`
+}
+
+func (m SilenceMatcher) AlertMatcher() (alert.Matcher, error) {
+	var typ alert.MatcherType
+	switch m.Type {
+	case MatcherTypeTag:
+		typ = alert.MatchTag
+	case MatcherTypeID:
+		typ = alert.MatchID
+	case MatcherTypeLevel:
+		typ = alert.MatchLevel
+	default:
+		return alert.Matcher{}, fmt.Errorf("unknown matcher type %q, must be one of %q, %q or %q", m.Type, MatcherTypeTag, MatcherTypeID, MatcherTypeLevel)
+	}
+	return alert.NewMatcher(typ, m.Tag, m.Value, m.Regex)
+}
+
+// Silence suppresses sending the matching events of a topic to its handlers until it expires.
+type Silence struct {
+	ID       string           `json:"id"`
+	Topic    string           `json:"topic"`
+	Matchers []SilenceMatcher `json:"matchers"`
+	Expires  time.Time        `json:"expires"`
+	Author   string           `json:"author"`
+	Comment  string           `json:"comment"`
+	Created  time.Time        `json:"created"`
+}
+
+func (s Silence) Validate() error {
+	if !validTopicID.MatchString(s.Topic) {
+		return fmt.Errorf("silence topic must contain only letters, numbers, '-', '.' and '_'. %q", s.Topic)
+	}
+	if !validHandlerID.MatchString(s.ID) {
+		return fmt.Errorf("silence ID must contain only letters, numbers, '-', '.' and '_'. %q", s.ID)
+	}
+	if s.Expires.IsZero() {
+		return errors.New("silence must have an expiry")
+	}
+	_, err := s.AlertSilence()
+	return err
+}
+
+// AlertSilence returns the silence as enforced by the topic.
+func (s Silence) AlertSilence() (alert.Silence, error) {
+	if len(s.Matchers) == 0 {
+		return alert.Silence{}, errors.New("silence must have at least one matcher")
+	}
+	matchers := make([]alert.Matcher, len(s.Matchers))
+	for i, m := range s.Matchers {
+		am, err := m.AlertMatcher()
+		if err != nil {
+			return alert.Silence{}, errors.Wrapf(err, "invalid matcher %d", i)
+		}
+		matchers[i] = am
+	}
+	return alert.Silence{
+		ID:       s.ID,
+		Matchers: matchers,
+		Expires:  s.Expires,
+	}, nil
+}
+
+func (s Silence) ObjectID() string {
+	return fullID(s.Topic, s.ID)
+}
+
+func (s Silence) MarshalBinary() ([]byte, error) {
+	return storage.VersionJSONEncode(silenceVersion, s)
+}
+
+func (s *Silence) UnmarshalBinary(data []byte) error {
+	return storage.VersionJSONDecode(data, func(version int, dec *json.Decoder) error {
+		switch version {
+		case silenceVersion:
+			return dec.Decode(s)
+		default:
+			return fmt.Errorf("unknown silence version %d: cannot decode", version)
+		}
+	})
+}
+
+// Acknowledgement suppresses sending an event to handlers while it remains at the acknowledged level.
+type Acknowledgement struct {
+	Topic string      `json:"topic"`
+	Event string      `json:"event"`
+	Level alert.Level `json:"level"`
+	// Expires is optional, a zero value means the acknowledgement lasts until the level of the event changes.
+	Expires time.Time `json:"expires"`
+	Author  string    `json:"author"`
+	Comment string    `json:"comment"`
+	Created time.Time `json:"created"`
+}
+
+// AlertAcknowledgement returns the acknowledgement as enforced by the topic.
+func (a Acknowledgement) AlertAcknowledgement() alert.Acknowledgement {
+	return alert.Acknowledgement{
+		Level:   a.Level,
+		Expires: a.Expires,
+	}
+}
+
+func (a Acknowledgement) ObjectID() string {
+	return fullID(a.Topic, a.Event)
+}
+
+func (a Acknowledgement) MarshalBinary() ([]byte, error) {
+	return storage.VersionJSONEncode(acknowledgementVersion, a)
+}
+
+func (a *Acknowledgement) UnmarshalBinary(data []byte) error {
+	return storage.VersionJSONDecode(data, func(version int, dec *json.Decoder) error {
+		switch version {
+		case acknowledgementVersion:
+			return dec.Decode(a)
+		default:
+			return fmt.Errorf("unknown acknowledgement version %d: cannot decode", version)
+		}
+	})
+}
+
+// Data access object for Silence and Acknowledgement data.
+type SuppressionDAO interface {
+	// Retrieve a silence
+	Silence(topic, id string) (Silence, error)
+	// Create or replace a silence.
+	PutSilence(s Silence) error
+	// Delete a silence.
+	// It is not an error to delete a non-existent silence.
+	DeleteSilence(topic, id string) error
+	// List all silences.
+	Silences() ([]Silence, error)
+
+	// Retrieve the acknowledgement of an event.
+	Acknowledgement(topic, event string) (Acknowledgement, error)
+	// Create or replace an acknowledgement.
+	PutAcknowledgement(a Acknowledgement) error
+	// Delete an acknowledgement.
+	// It is not an error to delete a non-existent acknowledgement.
+	DeleteAcknowledgement(topic, event string) error
+	// List all acknowledgements.
+	Acknowledgements() ([]Acknowledgement, error)
+}
+
+// Key/Value store based implementation of the SuppressionDAO
+type suppressionKV struct {
+	silences *storage.IndexedStore
+	acks     *storage.IndexedStore
+}
+
+const (
+	silencePrefix         = "silences"
+	acknowledgementPrefix = "acknowledgements"
+)
+
+func newSuppressionKV(store storage.Interface) (*suppressionKV, error) {
+	silences, err := storage.NewIndexedStore(store, storage.DefaultIndexedStoreConfig(silencePrefix, func() storage.BinaryObject {
+		return new(Silence)
+	}))
+	if err != nil {
+		return nil, err
+	}
+	acks, err := storage.NewIndexedStore(store, storage.DefaultIndexedStoreConfig(acknowledgementPrefix, func() storage.BinaryObject {
+		return new(Acknowledgement)
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &suppressionKV{
+		silences: silences,
+		acks:     acks,
+	}, nil
+}
+
+func (kv *suppressionKV) Silence(topic, id string) (Silence, error) {
+	o, err := kv.silences.Get(fullID(topic, id))
+	if err == storage.ErrNoObjectExists {
+		return Silence{}, ErrNoSilenceExists
+	} else if err != nil {
+		return Silence{}, err
+	}
+	s, ok := o.(*Silence)
+	if !ok {
+		return Silence{}, storage.ImpossibleTypeErr(s, o)
+	}
+	return *s, nil
+}
+
+func (kv *suppressionKV) PutSilence(s Silence) error {
+	return kv.silences.Put(&s)
+}
+
+func (kv *suppressionKV) DeleteSilence(topic, id string) error {
+	return kv.silences.Delete(fullID(topic, id))
+}
+
+func (kv *suppressionKV) Silences() ([]Silence, error) {
+	objects, err := kv.silences.List(storage.DefaultIDIndex, "", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	silences := make([]Silence, len(objects))
+	for i, o := range objects {
+		s, ok := o.(*Silence)
+		if !ok {
+			return nil, storage.ImpossibleTypeErr(s, o)
+		}
+		silences[i] = *s
+	}
+	return silences, nil
+}
+
+func (kv *suppressionKV) Acknowledgement(topic, event string) (Acknowledgement, error) {
+	o, err := kv.acks.Get(fullID(topic, event))
+	if err == storage.ErrNoObjectExists {
+		return Acknowledgement{}, ErrNoAcknowledgementExists
+	} else if err != nil {
+		return Acknowledgement{}, err
+	}
+	a, ok := o.(*Acknowledgement)
+	if !ok {
+		return Acknowledgement{}, storage.ImpossibleTypeErr(a, o)
+	}
+	return *a, nil
+}
+
+func (kv *suppressionKV) PutAcknowledgement(a Acknowledgement) error {
+	return kv.acks.Put(&a)
+}
+
+func (kv *suppressionKV) DeleteAcknowledgement(topic, event string) error {
+	return kv.acks.Delete(fullID(topic, event))
+}
+
+func (kv *suppressionKV) Acknowledgements() ([]Acknowledgement, error) {
+	objects, err := kv.acks.List(storage.DefaultIDIndex, "", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	acks := make([]Acknowledgement, len(objects))
+	for i, o := range objects {
+		a, ok := o.(*Acknowledgement)
+		if !ok {
+			return nil, storage.ImpossibleTypeErr(a, o)
+		}
+		acks[i] = *a
+	}
+	return acks, nil
+}
+
+const historyEntryVersion = 1
+
+// HistoryEntry records a single state transition of an event.
+type HistoryEntry struct {
+	Topic string      `json:"topic"`
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Level alert.Level `json:"level"`
+	// PreviousLevel is the level of the event before the transition.
+	PreviousLevel alert.Level   `json:"previous-level"`
+	Message       string        `json:"message"`
+	Duration      time.Duration `json:"duration"`
+}
+
+func (h HistoryEntry) MarshalBinary() ([]byte, error) {
+	return storage.VersionJSONEncode(historyEntryVersion, h)
+}
+
+func (h *HistoryEntry) UnmarshalBinary(data []byte) error {
+	return storage.VersionJSONDecode(data, func(version int, dec *json.Decoder) error {
+		switch version {
+		case historyEntryVersion:
+			return dec.Decode(h)
+		default:
+			return fmt.Errorf("unknown history entry version %d: cannot decode", version)
+		}
+	})
+}
+
+// Data access object for the history of state transitions of events.
+type HistoryDAO interface {
+	// Append records a state transition of an event.
+	Append(h HistoryEntry) error
+	// History returns the entries of an event with start <= time < stop, ordered by time.
+	// A zero start or stop leaves that end of the range unbounded.
+	History(topic, event string, start, stop time.Time) ([]HistoryEntry, error)
+	// DeleteBefore deletes all entries older than t.
+	DeleteBefore(t time.Time) error
+	// DeleteTopic deletes the history of all events of the topic.
+	// It is not an error to delete the history of a topic without history.
+	DeleteTopic(topic string) error
+}
+
+// Key/Value store based implementation of the HistoryDAO.
+// Entries are stored in a bucket per topic and event keyed by their time,
+// so that the entries of an event are sorted by time.
+type historyKV struct {
+	store storage.Interface
+}
+
+func NewHistoryKV(store storage.Interface) *historyKV {
+	return &historyKV{
+		store: store,
+	}
+}
+
+func historyKey(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
+
+func (kv *historyKV) Append(h HistoryEntry) error {
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Bucket([]byte(h.Topic)).Bucket([]byte(h.Event)).Put(historyKey(h.Time), data)
+	})
+}
+
+func (kv *historyKV) History(topic, event string, start, stop time.Time) ([]HistoryEntry, error) {
+	var history []HistoryEntry
+	err := kv.store.View(func(tx storage.ReadOnlyTx) error {
+		cursor := tx.Bucket([]byte(topic)).Bucket([]byte(event)).Cursor()
+		if cursor == nil {
+			return nil
+		}
+		var k, v []byte
+		if start.IsZero() {
+			k, v = cursor.First()
+		} else {
+			k, v = cursor.Seek([]byte(historyKey(start)))
+		}
+		var end []byte
+		if !stop.IsZero() {
+			end = []byte(historyKey(stop))
+		}
+		for ; k != nil && (end == nil || bytes.Compare(k, end) < 0); k, v = cursor.Next() {
+			var h HistoryEntry
+			if err := h.UnmarshalBinary(v); err != nil {
+				return errors.Wrapf(err, "failed to read history entry %s of event %q in topic %q", k, event, topic)
+			}
+			history = append(history, h)
+		}
+		return nil
+	})
+	return history, err
+}
+
+// historyPurgeBatchSize is the maximum number of entries deleted in a single transaction,
+// so that purging a large history does not block other writes for its whole duration.
+const historyPurgeBatchSize = 1000
+
+func (kv *historyKV) DeleteBefore(t time.Time) error {
+	return kv.deleteBefore(historyKey(t), historyPurgeBatchSize)
+}
+
+// historyPosition is the event bucket a purge continues from.
+type historyPosition struct {
+	topic, event string
+}
+
+// deleteBefore deletes all entries with keys before cutoff in transactions of at most batchSize entries.
+// Each transaction continues from the event the previous transaction stopped at.
+func (kv *historyKV) deleteBefore(cutoff string, batchSize int) error {
+	var from historyPosition
+	for {
+		deleted, next, err := kv.deleteBeforeBatch(cutoff, batchSize, from)
+		if err != nil {
+			return err
+		}
+		if deleted < batchSize {
+			return nil
+		}
+		from = next
+	}
+}
+
+// deleteBeforeBatch deletes up to batchSize entries with keys before cutoff in a single transaction,
+// starting from the event at position from, and returns the event it stopped at.
+// Buckets of events and topics without remaining entries are deleted as well.
+func (kv *historyKV) deleteBeforeBatch(cutoff string, batchSize int, from historyPosition) (deleted int, next historyPosition, err error) {
+	err = kv.store.Update(func(tx storage.Tx) error {
+		for _, topic := range cursorKeys(tx.Cursor(), from.topic) {
+			topicTx := tx.Bucket([]byte(topic))
+			var first string
+			if topic == from.topic {
+				first = from.event
+			}
+			for _, event := range cursorKeys(topicTx.Cursor(), first) {
+				eventTx := topicTx.Bucket([]byte(event))
+				// Entries are sorted by time, collect the expired entries before deleting them
+				// since deleting moves the cursor.
+				var expired []string
+				if cursor := eventTx.Cursor(); cursor != nil {
+					for k, _ := cursor.First(); k != nil && string(k) < cutoff && deleted+len(expired) < batchSize; k, _ = cursor.Next() {
+						expired = append(expired, string(k))
+					}
+				}
+				for _, key := range expired {
+					if err := eventTx.Delete(key); err != nil {
+						return err
+					}
+				}
+				deleted += len(expired)
+				if isEmptyBucket(eventTx) {
+					if err := topicTx.Delete(event); err != nil {
+						return err
+					}
+				}
+				if deleted == batchSize {
+					next = historyPosition{topic: topic, event: event}
+					return nil
+				}
+			}
+			if isEmptyBucket(topicTx) {
+				if err := tx.Delete(topic); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return deleted, next, err
+}
+
+// cursorKeys returns the keys of a bucket starting at from without reading their values.
+func cursorKeys(cursor *bbolt.Cursor, from string) []string {
+	if cursor == nil {
+		return nil
+	}
+	var keys []string
+	for k, _ := cursor.Seek([]byte(from)); k != nil; k, _ = cursor.Next() {
+		keys = append(keys, string(k))
+	}
+	return keys
+}
+
+func isEmptyBucket(tx storage.Tx) bool {
+	cursor := tx.Cursor()
+	if cursor == nil {
+		return true
+	}
+	k, _ := cursor.First()
+	return k == nil
+}
+
+func (kv *historyKV) DeleteTopic(topic string) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Delete(topic)
+	})
+}
+
+const deadLetterVersion = 1
+
+// DeadLetter is an event that a handler failed to handle after all of its attempts.
+type DeadLetter struct {
+	ID      string      `json:"id"`
+	Topic   string      `json:"topic"`
+	Handler string      `json:"handler"`
+	Event   alert.Event `json:"event"`
+	// PreviousLevel is the level of the event before it was handled,
+	// the previous state of an event is not exported.
+	PreviousLevel alert.Level `json:"previous-level"`
+	// Error is the error of the last attempt.
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	Time     time.Time `json:"time"`
+}
+
+// AlertEvent returns the dead-lettered event as it was passed to the handler.
+func (d DeadLetter) AlertEvent() alert.Event {
+	event := d.Event
+	event.SetPreviousState(alert.EventState{Level: d.PreviousLevel})
+	return event
+}
+
+func (d DeadLetter) MarshalBinary() ([]byte, error) {
+	return storage.VersionJSONEncode(deadLetterVersion, d)
+}
+
+func (d *DeadLetter) UnmarshalBinary(data []byte) error {
+	return storage.VersionJSONDecode(data, func(version int, dec *json.Decoder) error {
+		switch version {
+		case deadLetterVersion:
+			return dec.Decode(d)
+		default:
+			return fmt.Errorf("unknown dead letter version %d: cannot decode", version)
+		}
+	})
+}
+
+// Data access object for the events that handlers failed to handle.
+type DeadLetterDAO interface {
+	// Put stores a dead letter.
+	Put(d DeadLetter) error
+	// List returns the dead letters of a handler, ordered by time.
+	List(topic, handler string) ([]DeadLetter, error)
+	// Delete deletes a dead letter of a handler.
+	// It is not an error to delete a dead letter that does not exist.
+	Delete(topic, handler, id string) error
+	// DeleteHandler deletes all dead letters of a handler.
+	DeleteHandler(topic, handler string) error
+}
+
+// Key/Value store based implementation of the DeadLetterDAO.
+// Dead letters are stored in a bucket per topic and handler keyed by their ID.
+type deadLetterKV struct {
+	store storage.Interface
+}
+
+func NewDeadLetterKV(store storage.Interface) *deadLetterKV {
+	return &deadLetterKV{
+		store: store,
+	}
+}
+
+func (kv *deadLetterKV) Put(d DeadLetter) error {
+	data, err := d.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Bucket([]byte(d.Topic)).Bucket([]byte(d.Handler)).Put(d.ID, data)
+	})
+}
+
+func (kv *deadLetterKV) List(topic, handler string) ([]DeadLetter, error) {
+	var deadLetters []DeadLetter
+	err := kv.store.View(func(tx storage.ReadOnlyTx) error {
+		kvs, err := tx.Bucket([]byte(topic)).Bucket([]byte(handler)).List("")
+		if err != nil {
+			return err
+		}
+		deadLetters = make([]DeadLetter, len(kvs))
+		for i, e := range kvs {
+			if err := deadLetters[i].UnmarshalBinary(e.Value); err != nil {
+				return errors.Wrapf(err, "failed to read dead letter %s of handler %q in topic %q", e.Key, handler, topic)
+			}
+		}
+		return nil
+	})
+	sort.SliceStable(deadLetters, func(i, j int) bool {
+		return deadLetters[i].Time.Before(deadLetters[j].Time)
+	})
+	return deadLetters, err
+}
+
+func (kv *deadLetterKV) Delete(topic, handler, id string) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Bucket([]byte(topic)).Bucket([]byte(handler)).Delete(id)
+	})
+}
+
+func (kv *deadLetterKV) DeleteHandler(topic, handler string) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Bucket([]byte(topic)).Delete(handler)
+	})
+}
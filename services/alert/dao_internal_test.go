@@ -0,0 +1,84 @@
+package alert
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/services/storage"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestHistoryKV_DeleteBeforeBatches(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "history.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := NewHistoryKV(storage.NewBolt(db, []byte("history")))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, topic := range []string{"t1", "t2"} {
+		for _, event := range []string{"a", "b", "c"} {
+			for i := 0; i < 4; i++ {
+				if err := kv.Append(HistoryEntry{
+					Topic: topic,
+					Event: event,
+					Time:  start.Add(time.Duration(i) * time.Minute),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	// Delete the first three entries of every event, two entries per transaction.
+	deleted, next, err := kv.deleteBeforeBatch(historyKey(start.Add(3*time.Minute)), 2, historyPosition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("unexpected number of entries deleted by one batch: got %d exp 2", deleted)
+	}
+	if exp := (historyPosition{topic: "t1", event: "a"}); next != exp {
+		t.Errorf("unexpected position after one batch: got %+v exp %+v", next, exp)
+	}
+	// The next batch continues from the event the previous batch stopped at.
+	if _, next, err = kv.deleteBeforeBatch(historyKey(start.Add(3*time.Minute)), 2, next); err != nil {
+		t.Fatal(err)
+	}
+	if exp := (historyPosition{topic: "t1", event: "b"}); next != exp {
+		t.Errorf("unexpected position after two batches: got %+v exp %+v", next, exp)
+	}
+	if err := kv.deleteBefore(historyKey(start.Add(3*time.Minute)), 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range []string{"t1", "t2"} {
+		for _, event := range []string{"a", "b", "c"} {
+			history, err := kv.History(topic, event, time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 1 || !history[0].Time.Equal(start.Add(3*time.Minute)) {
+				t.Errorf("unexpected history of %s/%s after purge: %+v", topic, event, history)
+			}
+		}
+	}
+
+	// Deleting everything also deletes the topic buckets.
+	if err := kv.deleteBefore(historyKey(start.Add(time.Hour)), 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := kv.store.View(func(tx storage.ReadOnlyTx) error {
+		topics, err := tx.List("")
+		if err != nil {
+			return err
+		}
+		if len(topics) != 0 {
+			t.Errorf("unexpected topics after purging all history: %d", len(topics))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,9 +2,13 @@ package alert_test
 
 import (
 	"fmt"
+	"testing"
+	"time"
+
+	alertcore "github.com/influxdata/kapacitor/alert"
 	"github.com/influxdata/kapacitor/services/alert"
 	"github.com/influxdata/kapacitor/services/alert/alerttest"
-	"testing"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
 )
 
 func BenchmarkTopicState_MarshalBinary(b *testing.B) {
@@ -89,3 +93,146 @@ func BenchmarkTopicState_UnmarshalBinary(b *testing.B) {
 		})
 	}
 }
+
+func TestHistoryKV(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := alert.NewHistoryKV(db.Store("history"))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	levels := []alertcore.Level{alertcore.Warning, alertcore.Critical, alertcore.OK}
+	for _, event := range []string{"a", "b"} {
+		previous := alertcore.OK
+		for i, level := range levels {
+			if err := kv.Append(alert.HistoryEntry{
+				Topic:         "topic",
+				Event:         event,
+				Time:          start.Add(time.Duration(i) * time.Minute),
+				Level:         level,
+				PreviousLevel: previous,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			previous = level
+		}
+	}
+
+	history, err := kv.History("topic", "a", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(levels) {
+		t.Fatalf("unexpected history length: got %d exp %d", len(history), len(levels))
+	}
+	for i, h := range history {
+		if h.Event != "a" || h.Level != levels[i] || !h.Time.Equal(start.Add(time.Duration(i)*time.Minute)) {
+			t.Errorf("unexpected history entry %d: %+v", i, h)
+		}
+	}
+
+	history, err = kv.History("topic", "a", start.Add(time.Minute), start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Level != alertcore.Critical || history[0].PreviousLevel != alertcore.Warning {
+		t.Errorf("unexpected history in time range: %+v", history)
+	}
+
+	if err := kv.DeleteBefore(start.Add(2 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	history, err = kv.History("topic", "b", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Level != alertcore.OK {
+		t.Errorf("unexpected history after deleting old entries: %+v", history)
+	}
+
+	if err := kv.DeleteTopic("topic"); err != nil {
+		t.Fatal(err)
+	}
+	history, err = kv.History("topic", "a", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected no history after deleting the topic, got %+v", history)
+	}
+}
+
+func TestDeadLetterKV(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := alert.NewDeadLetterKV(db.Store("dead_letters"))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Put the dead letters out of order, they are listed by time.
+	for _, i := range []int{2, 0, 1} {
+		event := alertcore.Event{
+			Topic: "topic",
+			State: alertcore.EventState{
+				ID:      fmt.Sprintf("event%d", i),
+				Message: "message",
+				Level:   alertcore.Critical,
+			},
+		}
+		if err := kv.Put(alert.DeadLetter{
+			ID:            fmt.Sprintf("id%d", i),
+			Topic:         "topic",
+			Handler:       "handler",
+			Event:         event,
+			PreviousLevel: alertcore.OK,
+			Error:         "failed",
+			Attempts:      3,
+			Time:          start.Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadLetters, err := kv.List("topic", "handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 3 {
+		t.Fatalf("unexpected number of dead letters: %d", len(deadLetters))
+	}
+	for i, d := range deadLetters {
+		if d.ID != fmt.Sprintf("id%d", i) || d.Event.State.ID != fmt.Sprintf("event%d", i) || d.Attempts != 3 {
+			t.Errorf("unexpected dead letter %d: %+v", i, d)
+		}
+		event := d.AlertEvent()
+		if event.State.Level != alertcore.Critical || event.PreviousState().Level != alertcore.OK {
+			t.Errorf("unexpected event of dead letter %d: %+v", i, event)
+		}
+	}
+
+	if err := kv.Delete("topic", "handler", "id1"); err != nil {
+		t.Fatal(err)
+	}
+	deadLetters, err = kv.List("topic", "handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 2 || deadLetters[0].ID != "id0" || deadLetters[1].ID != "id2" {
+		t.Errorf("unexpected dead letters after delete: %+v", deadLetters)
+	}
+
+	if err := kv.DeleteHandler("topic", "handler"); err != nil {
+		t.Fatal(err)
+	}
+	deadLetters, err = kv.List("topic", "handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadLetters) != 0 {
+		t.Errorf("expected no dead letters after deleting the handler, got %+v", deadLetters)
+	}
+}
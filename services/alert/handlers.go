@@ -15,7 +15,9 @@ import (
 	"github.com/influxdata/kapacitor/alert"
 	"github.com/influxdata/kapacitor/bufpool"
 	"github.com/influxdata/kapacitor/command"
+	"github.com/influxdata/kapacitor/expvar"
 	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/server/vars"
 	"github.com/influxdata/kapacitor/tick/ast"
 	"github.com/influxdata/kapacitor/tick/stateful"
 	"github.com/pkg/errors"
@@ -350,6 +352,72 @@ func (h *externalHandler) Handle(event alert.Event) {
 	}
 }
 
+func (h *externalHandler) TryHandle(event alert.Event) error {
+	if event.NoExternal {
+		return nil
+	}
+	if f, ok := h.h.(alert.FallibleHandler); ok {
+		return f.TryHandle(event)
+	}
+	h.h.Handle(event)
+	return nil
+}
+
+// isFallible reports whether the handler, or the external handler it is wrapped in, reports its failures.
+func isFallible(h alert.Handler) bool {
+	if e, ok := h.(*externalHandler); ok {
+		h = e.h
+	}
+	_, ok := h.(alert.FallibleHandler)
+	return ok
+}
+
+// statsHandler counts the events passed to the handler of a handler spec.
+type statsHandler struct {
+	h alert.Handler
+
+	statsKey string
+	handled  *expvar.Int
+}
+
+func newStatsHandler(spec HandlerSpec, h alert.Handler) *statsHandler {
+	key, statMap := vars.NewStatistic("handlers", map[string]string{
+		"topic":   spec.Topic,
+		"handler": spec.ID,
+		"kind":    spec.Kind,
+	})
+	handled := new(expvar.Int)
+	statMap.Set("handled", handled)
+	if r, ok := h.(*retryHandler); ok {
+		statMap.Set("retry_queue_full", r.queueFull)
+	}
+	return &statsHandler{
+		h:        h,
+		statsKey: key,
+		handled:  handled,
+	}
+}
+
+func (h *statsHandler) Handle(event alert.Event) {
+	h.handled.Add(1)
+	h.h.Handle(event)
+}
+
+// Dropped passes the dropped event on to the wrapped handler, if it is notified of dropped events.
+func (h *statsHandler) Dropped(event alert.Event, err error) {
+	if d, ok := h.h.(alert.DropHandler); ok {
+		d.Dropped(event, err)
+	}
+}
+
+// Close removes the statistics of the handler and closes the wrapped handler.
+func (h *statsHandler) Close() {
+	vars.DeleteStatistic(h.statsKey)
+	if c, ok := h.h.(closer); ok {
+		c.Close()
+	}
+}
+
 type matchHandler struct {
 	h alert.Handler
 
@@ -439,6 +507,12 @@ func newMatchHandler(match string, h alert.Handler, d HandlerDiagnostic) (*match
 }
 
 func (h *matchHandler) Handle(event alert.Event) {
+	h.TryHandle(event)
+}
+
+// TryHandle passes the event on to the wrapped handler if it matches,
+// and returns the error of the wrapped handler if it reports its failures.
+func (h *matchHandler) TryHandle(event alert.Event) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch r := r.(type) {
@@ -450,11 +524,19 @@ func (h *matchHandler) Handle(event alert.Event) {
 		}
 	}()
 
-	if ok, err := h.match(event); err != nil {
+	ok, err := h.match(event)
+	if err != nil {
 		h.diag.Error("failed to evaluate match expression", err)
-	} else if ok {
-		h.h.Handle(event)
+		return nil
+	}
+	if !ok {
+		return nil
 	}
+	if f, ok := h.h.(alert.FallibleHandler); ok {
+		return f.TryHandle(event)
+	}
+	h.h.Handle(event)
+	return nil
 }
 
 var changedFuncSignature = map[stateful.Domain]ast.ValueType{}
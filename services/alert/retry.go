@@ -0,0 +1,415 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/kapacitor/alert"
+	"github.com/influxdata/kapacitor/expvar"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/uuid"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// retryOption is the reserved option of a handler spec that configures its retry policy.
+const retryOption = "retry"
+
+const (
+	DefaultRetryMaxAttempts     = 3
+	DefaultRetryInitialInterval = time.Second
+	DefaultRetryMaxInterval     = time.Minute
+	DefaultRetryMultiplier      = 2.0
+	DefaultRetryMaxQueue        = 1000
+)
+
+// errRetryQueueFull is the error of events that could not be queued to be retried.
+var errRetryQueueFull = errors.New("retry queue is full")
+
+// RetryPolicy configures how a handler retries the events it failed to handle.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts to handle an event, including the first one.
+	MaxAttempts int `mapstructure:"max-attempts"`
+	// InitialInterval is the time to wait before the first retry.
+	InitialInterval time.Duration `mapstructure:"initial-interval"`
+	// MaxInterval caps the time to wait between retries.
+	MaxInterval time.Duration `mapstructure:"max-interval"`
+	// Multiplier is the factor by which the interval grows after each retry.
+	Multiplier float64 `mapstructure:"multiplier"`
+	// MaxQueue is the maximum number of events waiting to be retried,
+	// including the events waiting behind an older event of the same alert.
+	// Events that fail while the queue is full are dead-lettered without further attempts.
+	MaxQueue int `mapstructure:"max-queue"`
+	// DeadLetter stores the events that could not be handled after all attempts,
+	// so that they can be replayed. Otherwise they are dropped.
+	DeadLetter bool `mapstructure:"dead-letter"`
+}
+
+func NewRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     DefaultRetryMaxAttempts,
+		InitialInterval: DefaultRetryInitialInterval,
+		MaxInterval:     DefaultRetryMaxInterval,
+		Multiplier:      DefaultRetryMultiplier,
+		MaxQueue:        DefaultRetryMaxQueue,
+		DeadLetter:      true,
+	}
+}
+
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("retry max-attempts must be at least 1")
+	}
+	if p.InitialInterval < 0 {
+		return errors.New("retry initial-interval must not be negative")
+	}
+	if p.MaxInterval < p.InitialInterval {
+		return errors.New("retry max-interval must not be less than initial-interval")
+	}
+	if p.Multiplier < 1 {
+		return errors.New("retry multiplier must be at least 1")
+	}
+	if p.MaxQueue < 1 {
+		return errors.New("retry max-queue must be at least 1")
+	}
+	return nil
+}
+
+// interval returns the time to wait before an event is attempted again after the number of attempts.
+func (p RetryPolicy) interval(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := float64(p.InitialInterval) * math.Pow(p.Multiplier, float64(attempts-1))
+	if d > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	return time.Duration(d)
+}
+
+// retryPolicyFromOptions removes the retry option from the options of a handler spec and decodes it.
+// The returned options are the options of the handler kind, nil is returned if no retry policy is configured.
+func retryPolicyFromOptions(options map[string]interface{}) (map[string]interface{}, *RetryPolicy, error) {
+	o, ok := options[retryOption]
+	if !ok {
+		return options, nil, nil
+	}
+	kindOptions := make(map[string]interface{}, len(options)-1)
+	for k, v := range options {
+		if k != retryOption {
+			kindOptions[k] = v
+		}
+	}
+	p := NewRetryPolicy()
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused: true,
+		Result:      &p,
+		DecodeHook:  mapstructure.StringToTimeDurationHookFunc(),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to initialize mapstructure decoder")
+	}
+	if err := dec.Decode(o); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode retry option")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return kindOptions, &p, nil
+}
+
+// retryHandler retries the events its handler failed to handle with exponential backoff.
+// Events that could not be handled after all attempts, or that were dropped
+// because the buffer of the handler was full, are dead-lettered.
+type retryHandler struct {
+	// mu serializes the attempts to handle events.
+	mu sync.Mutex
+	h  alert.FallibleHandler
+
+	topic   string
+	id      string
+	policy  RetryPolicy
+	dao     DeadLetterDAO
+	diag    HandlerDiagnostic
+	timeNow func() time.Time
+
+	queueMu sync.Mutex
+	// queues holds the events waiting to be retried per alert ID.
+	// The events of an alert are attempted in the order they were handled,
+	// so that an alert is not delivered after the newer state that replaced it.
+	queues map[string][]retryEvent
+	queued int
+	seq    uint64
+	closed bool
+	// queueFull counts the events that were not retried because the queue was full.
+	queueFull *expvar.Int
+
+	notify  chan struct{}
+	closing chan struct{}
+	wg      sync.WaitGroup
+}
+
+// retryEvent is an event waiting to be attempted again.
+type retryEvent struct {
+	event    alert.Event
+	attempts int
+	err      error
+	// due is the time the event is attempted once it is the oldest event of its alert.
+	due time.Time
+	// seq orders the events in the order they were queued.
+	seq uint64
+}
+
+func newRetryHandler(spec HandlerSpec, policy RetryPolicy, h alert.FallibleHandler, dao DeadLetterDAO, d HandlerDiagnostic) *retryHandler {
+	r := &retryHandler{
+		h:         h,
+		topic:     spec.Topic,
+		id:        spec.ID,
+		policy:    policy,
+		dao:       dao,
+		diag:      d,
+		timeNow:   time.Now,
+		queues:    make(map[string][]retryEvent),
+		queueFull: new(expvar.Int),
+		notify:    make(chan struct{}, 1),
+		closing:   make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run()
+	}()
+	return r
+}
+
+func (r *retryHandler) Handle(event alert.Event) {
+	// Events of an alert with older events waiting to be retried are queued behind them.
+	if r.waiting(event.State.ID) {
+		r.retry(retryEvent{event: event})
+		return
+	}
+	if err := r.try(event); err != nil {
+		r.retry(retryEvent{event: event, attempts: 1, err: err})
+	}
+}
+
+// TryHandle attempts to handle the event once without retrying it.
+func (r *retryHandler) TryHandle(event alert.Event) error {
+	return r.try(event)
+}
+
+// Dropped dead-letters the events that were dropped before they could be handled.
+func (r *retryHandler) Dropped(event alert.Event, err error) {
+	r.deadLetter(retryEvent{event: event, err: err})
+}
+
+// Replay attempts to handle the events again, starting over with their attempts.
+func (r *retryHandler) Replay(events []alert.Event) {
+	for _, event := range events {
+		r.retry(retryEvent{event: event})
+	}
+}
+
+// Close stops retrying and dead-letters the events that are still waiting to be retried.
+func (r *retryHandler) Close() {
+	r.queueMu.Lock()
+	if r.closed {
+		r.queueMu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.closing)
+	r.queueMu.Unlock()
+	r.wg.Wait()
+
+	r.queueMu.Lock()
+	var pending []retryEvent
+	for _, q := range r.queues {
+		pending = append(pending, q...)
+	}
+	r.queues = make(map[string][]retryEvent)
+	r.queued = 0
+	r.queueMu.Unlock()
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].seq < pending[j].seq
+	})
+	for _, e := range pending {
+		r.deadLetter(e)
+	}
+	if c, ok := r.h.(closer); ok {
+		c.Close()
+	}
+}
+
+func (r *retryHandler) try(event alert.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.h.TryHandle(event)
+}
+
+// waiting reports whether events of the alert are waiting to be retried.
+func (r *retryHandler) waiting(id string) bool {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	return len(r.queues[id]) > 0
+}
+
+// retry queues the event to be attempted again, or dead-letters it if it has no attempts left
+// or the queue is full.
+func (r *retryHandler) retry(e retryEvent) {
+	if e.attempts >= r.policy.MaxAttempts {
+		r.deadLetter(e)
+		return
+	}
+	r.queueMu.Lock()
+	if r.closed {
+		r.queueMu.Unlock()
+		r.deadLetter(e)
+		return
+	}
+	if r.queued >= r.policy.MaxQueue {
+		r.queueMu.Unlock()
+		r.queueFull.Add(1)
+		if e.err != nil {
+			e.err = errors.Wrap(e.err, errRetryQueueFull.Error())
+		} else {
+			e.err = errRetryQueueFull
+		}
+		r.deadLetter(e)
+		return
+	}
+	e.due = r.timeNow().Add(r.policy.interval(e.attempts))
+	r.seq++
+	e.seq = r.seq
+	id := e.event.State.ID
+	r.queues[id] = append(r.queues[id], e)
+	r.queued++
+	r.queueMu.Unlock()
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// nextDue returns the earliest time at which the oldest event of an alert is due.
+func (r *retryHandler) nextDue() (time.Time, bool) {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	var next time.Time
+	for _, q := range r.queues {
+		if due := q[0].due; next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// dueEvents returns the oldest events of the alerts that are due at now, in the order they were queued.
+// The events stay queued while they are attempted, so that newer events of their alerts are queued behind them
+// and they are dead-lettered if the handler is closed.
+func (r *retryHandler) dueEvents(now time.Time) []retryEvent {
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	var due []retryEvent
+	for _, q := range r.queues {
+		if !q[0].due.After(now) {
+			due = append(due, q[0])
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].seq < due[j].seq
+	})
+	return due
+}
+
+// attempted removes an attempted event from the queue of its alert if it was handled or has no attempts left,
+// otherwise it waits for its next attempt at the head of the queue.
+func (r *retryHandler) attempted(e retryEvent, err error) {
+	id := e.event.State.ID
+	r.queueMu.Lock()
+	q := r.queues[id]
+	if err != nil {
+		e.attempts++
+		e.err = err
+		if e.attempts < r.policy.MaxAttempts {
+			e.due = r.timeNow().Add(r.policy.interval(e.attempts))
+			q[0] = e
+			r.queueMu.Unlock()
+			return
+		}
+	}
+	if len(q) == 1 {
+		delete(r.queues, id)
+	} else {
+		r.queues[id] = q[1:]
+	}
+	r.queued--
+	r.queueMu.Unlock()
+	if err != nil {
+		r.deadLetter(e)
+	}
+}
+
+func (r *retryHandler) run() {
+	for {
+		if due, ok := r.nextDue(); !ok {
+			select {
+			case <-r.notify:
+				continue
+			case <-r.closing:
+				return
+			}
+		} else {
+			timer := time.NewTimer(due.Sub(r.timeNow()))
+			select {
+			case <-timer.C:
+			case <-r.notify:
+				// A newly queued event may be due earlier.
+				timer.Stop()
+				continue
+			case <-r.closing:
+				timer.Stop()
+				return
+			}
+		}
+		for _, e := range r.dueEvents(r.timeNow()) {
+			select {
+			case <-r.closing:
+				return
+			default:
+			}
+			r.attempted(e, r.try(e.event))
+		}
+	}
+}
+
+func (r *retryHandler) deadLetter(e retryEvent) {
+	ctx := []keyvalue.T{
+		keyvalue.KV("event", e.event.State.ID),
+		keyvalue.KV("attempts", fmt.Sprint(e.attempts)),
+	}
+	if !r.policy.DeadLetter {
+		r.diag.Error("dropping event that could not be handled", e.err, ctx...)
+		return
+	}
+	d := DeadLetter{
+		ID:            uuid.New().String(),
+		Topic:         r.topic,
+		Handler:       r.id,
+		Event:         e.event,
+		PreviousLevel: e.event.PreviousState().Level,
+		Attempts:      e.attempts,
+		Time:          r.timeNow().UTC(),
+	}
+	if e.err != nil {
+		d.Error = e.err.Error()
+	}
+	if err := r.dao.Put(d); err != nil {
+		r.diag.Error("failed to store dead letter", err, ctx...)
+		return
+	}
+	r.diag.Error("dead-lettered event that could not be handled", e.err, ctx...)
+}
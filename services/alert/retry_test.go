@@ -0,0 +1,300 @@
+package alert
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/alert"
+	"github.com/influxdata/kapacitor/keyvalue"
+)
+
+// failingHandler fails to handle each event a number of times before it succeeds.
+type failingHandler struct {
+	mu       sync.Mutex
+	failures int
+	attempts map[string]int
+	handled  []string
+	events   []alert.Event
+}
+
+func (h *failingHandler) Handle(event alert.Event) {
+	h.TryHandle(event)
+}
+
+func (h *failingHandler) TryHandle(event alert.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.attempts[event.State.ID]++
+	if h.attempts[event.State.ID] <= h.failures {
+		return errors.New("failed")
+	}
+	h.handled = append(h.handled, event.State.ID)
+	h.events = append(h.events, event)
+	return nil
+}
+
+func (h *failingHandler) Events() []alert.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]alert.Event(nil), h.events...)
+}
+
+func (h *failingHandler) Handled() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.handled...)
+}
+
+type deadLetterDAO struct {
+	mu          sync.Mutex
+	deadLetters []DeadLetter
+}
+
+func (d *deadLetterDAO) Put(l DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadLetters = append(d.deadLetters, l)
+	return nil
+}
+
+func (d *deadLetterDAO) List(topic, handler string) ([]DeadLetter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DeadLetter(nil), d.deadLetters...), nil
+}
+
+func (d *deadLetterDAO) Delete(topic, handler, id string) error { return nil }
+
+func (d *deadLetterDAO) DeleteHandler(topic, handler string) error { return nil }
+
+type nopDiag struct{}
+
+func (nopDiag) Error(msg string, err error, ctx ...keyvalue.T) {}
+
+func TestRetryPolicy_Interval(t *testing.T) {
+	p := RetryPolicy{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+	}
+	for attempts, exp := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := p.interval(attempts); got != exp {
+			t.Errorf("unexpected interval after %d attempts: got %v exp %v", attempts, got, exp)
+		}
+	}
+}
+
+func TestRetryPolicyFromOptions(t *testing.T) {
+	options, p, err := retryPolicyFromOptions(map[string]interface{}{
+		"url": "http://example.com",
+		"retry": map[string]interface{}{
+			"max-attempts":     5.0,
+			"initial-interval": "10ms",
+			"dead-letter":      false,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := options[retryOption]; ok || options["url"] != "http://example.com" {
+		t.Errorf("unexpected options: %v", options)
+	}
+	exp := NewRetryPolicy()
+	exp.MaxAttempts = 5
+	exp.InitialInterval = 10 * time.Millisecond
+	exp.DeadLetter = false
+	if *p != exp {
+		t.Errorf("unexpected retry policy: got %+v exp %+v", *p, exp)
+	}
+
+	if _, _, err := retryPolicyFromOptions(map[string]interface{}{
+		"retry": map[string]interface{}{"max-attempts": 0},
+	}); err == nil {
+		t.Error("expected error for invalid retry policy")
+	}
+	if _, _, err := retryPolicyFromOptions(map[string]interface{}{
+		"retry": map[string]interface{}{"unknown": 1},
+	}); err == nil {
+		t.Error("expected error for unknown retry option")
+	}
+}
+
+func TestRetryHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		failures       int
+		expHandled     []string
+		expDeadLetters int
+	}{
+		{
+			name:       "succeeds",
+			expHandled: []string{"a", "b"},
+		},
+		{
+			name:       "succeeds after retries",
+			failures:   2,
+			expHandled: []string{"a", "b"},
+		},
+		{
+			name:           "dead-lettered",
+			failures:       3,
+			expDeadLetters: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &failingHandler{failures: tc.failures, attempts: make(map[string]int)}
+			dao := new(deadLetterDAO)
+			policy := RetryPolicy{
+				MaxAttempts:     3,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+				Multiplier:      2,
+				MaxQueue:        10,
+				DeadLetter:      true,
+			}
+			r := newRetryHandler(HandlerSpec{Topic: "topic", ID: "handler"}, policy, h, dao, nopDiag{})
+			for _, id := range []string{"a", "b"} {
+				r.Handle(alert.Event{Topic: "topic", State: alert.EventState{ID: id}})
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
+				if deadLetters, _ := dao.List("topic", "handler"); len(h.Handled())+len(deadLetters) == 2 {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			r.Close()
+
+			if got := h.Handled(); len(got) != len(tc.expHandled) {
+				t.Errorf("unexpected handled events: got %v exp %v", got, tc.expHandled)
+			}
+			deadLetters, _ := dao.List("topic", "handler")
+			if len(deadLetters) != tc.expDeadLetters {
+				t.Fatalf("unexpected number of dead letters: got %d exp %d", len(deadLetters), tc.expDeadLetters)
+			}
+			for _, d := range deadLetters {
+				if d.Topic != "topic" || d.Handler != "handler" || d.Attempts != 3 || d.Error != "failed" {
+					t.Errorf("unexpected dead letter: %+v", d)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryHandler_CloseDeadLettersPending(t *testing.T) {
+	h := &failingHandler{failures: 1, attempts: make(map[string]int)}
+	dao := new(deadLetterDAO)
+	policy := NewRetryPolicy()
+	policy.InitialInterval = time.Hour
+	policy.MaxInterval = time.Hour
+	r := newRetryHandler(HandlerSpec{Topic: "topic", ID: "handler"}, policy, h, dao, nopDiag{})
+	r.Handle(alert.Event{State: alert.EventState{ID: "a"}})
+	r.Dropped(alert.Event{State: alert.EventState{ID: "b"}}, errors.New("dropped"))
+	r.Close()
+
+	deadLetters, _ := dao.List("topic", "handler")
+	if len(deadLetters) != 2 {
+		t.Fatalf("unexpected number of dead letters: %d", len(deadLetters))
+	}
+	if d := deadLetters[0]; d.Event.State.ID != "b" || d.Error != "dropped" || d.Attempts != 0 {
+		t.Errorf("unexpected dead letter of dropped event: %+v", d)
+	}
+	if d := deadLetters[1]; d.Event.State.ID != "a" || d.Error != "failed" || d.Attempts != 1 {
+		t.Errorf("unexpected dead letter of pending event: %+v", d)
+	}
+
+	// Events replayed to a closed handler are dead-lettered again.
+	r.Replay([]alert.Event{deadLetters[1].AlertEvent()})
+	if deadLetters, _ = dao.List("topic", "handler"); len(deadLetters) != 3 {
+		t.Errorf("unexpected number of dead letters after replay: %d", len(deadLetters))
+	}
+}
+
+func TestRetryHandler_QueueFull(t *testing.T) {
+	h := &failingHandler{failures: 1, attempts: make(map[string]int)}
+	dao := new(deadLetterDAO)
+	policy := NewRetryPolicy()
+	policy.InitialInterval = time.Hour
+	policy.MaxInterval = time.Hour
+	policy.MaxQueue = 2
+	r := newRetryHandler(HandlerSpec{Topic: "topic", ID: "handler"}, policy, h, dao, nopDiag{})
+	defer r.Close()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		r.Handle(alert.Event{State: alert.EventState{ID: id}})
+	}
+
+	// Only the events that fit in the queue wait to be retried, the others are dead-lettered.
+	deadLetters, _ := dao.List("topic", "handler")
+	if len(deadLetters) != 2 {
+		t.Fatalf("unexpected number of dead letters: got %d exp 2", len(deadLetters))
+	}
+	for i, id := range []string{"c", "d"} {
+		if d := deadLetters[i]; d.Event.State.ID != id || d.Attempts != 1 || d.Error != "retry queue is full: failed" {
+			t.Errorf("unexpected dead letter %d: %+v", i, d)
+		}
+	}
+	if got := r.queueFull.IntValue(); got != 2 {
+		t.Errorf("unexpected number of events not retried because the queue was full: got %d exp 2", got)
+	}
+}
+
+func TestRetryHandler_OrderPerAlert(t *testing.T) {
+	h := &failingHandler{failures: 1, attempts: make(map[string]int)}
+	dao := new(deadLetterDAO)
+	policy := NewRetryPolicy()
+	policy.InitialInterval = 10 * time.Millisecond
+	r := newRetryHandler(HandlerSpec{Topic: "topic", ID: "handler"}, policy, h, dao, nopDiag{})
+	defer r.Close()
+
+	// The recovery of an alert waiting to be retried is not delivered before it.
+	r.Handle(alert.Event{State: alert.EventState{ID: "a", Level: alert.Critical}})
+	r.Handle(alert.Event{State: alert.EventState{ID: "a", Level: alert.OK}})
+	if got := h.Events(); len(got) != 0 {
+		t.Fatalf("unexpected events delivered before the retry: %v", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(h.Events()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	events := h.Events()
+	if len(events) != 2 {
+		t.Fatalf("unexpected number of events delivered: got %d exp 2", len(events))
+	}
+	for i, exp := range []alert.Level{alert.Critical, alert.OK} {
+		if got := events[i].State.Level; got != exp {
+			t.Errorf("unexpected level of event %d: got %v exp %v", i, got, exp)
+		}
+	}
+	if deadLetters, _ := dao.List("topic", "handler"); len(deadLetters) != 0 {
+		t.Errorf("unexpected dead letters: %+v", deadLetters)
+	}
+}
+
+func TestRetryHandler_IndependentBackoff(t *testing.T) {
+	h := &failingHandler{failures: 1, attempts: make(map[string]int)}
+	dao := new(deadLetterDAO)
+	policy := NewRetryPolicy()
+	policy.InitialInterval = 100 * time.Millisecond
+	r := newRetryHandler(HandlerSpec{Topic: "topic", ID: "handler"}, policy, h, dao, nopDiag{})
+	defer r.Close()
+
+	// Each event waits for its own interval, instead of the interval of all the events queued before it.
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	start := time.Now()
+	for _, id := range ids {
+		r.Handle(alert.Event{State: alert.EventState{ID: id}})
+	}
+	deadline := start.Add(5 * time.Second)
+	for len(h.Handled()) < len(ids) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := h.Handled(); len(got) != len(ids) {
+		t.Fatalf("unexpected handled events: %v", got)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Duration(len(ids))*policy.InitialInterval/2 {
+		t.Errorf("retrying %d events took %v, expected about one interval of %v", len(ids), elapsed, policy.InitialInterval)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/influxdata/kapacitor/alert"
 	"github.com/influxdata/kapacitor/command"
@@ -69,9 +70,20 @@ type Service struct {
 	disabled map[string]struct{}
 	// Handler store API
 	specsDAO HandlerSpecDAO
+	// Silences and acknowledgements store
+	suppressionDAO SuppressionDAO
 	// V2 topic store
 	topicsStore   storage.Interface
 	PersistTopics bool
+	// History of state transitions of events
+	historyDAO HistoryDAO
+	// HistoryRetention is how long the history of events is kept, zero disables the history.
+	HistoryRetention time.Duration
+	// Events that handlers failed to handle
+	deadLetterDAO DeadLetterDAO
+
+	closing chan struct{}
+	wg      sync.WaitGroup
 
 	APIServer *apiServer
 
@@ -174,10 +186,13 @@ func NewService(d Diagnostic, disabled map[string]struct{}, topicBufLen int) *Se
 		inhibitorLookup: alert.NewInhibitorLookup(),
 	}
 	s.APIServer = &apiServer{
-		Registrar: s,
-		Topics:    s,
-		Persister: s,
-		diag:      d,
+		Registrar:   s,
+		Topics:      s,
+		Persister:   s,
+		Silencer:    s,
+		Historian:   s,
+		DeadLetters: s,
+		diag:        d,
 	}
 	s.EventCollector = s
 	return s
@@ -191,6 +206,13 @@ const (
 	AlertNameSpace = "alert_store"
 	// TopicStatesNameSpace - The storage namespace for the V2 topic store and nothing else
 	TopicStatesNameSpace = "topic_states_store"
+	// AlertHistoryNameSpace - The storage namespace for the history of events
+	AlertHistoryNameSpace = "alert_history_store"
+	// AlertDeadLettersNameSpace - The storage namespace for the events that handlers failed to handle
+	AlertDeadLettersNameSpace = "alert_dead_letters_store"
+
+	// How often expired history is deleted.
+	historyPurgeInterval = time.Hour
 )
 
 func (s *Service) Open() error {
@@ -204,8 +226,15 @@ func (s *Service) Open() error {
 	}
 	s.specsDAO = specsDAO
 	s.StorageService.Register(handlerSpecsAPIName, s.specsDAO)
+	suppressionDAO, err := newSuppressionKV(store)
+	if err != nil {
+		return err
+	}
+	s.suppressionDAO = suppressionDAO
 	s.topicsStore = s.StorageService.Store(TopicStatesNameSpace)
 	// NOTE: since the topics store doesn't use the indexing store, we don't need to register the api
+	s.historyDAO = NewHistoryKV(s.StorageService.Store(AlertHistoryNameSpace))
+	s.deadLetterDAO = NewDeadLetterKV(s.StorageService.Store(AlertDeadLettersNameSpace))
 
 	// Migrate v1.2 handlers
 	if err := s.migrateHandlerSpecs(store); err != nil {
@@ -226,6 +255,20 @@ func (s *Service) Open() error {
 		return err
 	}
 
+	// Load saved silences and acknowledgements
+	if err := s.loadSavedSuppressions(); err != nil {
+		return err
+	}
+
+	if s.HistoryRetention > 0 {
+		s.closing = make(chan struct{})
+		s.wg.Add(1)
+		go func(closing <-chan struct{}) {
+			defer s.wg.Done()
+			s.runHistoryPurge(closing)
+		}(s.closing)
+	}
+
 	s.APIServer.HTTPDService = s.HTTPDService
 	if err := s.APIServer.Open(); err != nil {
 		return err
@@ -237,7 +280,18 @@ func (s *Service) Open() error {
 func (s *Service) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.closing != nil {
+		close(s.closing)
+		s.closing = nil
+	}
+	s.wg.Wait()
 	s.topics.Close()
+	// Close the handlers so that the events waiting to be retried are dead-lettered.
+	for _, handlers := range s.handlers {
+		for _, h := range handlers {
+			closeHandler(h)
+		}
+	}
 	return s.APIServer.Close()
 }
 
@@ -387,6 +441,42 @@ func (s *Service) loadSavedHandlerSpecs() error {
 	return nil
 }
 
+func (s *Service) loadSavedSuppressions() error {
+	now := time.Now()
+	silences, err := s.suppressionDAO.Silences()
+	if err != nil {
+		return err
+	}
+	for _, silence := range silences {
+		if !now.Before(silence.Expires) {
+			if err := s.suppressionDAO.DeleteSilence(silence.Topic, silence.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		as, err := silence.AlertSilence()
+		if err != nil {
+			s.diag.Error("failed to load silence on startup", err, keyvalue.KV("topic", silence.Topic), keyvalue.KV("silence", silence.ID))
+			continue
+		}
+		s.topics.SetSilence(silence.Topic, as)
+	}
+	acks, err := s.suppressionDAO.Acknowledgements()
+	if err != nil {
+		return err
+	}
+	for _, ack := range acks {
+		if !ack.AlertAcknowledgement().Active(now) {
+			if err := s.suppressionDAO.DeleteAcknowledgement(ack.Topic, ack.Event); err != nil {
+				return err
+			}
+			continue
+		}
+		s.topics.Acknowledge(ack.Topic, ack.Event, ack.AlertAcknowledgement())
+	}
+	return nil
+}
+
 func convertEventStateToAlert(id string, state *EventState) *alert.EventState {
 	return &alert.EventState{
 		ID:       id,
@@ -483,10 +573,26 @@ func (s *Service) Collect(event alert.Event) error {
 		}
 	}
 
+	previous, known := s.topics.EventState(event.Topic, event.State.ID)
+
+	if ack, ok := s.topics.Acknowledgement(event.Topic, event.State.ID); ok && ack.Level != event.State.Level {
+		// The level of the event changed so the acknowledgement no longer applies.
+		if err := s.Unacknowledge(event.Topic, event.State.ID); err != nil {
+			s.diag.Error("failed to remove acknowledgement", err, keyvalue.KV("topic", event.Topic), keyvalue.KV("event", event.State.ID))
+		}
+	}
+
 	err := s.topics.Collect(event)
 	if err != nil {
 		return err
 	}
+	// Only transitions of the level are recorded, an event first seen as OK is not a transition.
+	// Failing to record the history must not prevent persisting the state of the event.
+	if (known && previous.Level != event.State.Level) || (!known && event.State.Level != alert.OK) {
+		if err := s.appendHistory(event, previous.Level); err != nil {
+			s.diag.Error("failed to record event history", err, keyvalue.KV("topic", event.Topic), keyvalue.KV("event", event.State.ID))
+		}
+	}
 	// Events with alert.OK status should always only be resets from other statuses.
 	if event.State.Level == alert.OK && s.PersistTopics {
 		if err := s.clearHistory(&event); err != nil {
@@ -522,6 +628,103 @@ func (s *Service) persistEventState(event alert.Event) error {
 	})
 }
 
+func (s *Service) appendHistory(event alert.Event, previous alert.Level) error {
+	if s.HistoryRetention <= 0 {
+		return nil
+	}
+	return s.historyDAO.Append(HistoryEntry{
+		Topic:         event.Topic,
+		Event:         event.State.ID,
+		Time:          event.State.Time,
+		Level:         event.State.Level,
+		PreviousLevel: previous,
+		Message:       event.State.Message,
+		Duration:      event.State.Duration,
+	})
+}
+
+// EventHistory returns the recorded state transitions of an event with start <= time < stop
+// and a level of at least minLevel, ordered by time.
+func (s *Service) EventHistory(topic, event string, start, stop time.Time, minLevel alert.Level) ([]HistoryEntry, error) {
+	if s.HistoryRetention > 0 {
+		// Hide entries that have expired but have not yet been purged.
+		if oldest := time.Now().Add(-s.HistoryRetention); start.Before(oldest) {
+			start = oldest
+		}
+	}
+	history, err := s.historyDAO.History(topic, event, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	filtered := history[:0]
+	for _, h := range history {
+		if h.Level >= minLevel {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered, nil
+}
+
+// ErrNoRetryPolicy is returned when dead letters are replayed to a handler without a retry policy.
+var ErrNoRetryPolicy = errors.New("handler has no retry policy")
+
+func (s *Service) DeadLetters(topic, handler string) ([]DeadLetter, error) {
+	return s.deadLetterDAO.List(topic, handler)
+}
+
+func (s *Service) ReplayDeadLetters(topic, handler string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	h, ok := s.handlers[topic][handler]
+	if !ok {
+		return 0, fmt.Errorf("unknown handler %q in topic %q", handler, topic)
+	}
+	if h.retry == nil {
+		return 0, ErrNoRetryPolicy
+	}
+	deadLetters, err := s.deadLetterDAO.List(topic, handler)
+	if err != nil {
+		return 0, err
+	}
+	// Events that fail again are dead-lettered again with a new ID.
+	events := make([]alert.Event, 0, len(deadLetters))
+	for _, d := range deadLetters {
+		if err = s.deadLetterDAO.Delete(topic, handler, d.ID); err != nil {
+			break
+		}
+		events = append(events, d.AlertEvent())
+	}
+	h.retry.Replay(events)
+	return len(events), err
+}
+
+func (s *Service) PurgeDeadLetters(topic, handler string) error {
+	return s.deadLetterDAO.DeleteHandler(topic, handler)
+}
+
+// runHistoryPurge purges the expired history in the background, first when opening
+// so that a large backlog does not delay startup and then periodically.
+func (s *Service) runHistoryPurge(closing <-chan struct{}) {
+	s.purgeHistory()
+	ticker := time.NewTicker(historyPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+			s.purgeHistory()
+		}
+	}
+}
+
+// purgeHistory deletes the history older than the retention period.
+func (s *Service) purgeHistory() {
+	if err := s.historyDAO.DeleteBefore(time.Now().Add(-s.HistoryRetention)); err != nil {
+		s.diag.Error("failed to purge expired event history", err)
+	}
+}
+
 func (s *Service) clearHistory(event *alert.Event) error {
 	// clear on-disk EventStates, but leave the in-memory history
 	return s.topicsStore.Update(func(tx storage.Tx) error {
@@ -610,11 +813,145 @@ func (s *Service) DeleteTopic(topic string) error {
 	defer s.mu.Unlock()
 	delete(s.closedTopics, topic)
 	s.topics.DeleteTopic(topic)
+	if err := s.deleteSuppressions(topic); err != nil {
+		return err
+	}
+	if err := s.historyDAO.DeleteTopic(topic); err != nil {
+		return err
+	}
 	return s.topicsStore.Update(func(tx storage.Tx) error {
 		return tx.Delete(topic)
 	})
 }
 
+// deleteSuppressions deletes all silences and acknowledgements of the topic.
+// Caller must have the write lock.
+func (s *Service) deleteSuppressions(topic string) error {
+	s.topics.DeleteSuppressions(topic)
+	silences, err := s.suppressionDAO.Silences()
+	if err != nil {
+		return err
+	}
+	for _, silence := range silences {
+		if silence.Topic == topic {
+			if err := s.suppressionDAO.DeleteSilence(topic, silence.ID); err != nil {
+				return err
+			}
+		}
+	}
+	acks, err := s.suppressionDAO.Acknowledgements()
+	if err != nil {
+		return err
+	}
+	for _, ack := range acks {
+		if ack.Topic == topic {
+			if err := s.suppressionDAO.DeleteAcknowledgement(topic, ack.Event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Silences returns the silences of the topic, expired silences are deleted.
+func (s *Service) Silences(topic string) ([]Silence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.suppressionDAO.Silences()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	silences := make([]Silence, 0, len(all))
+	for _, silence := range all {
+		if silence.Topic != topic {
+			continue
+		}
+		if !now.Before(silence.Expires) {
+			if err := s.suppressionDAO.DeleteSilence(topic, silence.ID); err != nil {
+				return nil, err
+			}
+			s.topics.RemoveSilence(topic, silence.ID)
+			continue
+		}
+		silences = append(silences, silence)
+	}
+	return silences, nil
+}
+
+// Silence returns the silence of the topic.
+func (s *Service) Silence(topic, id string) (Silence, bool, error) {
+	silence, err := s.suppressionDAO.Silence(topic, id)
+	if err == ErrNoSilenceExists {
+		return Silence{}, false, nil
+	} else if err != nil {
+		return Silence{}, false, err
+	}
+	return silence, true, nil
+}
+
+// PutSilence saves the silence and starts enforcing it, replacing any silence with the same ID.
+func (s *Service) PutSilence(silence Silence) error {
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	as, err := silence.AlertSilence()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.suppressionDAO.PutSilence(silence); err != nil {
+		return err
+	}
+	s.topics.SetSilence(silence.Topic, as)
+	return nil
+}
+
+// DeleteSilence deletes the silence of the topic.
+func (s *Service) DeleteSilence(topic, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.suppressionDAO.DeleteSilence(topic, id); err != nil {
+		return err
+	}
+	s.topics.RemoveSilence(topic, id)
+	return nil
+}
+
+// Acknowledgement returns the acknowledgement of the event.
+func (s *Service) Acknowledgement(topic, event string) (Acknowledgement, bool, error) {
+	ack, err := s.suppressionDAO.Acknowledgement(topic, event)
+	if err == ErrNoAcknowledgementExists {
+		return Acknowledgement{}, false, nil
+	} else if err != nil {
+		return Acknowledgement{}, false, err
+	}
+	return ack, true, nil
+}
+
+// Acknowledge saves the acknowledgement and starts enforcing it, replacing any previous acknowledgement of the event.
+func (s *Service) Acknowledge(ack Acknowledgement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.suppressionDAO.PutAcknowledgement(ack); err != nil {
+		return err
+	}
+	s.topics.Acknowledge(ack.Topic, ack.Event, ack.AlertAcknowledgement())
+	return nil
+}
+
+// Unacknowledge deletes the acknowledgement of the event.
+func (s *Service) Unacknowledge(topic, event string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.suppressionDAO.DeleteAcknowledgement(topic, event); err != nil {
+		return err
+	}
+	s.topics.Unacknowledge(topic, event)
+	return nil
+}
+
 func (s *Service) UpdateEvent(topic string, event alert.EventState) error {
 	s.topics.UpdateEvent(topic, event)
 	return s.persistEventState(alert.Event{
@@ -659,11 +996,13 @@ func (s *Service) RegisterHandlerSpec(spec HandlerSpec) error {
 
 	_, ok := s.handlers[spec.Topic][spec.ID]
 	if ok {
+		closeHandler(h)
 		return fmt.Errorf("cannot register handler, handler with ID %q already exists", spec.ID)
 	}
 
 	// Persist handler spec
 	if err := s.specsDAO.Create(spec); err != nil {
+		closeHandler(h)
 		return err
 	}
 
@@ -689,9 +1028,9 @@ func (s *Service) DeregisterHandlerSpec(topic, handler string) error {
 			return err
 		}
 		s.topics.DeregisterHandler(topic, h.Handler)
-
-		if ha, ok := h.Handler.(closer); ok {
-			ha.Close()
+		closeHandler(h)
+		if err := s.deadLetterDAO.DeleteHandler(topic, handler); err != nil {
+			return err
 		}
 
 		delete(s.handlers[h.Spec.Topic], handler)
@@ -720,13 +1059,16 @@ func (s *Service) UpdateHandlerSpec(oldSpec, newSpec HandlerSpec) error {
 	// Persist new handler specs
 	if newSpec.ID == oldSpec.ID {
 		if err := s.specsDAO.Replace(newSpec); err != nil {
+			closeHandler(newH)
 			return err
 		}
 	} else {
 		if err := s.specsDAO.Create(newSpec); err != nil {
+			closeHandler(newH)
 			return err
 		}
 		if err := s.specsDAO.Delete(oldSpec.Topic, oldSpec.ID); err != nil {
+			closeHandler(newH)
 			return err
 		}
 	}
@@ -735,6 +1077,7 @@ func (s *Service) UpdateHandlerSpec(oldSpec, newSpec HandlerSpec) error {
 	s.setTopicHandler(newSpec.Topic, newSpec.ID, newH)
 
 	s.topics.ReplaceHandler(topic, oldH.Handler, newH.Handler)
+	closeHandler(oldH)
 	return nil
 }
 
@@ -858,10 +1201,14 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		keyvalue.KV("handler", spec.ID),
 		keyvalue.KV("topic", spec.Topic),
 	}
+	options, retryPolicy, err := retryPolicyFromOptions(spec.Options)
+	if err != nil {
+		return handler{}, err
+	}
 	switch spec.Kind {
 	case "aggregate":
 		c := newDefaultAggregateHandlerConfig(s.EventCollector)
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -872,7 +1219,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		}
 	case "alerta":
 		c := s.AlertaService.DefaultHandlerConfig()
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -883,7 +1230,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "bigpanda":
 		c := bigpanda.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -894,7 +1241,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "discord":
 		c := discord.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -907,7 +1254,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		c := ExecHandlerConfig{
 			Commander: s.Commander,
 		}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -916,7 +1263,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "hipchat":
 		c := hipchat.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -924,7 +1271,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "kafka":
 		c := kafka.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -935,7 +1282,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "log":
 		c := DefaultLogHandlerConfig()
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -947,7 +1294,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "mqtt":
 		c := mqtt.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -958,7 +1305,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "opsgenie":
 		c := opsgenie.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -966,7 +1313,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "opsgenie2":
 		c := opsgenie2.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -974,7 +1321,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "pagerduty":
 		c := pagerduty.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -982,7 +1329,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "pagerduty2":
 		c := pagerduty2.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -993,7 +1340,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "pushover":
 		c := pushover.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1001,7 +1348,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "post":
 		c := httppost.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1014,7 +1361,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		c := PublishHandlerConfig{
 			ec: s.EventCollector,
 		}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1022,7 +1369,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = NewPublishHandler(c, handlerDiag)
 	case "sensu":
 		c := sensu.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1033,7 +1380,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "servicenow":
 		c := servicenow.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1041,7 +1388,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "slack":
 		c := slack.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1049,7 +1396,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "smtp":
 		c := smtp.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1057,7 +1404,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "snmptrap":
 		c := snmptrap.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1071,7 +1418,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "tcp":
 		c := TCPHandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1080,7 +1427,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "teams":
 		c := teams.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1088,7 +1435,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "telegram":
 		c := telegram.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1096,7 +1443,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "victorops":
 		c := victorops.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1104,7 +1451,7 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 		h = newExternalHandler(h)
 	case "zenoss":
 		c := zenoss.HandlerConfig{}
-		err = decodeOptions(spec.Options, &c)
+		err = decodeOptions(options, &c)
 		if err != nil {
 			return handler{}, err
 		}
@@ -1116,6 +1463,10 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 	if h == nil && err != nil {
 		return handler{}, err
 	}
+	if retryPolicy != nil && !isFallible(h) {
+		closeHandler(handler{Handler: h})
+		return handler{}, fmt.Errorf("handler kind %q does not support the %s option", spec.Kind, retryOption)
+	}
 	if spec.Match != "" {
 		// Wrap handler in match handler
 		handlerDiag := s.diag.WithHandlerContext(ctx...)
@@ -1128,7 +1479,27 @@ func (s *Service) createHandlerFromSpec(spec HandlerSpec) (handler, error) {
 			return handler{Spec: spec, Handler: h}, err2
 		}
 	}
-	return handler{Spec: spec, Handler: h}, err
+	var retry *retryHandler
+	if err == nil && retryPolicy != nil {
+		// The match handler is retried as well, since it passes on the failures of the handler.
+		retry = newRetryHandler(spec, *retryPolicy, h.(alert.FallibleHandler), s.deadLetterDAO, s.diag.WithHandlerContext(ctx...))
+		h = retry
+	}
+	if err == nil && h != nil {
+		h = newStatsHandler(spec, h)
+	}
+	return handler{Spec: spec, Handler: h, retry: retry}, err
+}
+
+// closeHandler closes a handler created from a spec, if it can be closed.
+func closeHandler(h handler) {
+	if c, ok := h.Handler.(closer); ok {
+		c.Close()
+	}
+	if h.retry != nil {
+		// Match handlers do not close the handlers they wrap.
+		h.retry.Close()
+	}
 }
 
 func (s *Service) IsInhibited(name string, tags models.Tags) bool {
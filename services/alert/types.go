@@ -1,6 +1,8 @@
 package alert
 
 import (
+	"time"
+
 	"github.com/influxdata/kapacitor/alert"
 	"github.com/influxdata/kapacitor/models"
 )
@@ -66,9 +68,48 @@ type TopicPersister interface {
 	RestoreTopic(topic string) error
 }
 
+// Silencer is responsible for silencing and acknowledging events of topics.
+type Silencer interface {
+	// Silences returns the unexpired silences of the topic.
+	Silences(topic string) ([]Silence, error)
+	// Silence returns a silence of the topic.
+	Silence(topic, id string) (Silence, bool, error)
+	// PutSilence saves the silence, replacing any silence with the same ID.
+	PutSilence(silence Silence) error
+	// DeleteSilence deletes a silence of the topic.
+	DeleteSilence(topic, id string) error
+
+	// Acknowledgement returns the acknowledgement of the event.
+	Acknowledgement(topic, event string) (Acknowledgement, bool, error)
+	// Acknowledge saves the acknowledgement, replacing any previous acknowledgement of the event.
+	Acknowledge(ack Acknowledgement) error
+	// Unacknowledge deletes the acknowledgement of the event.
+	Unacknowledge(topic, event string) error
+}
+
+// Historian is responsible for querying the history of state transitions of events.
+type Historian interface {
+	// EventHistory returns the state transitions of the event with start <= time < stop
+	// and a level of at least minLevel, ordered by time.
+	// A zero start or stop leaves that end of the range unbounded.
+	EventHistory(topic, event string, start, stop time.Time, minLevel alert.Level) ([]HistoryEntry, error)
+}
+
+type DeadLetterer interface {
+	// DeadLetters returns the events that the handler failed to handle, ordered by time.
+	DeadLetters(topic, handler string) ([]DeadLetter, error)
+	// ReplayDeadLetters passes the dead-lettered events to the handler again
+	// and returns the number of events that were replayed.
+	ReplayDeadLetters(topic, handler string) (int, error)
+	// PurgeDeadLetters deletes the events that the handler failed to handle.
+	PurgeDeadLetters(topic, handler string) error
+}
+
 type handler struct {
 	Spec    HandlerSpec
 	Handler alert.Handler
+	// retry is the retry handler of the spec, nil if the spec has no retry policy.
+	retry *retryHandler
 }
 
 // InhibitorLookup provides lookup access to inhibitors
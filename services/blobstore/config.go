@@ -0,0 +1,24 @@
+package blobstore
+
+import "fmt"
+
+// DefaultMaxBlobSize is the default maximum size in bytes of a single blob, blob sizes are not limited by default.
+const DefaultMaxBlobSize = 0
+
+type Config struct {
+	// MaxBlobSize is the maximum size in bytes of a single blob, zero means no limit.
+	MaxBlobSize int64 `toml:"max-blob-size"`
+}
+
+func NewConfig() Config {
+	return Config{
+		MaxBlobSize: DefaultMaxBlobSize,
+	}
+}
+
+func (c Config) Validate() error {
+	if c.MaxBlobSize < 0 {
+		return fmt.Errorf("max-blob-size must not be negative, got %d", c.MaxBlobSize)
+	}
+	return nil
+}
@@ -0,0 +1,391 @@
+package blobstore
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/kapacitor/services/storage"
+)
+
+var (
+	ErrBlobExists   = errors.New("blob already exists")
+	ErrNoBlobExists = errors.New("no blob exists")
+
+	ErrNoTagExists = errors.New("no tag exists")
+)
+
+// Data access object for Blob data.
+type BlobDAO interface {
+	// Retrieve a blob's metadata.
+	Get(id string) (Blob, error)
+
+	// Store a chunk of content, content is the Content of the blob the chunk will belong to.
+	PutChunk(content string, index int, data []byte) error
+
+	// Retrieve a chunk of the content of a blob.
+	Chunk(blob Blob, index int) ([]byte, error)
+
+	// Create a blob whose content chunks were stored with PutChunk.
+	// ErrBlobExists is returned if a blob already exists with the same ID.
+	Create(blob Blob) error
+
+	// Delete a blob and its content.
+	// It is not an error to delete an non-existent blob.
+	Delete(id string) error
+
+	// Delete the chunks of content that no blob refers to.
+	DeleteContent(content string) error
+
+	// Delete the chunks of all content that no blob refers to,
+	// as left by uploads that were interrupted.
+	DeleteUnreferencedContent() error
+
+	// List blobs matching a pattern.
+	// The pattern is shell/glob matching see https://golang.org/pkg/path/#Match
+	// Offset and limit are pagination bounds. Offset is inclusive starting at index 0.
+	// More results may exist while the number of returned items is equal to limit.
+	List(pattern string, offset, limit int) ([]Blob, error)
+
+	// Rebuild fixes all indexes of the data.
+	Rebuild() error
+}
+
+// Data access object for Tag data.
+type TagDAO interface {
+	// Retrieve a tag
+	Get(name string) (Tag, error)
+
+	// Put a tag, creating or replacing any existing tag with the same name.
+	Put(tag Tag) error
+
+	// Delete a tag.
+	// It is not an error to delete an non-existent tag.
+	Delete(name string) error
+
+	// List tags matching a pattern.
+	// The pattern is shell/glob matching see https://golang.org/pkg/path/#Match
+	// Offset and limit are pagination bounds. Offset is inclusive starting at index 0.
+	// More results may exist while the number of returned items is equal to limit.
+	List(pattern string, offset, limit int) ([]Tag, error)
+
+	// Rebuild fixes all indexes of the data.
+	Rebuild() error
+}
+
+//--------------------------------------------------------------------
+// The following structures are stored in a database via gob encoding.
+// Changes to the structures could break existing data.
+
+// Blob is the metadata of a single stored blob.
+// The content of the blob is stored separately in chunks.
+type Blob struct {
+	// ID is the hex encoded sha256 sum of the content.
+	ID      string
+	Size    int64
+	Created time.Time
+	// Content identifies the chunks of the content,
+	// it is chosen when the upload starts since the ID is only known once it ends.
+	Content string
+	// Chunks is the number of chunks of the content.
+	Chunks int
+}
+
+type rawBlob Blob
+
+func (b Blob) ObjectID() string {
+	return b.ID
+}
+
+func (b Blob) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(rawBlob(b))
+	return buf.Bytes(), err
+}
+
+func (b *Blob) UnmarshalBinary(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*rawBlob)(b))
+}
+
+// TagEntry records that a tag referred to a blob from the given date.
+type TagEntry struct {
+	BlobID string
+	Date   time.Time
+}
+
+// Tag is a named reference to a blob.
+type Tag struct {
+	Name string
+	// BlobID is the ID of the blob the tag currently refers to.
+	BlobID   string
+	Modified time.Time
+	// History of all blobs the tag has referred to, oldest first.
+	// The last entry is always the current blob.
+	History []TagEntry
+}
+
+type rawTag Tag
+
+func (t Tag) ObjectID() string {
+	return t.Name
+}
+
+func (t Tag) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(rawTag(t))
+	return buf.Bytes(), err
+}
+
+func (t *Tag) UnmarshalBinary(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*rawTag)(t))
+}
+
+// Name of the blob created index
+const blobCreatedIndex = "created"
+
+// createdIndexLayout is RFC3339 with fixed width nanoseconds,
+// so that index values sort lexically in time order.
+// RFC3339Nano trims trailing zeros and does not sort correctly.
+const createdIndexLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Prefix of the keys storing blob content
+const blobContentPrefix = "/blobs/content/"
+
+// Key/Value based implementation of the BlobDAO.
+type blobKV struct {
+	store *storage.IndexedStore
+}
+
+func newBlobKV(store storage.Interface) (*blobKV, error) {
+	c := storage.DefaultIndexedStoreConfig("blobs", func() storage.BinaryObject {
+		return new(Blob)
+	})
+	c.Indexes = append(c.Indexes, storage.Index{
+		Name: blobCreatedIndex,
+		ValueFunc: func(o storage.BinaryObject) (string, error) {
+			b, ok := o.(*Blob)
+			if !ok {
+				return "", storage.ImpossibleTypeErr(b, o)
+			}
+			// The store appends the ID to the value, so blobs created
+			// at the same time still have distinct index keys.
+			return b.Created.UTC().Format(createdIndexLayout), nil
+		},
+	})
+	istore, err := storage.NewIndexedStore(store, c)
+	if err != nil {
+		return nil, err
+	}
+	return &blobKV{
+		store: istore,
+	}, nil
+}
+
+// contentPrefix returns the prefix of the keys of the chunks of content.
+func (kv *blobKV) contentPrefix(content string) string {
+	return blobContentPrefix + content + "/"
+}
+
+// chunkKey returns the key of a chunk, keys sort in the order of the chunks.
+func (kv *blobKV) chunkKey(content string, index int) string {
+	return fmt.Sprintf("%s%08d", kv.contentPrefix(content), index)
+}
+
+func (kv *blobKV) error(err error) error {
+	if err == storage.ErrNoObjectExists {
+		return ErrNoBlobExists
+	} else if err == storage.ErrObjectExists {
+		return ErrBlobExists
+	}
+	return err
+}
+
+func (kv *blobKV) Rebuild() error {
+	return kv.store.Rebuild()
+}
+
+func (kv *blobKV) Get(id string) (Blob, error) {
+	o, err := kv.store.Get(id)
+	if err != nil {
+		return Blob{}, kv.error(err)
+	}
+	b, ok := o.(*Blob)
+	if !ok {
+		return Blob{}, storage.ImpossibleTypeErr(b, o)
+	}
+	return *b, nil
+}
+
+func (kv *blobKV) PutChunk(content string, index int, data []byte) error {
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		return tx.Put(kv.chunkKey(content, index), data)
+	})
+}
+
+func (kv *blobKV) Chunk(b Blob, index int) (data []byte, err error) {
+	err = kv.store.Store().View(func(tx storage.ReadOnlyTx) error {
+		value, err := tx.Get(kv.chunkKey(b.Content, index))
+		if err == storage.ErrNoKeyExists {
+			return ErrNoBlobExists
+		} else if err != nil {
+			return err
+		}
+		data = value.Value
+		return nil
+	})
+	return
+}
+
+func (kv *blobKV) Create(b Blob) error {
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		return kv.error(kv.store.CreateTx(tx, &b))
+	})
+}
+
+func (kv *blobKV) Delete(id string) error {
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		o, err := kv.store.GetTx(tx, id)
+		if err == storage.ErrNoObjectExists {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		b, ok := o.(*Blob)
+		if !ok {
+			return storage.ImpossibleTypeErr(b, o)
+		}
+		if err := kv.store.DeleteTx(tx, id); err != nil {
+			return err
+		}
+		return kv.deleteKeys(tx, kv.contentPrefix(b.Content), nil)
+	})
+}
+
+func (kv *blobKV) DeleteContent(content string) error {
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		return kv.deleteKeys(tx, kv.contentPrefix(content), nil)
+	})
+}
+
+func (kv *blobKV) DeleteUnreferencedContent() error {
+	blobs, err := kv.List("", 0, -1)
+	if err != nil {
+		return err
+	}
+	referenced := make(map[string]bool, len(blobs))
+	for _, b := range blobs {
+		referenced[b.Content] = true
+	}
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		return kv.deleteKeys(tx, blobContentPrefix, func(key string) bool {
+			content, _, _ := strings.Cut(strings.TrimPrefix(key, blobContentPrefix), "/")
+			return !referenced[content]
+		})
+	})
+}
+
+// deleteKeys deletes the keys with the prefix that match, or all keys with the prefix if match is nil,
+// without reading their values.
+func (kv *blobKV) deleteKeys(tx storage.Tx, prefix string, match func(key string) bool) error {
+	cursor := tx.Cursor()
+	if cursor == nil {
+		return nil
+	}
+	// Collect the keys before deleting them since deleting moves the cursor.
+	var keys []string
+	for k, _ := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = cursor.Next() {
+		if match == nil || match(string(k)) {
+			keys = append(keys, string(k))
+		}
+	}
+	for _, key := range keys {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kv *blobKV) List(pattern string, offset, limit int) ([]Blob, error) {
+	objects, err := kv.store.ReverseList(blobCreatedIndex, pattern, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	blobs := make([]Blob, len(objects))
+	for i, o := range objects {
+		b, ok := o.(*Blob)
+		if !ok {
+			return nil, storage.ImpossibleTypeErr(b, o)
+		}
+		blobs[i] = *b
+	}
+	return blobs, nil
+}
+
+// Key/Value based implementation of the TagDAO.
+type tagKV struct {
+	store *storage.IndexedStore
+}
+
+func newTagKV(store storage.Interface) (*tagKV, error) {
+	c := storage.DefaultIndexedStoreConfig("tags", func() storage.BinaryObject {
+		return new(Tag)
+	})
+	istore, err := storage.NewIndexedStore(store, c)
+	if err != nil {
+		return nil, err
+	}
+	return &tagKV{
+		store: istore,
+	}, nil
+}
+
+func (kv *tagKV) error(err error) error {
+	if err == storage.ErrNoObjectExists {
+		return ErrNoTagExists
+	}
+	return err
+}
+
+func (kv *tagKV) Rebuild() error {
+	return kv.store.Rebuild()
+}
+
+func (kv *tagKV) Get(name string) (Tag, error) {
+	o, err := kv.store.Get(name)
+	if err != nil {
+		return Tag{}, kv.error(err)
+	}
+	t, ok := o.(*Tag)
+	if !ok {
+		return Tag{}, storage.ImpossibleTypeErr(t, o)
+	}
+	return *t, nil
+}
+
+func (kv *tagKV) Put(t Tag) error {
+	return kv.error(kv.store.Put(&t))
+}
+
+func (kv *tagKV) Delete(name string) error {
+	return kv.store.Delete(name)
+}
+
+func (kv *tagKV) List(pattern string, offset, limit int) ([]Tag, error) {
+	objects, err := kv.store.List(storage.DefaultIDIndex, pattern, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]Tag, len(objects))
+	for i, o := range objects {
+		t, ok := o.(*Tag)
+		if !ok {
+			return nil, storage.ImpossibleTypeErr(t, o)
+		}
+		tags[i] = *t
+	}
+	return tags, nil
+}
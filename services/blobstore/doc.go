@@ -0,0 +1,16 @@
+/*
+Blobstore provides a content addressable store for arbitrary data.
+
+Blobs are immutable and opaque to Kapacitor.
+Each blob is identified by the hex encoded sha256 sum of its content,
+so storing the same content twice results in a single blob.
+
+Tags provide named references to blobs.
+A tag always refers to the most recently associated blob,
+and the history of all blobs a tag has referred to is preserved.
+This allows, for example, a UDF to store successive versions of a trained model
+under a single name and retrieve either the latest or a specific version.
+
+The data is persisted via the storage service and exposed via the HTTP API.
+*/
+package blobstore
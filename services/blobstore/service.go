@@ -0,0 +1,659 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/services/storage"
+	"github.com/influxdata/kapacitor/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	blobsPath             = "/blobs"
+	blobsPathAnchored     = "/blobs/"
+	blobsBasePath         = httpd.BasePath + blobsPath
+	blobsBasePathAnchored = httpd.BasePath + blobsPathAnchored
+
+	tagsPath         = "tags"
+	tagsPathAnchored = "tags/"
+	dataPath         = "data"
+)
+
+const (
+	// Public name of the blobs store
+	blobsAPIName = "blobs"
+	// Public name of the blob tags store
+	tagsAPIName = "blob-tags"
+	// The storage namespace for all blob data.
+	blobsNamespace = "blob_store"
+)
+
+var (
+	validTagName = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
+	validBlobID  = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+	ErrBlobTagged   = errors.New("blob is referenced by a tag")
+	ErrBlobTooLarge = errors.New("blob exceeds the maximum blob size")
+)
+
+type Diagnostic interface {
+	Error(msg string, err error, ctx ...keyvalue.T)
+}
+
+// Service stores blobs and their tags and exposes them via the HTTP API.
+type Service struct {
+	// mu serializes modifications to tags and blobs
+	// so that a tagged blob cannot be deleted.
+	mu sync.Mutex
+
+	maxBlobSize int64
+
+	blobs BlobDAO
+	tags  TagDAO
+
+	routes []httpd.Route
+
+	StorageService interface {
+		Store(namespace string) storage.Interface
+		Register(name string, store storage.StoreActioner)
+	}
+	HTTPDService interface {
+		AddRoutes([]httpd.Route) error
+		DelRoutes([]httpd.Route)
+	}
+
+	diag Diagnostic
+}
+
+func NewService(c Config, d Diagnostic) *Service {
+	return &Service{
+		maxBlobSize: c.MaxBlobSize,
+		diag:        d,
+	}
+}
+
+func (s *Service) Open() error {
+	store := s.StorageService.Store(blobsNamespace)
+	blobs, err := newBlobKV(store)
+	if err != nil {
+		return err
+	}
+	s.blobs = blobs
+	s.StorageService.Register(blobsAPIName, s.blobs)
+	if err := s.blobs.DeleteUnreferencedContent(); err != nil {
+		return errors.Wrap(err, "deleting content of interrupted blob uploads")
+	}
+
+	tags, err := newTagKV(store)
+	if err != nil {
+		return err
+	}
+	s.tags = tags
+	s.StorageService.Register(tagsAPIName, s.tags)
+
+	s.routes = []httpd.Route{
+		{
+			Method:      "GET",
+			Pattern:     blobsPath,
+			HandlerFunc: s.handleListBlobs,
+		},
+		{
+			Method:      "POST",
+			Pattern:     blobsPath,
+			HandlerFunc: s.handleCreateBlob,
+		},
+		{
+			Method:      "GET",
+			Pattern:     blobsPathAnchored,
+			HandlerFunc: s.handleRouteGet,
+		},
+		{
+			Method:      "PUT",
+			Pattern:     blobsPathAnchored,
+			HandlerFunc: s.handleRoutePut,
+		},
+		{
+			Method:      "DELETE",
+			Pattern:     blobsPathAnchored,
+			HandlerFunc: s.handleRouteDelete,
+		},
+		{
+			// Satisfy CORS checks.
+			Method:      "OPTIONS",
+			Pattern:     blobsPathAnchored,
+			HandlerFunc: httpd.ServeOptions,
+		},
+	}
+	return s.HTTPDService.AddRoutes(s.routes)
+}
+
+func (s *Service) Close() error {
+	if s.HTTPDService != nil {
+		s.HTTPDService.DelRoutes(s.routes)
+	}
+	return nil
+}
+
+// BlobID returns the content address for the data.
+// It is the ID of the blob CreateBlob creates from the data.
+func BlobID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// blobChunkSize is the size of the chunks the content of blobs is stored in,
+// so that blobs are streamed in and out of the store without holding them in memory.
+const blobChunkSize = 1 << 20
+
+// CreateBlob stores the data read from r as a new blob, one chunk per transaction.
+// If a blob with the same content already exists it is returned and created is false.
+// ErrBlobTooLarge is returned if the data exceeds the configured maximum blob size.
+func (s *Service) CreateBlob(r io.Reader) (blob Blob, created bool, err error) {
+	// The ID is only known once all the data is read, the chunks are stored under a unique content ID instead.
+	content := uuid.New().String()
+	defer func() {
+		if created {
+			return
+		}
+		if err := s.blobs.DeleteContent(content); err != nil {
+			s.diag.Error("failed to delete content of blob that was not created", err, keyvalue.KV("content", content))
+		}
+	}()
+	h := sha256.New()
+	buf := make([]byte, blobChunkSize)
+	var size int64
+	chunks := 0
+	for {
+		n, rerr := io.ReadFull(r, buf)
+		if n > 0 {
+			size += int64(n)
+			if s.maxBlobSize > 0 && size > s.maxBlobSize {
+				return Blob{}, false, errors.Wrapf(ErrBlobTooLarge, "more than %d bytes", s.maxBlobSize)
+			}
+			h.Write(buf[:n])
+			if err := s.blobs.PutChunk(content, chunks, buf[:n]); err != nil {
+				return Blob{}, false, err
+			}
+			chunks++
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return Blob{}, false, errors.Wrap(rerr, "failed to read blob data")
+		}
+	}
+	blob = Blob{
+		ID:      hex.EncodeToString(h.Sum(nil)),
+		Size:    size,
+		Created: time.Now().UTC(),
+		Content: content,
+		Chunks:  chunks,
+	}
+	err = s.blobs.Create(blob)
+	if err == ErrBlobExists {
+		blob, err = s.blobs.Get(blob.ID)
+		return blob, false, err
+	}
+	if err != nil {
+		return Blob{}, false, err
+	}
+	return blob, true, nil
+}
+
+// Blob returns the metadata for a blob.
+func (s *Service) Blob(id string) (Blob, error) {
+	return s.blobs.Get(id)
+}
+
+// Reader returns the metadata of a blob and a reader of its content.
+// The content is read one chunk at a time.
+func (s *Service) Reader(id string) (Blob, io.Reader, error) {
+	blob, err := s.blobs.Get(id)
+	if err != nil {
+		return Blob{}, nil, err
+	}
+	return blob, &blobReader{blobs: s.blobs, blob: blob}, nil
+}
+
+// Data returns the content of a blob.
+func (s *Service) Data(id string) ([]byte, error) {
+	_, r, err := s.Reader(id)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// blobReader reads the content of a blob one chunk per transaction.
+type blobReader struct {
+	blobs BlobDAO
+	blob  Blob
+	next  int
+	buf   []byte
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.next == r.blob.Chunks {
+			return 0, io.EOF
+		}
+		data, err := r.blobs.Chunk(r.blob, r.next)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to read chunk %d of blob %s", r.next, r.blob.ID)
+		}
+		r.next++
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// DeleteBlob deletes a blob.
+// ErrBlobTagged is returned if any tag currently refers to the blob.
+func (s *Service) DeleteBlob(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags, err := s.tags.List("", 0, -1)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if t.BlobID == id {
+			return errors.Wrapf(ErrBlobTagged, "tag %q", t.Name)
+		}
+	}
+	return s.blobs.Delete(id)
+}
+
+// Tag returns a tag and its history.
+func (s *Service) Tag(name string) (Tag, error) {
+	return s.tags.Get(name)
+}
+
+// TagData returns the content of the blob a tag currently refers to.
+func (s *Service) TagData(name string) ([]byte, error) {
+	t, err := s.tags.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.Data(t.BlobID)
+}
+
+// SetTag associates a tag with a blob, creating the tag if it does not exist.
+// Setting a tag to the blob it already refers to is a no-op.
+func (s *Service) SetTag(name, blobID string) (Tag, error) {
+	if !validTagName.MatchString(name) {
+		return Tag{}, fmt.Errorf("tag name must contain only letters, numbers, '-', '.' and '_'. %q", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.blobs.Get(blobID); err != nil {
+		return Tag{}, err
+	}
+	t, err := s.tags.Get(name)
+	if err != nil && err != ErrNoTagExists {
+		return Tag{}, err
+	}
+	if err == nil && t.BlobID == blobID {
+		return t, nil
+	}
+	now := time.Now().UTC()
+	t.Name = name
+	t.BlobID = blobID
+	t.Modified = now
+	t.History = append(t.History, TagEntry{
+		BlobID: blobID,
+		Date:   now,
+	})
+	if err := s.tags.Put(t); err != nil {
+		return Tag{}, err
+	}
+	return t, nil
+}
+
+// DeleteTag deletes a tag, the blobs it referred to are left intact.
+func (s *Service) DeleteTag(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tags.Delete(name)
+}
+
+func blobLink(id string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(blobsBasePath, id)}
+}
+func blobDataLink(id string) client.Link {
+	return client.Link{Relation: dataPath, Href: path.Join(blobsBasePath, id, dataPath)}
+}
+func tagLink(name string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(blobsBasePath, tagsPath, name)}
+}
+func tagDataLink(name string) client.Link {
+	return client.Link{Relation: dataPath, Href: path.Join(blobsBasePath, tagsPath, name, dataPath)}
+}
+
+func convertBlob(b Blob) client.Blob {
+	return client.Blob{
+		Link:     blobLink(b.ID),
+		DataLink: blobDataLink(b.ID),
+		ID:       b.ID,
+		Size:     b.Size,
+		Created:  b.Created,
+	}
+}
+
+func convertTag(t Tag) client.BlobTag {
+	history := make([]client.BlobTagEntry, len(t.History))
+	for i, e := range t.History {
+		history[i] = client.BlobTagEntry{
+			Blob: e.BlobID,
+			Date: e.Date,
+		}
+	}
+	blob := blobLink(t.BlobID)
+	blob.Relation = "blob"
+	return client.BlobTag{
+		Link:     tagLink(t.Name),
+		DataLink: tagDataLink(t.Name),
+		BlobLink: blob,
+		Name:     t.Name,
+		Blob:     t.BlobID,
+		Modified: t.Modified,
+		History:  history,
+	}
+}
+
+func httpStatus(err error) int {
+	switch errors.Cause(err) {
+	case ErrNoBlobExists, ErrNoTagExists:
+		return http.StatusNotFound
+	case ErrBlobTagged:
+		return http.StatusConflict
+	case ErrBlobTooLarge:
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// parseListOptions returns the pattern, offset and limit of a list request.
+func parseListOptions(r *http.Request) (string, int, int, error) {
+	pattern := r.URL.Query().Get("pattern")
+	var err error
+	offset := int64(0)
+	offsetStr := r.URL.Query().Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid offset parameter %q must be an integer: %s", offsetStr, err)
+		}
+	}
+
+	limit := int64(100)
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid limit parameter %q must be an integer: %s", limitStr, err)
+		}
+	}
+	return pattern, int(offset), int(limit), nil
+}
+
+func (s *Service) handleListBlobs(w http.ResponseWriter, r *http.Request) {
+	pattern, offset, limit, err := parseListOptions(r)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	blobs, err := s.blobs.List(pattern, offset, limit)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to list blobs with pattern %q: %s", pattern, err), true, http.StatusBadRequest)
+		return
+	}
+	list := client.Blobs{
+		Link:  client.Link{Relation: client.Self, Href: r.URL.String()},
+		Blobs: make([]client.Blob, len(blobs)),
+	}
+	for i, b := range blobs {
+		list.Blobs[i] = convertBlob(b)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(list, true))
+}
+
+func (s *Service) handleCreateBlob(w http.ResponseWriter, r *http.Request) {
+	tag := r.URL.Query().Get("tag")
+	if tag != "" && !validTagName.MatchString(tag) {
+		httpd.HttpError(w, fmt.Sprintf("tag name must contain only letters, numbers, '-', '.' and '_'. %q", tag), true, http.StatusBadRequest)
+		return
+	}
+	body := &bodyReader{r: r.Body}
+	if s.maxBlobSize > 0 {
+		body.r = http.MaxBytesReader(w, r.Body, s.maxBlobSize)
+	}
+	blob, created, err := s.CreateBlob(body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(body.err, &maxErr):
+			httpd.HttpError(w, fmt.Sprintf("blob data exceeds the maximum blob size of %d bytes", s.maxBlobSize), true, http.StatusRequestEntityTooLarge)
+		case body.err != nil:
+			httpd.HttpError(w, fmt.Sprint("failed to read blob data: ", body.err), true, http.StatusBadRequest)
+		default:
+			httpd.HttpError(w, fmt.Sprint("failed to create blob: ", err), true, httpStatus(err))
+		}
+		return
+	}
+	if tag != "" {
+		if _, err := s.SetTag(tag, blob.ID); err != nil {
+			httpd.HttpError(w, fmt.Sprintf("failed to tag blob %s as %q: %v", blob.ID, tag, err), true, httpStatus(err))
+			return
+		}
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.Write(httpd.MarshalJSON(convertBlob(blob), true))
+}
+
+// bodyReader records the error reading a request body,
+// to tell errors of the client apart from errors storing what it sent.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// splitPath splits a path relative to the blobs base path into
+// whether it refers to a tag, the blob ID or tag name, and whether the data is requested.
+func splitPath(p string) (isTag bool, id string, data bool) {
+	if strings.HasPrefix(p, tagsPathAnchored) {
+		isTag = true
+		p = strings.TrimPrefix(p, tagsPathAnchored)
+	}
+	if dir, base := path.Split(p); dir != "" && base == dataPath {
+		data = true
+		p = strings.TrimSuffix(dir, "/")
+	}
+	return isTag, p, data
+}
+
+func (s *Service) handleRouteGet(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, blobsBasePathAnchored), "/")
+	if p == tagsPath {
+		s.handleListTags(w, r)
+		return
+	}
+	isTag, id, data := splitPath(p)
+	switch {
+	case id == "":
+		httpd.HttpError(w, "must specify blob id or tag name on path", true, http.StatusBadRequest)
+	case isTag && data:
+		s.handleTagData(id, w, r)
+	case isTag:
+		s.handleGetTag(id, w, r)
+	case data:
+		s.handleBlobData(id, w, r)
+	default:
+		s.handleGetBlob(id, w, r)
+	}
+}
+
+func (s *Service) handleRoutePut(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, blobsBasePathAnchored), "/")
+	isTag, name, data := splitPath(p)
+	if !isTag || data || name == "" {
+		httpd.HttpError(w, "only blob tags can be updated, blobs are immutable", true, http.StatusMethodNotAllowed)
+		return
+	}
+	s.handlePutTag(name, w, r)
+}
+
+func (s *Service) handleRouteDelete(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, blobsBasePathAnchored), "/")
+	isTag, id, data := splitPath(p)
+	if data || id == "" {
+		httpd.HttpError(w, "must specify blob id or tag name on path", true, http.StatusBadRequest)
+		return
+	}
+	var err error
+	if isTag {
+		err = s.DeleteTag(id)
+	} else {
+		err = s.DeleteBlob(id)
+	}
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Service) handleGetBlob(id string, w http.ResponseWriter, r *http.Request) {
+	if !validBlobID.MatchString(id) {
+		httpd.HttpError(w, fmt.Sprintf("invalid blob id %q", id), true, http.StatusBadRequest)
+		return
+	}
+	blob, err := s.blobs.Get(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get blob %s: %v", id, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(convertBlob(blob), true))
+}
+
+func (s *Service) handleBlobData(id string, w http.ResponseWriter, r *http.Request) {
+	blob, data, err := s.Reader(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get blob %s: %v", id, err), true, httpStatus(err))
+		return
+	}
+	s.writeData(blob, data, w)
+}
+
+func (s *Service) handleTagData(name string, w http.ResponseWriter, r *http.Request) {
+	t, err := s.tags.Get(name)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get tag %q: %v", name, err), true, httpStatus(err))
+		return
+	}
+	blob, data, err := s.Reader(t.BlobID)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get blob %s for tag %q: %v", t.BlobID, name, err), true, httpStatus(err))
+		return
+	}
+	s.writeData(blob, data, w)
+}
+
+// writeData streams the content of a blob.
+func (s *Service) writeData(blob Blob, data io.Reader, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, blob.ID))
+	w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, data); err != nil {
+		// The headers have already been sent, the client can compare
+		// the Content-Length with the amount of data received.
+		s.diag.Error("failed to send blob data", err, keyvalue.KV("blob", blob.ID))
+	}
+}
+
+func (s *Service) handleListTags(w http.ResponseWriter, r *http.Request) {
+	pattern, offset, limit, err := parseListOptions(r)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	tags, err := s.tags.List(pattern, offset, limit)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to list blob tags with pattern %q: %s", pattern, err), true, http.StatusBadRequest)
+		return
+	}
+	list := client.BlobTags{
+		Link: client.Link{Relation: client.Self, Href: r.URL.String()},
+		Tags: make([]client.BlobTag, len(tags)),
+	}
+	for i, t := range tags {
+		list.Tags[i] = convertTag(t)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(list, true))
+}
+
+func (s *Service) handleGetTag(name string, w http.ResponseWriter, r *http.Request) {
+	t, err := s.tags.Get(name)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get tag %q: %v", name, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(convertTag(t), true))
+}
+
+func (s *Service) handlePutTag(name string, w http.ResponseWriter, r *http.Request) {
+	opt := client.BlobTagOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid tag json: ", err), true, http.StatusBadRequest)
+		return
+	}
+	if !validBlobID.MatchString(opt.Blob) {
+		httpd.HttpError(w, fmt.Sprintf("invalid blob id %q", opt.Blob), true, http.StatusBadRequest)
+		return
+	}
+	if !validTagName.MatchString(name) {
+		httpd.HttpError(w, fmt.Sprintf("tag name must contain only letters, numbers, '-', '.' and '_'. %q", name), true, http.StatusBadRequest)
+		return
+	}
+	t, err := s.SetTag(name, opt.Blob)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to tag blob %s as %q: %v", opt.Blob, name, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(convertTag(t), true))
+}
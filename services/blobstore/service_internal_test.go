@@ -0,0 +1,115 @@
+package blobstore
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/services/storage"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+)
+
+type nopDiag struct{}
+
+func (nopDiag) Error(msg string, err error, ctx ...keyvalue.T) {}
+
+// failingReader fails after reading its data.
+type failingReader struct {
+	r io.Reader
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+// contentKeys returns the keys of the chunks of all content in the store.
+func contentKeys(t *testing.T, store storage.Interface) []string {
+	t.Helper()
+	var keys []string
+	if err := store.View(func(tx storage.ReadOnlyTx) error {
+		kvs, err := tx.List(blobContentPrefix)
+		for _, kv := range kvs {
+			keys = append(keys, kv.Key)
+		}
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestService_CreateBlobContent(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := db.Store(blobsNamespace)
+	blobs, err := newBlobKV(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(NewConfig(), nopDiag{})
+	s.blobs = blobs
+
+	content := strings.Repeat("x", 2*blobChunkSize+1)
+	blob, created, err := s.CreateBlob(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || blob.Chunks != 3 {
+		t.Fatalf("unexpected blob created %v: %+v", created, blob)
+	}
+	if got := len(contentKeys(t, store)); got != 3 {
+		t.Fatalf("unexpected number of chunks: got %d exp 3", got)
+	}
+
+	// Uploading the same content again keeps the chunks of the existing blob only.
+	again, created, err := s.CreateBlob(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || again.Content != blob.Content {
+		t.Errorf("unexpected blob on duplicate create %v: %+v", created, again)
+	}
+	// Failed uploads do not leave chunks behind.
+	if _, _, err := s.CreateBlob(failingReader{r: strings.NewReader(content)}); err == nil {
+		t.Error("expected error creating blob from failing reader")
+	}
+	if got := len(contentKeys(t, store)); got != 3 {
+		t.Errorf("unexpected number of chunks after duplicate and failed uploads: got %d exp 3", got)
+	}
+
+	// Chunks of uploads interrupted before they were cleaned up are deleted.
+	if err := blobs.PutChunk("interrupted", 0, []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := blobs.DeleteUnreferencedContent(); err != nil {
+		t.Fatal(err)
+	}
+	keys := contentKeys(t, store)
+	if len(keys) != 3 {
+		t.Fatalf("unexpected chunks after deleting unreferenced content: %v", keys)
+	}
+	data, err := s.Data(blob.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte(content)) {
+		t.Errorf("unexpected blob data of %d bytes, exp %d bytes", len(data), len(content))
+	}
+
+	if err := blobs.Delete(blob.ID); err != nil {
+		t.Fatal(err)
+	}
+	if keys := contentKeys(t, store); len(keys) != 0 {
+		t.Errorf("unexpected chunks of deleted blob: %v", keys)
+	}
+}
@@ -0,0 +1,264 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/services/blobstore"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httpd/httpdtest"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+	"github.com/pkg/errors"
+)
+
+var diagService *diagnostic.Service
+
+func init() {
+	diagService = diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	diagService.Open()
+}
+
+func OpenNewService(t *testing.T, c blobstore.Config) (*blobstore.Service, *client.Client) {
+	service := blobstore.NewService(c, diagService.NewBlobStoreHandler())
+	store := storagetest.New(t, diagService.NewStorageHandler())
+	t.Cleanup(func() { store.Close() })
+	service.StorageService = store
+	server := httpdtest.NewServer(testing.Verbose())
+	t.Cleanup(func() { server.Close() })
+	service.HTTPDService = server
+	if err := service.Open(); err != nil {
+		t.Fatal(err)
+	}
+	cli, err := client.New(client.Config{URL: server.Server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, cli
+}
+
+func readData(t *testing.T, cli *client.Client, link client.Link) []byte {
+	t.Helper()
+	r, err := cli.BlobData(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestService_CreateBlob(t *testing.T) {
+	_, cli := OpenNewService(t, blobstore.NewConfig())
+
+	content := []byte("model v1")
+	blob, err := cli.CreateBlob(bytes.NewReader(content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := blobstore.BlobID(content), blob.ID; exp != got {
+		t.Errorf("unexpected blob ID: got %s exp %s", got, exp)
+	}
+	if exp, got := int64(len(content)), blob.Size; exp != got {
+		t.Errorf("unexpected blob size: got %d exp %d", got, exp)
+	}
+
+	// Creating the same content again returns the same blob.
+	again, err := cli.CreateBlob(bytes.NewReader(content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(blob, again) {
+		t.Errorf("unexpected blob on duplicate create:\ngot\n%+v\nexp\n%+v", again, blob)
+	}
+
+	got, err := cli.Blob(cli.BlobLink(blob.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(blob, got) {
+		t.Errorf("unexpected blob:\ngot\n%+v\nexp\n%+v", got, blob)
+	}
+	if data := readData(t, cli, blob.DataLink); !bytes.Equal(data, content) {
+		t.Errorf("unexpected blob data: got %q exp %q", data, content)
+	}
+
+	blobs, err := cli.ListBlobs(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs.Blobs) != 1 || blobs.Blobs[0].ID != blob.ID {
+		t.Errorf("unexpected blob list %+v", blobs.Blobs)
+	}
+}
+
+func TestService_TagHistory(t *testing.T) {
+	_, cli := OpenNewService(t, blobstore.NewConfig())
+
+	v1, err := cli.CreateBlob(strings.NewReader("model v1"), &client.CreateBlobOptions{Tag: "model"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := cli.CreateBlob(strings.NewReader("model v2"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := cli.TagBlob(cli.BlobTagLink("model"), client.BlobTagOptions{Blob: v2.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Blob != v2.ID {
+		t.Errorf("unexpected tagged blob: got %s exp %s", tag.Blob, v2.ID)
+	}
+	// Re-tagging the current blob does not add history.
+	if _, err := cli.TagBlob(cli.BlobTagLink("model"), client.BlobTagOptions{Blob: v2.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	tag, err = cli.BlobTag(cli.BlobTagLink("model"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := make([]string, len(tag.History))
+	for i, e := range tag.History {
+		history[i] = e.Blob
+	}
+	if exp := []string{v1.ID, v2.ID}; !reflect.DeepEqual(history, exp) {
+		t.Errorf("unexpected tag history: got %v exp %v", history, exp)
+	}
+	if data := readData(t, cli, tag.DataLink); string(data) != "model v2" {
+		t.Errorf("unexpected tag data: got %q", data)
+	}
+
+	// A tagged blob cannot be deleted.
+	if err := cli.DeleteBlob(cli.BlobLink(v2.ID)); err == nil {
+		t.Error("expected error deleting tagged blob")
+	}
+	// Blobs only in the tag history can be deleted.
+	if err := cli.DeleteBlob(cli.BlobLink(v1.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Blob(cli.BlobLink(v1.ID)); err == nil {
+		t.Error("expected error getting deleted blob")
+	}
+
+	if err := cli.DeleteBlobTag(cli.BlobTagLink("model")); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := cli.ListBlobTags(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags.Tags) != 0 {
+		t.Errorf("unexpected tags after delete %+v", tags.Tags)
+	}
+	if err := cli.DeleteBlob(cli.BlobLink(v2.ID)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_TagUnknownBlob(t *testing.T) {
+	_, cli := OpenNewService(t, blobstore.NewConfig())
+	_, err := cli.TagBlob(cli.BlobTagLink("model"), client.BlobTagOptions{Blob: blobstore.BlobID([]byte("missing"))})
+	if err == nil {
+		t.Fatal("expected error tagging unknown blob")
+	}
+}
+
+func TestService_ListBlobsCreatedTogether(t *testing.T) {
+	_, cli := OpenNewService(t, blobstore.NewConfig())
+
+	// Blobs created within the same second are listed newest first.
+	var ids []string
+	for i := 0; i < 5; i++ {
+		blob, err := cli.CreateBlob(strings.NewReader(fmt.Sprintf("model v%d", i)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append([]string{blob.ID}, ids...)
+	}
+	listIDs := func() []string {
+		t.Helper()
+		blobs, err := cli.ListBlobs(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(blobs.Blobs))
+		for i, b := range blobs.Blobs {
+			got[i] = b.ID
+		}
+		return got
+	}
+	if got := listIDs(); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("unexpected blob list order: got %v exp %v", got, ids)
+	}
+
+	if err := cli.DeleteBlob(cli.BlobLink(ids[2])); err != nil {
+		t.Fatal(err)
+	}
+	ids = append(ids[:2], ids[3:]...)
+	if got := listIDs(); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("unexpected blob list after delete: got %v exp %v", got, ids)
+	}
+}
+
+func TestService_CreateBlobTooLarge(t *testing.T) {
+	c := blobstore.NewConfig()
+	c.MaxBlobSize = 8
+	service, cli := OpenNewService(t, c)
+
+	if _, err := cli.CreateBlob(strings.NewReader("too large for the store"), nil); err == nil {
+		t.Fatal("expected error creating blob larger than the maximum size")
+	}
+	if _, _, err := service.CreateBlob(strings.NewReader("too large for the store")); errors.Cause(err) != blobstore.ErrBlobTooLarge {
+		t.Fatalf("unexpected error: got %v exp %v", err, blobstore.ErrBlobTooLarge)
+	}
+	blobs, err := cli.ListBlobs(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs.Blobs) != 0 {
+		t.Errorf("unexpected blobs %+v", blobs.Blobs)
+	}
+	if _, err := cli.CreateBlob(strings.NewReader("small"), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_CreateBlobChunked(t *testing.T) {
+	service, cli := OpenNewService(t, blobstore.NewConfig())
+
+	// Blobs larger than a chunk are streamed in and out of the store.
+	content := bytes.Repeat([]byte("0123456789abcdef"), 160*1024)
+	blob, err := cli.CreateBlob(bytes.NewReader(content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := blobstore.BlobID(content), blob.ID; exp != got {
+		t.Errorf("unexpected blob ID: got %s exp %s", got, exp)
+	}
+	if exp, got := int64(len(content)), blob.Size; exp != got {
+		t.Errorf("unexpected blob size: got %d exp %d", got, exp)
+	}
+	if data := readData(t, cli, blob.DataLink); !bytes.Equal(data, content) {
+		t.Errorf("unexpected blob data of %d bytes, exp %d bytes", len(data), len(content))
+	}
+	if data, err := service.Data(blob.ID); err != nil || !bytes.Equal(data, content) {
+		t.Errorf("unexpected blob data of %d bytes, exp %d bytes: %v", len(data), len(content), err)
+	}
+
+	// Deleting the blob deletes its content.
+	if err := cli.DeleteBlob(cli.BlobLink(blob.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Data(blob.ID); errors.Cause(err) != blobstore.ErrNoBlobExists {
+		t.Errorf("unexpected error reading deleted blob: got %v exp %v", err, blobstore.ErrNoBlobExists)
+	}
+}
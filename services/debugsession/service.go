@@ -0,0 +1,421 @@
+// Package debugsession pauses the delivery of messages into a node of an executing task,
+// so that the messages can be inspected and stepped through one by one.
+//
+// A session sets a breakpoint on the edges into the node.
+// The breakpoint is removed when the session is deleted, has been idle for too long, or the task stops.
+//
+// Messages are forked to all tasks of a task master from a single goroutine,
+// so pausing a node stalls every task of its task master.
+// Sessions on the tasks of the main task master must therefore be forced.
+package debugsession
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/uuid"
+)
+
+const (
+	debugSessionsPath             = "/debug-sessions"
+	debugSessionsPathAnchored     = "/debug-sessions/"
+	debugSessionsBasePath         = httpd.BasePath + debugSessionsPath
+	debugSessionsBasePathAnchored = httpd.BasePath + debugSessionsPathAnchored
+)
+
+const (
+	// stateTimeout is how long a request waits for stepped messages to be delivered
+	// and for the next messages to arrive at the breakpoint.
+	stateTimeout = time.Second
+	// maxNext is the maximum number of next messages shown per edge.
+	maxNext = 100
+	// DefaultIdleTimeout is how long a session may go without requests before it is deleted.
+	DefaultIdleTimeout = 10 * time.Minute
+)
+
+var validID = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
+
+type session struct {
+	id       string
+	task     string
+	node     string
+	replayID string
+	created  time.Time
+
+	tm *kapacitor.TaskMaster
+	bp *edge.Breakpoint
+
+	// lastUsed is the time of the last request for the session, guarded by the service.
+	lastUsed time.Time
+}
+
+// attached reports whether the breakpoint of the session is still set on the task.
+func (s *session) attached() bool {
+	return s.tm.Breakpoint(s.task, s.node) == s.bp
+}
+
+func (s *session) close() {
+	if s.attached() {
+		s.tm.ClearBreakpoint(s.task, s.node)
+	}
+	s.bp.Clear()
+}
+
+type Service struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+
+	routes []httpd.Route
+
+	// IdleTimeout is how long a session may go without requests before it is deleted.
+	IdleTimeout time.Duration
+
+	closing chan struct{}
+	wg      sync.WaitGroup
+
+	HTTPDService interface {
+		AddRoutes([]httpd.Route) error
+		DelRoutes([]httpd.Route)
+	}
+	TaskMasterLookup interface {
+		Get(string) *kapacitor.TaskMaster
+	}
+}
+
+func NewService() *Service {
+	return &Service{
+		sessions:    make(map[string]*session),
+		IdleTimeout: DefaultIdleTimeout,
+	}
+}
+
+func (s *Service) Open() error {
+	s.routes = []httpd.Route{
+		{
+			Method:      "GET",
+			Pattern:     debugSessionsPath,
+			HandlerFunc: s.handleListSessions,
+		},
+		{
+			Method:      "POST",
+			Pattern:     debugSessionsPath,
+			HandlerFunc: s.handleCreateSession,
+		},
+		{
+			Method:      "GET",
+			Pattern:     debugSessionsPathAnchored,
+			HandlerFunc: s.handleGetSession,
+		},
+		{
+			Method:      "PATCH",
+			Pattern:     debugSessionsPathAnchored,
+			HandlerFunc: s.handleUpdateSession,
+		},
+		{
+			Method:      "DELETE",
+			Pattern:     debugSessionsPathAnchored,
+			HandlerFunc: s.handleDeleteSession,
+		},
+		{
+			// Satisfy CORS checks.
+			Method:      "OPTIONS",
+			Pattern:     debugSessionsPathAnchored,
+			HandlerFunc: httpd.ServeOptions,
+		},
+	}
+	if err := s.HTTPDService.AddRoutes(s.routes); err != nil {
+		return err
+	}
+	s.closing = make(chan struct{})
+	s.wg.Add(1)
+	go func(closing <-chan struct{}) {
+		defer s.wg.Done()
+		s.runExpire(closing)
+	}(s.closing)
+	return nil
+}
+
+func (s *Service) Close() error {
+	if s.HTTPDService != nil {
+		s.HTTPDService.DelRoutes(s.routes)
+	}
+	if s.closing != nil {
+		close(s.closing)
+		s.wg.Wait()
+		s.closing = nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		sess.close()
+		delete(s.sessions, id)
+	}
+	return nil
+}
+
+// createSession sets a paused breakpoint on the edges into the node of the task
+// executing on the given task master.
+func (s *Service) createSession(opt client.CreateDebugSessionOptions) (*session, error) {
+	if opt.ID == "" {
+		opt.ID = uuid.New().String()
+	}
+	if !validID.MatchString(opt.ID) {
+		return nil, fmt.Errorf("debug session ID must contain only letters, numbers, '-', '.' and '_'. %q", opt.ID)
+	}
+	if opt.Task == "" {
+		return nil, fmt.Errorf("must provide a task")
+	}
+	if opt.Node == "" {
+		return nil, fmt.Errorf("must provide a node")
+	}
+	tmID := opt.ReplayID
+	if tmID == "" {
+		tmID = kapacitor.MainTaskMaster
+	}
+	tm := s.TaskMasterLookup.Get(tmID)
+	if tm == nil {
+		return nil, fmt.Errorf("no running replay with ID: %s", tmID)
+	}
+	if tmID == kapacitor.MainTaskMaster && !opt.Force {
+		return nil, fmt.Errorf("pausing a node of enabled task %s stalls all tasks, debug a replay of the task or force the session", opt.Task)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[opt.ID]; ok {
+		return nil, fmt.Errorf("debug session %s already exists", opt.ID)
+	}
+	bp := edge.NewBreakpoint()
+	if err := tm.SetBreakpoint(opt.Task, opt.Node, bp); err != nil {
+		return nil, err
+	}
+	sess := &session{
+		id:       opt.ID,
+		task:     opt.Task,
+		node:     opt.Node,
+		replayID: opt.ReplayID,
+		created:  time.Now().UTC(),
+		tm:       tm,
+		bp:       bp,
+		lastUsed: time.Now(),
+	}
+	s.sessions[sess.id] = sess
+	return sess, nil
+}
+
+// session returns the session and marks it as used.
+func (s *Service) session(id string) (*session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if ok {
+		sess.lastUsed = time.Now()
+	}
+	return sess, ok
+}
+
+// deleteSession removes the breakpoint of the session and resumes the delivery of messages.
+func (s *Service) deleteSession(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	sess.close()
+	delete(s.sessions, id)
+	return true
+}
+
+func (s *Service) runExpire(closing <-chan struct{}) {
+	ticker := time.NewTicker(s.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case now := <-ticker.C:
+			s.expire(now)
+		}
+	}
+}
+
+// expire deletes the sessions that have been idle for longer than the idle timeout.
+func (s *Service) expire(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		if now.Sub(sess.lastUsed) > s.IdleTimeout {
+			sess.close()
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func sessionID(r *http.Request) string {
+	return strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, debugSessionsBasePathAnchored), "/")
+}
+
+func (s *Service) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].id < sessions[j].id })
+
+	list := client.DebugSessions{
+		Link:     client.Link{Relation: client.Self, Href: r.URL.String()},
+		Sessions: make([]client.DebugSession, len(sessions)),
+	}
+	for i, sess := range sessions {
+		// Do not wait for messages when listing sessions.
+		list.Sessions[i] = convertSession(sess, sess.bp.State(1, 0))
+	}
+	w.Write(httpd.MarshalJSON(list, true))
+}
+
+func (s *Service) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	opt := client.CreateDebugSessionOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid debug session json: ", err), true, http.StatusBadRequest)
+		return
+	}
+	sess, err := s.createSession(opt)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprint("failed to create debug session: ", err), true, http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(httpd.MarshalJSON(convertSession(sess, sess.bp.State(1, stateTimeout)), true))
+}
+
+func (s *Service) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	id := sessionID(r)
+	sess, ok := s.session(id)
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("debug session %q does not exist", id), true, http.StatusNotFound)
+		return
+	}
+	next := 1
+	if str := r.URL.Query().Get("next"); str != "" {
+		n, err := strconv.Atoi(str)
+		if err != nil || n < 1 || n > maxNext {
+			httpd.HttpError(w, fmt.Sprintf("invalid next parameter %q must be an integer between 1 and %d", str, maxNext), true, http.StatusBadRequest)
+			return
+		}
+		next = n
+	}
+	w.Write(httpd.MarshalJSON(convertSession(sess, sess.bp.State(next, stateTimeout)), true))
+}
+
+func (s *Service) handleUpdateSession(w http.ResponseWriter, r *http.Request) {
+	id := sessionID(r)
+	sess, ok := s.session(id)
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("debug session %q does not exist", id), true, http.StatusNotFound)
+		return
+	}
+	opt := client.UpdateDebugSessionOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid debug session json: ", err), true, http.StatusBadRequest)
+		return
+	}
+	switch opt.Action {
+	case client.DebugStep:
+		if opt.Count < 0 {
+			httpd.HttpError(w, fmt.Sprintf("invalid count %d must not be negative", opt.Count), true, http.StatusBadRequest)
+			return
+		}
+		if opt.Count == 0 {
+			opt.Count = 1
+		}
+		sess.bp.Step(opt.Count)
+	case client.DebugPause:
+		sess.bp.Pause()
+	case client.DebugResume:
+		sess.bp.Resume()
+	default:
+		httpd.HttpError(w, fmt.Sprintf("invalid action %q must be one of %q, %q or %q", opt.Action, client.DebugStep, client.DebugPause, client.DebugResume), true, http.StatusBadRequest)
+		return
+	}
+	w.Write(httpd.MarshalJSON(convertSession(sess, sess.bp.State(1, stateTimeout)), true))
+}
+
+func (s *Service) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	id := sessionID(r)
+	if !s.deleteSession(id) {
+		httpd.HttpError(w, fmt.Sprintf("debug session %q does not exist", id), true, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func convertSession(sess *session, state edge.BreakpointState) client.DebugSession {
+	cs := client.DebugSession{
+		Link:      client.Link{Relation: client.Self, Href: path.Join(debugSessionsBasePath, sess.id)},
+		ID:        sess.id,
+		Task:      sess.task,
+		Node:      sess.node,
+		ReplayID:  sess.replayID,
+		Attached:  sess.attached(),
+		Paused:    state.Paused,
+		Next:      make([]client.DebugMessage, len(state.Next)),
+		Delivered: make([]client.DebugMessage, len(state.Delivered)),
+		Created:   sess.created,
+	}
+	for i, m := range state.Next {
+		cs.Next[i] = convertMessage(m)
+	}
+	for i, m := range state.Delivered {
+		cs.Delivered[i] = convertMessage(m)
+	}
+	return cs
+}
+
+func convertMessage(em edge.EdgeMessage) client.DebugMessage {
+	m := em.Message
+	dm := client.DebugMessage{
+		Edge: em.Edge,
+		Type: m.Type().String(),
+	}
+	if n, ok := m.(edge.NameGetter); ok {
+		dm.Name = n.Name()
+	}
+	if t, ok := m.(edge.TimeGetter); ok {
+		dm.Time = t.Time()
+	}
+	if g, ok := m.(edge.GroupIDGetter); ok {
+		dm.Group = string(g.GroupID())
+	}
+	if t, ok := m.(edge.TagGetter); ok {
+		dm.Tags = t.Tags()
+	}
+	if f, ok := m.(edge.FieldGetter); ok {
+		dm.Fields = f.Fields()
+	}
+	if b, ok := m.(edge.BufferedBatchMessage); ok {
+		dm.Points = make([]client.DebugPoint, len(b.Points()))
+		for i, p := range b.Points() {
+			dm.Points[i] = client.DebugPoint{
+				Time:   p.Time(),
+				Tags:   p.Tags(),
+				Fields: p.Fields(),
+			}
+		}
+	}
+	return dm
+}
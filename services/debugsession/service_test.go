@@ -0,0 +1,194 @@
+package debugsession
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/server/vars"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const script = `
+stream
+    |from()
+        .measurement('cpu')
+        .groupBy('host')
+    |default()
+        .field('checked', TRUE)
+`
+
+var dbrp = kapacitor.DBRP{Database: "telegraf", RetentionPolicy: "autogen"}
+
+type deadman struct{}
+
+func (deadman) Interval() time.Duration { return 10 * time.Second }
+func (deadman) Threshold() float64      { return 0 }
+func (deadman) Id() string              { return "" }
+func (deadman) Message() string         { return "" }
+func (deadman) Global() bool            { return false }
+
+type taskStore struct{}
+
+func (taskStore) SaveSnapshot(string, *kapacitor.TaskSnapshot) error   { return nil }
+func (taskStore) HasSnapshot(string) bool                              { return false }
+func (taskStore) LoadSnapshot(string) (*kapacitor.TaskSnapshot, error) { return nil, nil }
+func (taskStore) LoadLibrary(string) (string, error)                   { return "", nil }
+
+// newTaskMaster returns an open task master executing the task "cpu" and a stream into it.
+func newTaskMaster(t *testing.T) (*kapacitor.TaskMaster, kapacitor.StreamCollector) {
+	t.Helper()
+	diag := diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	require.NoError(t, diag.Open())
+	t.Cleanup(func() { diag.Close() })
+	tm := kapacitor.NewTaskMaster(kapacitor.MainTaskMaster, vars.Info, diag.NewKapacitorHandler())
+	tm.DeadmanService = deadman{}
+	tm.TaskStore = taskStore{}
+	require.NoError(t, tm.Open())
+	t.Cleanup(func() { tm.Close() })
+	task, err := tm.NewTask("cpu", script, kapacitor.StreamTask, []kapacitor.DBRP{dbrp}, 0, nil)
+	require.NoError(t, err)
+	_, err = tm.StartTask(task)
+	require.NoError(t, err)
+	stream, err := tm.Stream("test")
+	require.NoError(t, err)
+	t.Cleanup(func() { stream.Close() })
+	return tm, stream
+}
+
+func writePoint(t *testing.T, stream kapacitor.StreamCollector, host string, value float64, sec int64) {
+	t.Helper()
+	p := edge.NewPointMessage("cpu", dbrp.Database, dbrp.RetentionPolicy, models.Dimensions{}, models.Fields{"value": value}, models.Tags{"host": host}, time.Unix(sec, 0).UTC())
+	require.NoError(t, stream.CollectPoint(p))
+}
+
+func do(t *testing.T, h http.HandlerFunc, method, url, body string) (*httptest.ResponseRecorder, client.DebugSession) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(method, httpd.BasePath+url, strings.NewReader(body)))
+	var sess client.DebugSession
+	if w.Code == http.StatusOK || w.Code == http.StatusCreated {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sess))
+	}
+	return w, sess
+}
+
+func TestService_Session(t *testing.T) {
+	tm, stream := newTaskMaster(t)
+	lookup := kapacitor.NewTaskMasterLookup()
+	lookup.Set(tm)
+	s := NewService()
+	s.TaskMasterLookup = lookup
+
+	_, err := s.createSession(client.CreateDebugSessionOptions{ID: "s", Task: "cpu", Node: "default2"})
+	assert.EqualError(t, err, "pausing a node of enabled task cpu stalls all tasks, debug a replay of the task or force the session")
+	_, err = s.createSession(client.CreateDebugSessionOptions{ID: "s", Task: "cpu", Node: "default2", Force: true})
+	require.NoError(t, err)
+	_, err = s.createSession(client.CreateDebugSessionOptions{ID: "other", Task: "cpu", Node: "default2", Force: true})
+	assert.EqualError(t, err, "node default2 already has a breakpoint")
+	_, err = s.createSession(client.CreateDebugSessionOptions{Task: "cpu", Node: "alert9", Force: true})
+	assert.EqualError(t, err, "unknown node alert9")
+	_, err = s.createSession(client.CreateDebugSessionOptions{Task: "mem", Node: "default2", Force: true})
+	assert.EqualError(t, err, "task mem is not executing")
+
+	writePoint(t, stream, "a", 1, 10)
+	writePoint(t, stream, "b", 2, 20)
+	writePoint(t, stream, "a", 3, 30)
+
+	w, sess := do(t, s.handleGetSession, "GET", "/debug-sessions/s?next=2", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, sess.Attached)
+	assert.True(t, sess.Paused)
+	assert.Empty(t, sess.Delivered)
+	require.Len(t, sess.Next, 2)
+	assert.Equal(t, client.DebugMessage{
+		Edge:   "from1->default2",
+		Type:   "point",
+		Name:   "cpu",
+		Time:   time.Unix(10, 0).UTC(),
+		Group:  "host=a",
+		Tags:   map[string]string{"host": "a"},
+		Fields: map[string]interface{}{"value": 1.0},
+	}, sess.Next[0])
+	assert.Equal(t, map[string]string{"host": "b"}, sess.Next[1].Tags)
+
+	w, sess = do(t, s.handleUpdateSession, "PATCH", "/debug-sessions/s", `{"action":"step"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, sess.Delivered, 1)
+	assert.Equal(t, time.Unix(10, 0).UTC(), sess.Delivered[0].Time)
+	require.Len(t, sess.Next, 1)
+	assert.Equal(t, time.Unix(20, 0).UTC(), sess.Next[0].Time)
+
+	w, _ = do(t, s.handleUpdateSession, "PATCH", "/debug-sessions/s", `{"action":"jump"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, sess = do(t, s.handleUpdateSession, "PATCH", "/debug-sessions/s", `{"action":"resume"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, sess.Paused)
+
+	w, _ = do(t, s.handleDeleteSession, "DELETE", "/debug-sessions/s", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w, _ = do(t, s.handleGetSession, "GET", "/debug-sessions/s", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestService_StopTask(t *testing.T) {
+	tm, stream := newTaskMaster(t)
+	lookup := kapacitor.NewTaskMasterLookup()
+	lookup.Set(tm)
+	s := NewService()
+	s.TaskMasterLookup = lookup
+
+	_, err := s.createSession(client.CreateDebugSessionOptions{ID: "s", Task: "cpu", Node: "default2", Force: true})
+	require.NoError(t, err)
+	writePoint(t, stream, "a", 1, 10)
+
+	// Stopping the task releases the paused node.
+	stopped := make(chan error, 1)
+	go func() { stopped <- tm.StopTask("cpu") }()
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out stopping task")
+	}
+
+	w, sess := do(t, s.handleGetSession, "GET", "/debug-sessions/s", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, sess.Attached)
+	assert.False(t, sess.Paused)
+}
+
+func TestService_Expire(t *testing.T) {
+	tm, stream := newTaskMaster(t)
+	lookup := kapacitor.NewTaskMasterLookup()
+	lookup.Set(tm)
+	s := NewService()
+	s.TaskMasterLookup = lookup
+
+	sess, err := s.createSession(client.CreateDebugSessionOptions{ID: "s", Task: "cpu", Node: "default2", Force: true})
+	require.NoError(t, err)
+	writePoint(t, stream, "a", 1, 10)
+
+	s.expire(time.Now())
+	_, ok := s.session("s")
+	require.True(t, ok)
+	assert.True(t, sess.attached())
+
+	s.expire(time.Now().Add(DefaultIdleTimeout + time.Second))
+	_, ok = s.session("s")
+	assert.False(t, ok)
+	assert.False(t, sess.attached())
+	assert.Nil(t, tm.Breakpoint("cpu", "default2"))
+}
@@ -981,6 +981,26 @@ func (h *ReplayHandler) Debug(msg string, ctx ...keyvalue.T) {
 	Debug(h.l, msg, ctx)
 }
 
+// BlobStore handler
+
+type BlobStoreHandler struct {
+	l Logger
+}
+
+func (h *BlobStoreHandler) Error(msg string, err error, ctx ...keyvalue.T) {
+	Err(h.l, msg, err, ctx)
+}
+
+// Schedule handler
+
+type ScheduleHandler struct {
+	l Logger
+}
+
+func (h *ScheduleHandler) Error(msg string, err error, ctx ...keyvalue.T) {
+	Err(h.l, msg, err, ctx)
+}
+
 // K8s handler
 
 type K8sHandler struct {
@@ -1083,6 +1103,24 @@ func (h *UDPHandler) ClosedService() {
 	h.l.Info("closed service")
 }
 
+// Kafka consumer handler
+
+type KafkaConsumerHandler struct {
+	l Logger
+}
+
+func (h *KafkaConsumerHandler) Error(msg string, err error, ctx ...keyvalue.T) {
+	Err(h.l, msg, err, ctx)
+}
+
+func (h *KafkaConsumerHandler) StartedConsuming(id, group string, topics []string) {
+	h.l.Info("started consuming from kafka", String("id", id), String("consumer_group", group), Strings("topics", topics))
+}
+
+func (h *KafkaConsumerHandler) ClosedService(id string) {
+	h.l.Info("closed service", String("id", id))
+}
+
 // InfluxDB handler
 
 type InfluxDBHandler struct {
@@ -312,6 +312,18 @@ func (s *Service) NewReplayHandler() *ReplayHandler {
 	}
 }
 
+func (s *Service) NewBlobStoreHandler() *BlobStoreHandler {
+	return &BlobStoreHandler{
+		l: s.Logger.With(String("service", "blobstore")),
+	}
+}
+
+func (s *Service) NewScheduleHandler() *ScheduleHandler {
+	return &ScheduleHandler{
+		l: s.Logger.With(String("service", "schedule")),
+	}
+}
+
 func (s *Service) NewK8sHandler() *K8sHandler {
 	return &K8sHandler{
 		l: s.Logger.With(String("service", "kubernetes")),
@@ -363,6 +375,12 @@ func (s *Service) NewUDPHandler() *UDPHandler {
 	}
 }
 
+func (s *Service) NewKafkaConsumerHandler() *KafkaConsumerHandler {
+	return &KafkaConsumerHandler{
+		l: s.Logger.With(String("service", "kafka_consumer")),
+	}
+}
+
 func (s *Service) NewInfluxDBHandler() *InfluxDBHandler {
 	return &InfluxDBHandler{
 		l: s.Logger.With(String("service", "influxdb")),
@@ -15,16 +15,21 @@ const (
 )
 
 type Config struct {
-	BindAddress      string        `toml:"bind-address"`
-	AuthEnabled      bool          `toml:"auth-enabled"`
-	LogEnabled       bool          `toml:"log-enabled"`
-	WriteTracing     bool          `toml:"write-tracing"`
-	PprofEnabled     bool          `toml:"pprof-enabled"`
-	HttpsEnabled     bool          `toml:"https-enabled"`
-	HttpsCertificate string        `toml:"https-certificate"`
-	HTTPSPrivateKey  string        `toml:"https-private-key"`
-	ShutdownTimeout  toml.Duration `toml:"shutdown-timeout"`
-	SharedSecret     string        `toml:"shared-secret"`
+	BindAddress        string        `toml:"bind-address"`
+	AuthEnabled        bool          `toml:"auth-enabled"`
+	LogEnabled         bool          `toml:"log-enabled"`
+	WriteTracing       bool          `toml:"write-tracing"`
+	PprofEnabled       bool          `toml:"pprof-enabled"`
+	MetricsAuthEnabled bool          `toml:"metrics-auth-enabled"`
+	HttpsEnabled       bool          `toml:"https-enabled"`
+	HttpsCertificate   string        `toml:"https-certificate"`
+	HTTPSPrivateKey    string        `toml:"https-private-key"`
+	ShutdownTimeout    toml.Duration `toml:"shutdown-timeout"`
+	SharedSecret       string        `toml:"shared-secret"`
+
+	// BucketMappings map the buckets of /api/v2/write requests to databases and retention policies.
+	// Buckets without a mapping are of the form "db/rp" or "db".
+	BucketMappings []BucketMapping `toml:"bucket-mapping"`
 
 	// Enable gzipped encoding
 	// NOTE: this is ignored in toml since it is only consumed by the tests
@@ -33,11 +38,12 @@ type Config struct {
 
 func NewConfig() Config {
 	return Config{
-		BindAddress:      ":9092",
-		LogEnabled:       true,
-		HttpsCertificate: "/etc/ssl/kapacitor.pem",
-		ShutdownTimeout:  DefaultShutdownTimeout,
-		GZIP:             true,
+		BindAddress:        ":9092",
+		LogEnabled:         true,
+		MetricsAuthEnabled: true,
+		HttpsCertificate:   "/etc/ssl/kapacitor.pem",
+		ShutdownTimeout:    DefaultShutdownTimeout,
+		GZIP:               true,
 	}
 }
 
@@ -55,6 +61,34 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid http bind address port %d: out of range", pn)
 	}
 
+	buckets := make(map[string]bool, len(c.BucketMappings))
+	for _, m := range c.BucketMappings {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		if buckets[m.Bucket] {
+			return fmt.Errorf("duplicate bucket mapping for bucket %q", m.Bucket)
+		}
+		buckets[m.Bucket] = true
+	}
+
+	return nil
+}
+
+// BucketMapping maps an InfluxDB 2.x bucket to a database and retention policy.
+type BucketMapping struct {
+	Bucket          string `toml:"bucket"`
+	Database        string `toml:"database"`
+	RetentionPolicy string `toml:"retention-policy"`
+}
+
+func (m BucketMapping) Validate() error {
+	if m.Bucket == "" {
+		return errors.New("bucket mapping must specify a bucket")
+	}
+	if m.Database == "" {
+		return fmt.Errorf("bucket mapping for bucket %q must specify a database", m.Bucket)
+	}
 	return nil
 }
 
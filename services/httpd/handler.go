@@ -60,6 +60,8 @@ type Route struct {
 	NoGzip      bool
 	NoJSON      bool
 	BypassAuth  bool
+	// NoAuth disables authentication of the route.
+	NoAuth bool
 }
 
 // Handler represents an HTTP handler for the Kapacitor API server.
@@ -84,6 +86,9 @@ type Handler struct {
 		SetLogLevelFromName(lvl string) error
 	}
 
+	// BucketMappings map the buckets of InfluxDB 2.x writes to databases and retention policies.
+	BucketMappings []BucketMapping
+
 	diag Diagnostic
 	// Detailed logging of write path
 	// Uses normal logger
@@ -99,6 +104,7 @@ type Handler struct {
 func NewHandler(
 	requireAuthentication,
 	pprofEnabled,
+	metricsAuthEnabled,
 	loggingEnabled,
 	writeTrace,
 	allowGzip bool,
@@ -183,6 +189,18 @@ func NewHandler(
 			Pattern:     "/write",
 			HandlerFunc: ServeOptions,
 		},
+		{
+			// InfluxDB 2.x compatible data-ingest route.
+			Method:      "POST",
+			Pattern:     "/api/v2/write",
+			HandlerFunc: h.serveWriteV2,
+		},
+		{
+			// Satisfy CORS checks.
+			Method:      "OPTIONS",
+			Pattern:     "/api/v2/write",
+			HandlerFunc: ServeOptions,
+		},
 		{
 			// Display current API routes
 			Method:      "GET",
@@ -236,6 +254,15 @@ func NewHandler(
 			HandlerFunc: serveExpvar,
 			BypassAuth:  true,
 		},
+		{
+			// Prometheus metrics
+			Method:      "GET",
+			Pattern:     "/metrics",
+			HandlerFunc: newMetricsHandler().ServeHTTP,
+			NoGzip:      true,
+			NoJSON:      true,
+			NoAuth:      !metricsAuthEnabled,
+		},
 	})
 
 	return h
@@ -298,7 +325,7 @@ func (h *Handler) addRawRoute(r Route) error {
 	// This is a normal handler signature so perform standard authentication/authorization.
 	if hf, ok := r.HandlerFunc.(func(http.ResponseWriter, *http.Request)); ok {
 		requireAuth := h.requireAuthentication
-		if r.BypassAuth && h.exposePprof {
+		if r.BypassAuth && h.exposePprof || r.NoAuth {
 			requireAuth = false
 		}
 		handler = authenticate(authorize(hf), h, requireAuth)
@@ -431,13 +458,23 @@ func (h *Handler) servePing(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user auth.User) {
 	h.statMap.Add(statWriteRequest, 1)
 
+	b, err := h.readWriteBody(r)
+	if err != nil {
+		h.writeError(w, query.Result{Err: err}, http.StatusBadRequest)
+		return
+	}
+
+	h.serveWriteLine(w, r, b, user)
+}
+
+// readWriteBody reads the body of a write request, decoding it if it is gzipped.
+func (h *Handler) readWriteBody(r *http.Request) ([]byte, error) {
 	// Handle gzip decoding of the body
 	body := r.Body
 	if r.Header.Get("Content-encoding") == "gzip" {
 		b, err := gzip.NewReader(r.Body)
 		if err != nil {
-			h.writeError(w, query.Result{Err: err}, http.StatusBadRequest)
-			return
+			return nil, err
 		}
 		body = b
 	}
@@ -448,15 +485,13 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user auth.U
 		if h.writeTrace {
 			h.diag.Error("write handler unabled to read bytes from request body", err)
 		}
-		h.writeError(w, query.Result{Err: err}, http.StatusBadRequest)
-		return
+		return nil, err
 	}
 	h.statMap.Add(statWriteRequestBytesReceived, int64(len(b)))
 	if h.writeTrace {
 		h.diag.WriteBodyReceived(string(b))
 	}
-
-	h.serveWriteLine(w, r, b, user)
+	return b, nil
 }
 
 // serveWriteLine receives incoming series data in line protocol format and writes it to the database.
@@ -467,20 +502,27 @@ func (h *Handler) serveWriteLine(w http.ResponseWriter, r *http.Request, body []
 		precision = "n"
 	}
 
+	code, err := h.writeLine(body, precision, qp.Get("db"), qp.Get("rp"), user)
+	if err != nil {
+		h.writeError(w, query.Result{Err: err}, code)
+		return
+	}
+	w.WriteHeader(code)
+}
+
+// writeLine parses the points of a line protocol body and writes them to the database.
+// It returns the status code of the response and the error of a failed write.
+func (h *Handler) writeLine(body []byte, precision, database, retentionPolicy string, user auth.User) (int, error) {
 	points, err := models.ParsePointsWithPrecision(body, time.Now().UTC(), precision)
 	if err != nil {
 		if err.Error() == "EOF" {
-			w.WriteHeader(http.StatusOK)
-			return
+			return http.StatusOK, nil
 		}
-		h.writeError(w, query.Result{Err: err}, http.StatusBadRequest)
-		return
+		return http.StatusBadRequest, err
 	}
 
-	database := qp.Get("db")
 	if database == "" {
-		h.writeError(w, query.Result{Err: fmt.Errorf("database is required")}, http.StatusBadRequest)
-		return
+		return http.StatusBadRequest, fmt.Errorf("database is required")
 	}
 
 	action := auth.Action{
@@ -488,28 +530,25 @@ func (h *Handler) serveWriteLine(w http.ResponseWriter, r *http.Request, body []
 		Privilege: auth.WritePrivilege,
 	}
 	if err := user.AuthorizeAction(action); err != nil {
-		h.writeError(w, query.Result{Err: fmt.Errorf("%q user is not authorized to write to database %q", user.Name(), database)}, http.StatusUnauthorized)
-		return
+		return http.StatusUnauthorized, fmt.Errorf("%q user is not authorized to write to database %q", user.Name(), database)
 	}
 
 	// Write points.
 	if err := h.PointsWriter.WritePoints(
 		database,
-		qp.Get("rp"),
+		retentionPolicy,
 		models.ConsistencyLevelAll,
 		points,
 	); influxdb.IsClientError(err) {
 		h.statMap.Add(statPointsWrittenFail, int64(len(points)))
-		h.writeError(w, query.Result{Err: err}, http.StatusBadRequest)
-		return
+		return http.StatusBadRequest, err
 	} else if err != nil {
 		h.statMap.Add(statPointsWrittenFail, int64(len(points)))
-		h.writeError(w, query.Result{Err: err}, http.StatusInternalServerError)
-		return
+		return http.StatusInternalServerError, err
 	}
 
 	h.statMap.Add(statPointsWrittenOK, int64(len(points)))
-	w.WriteHeader(http.StatusNoContent)
+	return http.StatusNoContent, nil
 }
 
 // MarshalJSON will marshal v to JSON. Pretty prints if pretty is true.
@@ -761,6 +800,19 @@ func parseCredentials(r *http.Request) (credentials, error) {
 			}, nil
 		}
 
+		// Check for an InfluxDB 2.x token of the form username:password.
+		if found && bearer == "Token" {
+			u, p, ok := strings.Cut(token, ":")
+			if !ok {
+				return credentials{}, fmt.Errorf("token must be of the form username:password")
+			}
+			return credentials{
+				Method:   UserAuthentication,
+				Username: u,
+				Password: p,
+			}, nil
+		}
+
 		// Check for basic auth.
 		if u, p, ok := r.BasicAuth(); ok {
 			// Check for special subscription username
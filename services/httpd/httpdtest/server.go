@@ -21,6 +21,7 @@ func NewServer(verbose bool) *Server {
 	ds.Open()
 	s := &Server{
 		Handler: httpd.NewHandler(
+			false,
 			false,
 			false,
 			verbose,
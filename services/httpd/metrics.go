@@ -0,0 +1,111 @@
+package httpd
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/kapacitor/server/vars"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace of the metrics of the internal statistics.
+const metricsNamespace = "kapacitor"
+
+// statLabels renames the tags of statistics whose name is ambiguous as a label.
+var statLabels = map[string]map[string]string{
+	"topics": {"id": "topic"},
+}
+
+// newMetricsHandler returns a handler that serves the internal statistics,
+// and the Go runtime and process metrics, in the Prometheus text format.
+func newMetricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		statsCollector{},
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		// Serve the metrics that could be collected even if some failed.
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// statsCollector collects the same statistics as are written to the _kapacitor database.
+// Each value of a statistic is a metric named <namespace>_<statistic>_<value>,
+// labeled with the tags of the statistic.
+type statsCollector struct{}
+
+// Describe sends no descriptions since the statistics change as tasks and services come and go,
+// which makes the collector unchecked.
+func (statsCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (statsCollector) Collect(ch chan<- prometheus.Metric) {
+	data, err := vars.GetStatsData()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(prometheus.NewDesc(metricsNamespace+"_error", "Failed to read statistics", nil, nil), err)
+		return
+	}
+	for _, d := range data {
+		// The runtime statistics are covered by the Go collector.
+		if d.Name == "runtime" {
+			continue
+		}
+		labelNames := make([]string, 0, len(d.Tags))
+		for tag := range d.Tags {
+			labelNames = append(labelNames, tag)
+		}
+		sort.Strings(labelNames)
+		labelValues := make([]string, len(labelNames))
+		for i, tag := range labelNames {
+			labelValues[i] = d.Tags[tag]
+			if l, ok := statLabels[d.Name][tag]; ok {
+				tag = l
+			}
+			labelNames[i] = metricName(tag)
+		}
+
+		subsystem := metricName(d.Name)
+		if d.Name == metricsNamespace {
+			// Global statistics
+			subsystem = ""
+		}
+		for key, v := range d.Values {
+			var value float64
+			switch v := v.(type) {
+			case int64:
+				value = float64(v)
+			case float64:
+				value = v
+			default:
+				continue
+			}
+			desc := prometheus.NewDesc(
+				prometheus.BuildFQName(metricsNamespace, subsystem, metricName(key)),
+				"Kapacitor "+d.Name+" statistic "+key,
+				labelNames,
+				nil,
+			)
+			m, err := prometheus.NewConstMetric(desc, prometheus.UntypedValue, value, labelValues...)
+			if err != nil {
+				ch <- prometheus.NewInvalidMetric(desc, err)
+				continue
+			}
+			ch <- m
+		}
+	}
+}
+
+// metricName replaces the characters of a name that are not valid in metric and label names.
+func metricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
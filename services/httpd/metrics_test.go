@@ -0,0 +1,68 @@
+package httpd
+
+import (
+	goexpvar "expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/kapacitor/expvar"
+	"github.com/influxdata/kapacitor/server/vars"
+)
+
+func TestMetrics(t *testing.T) {
+	key, statMap := vars.NewStatistic("topics", map[string]string{"id": "main:cpu"})
+	defer vars.DeleteStatistic(key)
+	collected := new(expvar.Int)
+	collected.Set(42)
+	statMap.Set("collected", collected)
+
+	nodeKey, nodeMap := vars.NewStatistic("nodes", map[string]string{
+		"task": "cpu",
+		"node": "alert2",
+	})
+	defer vars.DeleteStatistic(nodeKey)
+	avgExecTime := new(expvar.Float)
+	avgExecTime.Set(1.5)
+	nodeMap.Set("avg_exec_time_ns", avgExecTime)
+
+	handlerStats := new(goexpvar.Map).Init()
+	testCases := []struct {
+		name               string
+		metricsAuthEnabled bool
+		expCode            int
+	}{
+		{name: "auth", metricsAuthEnabled: true, expCode: http.StatusUnauthorized},
+		{name: "no auth", metricsAuthEnabled: false, expCode: http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(true, false, tc.metricsAuthEnabled, false, false, false, handlerStats, nil, "")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+			if w.Code != tc.expCode {
+				t.Fatalf("unexpected status code: got %d exp %d", w.Code, tc.expCode)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			b, err := io.ReadAll(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(b)
+			for _, exp := range []string{
+				`kapacitor_topics_collected{cluster_id="",host="",server_id="",topic="main:cpu"} 42`,
+				`kapacitor_nodes_avg_exec_time_ns{cluster_id="",host="",node="alert2",server_id="",task="cpu"} 1.5`,
+				`kapacitor_num_tasks `,
+				`go_goroutines `,
+			} {
+				if !strings.Contains(body, exp) {
+					t.Errorf("missing metric %q in:\n%s", exp, body)
+				}
+			}
+		})
+	}
+}
@@ -114,6 +114,7 @@ func NewService(c Config, hostname string, t *tls.Config, d Diagnostic) *Service
 		Handler: NewHandler(
 			c.AuthEnabled,
 			c.PprofEnabled,
+			c.MetricsAuthEnabled,
 			c.LogEnabled,
 			c.WriteTracing,
 			c.GZIP,
@@ -127,6 +128,7 @@ func NewService(c Config, hostname string, t *tls.Config, d Diagnostic) *Service
 			false,
 			false,
 			false,
+			false,
 			localStatMap,
 			d,
 			"",
@@ -137,6 +139,7 @@ func NewService(c Config, hostname string, t *tls.Config, d Diagnostic) *Service
 	if s.key == "" {
 		s.key = s.cert
 	}
+	s.Handler.BucketMappings = c.BucketMappings
 
 	return s
 }
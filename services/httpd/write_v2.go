@@ -0,0 +1,96 @@
+package httpd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/influxdata/kapacitor/auth"
+)
+
+// precisionsV2 maps the precisions of InfluxDB 2.x writes to the precisions of line protocol parsing.
+var precisionsV2 = map[string]string{
+	"ns": "n",
+	"us": "u",
+	"ms": "ms",
+	"s":  "s",
+}
+
+// serveWriteV2 receives line protocol writes in the format of the InfluxDB 2.x /api/v2/write endpoint.
+// The bucket is mapped to a database and retention policy, the org is ignored.
+func (h *Handler) serveWriteV2(w http.ResponseWriter, r *http.Request, user auth.User) {
+	h.statMap.Add(statWriteRequest, 1)
+
+	qp := r.URL.Query()
+	bucket := qp.Get("bucket")
+	if bucket == "" {
+		writeErrorV2(w, fmt.Errorf("bucket is required"), http.StatusBadRequest)
+		return
+	}
+	database, retentionPolicy, err := h.bucketDBRP(bucket)
+	if err != nil {
+		writeErrorV2(w, err, http.StatusBadRequest)
+		return
+	}
+
+	precision := "n"
+	if p := qp.Get("precision"); p != "" {
+		var ok bool
+		precision, ok = precisionsV2[p]
+		if !ok {
+			writeErrorV2(w, fmt.Errorf("invalid precision %q, must be one of ns, us, ms or s", p), http.StatusBadRequest)
+			return
+		}
+	}
+
+	b, err := h.readWriteBody(r)
+	if err != nil {
+		writeErrorV2(w, err, http.StatusBadRequest)
+		return
+	}
+
+	code, err := h.writeLine(b, precision, database, retentionPolicy, user)
+	if err != nil {
+		writeErrorV2(w, err, code)
+		return
+	}
+	// InfluxDB 2.x always responds with no content to a successful write.
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// bucketDBRP returns the database and retention policy of a bucket.
+// Buckets without a mapping are of the form "db/rp" or "db",
+// where the default retention policy of the database is used.
+func (h *Handler) bucketDBRP(bucket string) (string, string, error) {
+	for _, m := range h.BucketMappings {
+		if m.Bucket == bucket {
+			return m.Database, m.RetentionPolicy, nil
+		}
+	}
+	db, rp, _ := strings.Cut(bucket, "/")
+	if db == "" || strings.Contains(rp, "/") {
+		return "", "", fmt.Errorf("invalid bucket %q, must be of the form db/rp or db", bucket)
+	}
+	return db, rp, nil
+}
+
+// writeErrorV2 writes an error in the format of the InfluxDB 2.x API.
+func writeErrorV2(w http.ResponseWriter, err error, statusCode int) {
+	code := "internal error"
+	switch statusCode {
+	case http.StatusBadRequest:
+		code = "invalid"
+	case http.StatusUnauthorized:
+		code = "unauthorized"
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{
+		Code:    code,
+		Message: err.Error(),
+	})
+}
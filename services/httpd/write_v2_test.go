@@ -0,0 +1,156 @@
+package httpd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+type pointsWriter struct {
+	database        string
+	retentionPolicy string
+	points          []models.Point
+}
+
+func (w *pointsWriter) WritePoints(database, retentionPolicy string, _ models.ConsistencyLevel, points []models.Point) error {
+	w.database = database
+	w.retentionPolicy = retentionPolicy
+	w.points = points
+	return nil
+}
+
+func TestServeWriteV2(t *testing.T) {
+	gzipped := func(s string) string {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		gw.Write([]byte(s))
+		gw.Close()
+		return buf.String()
+	}
+	testCases := []struct {
+		name     string
+		query    string
+		body     string
+		gzip     bool
+		expCode  int
+		expDB    string
+		expRP    string
+		expTime  time.Time
+		expError string
+	}{
+		{
+			name:    "db/rp bucket",
+			query:   "org=myorg&bucket=telegraf/autogen",
+			body:    "cpu value=1 1000000000",
+			expCode: http.StatusNoContent,
+			expDB:   "telegraf",
+			expRP:   "autogen",
+			expTime: time.Unix(1, 0),
+		},
+		{
+			name:    "db bucket",
+			query:   "bucket=telegraf&precision=s",
+			body:    "cpu value=1 1",
+			expCode: http.StatusNoContent,
+			expDB:   "telegraf",
+			expTime: time.Unix(1, 0),
+		},
+		{
+			name:    "mapped bucket",
+			query:   "bucket=mapped&precision=ms",
+			body:    "cpu value=1 1000",
+			expCode: http.StatusNoContent,
+			expDB:   "db",
+			expRP:   "rp",
+			expTime: time.Unix(1, 0),
+		},
+		{
+			name:    "gzip",
+			query:   "bucket=telegraf&precision=us",
+			body:    gzipped("cpu value=1 1000000"),
+			gzip:    true,
+			expCode: http.StatusNoContent,
+			expDB:   "telegraf",
+			expTime: time.Unix(1, 0),
+		},
+		{
+			name:     "missing bucket",
+			query:    "org=myorg",
+			body:     "cpu value=1",
+			expCode:  http.StatusBadRequest,
+			expError: `{"code":"invalid","message":"bucket is required"}`,
+		},
+		{
+			name:     "invalid bucket",
+			query:    "bucket=a/b/c",
+			body:     "cpu value=1",
+			expCode:  http.StatusBadRequest,
+			expError: `{"code":"invalid","message":"invalid bucket \"a/b/c\", must be of the form db/rp or db"}`,
+		},
+		{
+			name:     "invalid precision",
+			query:    "bucket=telegraf&precision=n",
+			body:     "cpu value=1",
+			expCode:  http.StatusBadRequest,
+			expError: `{"code":"invalid","message":"invalid precision \"n\", must be one of ns, us, ms or s"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(false, false, false, false, false, false, new(expvar.Map).Init(), nil, "")
+			h.BucketMappings = []BucketMapping{{Bucket: "mapped", Database: "db", RetentionPolicy: "rp"}}
+			pw := new(pointsWriter)
+			h.PointsWriter = pw
+
+			r := httptest.NewRequest("POST", "/api/v2/write?"+tc.query, strings.NewReader(tc.body))
+			r.Header.Set("Authorization", "Token user:password")
+			if tc.gzip {
+				r.Header.Set("Content-Encoding", "gzip")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.expCode {
+				t.Fatalf("unexpected status code: got %d exp %d: %s", w.Code, tc.expCode, w.Body.String())
+			}
+			if tc.expError != "" {
+				if got := strings.TrimSpace(w.Body.String()); got != tc.expError {
+					t.Errorf("unexpected error:\ngot %s\nexp %s", got, tc.expError)
+				}
+				return
+			}
+			if pw.database != tc.expDB || pw.retentionPolicy != tc.expRP {
+				t.Errorf("unexpected database and retention policy: got %s/%s exp %s/%s", pw.database, pw.retentionPolicy, tc.expDB, tc.expRP)
+			}
+			if len(pw.points) != 1 {
+				t.Fatalf("unexpected number of points: %d", len(pw.points))
+			}
+			if got := pw.points[0].Time(); !got.Equal(tc.expTime) {
+				t.Errorf("unexpected time: got %v exp %v", got, tc.expTime)
+			}
+		})
+	}
+}
+
+func TestParseCredentials_Token(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/v2/write", nil)
+	r.Header.Set("Authorization", "Token bob:secret")
+	creds, err := parseCredentials(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Method != UserAuthentication || creds.Username != "bob" || creds.Password != "secret" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+
+	r.Header.Set("Authorization", "Token secret")
+	if _, err := parseCredentials(r); err == nil {
+		t.Error("expected error for token without username")
+	}
+}
@@ -318,6 +318,11 @@ func (h *handler) NewHTTPRequest(body io.Reader, tmplCTX interface{}) (req *http
 }
 
 func (h *handler) Handle(event alert.Event) {
+	h.TryHandle(event)
+}
+
+// TryHandle posts the alert data and returns an error if it could not be posted.
+func (h *handler) TryHandle(event alert.Event) error {
 	var err error
 
 	// Construct the body of the HTTP request
@@ -329,13 +334,13 @@ func (h *handler) Handle(event alert.Event) {
 		err := h.endpoint.AlertTemplate().Execute(body, ad)
 		if err != nil {
 			h.diag.Error("failed to execute alert template", err)
-			return
+			return err
 		}
 	} else {
 		err = json.NewEncoder(body).Encode(ad)
 		if err != nil {
 			h.diag.Error("failed to marshal alert data json", err)
-			return
+			return err
 		}
 		contentType = "application/json"
 	}
@@ -343,7 +348,7 @@ func (h *handler) Handle(event alert.Event) {
 	req, err := h.NewHTTPRequest(body, ad)
 	if err != nil {
 		h.diag.Error("failed to create HTTP request", err)
-		return
+		return err
 	}
 
 	if contentType != "" {
@@ -371,7 +376,7 @@ func (h *handler) Handle(event alert.Event) {
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		h.diag.Error("failed to POST alert data", err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -388,6 +393,7 @@ func (h *handler) Handle(event alert.Event) {
 			err = errors.New("unknown error, use .captureResponse() to capture the HTTP response")
 		}
 		h.diag.Error("POST returned non 2xx status code", err, keyvalue.KV("code", strconv.Itoa(resp.StatusCode)))
+		return err
 	}
-
+	return nil
 }
@@ -0,0 +1,189 @@
+package kafka_consumer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/influxdata/influxdb/toml"
+	"github.com/influxdata/kapacitor/services/kafka"
+	"github.com/influxdata/kapacitor/services/pointdecode"
+	"github.com/influxdata/kapacitor/tlsconfig"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTimeout        = 10 * time.Second
+	DefaultCommitInterval = 1 * time.Second
+	DefaultOffset         = OffsetNewest
+	DefaultDataFormat     = DataFormatLineProtocol
+	DefaultPrecision      = "ns"
+)
+
+// Offsets to start consuming from when a consumer group has no committed offset.
+const (
+	OffsetOldest = "oldest"
+	OffsetNewest = "newest"
+)
+
+// Formats of the consumed messages.
+const (
+	DataFormatLineProtocol = pointdecode.FormatLineProtocol
+	DataFormatJSON         = pointdecode.FormatJSON
+)
+
+type Config struct {
+	Enabled bool `toml:"enabled"`
+	// ID is a unique identifier for this consumer.
+	ID string `toml:"id"`
+	// Brokers is a list of host:port addresses of Kafka brokers.
+	Brokers []string `toml:"brokers"`
+	// Topics to consume.
+	Topics []string `toml:"topics"`
+	// ConsumerGroup is the name of the consumer group that offsets are committed for.
+	ConsumerGroup string `toml:"consumer-group"`
+	// Offset to start consuming from when the consumer group has no committed offset,
+	// either "oldest" or "newest".
+	Offset string `toml:"offset"`
+	// CommitInterval is how often the offsets of processed messages are committed.
+	CommitInterval toml.Duration `toml:"commit-interval"`
+	// Timeout on network operations with the brokers.
+	Timeout toml.Duration `toml:"timeout"`
+
+	// Database and RetentionPolicy the consumed points are written to.
+	Database        string `toml:"database"`
+	RetentionPolicy string `toml:"retention-policy"`
+
+	// DataFormat of the messages, either "line-protocol" or "json".
+	DataFormat string `toml:"data-format"`
+	// Precision of timestamps in line protocol messages.
+	Precision string `toml:"precision"`
+	// JSON maps JSON messages to points.
+	JSON JSONConfig `toml:"json"`
+
+	// UseSSL enable ssl communication
+	// Must be true for the other ssl options to take effect.
+	UseSSL bool `toml:"use-ssl"`
+	// Path to CA file
+	SSLCA string `toml:"ssl-ca"`
+	// Path to host cert file
+	SSLCert string `toml:"ssl-cert"`
+	// Path to cert key file
+	SSLKey string `toml:"ssl-key"`
+	// Use SSL but skip chain & host verification
+	InsecureSkipVerify bool `toml:"insecure-skip-verify"`
+	// Authentication using SASL
+	kafka.SASLAuth
+}
+
+// JSONConfig maps the keys of JSON objects to the measurement, tags, fields and time of points.
+type JSONConfig = pointdecode.JSONConfig
+
+func NewConfig() Config {
+	return Config{
+		Offset:         DefaultOffset,
+		CommitInterval: toml.Duration(DefaultCommitInterval),
+		Timeout:        toml.Duration(DefaultTimeout),
+		DataFormat:     DefaultDataFormat,
+		Precision:      DefaultPrecision,
+		SASLAuth:       kafka.SASLAuth{SASLOAUTHExpiryMargin: kafka.DefaultSASLOAUTHExpiryMargin},
+	}
+}
+
+// ApplyConditionalDefaults sets the defaults of any options that are not set.
+func (c *Config) ApplyConditionalDefaults() {
+	if c.Offset == "" {
+		c.Offset = DefaultOffset
+	}
+	if c.CommitInterval == 0 {
+		c.CommitInterval = toml.Duration(DefaultCommitInterval)
+	}
+	if c.Timeout == 0 {
+		c.Timeout = toml.Duration(DefaultTimeout)
+	}
+	if c.DataFormat == "" {
+		c.DataFormat = DefaultDataFormat
+	}
+	if c.Precision == "" {
+		c.Precision = DefaultPrecision
+	}
+	if c.SASLOAUTHExpiryMargin == 0 {
+		c.SASLOAUTHExpiryMargin = kafka.DefaultSASLOAUTHExpiryMargin
+	}
+}
+
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ID == "" {
+		return errors.New("id must not be empty")
+	}
+	if len(c.Brokers) == 0 {
+		return errors.New("no brokers specified, must provide at least one broker URL")
+	}
+	if len(c.Topics) == 0 {
+		return errors.New("no topics specified, must provide at least one topic")
+	}
+	if c.ConsumerGroup == "" {
+		return errors.New("consumer-group must not be empty")
+	}
+	if c.Database == "" {
+		return errors.New("database must not be empty")
+	}
+	switch c.Offset {
+	case OffsetOldest, OffsetNewest:
+	default:
+		return fmt.Errorf("invalid offset %q, must be one of %q or %q", c.Offset, OffsetOldest, OffsetNewest)
+	}
+	switch c.DataFormat {
+	case DataFormatLineProtocol:
+	case DataFormatJSON:
+		if c.JSON.Measurement == "" && c.JSON.MeasurementKey == "" {
+			return errors.New("json must specify a measurement or measurement-key")
+		}
+	default:
+		return fmt.Errorf("invalid data-format %q, must be one of %q or %q", c.DataFormat, DataFormatLineProtocol, DataFormatJSON)
+	}
+	if c.CommitInterval <= 0 {
+		return errors.New("commit-interval must be positive")
+	}
+	return c.SASLAuth.Validate()
+}
+
+// consumerConfig returns the configuration of the consumer group.
+// The returned Closer releases any resources of the SASL authentication and may be nil.
+func (c Config) consumerConfig() (*sarama.Config, kafka.Closer, error) {
+	cfg := sarama.NewConfig()
+	cfg.ClientID = c.ID
+	cfg.Consumer.Return.Errors = true
+	// Offsets are only marked once a message has been processed,
+	// so only the offsets of processed messages are committed.
+	cfg.Consumer.Offsets.AutoCommit.Enable = true
+	cfg.Consumer.Offsets.AutoCommit.Interval = time.Duration(c.CommitInterval)
+	if c.Offset == OffsetOldest {
+		cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	} else {
+		cfg.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+
+	if c.UseSSL {
+		var err error
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config, err = tlsconfig.Create(c.SSLCA, c.SSLCert, c.SSLKey, c.InsecureSkipVerify)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.Timeout > 0 {
+		cfg.Net.DialTimeout = time.Duration(c.Timeout)
+		cfg.Net.WriteTimeout = time.Duration(c.Timeout)
+		cfg.Net.ReadTimeout = time.Duration(c.Timeout)
+	}
+
+	closer, err := c.SASLAuth.SetSASLConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, closer, cfg.Validate()
+}
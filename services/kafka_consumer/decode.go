@@ -0,0 +1,18 @@
+package kafka_consumer
+
+import (
+	"fmt"
+
+	"github.com/influxdata/kapacitor/services/pointdecode"
+)
+
+func newDecoder(c Config) (pointdecode.Decoder, error) {
+	switch c.DataFormat {
+	case DataFormatLineProtocol:
+		return pointdecode.LineProtocolDecoder{Precision: c.Precision}, nil
+	case DataFormatJSON:
+		return pointdecode.NewJSONDecoder(c.JSON), nil
+	default:
+		return nil, fmt.Errorf("unknown data format %q", c.DataFormat)
+	}
+}
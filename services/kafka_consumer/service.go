@@ -0,0 +1,221 @@
+package kafka_consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/kapacitor/expvar"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/server/vars"
+	"github.com/influxdata/kapacitor/services/kafka"
+	"github.com/influxdata/kapacitor/services/pointdecode"
+)
+
+// statistics gathered per consumed topic.
+const (
+	statMessagesReceived  = "messages_rx"
+	statBytesReceived     = "bytes_rx"
+	statPointsParseFail   = "points_parse_fail"
+	statPointsTransmitted = "points_tx"
+	statTransmitFail      = "tx_fail"
+)
+
+// How long to wait before rejoining the consumer group after an error.
+const rejoinDelay = time.Second
+
+type Diagnostic interface {
+	Error(msg string, err error, ctx ...keyvalue.T)
+	StartedConsuming(id, group string, topics []string)
+	ClosedService(id string)
+}
+
+// Service consumes messages from Kafka topics as a member of a consumer group
+// and writes the points decoded from the messages.
+type Service struct {
+	config  Config
+	decoder pointdecode.Decoder
+
+	group  sarama.ConsumerGroup
+	closer kafka.Closer
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	PointsWriter interface {
+		WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+	}
+
+	Diag Diagnostic
+
+	// Statistics of each topic, keyed by topic.
+	stats    map[string]*expvar.Map
+	statKeys []string
+}
+
+func NewService(c Config, d Diagnostic) *Service {
+	c.ApplyConditionalDefaults()
+	return &Service{
+		config: c,
+		Diag:   d,
+	}
+}
+
+func (s *Service) Open() error {
+	decoder, err := newDecoder(s.config)
+	if err != nil {
+		return err
+	}
+	s.decoder = decoder
+
+	cfg, closer, err := s.config.consumerConfig()
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}
+	s.closer = closer
+	group, err := sarama.NewConsumerGroup(s.config.Brokers, s.config.ConsumerGroup, cfg)
+	if err != nil {
+		s.closeSASL()
+		return err
+	}
+	s.group = group
+
+	s.stats = make(map[string]*expvar.Map, len(s.config.Topics))
+	for _, topic := range s.config.Topics {
+		key, statMap := vars.NewStatistic("kafka_consumer", map[string]string{
+			"consumer": s.config.ID,
+			"topic":    topic,
+		})
+		s.statKeys = append(s.statKeys, key)
+		s.stats[topic] = statMap
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		s.consume(ctx)
+	}()
+	go func() {
+		defer s.wg.Done()
+		for err := range s.group.Errors() {
+			s.Diag.Error("kafka consumer error", err)
+		}
+	}()
+
+	s.Diag.StartedConsuming(s.config.ID, s.config.ConsumerGroup, s.config.Topics)
+	return nil
+}
+
+func (s *Service) Close() error {
+	if s.group == nil {
+		return errors.New("service already closed")
+	}
+	s.cancel()
+	err := s.group.Close()
+	s.wg.Wait()
+	s.closeSASL()
+	for _, key := range s.statKeys {
+		vars.DeleteStatistic(key)
+	}
+	s.group = nil
+	s.statKeys = nil
+	s.Diag.ClosedService(s.config.ID)
+	return err
+}
+
+func (s *Service) closeSASL() {
+	if s.closer != nil {
+		s.closer.Close()
+		s.closer = nil
+	}
+}
+
+// consume joins the consumer group until the context is canceled.
+// Each session of the group lasts until the group is rebalanced.
+func (s *Service) consume(ctx context.Context) {
+	h := &groupHandler{s: s}
+	for {
+		if err := s.group.Consume(ctx, s.config.Topics, h); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
+			s.Diag.Error("failed to consume from kafka", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(rejoinDelay):
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// process decodes and writes the points of a message.
+func (s *Service) process(msg *sarama.ConsumerMessage) {
+	statMap := s.stats[msg.Topic]
+	if statMap == nil {
+		// Only configured topics are consumed, but don't panic if that changes.
+		statMap = new(expvar.Map).Init()
+	}
+	statMap.Add(statMessagesReceived, 1)
+	statMap.Add(statBytesReceived, int64(len(msg.Value)))
+
+	now := msg.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+	points, err := s.decoder.Decode(msg.Value, now.UTC())
+	if err != nil {
+		statMap.Add(statPointsParseFail, 1)
+		s.Diag.Error("failed to parse points", err, keyvalue.KV("topic", msg.Topic))
+		return
+	}
+	if len(points) == 0 {
+		return
+	}
+	if err := s.PointsWriter.WritePoints(
+		s.config.Database,
+		s.config.RetentionPolicy,
+		models.ConsistencyLevelAll,
+		points,
+	); err != nil {
+		statMap.Add(statTransmitFail, 1)
+		s.Diag.Error("failed to write points", err, keyvalue.KV("topic", msg.Topic), keyvalue.KV("database", s.config.Database))
+		return
+	}
+	statMap.Add(statPointsTransmitted, int64(len(points)))
+}
+
+// groupHandler handles the claims of a consumer group session.
+type groupHandler struct {
+	s *Service
+}
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim processes the messages of a claimed partition.
+// The offset of a message is marked once the message is processed,
+// marked offsets are committed periodically and when the session ends.
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			h.s.process(msg)
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
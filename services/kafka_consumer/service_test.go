@@ -0,0 +1,114 @@
+package kafka_consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/kapacitor/expvar"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pointsWriter struct {
+	mu     sync.Mutex
+	points []string
+	err    error
+}
+
+func (w *pointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	for _, p := range points {
+		w.points = append(w.points, database+"."+retentionPolicy+" "+p.String())
+	}
+	return nil
+}
+
+type diag struct{}
+
+func (diag) Error(msg string, err error, ctx ...keyvalue.T)     {}
+func (diag) StartedConsuming(id, group string, topics []string) {}
+func (diag) ClosedService(id string)                            {}
+
+// session is a consumer group session that records the marked offsets.
+type session struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *session) Claims() map[string][]int32                                               { return nil }
+func (s *session) MemberID() string                                                         { return "member" }
+func (s *session) GenerationID() int32                                                      { return 1 }
+func (s *session) MarkOffset(topic string, partition int32, offset int64, metadata string)  {}
+func (s *session) Commit()                                                                  {}
+func (s *session) ResetOffset(topic string, partition int32, offset int64, metadata string) {}
+func (s *session) Context() context.Context                                                 { return s.ctx }
+func (s *session) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type claim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c claim) Topic() string                            { return "telegraf" }
+func (c claim) Partition() int32                         { return 0 }
+func (c claim) InitialOffset() int64                     { return 0 }
+func (c claim) HighWaterMarkOffset() int64               { return 0 }
+func (c claim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestGroupHandler_ConsumeClaim(t *testing.T) {
+	c := NewConfig()
+	c.ID = "test"
+	c.Database = "db"
+	c.RetentionPolicy = "rp"
+	c.Topics = []string{"telegraf"}
+	s := NewService(c, diag{})
+	w := new(pointsWriter)
+	s.PointsWriter = w
+	decoder, err := newDecoder(s.config)
+	require.NoError(t, err)
+	s.decoder = decoder
+	statMap := new(expvar.Map).Init()
+	s.stats = map[string]*expvar.Map{"telegraf": statMap}
+
+	msgs := make(chan *sarama.ConsumerMessage, 3)
+	msgs <- &sarama.ConsumerMessage{Topic: "telegraf", Offset: 1, Value: []byte("cpu value=1 1")}
+	msgs <- &sarama.ConsumerMessage{Topic: "telegraf", Offset: 2, Value: []byte("not line protocol")}
+	msgs <- &sarama.ConsumerMessage{Topic: "telegraf", Offset: 3, Value: []byte("cpu value=3 3")}
+	close(msgs)
+
+	sess := &session{ctx: context.Background()}
+	h := &groupHandler{s: s}
+	require.NoError(t, h.ConsumeClaim(sess, claim{messages: msgs}))
+
+	assert.Equal(t, []string{"db.rp cpu value=1 1", "db.rp cpu value=3 3"}, w.points)
+	// Messages that fail to parse are still marked as processed.
+	assert.Equal(t, []int64{1, 2, 3}, sess.marked)
+	assert.Equal(t, "3", statMap.Get(statMessagesReceived).String())
+	assert.Equal(t, "1", statMap.Get(statPointsParseFail).String())
+	assert.Equal(t, "2", statMap.Get(statPointsTransmitted).String())
+
+	// A failed write is counted but does not stop consuming.
+	w.err = errors.New("write failed")
+	msgs = make(chan *sarama.ConsumerMessage, 1)
+	msgs <- &sarama.ConsumerMessage{Topic: "telegraf", Offset: 4, Value: []byte("cpu value=4 4")}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	sess.ctx = ctx
+	require.NoError(t, h.ConsumeClaim(sess, claim{messages: msgs}))
+	assert.Equal(t, []int64{1, 2, 3, 4}, sess.marked)
+	assert.Equal(t, "1", statMap.Get(statTransmitFail).String())
+}
@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"sync"
 	"time"
 
 	pahomqtt "github.com/eclipse/paho.mqtt.golang"
@@ -13,6 +14,9 @@ type Client interface {
 	Connect() error
 	Disconnect()
 	Publish(topic string, qos QoSLevel, retained bool, message []byte) error
+	// Subscribe calls callback with the topic and payload of each message
+	// published to the topic filter.
+	Subscribe(filter string, qos QoSLevel, callback func(topic string, payload []byte)) error
 }
 
 // newClient produces a disconnected MQTT client
@@ -41,6 +45,14 @@ var newClient = func(c Config) (Client, error) {
 type PahoClient struct {
 	opts   *pahomqtt.ClientOptions
 	client pahomqtt.Client
+
+	mu            sync.Mutex
+	subscriptions map[string]pahoSubscription
+}
+
+type pahoSubscription struct {
+	qos      QoSLevel
+	callback pahomqtt.MessageHandler
 }
 
 // DefaultQuiesceTimeout is the duration the client will wait for outstanding
@@ -54,6 +66,9 @@ func (p *PahoClient) Connect() error {
 	// storage requirements and can reduce load on the broker by using a clean
 	// session.
 	p.opts.SetCleanSession(true)
+	// A clean session also drops the subscriptions of the client,
+	// so subscribe again whenever the client reconnects.
+	p.opts.SetOnConnectHandler(p.resubscribe)
 
 	p.client = pahomqtt.NewClient(p.opts)
 	token := p.client.Connect()
@@ -72,3 +87,28 @@ func (p *PahoClient) Publish(topic string, qos QoSLevel, retained bool, message
 	token.Wait()
 	return token.Error()
 }
+
+func (p *PahoClient) Subscribe(filter string, qos QoSLevel, callback func(topic string, payload []byte)) error {
+	handler := func(_ pahomqtt.Client, m pahomqtt.Message) {
+		callback(m.Topic(), m.Payload())
+	}
+	p.mu.Lock()
+	if p.subscriptions == nil {
+		p.subscriptions = make(map[string]pahoSubscription)
+	}
+	p.subscriptions[filter] = pahoSubscription{qos: qos, callback: handler}
+	p.mu.Unlock()
+
+	token := p.client.Subscribe(filter, byte(qos), handler)
+	token.Wait()
+	return token.Error()
+}
+
+func (p *PahoClient) resubscribe(c pahomqtt.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for filter, s := range p.subscriptions {
+		// Errors are not returned here, a failed subscription is retried on the next reconnect.
+		c.Subscribe(filter, byte(s.qos), s.callback)
+	}
+}
@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"errors"
+	"reflect"
 )
 
 type Config struct {
@@ -29,6 +30,10 @@ type Config struct {
 	Username string `toml:"username" override:"username"`
 	Password string `toml:"password" override:"password,redact"`
 
+	// Subscriptions are the topic filters whose messages are written as points.
+	// Subscriptions can only be configured in the configuration file.
+	Subscriptions []SubscriptionConfig `toml:"subscriptions" override:"-"`
+
 	// newClientF is a function that returns a client for a given config.
 	// It is used exclusively for testing.
 	newClientF func(c Config) (Client, error) `override:"-"`
@@ -56,6 +61,11 @@ func (c Config) Validate() error {
 			return errors.New("must specify a url for mqtt service")
 		}
 	}
+	for _, sub := range c.Subscriptions {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -104,6 +114,9 @@ func (c Config) Equal(o Config) bool {
 	if c.Password != o.Password {
 		return false
 	}
+	if !reflect.DeepEqual(c.Subscriptions, o.Subscriptions) {
+		return false
+	}
 	return true
 }
 
@@ -2,6 +2,7 @@ package mqtttest
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/influxdata/kapacitor/services/mqtt"
 )
@@ -23,7 +24,8 @@ func (s *ClientCreator) NewClient(c mqtt.Config) (mqtt.Client, error) {
 type MockClient struct {
 	connected bool
 
-	PublishData []PublishData
+	PublishData   []PublishData
+	Subscriptions []Subscription
 }
 
 func NewClient(mqtt.Config) (mqtt.Client, error) {
@@ -37,6 +39,7 @@ func (m *MockClient) Connect() error {
 
 func (m *MockClient) Disconnect() {
 	m.connected = false
+	m.Subscriptions = nil
 }
 
 func (m *MockClient) Publish(topic string, qos mqtt.QoSLevel, retained bool, message []byte) error {
@@ -58,3 +61,50 @@ type PublishData struct {
 	Retained bool
 	Message  []byte
 }
+
+func (m *MockClient) Subscribe(filter string, qos mqtt.QoSLevel, callback func(topic string, payload []byte)) error {
+	if !m.connected {
+		return errors.New("Subscribe() called before Connect()")
+	}
+	m.Subscriptions = append(m.Subscriptions, Subscription{
+		Filter:   filter,
+		QoS:      qos,
+		Callback: callback,
+	})
+	return nil
+}
+
+// Deliver calls the callbacks of the subscriptions matching the topic,
+// as if the message was published to the broker.
+func (m *MockClient) Deliver(topic string, payload []byte) {
+	for _, s := range m.Subscriptions {
+		if Match(s.Filter, topic) {
+			s.Callback(topic, payload)
+		}
+	}
+}
+
+type Subscription struct {
+	Filter   string
+	QoS      mqtt.QoSLevel
+	Callback func(topic string, payload []byte)
+}
+
+// Match reports whether the topic matches the topic filter,
+// where + matches a single level and # matches all remaining levels.
+func Match(filter, topic string) bool {
+	fs := strings.Split(filter, "/")
+	ts := strings.Split(topic, "/")
+	for i, f := range fs {
+		if f == "#" {
+			return true
+		}
+		if i >= len(ts) {
+			return false
+		}
+		if f != "+" && f != ts[i] {
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
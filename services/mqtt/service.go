@@ -6,8 +6,10 @@ import (
 	"log"
 	"sync"
 	text "text/template"
+	"time"
 
 	"github.com/influxdata/kapacitor/alert"
+	"github.com/influxdata/kapacitor/edge"
 	"github.com/influxdata/kapacitor/keyvalue"
 	"github.com/pkg/errors"
 )
@@ -33,7 +35,7 @@ func (q *QoSLevel) UnmarshalText(text []byte) error {
 		*q = AtMostOnce
 	case "at-least-once":
 		*q = AtLeastOnce
-	case "exactly-one":
+	case "exactly-once", "exactly-one":
 		*q = ExactlyOnce
 	default:
 		return ErrInvalidQoS
@@ -72,6 +74,10 @@ type Service struct {
 	configs map[string]Config
 
 	defaultBrokerName string
+
+	PointsWriter interface {
+		WriteKapacitorPoint(edge.PointMessage) error
+	}
 }
 
 func NewService(cs Configs, d Diagnostic) (*Service, error) {
@@ -118,6 +124,9 @@ func (s *Service) Open() error {
 		if err := client.Connect(); err != nil {
 			return errors.Wrapf(err, "failed to connect to MQTT broker %q", name)
 		}
+		if err := s.subscribe(name, client, s.configs[name].Subscriptions); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -196,6 +205,9 @@ func (s *Service) update(cs Configs) error {
 				return err
 			}
 			s.clients[name] = client
+			if err := s.subscribe(name, client, c.Subscriptions); err != nil {
+				return err
+			}
 		}
 	}
 	if len(cs) == 1 {
@@ -216,6 +228,39 @@ func (s *Service) update(cs Configs) error {
 	return nil
 }
 
+// subscribe subscribes the client of a broker to the topic filters of the subscriptions.
+func (s *Service) subscribe(brokerName string, client Client, subscriptions []SubscriptionConfig) error {
+	for _, c := range subscriptions {
+		sub := newSubscription(c)
+		d := s.diag.WithContext(
+			keyvalue.KV("broker_name", brokerName),
+			keyvalue.KV("subscription", c.Topic),
+		)
+		err := client.Subscribe(c.Topic, c.QoS, func(topic string, payload []byte) {
+			s.handleMessage(sub, d, topic, payload)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to subscribe to %q on MQTT broker %q", c.Topic, brokerName)
+		}
+	}
+	return nil
+}
+
+// handleMessage writes the points of a message received for a subscription.
+func (s *Service) handleMessage(sub *subscription, d Diagnostic, topic string, payload []byte) {
+	points, err := sub.Points(topic, payload, time.Now().UTC())
+	if err != nil {
+		d.Error("failed to parse MQTT message", err)
+		return
+	}
+	for _, p := range points {
+		if err := s.PointsWriter.WriteKapacitorPoint(p); err != nil {
+			d.Error("failed to write point from MQTT message", err)
+			return
+		}
+	}
+}
+
 func (s *Service) Handler(c HandlerConfig, ctx ...keyvalue.T) (alert.Handler, error) {
 	d := s.diag.WithContext(ctx...)
 	d.CreatingAlertHandler(c)
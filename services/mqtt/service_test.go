@@ -0,0 +1,243 @@
+package mqtt_test
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/mqtt"
+	"github.com/influxdata/kapacitor/services/mqtt/mqtttest"
+)
+
+var diagService *diagnostic.Service
+
+func init() {
+	diagService = diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	diagService.Open()
+}
+
+type pointsWriter struct {
+	mu     sync.Mutex
+	points []edge.PointMessage
+}
+
+func (w *pointsWriter) WriteKapacitorPoint(p edge.PointMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.points = append(w.points, p)
+	return nil
+}
+
+type point struct {
+	Name            string
+	Database        string
+	RetentionPolicy string
+	Tags            models.Tags
+	Fields          models.Fields
+	Time            time.Time
+}
+
+func (w *pointsWriter) Points() []point {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	points := make([]point, len(w.points))
+	for i, p := range w.points {
+		points[i] = point{
+			Name:            p.Name(),
+			Database:        p.Database(),
+			RetentionPolicy: p.RetentionPolicy(),
+			Tags:            p.Tags(),
+			Fields:          p.Fields(),
+			Time:            p.Time(),
+		}
+	}
+	return points
+}
+
+func TestService_Subscriptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		sub     mqtt.SubscriptionConfig
+		topic   string
+		payload string
+		exp     []point
+	}{
+		{
+			name: "line protocol",
+			sub: mqtt.SubscriptionConfig{
+				Topic:           "sensors/#",
+				Database:        "db",
+				RetentionPolicy: "rp",
+			},
+			topic:   "sensors/d1/temp",
+			payload: "temp,location=kitchen value=21.5 1000000000\ntemp,location=garage value=12 2000000000",
+			exp: []point{
+				{
+					Name:            "temp",
+					Database:        "db",
+					RetentionPolicy: "rp",
+					Tags:            models.Tags{"location": "kitchen"},
+					Fields:          models.Fields{"value": 21.5},
+					Time:            time.Unix(1, 0).UTC(),
+				},
+				{
+					Name:            "temp",
+					Database:        "db",
+					RetentionPolicy: "rp",
+					Tags:            models.Tags{"location": "garage"},
+					Fields:          models.Fields{"value": 12.0},
+					Time:            time.Unix(2, 0).UTC(),
+				},
+			},
+		},
+		{
+			name: "line protocol with measurement and topic tags",
+			sub: mqtt.SubscriptionConfig{
+				Topic:       "sensors/+/temp",
+				Database:    "db",
+				Measurement: "temperature",
+				Precision:   "s",
+				TopicTags:   map[string]int{"device": 1, "missing": 5},
+			},
+			topic:   "sensors/d1/temp",
+			payload: "temp value=21i 10",
+			exp: []point{{
+				Name:     "temperature",
+				Database: "db",
+				Tags:     models.Tags{"device": "d1"},
+				Fields:   models.Fields{"value": int64(21)},
+				Time:     time.Unix(10, 0).UTC(),
+			}},
+		},
+		{
+			name: "json",
+			sub: mqtt.SubscriptionConfig{
+				Topic:       "sensors/+/temp",
+				Database:    "db",
+				Measurement: "temperature",
+				DataFormat:  mqtt.DataFormatJSON,
+				TopicTags:   map[string]int{"device": 1},
+				TagKeys:     []string{"location"},
+				TimeKey:     "time",
+				TimeFormat:  "unix_ms",
+			},
+			topic:   "sensors/d1/temp",
+			payload: `[{"location":"kitchen","value":21.5,"ok":true,"time":1500},{"value":3,"nested":{"ignored":1},"time":2000}]`,
+			exp: []point{
+				{
+					Name:     "temperature",
+					Database: "db",
+					Tags:     models.Tags{"device": "d1", "location": "kitchen"},
+					Fields:   models.Fields{"value": 21.5, "ok": true},
+					Time:     time.Unix(1, 500*int64(time.Millisecond)).UTC(),
+				},
+				{
+					Name:     "temperature",
+					Database: "db",
+					Tags:     models.Tags{"device": "d1"},
+					Fields:   models.Fields{"value": 3.0},
+					Time:     time.Unix(2, 0).UTC(),
+				},
+			},
+		},
+		{
+			name: "not matching",
+			sub: mqtt.SubscriptionConfig{
+				Topic:    "sensors/+/humidity",
+				Database: "db",
+			},
+			topic:   "sensors/d1/temp",
+			payload: "temp value=1 1",
+			exp:     []point{},
+		},
+		{
+			name: "invalid payload",
+			sub: mqtt.SubscriptionConfig{
+				Topic:       "sensors/#",
+				Database:    "db",
+				Measurement: "m",
+				DataFormat:  mqtt.DataFormatJSON,
+			},
+			topic:   "sensors/d1/temp",
+			payload: `{"value":`,
+			exp:     []point{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := new(mqtttest.ClientCreator)
+			c := mqtt.NewConfig()
+			c.Enabled = true
+			c.URL = "tcp://mqtt.example.com:1883"
+			c.Subscriptions = []mqtt.SubscriptionConfig{tc.sub}
+			c.SetNewClientF(cc.NewClient)
+			if err := c.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := mqtt.NewService(mqtt.Configs{c}, diagService.NewMQTTHandler())
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := new(pointsWriter)
+			s.PointsWriter = w
+			if err := s.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			cli := cc.Clients[0]
+			if got, exp := len(cli.Subscriptions), 1; got != exp {
+				t.Fatalf("unexpected number of subscriptions: got %d exp %d", got, exp)
+			}
+			cli.Deliver(tc.topic, []byte(tc.payload))
+
+			if got := w.Points(); !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("unexpected points:\ngot %+v\nexp %+v", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestService_UpdateSubscriptions(t *testing.T) {
+	cc := new(mqtttest.ClientCreator)
+	c := mqtt.NewConfig()
+	c.Enabled = true
+	c.URL = "tcp://mqtt.example.com:1883"
+	c.SetNewClientF(cc.NewClient)
+
+	s, err := mqtt.NewService(mqtt.Configs{c}, diagService.NewMQTTHandler())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PointsWriter = new(pointsWriter)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got := len(cc.Clients[0].Subscriptions); got != 0 {
+		t.Fatalf("unexpected subscriptions: %d", got)
+	}
+
+	c.Subscriptions = []mqtt.SubscriptionConfig{{Topic: "a/#", Database: "db"}}
+	if err := s.Update([]interface{}{c}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := len(cc.Clients), 2; got != exp {
+		t.Fatalf("unexpected number of clients: got %d exp %d", got, exp)
+	}
+	subs := cc.Clients[1].Subscriptions
+	if len(subs) != 1 || subs[0].Filter != "a/#" {
+		t.Errorf("unexpected subscriptions: %+v", subs)
+	}
+
+	c.Subscriptions[0].Database = ""
+	if err := s.Update([]interface{}{c}); err == nil {
+		t.Error("expected error for subscription without database")
+	}
+}
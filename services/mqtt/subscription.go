@@ -0,0 +1,162 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	kmodels "github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/services/pointdecode"
+	"github.com/pkg/errors"
+)
+
+// Formats of the payloads of subscribed messages.
+const (
+	DataFormatLineProtocol = pointdecode.FormatLineProtocol
+	DataFormatJSON         = pointdecode.FormatJSON
+)
+
+const DefaultPrecision = "ns"
+
+// SubscriptionConfig maps the messages of a topic filter to points.
+type SubscriptionConfig struct {
+	// Topic is the topic filter to subscribe to, it may contain the + and # wildcards.
+	Topic string `toml:"topic"`
+	// QoS of the subscription, one of "at-most-once", "at-least-once" or "exactly-once".
+	QoS QoSLevel `toml:"qos"`
+
+	// Database and RetentionPolicy the points are written to.
+	Database        string `toml:"database"`
+	RetentionPolicy string `toml:"retention-policy"`
+	// Measurement of the points.
+	// Required for JSON payloads, overrides the measurement of line protocol payloads.
+	Measurement string `toml:"measurement"`
+
+	// DataFormat of the payloads, either "line-protocol" or "json".
+	// Defaults to "line-protocol".
+	DataFormat string `toml:"data-format"`
+	// Precision of timestamps in line protocol payloads.
+	Precision string `toml:"precision"`
+
+	// TopicTags maps tag names to the zero based index of a segment of the message topic.
+	// For example {"device" = 1} tags messages on "sensors/d1/temp" with device=d1.
+	TopicTags map[string]int `toml:"topic-tags"`
+
+	// TagKeys are the keys of JSON payloads whose values are tags.
+	// All other keys with number, string or boolean values are fields.
+	TagKeys []string `toml:"tag-keys"`
+	// TimeKey is the key of JSON payloads whose value is the time of the point.
+	// If empty or missing the time the message is received is used.
+	TimeKey string `toml:"time-key"`
+	// TimeFormat is one of "unix", "unix_ms", "unix_us", "unix_ns" or a Go time layout.
+	// Defaults to RFC3339.
+	TimeFormat string `toml:"time-format"`
+}
+
+func (c SubscriptionConfig) Validate() error {
+	if c.Topic == "" {
+		return errors.New("must specify a topic for mqtt subscription")
+	}
+	if c.Database == "" {
+		return fmt.Errorf("must specify a database for mqtt subscription %q", c.Topic)
+	}
+	switch c.DataFormat {
+	case "", DataFormatLineProtocol:
+	case DataFormatJSON:
+		if c.Measurement == "" {
+			return fmt.Errorf("must specify a measurement for json mqtt subscription %q", c.Topic)
+		}
+	default:
+		return fmt.Errorf("invalid data-format %q for mqtt subscription %q, must be one of %q or %q", c.DataFormat, c.Topic, DataFormatLineProtocol, DataFormatJSON)
+	}
+	for tag, i := range c.TopicTags {
+		if i < 0 {
+			return fmt.Errorf("invalid topic segment %d for tag %q of mqtt subscription %q", i, tag, c.Topic)
+		}
+	}
+	return nil
+}
+
+// subscription decodes the messages of a topic filter into points.
+type subscription struct {
+	c       SubscriptionConfig
+	decoder pointdecode.Decoder
+}
+
+func newSubscription(c SubscriptionConfig) *subscription {
+	if c.DataFormat == "" {
+		c.DataFormat = DataFormatLineProtocol
+	}
+	if c.Precision == "" {
+		c.Precision = DefaultPrecision
+	}
+	var decoder pointdecode.Decoder
+	if c.DataFormat == DataFormatJSON {
+		tags := make(map[string]string, len(c.TagKeys))
+		for _, k := range c.TagKeys {
+			tags[k] = k
+		}
+		decoder = pointdecode.NewJSONDecoder(pointdecode.JSONConfig{
+			Measurement: c.Measurement,
+			Tags:        tags,
+			TimeKey:     c.TimeKey,
+			TimeFormat:  c.TimeFormat,
+		})
+	} else {
+		decoder = pointdecode.LineProtocolDecoder{Precision: c.Precision}
+	}
+	return &subscription{
+		c:       c,
+		decoder: decoder,
+	}
+}
+
+// Points decodes the payload of a message received on topic.
+// Tags from the topic take precedence over tags from the payload.
+func (s *subscription) Points(topic string, payload []byte, now time.Time) ([]edge.PointMessage, error) {
+	points, err := s.decoder.Decode(payload, now)
+	if err != nil {
+		return nil, err
+	}
+	topicTags := s.topicTags(topic)
+	msgs := make([]edge.PointMessage, len(points))
+	for i, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			return nil, err
+		}
+		tags := kmodels.Tags(p.Tags().Map())
+		for k, v := range topicTags {
+			tags[k] = v
+		}
+		name := s.c.Measurement
+		if name == "" {
+			name = string(p.Name())
+		}
+		msgs[i] = edge.NewPointMessage(
+			name,
+			s.c.Database,
+			s.c.RetentionPolicy,
+			kmodels.Dimensions{},
+			kmodels.Fields(fields),
+			tags,
+			p.Time(),
+		)
+	}
+	return msgs, nil
+}
+
+func (s *subscription) topicTags(topic string) kmodels.Tags {
+	if len(s.c.TopicTags) == 0 {
+		return nil
+	}
+	segments := strings.Split(topic, "/")
+	tags := make(kmodels.Tags, len(s.c.TopicTags))
+	for tag, i := range s.c.TopicTags {
+		if i < len(segments) && segments[i] != "" {
+			tags[tag] = segments[i]
+		}
+	}
+	return tags
+}
@@ -370,6 +370,11 @@ func (s *Service) Handler(c HandlerConfig, ctx ...keyvalue.T) (alert.Handler, er
 
 // Handle is a bound method to the handler that processes a given alert
 func (h *handler) Handle(event alert.Event) {
+	h.TryHandle(event)
+}
+
+// TryHandle sends the event to PagerDuty and returns an error if it could not be sent.
+func (h *handler) TryHandle(event alert.Event) error {
 	// Execute templates
 	td := event.TemplateData()
 	var hrefBuf bytes.Buffer
@@ -378,7 +383,7 @@ func (h *handler) Handle(event alert.Event) {
 		err := l.hrefTmpl.Execute(&hrefBuf, td)
 		if err != nil {
 			h.diag.Error("failed to handle event", err)
-			return
+			return err
 		}
 		h.c.Links[i].Href = hrefBuf.String()
 		hrefBuf.Reset()
@@ -387,7 +392,7 @@ func (h *handler) Handle(event alert.Event) {
 			err = l.textTmpl.Execute(&textBuf, td)
 			if err != nil {
 				h.diag.Error("failed to handle event", err)
-				return
+				return err
 			}
 			h.c.Links[i].Text = textBuf.String()
 			textBuf.Reset()
@@ -406,5 +411,7 @@ func (h *handler) Handle(event alert.Event) {
 		event.Data,
 	); err != nil {
 		h.diag.Error("failed to send event to PagerDuty", err)
+		return err
 	}
+	return nil
 }
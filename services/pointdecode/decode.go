@@ -0,0 +1,263 @@
+// Package pointdecode decodes line protocol and JSON data into points.
+// It is shared by the input services that receive data as opaque messages.
+package pointdecode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Formats of the decoded data.
+const (
+	FormatLineProtocol = "line-protocol"
+	FormatJSON         = "json"
+)
+
+// Decoder decodes the points of a message.
+type Decoder interface {
+	Decode(data []byte, now time.Time) ([]models.Point, error)
+}
+
+// JSONConfig maps the keys of JSON objects to the measurement, tags, fields and time of points.
+// Keys of nested objects are referenced with dots, i.e. "cpu.usage".
+type JSONConfig struct {
+	// Measurement is the name of the measurement of all points.
+	Measurement string `toml:"measurement"`
+	// MeasurementKey is the key whose value is the name of the measurement.
+	// If the key is missing Measurement is used.
+	MeasurementKey string `toml:"measurement-key"`
+	// Tags maps keys to tag names.
+	Tags map[string]string `toml:"tags"`
+	// Fields maps keys to field names.
+	// If empty all other top level keys with a number, string or boolean value are fields.
+	Fields map[string]string `toml:"fields"`
+	// TimeKey is the key whose value is the time of the point.
+	// If empty or missing the time the message is received is used.
+	TimeKey string `toml:"time-key"`
+	// TimeFormat is one of "unix", "unix_ms", "unix_us", "unix_ns" or a Go time layout.
+	// Defaults to RFC3339.
+	TimeFormat string `toml:"time-format"`
+}
+
+// LineProtocolDecoder decodes line protocol into points.
+type LineProtocolDecoder struct {
+	// Precision of timestamps, defaults to nanoseconds.
+	Precision string
+}
+
+func (d LineProtocolDecoder) Decode(data []byte, now time.Time) ([]models.Point, error) {
+	return models.ParsePointsWithPrecision(data, now, d.Precision)
+}
+
+// JSONDecoder decodes a JSON object, or an array of JSON objects, into points.
+type JSONDecoder struct {
+	c JSONConfig
+	// Top level keys that are not fields when all keys are fields.
+	reserved map[string]bool
+}
+
+func NewJSONDecoder(c JSONConfig) *JSONDecoder {
+	reserved := make(map[string]bool)
+	for _, k := range []string{c.MeasurementKey, c.TimeKey} {
+		if k != "" {
+			reserved[topLevelKey(k)] = true
+		}
+	}
+	for k := range c.Tags {
+		reserved[topLevelKey(k)] = true
+	}
+	return &JSONDecoder{
+		c:        c,
+		reserved: reserved,
+	}
+}
+
+func topLevelKey(key string) string {
+	if i := strings.IndexByte(key, '.'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+func (d *JSONDecoder) Decode(data []byte, now time.Time) ([]models.Point, error) {
+	data = bytes.TrimSpace(data)
+	// Decode numbers as json.Number so that integer timestamps keep their precision.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var objects []map[string]interface{}
+	if len(data) > 0 && data[0] == '[' {
+		if err := dec.Decode(&objects); err != nil {
+			return nil, err
+		}
+	} else {
+		var o map[string]interface{}
+		if err := dec.Decode(&o); err != nil {
+			return nil, err
+		}
+		objects = append(objects, o)
+	}
+	points := make([]models.Point, 0, len(objects))
+	for _, o := range objects {
+		p, err := d.point(o, now)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (d *JSONDecoder) point(o map[string]interface{}, now time.Time) (models.Point, error) {
+	name := d.c.Measurement
+	if d.c.MeasurementKey != "" {
+		if v, ok := lookup(o, d.c.MeasurementKey); ok {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("measurement key %q must be a string, got %T", d.c.MeasurementKey, v)
+			}
+			name = s
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("missing measurement key %q", d.c.MeasurementKey)
+	}
+
+	tags := make(map[string]string, len(d.c.Tags))
+	for key, tag := range d.c.Tags {
+		v, ok := lookup(o, key)
+		if !ok || v == nil {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			tags[tag] = v
+		case json.Number:
+			tags[tag] = v.String()
+		case bool:
+			tags[tag] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("tag key %q must have a scalar value, got %T", key, v)
+		}
+	}
+
+	fields := make(models.Fields)
+	if len(d.c.Fields) > 0 {
+		for key, field := range d.c.Fields {
+			v, ok := lookup(o, key)
+			if !ok || v == nil {
+				continue
+			}
+			fv, ok := fieldValue(v)
+			if !ok {
+				return nil, fmt.Errorf("field key %q must have a number, string or boolean value, got %T", key, v)
+			}
+			fields[field] = fv
+		}
+	} else {
+		for key, v := range o {
+			if d.reserved[key] {
+				continue
+			}
+			if fv, ok := fieldValue(v); ok {
+				fields[key] = fv
+			}
+		}
+	}
+	if len(fields) == 0 {
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("no fields found in object with keys %v", keys)
+	}
+
+	t := now
+	if d.c.TimeKey != "" {
+		if v, ok := lookup(o, d.c.TimeKey); ok {
+			var err error
+			t, err = ParseTime(v, d.c.TimeFormat)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time key %q: %v", d.c.TimeKey, err)
+			}
+		}
+	}
+	return models.NewPoint(name, models.NewTags(tags), fields, t)
+}
+
+// fieldValue returns the value as a field value, numbers are always floats.
+func fieldValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string, bool:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// lookup returns the value of a dot separated key of nested objects.
+func lookup(o map[string]interface{}, key string) (interface{}, bool) {
+	for {
+		v, ok := o[key]
+		if ok {
+			return v, true
+		}
+		i := strings.IndexByte(key, '.')
+		if i < 0 {
+			return nil, false
+		}
+		nested, ok := o[key[:i]].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		o = nested
+		key = key[i+1:]
+	}
+}
+
+// ParseTime parses a JSON value as a time.
+// The format is one of "unix", "unix_ms", "unix_us", "unix_ns" or a Go time layout,
+// and defaults to RFC3339.
+func ParseTime(v interface{}, format string) (time.Time, error) {
+	switch format {
+	case "unix", "unix_ms", "unix_us", "unix_ns":
+		n, ok := v.(json.Number)
+		if !ok {
+			return time.Time{}, fmt.Errorf("%s time must be a number, got %T", format, v)
+		}
+		unit := time.Nanosecond
+		switch format {
+		case "unix":
+			unit = time.Second
+		case "unix_ms":
+			unit = time.Millisecond
+		case "unix_us":
+			unit = time.Microsecond
+		}
+		if i, err := n.Int64(); err == nil {
+			return time.Unix(0, i*int64(unit)).UTC(), nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(f*float64(unit))).UTC(), nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("time must be a string, got %T", v)
+	}
+	if format == "" {
+		format = time.RFC3339Nano
+	}
+	return time.Parse(format, s)
+}
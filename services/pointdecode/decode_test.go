@@ -0,0 +1,94 @@
+package pointdecode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/services/pointdecode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineProtocolDecoder(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := pointdecode.LineProtocolDecoder{Precision: "s"}
+	points, err := d.Decode([]byte("cpu,host=serverA value=1 1609459260\ncpu,host=serverB value=2\n"), now)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, "cpu,host=serverA value=1 1609459260000000000", points[0].String())
+	assert.Equal(t, now, points[1].Time())
+}
+
+func TestJSONDecoder(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name string
+		c    pointdecode.JSONConfig
+		data string
+		exp  []string
+		err  bool
+	}{
+		{
+			name: "all fields",
+			c: pointdecode.JSONConfig{
+				Measurement: "cpu",
+				Tags:        map[string]string{"host": "host"},
+				TimeKey:     "time",
+			},
+			data: `{"host":"serverA","time":"2021-01-01T00:01:00Z","usage":12.5,"ok":true,"nested":{"x":1}}`,
+			exp:  []string{"cpu,host=serverA ok=true,usage=12.5 1609459260000000000"},
+		},
+		{
+			name: "field mapping",
+			c: pointdecode.JSONConfig{
+				MeasurementKey: "name",
+				Tags:           map[string]string{"meta.host": "host"},
+				Fields:         map[string]string{"values.usage": "usage_percent"},
+				TimeKey:        "ts",
+				TimeFormat:     "unix_ms",
+			},
+			data: `[{"name":"cpu","meta":{"host":"serverA"},"values":{"usage":50},"ts":1609459260123},{"name":"mem","values":{"usage":20}}]`,
+			exp: []string{
+				"cpu,host=serverA usage_percent=50 1609459260123000000",
+				"mem usage_percent=20 1609459200000000000",
+			},
+		},
+		{
+			name: "unix nanoseconds",
+			c: pointdecode.JSONConfig{
+				Measurement: "cpu",
+				TimeKey:     "ts",
+				TimeFormat:  "unix_ns",
+			},
+			data: `{"value":1,"ts":1609459260123456789}`,
+			exp:  []string{"cpu value=1 1609459260123456789"},
+		},
+		{
+			name: "no fields",
+			c:    pointdecode.JSONConfig{Measurement: "cpu"},
+			data: `{"nested":{"x":1}}`,
+			err:  true,
+		},
+		{
+			name: "invalid time",
+			c:    pointdecode.JSONConfig{Measurement: "cpu", TimeKey: "time"},
+			data: `{"value":1,"time":"yesterday"}`,
+			err:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			points, err := pointdecode.NewJSONDecoder(tc.c).Decode([]byte(tc.data), now)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]string, len(points))
+			for i, p := range points {
+				got[i] = p.String()
+			}
+			assert.Equal(t, tc.exp, got)
+		})
+	}
+}
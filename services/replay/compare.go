@@ -0,0 +1,211 @@
+package replay
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/influxdata/kapacitor"
+	kclient "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/clock"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/services/tasktest"
+	"github.com/influxdata/kapacitor/uuid"
+	"github.com/pkg/errors"
+)
+
+func (s *Service) handleCompareReplay(w http.ResponseWriter, req *http.Request) {
+	var opt kclient.CompareReplayOptions
+	dec := json.NewDecoder(req.Body)
+	err := dec.Decode(&opt)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	if opt.TICKscript == "" {
+		httpd.HttpError(w, "must provide a candidate TICKscript", true, http.StatusBadRequest)
+		return
+	}
+
+	current, err := s.TaskStore.Load(opt.Task)
+	if err != nil {
+		httpd.HttpError(w, "task load: "+err.Error(), true, http.StatusNotFound)
+		return
+	}
+	recording, err := s.recordings.Get(opt.Recording)
+	if err != nil {
+		httpd.HttpError(w, "recording not found: "+err.Error(), true, http.StatusNotFound)
+		return
+	}
+
+	id := uuid.New().String()
+	currentTM := s.TaskMaster.New("compare-current:" + id)
+	candidateTM := s.TaskMaster.New("compare-candidate:" + id)
+	currentOutputs := tasktest.Isolate(currentTM, "")
+	candidateOutputs := tasktest.Isolate(candidateTM, "")
+	// The candidate replaces the current task, so it keeps its ID, type and DBRPs.
+	candidate, err := candidateTM.NewTask(current.ID, opt.TICKscript, current.Type, current.DBRPs, current.SnapshotInterval, nil)
+	if err != nil {
+		httpd.HttpError(w, "invalid TICKscript: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	type result struct {
+		out *compareOutput
+		err error
+	}
+	currentC := make(chan result, 1)
+	candidateC := make(chan result, 1)
+	go func() {
+		out, err := s.doCompareReplay(currentTM, currentOutputs, current, recording)
+		currentC <- result{out: out, err: err}
+	}()
+	go func() {
+		out, err := s.doCompareReplay(candidateTM, candidateOutputs, candidate, recording)
+		candidateC <- result{out: out, err: err}
+	}()
+	cur, cand := <-currentC, <-candidateC
+	if cur.err != nil {
+		httpd.HttpError(w, "current task: "+cur.err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	if cand.err != nil {
+		httpd.HttpError(w, "candidate task: "+cand.err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+
+	comparison := kclient.ReplayComparison{
+		Recording:      recording.ID,
+		Task:           current.ID,
+		Alerts:         diffAlerts(cur.out.alerts, cand.out.alerts),
+		CurrentStats:   cur.out.stats,
+		CandidateStats: cand.out.stats,
+	}
+	comparison.WritesAdded, comparison.WritesRemoved = diffWrites(cur.out.writes, cand.out.writes)
+	comparison.Equal = len(comparison.Alerts) == 0 &&
+		len(comparison.WritesAdded) == 0 &&
+		len(comparison.WritesRemoved) == 0
+	w.Write(httpd.MarshalJSON(comparison, true))
+}
+
+// compareOutput is what one version of a task output while replaying the recording.
+type compareOutput struct {
+	alerts []kclient.TaskTestAlert
+	writes []string
+	stats  kclient.ExecutionStats
+}
+
+// doCompareReplay replays the recording into the task on an isolated task master and returns its outputs.
+// The recording is replayed as fast as possible with its own times, so that the outputs of both versions are comparable.
+func (s *Service) doCompareReplay(tm *kapacitor.TaskMaster, outputs *tasktest.Outputs, task *kapacitor.Task, recording Recording) (*compareOutput, error) {
+	replay := &Replay{ID: tm.ID()}
+	runReplay := func(tm *kapacitor.TaskMaster) error {
+		return s.runRecording(tm, task, recording, clock.Fast(), true)
+	}
+	if err := s.runReplay(tm, replay, task, runReplay); err != nil {
+		return nil, err
+	}
+	return &compareOutput{
+		alerts: outputs.Alerts(),
+		writes: outputs.Writes(),
+		stats: kclient.ExecutionStats{
+			TaskStats: replay.ExecutionStats.TaskStats,
+			NodeStats: replay.ExecutionStats.NodeStats,
+		},
+	}, nil
+}
+
+// diffAlerts compares the alert events of both versions per alert ID.
+// The events of an alert are paired in order, paired events differ if their level or time differs.
+func diffAlerts(current, candidate []kclient.TaskTestAlert) []kclient.AlertDifference {
+	byID := func(alerts []kclient.TaskTestAlert) map[string][]kclient.TaskTestAlert {
+		m := make(map[string][]kclient.TaskTestAlert)
+		for _, a := range alerts {
+			m[a.ID] = append(m[a.ID], a)
+		}
+		return m
+	}
+	cur, cand := byID(current), byID(candidate)
+	ids := make([]string, 0, len(cur)+len(cand))
+	for id := range cur {
+		ids = append(ids, id)
+	}
+	for id := range cand {
+		if _, ok := cur[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var diffs []kclient.AlertDifference
+	for _, id := range ids {
+		a, b := cur[id], cand[id]
+		d := kclient.AlertDifference{ID: id}
+		n := len(a)
+		if len(b) < n {
+			n = len(b)
+		}
+		for i := 0; i < n; i++ {
+			if a[i].Level != b[i].Level || !a[i].Time.Equal(b[i].Time) {
+				d.Changed = append(d.Changed, kclient.AlertChange{Current: a[i], Candidate: b[i]})
+			}
+		}
+		d.Removed = append(d.Removed, a[n:]...)
+		d.Added = append(d.Added, b[n:]...)
+		if len(d.Changed) > 0 || len(d.Removed) > 0 || len(d.Added) > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+// diffWrites returns the writes only in the candidate and only in the current version.
+// Both lists must be sorted.
+func diffWrites(current, candidate []string) (added, removed []string) {
+	i, j := 0, 0
+	for i < len(current) && j < len(candidate) {
+		switch {
+		case current[i] == candidate[j]:
+			i++
+			j++
+		case current[i] < candidate[j]:
+			removed = append(removed, current[i])
+			i++
+		default:
+			added = append(added, candidate[j])
+			j++
+		}
+	}
+	removed = append(removed, current[i:]...)
+	added = append(added, candidate[j:]...)
+	return added, removed
+}
+
+// runRecording replays a recording into the task.
+func (s *Service) runRecording(tm *kapacitor.TaskMaster, task *kapacitor.Task, recording Recording, clk clock.Clock, recTime bool) error {
+	dataSource, err := parseDataSourceURL(recording.DataURL)
+	if err != nil {
+		return errors.Wrap(err, "load data source")
+	}
+	var replayC <-chan error
+	switch task.Type {
+	case kapacitor.StreamTask:
+		f, err := dataSource.StreamReader()
+		if err != nil {
+			return errors.Wrap(err, "data source open")
+		}
+		stream, err := tm.Stream(recording.ID)
+		if err != nil {
+			return errors.Wrap(err, "stream start")
+		}
+		replayC = kapacitor.ReplayStreamFromIO(clk, f, stream, recTime, precision)
+	case kapacitor.BatchTask:
+		fs, err := dataSource.BatchReaders()
+		if err != nil {
+			return errors.Wrap(err, "data source open")
+		}
+		collectors := tm.BatchCollectors(task.ID)
+		replayC = kapacitor.ReplayBatchFromIO(clk, fs, collectors, recTime)
+	}
+	return <-replayC
+}
@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	kclient "github.com/influxdata/kapacitor/client/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAlerts(t *testing.T) {
+	at := func(id, level string, sec int64) kclient.TaskTestAlert {
+		return kclient.TaskTestAlert{ID: id, Level: level, Message: id + " is " + level, Time: time.Unix(sec, 0).UTC()}
+	}
+	current := []kclient.TaskTestAlert{
+		at("a", "WARNING", 10),
+		at("b", "CRITICAL", 10),
+		at("a", "OK", 20),
+		at("c", "WARNING", 30),
+		at("c", "OK", 40),
+	}
+	candidate := []kclient.TaskTestAlert{
+		at("a", "WARNING", 10),
+		at("b", "WARNING", 10),
+		at("a", "OK", 25),
+		at("c", "WARNING", 30),
+		at("d", "CRITICAL", 50),
+	}
+	exp := []kclient.AlertDifference{
+		{
+			ID: "a",
+			Changed: []kclient.AlertChange{
+				{Current: at("a", "OK", 20), Candidate: at("a", "OK", 25)},
+			},
+		},
+		{
+			ID: "b",
+			Changed: []kclient.AlertChange{
+				{Current: at("b", "CRITICAL", 10), Candidate: at("b", "WARNING", 10)},
+			},
+		},
+		{
+			ID:      "c",
+			Removed: []kclient.TaskTestAlert{at("c", "OK", 40)},
+		},
+		{
+			ID:    "d",
+			Added: []kclient.TaskTestAlert{at("d", "CRITICAL", 50)},
+		},
+	}
+	assert.Equal(t, exp, diffAlerts(current, candidate))
+	assert.Empty(t, diffAlerts(current, current))
+}
+
+func TestDiffWrites(t *testing.T) {
+	current := []string{"cpu value=1 0", "cpu value=2 10", "cpu value=2 10", "cpu value=3 20"}
+	candidate := []string{"cpu value=1 0", "cpu value=2 10", "cpu value=4 30"}
+	added, removed := diffWrites(current, candidate)
+	assert.Equal(t, []string{"cpu value=4 30"}, added)
+	assert.Equal(t, []string{"cpu value=2 10", "cpu value=3 20"}, removed)
+
+	added, removed = diffWrites(current, current)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
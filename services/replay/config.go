@@ -4,19 +4,28 @@ import (
 	"fmt"
 )
 
+// DefaultMaxUploadSize is the default maximum size in bytes of an uploaded recording.
+const DefaultMaxUploadSize = 1 << 30
+
 type Config struct {
 	Dir string `toml:"dir"`
+	// MaxUploadSize is the maximum size in bytes of an uploaded recording, zero means no limit.
+	MaxUploadSize int64 `toml:"max-upload-size"`
 }
 
 func (c Config) Validate() error {
 	if c.Dir == "" {
 		return fmt.Errorf("must specify dir")
 	}
+	if c.MaxUploadSize < 0 {
+		return fmt.Errorf("max-upload-size must not be negative, got %d", c.MaxUploadSize)
+	}
 	return nil
 }
 
 func NewConfig() Config {
 	return Config{
-		Dir: "./replay",
+		Dir:           "./replay",
+		MaxUploadSize: DefaultMaxUploadSize,
 	}
 }
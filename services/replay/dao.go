@@ -78,6 +78,9 @@ type Recording struct {
 	Error    string
 	Status   Status
 	Progress float64
+	// Time range of uploaded recordings
+	Start time.Time
+	Stop  time.Time
 }
 
 type rawRecording Recording
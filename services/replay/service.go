@@ -41,11 +41,13 @@ const (
 	recordStreamPath       = recordingsPath + "/stream"
 	recordBatchPath        = recordingsPath + "/batch"
 	recordQueryPath        = recordingsPath + "/query"
+	recordUploadPath       = recordingsPath + "/upload"
 
 	replaysPath         = "/replays"
 	replaysPathAnchored = "/replays/"
 	replayBatchPath     = replaysPath + "/batch"
 	replayQueryPath     = replaysPath + "/query"
+	replayComparePath   = replaysPath + "/compare"
 )
 
 var validID = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
@@ -57,7 +59,8 @@ type Diagnostic interface {
 
 // Handles recording, starting, and waiting on replays
 type Service struct {
-	saveDir string
+	saveDir       string
+	maxUploadSize int64
 
 	recordings RecordingDAO
 	replays    ReplayDAO
@@ -96,8 +99,9 @@ type Service struct {
 // Create a new replay master.
 func NewService(conf Config, d Diagnostic) *Service {
 	return &Service{
-		saveDir: conf.Dir,
-		diag:    d,
+		saveDir:       conf.Dir,
+		maxUploadSize: conf.MaxUploadSize,
+		diag:          d,
 	}
 }
 
@@ -177,6 +181,11 @@ func (s *Service) Open() error {
 			Pattern:     recordQueryPath,
 			HandlerFunc: s.handleRecordQuery,
 		},
+		{
+			Method:      "POST",
+			Pattern:     recordUploadPath,
+			HandlerFunc: s.handleUploadRecording,
+		},
 		{
 			Method:      "GET",
 			Pattern:     replaysPathAnchored,
@@ -212,6 +221,11 @@ func (s *Service) Open() error {
 			Pattern:     replayQueryPath,
 			HandlerFunc: s.handleReplayQuery,
 		},
+		{
+			Method:      "POST",
+			Pattern:     replayComparePath,
+			HandlerFunc: s.handleCompareReplay,
+		},
 	}
 
 	return s.HTTPDService.AddRoutes(s.routes)
@@ -388,6 +402,8 @@ func convertRecording(recording Recording) kclient.Recording {
 		Error:    recording.Error,
 		Status:   status,
 		Progress: recording.Progress,
+		Start:    recording.Start,
+		Stop:     recording.Stop,
 	}
 }
 
@@ -449,6 +465,8 @@ var allRecordingFields = []string{
 	"error",
 	"status",
 	"progress",
+	"start",
+	"stop",
 }
 
 func (s *Service) handleListRecordings(w http.ResponseWriter, r *http.Request) {
@@ -522,6 +540,10 @@ func (s *Service) handleListRecordings(w http.ResponseWriter, r *http.Request) {
 				}
 			case "progress":
 				value = recording.Progress
+			case "start":
+				value = recording.Start
+			case "stop":
+				value = recording.Stop
 			default:
 				httpd.HttpError(w, fmt.Sprintf("unsupported field %q", field), true, http.StatusBadRequest)
 				return
@@ -1112,35 +1134,10 @@ func (r *Service) handleReplayQuery(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Service) doReplayFromRecording(replay *Replay, task *kapacitor.Task, recording Recording, clk clock.Clock, recTime bool) error {
-	dataSource, err := parseDataSourceURL(recording.DataURL)
-	if err != nil {
-		return errors.Wrap(err, "load data source")
-	}
 	runReplay := func(tm *kapacitor.TaskMaster) error {
-		var replayC <-chan error
-		switch task.Type {
-		case kapacitor.StreamTask:
-			f, err := dataSource.StreamReader()
-			if err != nil {
-				return errors.Wrap(err, "data source open")
-			}
-			stream, err := tm.Stream(recording.ID)
-			if err != nil {
-				return errors.Wrap(err, "stream start")
-			}
-			replayC = kapacitor.ReplayStreamFromIO(clk, f, stream, recTime, precision)
-		case kapacitor.BatchTask:
-			fs, err := dataSource.BatchReaders()
-			if err != nil {
-				return errors.Wrap(err, "data source open")
-			}
-			collectors := tm.BatchCollectors(task.ID)
-			replayC = kapacitor.ReplayBatchFromIO(clk, fs, collectors, recTime)
-		}
-		return <-replayC
+		return r.runRecording(tm, task, recording, clk, recTime)
 	}
 	return r.doReplay(replay, task, runReplay)
-
 }
 
 func (r *Service) doLiveBatchReplay(replay *Replay, task *kapacitor.Task, clk clock.Clock, recTime bool, start, stop time.Time) error {
@@ -1210,6 +1207,11 @@ func (r *Service) doReplay(replay *Replay, task *kapacitor.Task, runReplay func(
 	r.TaskMasterLookup.Set(tm)
 	defer r.TaskMasterLookup.Delete(tm)
 
+	return r.runReplay(tm, replay, task, runReplay)
+}
+
+// runReplay runs the task on the task master while replaying data into it and waits for the task to finish.
+func (r *Service) runReplay(tm *kapacitor.TaskMaster, replay *Replay, task *kapacitor.Task, runReplay func(tm *kapacitor.TaskMaster) error) error {
 	tm.Open()
 	defer tm.Close()
 	et, err := tm.StartTask(task)
@@ -0,0 +1,79 @@
+package replay_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httpd/httpdtest"
+	"github.com/influxdata/kapacitor/services/replay"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+)
+
+var diagService *diagnostic.Service
+
+func init() {
+	diagService = diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	diagService.Open()
+}
+
+func OpenNewService(t *testing.T, c replay.Config) *httpdtest.Server {
+	c.Dir = t.TempDir()
+	service := replay.NewService(c, diagService.NewReplayHandler())
+	store := storagetest.New(t, diagService.NewStorageHandler())
+	t.Cleanup(func() { store.Close() })
+	service.StorageService = store
+	server := httpdtest.NewServer(testing.Verbose())
+	t.Cleanup(func() { server.Close() })
+	service.HTTPDService = server
+	if err := service.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+// upload posts line protocol to the upload endpoint and returns the response status.
+func upload(t *testing.T, server *httpdtest.Server, id, data string) int {
+	t.Helper()
+	q := url.Values{
+		"id":     {id},
+		"format": {client.LineProtocolFormat},
+		"db":     {"telegraf"},
+		"rp":     {"autogen"},
+	}
+	resp, err := http.Post(server.Server.URL+"/kapacitor/v1/recordings/upload?"+q.Encode(), "text/plain", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestService_UploadRecordingExists(t *testing.T) {
+	server := OpenNewService(t, replay.NewConfig())
+
+	if got, exp := upload(t, server, "incident", "cpu value=1 0\n"), http.StatusCreated; got != exp {
+		t.Fatalf("unexpected status of first upload: got %d exp %d", got, exp)
+	}
+	if got, exp := upload(t, server, "incident", "cpu value=1 0\n"), http.StatusConflict; got != exp {
+		t.Fatalf("unexpected status of upload with an existing ID: got %d exp %d", got, exp)
+	}
+}
+
+func TestService_UploadRecordingTooLarge(t *testing.T) {
+	c := replay.NewConfig()
+	c.MaxUploadSize = 32
+	server := OpenNewService(t, c)
+
+	data := strings.Repeat("cpu value=1 0\n", 10)
+	if got, exp := upload(t, server, "incident", data), http.StatusRequestEntityTooLarge; got != exp {
+		t.Fatalf("unexpected status of upload larger than the maximum size: got %d exp %d", got, exp)
+	}
+	if got, exp := upload(t, server, "incident", data[:28]), http.StatusCreated; got != exp {
+		t.Fatalf("unexpected status of upload within the maximum size: got %d exp %d", got, exp)
+	}
+}
@@ -0,0 +1,415 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	imodels "github.com/influxdata/influxdb/models"
+	"github.com/influxdata/kapacitor"
+	kclient "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/influxdb"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/uuid"
+	"github.com/pkg/errors"
+)
+
+// Name of the multipart form field that holds the uploaded data.
+const uploadFormField = "data"
+
+func (s *Service) handleUploadRecording(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		id = uuid.New().String()
+	}
+	if !validID.MatchString(id) {
+		httpd.HttpError(w, fmt.Sprintf("recording ID must contain only letters, numbers, '-', '.' and '_'. %q", id), true, http.StatusBadRequest)
+		return
+	}
+	format := q.Get("format")
+	dbrp := kapacitor.DBRP{
+		Database:        q.Get("db"),
+		RetentionPolicy: q.Get("rp"),
+	}
+	lpPrecision := q.Get("precision")
+	if !validPrecision(lpPrecision) {
+		httpd.HttpError(w, fmt.Sprintf("invalid precision %q", lpPrecision), true, http.StatusBadRequest)
+		return
+	}
+
+	var typ RecordingType
+	switch format {
+	case kclient.LineProtocolFormat:
+		typ = StreamRecording
+	case kclient.BatchJSONFormat:
+		typ = BatchRecording
+	case kclient.CSVFormat:
+		switch q.Get("type") {
+		case "", "stream":
+			typ = StreamRecording
+		case "batch":
+			typ = BatchRecording
+		default:
+			httpd.HttpError(w, fmt.Sprintf("invalid recording type %q", q.Get("type")), true, http.StatusBadRequest)
+			return
+		}
+	default:
+		httpd.HttpError(w, fmt.Sprintf("invalid format %q, must be one of %q, %q or %q", format, kclient.LineProtocolFormat, kclient.CSVFormat, kclient.BatchJSONFormat), true, http.StatusBadRequest)
+		return
+	}
+	if typ == StreamRecording && (dbrp.Database == "" || dbrp.RetentionPolicy == "") {
+		httpd.HttpError(w, "must provide the database and retention policy of stream recordings", true, http.StatusBadRequest)
+		return
+	}
+
+	if s.maxUploadSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+	}
+	data, err := uploadedData(r)
+	if err != nil {
+		s.uploadError(w, err)
+		return
+	}
+
+	var dataUrl = s.dataURLFromID(id, streamEXT)
+	if typ == BatchRecording {
+		dataUrl = s.dataURLFromID(id, batchEXT)
+	}
+	recording := Recording{
+		ID:      id,
+		DataURL: dataUrl.String(),
+		Type:    typ,
+		Date:    time.Now(),
+		Status:  Running,
+	}
+	if err := s.recordings.Create(recording); err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrRecordingExists {
+			status = http.StatusConflict
+		}
+		httpd.HttpError(w, err.Error(), true, status)
+		return
+	}
+	ds, _ := parseDataSourceURL(recording.DataURL)
+
+	var tr timeRange
+	switch typ {
+	case StreamRecording:
+		tr, err = saveUploadedStream(ds, data, format, dbrp, lpPrecision)
+	case BatchRecording:
+		tr, err = saveUploadedBatch(ds, data, format)
+	}
+	if err != nil {
+		// Invalid data is not kept as a failed recording.
+		if err := s.recordings.Delete(id); err != nil {
+			s.diag.Error("failed to delete recording", err)
+		}
+		ds.Remove()
+		s.uploadError(w, err)
+		return
+	}
+	recording.Start = tr.start
+	recording.Stop = tr.stop
+	s.updateRecordingResult(recording, ds, nil)
+
+	recording, err = s.recordings.Get(id)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(httpd.MarshalJSON(convertRecording(recording), true))
+}
+
+// uploadError responds to an upload whose data could not be read or is invalid.
+func (s *Service) uploadError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		httpd.HttpError(w, fmt.Sprintf("recording data exceeds the maximum upload size of %d bytes", s.maxUploadSize), true, http.StatusRequestEntityTooLarge)
+		return
+	}
+	httpd.HttpError(w, "invalid recording data: "+err.Error(), true, http.StatusBadRequest)
+}
+
+func validPrecision(precision string) bool {
+	switch precision {
+	case "", "n", "ns", "u", "ms", "s", "m", "h":
+		return true
+	}
+	return false
+}
+
+// uploadedData returns the request body or,
+// for multipart requests, the part named data.
+func uploadedData(r *http.Request) (io.Reader, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("missing form field %q", uploadFormField)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == uploadFormField {
+			return part, nil
+		}
+	}
+}
+
+// timeRange is the time range of the points in a recording.
+type timeRange struct {
+	start, stop time.Time
+}
+
+func (r *timeRange) add(t time.Time) {
+	if r.start.IsZero() || t.Before(r.start) {
+		r.start = t
+	}
+	if t.After(r.stop) {
+		r.stop = t
+	}
+}
+
+func saveUploadedStream(ds DataSource, data io.Reader, format string, dbrp kapacitor.DBRP, lpPrecision string) (timeRange, error) {
+	sw, err := ds.StreamWriter()
+	if err != nil {
+		return timeRange{}, err
+	}
+	var tr timeRange
+	switch format {
+	case kclient.LineProtocolFormat:
+		tr, err = writeLineProtocol(sw, data, dbrp, lpPrecision)
+	case kclient.CSVFormat:
+		tr, err = writeCSVStream(sw, data, dbrp)
+	default:
+		err = fmt.Errorf("cannot record %s as stream", format)
+	}
+	if err != nil {
+		sw.Close()
+		return timeRange{}, err
+	}
+	return tr, sw.Close()
+}
+
+func saveUploadedBatch(ds DataSource, data io.Reader, format string) (timeRange, error) {
+	archiver, err := ds.BatchArchiver()
+	if err != nil {
+		return timeRange{}, err
+	}
+	w, err := archiver.Archive(0)
+	if err != nil {
+		archiver.Close()
+		return timeRange{}, err
+	}
+	var tr timeRange
+	switch format {
+	case kclient.BatchJSONFormat:
+		tr, err = writeBatchJSON(w, data)
+	case kclient.CSVFormat:
+		tr, err = writeCSVBatch(w, data)
+	default:
+		err = fmt.Errorf("cannot record %s as batch", format)
+	}
+	if err != nil {
+		archiver.Close()
+		return timeRange{}, err
+	}
+	return tr, archiver.Close()
+}
+
+// writeLineProtocol validates line protocol and writes it in the stream recording format.
+// Blank lines and comments are skipped.
+// The timestamps are parsed with the given precision and written with the precision of recordings.
+func writeLineProtocol(w io.Writer, data io.Reader, dbrp kapacitor.DBRP, lpPrecision string) (timeRange, error) {
+	var tr timeRange
+	now := time.Now().UTC()
+	r := bufio.NewReader(data)
+	n := 0
+	for lineNum := 1; ; lineNum++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return tr, err
+		}
+		if l := bytes.TrimSpace(line); len(l) > 0 && l[0] != '#' {
+			points, perr := imodels.ParsePointsWithPrecision(l, now, lpPrecision)
+			if perr != nil {
+				return tr, errors.Wrapf(perr, "line %d", lineNum)
+			}
+			for _, p := range points {
+				fields, ferr := p.Fields()
+				if ferr != nil {
+					return tr, errors.Wrapf(ferr, "line %d", lineNum)
+				}
+				pm := edge.NewPointMessage(
+					string(p.Name()),
+					dbrp.Database,
+					dbrp.RetentionPolicy,
+					models.Dimensions{},
+					models.Fields(fields),
+					models.Tags(p.Tags().Map()),
+					p.Time().UTC(),
+				)
+				if err := kapacitor.WritePointForRecording(w, pm, precision); err != nil {
+					return tr, err
+				}
+				tr.add(pm.Time())
+				n++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if n == 0 {
+		return tr, errors.New("no points")
+	}
+	return tr, nil
+}
+
+// writeCSVStream converts annotated CSV into points and writes them in the stream recording format.
+func writeCSVStream(w io.Writer, data io.Reader, dbrp kapacitor.DBRP) (timeRange, error) {
+	var tr timeRange
+	err := readCSVBatches(data, func(b edge.BufferedBatchMessage) error {
+		for _, p := range b.Points() {
+			pm := edge.NewPointMessage(
+				b.Name(),
+				dbrp.Database,
+				dbrp.RetentionPolicy,
+				models.Dimensions{},
+				p.Fields(),
+				p.Tags(),
+				p.Time(),
+			)
+			if err := kapacitor.WritePointForRecording(w, pm, precision); err != nil {
+				return err
+			}
+			tr.add(pm.Time())
+		}
+		return nil
+	})
+	return tr, err
+}
+
+// writeCSVBatch converts annotated CSV into batches, one per table, and writes them in the batch recording format.
+func writeCSVBatch(w io.Writer, data io.Reader) (timeRange, error) {
+	var tr timeRange
+	err := readCSVBatches(data, func(b edge.BufferedBatchMessage) error {
+		if err := kapacitor.WriteBatchForRecording(w, b); err != nil {
+			return err
+		}
+		for _, p := range b.Points() {
+			tr.add(p.Time())
+		}
+		return nil
+	})
+	return tr, err
+}
+
+// readCSVBatches parses annotated CSV and calls f with one batch per table as soon as the table has been read.
+// Tables with _field and _value columns are converted so that the _value column is named after the field.
+func readCSVBatches(data io.Reader, f func(edge.BufferedBatchMessage) error) error {
+	n := 0
+	err := influxdb.ReadFluxTables(data, func(row imodels.Row) error {
+		if field, ok := row.Tags["_field"]; ok {
+			tags := make(map[string]string, len(row.Tags)-1)
+			for k, v := range row.Tags {
+				if k != "_field" {
+					tags[k] = v
+				}
+			}
+			columns := make([]string, len(row.Columns))
+			for j, c := range row.Columns {
+				if c == "_value" {
+					c = field
+				}
+				columns[j] = c
+			}
+			row.Tags = tags
+			row.Columns = columns
+		}
+		bs, err := edge.ResultToBufferedBatches(influxdb.Result{Series: []imodels.Row{row}}, false)
+		if err != nil {
+			return err
+		}
+		for _, b := range bs {
+			if len(b.Points()) == 0 {
+				continue
+			}
+			if err := f(b); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("no points")
+	}
+	return nil
+}
+
+// uploadedBatch is the part of the batch recording format that is validated before decoding a batch,
+// since decoding a batch ignores invalid values.
+type uploadedBatch struct {
+	Name   string `json:"name"`
+	Points []struct {
+		Fields map[string]interface{} `json:"fields"`
+		Time   time.Time              `json:"time"`
+	} `json:"points"`
+}
+
+// writeBatchJSON validates newline delimited batches and writes them in the batch recording format.
+func writeBatchJSON(w io.Writer, data io.Reader) (timeRange, error) {
+	var tr timeRange
+	dec := json.NewDecoder(data)
+	n := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return tr, errors.Wrapf(err, "batch %d", n+1)
+		}
+		var ub uploadedBatch
+		if err := json.Unmarshal(raw, &ub); err != nil {
+			return tr, errors.Wrapf(err, "batch %d", n+1)
+		}
+		for i, p := range ub.Points {
+			if len(p.Fields) == 0 {
+				return tr, fmt.Errorf("batch %d: point %d has no fields", n+1, i+1)
+			}
+		}
+		b, err := edge.NewBufferedBatchMessageDecoder(bytes.NewReader(raw)).Decode()
+		if err != nil {
+			return tr, errors.Wrapf(err, "batch %d", n+1)
+		}
+		if err := kapacitor.WriteBatchForRecording(w, b); err != nil {
+			return tr, err
+		}
+		for _, p := range b.Points() {
+			tr.add(p.Time())
+		}
+		n++
+	}
+	if n == 0 {
+		return tr, errors.New("no batches")
+	}
+	return tr, nil
+}
@@ -0,0 +1,109 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uploadDBRP = kapacitor.DBRP{Database: "telegraf", RetentionPolicy: "autogen"}
+
+func TestWriteLineProtocol(t *testing.T) {
+	data := `
+# incident data
+cpu,host=a value=1 10
+cpu,host=b value=2i 5
+`
+	var buf bytes.Buffer
+	tr, err := writeLineProtocol(&buf, strings.NewReader(data), uploadDBRP, "s")
+	require.NoError(t, err)
+	assert.Equal(t, "telegraf\nautogen\ncpu,host=a value=1 10000000000\ntelegraf\nautogen\ncpu,host=b value=2i 5000000000\n", buf.String())
+	assert.Equal(t, time.Unix(5, 0).UTC(), tr.start)
+	assert.Equal(t, time.Unix(10, 0).UTC(), tr.stop)
+}
+
+func TestWriteLineProtocol_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := writeLineProtocol(&buf, strings.NewReader("cpu value=1 0\ncpu,host=a 0\n"), uploadDBRP, "")
+	assert.EqualError(t, err, "line 2: unable to parse 'cpu,host=a 0': invalid field format")
+
+	_, err = writeLineProtocol(&buf, strings.NewReader("\n# nothing\n"), uploadDBRP, "")
+	assert.EqualError(t, err, "no points")
+}
+
+const uploadCSV = `
+#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+,result,table,_start,_stop,_time,_value,_field,_measurement,host
+,_result,0,2021-04-22T16:00:00Z,2021-04-22T17:00:00Z,2021-04-22T16:10:00Z,45,usage_idle,cpu,a
+,_result,0,2021-04-22T16:00:00Z,2021-04-22T17:00:00Z,2021-04-22T16:20:00Z,47,usage_idle,cpu,a
+,_result,1,2021-04-22T16:00:00Z,2021-04-22T17:00:00Z,2021-04-22T16:05:00Z,20,usage_idle,cpu,b
+
+`
+
+func TestWriteCSVStream(t *testing.T) {
+	var buf bytes.Buffer
+	tr, err := writeCSVStream(&buf, strings.NewReader(uploadCSV), uploadDBRP)
+	require.NoError(t, err)
+	assert.Equal(t, `telegraf
+autogen
+cpu,host=a usage_idle=45 1619107800000000000
+telegraf
+autogen
+cpu,host=a usage_idle=47 1619108400000000000
+telegraf
+autogen
+cpu,host=b usage_idle=20 1619107500000000000
+`, buf.String())
+	assert.Equal(t, time.Date(2021, 4, 22, 16, 5, 0, 0, time.UTC), tr.start)
+	assert.Equal(t, time.Date(2021, 4, 22, 16, 20, 0, 0, time.UTC), tr.stop)
+}
+
+func TestWriteCSVBatch(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := writeCSVBatch(&buf, strings.NewReader(uploadCSV))
+	require.NoError(t, err)
+
+	dec := edge.NewBufferedBatchMessageDecoder(&buf)
+	var batches []edge.BufferedBatchMessage
+	for dec.More() {
+		b, err := dec.Decode()
+		require.NoError(t, err)
+		batches = append(batches, b)
+	}
+	require.Len(t, batches, 2)
+	assert.Equal(t, "cpu", batches[0].Name())
+	assert.Equal(t, models.Tags{"host": "a"}, batches[0].Tags())
+	assert.Equal(t, time.Date(2021, 4, 22, 16, 20, 0, 0, time.UTC), batches[0].Begin().Time())
+	require.Len(t, batches[0].Points(), 2)
+	assert.Equal(t, models.Fields{"usage_idle": 45.0}, batches[0].Points()[0].Fields())
+	assert.Equal(t, models.Tags{"host": "b"}, batches[1].Tags())
+}
+
+func TestWriteBatchJSON(t *testing.T) {
+	data := `{"name":"cpu","tmax":"2021-04-22T16:20:00Z","tags":{"host":"a"},"points":[{"fields":{"mean":45},"time":"2021-04-22T16:10:00Z"}]}
+{"name":"cpu","tmax":"2021-04-22T16:30:00Z","tags":{"host":"a"},"points":[{"fields":{"mean":47},"time":"2021-04-22T16:20:00Z"}]}
+`
+	var buf bytes.Buffer
+	tr, err := writeBatchJSON(&buf, strings.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 4, 22, 16, 10, 0, 0, time.UTC), tr.start)
+	assert.Equal(t, time.Date(2021, 4, 22, 16, 20, 0, 0, time.UTC), tr.stop)
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+
+	_, err = writeBatchJSON(&buf, strings.NewReader(`{"name":"cpu","points":[{"fields":{},"time":"2021-04-22T16:10:00Z"}]}`))
+	assert.EqualError(t, err, "batch 1: point 1 has no fields")
+
+	_, err = writeBatchJSON(&buf, strings.NewReader(`{"name":"cpu","points":[{"fields":{"mean":1},"time":"yesterday"}]}`))
+	assert.Error(t, err)
+
+	_, err = writeBatchJSON(&buf, strings.NewReader(`{"name":"cpu"`))
+	assert.Error(t, err)
+}
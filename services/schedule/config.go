@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Config defines a read-only schedule.
+type Config struct {
+	ID string `toml:"id"`
+	// Timezone is the IANA name of the timezone, defaults to UTC.
+	Timezone   string        `toml:"timezone"`
+	Weekly     []WeeklyRange `toml:"weekly"`
+	Exclusions []DateRange   `toml:"exclusion"`
+}
+
+func (c Config) Validate() error {
+	if !validID.MatchString(c.ID) {
+		return fmt.Errorf("id must contain only letters, numbers, '-', '.' and '_'. %q", c.ID)
+	}
+	_, err := compile(c.Timezone, c.Weekly, c.Exclusions)
+	return errors.Wrapf(err, "schedule %q", c.ID)
+}
+
+func (c Config) schedule() Schedule {
+	return Schedule{
+		ID:         c.ID,
+		Timezone:   c.Timezone,
+		Weekly:     c.Weekly,
+		Exclusions: c.Exclusions,
+	}
+}
+
+type Configs []Config
+
+func (cs Configs) Validate() error {
+	ids := make(map[string]bool, len(cs))
+	for _, c := range cs {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if ids[c.ID] {
+			return fmt.Errorf("duplicate id %q", c.ID)
+		}
+		ids[c.ID] = true
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"github.com/influxdata/kapacitor/services/storage"
+)
+
+var (
+	ErrScheduleExists   = errors.New("schedule already exists")
+	ErrNoScheduleExists = errors.New("no schedule exists")
+)
+
+// Data access object for Schedule data.
+type ScheduleDAO interface {
+	// Retrieve a schedule
+	Get(id string) (Schedule, error)
+
+	// Create a schedule.
+	// ErrScheduleExists is returned if a schedule already exists with the same ID.
+	Create(s Schedule) error
+
+	// Replace an existing schedule.
+	// ErrNoScheduleExists is returned if the schedule does not exist.
+	Replace(s Schedule) error
+
+	// Delete a schedule.
+	// It is not an error to delete an non-existent schedule.
+	Delete(id string) error
+
+	// List schedules matching a pattern.
+	// The pattern is shell/glob matching see https://golang.org/pkg/path/#Match
+	// Offset and limit are pagination bounds. Offset is inclusive starting at index 0.
+	// More results may exist while the number of returned items is equal to limit.
+	List(pattern string, offset, limit int) ([]Schedule, error)
+
+	// Rebuild fixes all indexes of the data.
+	Rebuild() error
+}
+
+//--------------------------------------------------------------------
+// The following structures are stored in a database via gob encoding.
+// Changes to the structures could break existing data.
+
+// Schedule is a named calendar of weekly time ranges and excluded dates.
+type Schedule struct {
+	ID string
+	// Timezone is the IANA name of the timezone, empty means UTC.
+	Timezone   string
+	Weekly     []WeeklyRange
+	Exclusions []DateRange
+	Created    time.Time
+	Modified   time.Time
+}
+
+// Validate reports whether the schedule's timezone, weekly ranges and exclusions are valid.
+func (s Schedule) Validate() error {
+	_, err := compile(s.Timezone, s.Weekly, s.Exclusions)
+	return err
+}
+
+type rawSchedule Schedule
+
+func (s Schedule) ObjectID() string {
+	return s.ID
+}
+
+func (s Schedule) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(rawSchedule(s))
+	return buf.Bytes(), err
+}
+
+func (s *Schedule) UnmarshalBinary(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode((*rawSchedule)(s))
+}
+
+// Key/Value based implementation of the ScheduleDAO.
+type scheduleKV struct {
+	store *storage.IndexedStore
+}
+
+func newScheduleKV(store storage.Interface) (*scheduleKV, error) {
+	c := storage.DefaultIndexedStoreConfig("schedules", func() storage.BinaryObject {
+		return new(Schedule)
+	})
+	istore, err := storage.NewIndexedStore(store, c)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduleKV{
+		store: istore,
+	}, nil
+}
+
+func (kv *scheduleKV) error(err error) error {
+	if err == storage.ErrNoObjectExists {
+		return ErrNoScheduleExists
+	} else if err == storage.ErrObjectExists {
+		return ErrScheduleExists
+	}
+	return err
+}
+
+func (kv *scheduleKV) Rebuild() error {
+	return kv.store.Rebuild()
+}
+
+func (kv *scheduleKV) Get(id string) (Schedule, error) {
+	o, err := kv.store.Get(id)
+	if err != nil {
+		return Schedule{}, kv.error(err)
+	}
+	s, ok := o.(*Schedule)
+	if !ok {
+		return Schedule{}, storage.ImpossibleTypeErr(s, o)
+	}
+	return *s, nil
+}
+
+func (kv *scheduleKV) Create(s Schedule) error {
+	return kv.error(kv.store.Create(&s))
+}
+
+func (kv *scheduleKV) Replace(s Schedule) error {
+	return kv.error(kv.store.Replace(&s))
+}
+
+func (kv *scheduleKV) Delete(id string) error {
+	return kv.store.Delete(id)
+}
+
+func (kv *scheduleKV) List(pattern string, offset, limit int) ([]Schedule, error) {
+	objects, err := kv.store.List(storage.DefaultIDIndex, pattern, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]Schedule, len(objects))
+	for i, o := range objects {
+		s, ok := o.(*Schedule)
+		if !ok {
+			return nil, storage.ImpossibleTypeErr(s, o)
+		}
+		schedules[i] = *s
+	}
+	return schedules, nil
+}
@@ -0,0 +1,11 @@
+/*
+Schedule provides named calendars that lambda expressions can consult via the inSchedule function.
+
+A schedule is a set of weekly time of day ranges, for example business hours,
+plus a set of excluded dates, for example public holidays,
+evaluated in the schedule's timezone.
+
+Schedules are either defined in the configuration file, in which case they are read-only,
+or persisted via the storage service. Both are exposed via the HTTP API.
+*/
+package schedule
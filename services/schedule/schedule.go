@@ -0,0 +1,187 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun":       time.Sunday,
+	"sunday":    time.Sunday,
+	"mon":       time.Monday,
+	"monday":    time.Monday,
+	"tue":       time.Tuesday,
+	"tuesday":   time.Tuesday,
+	"wed":       time.Wednesday,
+	"wednesday": time.Wednesday,
+	"thu":       time.Thursday,
+	"thursday":  time.Thursday,
+	"fri":       time.Friday,
+	"friday":    time.Friday,
+	"sat":       time.Saturday,
+	"saturday":  time.Saturday,
+}
+
+// WeeklyRange is a time of day range, in the format HH:MM, on the given days of the week.
+// The start is inclusive and the end is exclusive, an end of 24:00 means the end of the day.
+// No days means every day of the week.
+type WeeklyRange struct {
+	Days  []string `toml:"days"`
+	Start string   `toml:"start"`
+	End   string   `toml:"end"`
+}
+
+// DateRange is an inclusive range of dates, in the format YYYY-MM-DD.
+// An empty end means the range is the single start date.
+type DateRange struct {
+	Start string `toml:"start"`
+	End   string `toml:"end"`
+}
+
+// calendar is the compiled form of a schedule.
+type calendar struct {
+	loc        *time.Location
+	weekly     []weeklyRange
+	exclusions []dateRange
+}
+
+type weeklyRange struct {
+	days       [7]bool
+	start, end time.Duration
+}
+
+// dateRange is a range of dates encoded as YYYYMMDD so they can be compared as integers.
+type dateRange struct {
+	start, end int
+}
+
+func dateKey(year int, month time.Month, day int) int {
+	return year*10000 + int(month)*100 + day
+}
+
+// contains reports whether t is within the calendar.
+// A time is within the calendar if its date is not excluded and,
+// when weekly ranges are defined, it falls within any of them.
+func (c *calendar) contains(t time.Time) bool {
+	t = t.In(c.loc)
+	date := dateKey(t.Date())
+	for _, e := range c.exclusions {
+		if date >= e.start && date <= e.end {
+			return false
+		}
+	}
+	if len(c.weekly) == 0 {
+		return true
+	}
+	clock := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	day := t.Weekday()
+	for _, w := range c.weekly {
+		if w.days[day] && clock >= w.start && clock < w.end {
+			return true
+		}
+	}
+	return false
+}
+
+func compile(timezone string, weekly []WeeklyRange, exclusions []DateRange) (*calendar, error) {
+	c := &calendar{
+		loc:        time.UTC,
+		weekly:     make([]weeklyRange, len(weekly)),
+		exclusions: make([]dateRange, len(exclusions)),
+	}
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+		}
+		c.loc = loc
+	}
+	for i, w := range weekly {
+		r, err := compileWeekly(w)
+		if err != nil {
+			return nil, fmt.Errorf("weekly range %d: %v", i, err)
+		}
+		c.weekly[i] = r
+	}
+	for i, e := range exclusions {
+		r, err := compileDates(e)
+		if err != nil {
+			return nil, fmt.Errorf("exclusion %d: %v", i, err)
+		}
+		c.exclusions[i] = r
+	}
+	return c, nil
+}
+
+func compileWeekly(w WeeklyRange) (weeklyRange, error) {
+	r := weeklyRange{}
+	if len(w.Days) == 0 {
+		for i := range r.days {
+			r.days[i] = true
+		}
+	}
+	for _, d := range w.Days {
+		day, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return r, fmt.Errorf("invalid day %q", d)
+		}
+		r.days[day] = true
+	}
+	var err error
+	if r.start, err = parseClock(w.Start); err != nil {
+		return r, err
+	}
+	if r.end, err = parseClock(w.End); err != nil {
+		return r, err
+	}
+	if r.start >= r.end {
+		return r, fmt.Errorf("start %s must be before end %s, split ranges that span midnight", w.Start, w.End)
+	}
+	return r, nil
+}
+
+// parseClock parses a time of day in the format HH:MM, 24:00 is the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, must be HH:MM", s)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, must be HH:MM", s)
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, must be HH:MM", s)
+	}
+	if h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time of day %q, must be between 00:00 and 24:00", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func compileDates(e DateRange) (dateRange, error) {
+	start, err := time.Parse("2006-01-02", e.Start)
+	if err != nil {
+		return dateRange{}, fmt.Errorf("invalid start date %q, must be YYYY-MM-DD", e.Start)
+	}
+	end := start
+	if e.End != "" {
+		end, err = time.Parse("2006-01-02", e.End)
+		if err != nil {
+			return dateRange{}, fmt.Errorf("invalid end date %q, must be YYYY-MM-DD", e.End)
+		}
+	}
+	if end.Before(start) {
+		return dateRange{}, fmt.Errorf("end date %s is before start date %s", e.End, e.Start)
+	}
+	return dateRange{
+		start: dateKey(start.Date()),
+		end:   dateKey(end.Date()),
+	}, nil
+}
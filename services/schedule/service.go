@@ -0,0 +1,471 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/services/storage"
+	"github.com/pkg/errors"
+)
+
+const (
+	schedulesPath             = "/schedules"
+	schedulesPathAnchored     = "/schedules/"
+	schedulesBasePath         = httpd.BasePath + schedulesPath
+	schedulesBasePathAnchored = httpd.BasePath + schedulesPathAnchored
+)
+
+const (
+	// Public name of the schedules store
+	schedulesAPIName = "schedules"
+	// The storage namespace for all schedule data.
+	schedulesNamespace = "schedule_store"
+)
+
+var (
+	validID = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
+
+	ErrReadOnly = errors.New("schedule is defined in the configuration and is read-only")
+)
+
+type Diagnostic interface {
+	Error(msg string, err error, ctx ...keyvalue.T)
+}
+
+// Service stores schedules, exposes them via the HTTP API
+// and provides them to the inSchedule lambda function.
+type Service struct {
+	configs Configs
+
+	schedules ScheduleDAO
+
+	// mu protects calendars
+	mu sync.RWMutex
+	// calendars caches the compiled schedules by ID.
+	calendars map[string]*calendar
+	// configured holds the compiled schedules defined in the configuration by ID.
+	configured map[string]*calendar
+
+	routes []httpd.Route
+
+	StorageService interface {
+		Store(namespace string) storage.Interface
+		Register(name string, store storage.StoreActioner)
+	}
+	HTTPDService interface {
+		AddRoutes([]httpd.Route) error
+		DelRoutes([]httpd.Route)
+	}
+
+	diag Diagnostic
+}
+
+func NewService(c Configs, d Diagnostic) *Service {
+	return &Service{
+		configs:    c,
+		calendars:  make(map[string]*calendar),
+		configured: make(map[string]*calendar, len(c)),
+		diag:       d,
+	}
+}
+
+func (s *Service) Open() error {
+	for _, c := range s.configs {
+		cal, err := compile(c.Timezone, c.Weekly, c.Exclusions)
+		if err != nil {
+			return errors.Wrapf(err, "schedule %q", c.ID)
+		}
+		s.configured[c.ID] = cal
+	}
+
+	store := s.StorageService.Store(schedulesNamespace)
+	schedules, err := newScheduleKV(store)
+	if err != nil {
+		return err
+	}
+	s.schedules = schedules
+	s.StorageService.Register(schedulesAPIName, s.schedules)
+
+	s.routes = []httpd.Route{
+		{
+			Method:      "GET",
+			Pattern:     schedulesPath,
+			HandlerFunc: s.handleListSchedules,
+		},
+		{
+			Method:      "POST",
+			Pattern:     schedulesPath,
+			HandlerFunc: s.handleCreateSchedule,
+		},
+		{
+			Method:      "GET",
+			Pattern:     schedulesPathAnchored,
+			HandlerFunc: s.handleGetSchedule,
+		},
+		{
+			Method:      "PUT",
+			Pattern:     schedulesPathAnchored,
+			HandlerFunc: s.handleReplaceSchedule,
+		},
+		{
+			Method:      "DELETE",
+			Pattern:     schedulesPathAnchored,
+			HandlerFunc: s.handleDeleteSchedule,
+		},
+		{
+			// Satisfy CORS checks.
+			Method:      "OPTIONS",
+			Pattern:     schedulesPathAnchored,
+			HandlerFunc: httpd.ServeOptions,
+		},
+	}
+	if err := s.HTTPDService.AddRoutes(s.routes); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) Close() error {
+	if s.HTTPDService != nil {
+		s.HTTPDService.DelRoutes(s.routes)
+	}
+	return nil
+}
+
+// InSchedule reports whether t is within the named schedule.
+func (s *Service) InSchedule(id string, t time.Time) (bool, error) {
+	if cal, ok := s.configured[id]; ok {
+		return cal.contains(t), nil
+	}
+	s.mu.RLock()
+	cal, ok := s.calendars[id]
+	s.mu.RUnlock()
+	if !ok {
+		sch, err := s.schedules.Get(id)
+		if err != nil {
+			if err == ErrNoScheduleExists {
+				return false, fmt.Errorf("unknown schedule %q", id)
+			}
+			return false, err
+		}
+		cal, err = compile(sch.Timezone, sch.Weekly, sch.Exclusions)
+		if err != nil {
+			return false, errors.Wrapf(err, "schedule %q", id)
+		}
+		s.mu.Lock()
+		s.calendars[id] = cal
+		s.mu.Unlock()
+	}
+	return cal.contains(t), nil
+}
+
+// IsReadOnly reports whether the schedule is defined in the configuration.
+func (s *Service) IsReadOnly(id string) bool {
+	_, ok := s.configured[id]
+	return ok
+}
+
+// Schedule returns a schedule, either from the configuration or the store.
+func (s *Service) Schedule(id string) (Schedule, error) {
+	for _, c := range s.configs {
+		if c.ID == id {
+			return c.schedule(), nil
+		}
+	}
+	return s.schedules.Get(id)
+}
+
+// CreateSchedule validates and stores a new schedule.
+func (s *Service) CreateSchedule(sch Schedule) (Schedule, error) {
+	if !validID.MatchString(sch.ID) {
+		return Schedule{}, fmt.Errorf("schedule id must contain only letters, numbers, '-', '.' and '_'. %q", sch.ID)
+	}
+	if s.IsReadOnly(sch.ID) {
+		return Schedule{}, ErrReadOnly
+	}
+	if err := sch.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	now := time.Now().UTC()
+	sch.Created = now
+	sch.Modified = now
+	if err := s.schedules.Create(sch); err != nil {
+		return Schedule{}, err
+	}
+	s.invalidate(sch.ID)
+	return sch, nil
+}
+
+// ReplaceSchedule validates and replaces an existing schedule.
+func (s *Service) ReplaceSchedule(sch Schedule) (Schedule, error) {
+	if s.IsReadOnly(sch.ID) {
+		return Schedule{}, ErrReadOnly
+	}
+	if err := sch.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	existing, err := s.schedules.Get(sch.ID)
+	if err != nil {
+		return Schedule{}, err
+	}
+	sch.Created = existing.Created
+	sch.Modified = time.Now().UTC()
+	if err := s.schedules.Replace(sch); err != nil {
+		return Schedule{}, err
+	}
+	s.invalidate(sch.ID)
+	return sch, nil
+}
+
+// DeleteSchedule deletes a stored schedule.
+func (s *Service) DeleteSchedule(id string) error {
+	if s.IsReadOnly(id) {
+		return ErrReadOnly
+	}
+	if err := s.schedules.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate(id)
+	return nil
+}
+
+func (s *Service) invalidate(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.calendars, id)
+}
+
+// ListSchedules returns the configured and stored schedules matching pattern sorted by ID.
+func (s *Service) ListSchedules(pattern string, offset, limit int) ([]Schedule, error) {
+	stored, err := s.schedules.List("", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	var schedules []Schedule
+	match := func(id string) bool {
+		if pattern == "" {
+			return true
+		}
+		matched, _ := path.Match(pattern, id)
+		return matched
+	}
+	for _, c := range s.configs {
+		if match(c.ID) {
+			schedules = append(schedules, c.schedule())
+		}
+	}
+	for _, sch := range stored {
+		// Configured schedules take precedence over stored schedules.
+		if match(sch.ID) && !s.IsReadOnly(sch.ID) {
+			schedules = append(schedules, sch)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	if offset >= len(schedules) {
+		return nil, nil
+	}
+	schedules = schedules[offset:]
+	if limit >= 0 && limit < len(schedules) {
+		schedules = schedules[:limit]
+	}
+	return schedules, nil
+}
+
+func scheduleLink(id string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(schedulesBasePath, id)}
+}
+
+func (s *Service) convertSchedule(sch Schedule) client.Schedule {
+	weekly := make([]client.ScheduleWeeklyRange, len(sch.Weekly))
+	for i, w := range sch.Weekly {
+		weekly[i] = client.ScheduleWeeklyRange{
+			Days:  w.Days,
+			Start: w.Start,
+			End:   w.End,
+		}
+	}
+	exclusions := make([]client.ScheduleDateRange, len(sch.Exclusions))
+	for i, e := range sch.Exclusions {
+		exclusions[i] = client.ScheduleDateRange{
+			Start: e.Start,
+			End:   e.End,
+		}
+	}
+	timezone := sch.Timezone
+	if timezone == "" {
+		timezone = time.UTC.String()
+	}
+	return client.Schedule{
+		Link:       scheduleLink(sch.ID),
+		ID:         sch.ID,
+		Timezone:   timezone,
+		Weekly:     weekly,
+		Exclusions: exclusions,
+		ReadOnly:   s.IsReadOnly(sch.ID),
+		Created:    sch.Created,
+		Modified:   sch.Modified,
+	}
+}
+
+func convertOptions(o client.ScheduleOptions) Schedule {
+	weekly := make([]WeeklyRange, len(o.Weekly))
+	for i, w := range o.Weekly {
+		weekly[i] = WeeklyRange{
+			Days:  w.Days,
+			Start: w.Start,
+			End:   w.End,
+		}
+	}
+	exclusions := make([]DateRange, len(o.Exclusions))
+	for i, e := range o.Exclusions {
+		exclusions[i] = DateRange{
+			Start: e.Start,
+			End:   e.End,
+		}
+	}
+	return Schedule{
+		ID:         o.ID,
+		Timezone:   o.Timezone,
+		Weekly:     weekly,
+		Exclusions: exclusions,
+	}
+}
+
+func httpStatus(err error) int {
+	switch errors.Cause(err) {
+	case ErrNoScheduleExists:
+		return http.StatusNotFound
+	case ErrScheduleExists, ErrReadOnly:
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// parseListOptions returns the pattern, offset and limit of a list request.
+func parseListOptions(r *http.Request) (string, int, int, error) {
+	pattern := r.URL.Query().Get("pattern")
+	var err error
+	offset := int64(0)
+	offsetStr := r.URL.Query().Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid offset parameter %q must be an integer: %s", offsetStr, err)
+		}
+	}
+
+	limit := int64(100)
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid limit parameter %q must be an integer: %s", limitStr, err)
+		}
+	}
+	return pattern, int(offset), int(limit), nil
+}
+
+func (s *Service) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	pattern, offset, limit, err := parseListOptions(r)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	schedules, err := s.ListSchedules(pattern, offset, limit)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to list schedules with pattern %q: %s", pattern, err), true, http.StatusBadRequest)
+		return
+	}
+	list := client.Schedules{
+		Link:      client.Link{Relation: client.Self, Href: r.URL.String()},
+		Schedules: make([]client.Schedule, len(schedules)),
+	}
+	for i, sch := range schedules {
+		list.Schedules[i] = s.convertSchedule(sch)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(list, true))
+}
+
+func (s *Service) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	opt := client.ScheduleOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid schedule json: ", err), true, http.StatusBadRequest)
+		return
+	}
+	sch, err := s.CreateSchedule(convertOptions(opt))
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to create schedule %q: %v", opt.ID, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertSchedule(sch), true))
+}
+
+func scheduleID(r *http.Request) string {
+	return strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, schedulesBasePathAnchored), "/")
+}
+
+func (s *Service) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	id := scheduleID(r)
+	if id == "" {
+		httpd.HttpError(w, "must specify schedule id on path", true, http.StatusBadRequest)
+		return
+	}
+	sch, err := s.Schedule(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to get schedule %q: %v", id, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertSchedule(sch), true))
+}
+
+func (s *Service) handleReplaceSchedule(w http.ResponseWriter, r *http.Request) {
+	id := scheduleID(r)
+	if id == "" {
+		httpd.HttpError(w, "must specify schedule id on path", true, http.StatusBadRequest)
+		return
+	}
+	opt := client.ScheduleOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, fmt.Sprint("invalid schedule json: ", err), true, http.StatusBadRequest)
+		return
+	}
+	if opt.ID != "" && opt.ID != id {
+		httpd.HttpError(w, fmt.Sprintf("schedule id %q does not match the path %q, schedules cannot be renamed", opt.ID, id), true, http.StatusBadRequest)
+		return
+	}
+	opt.ID = id
+	sch, err := s.ReplaceSchedule(convertOptions(opt))
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to replace schedule %q: %v", id, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(s.convertSchedule(sch), true))
+}
+
+func (s *Service) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id := scheduleID(r)
+	if id == "" {
+		httpd.HttpError(w, "must specify schedule id on path", true, http.StatusBadRequest)
+		return
+	}
+	if err := s.DeleteSchedule(id); err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to delete schedule %q: %v", id, err), true, httpStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
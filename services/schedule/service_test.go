@@ -0,0 +1,254 @@
+package schedule_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httpd/httpdtest"
+	"github.com/influxdata/kapacitor/services/schedule"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+)
+
+var diagService *diagnostic.Service
+
+func init() {
+	diagService = diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	diagService.Open()
+}
+
+var businessHours = schedule.Config{
+	ID:       "business-hours",
+	Timezone: "Europe/Berlin",
+	Weekly: []schedule.WeeklyRange{{
+		Days:  []string{"mon", "tue", "wed", "thu", "fri"},
+		Start: "09:00",
+		End:   "17:00",
+	}},
+	Exclusions: []schedule.DateRange{
+		{Start: "2026-12-24", End: "2026-12-26"},
+		{Start: "2026-10-03"},
+	},
+}
+
+func OpenNewService(t *testing.T, configs schedule.Configs) (*schedule.Service, *client.Client) {
+	service := schedule.NewService(configs, diagService.NewScheduleHandler())
+	store := storagetest.New(t, diagService.NewStorageHandler())
+	t.Cleanup(func() { store.Close() })
+	service.StorageService = store
+	server := httpdtest.NewServer(testing.Verbose())
+	t.Cleanup(func() { server.Close() })
+	service.HTTPDService = server
+	if err := service.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { service.Close() })
+	cli, err := client.New(client.Config{URL: server.Server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, cli
+}
+
+func TestService_InSchedule(t *testing.T) {
+	service, _ := OpenNewService(t, schedule.Configs{businessHours})
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		time time.Time
+		exp  bool
+	}{
+		{name: "start of day", time: time.Date(2026, 10, 16, 9, 0, 0, 0, berlin), exp: true},
+		{name: "before start", time: time.Date(2026, 10, 16, 8, 59, 59, 0, berlin), exp: false},
+		{name: "end is exclusive", time: time.Date(2026, 10, 16, 17, 0, 0, 0, berlin), exp: false},
+		// 08:30 UTC is 10:30 in Berlin during summer time.
+		{name: "utc converted", time: time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC), exp: true},
+		{name: "weekend", time: time.Date(2026, 10, 17, 10, 0, 0, 0, berlin), exp: false},
+		{name: "single date exclusion", time: time.Date(2026, 10, 2, 10, 0, 0, 0, berlin), exp: true},
+		{name: "range exclusion", time: time.Date(2026, 12, 24, 10, 0, 0, 0, berlin), exp: false},
+		{name: "range exclusion inclusive end", time: time.Date(2026, 12, 25, 10, 0, 0, 0, berlin), exp: false},
+		{name: "after exclusion", time: time.Date(2026, 12, 28, 10, 0, 0, 0, berlin), exp: true},
+	}
+	for _, tc := range testCases {
+		got, err := service.InSchedule(businessHours.ID, tc.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.exp {
+			t.Errorf("%s: unexpected result for %v got %v exp %v", tc.name, tc.time, got, tc.exp)
+		}
+	}
+
+	if _, err := service.InSchedule("missing", time.Now()); err == nil {
+		t.Error("expected error for unknown schedule")
+	}
+}
+
+func TestService_CRUD(t *testing.T) {
+	service, cli := OpenNewService(t, schedule.Configs{businessHours})
+
+	created, err := cli.CreateSchedule(client.ScheduleOptions{
+		ID: "maintenance",
+		Weekly: []client.ScheduleWeeklyRange{{
+			Days:  []string{"Sunday"},
+			Start: "02:00",
+			End:   "04:00",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := "UTC", created.Timezone; exp != got {
+		t.Errorf("unexpected timezone got %s exp %s", got, exp)
+	}
+	if created.ReadOnly {
+		t.Error("stored schedule must not be read-only")
+	}
+
+	sunday := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	if in, err := service.InSchedule("maintenance", sunday); err != nil {
+		t.Fatal(err)
+	} else if !in {
+		t.Error("expected time to be within the maintenance schedule")
+	}
+
+	got, err := cli.Schedule(created.Link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(created, got) {
+		t.Errorf("unexpected schedule:\ngot\n%+v\nexp\n%+v", got, created)
+	}
+
+	// Replacing the schedule is reflected by InSchedule.
+	replaced, err := cli.ReplaceSchedule(created.Link, client.ScheduleOptions{
+		Weekly: []client.ScheduleWeeklyRange{{
+			Days:  []string{"sat"},
+			Start: "02:00",
+			End:   "04:00",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replaced.Created.Equal(created.Created) {
+		t.Errorf("unexpected created time got %v exp %v", replaced.Created, created.Created)
+	}
+	if in, err := service.InSchedule("maintenance", sunday); err != nil {
+		t.Fatal(err)
+	} else if in {
+		t.Error("expected time to no longer be within the replaced maintenance schedule")
+	}
+
+	list, err := cli.ListSchedules(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range list.Schedules {
+		ids = append(ids, s.ID)
+	}
+	if exp := []string{"business-hours", "maintenance"}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("unexpected schedules got %v exp %v", ids, exp)
+	}
+	if !list.Schedules[0].ReadOnly {
+		t.Error("configured schedule must be read-only")
+	}
+
+	if err := cli.DeleteSchedule(created.Link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Schedule(created.Link); err == nil {
+		t.Error("expected error getting deleted schedule")
+	}
+	if _, err := service.InSchedule("maintenance", sunday); err == nil {
+		t.Error("expected error for deleted schedule")
+	}
+}
+
+func TestService_ReadOnly(t *testing.T) {
+	_, cli := OpenNewService(t, schedule.Configs{businessHours})
+
+	link := cli.ScheduleLink(businessHours.ID)
+	if _, err := cli.CreateSchedule(client.ScheduleOptions{ID: businessHours.ID}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error on create, got %v", err)
+	}
+	if _, err := cli.ReplaceSchedule(link, client.ScheduleOptions{}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error on replace, got %v", err)
+	}
+	if err := cli.DeleteSchedule(link); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error on delete, got %v", err)
+	}
+}
+
+func TestService_Invalid(t *testing.T) {
+	_, cli := OpenNewService(t, nil)
+
+	testCases := []struct {
+		name string
+		opt  client.ScheduleOptions
+		err  string
+	}{
+		{
+			name: "bad id",
+			opt:  client.ScheduleOptions{ID: "a/b"},
+			err:  "schedule id must contain only",
+		},
+		{
+			name: "bad timezone",
+			opt:  client.ScheduleOptions{ID: "tz", Timezone: "Nowhere/Special"},
+			err:  `invalid timezone "Nowhere/Special"`,
+		},
+		{
+			name: "bad day",
+			opt: client.ScheduleOptions{ID: "day", Weekly: []client.ScheduleWeeklyRange{{
+				Days: []string{"funday"}, Start: "09:00", End: "17:00",
+			}}},
+			err: `invalid day "funday"`,
+		},
+		{
+			name: "spans midnight",
+			opt: client.ScheduleOptions{ID: "night", Weekly: []client.ScheduleWeeklyRange{{
+				Start: "22:00", End: "06:00",
+			}}},
+			err: "split ranges that span midnight",
+		},
+		{
+			name: "bad date",
+			opt: client.ScheduleOptions{ID: "date", Exclusions: []client.ScheduleDateRange{{
+				Start: "24/12/2026",
+			}}},
+			err: `invalid start date "24/12/2026"`,
+		},
+	}
+	for _, tc := range testCases {
+		_, err := cli.CreateSchedule(tc.opt)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: unexpected error got %v exp %q", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestConfigs_Validate(t *testing.T) {
+	if err := (schedule.Configs{businessHours}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (schedule.Configs{businessHours, businessHours}).Validate(); err == nil {
+		t.Error("expected error for duplicate ids")
+	}
+	allDay := schedule.Config{
+		ID:     "all-day",
+		Weekly: []schedule.WeeklyRange{{Start: "00:00", End: "24:00"}},
+	}
+	if err := allDay.Validate(); err != nil {
+		t.Errorf("unexpected error for all day range: %v", err)
+	}
+}
@@ -350,7 +350,11 @@ func (s *Service) Handler(c HandlerConfig, ctx ...keyvalue.T) alert.Handler {
 }
 
 func (h *handler) Handle(event alert.Event) {
+	h.TryHandle(event)
+}
 
+// TryHandle sends the event to Slack and returns an error if it could not be sent.
+func (h *handler) TryHandle(event alert.Event) error {
 	if err := h.s.Alert(
 		h.c.Workspace,
 		h.c.Channel,
@@ -360,5 +364,7 @@ func (h *handler) Handle(event alert.Event) {
 		event.State.Level,
 	); err != nil {
 		h.diag.Error("failed to send event", err)
+		return err
 	}
+	return nil
 }
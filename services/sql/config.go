@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/toml"
+)
+
+const (
+	// DefaultQueryTimeout is the maximum time a query may take if no query timeout is configured.
+	DefaultQueryTimeout = time.Minute
+)
+
+// Config defines a named connection to a database.
+type Config struct {
+	Enabled bool `toml:"enabled"`
+	// Name of the connection, used by the querySQL node to select the connection.
+	Name string `toml:"name"`
+	// Default marks the connection used by querySQL nodes that do not name a connection.
+	Default bool `toml:"default"`
+	// Driver is the name of the database/sql driver, one of sqlite3, postgres or mysql.
+	Driver string `toml:"driver"`
+	// DataSourceName is the driver specific connection string.
+	DataSourceName string `toml:"data-source-name"`
+	// MaxOpenConnections limits the number of open connections, zero means unlimited.
+	MaxOpenConnections int `toml:"max-open-connections"`
+	// ConnectionMaxLifetime is the maximum time a connection may be reused, zero means forever.
+	ConnectionMaxLifetime toml.Duration `toml:"connection-max-lifetime"`
+	// QueryTimeout is the maximum time a query may take, including reading its results.
+	QueryTimeout toml.Duration `toml:"query-timeout"`
+}
+
+func NewConfig() Config {
+	return Config{
+		QueryTimeout: toml.Duration(DefaultQueryTimeout),
+	}
+}
+
+func (c Config) Validate() error {
+	if c.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if !c.Enabled {
+		return nil
+	}
+	if !registeredDriver(c.Driver) {
+		return fmt.Errorf("connection %q: unknown driver %q, must be one of %v", c.Name, c.Driver, sql.Drivers())
+	}
+	if c.DataSourceName == "" {
+		return fmt.Errorf("connection %q: data-source-name must not be empty", c.Name)
+	}
+	if c.MaxOpenConnections < 0 {
+		return fmt.Errorf("connection %q: max-open-connections must not be negative", c.Name)
+	}
+	if c.ConnectionMaxLifetime < 0 {
+		return fmt.Errorf("connection %q: connection-max-lifetime must not be negative", c.Name)
+	}
+	if c.QueryTimeout < 0 {
+		return fmt.Errorf("connection %q: query-timeout must not be negative", c.Name)
+	}
+	return nil
+}
+
+func registeredDriver(name string) bool {
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+type Configs []Config
+
+func (cs Configs) Validate() error {
+	names := make(map[string]bool, len(cs))
+	defaults := 0
+	for _, c := range cs {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate name %q", c.Name)
+		}
+		names[c.Name] = true
+		if c.Enabled && c.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return errors.New("at most one enabled connection can be the default")
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+/*
+Sql provides named connections to relational databases for the querySQL batch node.
+
+Connections are defined in the configuration file in [[sql]] sections and use the
+database/sql drivers registered by this package: sqlite3, postgres and mysql.
+
+Queries may reference the time bounds of the batch via the {{start}} and {{stop}} placeholders,
+which are passed to the database as bind parameters using the driver's parameter syntax.
+*/
+package sql
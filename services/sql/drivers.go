@@ -0,0 +1,8 @@
+package sql
+
+import (
+	// Register the supported database/sql drivers.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -0,0 +1,145 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// placeholderPattern matches the {{start}} and {{stop}} placeholders of a query.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(start|stop)\s*\}\}`)
+
+type connection struct {
+	db      *sql.DB
+	driver  string
+	timeout time.Duration
+}
+
+// Service manages the configured database connections.
+type Service struct {
+	configs Configs
+
+	mu          sync.RWMutex
+	connections map[string]*connection
+	defaultName string
+}
+
+func NewService(c Configs) *Service {
+	return &Service{
+		configs: c,
+	}
+}
+
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connections = make(map[string]*connection, len(s.configs))
+	var enabled []string
+	for _, c := range s.configs {
+		if !c.Enabled {
+			continue
+		}
+		db, err := sql.Open(c.Driver, c.DataSourceName)
+		if err != nil {
+			s.closeConnections()
+			return errors.Wrapf(err, "failed to open connection %q", c.Name)
+		}
+		db.SetMaxOpenConns(c.MaxOpenConnections)
+		db.SetConnMaxLifetime(time.Duration(c.ConnectionMaxLifetime))
+		timeout := time.Duration(c.QueryTimeout)
+		if timeout == 0 {
+			timeout = DefaultQueryTimeout
+		}
+		s.connections[c.Name] = &connection{
+			db:      db,
+			driver:  c.Driver,
+			timeout: timeout,
+		}
+		enabled = append(enabled, c.Name)
+		if c.Default {
+			s.defaultName = c.Name
+		}
+	}
+	if s.defaultName == "" && len(enabled) == 1 {
+		s.defaultName = enabled[0]
+	}
+	return nil
+}
+
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConnections()
+	return nil
+}
+
+func (s *Service) closeConnections() {
+	for _, c := range s.connections {
+		c.db.Close()
+	}
+	s.connections = nil
+	s.defaultName = ""
+}
+
+// Query runs the query on the named connection, or the default connection if name is empty,
+// and calls f with the resulting rows.
+// The {{start}} and {{stop}} placeholders of the query are bound to the start and stop times.
+// The rows are closed once f returns, f must not retain them.
+func (s *Service) Query(name, query string, start, stop time.Time, f func(*sql.Rows) error) error {
+	s.mu.RLock()
+	if name == "" {
+		name = s.defaultName
+	}
+	c, ok := s.connections[name]
+	s.mu.RUnlock()
+	if !ok {
+		if name == "" {
+			return errors.New("no default SQL connection configured")
+		}
+		return fmt.Errorf("unknown SQL connection %q", name)
+	}
+
+	query, args := bindTimes(c.driver, query, start, stop)
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if err := f(rows); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// bindTimes replaces the time placeholders of the query with the bind parameters of the driver
+// and returns the times to bind in order.
+func bindTimes(driver, query string, start, stop time.Time) (string, []interface{}) {
+	var args []interface{}
+	query = placeholderPattern.ReplaceAllStringFunc(query, func(m string) string {
+		if placeholderPattern.FindStringSubmatch(m)[1] == "start" {
+			args = append(args, start.UTC())
+		} else {
+			args = append(args, stop.UTC())
+		}
+		return bindParameter(driver, len(args))
+	})
+	return query, args
+}
+
+// bindParameter returns the syntax of the nth bind parameter of the driver, starting at 1.
+func bindParameter(driver string, n int) string {
+	switch driver {
+	case "postgres":
+		return "$" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,132 @@
+package sql
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Query(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE orders (time TIMESTAMP, region TEXT, total REAL)`)
+	require.NoError(t, err)
+	for i, region := range []string{"east", "west", "east"} {
+		_, err := db.Exec(`INSERT INTO orders VALUES (?, ?, ?)`, time.Unix(int64(i*10), 0).UTC(), region, float64(i))
+		require.NoError(t, err)
+	}
+
+	c := NewConfig()
+	c.Enabled = true
+	c.Name = "orders"
+	c.Driver = "sqlite3"
+	c.DataSourceName = dsn
+	s := NewService(Configs{c})
+	require.NoError(t, s.Open())
+	defer s.Close()
+
+	var got []string
+	err = s.Query("", `SELECT region FROM orders WHERE time >= {{start}} AND time < {{ stop }} ORDER BY time`,
+		time.Unix(5, 0), time.Unix(30, 0),
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var region string
+				if err := rows.Scan(&region); err != nil {
+					return err
+				}
+				got = append(got, region)
+			}
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"west", "east"}, got)
+
+	err = s.Query("missing", `SELECT 1`, time.Time{}, time.Time{}, func(*sql.Rows) error { return nil })
+	assert.EqualError(t, err, `unknown SQL connection "missing"`)
+}
+
+func TestBindTimes(t *testing.T) {
+	start, stop := time.Unix(0, 0), time.Unix(60, 0)
+	query, args := bindTimes("postgres", `SELECT * FROM t WHERE a >= {{start}} AND a < {{stop}} OR b < {{stop}}`, start, stop)
+	assert.Equal(t, `SELECT * FROM t WHERE a >= $1 AND a < $2 OR b < $3`, query)
+	assert.Equal(t, []interface{}{start.UTC(), stop.UTC(), stop.UTC()}, args)
+
+	query, args = bindTimes("mysql", `SELECT * FROM t WHERE a < {{stop}}`, start, stop)
+	assert.Equal(t, `SELECT * FROM t WHERE a < ?`, query)
+	assert.Equal(t, []interface{}{stop.UTC()}, args)
+}
+
+func TestConfigs_Validate(t *testing.T) {
+	valid := func(name string) Config {
+		c := NewConfig()
+		c.Enabled = true
+		c.Name = name
+		c.Driver = "postgres"
+		c.DataSourceName = "postgres://localhost/db"
+		return c
+	}
+	tests := []struct {
+		name    string
+		configs func() Configs
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			configs: func() Configs { return Configs{valid("a"), valid("b")} },
+		},
+		{
+			name: "disabled without driver",
+			configs: func() Configs {
+				c := NewConfig()
+				c.Name = "a"
+				return Configs{c}
+			},
+		},
+		{
+			name: "unknown driver",
+			configs: func() Configs {
+				c := valid("a")
+				c.Driver = "oracle"
+				return Configs{c}
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing data source name",
+			configs: func() Configs {
+				c := valid("a")
+				c.DataSourceName = ""
+				return Configs{c}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate name",
+			configs: func() Configs { return Configs{valid("a"), valid("a")} },
+			wantErr: true,
+		},
+		{
+			name: "multiple defaults",
+			configs: func() Configs {
+				a, b := valid("a"), valid("b")
+				a.Default, b.Default = true, true
+				return Configs{a, b}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.configs().Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
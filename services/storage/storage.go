@@ -35,6 +35,9 @@ type WriteOperator interface {
 type ReadOnlyTx interface {
 	ReadOperator
 
+	// Cursor returns a cursor for that bucket, nil if the bucket doesn't exist.
+	Cursor() *bbolt.Cursor
+
 	// Bucket returns a ReadOnlyTx for that bucket. If the bucket doesn't exist Tx should be nil.
 	Bucket(name []byte) ReadOnlyTx
 
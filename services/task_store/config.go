@@ -1,24 +1,36 @@
 package task_store
 
 import (
+	"errors"
 	"time"
 
 	"github.com/influxdata/influxdb/toml"
 )
 
+const (
+	// DefaultShadowLogRetention is how long the recorded alerts and writes of shadow tasks are kept.
+	DefaultShadowLogRetention = toml.Duration(7 * 24 * time.Hour)
+)
+
 type Config struct {
 	// Deprecated, only needed to find old db and migrate
 	Dir              string        `toml:"dir"`
 	SnapshotInterval toml.Duration `toml:"snapshot-interval"`
+	// How long to keep the shadow log of tasks, zero keeps it until the task is deleted.
+	ShadowLogRetention toml.Duration `toml:"shadow-log-retention"`
 }
 
 func NewConfig() Config {
 	return Config{
-		Dir:              "./tasks",
-		SnapshotInterval: toml.Duration(time.Minute),
+		Dir:                "./tasks",
+		SnapshotInterval:   toml.Duration(time.Minute),
+		ShadowLogRetention: DefaultShadowLogRetention,
 	}
 }
 
 func (c Config) Validate() error {
+	if c.ShadowLogRetention < 0 {
+		return errors.New("shadow-log-retention must not be negative")
+	}
 	return nil
 }
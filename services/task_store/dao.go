@@ -17,6 +17,9 @@ var (
 	ErrTemplateExists   = errors.New("template already exists")
 	ErrNoTemplateExists = errors.New("no template exists")
 	ErrNoSnapshotExists = errors.New("no snapshot exists")
+	ErrLibraryExists    = errors.New("library already exists")
+	ErrNoLibraryExists  = errors.New("no library exists")
+	ErrNoRevisionsExist = errors.New("no revisions exist")
 )
 
 // Data access object for Task data.
@@ -90,6 +93,46 @@ type SnapshotDAO interface {
 	Exists(id string) (bool, error)
 }
 
+// Data access object for the revision logs of tasks and templates.
+type RevisionDAO interface {
+	// Retrieve the revisions of a task or template, ordered by revision number.
+	// ErrNoRevisionsExist is returned if no revisions have been recorded.
+	List(id string) ([]Revision, error)
+
+	// Append a revision to the log of a task or template.
+	// The revision number is assigned by the log and the appended revision is returned.
+	Append(id string, r Revision) (Revision, error)
+
+	// Move the log of a task or template to a new ID.
+	// It is not an error to rename a non-existent log.
+	Rename(oldID, newID string) error
+
+	// Delete the log of a task or template.
+	// It is not an error to delete a non-existent log.
+	Delete(id string) error
+}
+
+// Data access object for the shadow logs of tasks.
+type ShadowLogDAO interface {
+	// Append records to the log of a task.
+	Append(id string, records ...ShadowRecord) error
+
+	// Retrieve the records of a task with start <= time < stop, ordered by time.
+	// A zero start or stop leaves that end of the range unbounded.
+	List(id string, start, stop time.Time) ([]ShadowRecord, error)
+
+	// Delete the records older than t of all tasks.
+	DeleteBefore(t time.Time) error
+
+	// Rename moves the log of a task to a new ID, replacing any log of the new ID.
+	// It is not an error to rename a non-existent log.
+	Rename(oldID, newID string) error
+
+	// Delete the log of a task.
+	// It is not an error to delete a non-existent log.
+	Delete(id string) error
+}
+
 //--------------------------------------------------------------------
 // The following structures are stored in a database via gob encoding.
 // Changes to the structures could break existing data.
@@ -137,6 +180,8 @@ type Task struct {
 	Modified time.Time
 	// The time the task was last changed to status Enabled.
 	LastEnabled time.Time
+	// ID of the task this task shadows, empty if it is not a shadow task.
+	ShadowOf string
 }
 
 type rawTask Task
@@ -181,6 +226,90 @@ type Snapshot struct {
 	NodeSnapshots map[string][]byte
 }
 
+// A Revision is an immutable record of the definition of a task or template.
+type Revision struct {
+	// Revision number, starting at 1
+	Number int
+	// The task type (stream|batch).
+	Type TaskType
+	// The DBs and RPs of the task.
+	DBRPs []DBRP
+	// The TICKscript of the task or template.
+	TICKscript string
+	// ID of task template
+	TemplateID string
+	// Set of vars for a templated task
+	Vars map[string]Var
+	// Name of the user that made the change, empty if authentication is disabled.
+	Author string
+	// The time the revision was recorded
+	Created time.Time
+	// Number of the revision this revision rolled back to, zero if not a rollback.
+	RollbackOf int
+}
+
+// A RevisionLog contains all revisions of a task or template.
+type RevisionLog struct {
+	// ID of the task or template
+	ID        string
+	Revisions []Revision
+}
+
+type rawRevisionLog RevisionLog
+
+func (l RevisionLog) ObjectID() string {
+	return l.ID
+}
+
+func (l RevisionLog) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := enc.Encode(rawRevisionLog(l))
+	return buf.Bytes(), err
+}
+
+func (l *RevisionLog) UnmarshalBinary(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	return dec.Decode((*rawRevisionLog)(l))
+}
+
+// A ShadowRecord is an alert or write a shadow task recorded instead of sending it,
+// or an alert of a task while it was shadowed.
+type ShadowRecord struct {
+	// The kind of record, one of alert, influxdb, httppost, loopback or autoscale.
+	Type string
+	// Name of the node that recorded it
+	Node string
+	Time time.Time
+	// ID of the alert or of the scaled resource
+	ID      string
+	Level   string
+	Message string
+	// Destination of writes
+	Database        string
+	RetentionPolicy string
+	Measurement     string
+	Points          int
+	// URL of HTTP posts
+	URL string
+	// New replica count of autoscale events
+	Replicas int
+}
+
+type rawShadowRecord ShadowRecord
+
+func (r ShadowRecord) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := enc.Encode(rawShadowRecord(r))
+	return buf.Bytes(), err
+}
+
+func (r *ShadowRecord) UnmarshalBinary(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	return dec.Decode((*rawShadowRecord)(r))
+}
+
 // Key/Value store based implementation of the TaskDAO
 type taskKV struct {
 	store *storage.IndexedStore
@@ -656,3 +785,364 @@ func newVar(value interface{}, typ VarType, desc string) (Var, error) {
 	}
 	return g, nil
 }
+
+// Data access object for Library data.
+type LibraryDAO interface {
+	// Retrieve a library
+	Get(id string) (Library, error)
+
+	// Create a library.
+	// ErrLibraryExists is returned if a library already exists with the same ID.
+	Create(l Library) error
+
+	// Replace an existing library.
+	// ErrNoLibraryExists is returned if the library does not exist.
+	Replace(l Library) error
+
+	// Delete a library.
+	// It is not an error to delete an non-existent library.
+	Delete(id string) error
+
+	// List libraries matching a pattern.
+	// The pattern is shell/glob matching see https://golang.org/pkg/path/#Match
+	// Offset and limit are pagination bounds. Offset is inclusive starting at index 0.
+	// More results may exist while the number of returned items is equal to limit.
+	List(pattern string, offset, limit int) ([]Library, error)
+}
+
+// A Library is a TICKscript of definitions that tasks can import.
+type Library struct {
+	// Unique identifier for the library
+	ID string
+	// The TICKscript for the library.
+	TICKscript string
+	// Created Date
+	Created time.Time
+	// The time the library was last modified
+	Modified time.Time
+}
+
+type rawLibrary Library
+
+func (l Library) ObjectID() string {
+	return l.ID
+}
+
+func (l Library) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := enc.Encode(rawLibrary(l))
+	return buf.Bytes(), err
+}
+
+func (l *Library) UnmarshalBinary(data []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	return dec.Decode((*rawLibrary)(l))
+}
+
+// Key/Value store based implementation of the LibraryDAO
+type libraryKV struct {
+	store *storage.IndexedStore
+}
+
+func newLibraryKV(store storage.Interface) (*libraryKV, error) {
+	c := storage.DefaultIndexedStoreConfig("libraries", func() storage.BinaryObject {
+		return new(Library)
+	})
+	istore, err := storage.NewIndexedStore(store, c)
+	if err != nil {
+		return nil, err
+	}
+	return &libraryKV{
+		store: istore,
+	}, nil
+}
+
+func (kv *libraryKV) error(err error) error {
+	if err == storage.ErrObjectExists {
+		return ErrLibraryExists
+	} else if err == storage.ErrNoObjectExists {
+		return ErrNoLibraryExists
+	}
+	return err
+}
+
+func (kv *libraryKV) Get(id string) (Library, error) {
+	o, err := kv.store.Get(id)
+	if err != nil {
+		return Library{}, kv.error(err)
+	}
+	l, ok := o.(*Library)
+	if !ok {
+		return Library{}, fmt.Errorf("impossible error, object not a Library, got %T", o)
+	}
+	return *l, nil
+}
+
+func (kv *libraryKV) Create(l Library) error {
+	return kv.error(kv.store.Create(&l))
+}
+
+func (kv *libraryKV) Replace(l Library) error {
+	return kv.error(kv.store.Replace(&l))
+}
+
+func (kv *libraryKV) Delete(id string) error {
+	return kv.store.Delete(id)
+}
+
+func (kv *libraryKV) List(pattern string, offset, limit int) ([]Library, error) {
+	objects, err := kv.store.List(storage.DefaultIDIndex, pattern, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	libraries := make([]Library, len(objects))
+	for i, o := range objects {
+		l, ok := o.(*Library)
+		if !ok {
+			return nil, fmt.Errorf("impossible error, object not a Library, got %T", o)
+		}
+		libraries[i] = *l
+	}
+	return libraries, nil
+}
+
+// Key/Value store based implementation of the RevisionDAO
+type revisionKV struct {
+	store *storage.IndexedStore
+}
+
+func newRevisionKV(store storage.Interface, prefix string) (*revisionKV, error) {
+	c := storage.DefaultIndexedStoreConfig(prefix, func() storage.BinaryObject {
+		return new(RevisionLog)
+	})
+	istore, err := storage.NewIndexedStore(store, c)
+	if err != nil {
+		return nil, err
+	}
+	return &revisionKV{
+		store: istore,
+	}, nil
+}
+
+func (kv *revisionKV) error(err error) error {
+	if err == storage.ErrNoObjectExists {
+		return ErrNoRevisionsExist
+	}
+	return err
+}
+
+func (kv *revisionKV) getTx(tx storage.ReadOperator, id string) (*RevisionLog, error) {
+	o, err := kv.store.GetTx(tx, id)
+	if err != nil {
+		return nil, kv.error(err)
+	}
+	l, ok := o.(*RevisionLog)
+	if !ok {
+		return nil, fmt.Errorf("impossible error, object not a RevisionLog, got %T", o)
+	}
+	return l, nil
+}
+
+func (kv *revisionKV) List(id string) (revisions []Revision, err error) {
+	err = kv.store.Store().View(func(tx storage.ReadOnlyTx) error {
+		l, err := kv.getTx(tx, id)
+		if err != nil {
+			return err
+		}
+		revisions = l.Revisions
+		return nil
+	})
+	return
+}
+
+func (kv *revisionKV) Append(id string, r Revision) (Revision, error) {
+	err := kv.store.Store().Update(func(tx storage.Tx) error {
+		l, err := kv.getTx(tx, id)
+		if err == ErrNoRevisionsExist {
+			l = &RevisionLog{ID: id}
+		} else if err != nil {
+			return err
+		}
+		r.Number = len(l.Revisions) + 1
+		l.Revisions = append(l.Revisions, r)
+		return kv.store.PutTx(tx, l)
+	})
+	return r, err
+}
+
+func (kv *revisionKV) Rename(oldID, newID string) error {
+	return kv.store.Store().Update(func(tx storage.Tx) error {
+		l, err := kv.getTx(tx, oldID)
+		if err == ErrNoRevisionsExist {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := kv.store.DeleteTx(tx, oldID); err != nil {
+			return err
+		}
+		l.ID = newID
+		return kv.store.PutTx(tx, l)
+	})
+}
+
+func (kv *revisionKV) Delete(id string) error {
+	return kv.store.Delete(id)
+}
+
+// Key/Value store based implementation of the ShadowLogDAO.
+// Records are stored in a bucket per task keyed by their time and a sequence number,
+// since a task may record several alerts or writes at the same time.
+type shadowKV struct {
+	store storage.Interface
+}
+
+func newShadowKV(store storage.Interface) *shadowKV {
+	return &shadowKV{
+		store: store,
+	}
+}
+
+func shadowKey(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
+
+func (kv *shadowKV) Append(id string, records ...ShadowRecord) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		tx = tx.Bucket([]byte(id))
+		for _, r := range records {
+			data, err := r.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			prefix := shadowKey(r.Time)
+			existing, err := tx.List(prefix)
+			if err != nil {
+				return err
+			}
+			if err := tx.Put(fmt.Sprintf("%s%06d", prefix, len(existing)), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (kv *shadowKV) List(id string, start, stop time.Time) ([]ShadowRecord, error) {
+	var records []ShadowRecord
+	err := kv.store.View(func(tx storage.ReadOnlyTx) error {
+		cursor := tx.Bucket([]byte(id)).Cursor()
+		if cursor == nil {
+			return nil
+		}
+		var k, v []byte
+		if start.IsZero() {
+			k, v = cursor.First()
+		} else {
+			k, v = cursor.Seek([]byte(shadowKey(start)))
+		}
+		var end []byte
+		if !stop.IsZero() {
+			end = []byte(shadowKey(stop))
+		}
+		for ; k != nil && (end == nil || bytes.Compare(k, end) < 0); k, v = cursor.Next() {
+			var r ShadowRecord
+			if err := r.UnmarshalBinary(v); err != nil {
+				return fmt.Errorf("failed to read shadow record %s of task %q: %w", k, id, err)
+			}
+			records = append(records, r)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// shadowPurgeBatchSize is the maximum number of records deleted in a single transaction,
+// so that purging a large shadow log does not block recording shadow outputs.
+const shadowPurgeBatchSize = 1000
+
+func (kv *shadowKV) DeleteBefore(t time.Time) error {
+	return kv.deleteBefore(shadowKey(t), shadowPurgeBatchSize)
+}
+
+// deleteBefore deletes all records with keys before cutoff in transactions of at most batchSize records.
+func (kv *shadowKV) deleteBefore(cutoff string, batchSize int) error {
+	for {
+		deleted, err := kv.deleteBeforeBatch(cutoff, batchSize)
+		if err != nil {
+			return err
+		}
+		if deleted < batchSize {
+			return nil
+		}
+	}
+}
+
+// deleteBeforeBatch deletes up to batchSize records with keys before cutoff in a single transaction.
+// Logs without remaining records are deleted as well.
+func (kv *shadowKV) deleteBeforeBatch(cutoff string, batchSize int) (deleted int, err error) {
+	err = kv.store.Update(func(tx storage.Tx) error {
+		tasks, err := tx.List("")
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			taskTx := tx.Bucket([]byte(task.Key))
+			cursor := taskTx.Cursor()
+			if cursor == nil {
+				continue
+			}
+			// Records are sorted by time, collect the expired records before deleting them
+			// since deleting moves the cursor.
+			var expired []string
+			for k, _ := cursor.First(); k != nil && string(k) < cutoff && deleted+len(expired) < batchSize; k, _ = cursor.Next() {
+				expired = append(expired, string(k))
+			}
+			for _, key := range expired {
+				if err := taskTx.Delete(key); err != nil {
+					return err
+				}
+			}
+			deleted += len(expired)
+			if k, _ := taskTx.Cursor().First(); k == nil {
+				if err := tx.Delete(task.Key); err != nil {
+					return err
+				}
+			}
+			if deleted == batchSize {
+				return nil
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+func (kv *shadowKV) Rename(oldID, newID string) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		records, err := tx.Bucket([]byte(oldID)).List("")
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := tx.Delete(newID); err != nil {
+			return err
+		}
+		newTx := tx.Bucket([]byte(newID))
+		for _, r := range records {
+			if err := newTx.Put(r.Key, r.Value); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(oldID)
+	})
+}
+
+func (kv *shadowKV) Delete(id string) error {
+	return kv.store.Update(func(tx storage.Tx) error {
+		return tx.Delete(id)
+	})
+}
@@ -0,0 +1,342 @@
+package task_store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/services/storage"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+)
+
+func TestRevisionKV(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv, err := newRevisionKV(db.Store(taskNamespace), "task_revisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.List("t1"); err != ErrNoRevisionsExist {
+		t.Fatalf("unexpected error listing missing log: got %v exp %v", err, ErrNoRevisionsExist)
+	}
+
+	created := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	scripts := []string{"stream|from()", "stream|from().measurement('cpu')", "stream|from()"}
+	for i, script := range scripts {
+		r, err := kv.Append("t1", Revision{
+			TICKscript: script,
+			Author:     "bob",
+			Created:    created.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, exp := r.Number, i+1; got != exp {
+			t.Errorf("unexpected revision number: got %d exp %d", got, exp)
+		}
+	}
+
+	revisions, err := kv.List("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != len(scripts) {
+		t.Fatalf("unexpected number of revisions: got %d exp %d", len(revisions), len(scripts))
+	}
+	for i, r := range revisions {
+		exp := Revision{
+			Number:     i + 1,
+			TICKscript: scripts[i],
+			Author:     "bob",
+			Created:    created.Add(time.Duration(i) * time.Minute),
+		}
+		if !reflect.DeepEqual(r, exp) {
+			t.Errorf("unexpected revision %d:\ngot\n%+v\nexp\n%+v", i, r, exp)
+		}
+	}
+
+	if err := kv.Rename("t1", "t2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.List("t1"); err != ErrNoRevisionsExist {
+		t.Errorf("unexpected error listing renamed log: got %v exp %v", err, ErrNoRevisionsExist)
+	}
+	renamed, err := kv.List("t2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(renamed, revisions) {
+		t.Errorf("unexpected revisions after rename:\ngot\n%+v\nexp\n%+v", renamed, revisions)
+	}
+	if err := kv.Rename("missing", "other"); err != nil {
+		t.Errorf("unexpected error renaming missing log: %v", err)
+	}
+
+	if err := kv.Delete("t2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.List("t2"); err != ErrNoRevisionsExist {
+		t.Errorf("unexpected error listing deleted log: got %v exp %v", err, ErrNoRevisionsExist)
+	}
+	if err := kv.Delete("t2"); err != nil {
+		t.Errorf("unexpected error deleting missing log: %v", err)
+	}
+}
+
+func TestSameDefinition(t *testing.T) {
+	base := Revision{
+		Type:       StreamTask,
+		TICKscript: "stream|from()",
+		DBRPs:      []DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}},
+	}
+	templated := Revision{
+		TemplateID: "tmpl",
+		TICKscript: "var x = 1",
+		Vars:       map[string]Var{"x": {IntValue: 2, Type: VarInt}},
+	}
+	testCases := []struct {
+		name string
+		a, b Revision
+		exp  bool
+	}{
+		{
+			name: "equal",
+			a:    base,
+			b:    base,
+			exp:  true,
+		},
+		{
+			name: "author and time are ignored",
+			a:    base,
+			b:    Revision{Type: base.Type, TICKscript: base.TICKscript, DBRPs: base.DBRPs, Author: "bob", Created: time.Now()},
+			exp:  true,
+		},
+		{
+			name: "script changed",
+			a:    base,
+			b:    Revision{Type: base.Type, TICKscript: "stream|from().measurement('cpu')", DBRPs: base.DBRPs},
+			exp:  false,
+		},
+		{
+			name: "dbrps changed",
+			a:    base,
+			b:    Revision{Type: base.Type, TICKscript: base.TICKscript},
+			exp:  false,
+		},
+		{
+			name: "template script changed",
+			a:    templated,
+			b:    Revision{TemplateID: "tmpl", TICKscript: "var x = 3", Vars: templated.Vars},
+			exp:  true,
+		},
+		{
+			name: "template vars changed",
+			a:    templated,
+			b:    Revision{TemplateID: "tmpl", TICKscript: templated.TICKscript},
+			exp:  false,
+		},
+		{
+			name: "nil and empty vars",
+			a:    Revision{TICKscript: "stream|from()"},
+			b:    Revision{TICKscript: "stream|from()", Vars: map[string]Var{}},
+			exp:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sameDefinition(tc.a, tc.b); got != tc.exp {
+				t.Errorf("unexpected result: got %v exp %v", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestShadowKV(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := newShadowKV(db.Store(shadowLogNamespace))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []ShadowRecord{
+		{Type: "alert", Node: "alert2", Time: start, ID: "cpu:host=a", Level: "CRITICAL"},
+		// Records at the same time must not overwrite each other.
+		{Type: "alert", Node: "alert2", Time: start, ID: "cpu:host=b", Level: "WARNING"},
+		{Type: "influxdb", Node: "influxdb_out3", Time: start.Add(time.Minute), Database: "db", RetentionPolicy: "rp", Measurement: "cpu", Points: 2},
+		{Type: "alert", Node: "alert2", Time: start.Add(2 * time.Minute), ID: "cpu:host=a", Level: "OK"},
+	}
+	for _, r := range records {
+		if err := kv.Append("t1", r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := kv.Append("t2", records[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := kv.List("t1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("unexpected records:\ngot\n%+v\nexp\n%+v", got, records)
+	}
+	got, err = kv.List("t1", start.Add(time.Minute), start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records[2:3]) {
+		t.Errorf("unexpected records in range:\ngot\n%+v\nexp\n%+v", got, records[2:3])
+	}
+	if got, err := kv.List("missing", time.Time{}, time.Time{}); err != nil || len(got) != 0 {
+		t.Errorf("unexpected result listing missing log: %v %v", got, err)
+	}
+
+	// Deleting old records removes the logs left empty.
+	if err := kv.DeleteBefore(start.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	got, err = kv.List("t1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records[2:]) {
+		t.Errorf("unexpected records after purge:\ngot\n%+v\nexp\n%+v", got, records[2:])
+	}
+	if got, err := kv.List("t2", time.Time{}, time.Time{}); err != nil || len(got) != 0 {
+		t.Errorf("unexpected records of purged log: %v %v", got, err)
+	}
+
+	if err := kv.Rename("t1", "t3"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = kv.List("t3", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records[2:]) {
+		t.Errorf("unexpected records after rename:\ngot\n%+v\nexp\n%+v", got, records[2:])
+	}
+	if got, err := kv.List("t1", time.Time{}, time.Time{}); err != nil || len(got) != 0 {
+		t.Errorf("unexpected records of renamed log: %v %v", got, err)
+	}
+	if err := kv.Rename("missing", "t3"); err != nil {
+		t.Errorf("unexpected error renaming missing log: %v", err)
+	}
+
+	if err := kv.Delete("t3"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := kv.List("t3", time.Time{}, time.Time{}); err != nil || len(got) != 0 {
+		t.Errorf("unexpected records of deleted log: %v %v", got, err)
+	}
+	if err := kv.Delete("t3"); err != nil {
+		t.Errorf("unexpected error deleting missing log: %v", err)
+	}
+}
+
+func TestShadowKV_ListRange(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := newShadowKV(db.Store(shadowLogNamespace))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []ShadowRecord
+	for i := 0; i < 5; i++ {
+		for _, host := range []string{"a", "b"} {
+			records = append(records, ShadowRecord{Type: "alert", Node: "alert2", Time: start.Add(time.Duration(i) * time.Minute), ID: "cpu:host=" + host})
+		}
+	}
+	// Records appended in one call are numbered like records appended one at a time.
+	if err := kv.Append("t1", records...); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		start, stop time.Time
+		exp         []ShadowRecord
+	}{
+		{name: "all", exp: records},
+		{name: "start", start: start.Add(3 * time.Minute), exp: records[6:]},
+		{name: "stop", stop: start.Add(time.Minute), exp: records[:2]},
+		{name: "start and stop", start: start.Add(time.Minute), stop: start.Add(3 * time.Minute), exp: records[2:6]},
+		{name: "between records", start: start.Add(30 * time.Second), stop: start.Add(90 * time.Second), exp: records[2:4]},
+		{name: "after last record", start: start.Add(time.Hour)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := kv.List("t1", tc.start, tc.stop)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("unexpected records:\ngot\n%+v\nexp\n%+v", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestShadowKV_DeleteBeforeBatches(t *testing.T) {
+	db, err := storagetest.NewBolt(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kv := newShadowKV(db.Store(shadowLogNamespace))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []string{"t1", "t2", "t3"} {
+		for i := 0; i < 4; i++ {
+			if err := kv.Append(id, ShadowRecord{Type: "alert", Time: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Delete the first three records of every task, two records per transaction.
+	cutoff := shadowKey(start.Add(3 * time.Minute))
+	deleted, err := kv.deleteBeforeBatch(cutoff, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("unexpected number of records deleted by one batch: got %d exp 2", deleted)
+	}
+	if err := kv.deleteBefore(cutoff, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"t1", "t2", "t3"} {
+		records, err := kv.List(id, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || !records[0].Time.Equal(start.Add(3*time.Minute)) {
+			t.Errorf("unexpected records of %s after purge: %+v", id, records)
+		}
+	}
+
+	// Deleting everything also deletes the task buckets.
+	if err := kv.deleteBefore(shadowKey(start.Add(time.Hour)), 2); err != nil {
+		t.Fatal(err)
+	}
+	var tasks []*storage.KeyValue
+	if err := db.Store(shadowLogNamespace).View(func(tx storage.ReadOnlyTx) error {
+		tasks, err = tx.List("")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("unexpected task logs after purging all records: %d", len(tasks))
+	}
+}
@@ -0,0 +1,521 @@
+package task_store
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/kapacitor/auth"
+	"github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	revisionsPath = "revisions"
+	diffPath      = "diff"
+	rollbackPath  = "rollback"
+)
+
+// revisionAuthor returns the name of the user making a change.
+// The name is empty if authentication is disabled.
+func revisionAuthor(user auth.User) string {
+	if user.Name() == auth.AdminUser.Name() {
+		return ""
+	}
+	return user.Name()
+}
+
+func taskRevision(t Task, author string, created time.Time) Revision {
+	return Revision{
+		Type:       t.Type,
+		DBRPs:      t.DBRPs,
+		TICKscript: t.TICKscript,
+		TemplateID: t.TemplateID,
+		Vars:       t.Vars,
+		Author:     author,
+		Created:    created,
+	}
+}
+
+func templateRevision(t Template, author string, created time.Time) Revision {
+	return Revision{
+		Type:       t.Type,
+		TICKscript: t.TICKscript,
+		Author:     author,
+		Created:    created,
+	}
+}
+
+// sameDefinition reports whether two revisions define the same task or template.
+// The TICKscript of a templated task follows its template and is not compared.
+func sameDefinition(a, b Revision) bool {
+	if a.TemplateID != b.TemplateID {
+		return false
+	}
+	if a.TemplateID == "" && (a.Type != b.Type || a.TICKscript != b.TICKscript) {
+		return false
+	}
+	if len(a.Vars) != 0 || len(b.Vars) != 0 {
+		if !reflect.DeepEqual(a.Vars, b.Vars) {
+			return false
+		}
+	}
+	if len(a.DBRPs) != 0 || len(b.DBRPs) != 0 {
+		if !reflect.DeepEqual(a.DBRPs, b.DBRPs) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordRevision appends a revision to the log of a task or template unless the definition did not change.
+// Tasks and templates defined before revisions were recorded have their previous definition recorded first.
+// Failures are logged since the change itself has already been saved.
+func (ts *Service) recordRevision(dao RevisionDAO, id string, previous *Revision, r Revision) {
+	revisions, err := dao.List(id)
+	if err != nil && err != ErrNoRevisionsExist {
+		ts.diag.Error("failed to record revision", err, keyvalue.KV("id", id))
+		return
+	}
+	if len(revisions) == 0 && previous != nil {
+		if _, err := dao.Append(id, *previous); err != nil {
+			ts.diag.Error("failed to record revision", err, keyvalue.KV("id", id))
+			return
+		}
+		revisions = append(revisions, *previous)
+	}
+	if n := len(revisions); n > 0 && r.RollbackOf == 0 && sameDefinition(revisions[n-1], r) {
+		return
+	}
+	if _, err := dao.Append(id, r); err != nil {
+		ts.diag.Error("failed to record revision", err, keyvalue.KV("id", id))
+	}
+}
+
+// findRevision returns the revision with number n.
+func findRevision(revisions []Revision, n int) (Revision, bool) {
+	if n < 1 || n > len(revisions) {
+		return Revision{}, false
+	}
+	return revisions[n-1], true
+}
+
+// splitSubPath splits a path of the form <base><id>/<sub> into the id and sub path.
+func splitSubPath(p, base string) (string, string, bool) {
+	return strings.Cut(strings.TrimPrefix(p, base), "/")
+}
+
+func (ts *Service) convertRevision(r Revision, link client.Link) (client.Revision, error) {
+	var typ client.TaskType
+	switch r.Type {
+	case StreamTask:
+		typ = client.StreamTask
+	case BatchTask:
+		typ = client.BatchTask
+	default:
+		return client.Revision{}, fmt.Errorf("invalid task type %v", r.Type)
+	}
+	var dbrps []client.DBRP
+	for _, dbrp := range r.DBRPs {
+		dbrps = append(dbrps, client.DBRP{
+			Database:        dbrp.Database,
+			RetentionPolicy: dbrp.RetentionPolicy,
+		})
+	}
+	var vars client.Vars
+	if len(r.Vars) > 0 {
+		var err error
+		vars, err = ts.convertToClientVars(r.Vars)
+		if err != nil {
+			return client.Revision{}, err
+		}
+	}
+	return client.Revision{
+		Link:       client.Link{Relation: client.Self, Href: path.Join(link.Href, revisionsPath, strconv.Itoa(r.Number))},
+		Number:     r.Number,
+		Type:       typ,
+		TemplateID: r.TemplateID,
+		DBRPs:      dbrps,
+		TICKscript: r.TICKscript,
+		Vars:       vars,
+		Author:     r.Author,
+		Created:    r.Created,
+		RollbackOf: r.RollbackOf,
+	}, nil
+}
+
+// handleRevisions serves the revisions sub paths of a task or template.
+func (ts *Service) handleRevisions(w http.ResponseWriter, r *http.Request, dao RevisionDAO, id string, link client.Link, sub string) {
+	revisions, err := dao.List(id)
+	if err != nil && err != ErrNoRevisionsExist {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	parts := strings.Split(sub, "/")
+	if parts[0] != revisionsPath || len(parts) > 2 {
+		httpd.HttpError(w, fmt.Sprintf("unknown path %q", r.URL.Path), true, http.StatusNotFound)
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		list := client.Revisions{
+			Link:      client.Link{Relation: client.Self, Href: path.Join(link.Href, revisionsPath)},
+			Revisions: make([]client.Revision, len(revisions)),
+		}
+		for i, rev := range revisions {
+			list.Revisions[i], err = ts.convertRevision(rev, link)
+			if err != nil {
+				httpd.HttpError(w, fmt.Sprintf("invalid revision stored in db: %s", err), true, http.StatusInternalServerError)
+				return
+			}
+		}
+		w.Write(httpd.MarshalJSON(list, true))
+	case parts[1] == diffPath:
+		ts.handleDiffRevisions(w, r, revisions)
+	default:
+		n, err := strconv.Atoi(parts[1])
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid revision %q", parts[1]), true, http.StatusBadRequest)
+			return
+		}
+		rev, ok := findRevision(revisions, n)
+		if !ok {
+			httpd.HttpError(w, fmt.Sprintf("no revision %d exists for %s", n, id), true, http.StatusNotFound)
+			return
+		}
+		cr, err := ts.convertRevision(rev, link)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid revision stored in db: %s", err), true, http.StatusInternalServerError)
+			return
+		}
+		w.Write(httpd.MarshalJSON(cr, true))
+	}
+}
+
+func (ts *Service) handleDiffRevisions(w http.ResponseWriter, r *http.Request, revisions []Revision) {
+	parse := func(name string, def int) (int, bool) {
+		str := r.URL.Query().Get(name)
+		if str == "" {
+			return def, true
+		}
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid %s parameter %q must be a revision number", name, str), true, http.StatusBadRequest)
+			return 0, false
+		}
+		return n, true
+	}
+	to, ok := parse("to", len(revisions))
+	if !ok {
+		return
+	}
+	from, ok := parse("from", to-1)
+	if !ok {
+		return
+	}
+	fromRev, ok := findRevision(revisions, from)
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("no revision %d exists", from), true, http.StatusNotFound)
+		return
+	}
+	toRev, ok := findRevision(revisions, to)
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("no revision %d exists", to), true, http.StatusNotFound)
+		return
+	}
+	diff, err := ts.diffRevisions(fromRev, toRev)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	diff.Link = client.Link{Relation: client.Self, Href: r.URL.String()}
+	w.Write(httpd.MarshalJSON(diff, true))
+}
+
+func (ts *Service) diffRevisions(from, to Revision) (client.RevisionDiff, error) {
+	diff := client.RevisionDiff{
+		From: from.Number,
+		To:   to.Number,
+	}
+	var err error
+	if diff.TICKscript, err = unifiedDiff(from.TICKscript, to.TICKscript, from.Number, to.Number); err != nil {
+		return diff, err
+	}
+	if diff.DBRPs, err = unifiedDiff(dbrpLines(from.DBRPs), dbrpLines(to.DBRPs), from.Number, to.Number); err != nil {
+		return diff, err
+	}
+	fromVars, err := ts.varLines(from.Vars)
+	if err != nil {
+		return diff, err
+	}
+	toVars, err := ts.varLines(to.Vars)
+	if err != nil {
+		return diff, err
+	}
+	diff.Vars, err = unifiedDiff(fromVars, toVars, from.Number, to.Number)
+	return diff, err
+}
+
+// unifiedDiff returns the unified diff between a and b, or an empty string if they are equal.
+func unifiedDiff(a, b string, from, to int) (string, error) {
+	if a == b {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fmt.Sprintf("revision %d", from),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  3,
+	})
+}
+
+func dbrpLines(dbrps []DBRP) string {
+	var b strings.Builder
+	for _, dbrp := range dbrps {
+		fmt.Fprintf(&b, "%q.%q\n", dbrp.Database, dbrp.RetentionPolicy)
+	}
+	return b.String()
+}
+
+// varLines returns one line per var sorted by name.
+func (ts *Service) varLines(vars map[string]Var) (string, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		v, err := ts.convertToClientVar(vars[name])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s %v = %v\n", name, v.Type, v.Value)
+	}
+	return b.String(), nil
+}
+
+// associateTemplate moves the association of the task from one template to another,
+// either may be empty.
+func (ts *Service) associateTemplate(taskID, from, to string) error {
+	if from == to {
+		return nil
+	}
+	if from != "" {
+		if err := ts.templates.DisassociateTask(from, taskID); err != nil {
+			return fmt.Errorf("failed to disassociate task with template: %s", err)
+		}
+	}
+	if to != "" {
+		if err := ts.templates.AssociateTask(to, taskID); err != nil {
+			if from != "" {
+				if err := ts.templates.AssociateTask(from, taskID); err != nil {
+					ts.diag.Error("failed to restore template association", err, keyvalue.KV("task", taskID))
+				}
+			}
+			return fmt.Errorf("failed to associate task with template: %s", err)
+		}
+	}
+	return nil
+}
+
+// lookupRollbackRevision decodes a rollback request and finds the requested revision.
+// An error response has been written if ok is false.
+func lookupRollbackRevision(w http.ResponseWriter, r *http.Request, dao RevisionDAO, id string) (rev Revision, ok bool) {
+	opt := client.RollbackOptions{}
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, "invalid JSON", true, http.StatusBadRequest)
+		return Revision{}, false
+	}
+	revisions, err := dao.List(id)
+	if err != nil && err != ErrNoRevisionsExist {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return Revision{}, false
+	}
+	rev, ok = findRevision(revisions, opt.Revision)
+	if !ok {
+		httpd.HttpError(w, fmt.Sprintf("no revision %d exists for %s", opt.Revision, id), true, http.StatusNotFound)
+		return Revision{}, false
+	}
+	return rev, true
+}
+
+func (ts *Service) handleRollbackTask(w http.ResponseWriter, r *http.Request, user auth.User) {
+	id, sub, ok := splitSubPath(r.URL.Path, tasksBasePathAnchored)
+	if !ok || sub != rollbackPath {
+		httpd.HttpError(w, fmt.Sprintf("unknown path %q", r.URL.Path), true, http.StatusNotFound)
+		return
+	}
+	original, err := ts.tasks.Get(id)
+	if err != nil {
+		httpd.HttpError(w, "task does not exist, cannot roll back", true, http.StatusNotFound)
+		return
+	}
+	rev, ok := lookupRollbackRevision(w, r, ts.taskRevisions, id)
+	if !ok {
+		return
+	}
+
+	updated := original
+	updated.Type = rev.Type
+	updated.DBRPs = rev.DBRPs
+	updated.TICKscript = rev.TICKscript
+	updated.TemplateID = rev.TemplateID
+	updated.Vars = rev.Vars
+	if rev.TemplateID != "" {
+		// A templated task always uses the current definition of its template.
+		template, err := ts.templates.Get(rev.TemplateID)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("cannot roll back to revision %d: unknown template %s: err: %s", rev.Number, rev.TemplateID, err), true, http.StatusBadRequest)
+			return
+		}
+		updated.Type = template.Type
+		updated.TICKscript = template.TICKscript
+	}
+	pn, err := newProgramNodeFromTickscript(updated.TICKscript)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	if dbrps := dbrpsFromProgram(pn); len(dbrps) > 0 {
+		updated.DBRPs = []DBRP{}
+		for _, dbrp := range dbrps {
+			updated.DBRPs = append(updated.DBRPs, DBRP{
+				Database:        dbrp.Database,
+				RetentionPolicy: dbrp.RetentionPolicy,
+			})
+		}
+	}
+
+	// Validate task
+	if _, err := ts.newKapacitorTask(updated); err != nil {
+		httpd.HttpError(w, "invalid TICKscript: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	if err := ts.associateTemplate(id, original.TemplateID, updated.TemplateID); err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	// undo restores the original definition and template association of the task.
+	undo := func() {
+		if err := ts.tasks.Replace(original); err != nil {
+			ts.diag.Error("failed to restore task definition after failed rollback", err, keyvalue.KV("task", id))
+		}
+		if err := ts.associateTemplate(id, updated.TemplateID, original.TemplateID); err != nil {
+			ts.diag.Error("failed to restore template association after failed rollback", err, keyvalue.KV("task", id))
+		}
+	}
+
+	now := time.Now()
+	updated.Modified = now
+	if err := ts.tasks.Replace(updated); err != nil {
+		undo()
+		httpd.HttpError(w, fmt.Sprintf("failed to replace task definition: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+
+	if original.Status == Enabled {
+		ts.stopTask(id)
+	}
+	// The snapshot holds the state of the definition being replaced,
+	// it must not be restored into the rolled back definition.
+	if err := ts.snapshots.Delete(id); err != nil {
+		undo()
+		if original.Status == Enabled {
+			if err := ts.startTask(original); err != nil {
+				ts.diag.Error("failed to restart task after failed rollback", err, keyvalue.KV("task", id))
+			}
+		}
+		httpd.HttpError(w, fmt.Sprintf("failed to delete task snapshot: %s", err), true, http.StatusInternalServerError)
+		return
+	}
+	if updated.Status == Enabled {
+		if err := ts.startTask(updated); err != nil {
+			httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	recorded := taskRevision(updated, revisionAuthor(user), now)
+	recorded.RollbackOf = rev.Number
+	ts.recordRevision(ts.taskRevisions, id, nil, recorded)
+
+	t, err := ts.convertTask(updated, "formatted", "attributes", ts.TaskMasterLookup.Main())
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(t, true))
+}
+
+func (ts *Service) handleRollbackTemplate(w http.ResponseWriter, r *http.Request, user auth.User) {
+	id, sub, ok := splitSubPath(r.URL.Path, templatesBasePathAnchored)
+	if !ok || sub != rollbackPath {
+		httpd.HttpError(w, fmt.Sprintf("unknown path %q", r.URL.Path), true, http.StatusNotFound)
+		return
+	}
+	original, err := ts.templates.Get(id)
+	if err != nil {
+		httpd.HttpError(w, "template does not exist, cannot roll back", true, http.StatusNotFound)
+		return
+	}
+	rev, ok := lookupRollbackRevision(w, r, ts.templateRevisions, id)
+	if !ok {
+		return
+	}
+
+	updated := original
+	updated.Type = rev.Type
+	updated.TICKscript = rev.TICKscript
+
+	// Validate template
+	if _, err := ts.templateTask(updated); err != nil {
+		httpd.HttpError(w, "invalid TICKscript: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	taskIds, err := ts.templates.ListAssociatedTasks(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("error getting associated tasks for template %s: %s", id, err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	updated.Modified = now
+	if err := ts.templates.Replace(updated); err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to replace template definition: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	// The associated tasks are rolled back to the original template if any of them fails to update,
+	// so the template is restored as well.
+	if err := ts.updateAllAssociatedTasks(original, updated, taskIds, true); err != nil {
+		if err := ts.templates.Replace(original); err != nil {
+			ts.diag.Error("failed to restore template definition after failed rollback", err, keyvalue.KV("template", id))
+		}
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+
+	recorded := templateRevision(updated, revisionAuthor(user), now)
+	recorded.RollbackOf = rev.Number
+	ts.recordRevision(ts.templateRevisions, id, nil, recorded)
+
+	t, err := ts.convertTemplate(updated, "formatted")
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(t, true))
+}
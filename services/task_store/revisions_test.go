@@ -0,0 +1,328 @@
+package task_store
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/auth"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/server/vars"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/services/storage/storagetest"
+)
+
+type httpdService struct{}
+
+func (httpdService) AddRoutes([]httpd.Route) error { return nil }
+func (httpdService) DelRoutes([]httpd.Route)       {}
+
+type deadman struct{}
+
+func (deadman) Interval() time.Duration { return 10 * time.Second }
+func (deadman) Threshold() float64      { return 0 }
+func (deadman) Id() string              { return "" }
+func (deadman) Message() string         { return "" }
+func (deadman) Global() bool            { return false }
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	diag := diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	if err := diag.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { diag.Close() })
+
+	ts := NewService(Config{}, diag.NewTaskStoreHandler())
+	ts.StorageService = storagetest.New(t, diag.NewStorageHandler())
+	ts.HTTPDService = httpdService{}
+
+	tm := kapacitor.NewTaskMaster(kapacitor.MainTaskMaster, vars.Info, diag.NewKapacitorHandler())
+	tm.DeadmanService = deadman{}
+	tm.TaskStore = ts
+	if err := tm.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tm.Close() })
+	lookup := kapacitor.NewTaskMasterLookup()
+	lookup.Set(tm)
+	ts.TaskMasterLookup = lookup
+
+	if err := ts.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ts.Close() })
+	return ts
+}
+
+// do calls the handler h and decodes a successful response into result.
+func do(t *testing.T, h interface{}, user auth.User, method, url, body string, result interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, httpd.BasePath+url, strings.NewReader(body))
+	switch h := h.(type) {
+	case func(http.ResponseWriter, *http.Request):
+		h(w, r)
+	case func(http.ResponseWriter, *http.Request, auth.User):
+		h(w, r, user)
+	default:
+		t.Fatalf("invalid handler %T", h)
+	}
+	if w.Code == http.StatusOK && result != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w
+}
+
+func TestService_TaskRevisions(t *testing.T) {
+	ts := newTestService(t)
+	bob := auth.NewUser("bob", nil, false, nil)
+	alice := auth.NewUser("alice", nil, false, nil)
+
+	const (
+		script1 = "stream|from().measurement('cpu')"
+		script2 = "stream|from().measurement('mem')"
+	)
+	if w := do(t, ts.handleCreateTask, bob, "POST", "/tasks", `{"id":"t1","dbrps":[{"db":"telegraf","rp":"autogen"}],"script":"`+script1+`"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleUpdateTask, alice, "PATCH", "/tasks/t1", `{"script":"`+script2+`","status":"enabled"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	// Changing only the status does not record a revision.
+	if w := do(t, ts.handleUpdateTask, bob, "PATCH", "/tasks/t1", `{"status":"disabled"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleUpdateTask, bob, "PATCH", "/tasks/t1", `{"status":"enabled"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+
+	var list client.Revisions
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/t1/revisions", "", &list); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if got, exp := len(list.Revisions), 2; got != exp {
+		t.Fatalf("unexpected number of revisions: got %d exp %d", got, exp)
+	}
+	for i, exp := range []struct {
+		author, script string
+	}{{"bob", script1}, {"alice", script2}} {
+		r := list.Revisions[i]
+		if r.Number != i+1 || r.Author != exp.author || r.TICKscript != exp.script {
+			t.Errorf("unexpected revision %d: %+v", i, r)
+		}
+	}
+	if got, exp := list.Revisions[1].Link.Href, "/kapacitor/v1/tasks/t1/revisions/2"; got != exp {
+		t.Errorf("unexpected revision link: got %s exp %s", got, exp)
+	}
+
+	var diff client.RevisionDiff
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/t1/revisions/diff", "", &diff); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	expDiff := "--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-" + script1 + "\n+" + script2 + "\n"
+	if diff.From != 1 || diff.To != 2 || diff.TICKscript != expDiff || diff.DBRPs != "" || diff.Vars != "" {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/t1/revisions/3", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status getting missing revision: %d", w.Code)
+	}
+
+	// A snapshot of the current definition must not survive the rollback.
+	if err := ts.snapshots.Put("t1", &Snapshot{NodeSnapshots: map[string][]byte{"from1": []byte("state")}}); err != nil {
+		t.Fatal(err)
+	}
+	var task client.Task
+	if w := do(t, ts.handleRollbackTask, alice, "POST", "/tasks/t1/rollback", `{"revision":1}`, &task); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if task.TICKscript != "stream\n    |from()\n        .measurement('cpu')\n" {
+		t.Errorf("unexpected script after rollback: %q", task.TICKscript)
+	}
+	if !task.Executing {
+		t.Error("expected enabled task to be executing after rollback")
+	}
+	if ts.HasSnapshot("t1") {
+		t.Error("expected snapshot to be deleted by rollback")
+	}
+
+	var rev client.Revision
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/t1/revisions/3", "", &rev); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if rev.RollbackOf != 1 || rev.Author != "alice" || rev.TICKscript != script1 {
+		t.Errorf("unexpected rollback revision: %+v", rev)
+	}
+	if w := do(t, ts.handleRollbackTask, alice, "POST", "/tasks/t1/rollback", `{"revision":9}`, nil); w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status rolling back to missing revision: %d", w.Code)
+	}
+
+	// Revisions follow the task when its ID changes and are deleted with it.
+	if w := do(t, ts.handleUpdateTask, bob, "PATCH", "/tasks/t1", `{"id":"t2"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if revisions, err := ts.taskRevisions.List("t2"); err != nil || len(revisions) != 3 {
+		t.Errorf("unexpected revisions after ID change: %d %v", len(revisions), err)
+	}
+	if err := ts.deleteTask("t2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.taskRevisions.List("t2"); err != ErrNoRevisionsExist {
+		t.Errorf("unexpected error after delete: got %v exp %v", err, ErrNoRevisionsExist)
+	}
+}
+
+func TestService_TemplateRollback(t *testing.T) {
+	ts := newTestService(t)
+	user := auth.AdminUser
+
+	const (
+		script1 = "var m string\nstream|from().measurement(m)"
+		script2 = "var m string\nvar x = 1\nstream|from().measurement(m)|default().field('x', x)"
+	)
+	body, _ := json.Marshal(client.CreateTemplateOptions{ID: "tmpl", TICKscript: script1})
+	if w := do(t, ts.handleCreateTemplate, user, "POST", "/templates", string(body), nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleCreateTask, user, "POST", "/tasks", `{"id":"t1","template-id":"tmpl","dbrps":[{"db":"telegraf","rp":"autogen"}],"status":"enabled","vars":{"m":{"type":"string","value":"cpu"}}}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	body, _ = json.Marshal(client.UpdateTemplateOptions{TICKscript: script2})
+	if w := do(t, ts.handleUpdateTemplate, user, "PATCH", "/templates/tmpl", string(body), nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+
+	var list client.Revisions
+	if w := do(t, ts.handleTemplate, auth.User{}, "GET", "/templates/tmpl/revisions", "", &list); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if got, exp := len(list.Revisions), 2; got != exp {
+		t.Fatalf("unexpected number of revisions: got %d exp %d", got, exp)
+	}
+	if list.Revisions[0].Author != "" {
+		t.Errorf("unexpected author without authentication: %q", list.Revisions[0].Author)
+	}
+
+	if err := ts.snapshots.Put("t1", &Snapshot{NodeSnapshots: map[string][]byte{"from1": []byte("state")}}); err != nil {
+		t.Fatal(err)
+	}
+	var template client.Template
+	if w := do(t, ts.handleRollbackTemplate, user, "POST", "/templates/tmpl/rollback", `{"revision":1}`, &template); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if _, ok := template.Vars["x"]; ok {
+		t.Errorf("unexpected vars after rollback: %v", template.Vars)
+	}
+	task, err := ts.tasks.Get("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.TICKscript != script1 {
+		t.Errorf("unexpected task script after template rollback: %q", task.TICKscript)
+	}
+	if !ts.TaskMasterLookup.Main().IsExecuting("t1") {
+		t.Error("expected task to be executing after template rollback")
+	}
+	if ts.HasSnapshot("t1") {
+		t.Error("expected task snapshot to be deleted by template rollback")
+	}
+	// Tasks record their own definition, which the template rollback did not change.
+	if revisions, err := ts.taskRevisions.List("t1"); err != nil || len(revisions) != 1 {
+		t.Errorf("unexpected task revisions: %d %v", len(revisions), err)
+	}
+}
+
+type failingSnapshots struct {
+	SnapshotDAO
+}
+
+func (failingSnapshots) Delete(id string) error {
+	return errors.New("delete failed")
+}
+
+type failingTaskReplace struct {
+	TaskDAO
+}
+
+func (failingTaskReplace) Replace(t Task) error {
+	return errors.New("replace failed")
+}
+
+func TestService_RollbackFailure(t *testing.T) {
+	ts := newTestService(t)
+	user := auth.AdminUser
+
+	const (
+		script1 = "stream|from().measurement('cpu')"
+		script2 = "stream|from().measurement('mem')"
+	)
+	if w := do(t, ts.handleCreateTask, user, "POST", "/tasks", `{"id":"t1","dbrps":[{"db":"telegraf","rp":"autogen"}],"script":"`+script1+`"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleUpdateTask, user, "PATCH", "/tasks/t1", `{"script":"`+script2+`","status":"enabled"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+
+	// A task keeps its definition and keeps executing if its snapshot cannot be discarded.
+	snapshots := ts.snapshots
+	ts.snapshots = failingSnapshots{SnapshotDAO: snapshots}
+	if w := do(t, ts.handleRollbackTask, user, "POST", "/tasks/t1/rollback", `{"revision":1}`, nil); w.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status: %d %s", w.Code, w.Body.String())
+	}
+	ts.snapshots = snapshots
+	task, err := ts.tasks.Get("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.TICKscript != script2 {
+		t.Errorf("unexpected task script after failed rollback: %q", task.TICKscript)
+	}
+	if !ts.TaskMasterLookup.Main().IsExecuting("t1") {
+		t.Error("expected task to be executing after failed rollback")
+	}
+	if revisions, err := ts.taskRevisions.List("t1"); err != nil || len(revisions) != 2 {
+		t.Errorf("unexpected task revisions: %d %v", len(revisions), err)
+	}
+
+	// A template keeps its definition if its tasks cannot be updated.
+	const (
+		tmplScript1 = "var m string\nstream|from().measurement(m)"
+		tmplScript2 = "var m string\nvar x = 1\nstream|from().measurement(m)|default().field('x', x)"
+	)
+	body, _ := json.Marshal(client.CreateTemplateOptions{ID: "tmpl", TICKscript: tmplScript1})
+	if w := do(t, ts.handleCreateTemplate, user, "POST", "/templates", string(body), nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleCreateTask, user, "POST", "/tasks", `{"id":"t2","template-id":"tmpl","dbrps":[{"db":"telegraf","rp":"autogen"}],"status":"enabled","vars":{"m":{"type":"string","value":"cpu"}}}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	body, _ = json.Marshal(client.UpdateTemplateOptions{TICKscript: tmplScript2})
+	if w := do(t, ts.handleUpdateTemplate, user, "PATCH", "/templates/tmpl", string(body), nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	tasks := ts.tasks
+	ts.tasks = failingTaskReplace{TaskDAO: tasks}
+	if w := do(t, ts.handleRollbackTemplate, user, "POST", "/templates/tmpl/rollback", `{"revision":1}`, nil); w.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status: %d %s", w.Code, w.Body.String())
+	}
+	ts.tasks = tasks
+	template, err := ts.templates.Get("tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.TICKscript != tmplScript2 {
+		t.Errorf("unexpected template script after failed rollback: %q", template.TICKscript)
+	}
+	if task, err := ts.tasks.Get("t2"); err != nil || task.TICKscript != tmplScript2 {
+		t.Errorf("unexpected task script after failed template rollback: %q %v", task.TICKscript, err)
+	}
+}
@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/auth"
 	"github.com/influxdata/kapacitor/client/v1"
 	"github.com/influxdata/kapacitor/keyvalue"
 	"github.com/influxdata/kapacitor/server/vars"
@@ -31,6 +34,9 @@ const (
 
 	templatesPath         = "/templates"
 	templatesPathAnchored = "/templates/"
+
+	librariesPath         = "/libraries"
+	librariesPathAnchored = "/libraries/"
 )
 
 type Diagnostic interface {
@@ -48,13 +54,17 @@ type Diagnostic interface {
 }
 
 type Service struct {
-	oldDBDir         string
-	tasks            TaskDAO
-	templates        TemplateDAO
-	snapshots        SnapshotDAO
-	routes           []httpd.Route
-	snapshotInterval time.Duration
-	StorageService   interface {
+	oldDBDir          string
+	tasks             TaskDAO
+	templates         TemplateDAO
+	libraries         LibraryDAO
+	snapshots         SnapshotDAO
+	taskRevisions     RevisionDAO
+	templateRevisions RevisionDAO
+	shadowLog         ShadowLogDAO
+	routes            []httpd.Route
+	snapshotInterval  time.Duration
+	StorageService    interface {
 		Store(namespace string) storage.Interface
 		Register(name string, store storage.StoreActioner)
 	}
@@ -69,14 +79,21 @@ type Service struct {
 		Delete(*kapacitor.TaskMaster)
 	}
 
+	shadowLogRetention time.Duration
+	shadowQueue        chan queuedShadowRecord
+	closing            chan struct{}
+	wg                 sync.WaitGroup
+
 	diag Diagnostic
 }
 
 func NewService(conf Config, d Diagnostic) *Service {
 	return &Service{
-		snapshotInterval: time.Duration(conf.SnapshotInterval),
-		diag:             d,
-		oldDBDir:         conf.Dir,
+		snapshotInterval:   time.Duration(conf.SnapshotInterval),
+		shadowLogRetention: time.Duration(conf.ShadowLogRetention),
+		shadowQueue:        make(chan queuedShadowRecord, shadowQueueSize),
+		diag:               d,
+		oldDBDir:           conf.Dir,
 	}
 }
 
@@ -85,6 +102,8 @@ const (
 	tasksAPIName = "tasks"
 	// The storage namespace for all task data.
 	taskNamespace = "task_store"
+	// The storage namespace for the shadow logs of tasks.
+	shadowLogNamespace = "task_shadow_log"
 )
 
 func (ts *Service) Open() error {
@@ -98,6 +117,22 @@ func (ts *Service) Open() error {
 	ts.StorageService.Register(tasksAPIName, ts.tasks)
 	ts.templates = newTemplateKV(store)
 	ts.snapshots = newSnapshotKV(store)
+	librariesDAO, err := newLibraryKV(store)
+	if err != nil {
+		return err
+	}
+	ts.libraries = librariesDAO
+	taskRevisionsDAO, err := newRevisionKV(store, "task_revisions")
+	if err != nil {
+		return err
+	}
+	ts.taskRevisions = taskRevisionsDAO
+	templateRevisionsDAO, err := newRevisionKV(store, "template_revisions")
+	if err != nil {
+		return err
+	}
+	ts.templateRevisions = templateRevisionsDAO
+	ts.shadowLog = newShadowKV(ts.StorageService.Store(shadowLogNamespace))
 
 	// Perform migration to new storage service.
 	if err := ts.migrate(); err != nil {
@@ -127,6 +162,11 @@ func (ts *Service) Open() error {
 			Pattern:     tasksPathAnchored,
 			HandlerFunc: ts.handleUpdateTask,
 		},
+		{
+			Method:      "POST",
+			Pattern:     tasksPathAnchored,
+			HandlerFunc: ts.handleRollbackTask,
+		},
 		{
 			Method:      "GET",
 			Pattern:     tasksPath,
@@ -158,6 +198,11 @@ func (ts *Service) Open() error {
 			Pattern:     templatesPathAnchored,
 			HandlerFunc: ts.handleUpdateTemplate,
 		},
+		{
+			Method:      "POST",
+			Pattern:     templatesPathAnchored,
+			HandlerFunc: ts.handleRollbackTemplate,
+		},
 		{
 			Method:      "GET",
 			Pattern:     templatesPath,
@@ -168,6 +213,37 @@ func (ts *Service) Open() error {
 			Pattern:     templatesPath,
 			HandlerFunc: ts.handleCreateTemplate,
 		},
+		{
+			Method:      "GET",
+			Pattern:     librariesPathAnchored,
+			HandlerFunc: ts.handleLibrary,
+		},
+		{
+			Method:      "DELETE",
+			Pattern:     librariesPathAnchored,
+			HandlerFunc: ts.handleDeleteLibrary,
+		},
+		{
+			// Satisfy CORS checks.
+			Method:      "OPTIONS",
+			Pattern:     librariesPathAnchored,
+			HandlerFunc: httpd.ServeOptions,
+		},
+		{
+			Method:      "PATCH",
+			Pattern:     librariesPathAnchored,
+			HandlerFunc: ts.handleUpdateLibrary,
+		},
+		{
+			Method:      "GET",
+			Pattern:     librariesPath,
+			HandlerFunc: ts.handleListLibraries,
+		},
+		{
+			Method:      "POST",
+			Pattern:     librariesPath,
+			HandlerFunc: ts.handleCreateLibrary,
+		},
 	}
 
 	err = ts.HTTPDService.AddRoutes(ts.routes)
@@ -175,6 +251,20 @@ func (ts *Service) Open() error {
 		return err
 	}
 
+	ts.closing = make(chan struct{})
+	ts.wg.Add(1)
+	go func(closing <-chan struct{}) {
+		defer ts.wg.Done()
+		ts.runShadowWriter(closing)
+	}(ts.closing)
+	if ts.shadowLogRetention > 0 {
+		ts.wg.Add(1)
+		go func(closing <-chan struct{}) {
+			defer ts.wg.Done()
+			ts.runShadowLogPurge(closing)
+		}(ts.closing)
+	}
+
 	numTasks := int64(0)
 	numEnabledTasks := int64(0)
 
@@ -370,6 +460,11 @@ func (ts *Service) migrate() error {
 
 func (ts *Service) Close() error {
 	ts.HTTPDService.DelRoutes(ts.routes)
+	if ts.closing != nil {
+		close(ts.closing)
+		ts.closing = nil
+	}
+	ts.wg.Wait()
 	return nil
 }
 
@@ -408,6 +503,15 @@ func (ts *Service) LoadSnapshot(id string) (*kapacitor.TaskSnapshot, error) {
 	return s, nil
 }
 
+// LoadLibrary returns the TICKscript of the library with the ID.
+func (ts *Service) LoadLibrary(id string) (string, error) {
+	l, err := ts.libraries.Get(id)
+	if err != nil {
+		return "", err
+	}
+	return l.TICKscript, nil
+}
+
 type TaskInfo struct {
 	Name           string
 	Type           kapacitor.TaskType
@@ -421,6 +525,19 @@ type TaskInfo struct {
 }
 
 func (ts *Service) handleTask(w http.ResponseWriter, r *http.Request) {
+	if id, sub, ok := splitSubPath(r.URL.Path, tasksBasePathAnchored); ok {
+		task, err := ts.tasks.Get(id)
+		if err != nil {
+			httpd.HttpError(w, err.Error(), true, http.StatusNotFound)
+			return
+		}
+		if sub == shadowPath || strings.HasPrefix(sub, shadowPath+"/") {
+			ts.handleShadow(w, r, task, sub)
+			return
+		}
+		ts.handleRevisions(w, r, ts.taskRevisions, id, ts.taskLink(id), sub)
+		return
+	}
 	id, err := ts.taskIDFromPath(r.URL.Path)
 	if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
@@ -494,6 +611,7 @@ var allTaskFields = []string{
 	"last-enabled",
 	"vars",
 	"template-id",
+	"shadow-of",
 }
 
 const tasksBasePathAnchored = httpd.BasePath + tasksPathAnchored
@@ -653,6 +771,11 @@ func (ts *Service) handleListTasks(w http.ResponseWriter, r *http.Request) {
 					continue
 				}
 				value = task.TemplateID
+			case "shadow-of":
+				if len(task.ShadowOf) == 0 {
+					continue
+				}
+				value = task.ShadowOf
 			case "vars":
 				vars, err := ts.convertToClientVars(task.Vars)
 				if err != nil {
@@ -677,7 +800,7 @@ func (ts *Service) handleListTasks(w http.ResponseWriter, r *http.Request) {
 
 var validTaskID = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
 
-func (ts *Service) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+func (ts *Service) handleCreateTask(w http.ResponseWriter, r *http.Request, user auth.User) {
 	task := client.CreateTaskOptions{}
 	dec := json.NewDecoder(r.Body)
 	err := dec.Decode(&task)
@@ -704,6 +827,20 @@ func (ts *Service) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check for the task to shadow
+	if task.ShadowOf != "" {
+		primary, err := ts.tasks.Get(task.ShadowOf)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("unknown task to shadow %s: err: %s", task.ShadowOf, err), true, http.StatusBadRequest)
+			return
+		}
+		if primary.ShadowOf != "" {
+			httpd.HttpError(w, fmt.Sprintf("task %s is itself a shadow task", primary.ID), true, http.StatusBadRequest)
+			return
+		}
+		newTask.ShadowOf = task.ShadowOf
+	}
+
 	// Check for template ID
 	if task.TemplateID != "" {
 		template, err := ts.templates.Get(task.TemplateID)
@@ -827,6 +964,7 @@ func (ts *Service) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
 		return
 	}
+	ts.recordRevision(ts.taskRevisions, newTask.ID, nil, taskRevision(newTask, revisionAuthor(user), now))
 
 	// Count new task
 	vars.NumTasksVar.Add(1)
@@ -851,7 +989,7 @@ func (ts *Service) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 	w.Write(httpd.MarshalJSON(t, true))
 }
 
-func (ts *Service) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+func (ts *Service) handleUpdateTask(w http.ResponseWriter, r *http.Request, user auth.User) {
 	id, err := ts.taskIDFromPath(r.URL.Path)
 	if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
@@ -1021,6 +1159,22 @@ func (ts *Service) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 				keyvalue.KV("newID", updated.ID),
 			)
 		}
+		if err := ts.taskRevisions.Rename(original.ID, updated.ID); err != nil {
+			ts.diag.Error(
+				"failed to move revisions during ID change",
+				err,
+				keyvalue.KV("oldID", original.ID),
+				keyvalue.KV("newID", updated.ID),
+			)
+		}
+		if err := ts.shadowLog.Rename(original.ID, updated.ID); err != nil {
+			ts.diag.Error(
+				"failed to move shadow log during ID change",
+				err,
+				keyvalue.KV("oldID", original.ID),
+				keyvalue.KV("newID", updated.ID),
+			)
+		}
 		if original.Status == Enabled && updated.Status == Enabled {
 			// Stop task and start it under new name
 			ts.stopTask(original.ID)
@@ -1029,12 +1183,22 @@ func (ts *Service) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+		if err := ts.moveShadows(original.ID, updated.ID); err != nil {
+			ts.diag.Error(
+				"failed to move shadow tasks during ID change",
+				err,
+				keyvalue.KV("oldID", original.ID),
+				keyvalue.KV("newID", updated.ID),
+			)
+		}
 	} else {
 		if err := ts.tasks.Replace(updated); err != nil {
 			httpd.HttpError(w, fmt.Sprintf("failed to replace task definition: %s", err.Error()), true, http.StatusInternalServerError)
 			return
 		}
 	}
+	previous := taskRevision(original, "", original.Modified)
+	ts.recordRevision(ts.taskRevisions, updated.ID, &previous, taskRevision(updated, revisionAuthor(user), now))
 
 	if statusChanged {
 		// Enable/Disable task
@@ -1144,6 +1308,7 @@ func (ts *Service) convertTask(t Task, scriptFormat, dotView string, tm *kapacit
 		Modified:       t.Modified,
 		LastEnabled:    t.LastEnabled,
 		Error:          errMsg,
+		ShadowOf:       t.ShadowOf,
 	}, nil
 }
 
@@ -1404,16 +1569,36 @@ func (ts *Service) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = ts.deleteTask(id)
-	if err != nil {
+	if errors.Cause(err) == ErrTaskShadowed {
+		httpd.HttpError(w, err.Error(), true, http.StatusConflict)
+		return
+	} else if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// deleteTask deletes a task and its revisions, snapshot and shadow log.
+// A task cannot be deleted while other tasks shadow it.
 func (ts *Service) deleteTask(id string) error {
-	// Delete associated snapshot
+	shadows, err := ts.shadowsOf(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to find shadow tasks")
+	}
+	if len(shadows) > 0 {
+		ids := make([]string, len(shadows))
+		for i, s := range shadows {
+			ids[i] = s.ID
+		}
+		return errors.Wrapf(ErrTaskShadowed, "task %s is shadowed by tasks %v, cannot delete", id, ids)
+	}
+
+	// Delete associated snapshot and revisions
 	ts.snapshots.Delete(id)
+	if err := ts.taskRevisions.Delete(id); err != nil {
+		ts.diag.Error("failed to delete task revisions", err, keyvalue.KV("task", id))
+	}
 
 	// Delete task object
 	task, err := ts.tasks.Get(id)
@@ -1434,6 +1619,10 @@ func (ts *Service) deleteTask(id string) error {
 		vars.NumEnabledTasksVar.Add(-1)
 		ts.TaskMasterLookup.Main().DeleteTask(id)
 	}
+	// Delete the shadow log once the task can no longer record to it
+	if err := ts.shadowLog.Delete(id); err != nil {
+		ts.diag.Error("failed to delete task shadow log", err, keyvalue.KV("task", id))
+	}
 	return ts.tasks.Delete(id)
 }
 
@@ -1484,6 +1673,14 @@ func (ts *Service) convertTemplate(t Template, scriptFormat string) (client.Temp
 }
 
 func (ts *Service) handleTemplate(w http.ResponseWriter, r *http.Request) {
+	if id, sub, ok := splitSubPath(r.URL.Path, templatesBasePathAnchored); ok {
+		if _, err := ts.templates.Get(id); err != nil {
+			httpd.HttpError(w, err.Error(), true, http.StatusNotFound)
+			return
+		}
+		ts.handleRevisions(w, r, ts.templateRevisions, id, ts.templateLink(id), sub)
+		return
+	}
 	id, err := ts.templateIDFromPath(r.URL.Path)
 	if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
@@ -1652,7 +1849,7 @@ func (ts *Service) handleListTemplates(w http.ResponseWriter, r *http.Request) {
 
 var validTemplateID = regexp.MustCompile(`^[-\._\p{L}0-9]+$`)
 
-func (ts *Service) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+func (ts *Service) handleCreateTemplate(w http.ResponseWriter, r *http.Request, user auth.User) {
 	template := client.CreateTemplateOptions{}
 	dec := json.NewDecoder(r.Body)
 	err := dec.Decode(&template)
@@ -1720,6 +1917,7 @@ func (ts *Service) handleCreateTemplate(w http.ResponseWriter, r *http.Request)
 		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
 		return
 	}
+	ts.recordRevision(ts.templateRevisions, newTemplate.ID, nil, templateRevision(newTemplate, revisionAuthor(user), now))
 
 	// Return template definition
 	t, err := ts.convertTemplate(newTemplate, "formatted")
@@ -1731,7 +1929,7 @@ func (ts *Service) handleCreateTemplate(w http.ResponseWriter, r *http.Request)
 	w.Write(httpd.MarshalJSON(t, true))
 }
 
-func (ts *Service) handleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+func (ts *Service) handleUpdateTemplate(w http.ResponseWriter, r *http.Request, user auth.User) {
 	id, err := ts.templateIDFromPath(r.URL.Path)
 	if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
@@ -1798,6 +1996,10 @@ func (ts *Service) handleUpdateTemplate(w http.ResponseWriter, r *http.Request)
 			ts.diag.Error("failed to delete old template during ID change", err,
 				keyvalue.KV("oldID", original.ID), keyvalue.KV("newID", updated.ID))
 		}
+		if err := ts.templateRevisions.Rename(original.ID, updated.ID); err != nil {
+			ts.diag.Error("failed to move revisions during ID change", err,
+				keyvalue.KV("oldID", original.ID), keyvalue.KV("newID", updated.ID))
+		}
 	} else {
 		if err := ts.templates.Replace(updated); err != nil {
 			httpd.HttpError(w, fmt.Sprintf("failed to replace template definition: %s", err.Error()), true, http.StatusInternalServerError)
@@ -1805,8 +2007,11 @@ func (ts *Service) handleUpdateTemplate(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	previous := templateRevision(original, "", original.Modified)
+	ts.recordRevision(ts.templateRevisions, updated.ID, &previous, templateRevision(updated, revisionAuthor(user), now))
+
 	// Update all associated tasks
-	err = ts.updateAllAssociatedTasks(original, updated, taskIds)
+	err = ts.updateAllAssociatedTasks(original, updated, taskIds, false)
 	if err != nil {
 		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
 		return
@@ -1825,7 +2030,8 @@ func (ts *Service) handleUpdateTemplate(w http.ResponseWriter, r *http.Request)
 
 // Update all associated tasks. Return the first error if any.
 // Rollsback all updated tasks if an error occurs.
-func (ts *Service) updateAllAssociatedTasks(old, new Template, taskIds []string) error {
+// If discardSnapshots is set the snapshots of the tasks are deleted before they are restarted.
+func (ts *Service) updateAllAssociatedTasks(old, new Template, taskIds []string, discardSnapshots bool) error {
 	var i int
 	oldPn, err := newProgramNodeFromTickscript(old.TICKscript)
 	if err != nil {
@@ -1916,6 +2122,13 @@ func (ts *Service) updateAllAssociatedTasks(old, new Template, taskIds []string)
 		}
 		if task.Status == Enabled {
 			ts.stopTask(taskId)
+		}
+		if discardSnapshots {
+			if err := ts.snapshots.Delete(taskId); err != nil {
+				return fmt.Errorf("error deleting snapshot of associated task %s: %s", taskId, err)
+			}
+		}
+		if task.Status == Enabled {
 			err := ts.startTask(task)
 			if err != nil {
 				return fmt.Errorf("error reloading associated task %s: %s", taskId, err)
@@ -1937,6 +2150,357 @@ func (ts *Service) handleDeleteTemplate(w http.ResponseWriter, r *http.Request)
 		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
 		return
 	}
+	if err := ts.templateRevisions.Delete(id); err != nil {
+		ts.diag.Error("failed to delete template revisions", err, keyvalue.KV("template", id))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ts *Service) convertLibrary(l Library, scriptFormat string) client.Library {
+	script := l.TICKscript
+	if scriptFormat == "formatted" {
+		// Format TICKscript
+		formatted, err := tick.Format(script)
+		if err == nil {
+			// Only format if it succeeded.
+			// Otherwise a change in syntax may prevent library retrieval.
+			script = formatted
+		}
+	}
+	return client.Library{
+		Link:       ts.libraryLink(l.ID),
+		ID:         l.ID,
+		TICKscript: script,
+		Created:    l.Created,
+		Modified:   l.Modified,
+	}
+}
+
+const librariesBasePathAnchored = httpd.BasePath + librariesPathAnchored
+
+func (ts *Service) libraryIDFromPath(path string) (string, error) {
+	if len(path) <= len(librariesBasePathAnchored) {
+		return "", errors.New("must specify library id on path")
+	}
+	id := path[len(librariesBasePathAnchored):]
+	return id, nil
+}
+
+func (ts *Service) libraryLink(id string) client.Link {
+	return client.Link{Relation: client.Self, Href: path.Join(httpd.BasePath, librariesPath, id)}
+}
+
+// validateLibrary evaluates the library in a new scope,
+// which checks its definitions and resolves its imports.
+func (ts *Service) validateLibrary(script string) error {
+	if script == "" {
+		return errors.New("must provide TICKscript")
+	}
+	scope := ts.TaskMasterLookup.Main().CreateTICKScope()
+	return tick.EvaluateLibrary(script, scope)
+}
+
+func (ts *Service) handleLibrary(w http.ResponseWriter, r *http.Request) {
+	id, err := ts.libraryIDFromPath(r.URL.Path)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	raw, err := ts.libraries.Get(id)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusNotFound)
+		return
+	}
+
+	scriptFormat := r.URL.Query().Get("script-format")
+	switch scriptFormat {
+	case "":
+		scriptFormat = "formatted"
+	case "formatted", "raw":
+	default:
+		httpd.HttpError(w, fmt.Sprintf("invalid script-format parameter %q", scriptFormat), true, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(ts.convertLibrary(raw, scriptFormat), true))
+}
+
+func (ts *Service) handleListLibraries(w http.ResponseWriter, r *http.Request) {
+	pattern := r.URL.Query().Get("pattern")
+
+	scriptFormat := r.URL.Query().Get("script-format")
+	switch scriptFormat {
+	case "":
+		scriptFormat = "formatted"
+	case "formatted", "raw":
+	default:
+		httpd.HttpError(w, fmt.Sprintf("invalid script-format parameter %q", scriptFormat), true, http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	offset := int64(0)
+	offsetStr := r.URL.Query().Get("offset")
+	if offsetStr != "" {
+		offset, err = strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid offset parameter %q must be an integer: %s", offsetStr, err), true, http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := int64(100)
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid limit parameter %q must be an integer: %s", limitStr, err), true, http.StatusBadRequest)
+			return
+		}
+	}
+
+	rawLibraries, err := ts.libraries.List(pattern, int(offset), int(limit))
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to list libraries with pattern %q: %s", pattern, err), true, http.StatusBadRequest)
+		return
+	}
+	libraries := make([]client.Library, len(rawLibraries))
+	for i, l := range rawLibraries {
+		libraries[i] = ts.convertLibrary(l, scriptFormat)
+	}
+
+	type response struct {
+		Libraries []client.Library `json:"libraries"`
+	}
+
+	w.Write(httpd.MarshalJSON(response{libraries}, true))
+}
+
+func (ts *Service) handleCreateLibrary(w http.ResponseWriter, r *http.Request) {
+	library := client.CreateLibraryOptions{}
+	dec := json.NewDecoder(r.Body)
+	err := dec.Decode(&library)
+	if err != nil {
+		httpd.HttpError(w, "invalid JSON", true, http.StatusBadRequest)
+		return
+	}
+	if library.ID == "" {
+		httpd.HttpError(w, "must provide library ID", true, http.StatusBadRequest)
+		return
+	}
+	if !validTemplateID.MatchString(library.ID) {
+		httpd.HttpError(w, fmt.Sprintf("library ID must contain only letters, numbers, '-', '.' and '_'. %q", library.ID), true, http.StatusBadRequest)
+		return
+	}
+
+	// Check for existing library
+	_, err = ts.libraries.Get(library.ID)
+	if err == nil {
+		httpd.HttpError(w, fmt.Sprintf("library %s already exists", library.ID), true, http.StatusBadRequest)
+		return
+	}
+
+	// Validate library
+	if err := ts.validateLibrary(library.TICKscript); err != nil {
+		httpd.HttpError(w, "invalid TICKscript: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	newLibrary := Library{
+		ID:         library.ID,
+		TICKscript: library.TICKscript,
+		Created:    now,
+		Modified:   now,
+	}
+
+	// Save library
+	err = ts.libraries.Create(newLibrary)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(ts.convertLibrary(newLibrary, "formatted"), true))
+}
+
+// handleUpdateLibrary replaces the TICKscript of a library.
+// Tasks that import the library are validated and reloaded with the new TICKscript,
+// the update is rolled back if any of them fails.
+func (ts *Service) handleUpdateLibrary(w http.ResponseWriter, r *http.Request) {
+	id, err := ts.libraryIDFromPath(r.URL.Path)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	library := client.UpdateLibraryOptions{}
+	dec := json.NewDecoder(r.Body)
+	err = dec.Decode(&library)
+	if err != nil {
+		httpd.HttpError(w, "invalid JSON", true, http.StatusBadRequest)
+		return
+	}
+
+	// Check for existing library
+	existing, err := ts.libraries.Get(id)
+	if err != nil {
+		httpd.HttpError(w, "library does not exist, cannot update", true, http.StatusNotFound)
+		return
+	}
+	updated := existing
+
+	// Set tick script
+	if library.TICKscript != "" {
+		updated.TICKscript = library.TICKscript
+	}
+
+	// Validate library
+	if err := ts.validateLibrary(updated.TICKscript); err != nil {
+		httpd.HttpError(w, "invalid TICKscript: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+
+	importers, _, err := ts.libraryImporters(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to find tasks that import library %s: %s", id, err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+
+	// Save updated library
+	updated.Modified = time.Now()
+	if err := ts.libraries.Replace(updated); err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to replace library definition: %s", err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+
+	// Reload the tasks that import the library, restoring the old library if any of them fails.
+	if reloaded, err := ts.reloadTasks(importers); err != nil {
+		if err := ts.libraries.Replace(existing); err != nil {
+			ts.diag.Error("error rolling back library", err, keyvalue.KV("library", id))
+		}
+		if _, err := ts.reloadTasks(reloaded); err != nil {
+			ts.diag.Error("error rolling back tasks that import library", err, keyvalue.KV("library", id))
+		}
+		httpd.HttpError(w, fmt.Sprintf("error reloading tasks that import library %s: %s", id, err.Error()), true, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpd.MarshalJSON(ts.convertLibrary(updated, "formatted"), true))
+}
+
+// reloadTasks validates the tasks again and restarts the enabled ones,
+// so that they use the current TICKscript of the libraries they import.
+// On error the tasks reloaded so far are returned, so that they can be reloaded again once the error is undone.
+func (ts *Service) reloadTasks(taskIds []string) ([]string, error) {
+	for i, taskId := range taskIds {
+		task, err := ts.tasks.Get(taskId)
+		if err == ErrNoTaskExists {
+			continue
+		}
+		if err != nil {
+			return taskIds[:i], fmt.Errorf("error retrieving task %s: %s", taskId, err)
+		}
+		if task.Status != Enabled {
+			if _, err := ts.newKapacitorTask(task); err != nil {
+				return taskIds[:i], fmt.Errorf("invalid task %s: %s", taskId, err)
+			}
+			continue
+		}
+		ts.stopTask(taskId)
+		if err := ts.startTask(task); err != nil {
+			return taskIds[:i+1], fmt.Errorf("error reloading task %s: %s", taskId, err)
+		}
+	}
+	return taskIds, nil
+}
+
+// libraryImporters returns the IDs of the tasks that import the library, directly or via other libraries,
+// and of the libraries that import it directly.
+func (ts *Service) libraryImporters(id string) (tasks, libraries []string, err error) {
+	rawLibraries, err := ts.libraries.List("", 0, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	libraryImports := make(map[string][]string, len(rawLibraries))
+	for _, l := range rawLibraries {
+		pn, err := newProgramNodeFromTickscript(l.TICKscript)
+		if err != nil {
+			return nil, nil, fmt.Errorf("library %s: %v", l.ID, err)
+		}
+		libraryImports[l.ID] = importsFromProgram(pn)
+		for _, imported := range libraryImports[l.ID] {
+			if imported == id {
+				libraries = append(libraries, l.ID)
+				break
+			}
+		}
+	}
+
+	// imports reports whether the library imports id, directly or indirectly.
+	memo := make(map[string]bool)
+	var imports func(library string) bool
+	imports = func(library string) bool {
+		if library == id {
+			return true
+		}
+		if v, ok := memo[library]; ok {
+			return v
+		}
+		// Mark the library while visiting it, so that import cycles terminate.
+		memo[library] = false
+		for _, imported := range libraryImports[library] {
+			if imports(imported) {
+				memo[library] = true
+				break
+			}
+		}
+		return memo[library]
+	}
+
+	rawTasks, err := ts.tasks.List("", 0, -1)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, task := range rawTasks {
+		pn, err := newProgramNodeFromTickscript(task.TICKscript)
+		if err != nil {
+			// A task that cannot be parsed does not import anything.
+			continue
+		}
+		for _, imported := range importsFromProgram(pn) {
+			if imports(imported) {
+				tasks = append(tasks, task.ID)
+				break
+			}
+		}
+	}
+	return tasks, libraries, nil
+}
+
+func (ts *Service) handleDeleteLibrary(w http.ResponseWriter, r *http.Request) {
+	id, err := ts.libraryIDFromPath(r.URL.Path)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	tasks, libraries, err := ts.libraryImporters(id)
+	if err != nil {
+		httpd.HttpError(w, fmt.Sprintf("failed to find tasks that import library %s: %s", id, err.Error()), true, http.StatusInternalServerError)
+		return
+	}
+	if len(tasks) > 0 || len(libraries) > 0 {
+		httpd.HttpError(w, fmt.Sprintf("library %s is imported by tasks %v and libraries %v, cannot delete", id, tasks, libraries), true, http.StatusConflict)
+		return
+	}
+	err = ts.libraries.Delete(id)
+	if err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusInternalServerError)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -1959,13 +2523,18 @@ func (ts *Service) newKapacitorTask(task Task) (*kapacitor.Task, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ts.TaskMasterLookup.Main().NewTask(task.ID,
+	t, err := ts.TaskMasterLookup.Main().NewTask(task.ID,
 		task.TICKscript,
 		tt,
 		dbrps,
 		ts.snapshotInterval,
 		vars,
 	)
+	if err != nil {
+		return nil, err
+	}
+	t.ShadowOf = task.ShadowOf
+	return t, nil
 }
 
 func (ts *Service) templateTask(template Template) (*kapacitor.Template, error) {
@@ -0,0 +1,76 @@
+package task_store
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/influxdata/kapacitor/auth"
+)
+
+func TestService_LibraryImporters(t *testing.T) {
+	ts := newTestService(t)
+	user := auth.NewUser("bob", nil, false, nil)
+
+	const (
+		base    = `def logged() = |log()`
+		wrapper = `import 'base'\ndef wrapped() = @logged()`
+		broken  = `def other() = |log()`
+	)
+	for _, l := range []struct{ id, script string }{{"base", base}, {"wrapper", wrapper}} {
+		if w := do(t, ts.handleCreateLibrary, user, "POST", "/libraries", `{"id":"`+l.id+`","script":"`+l.script+`"}`, nil); w.Code != http.StatusOK {
+			t.Fatal(w.Body.String())
+		}
+	}
+	// The task imports base via wrapper.
+	script := `import 'wrapper'\nstream|from().measurement('cpu')@wrapped()`
+	if w := do(t, ts.handleCreateTask, user, "POST", "/tasks", `{"id":"t1","dbrps":[{"db":"telegraf","rp":"autogen"}],"script":"`+script+`","status":"enabled"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+
+	tasks, libraries, err := ts.libraryImporters("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0] != "t1" || len(libraries) != 1 || libraries[0] != "wrapper" {
+		t.Fatalf("unexpected importers of base: tasks %v libraries %v", tasks, libraries)
+	}
+
+	// Libraries that are imported cannot be deleted.
+	for _, id := range []string{"base", "wrapper"} {
+		if w := do(t, ts.handleDeleteLibrary, user, "DELETE", "/libraries/"+id, "", nil); w.Code != http.StatusConflict {
+			t.Fatalf("unexpected status deleting imported library %s: %d %s", id, w.Code, w.Body.String())
+		}
+	}
+
+	// An update that breaks an importing task is rolled back.
+	if w := do(t, ts.handleUpdateLibrary, user, "PATCH", "/libraries/base", `{"script":"`+broken+`"}`, nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status updating library that breaks a task: %d %s", w.Code, w.Body.String())
+	}
+	l, err := ts.libraries.Get("base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.TICKscript != base {
+		t.Errorf("library was not rolled back, got %q", l.TICKscript)
+	}
+	if !ts.TaskMasterLookup.Main().IsExecuting("t1") {
+		t.Error("importing task is not executing after rollback")
+	}
+
+	// A compatible update reloads the importing task.
+	if w := do(t, ts.handleUpdateLibrary, user, "PATCH", "/libraries/base", `{"script":"def logged() = |log().level('INFO')"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if !ts.TaskMasterLookup.Main().IsExecuting("t1") {
+		t.Error("importing task is not executing after update")
+	}
+
+	if w := do(t, ts.handleDeleteTask, user, "DELETE", "/tasks/t1", "", nil); w.Code != http.StatusNoContent {
+		t.Fatal(w.Body.String())
+	}
+	for _, id := range []string{"wrapper", "base"} {
+		if w := do(t, ts.handleDeleteLibrary, user, "DELETE", "/libraries/"+id, "", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("unexpected status deleting library %s: %d %s", id, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package task_store
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/services/httpd"
+)
+
+const (
+	shadowPath  = "shadow"
+	comparePath = "compare"
+
+	// How often expired shadow records are deleted.
+	shadowLogPurgeInterval = time.Hour
+
+	// Number of shadow records queued for writing before records are dropped.
+	shadowQueueSize = 10000
+	// Maximum number of queued shadow records written in a single transaction.
+	shadowWriteBatchSize = 1000
+)
+
+// ErrShadowQueueFull is returned when a shadow record is dropped because the shadow log cannot keep up.
+var ErrShadowQueueFull = errors.New("shadow log queue is full, dropping record")
+
+type queuedShadowRecord struct {
+	id string
+	r  ShadowRecord
+}
+
+// RecordShadow queues an alert or write of a task for its shadow log.
+// Records are written in the background so that tasks do not wait on storage.
+func (ts *Service) RecordShadow(id string, r kapacitor.ShadowRecord) error {
+	var level string
+	if r.Type == kapacitor.ShadowAlert {
+		level = r.Level.String()
+	}
+	q := queuedShadowRecord{id: id, r: ShadowRecord{
+		Type:            string(r.Type),
+		Node:            r.Node,
+		Time:            r.Time,
+		ID:              r.ID,
+		Level:           level,
+		Message:         r.Message,
+		Database:        r.Database,
+		RetentionPolicy: r.RetentionPolicy,
+		Measurement:     r.Measurement,
+		Points:          r.Points,
+		URL:             r.URL,
+		Replicas:        r.Replicas,
+	}}
+	select {
+	case ts.shadowQueue <- q:
+		return nil
+	default:
+		return ErrShadowQueueFull
+	}
+}
+
+// runShadowWriter writes the queued shadow records until closing,
+// then writes the records still queued.
+func (ts *Service) runShadowWriter(closing <-chan struct{}) {
+	for {
+		select {
+		case q := <-ts.shadowQueue:
+			ts.writeShadowRecords(q)
+		case <-closing:
+			for {
+				select {
+				case q := <-ts.shadowQueue:
+					ts.writeShadowRecords(q)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeShadowRecords writes a queued record along with the records queued after it,
+// up to shadowWriteBatchSize records, with one transaction per task.
+func (ts *Service) writeShadowRecords(first queuedShadowRecord) {
+	ids := []string{first.id}
+	records := map[string][]ShadowRecord{first.id: {first.r}}
+collect:
+	for n := 1; n < shadowWriteBatchSize; n++ {
+		select {
+		case q := <-ts.shadowQueue:
+			if _, ok := records[q.id]; !ok {
+				ids = append(ids, q.id)
+			}
+			records[q.id] = append(records[q.id], q.r)
+		default:
+			break collect
+		}
+	}
+	for _, id := range ids {
+		if err := ts.shadowLog.Append(id, records[id]...); err != nil {
+			ts.diag.Error("failed to record shadow output", err, keyvalue.KV("task", id))
+		}
+	}
+}
+
+// ErrTaskShadowed is returned when deleting a task that other tasks shadow.
+var ErrTaskShadowed = errors.New("task is shadowed")
+
+// shadowsOf returns the tasks that shadow the task.
+func (ts *Service) shadowsOf(id string) ([]Task, error) {
+	tasks, err := ts.tasks.List("", 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	var shadows []Task
+	for _, t := range tasks {
+		if t.ShadowOf == id {
+			shadows = append(shadows, t)
+		}
+	}
+	return shadows, nil
+}
+
+// moveShadows makes the shadows of a task that changed its ID shadow it under its new ID.
+// Enabled shadows are restarted so that the alerts of the task are recorded under its new ID.
+func (ts *Service) moveShadows(oldID, newID string) error {
+	shadows, err := ts.shadowsOf(oldID)
+	if err != nil {
+		return err
+	}
+	for _, shadow := range shadows {
+		shadow.ShadowOf = newID
+		if err := ts.tasks.Replace(shadow); err != nil {
+			return fmt.Errorf("failed to update shadow task %s: %v", shadow.ID, err)
+		}
+		if shadow.Status == Enabled {
+			ts.stopTask(shadow.ID)
+			if err := ts.startTask(shadow); err != nil {
+				return fmt.Errorf("failed to restart shadow task %s: %v", shadow.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runShadowLogPurge purges the expired shadow records in the background, first when opening
+// so that a large backlog does not delay startup and then periodically.
+func (ts *Service) runShadowLogPurge(closing <-chan struct{}) {
+	ts.purgeShadowLog()
+	ticker := time.NewTicker(shadowLogPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closing:
+			return
+		case <-ticker.C:
+			ts.purgeShadowLog()
+		}
+	}
+}
+
+// purgeShadowLog deletes the shadow records older than the retention period.
+func (ts *Service) purgeShadowLog() {
+	if err := ts.shadowLog.DeleteBefore(time.Now().Add(-ts.shadowLogRetention)); err != nil {
+		ts.diag.Error("failed to purge expired shadow log", err)
+	}
+}
+
+func convertShadowRecord(r ShadowRecord) client.ShadowRecord {
+	return client.ShadowRecord{
+		Type:            r.Type,
+		Node:            r.Node,
+		Time:            r.Time,
+		ID:              r.ID,
+		Level:           r.Level,
+		Message:         r.Message,
+		Database:        r.Database,
+		RetentionPolicy: r.RetentionPolicy,
+		Measurement:     r.Measurement,
+		Points:          r.Points,
+		URL:             r.URL,
+		Replicas:        r.Replicas,
+	}
+}
+
+// handleShadow serves the shadow sub paths of a task.
+func (ts *Service) handleShadow(w http.ResponseWriter, r *http.Request, task Task, sub string) {
+	q := r.URL.Query()
+	var start, stop time.Time
+	var err error
+	if str := q.Get("start"); str != "" {
+		start, err = time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid start time: %s", err.Error()), true, http.StatusBadRequest)
+			return
+		}
+	}
+	if str := q.Get("stop"); str != "" {
+		stop, err = time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("invalid stop time: %s", err.Error()), true, http.StatusBadRequest)
+			return
+		}
+	}
+
+	link := path.Join(ts.taskLink(task.ID).Href, shadowPath)
+	switch sub {
+	case shadowPath:
+		records, err := ts.shadowLog.List(task.ID, start, stop)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("failed to get shadow log: %s", err.Error()), true, http.StatusInternalServerError)
+			return
+		}
+		l := client.ShadowLog{
+			Link:     client.Link{Relation: client.Self, Href: link},
+			Task:     task.ID,
+			ShadowOf: task.ShadowOf,
+			Records:  make([]client.ShadowRecord, len(records)),
+		}
+		for i, r := range records {
+			l.Records[i] = convertShadowRecord(r)
+		}
+		w.Write(httpd.MarshalJSON(l, true))
+	case path.Join(shadowPath, comparePath):
+		if task.ShadowOf == "" {
+			httpd.HttpError(w, fmt.Sprintf("task %s is not a shadow task", task.ID), true, http.StatusBadRequest)
+			return
+		}
+		shadow, err := ts.shadowLog.List(task.ID, start, stop)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("failed to get shadow log: %s", err.Error()), true, http.StatusInternalServerError)
+			return
+		}
+		primary, err := ts.shadowLog.List(task.ShadowOf, start, stop)
+		if err != nil {
+			httpd.HttpError(w, fmt.Sprintf("failed to get shadow log of task %s: %s", task.ShadowOf, err.Error()), true, http.StatusInternalServerError)
+			return
+		}
+		c := compareShadowAlerts(primary, shadow)
+		c.Link = client.Link{Relation: client.Self, Href: path.Join(link, comparePath)}
+		c.Task = task.ID
+		c.ShadowOf = task.ShadowOf
+		w.Write(httpd.MarshalJSON(c, true))
+	default:
+		httpd.HttpError(w, fmt.Sprintf("unknown path %q", r.URL.Path), true, http.StatusNotFound)
+	}
+}
+
+// compareShadowAlerts groups the alert records of a task and of its shadow by alert ID.
+// The alerts of an ID match if both tasks changed them to the same sequence of levels.
+func compareShadowAlerts(primary, shadow []ShadowRecord) client.ShadowComparison {
+	alerts := make(map[string]*client.ShadowAlertComparison)
+	add := func(records []ShadowRecord, isShadow bool) {
+		for _, r := range records {
+			if r.Type != string(kapacitor.ShadowAlert) {
+				continue
+			}
+			a, ok := alerts[r.ID]
+			if !ok {
+				a = &client.ShadowAlertComparison{
+					ID:      r.ID,
+					Primary: []client.ShadowAlertEvent{},
+					Shadow:  []client.ShadowAlertEvent{},
+				}
+				alerts[r.ID] = a
+			}
+			e := client.ShadowAlertEvent{Time: r.Time, Level: r.Level}
+			if isShadow {
+				a.Shadow = append(a.Shadow, e)
+			} else {
+				a.Primary = append(a.Primary, e)
+			}
+		}
+	}
+	add(primary, false)
+	add(shadow, true)
+
+	c := client.ShadowComparison{
+		Alerts: make([]client.ShadowAlertComparison, 0, len(alerts)),
+	}
+	for _, a := range alerts {
+		switch {
+		case len(a.Shadow) == 0:
+			a.Status = client.ShadowAlertPrimaryOnly
+			c.Summary.PrimaryOnly++
+		case len(a.Primary) == 0:
+			a.Status = client.ShadowAlertShadowOnly
+			c.Summary.ShadowOnly++
+		case sameLevels(a.Primary, a.Shadow):
+			a.Status = client.ShadowAlertMatch
+			c.Summary.Match++
+		default:
+			a.Status = client.ShadowAlertDifferent
+			c.Summary.Different++
+		}
+		c.Alerts = append(c.Alerts, *a)
+	}
+	sort.Slice(c.Alerts, func(i, j int) bool {
+		return c.Alerts[i].ID < c.Alerts[j].ID
+	})
+	return c
+}
+
+func sameLevels(a, b []client.ShadowAlertEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Level != b[i].Level {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,319 @@
+package task_store
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/alert"
+	"github.com/influxdata/kapacitor/auth"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+)
+
+// alertService collects the events of tasks in place of the alert service.
+type alertService struct {
+	mu     sync.Mutex
+	events []alert.Event
+}
+
+func (s *alertService) Collect(event alert.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *alertService) collected() []alert.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]alert.Event(nil), s.events...)
+}
+
+func (*alertService) RegisterAnonHandler(string, alert.Handler)   {}
+func (*alertService) DeregisterAnonHandler(string, alert.Handler) {}
+func (*alertService) UpdateEvent(string, alert.EventState) error  { return nil }
+func (*alertService) EventState(string, string) (alert.EventState, bool, error) {
+	return alert.EventState{}, false, nil
+}
+func (*alertService) CloseTopic(string) error              { return nil }
+func (*alertService) DeleteTopic(string) error             { return nil }
+func (*alertService) RestoreTopic(string) error            { return nil }
+func (*alertService) IsInhibited(string, models.Tags) bool { return false }
+func (*alertService) AddInhibitor(*alert.Inhibitor)        {}
+func (*alertService) RemoveInhibitor(*alert.Inhibitor)     {}
+
+func TestService_ShadowTask(t *testing.T) {
+	ts := newTestService(t)
+	tm := ts.TaskMasterLookup.Main()
+	as := &alertService{}
+	tm.AlertService = as
+	tm.ShadowLog = ts
+	user := auth.AdminUser
+
+	const (
+		primary = `stream|from().measurement('cpu').groupBy('host')|alert().id('cpu:{{ index .Tags "host" }}').crit(lambda: "value" > 90).topic('ops')`
+		shadow  = `var data = stream|from().measurement('cpu').groupBy('host')
+data|alert().id('cpu:{{ index .Tags "host" }}').crit(lambda: "value" > 80).topic('ops')
+data|kapacitorLoopback().database('other').retentionPolicy('autogen').measurement('cpu_copy')`
+	)
+	dbrps := []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}}
+	create := func(o client.CreateTaskOptions, result interface{}) *httptest.ResponseRecorder {
+		o.DBRPs = dbrps
+		body, _ := json.Marshal(o)
+		return do(t, ts.handleCreateTask, user, "POST", "/tasks", string(body), result)
+	}
+	if w := create(client.CreateTaskOptions{ID: "cpu", TICKscript: primary, Status: client.Enabled}, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if w := create(client.CreateTaskOptions{ID: "other", TICKscript: primary, ShadowOf: "missing"}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status shadowing a missing task: %d", w.Code)
+	}
+	var task client.Task
+	if w := create(client.CreateTaskOptions{ID: "cpu_v2", TICKscript: shadow, Status: client.Enabled, ShadowOf: "cpu"}, &task); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if task.ShadowOf != "cpu" || !task.Executing {
+		t.Errorf("unexpected shadow task: %+v", task)
+	}
+	if w := create(client.CreateTaskOptions{ID: "cpu_v3", TICKscript: shadow, ShadowOf: "cpu_v2"}, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status shadowing a shadow task: %d", w.Code)
+	}
+	if !tm.IsShadowed("cpu") {
+		t.Fatal("expected task to be shadowed")
+	}
+
+	stream, err := tm.Stream("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	for i, p := range []struct {
+		host  string
+		value float64
+	}{{"a", 95}, {"b", 85}} {
+		pt := edge.NewPointMessage("cpu", "telegraf", "autogen", models.Dimensions{}, models.Fields{"value": p.value}, models.Tags{"host": p.host}, time.Unix(int64(10+i), 0).UTC())
+		if err := stream.CollectPoint(pt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wait for both tasks to process the points.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		primaryLog, err := ts.shadowLog.List("cpu", time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		shadowLog, err := ts.shadowLog.List("cpu_v2", time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(primaryLog) == 1 && len(shadowLog) == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for shadow records: primary %+v shadow %+v", primaryLog, shadowLog)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Only the primary task sends its alerts.
+	events := as.collected()
+	if len(events) != 1 || events[0].State.ID != "cpu:a" || events[0].Topic != "ops" {
+		t.Errorf("unexpected collected events: %+v", events)
+	}
+
+	var l client.ShadowLog
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/cpu_v2/shadow", "", &l); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	types := make(map[string]int)
+	for _, r := range l.Records {
+		types[r.Type]++
+	}
+	if exp := map[string]int{"alert": 2, "loopback": 2}; !reflect.DeepEqual(types, exp) {
+		t.Errorf("unexpected shadow records: got %v exp %v", types, exp)
+	}
+	if l.ShadowOf != "cpu" || l.Link.Href != "/kapacitor/v1/tasks/cpu_v2/shadow" {
+		t.Errorf("unexpected shadow log: %+v", l)
+	}
+
+	var c client.ShadowComparison
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/cpu_v2/shadow/compare?start=1970-01-01T00:00:11Z", "", &c); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if exp := (client.ShadowComparisonSummary{ShadowOnly: 1}); c.Summary != exp {
+		t.Errorf("unexpected summary for range: got %+v exp %+v", c.Summary, exp)
+	}
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/cpu_v2/shadow/compare", "", &c); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if exp := (client.ShadowComparisonSummary{Match: 1, ShadowOnly: 1}); c.Summary != exp {
+		t.Errorf("unexpected summary: got %+v exp %+v", c.Summary, exp)
+	}
+	if len(c.Alerts) != 2 || c.Alerts[0].ID != "cpu:a" || c.Alerts[0].Status != client.ShadowAlertMatch || c.Alerts[1].Status != client.ShadowAlertShadowOnly {
+		t.Errorf("unexpected alerts: %+v", c.Alerts)
+	}
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/cpu/shadow/compare", "", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status comparing a task that is not a shadow task: %d", w.Code)
+	}
+	if w := do(t, ts.handleTask, auth.User{}, "GET", "/tasks/cpu_v2/shadow?start=yesterday", "", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status with invalid start: %d", w.Code)
+	}
+
+	// Deleting the shadow task stops recording the alerts of its primary.
+	if err := ts.deleteTask("cpu_v2"); err != nil {
+		t.Fatal(err)
+	}
+	if tm.IsShadowed("cpu") {
+		t.Error("expected task not to be shadowed after deleting its shadow")
+	}
+	if records, err := ts.shadowLog.List("cpu_v2", time.Time{}, time.Time{}); err != nil || len(records) != 0 {
+		t.Errorf("unexpected shadow log after delete: %v %v", records, err)
+	}
+}
+
+func TestCompareShadowAlerts(t *testing.T) {
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec := func(id, level string, sec int) ShadowRecord {
+		return ShadowRecord{Type: "alert", ID: id, Level: level, Time: t0.Add(time.Duration(sec) * time.Second)}
+	}
+	primary := []ShadowRecord{
+		rec("a", "CRITICAL", 0),
+		rec("b", "WARNING", 0),
+		rec("c", "CRITICAL", 1),
+		rec("a", "OK", 2),
+	}
+	shadow := []ShadowRecord{
+		rec("a", "CRITICAL", 0),
+		rec("b", "CRITICAL", 0),
+		{Type: "influxdb", Time: t0, Points: 1},
+		rec("d", "WARNING", 1),
+		rec("a", "OK", 3),
+	}
+	c := compareShadowAlerts(primary, shadow)
+	if exp := (client.ShadowComparisonSummary{Match: 1, Different: 1, PrimaryOnly: 1, ShadowOnly: 1}); c.Summary != exp {
+		t.Errorf("unexpected summary: got %+v exp %+v", c.Summary, exp)
+	}
+	var statuses []client.ShadowAlertStatus
+	for _, a := range c.Alerts {
+		statuses = append(statuses, a.Status)
+	}
+	exp := []client.ShadowAlertStatus{
+		client.ShadowAlertMatch,
+		client.ShadowAlertDifferent,
+		client.ShadowAlertPrimaryOnly,
+		client.ShadowAlertShadowOnly,
+	}
+	if !reflect.DeepEqual(statuses, exp) {
+		t.Errorf("unexpected statuses: got %v exp %v", statuses, exp)
+	}
+	if got := c.Alerts[0].Shadow[1]; got.Level != "OK" || !got.Time.Equal(t0.Add(3*time.Second)) {
+		t.Errorf("unexpected shadow event: %+v", got)
+	}
+}
+
+func TestService_ShadowTaskRenameDelete(t *testing.T) {
+	ts := newTestService(t)
+	tm := ts.TaskMasterLookup.Main()
+	tm.AlertService = &alertService{}
+	user := auth.AdminUser
+
+	const script = `stream|from().measurement('cpu')|alert().crit(lambda: "value" > 90)`
+	for _, o := range []client.CreateTaskOptions{
+		{ID: "cpu", TICKscript: script, Status: client.Enabled},
+		{ID: "cpu_v2", TICKscript: script, Status: client.Enabled, ShadowOf: "cpu"},
+	} {
+		o.DBRPs = []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}}
+		body, _ := json.Marshal(o)
+		if w := do(t, ts.handleCreateTask, user, "POST", "/tasks", string(body), nil); w.Code != http.StatusOK {
+			t.Fatal(w.Body.String())
+		}
+	}
+	for _, id := range []string{"cpu", "cpu_v2"} {
+		if err := ts.shadowLog.Append(id, ShadowRecord{Type: "alert", ID: id, Time: time.Unix(10, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Renaming a task moves its shadow log and the shadows follow it.
+	if w := do(t, ts.handleUpdateTask, user, "PATCH", "/tasks/cpu", `{"id":"cpu_v1"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if records, err := ts.shadowLog.List("cpu_v1", time.Time{}, time.Time{}); err != nil || len(records) != 1 || records[0].ID != "cpu" {
+		t.Errorf("unexpected shadow log after rename: %v %v", records, err)
+	}
+	if records, err := ts.shadowLog.List("cpu", time.Time{}, time.Time{}); err != nil || len(records) != 0 {
+		t.Errorf("unexpected shadow log of old ID after rename: %v %v", records, err)
+	}
+	shadow, err := ts.tasks.Get("cpu_v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shadow.ShadowOf != "cpu_v1" {
+		t.Errorf("unexpected shadowed task after rename: %q", shadow.ShadowOf)
+	}
+	if !tm.IsShadowed("cpu_v1") || tm.IsShadowed("cpu") {
+		t.Error("expected the renamed task to be shadowed")
+	}
+
+	// Renaming a shadow moves its shadow log.
+	if w := do(t, ts.handleUpdateTask, user, "PATCH", "/tasks/cpu_v2", `{"id":"cpu_v3"}`, nil); w.Code != http.StatusOK {
+		t.Fatal(w.Body.String())
+	}
+	if records, err := ts.shadowLog.List("cpu_v3", time.Time{}, time.Time{}); err != nil || len(records) != 1 || records[0].ID != "cpu_v2" {
+		t.Errorf("unexpected shadow log after renaming the shadow: %v %v", records, err)
+	}
+
+	// A task cannot be deleted while it is shadowed.
+	if w := do(t, ts.handleDeleteTask, user, "DELETE", "/tasks/cpu_v1", "", nil); w.Code != http.StatusConflict {
+		t.Errorf("unexpected status deleting a shadowed task: %d %s", w.Code, w.Body.String())
+	}
+	if _, err := ts.tasks.Get("cpu_v1"); err != nil {
+		t.Errorf("expected shadowed task to exist: %v", err)
+	}
+	if w := do(t, ts.handleDeleteTask, user, "DELETE", "/tasks/cpu_v3", "", nil); w.Code != http.StatusNoContent {
+		t.Fatal(w.Body.String())
+	}
+	if w := do(t, ts.handleDeleteTask, user, "DELETE", "/tasks/cpu_v1", "", nil); w.Code != http.StatusNoContent {
+		t.Fatal(w.Body.String())
+	}
+}
+
+func TestService_RecordShadowQueue(t *testing.T) {
+	ts := newTestService(t)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := ts.RecordShadow("cpu", kapacitor.ShadowRecord{Type: kapacitor.ShadowAlert, Node: "alert2", Time: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Closing writes the records still queued.
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ts.shadowLog.List("cpu", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("unexpected number of records after close: got %d exp 3", len(records))
+	}
+
+	// Records are dropped while nothing writes the full queue.
+	for i := 0; i < shadowQueueSize; i++ {
+		if err := ts.RecordShadow("cpu", kapacitor.ShadowRecord{Type: kapacitor.ShadowAlert, Time: start}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.RecordShadow("cpu", kapacitor.ShadowRecord{Type: kapacitor.ShadowAlert, Time: start}); err != ErrShadowQueueFull {
+		t.Errorf("unexpected error recording to a full queue: got %v exp %v", err, ErrShadowQueueFull)
+	}
+}
@@ -24,6 +24,17 @@ func newProgramNodeFromTickscript(tickscript string) (*ast.ProgramNode, error) {
 	return pn, nil
 }
 
+// importsFromProgram returns the IDs of the libraries the program imports.
+func importsFromProgram(n *ast.ProgramNode) []string {
+	var imports []string
+	for _, nn := range n.Nodes {
+		if i, ok := nn.(*ast.ImportNode); ok {
+			imports = append(imports, i.Library.Literal)
+		}
+	}
+	return imports
+}
+
 func dbrpsFromProgram(n *ast.ProgramNode) []client.DBRP {
 	var dbrps []client.DBRP
 	for _, nn := range n.Nodes {
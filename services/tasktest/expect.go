@@ -0,0 +1,139 @@
+package tasktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	imodels "github.com/influxdata/influxdb/models"
+	client "github.com/influxdata/kapacitor/client/v1"
+)
+
+// checkExpectations compares the output of a test with its expectations and describes each difference.
+func checkExpectations(expect client.TaskTestExpectations, out *output) []string {
+	var failures []string
+	if expect.Alerts != nil {
+		failures = append(failures, checkAlerts(expect.Alerts, out.alerts)...)
+	}
+	failures = append(failures, checkHTTPOut(expect.HTTPOut, out.httpOut)...)
+	if expect.Writes != nil {
+		failures = append(failures, checkWrites(expect.Writes, out.writes, out.precision)...)
+	}
+	return failures
+}
+
+// checkAlerts compares the alerts in order, only the properties set on the expected alerts are compared.
+func checkAlerts(expected, got []client.TaskTestAlert) []string {
+	var failures []string
+	if len(expected) != len(got) {
+		failures = append(failures, fmt.Sprintf("expected %d alerts, got %d", len(expected), len(got)))
+	}
+	for i := 0; i < len(expected) && i < len(got); i++ {
+		e, g := expected[i], got[i]
+		if e.ID != "" && e.ID != g.ID {
+			failures = append(failures, fmt.Sprintf("alert %d: expected ID %q, got %q", i, e.ID, g.ID))
+		}
+		if e.Level != "" && !strings.EqualFold(e.Level, g.Level) {
+			failures = append(failures, fmt.Sprintf("alert %d: expected level %s, got %s", i, strings.ToUpper(e.Level), g.Level))
+		}
+		if e.Message != "" && e.Message != g.Message {
+			failures = append(failures, fmt.Sprintf("alert %d: expected message %q, got %q", i, e.Message, g.Message))
+		}
+		if !e.Time.IsZero() && !e.Time.Equal(g.Time) {
+			failures = append(failures, fmt.Sprintf("alert %d: expected time %v, got %v", i, e.Time.UTC(), g.Time))
+		}
+	}
+	return failures
+}
+
+// checkHTTPOut compares the results of the httpOut endpoints as JSON values.
+func checkHTTPOut(expected, got map[string]json.RawMessage) []string {
+	endpoints := make([]string, 0, len(expected))
+	for endpoint := range expected {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	var failures []string
+	for _, endpoint := range endpoints {
+		result, ok := got[endpoint]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("httpOut %q: no such endpoint", endpoint))
+			continue
+		}
+		var e, g interface{}
+		if err := json.Unmarshal(expected[endpoint], &e); err != nil {
+			failures = append(failures, fmt.Sprintf("httpOut %q: invalid expected result: %v", endpoint, err))
+			continue
+		}
+		if err := json.Unmarshal(result, &g); err != nil {
+			failures = append(failures, fmt.Sprintf("httpOut %q: invalid result: %v", endpoint, err))
+			continue
+		}
+		if !reflect.DeepEqual(e, g) {
+			failures = append(failures, fmt.Sprintf("httpOut %q: expected %s, got %s", endpoint, compact(expected[endpoint]), compact(result)))
+		}
+	}
+	return failures
+}
+
+func compact(data json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+// checkWrites compares the written points in any order.
+// The expected points are normalized so that the order of their tags and fields does not matter.
+func checkWrites(expected, got []string, precision string) []string {
+	var failures []string
+	remaining := make(map[string]int, len(got))
+	for _, line := range got {
+		remaining[line]++
+	}
+	for _, line := range expected {
+		normalized, err := normalizeLine(line, precision)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("invalid expected write %q: %v", line, err))
+			continue
+		}
+		if remaining[normalized] == 0 {
+			failures = append(failures, fmt.Sprintf("missing write %q", normalized))
+			continue
+		}
+		remaining[normalized]--
+	}
+	for _, line := range got {
+		if remaining[line] > 0 {
+			failures = append(failures, fmt.Sprintf("unexpected write %q", line))
+			remaining[line]--
+		}
+	}
+	return failures
+}
+
+func normalizeLine(line, precision string) (string, error) {
+	points, err := imodels.ParsePointsWithPrecision([]byte(strings.TrimSpace(line)), time.Time{}, precision)
+	if err != nil {
+		return "", err
+	}
+	if len(points) != 1 {
+		return "", fmt.Errorf("expected a single point, got %d", len(points))
+	}
+	p := points[0]
+	fields, err := p.Fields()
+	if err != nil {
+		return "", err
+	}
+	normalized, err := imodels.NewPoint(string(p.Name()), p.Tags(), fields, p.Time())
+	if err != nil {
+		return "", err
+	}
+	return normalized.PrecisionString(precision), nil
+}
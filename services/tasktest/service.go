@@ -0,0 +1,252 @@
+// Package tasktest runs TICKscript unit tests.
+//
+// Each test runs its script in an isolated task master with a fast clock.
+// The input data is replayed into the task and the alerts, httpOut results and influxDBOut writes
+// are captured by in-memory stand-ins and checked against the expectations of the test.
+package tasktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/kapacitor"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/clock"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	taskTestsPath = "/task-tests"
+
+	// ID of the task under test
+	testTaskID = "test"
+)
+
+var validPrecision = regexp.MustCompile(`^(n|ns|u|ms|s|m|h)?$`)
+
+type Service struct {
+	routes []httpd.Route
+
+	HTTPDService interface {
+		AddRoutes([]httpd.Route) error
+		DelRoutes([]httpd.Route)
+	}
+	TaskMaster interface {
+		New(name string) *kapacitor.TaskMaster
+	}
+}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Open() error {
+	s.routes = []httpd.Route{
+		{
+			Method:      "POST",
+			Pattern:     taskTestsPath,
+			HandlerFunc: s.handleRunTest,
+		},
+	}
+	return s.HTTPDService.AddRoutes(s.routes)
+}
+
+func (s *Service) Close() error {
+	if s.HTTPDService != nil {
+		s.HTTPDService.DelRoutes(s.routes)
+	}
+	return nil
+}
+
+func (s *Service) handleRunTest(w http.ResponseWriter, r *http.Request) {
+	var opt client.TaskTestOptions
+	if err := json.NewDecoder(r.Body).Decode(&opt); err != nil {
+		httpd.HttpError(w, "invalid JSON: "+err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	if err := validate(opt); err != nil {
+		httpd.HttpError(w, err.Error(), true, http.StatusBadRequest)
+		return
+	}
+	result := s.Run(opt)
+	w.Write(httpd.MarshalJSON(result, true))
+}
+
+func validate(opt client.TaskTestOptions) error {
+	if opt.TICKscript == "" {
+		return errors.New("must provide a TICKscript")
+	}
+	if len(opt.DBRPs) == 0 {
+		return errors.New("must provide at least one DBRP")
+	}
+	if !validPrecision.MatchString(opt.Precision) {
+		return fmt.Errorf("invalid precision %q", opt.Precision)
+	}
+	switch opt.Type {
+	case client.StreamTask:
+		if len(opt.Batches) > 0 {
+			return errors.New("stream tasks read their input from data, not batches")
+		}
+	case client.BatchTask:
+		if opt.Data != "" {
+			return errors.New("batch tasks read their input from batches, not data")
+		}
+	default:
+		return fmt.Errorf("invalid task type %v", opt.Type)
+	}
+	return nil
+}
+
+// Run runs the test and checks its expectations.
+func (s *Service) Run(opt client.TaskTestOptions) client.TaskTestResult {
+	result := client.TaskTestResult{
+		Name: opt.Name,
+	}
+	out, err := s.run(opt)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Alerts = out.alerts
+	result.HTTPOut = out.httpOut
+	result.Writes = out.writes
+	result.ExecutionStats = out.stats
+	result.Failures = checkExpectations(opt.Expect, out)
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// output is everything captured while running a test.
+type output struct {
+	alerts    []client.TaskTestAlert
+	httpOut   map[string]json.RawMessage
+	writes    []string
+	precision string
+	stats     client.ExecutionStats
+}
+
+func (s *Service) run(opt client.TaskTestOptions) (*output, error) {
+	dbrps := make([]kapacitor.DBRP, len(opt.DBRPs))
+	for i, dbrp := range opt.DBRPs {
+		dbrps[i] = kapacitor.DBRP{
+			Database:        dbrp.Database,
+			RetentionPolicy: dbrp.RetentionPolicy,
+		}
+	}
+	var tt kapacitor.TaskType
+	switch opt.Type {
+	case client.StreamTask:
+		tt = kapacitor.StreamTask
+	case client.BatchTask:
+		tt = kapacitor.BatchTask
+	}
+
+	// Create new isolated task master with in-memory stand-ins for the outputs.
+	tm := s.TaskMaster.New("task-test:" + uuid.New().String())
+	outputs := Isolate(tm, opt.Precision)
+
+	task, err := tm.NewTask(testTaskID, opt.TICKscript, tt, dbrps, 0, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid TICKscript")
+	}
+
+	tm.Open()
+	defer tm.Close()
+	et, err := tm.StartTask(task)
+	if err != nil {
+		return nil, errors.Wrap(err, "task start")
+	}
+	// This will force the task to stop or do nothing if it already stopped.
+	defer func() {
+		for _, b := range tm.BatchCollectors(task.ID) {
+			b.Close()
+		}
+		tm.StopTasks()
+	}()
+
+	// Run the input through the task
+	clk := clock.Fast()
+	var replayC <-chan error
+	switch tt {
+	case kapacitor.StreamTask:
+		stream, err := tm.Stream(testTaskID)
+		if err != nil {
+			return nil, errors.Wrap(err, "stream start")
+		}
+		data := recordingData(dbrps[0], opt.Data)
+		replayC = kapacitor.ReplayStreamFromIO(clk, data, stream, true, opt.Precision)
+	case kapacitor.BatchTask:
+		collectors := tm.BatchCollectors(task.ID)
+		if len(opt.Batches) > len(collectors) {
+			return nil, fmt.Errorf("got batches for %d queries but the task has %d", len(opt.Batches), len(collectors))
+		}
+		data := make([]io.ReadCloser, len(collectors))
+		for i := range data {
+			var batches []json.RawMessage
+			if i < len(opt.Batches) {
+				batches = opt.Batches[i]
+			}
+			data[i] = batchData(batches)
+		}
+		replayC = kapacitor.ReplayBatchFromIO(clk, data, collectors, true)
+	}
+	if err := <-replayC; err != nil {
+		return nil, errors.Wrap(err, "invalid input data")
+	}
+
+	stats, err := tm.ExecutionStats(task.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting execution stats")
+	}
+
+	// Drain tm so the task can finish
+	tm.Drain()
+	et.StopStats()
+	if err := et.Wait(); err != nil {
+		return nil, errors.Wrap(err, "task run")
+	}
+
+	// httpOut results must be read before the task stops and removes its routes,
+	// influxDBOut writes are flushed once the task stops.
+	out := &output{
+		httpOut:   outputs.HTTPOut(testTaskID),
+		precision: opt.Precision,
+		stats: client.ExecutionStats{
+			TaskStats: stats.TaskStats,
+			NodeStats: stats.NodeStats,
+		},
+	}
+	tm.StopTasks()
+	out.alerts = outputs.Alerts()
+	out.writes = outputs.Writes()
+	return out, nil
+}
+
+// recordingData converts line protocol into the stream recording format, which prefixes each line with its database and retention policy.
+func recordingData(dbrp kapacitor.DBRP, data string) io.ReadCloser {
+	var buf strings.Builder
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s\n%s\n%s\n", dbrp.Database, dbrp.RetentionPolicy, line)
+	}
+	return io.NopCloser(strings.NewReader(buf.String()))
+}
+
+// batchData converts a list of batches into the batch recording format.
+func batchData(batches []json.RawMessage) io.ReadCloser {
+	var buf strings.Builder
+	for _, b := range batches {
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return io.NopCloser(strings.NewReader(buf.String()))
+}
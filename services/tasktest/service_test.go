@@ -0,0 +1,263 @@
+package tasktest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/alert"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/keyvalue"
+	"github.com/influxdata/kapacitor/server/vars"
+	"github.com/influxdata/kapacitor/services/diagnostic"
+	"github.com/influxdata/kapacitor/services/httppost"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deadman struct{}
+
+func (deadman) Interval() time.Duration { return 10 * time.Second }
+func (deadman) Threshold() float64      { return 0 }
+func (deadman) Id() string              { return "" }
+func (deadman) Message() string         { return "" }
+func (deadman) Global() bool            { return false }
+
+func newService(t *testing.T) *Service {
+	t.Helper()
+	diag := diagnostic.NewService(diagnostic.NewConfig(), io.Discard, io.Discard)
+	require.NoError(t, diag.Open())
+	t.Cleanup(func() { diag.Close() })
+	tm := kapacitor.NewTaskMaster("main", vars.Info, diag.NewKapacitorHandler())
+	tm.DeadmanService = deadman{}
+	s := NewService()
+	s.TaskMaster = tm
+	return s
+}
+
+const streamScript = `
+var data = stream
+    |from()
+        .measurement('cpu')
+
+data
+    |alert()
+        .crit(lambda: "value" > 90)
+        .id('{{ index .Tags "host" }}')
+        .message('{{ .ID }} is {{ .Level }}')
+        .topic('cpu')
+
+data
+    |window()
+        .period(10s)
+        .every(10s)
+    |max('value')
+    |httpOut('max')
+
+data
+    |influxDBOut()
+        .database('out')
+        .measurement('cpu_copy')
+`
+
+func streamTest() client.TaskTestOptions {
+	return client.TaskTestOptions{
+		Name:       "cpu",
+		Type:       client.StreamTask,
+		DBRPs:      []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}},
+		TICKscript: streamScript,
+		Precision:  "s",
+		Data: `
+cpu,host=a value=50 0
+cpu,host=a value=95 5
+cpu,host=a value=60 10
+cpu,host=a value=70 20
+`,
+	}
+}
+
+func TestService_Run_Stream(t *testing.T) {
+	s := newService(t)
+	opt := streamTest()
+	opt.Expect = client.TaskTestExpectations{
+		Alerts: []client.TaskTestAlert{
+			{ID: "a", Level: "critical", Message: "a is CRITICAL", Time: time.Unix(5, 0)},
+			{Level: "OK", Time: time.Unix(10, 0)},
+		},
+		HTTPOut: map[string]json.RawMessage{
+			"max": json.RawMessage(`{"series":[{"name":"cpu","tags":{"host":"a"},"columns":["time","max"],"values":[["1970-01-01T00:00:20Z",60]]}]}`),
+		},
+		Writes: []string{
+			"cpu_copy,host=a value=50 0",
+			"cpu_copy,host=a value=95 5",
+			"cpu_copy,host=a value=60 10",
+			"cpu_copy,host=a value=70 20",
+		},
+	}
+	result := s.Run(opt)
+	require.Empty(t, result.Error)
+	assert.Empty(t, result.Failures)
+	assert.True(t, result.Passed)
+	assert.Equal(t, "cpu", result.Name)
+}
+
+func TestService_Run_Failures(t *testing.T) {
+	s := newService(t)
+	opt := streamTest()
+	opt.Expect = client.TaskTestExpectations{
+		Alerts: []client.TaskTestAlert{
+			{Level: "WARNING"},
+		},
+		HTTPOut: map[string]json.RawMessage{
+			"missing": json.RawMessage(`{}`),
+		},
+		Writes: []string{
+			"cpu_copy,host=a value=50 0",
+			"cpu_copy,host=b value=1 0",
+		},
+	}
+	result := s.Run(opt)
+	require.Empty(t, result.Error)
+	assert.False(t, result.Passed)
+	assert.Equal(t, []string{
+		"expected 1 alerts, got 2",
+		"alert 0: expected level WARNING, got CRITICAL",
+		`httpOut "missing": no such endpoint`,
+		`missing write "cpu_copy,host=b value=1 0"`,
+		`unexpected write "cpu_copy,host=a value=60 10"`,
+		`unexpected write "cpu_copy,host=a value=70 20"`,
+		`unexpected write "cpu_copy,host=a value=95 5"`,
+	}, result.Failures)
+}
+
+func TestService_Run_Batch(t *testing.T) {
+	s := newService(t)
+	opt := client.TaskTestOptions{
+		Type:  client.BatchTask,
+		DBRPs: []client.DBRP{{Database: "telegraf", RetentionPolicy: "autogen"}},
+		TICKscript: `
+batch
+    |query('SELECT mean("value") FROM "telegraf"."autogen"."cpu"')
+        .period(10s)
+        .every(10s)
+        .groupBy('host')
+    |alert()
+        .warn(lambda: "mean" > 50)
+        .topic('cpu')
+`,
+		Batches: [][]json.RawMessage{{
+			json.RawMessage(`{"name":"cpu","tmax":"1970-01-01T00:00:10Z","tags":{"host":"a"},"points":[{"fields":{"mean":20},"tags":{"host":"a"},"time":"1970-01-01T00:00:10Z"}]}`),
+			json.RawMessage(`{"name":"cpu","tmax":"1970-01-01T00:00:20Z","tags":{"host":"a"},"points":[{"fields":{"mean":60},"tags":{"host":"a"},"time":"1970-01-01T00:00:20Z"}]}`),
+		}},
+		Expect: client.TaskTestExpectations{
+			Alerts: []client.TaskTestAlert{
+				{ID: "cpu:host=a", Level: "WARNING", Time: time.Unix(20, 0)},
+			},
+			Writes: []string{},
+		},
+	}
+	result := s.Run(opt)
+	require.Empty(t, result.Error)
+	assert.Empty(t, result.Failures)
+	assert.True(t, result.Passed)
+}
+
+type httpPostEndpoints map[string]*httppost.Endpoint
+
+func (e httpPostEndpoints) Handler(httppost.HandlerConfig, ...keyvalue.T) (alert.Handler, error) {
+	return nil, errors.New("unexpected alert handler")
+}
+
+func (e httpPostEndpoints) Endpoint(name string) (*httppost.Endpoint, bool) {
+	endpoint, ok := e[name]
+	return endpoint, ok
+}
+
+func TestService_Run_HTTPPost(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer ts.Close()
+
+	url, err := httppost.GetTemplate(ts.URL, "")
+	require.NoError(t, err)
+	s := newService(t)
+	s.TaskMaster.(*kapacitor.TaskMaster).HTTPPostService = httpPostEndpoints{
+		"test": httppost.NewEndpoint(url, nil, httppost.BasicAuth{}, nil, nil),
+	}
+	opt := streamTest()
+	opt.TICKscript = `
+var data = stream
+    |from()
+        .measurement('cpu')
+
+data
+    |httpPost('` + ts.URL + `')
+        .codeField('code')
+    |influxDBOut()
+        .database('out')
+        .measurement('cpu_code')
+
+data
+    |httpPost()
+        .endpoint('test')
+
+data
+    |alert()
+        .crit(lambda: "value" > 90)
+        .post('` + ts.URL + `')
+`
+	opt.Expect = client.TaskTestExpectations{
+		Writes: []string{
+			"cpu_code,host=a code=204i,value=50 0",
+			"cpu_code,host=a code=204i,value=95 5",
+			"cpu_code,host=a code=204i,value=60 10",
+			"cpu_code,host=a code=204i,value=70 20",
+		},
+	}
+	result := s.Run(opt)
+	require.Empty(t, result.Error)
+	assert.Empty(t, result.Failures)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+}
+
+func TestService_Run_InvalidScript(t *testing.T) {
+	s := newService(t)
+	opt := streamTest()
+	opt.TICKscript = `stream|from()|nosuchnode()`
+	result := s.Run(opt)
+	assert.Contains(t, result.Error, "invalid TICKscript")
+	assert.False(t, result.Passed)
+}
+
+func TestValidate(t *testing.T) {
+	valid := streamTest()
+	assert.NoError(t, validate(valid))
+
+	noScript := streamTest()
+	noScript.TICKscript = ""
+	assert.Error(t, validate(noScript))
+
+	noDBRPs := streamTest()
+	noDBRPs.DBRPs = nil
+	assert.Error(t, validate(noDBRPs))
+
+	badPrecision := streamTest()
+	badPrecision.Precision = "d"
+	assert.Error(t, validate(badPrecision))
+
+	streamBatches := streamTest()
+	streamBatches.Batches = [][]json.RawMessage{{json.RawMessage(`{}`)}}
+	assert.Error(t, validate(streamBatches))
+
+	noType := streamTest()
+	noType.Type = client.InvalidTask
+	assert.Error(t, validate(noType))
+}
@@ -0,0 +1,379 @@
+package tasktest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	imodels "github.com/influxdata/influxdb/models"
+	"github.com/influxdata/kapacitor"
+	"github.com/influxdata/kapacitor/alert"
+	client "github.com/influxdata/kapacitor/client/v1"
+	"github.com/influxdata/kapacitor/influxdb"
+	"github.com/influxdata/kapacitor/keyvalue"
+	ec2 "github.com/influxdata/kapacitor/services/ec2/client"
+	"github.com/influxdata/kapacitor/services/httpd"
+	"github.com/influxdata/kapacitor/services/httppost"
+	k8s "github.com/influxdata/kapacitor/services/k8s/client"
+	swarm "github.com/influxdata/kapacitor/services/swarm/client"
+	"github.com/pkg/errors"
+)
+
+// Outputs records the outputs of the tasks of an isolated task master.
+type Outputs struct {
+	alerts *alertRecorder
+	routes *routeRecorder
+	writes *writeRecorder
+}
+
+// Isolate replaces the services through which the tasks of tm have side effects with in-memory stand-ins.
+// Alerts are recorded instead of handled, httpOut endpoints are not served,
+// influxDBOut writes are recorded in line protocol with the given precision,
+// httpPost requests are discarded, autoscale nodes and SQL queries are not supported,
+// and snapshots are neither loaded nor saved.
+func Isolate(tm *kapacitor.TaskMaster, precision string) *Outputs {
+	o := &Outputs{
+		alerts: newAlertRecorder(),
+		routes: newRouteRecorder(),
+		writes: newWriteRecorder(precision),
+	}
+	tm.AlertService = o.alerts
+	tm.HTTPDService = o.routes
+	tm.InfluxDBService = o.writes
+	tm.HTTPPostService = noHTTPPost{httpPostService: tm.HTTPPostService}
+	tm.HTTPPostClient = discardClient{}
+	tm.K8sService = noK8s{}
+	tm.SwarmService = noSwarm{}
+	tm.EC2Service = noEC2{}
+	tm.SQLService = noSQL{}
+	tm.TaskStore = noSnapshots{taskStore: tm.TaskStore}
+	return o
+}
+
+// Alerts returns the recorded alert events in order.
+func (o *Outputs) Alerts() []client.TaskTestAlert {
+	return o.alerts.alerts()
+}
+
+// HTTPOut returns the current result of each httpOut endpoint of a task.
+// The endpoints are removed once the task stops.
+func (o *Outputs) HTTPOut(taskID string) map[string]json.RawMessage {
+	return o.routes.results(taskID)
+}
+
+// Writes returns the recorded writes sorted in line protocol.
+// Writes are flushed once the task stops.
+func (o *Outputs) Writes() []string {
+	return o.writes.lines()
+}
+
+// alertRecorder stands in for the alert service.
+// It records the events of the task and never calls any handlers.
+type alertRecorder struct {
+	*alert.InhibitorLookup
+
+	mu     sync.Mutex
+	events []client.TaskTestAlert
+	states map[string]map[string]alert.EventState
+}
+
+func newAlertRecorder() *alertRecorder {
+	return &alertRecorder{
+		InhibitorLookup: alert.NewInhibitorLookup(),
+		states:          make(map[string]map[string]alert.EventState),
+	}
+}
+
+func (r *alertRecorder) RegisterAnonHandler(topic string, h alert.Handler)   {}
+func (r *alertRecorder) DeregisterAnonHandler(topic string, h alert.Handler) {}
+
+func (r *alertRecorder) Collect(event alert.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setState(event.Topic, event.State)
+	a := client.TaskTestAlert{
+		ID:      event.State.ID,
+		Level:   event.State.Level.String(),
+		Message: event.State.Message,
+		Time:    event.State.Time.UTC(),
+	}
+	// An event is collected once for the anonymous topic and once for the named topic of the node.
+	if l := len(r.events); l > 0 && r.events[l-1] == a {
+		return nil
+	}
+	r.events = append(r.events, a)
+	return nil
+}
+
+func (r *alertRecorder) UpdateEvent(topic string, state alert.EventState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setState(topic, state)
+	return nil
+}
+
+func (r *alertRecorder) setState(topic string, state alert.EventState) {
+	states, ok := r.states[topic]
+	if !ok {
+		states = make(map[string]alert.EventState)
+		r.states[topic] = states
+	}
+	states[state.ID] = state
+}
+
+func (r *alertRecorder) EventState(topic, event string) (alert.EventState, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[topic][event]
+	return state, ok, nil
+}
+
+func (r *alertRecorder) CloseTopic(topic string) error   { return nil }
+func (r *alertRecorder) RestoreTopic(topic string) error { return nil }
+func (r *alertRecorder) DeleteTopic(topic string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.states, topic)
+	return nil
+}
+
+func (r *alertRecorder) alerts() []client.TaskTestAlert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]client.TaskTestAlert{}, r.events...)
+}
+
+// routeRecorder stands in for the HTTP service so that httpOut results can be read directly.
+type routeRecorder struct {
+	mu     sync.Mutex
+	routes map[string]httpd.Route
+}
+
+func newRouteRecorder() *routeRecorder {
+	return &routeRecorder{
+		routes: make(map[string]httpd.Route),
+	}
+}
+
+func (r *routeRecorder) AddRoutes(routes []httpd.Route) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, route := range routes {
+		r.routes[route.Pattern] = route
+	}
+	return nil
+}
+
+func (r *routeRecorder) DelRoutes(routes []httpd.Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, route := range routes {
+		delete(r.routes, route.Pattern)
+	}
+}
+
+func (r *routeRecorder) URL() string {
+	return ""
+}
+
+// results returns the current result of each httpOut endpoint of a task.
+func (r *routeRecorder) results(taskID string) map[string]json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix := path.Join("/tasks", taskID) + "/"
+	results := make(map[string]json.RawMessage, len(r.routes))
+	for pattern, route := range r.routes {
+		if route.Method != "GET" || !strings.HasPrefix(pattern, prefix) {
+			continue
+		}
+		rec := httptest.NewRecorder()
+		route.HandlerFunc.(func(http.ResponseWriter, *http.Request))(rec, httptest.NewRequest("GET", pattern, nil))
+		results[strings.TrimPrefix(pattern, prefix)] = json.RawMessage(rec.Body.Bytes())
+	}
+	return results
+}
+
+// writeRecorder stands in for the InfluxDB service and records the points written by the task in line protocol.
+type writeRecorder struct {
+	precision string
+
+	mu     sync.Mutex
+	points []string
+}
+
+func newWriteRecorder(precision string) *writeRecorder {
+	return &writeRecorder{
+		precision: precision,
+	}
+}
+
+func (r *writeRecorder) NewNamedClient(name string) (influxdb.Client, error) {
+	return recordingClient{r: r}, nil
+}
+
+func (r *writeRecorder) write(points []influxdb.Point) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range points {
+		mp, err := imodels.NewPoint(p.Name, imodels.NewTags(p.Tags), p.Fields, p.Time)
+		if err != nil {
+			return err
+		}
+		r.points = append(r.points, mp.PrecisionString(r.precision))
+	}
+	return nil
+}
+
+// lines returns the written points sorted in line protocol.
+func (r *writeRecorder) lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lines := append([]string{}, r.points...)
+	sort.Strings(lines)
+	return lines
+}
+
+type recordingClient struct {
+	r *writeRecorder
+}
+
+func (c recordingClient) Ping(ctx context.Context) (time.Duration, string, error) {
+	return 0, "", nil
+}
+
+func (c recordingClient) Write(bp influxdb.BatchPoints) error {
+	return c.r.write(bp.Points())
+}
+
+func (c recordingClient) WriteV2(w influxdb.FluxWrite) error {
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	for _, p := range w.Points {
+		c.r.points = append(c.r.points, p.PrecisionString(c.r.precision))
+	}
+	return nil
+}
+
+func (c recordingClient) Query(q influxdb.Query) (*influxdb.Response, error) {
+	return &influxdb.Response{}, nil
+}
+
+func (c recordingClient) QueryFlux(q influxdb.FluxQuery) (flux.ResultIterator, error) {
+	return nil, errors.New("queries are not supported in task tests")
+}
+
+func (c recordingClient) QueryFluxResponse(q influxdb.FluxQuery) (*influxdb.Response, error) {
+	return nil, errors.New("queries are not supported in task tests")
+}
+
+func (c recordingClient) CreateBucketV2(bucket string, org string, orgID string) error {
+	return nil
+}
+
+type httpPostService interface {
+	Handler(httppost.HandlerConfig, ...keyvalue.T) (alert.Handler, error)
+	Endpoint(string) (*httppost.Endpoint, bool)
+}
+
+// noHTTPPost resolves the endpoints of httpPost nodes from the underlying service,
+// its alert handlers discard all events.
+type noHTTPPost struct {
+	httpPostService
+}
+
+func (noHTTPPost) Handler(httppost.HandlerConfig, ...keyvalue.T) (alert.Handler, error) {
+	return discardHandler{}, nil
+}
+
+func (s noHTTPPost) Endpoint(name string) (*httppost.Endpoint, bool) {
+	if s.httpPostService == nil {
+		return nil, false
+	}
+	return s.httpPostService.Endpoint(name)
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Handle(alert.Event) {}
+
+// discardClient stands in for the HTTP client of httpPost nodes,
+// it answers every request with an empty 204 No Content response without sending it.
+type discardClient struct{}
+
+func (discardClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		Status:     "204 No Content",
+		StatusCode: http.StatusNoContent,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+var errAutoscale = errors.New("autoscale nodes are not supported in task tests")
+
+// noK8s, noSwarm and noEC2 keep autoscale nodes from reading or changing the replicas of real resources.
+type noK8s struct{}
+
+func (noK8s) Client(string) (k8s.Client, error) { return nil, errAutoscale }
+
+type noSwarm struct{}
+
+func (noSwarm) Client(string) (swarm.Client, error) { return nil, errAutoscale }
+
+type noEC2 struct{}
+
+func (noEC2) Client(string) (ec2.Client, error) { return nil, errAutoscale }
+
+// noSQL keeps querySQL nodes from querying real databases.
+type noSQL struct{}
+
+func (noSQL) Query(name, query string, start, stop time.Time, f func(*sql.Rows) error) error {
+	return errors.New("sql queries are not supported in task tests")
+}
+
+type taskStore interface {
+	SaveSnapshot(id string, snapshot *kapacitor.TaskSnapshot) error
+	HasSnapshot(id string) bool
+	LoadSnapshot(id string) (*kapacitor.TaskSnapshot, error)
+	LoadLibrary(id string) (string, error)
+}
+
+// noSnapshots keeps the task under test from loading or overwriting the snapshots of a real task,
+// libraries are loaded from the underlying task store.
+type noSnapshots struct {
+	taskStore
+}
+
+func (noSnapshots) SaveSnapshot(id string, snapshot *kapacitor.TaskSnapshot) error {
+	return nil
+}
+
+func (noSnapshots) HasSnapshot(id string) bool {
+	return false
+}
+
+func (noSnapshots) LoadSnapshot(id string) (*kapacitor.TaskSnapshot, error) {
+	return nil, errors.New("snapshots are not supported in task tests")
+}
+
+func (s noSnapshots) LoadLibrary(id string) (string, error) {
+	if s.taskStore == nil {
+		return "", errors.New("libraries are not available")
+	}
+	return s.taskStore.LoadLibrary(id)
+}
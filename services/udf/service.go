@@ -1,13 +1,16 @@
 package udf
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/influxdata/kapacitor"
 	"github.com/influxdata/kapacitor/command"
+	"github.com/influxdata/kapacitor/services/blobstore"
 	"github.com/influxdata/kapacitor/udf"
 )
 
@@ -17,11 +20,21 @@ type Diagnostic interface {
 	WithUDFContext() udf.Diagnostic
 }
 
+// BlobStoreService stores the blobs requested by UDFs.
+type BlobStoreService interface {
+	Tag(name string) (blobstore.Tag, error)
+	Data(id string) ([]byte, error)
+	CreateBlob(r io.Reader) (blobstore.Blob, bool, error)
+	SetTag(name, blobID string) (blobstore.Tag, error)
+}
+
 type Service struct {
 	configs map[string]FunctionConfig
 	infos   map[string]udf.Info
 	diag    Diagnostic
 	mu      sync.RWMutex
+
+	BlobStoreService BlobStoreService
 }
 
 func NewService(c Config, d Diagnostic) *Service {
@@ -72,15 +85,21 @@ func (s *Service) Create(
 	if !ok {
 		return nil, fmt.Errorf("no such UDF %s", name)
 	}
+	var blobs udf.BlobStore
+	if s.BlobStoreService != nil {
+		blobs = blobStore{s: s.BlobStoreService}
+	}
 	if conf.Socket != "" {
 		// Create socket UDF
-		return kapacitor.NewUDFSocket(
+		u := kapacitor.NewUDFSocket(
 			taskID, nodeID,
 			kapacitor.NewSocketConn(conf.Socket),
 			d,
 			time.Duration(conf.Timeout),
 			abortCallback,
-		), nil
+		)
+		u.BlobStore = blobs
+		return u, nil
 	} else {
 		// Create process UDF
 		env := os.Environ()
@@ -92,14 +111,16 @@ func (s *Service) Create(
 			Args: conf.Args,
 			Env:  env,
 		}
-		return kapacitor.NewUDFProcess(
+		u := kapacitor.NewUDFProcess(
 			taskID, nodeID,
 			command.ExecCommander,
 			cmdSpec,
 			d,
 			time.Duration(conf.Timeout),
 			abortCallback,
-		), nil
+		)
+		u.BlobStore = blobs
+		return u, nil
 	}
 }
 
@@ -134,3 +155,33 @@ func (s *Service) loadUDFInfo(name string) (udf.Info, error) {
 	}
 	return info, nil
 }
+
+// blobStore serves the blob requests of UDFs from the blob store service.
+type blobStore struct {
+	s BlobStoreService
+}
+
+func (b blobStore) TagBlob(tag string) (string, error) {
+	t, err := b.s.Tag(tag)
+	if err != nil {
+		return "", err
+	}
+	return t.BlobID, nil
+}
+
+func (b blobStore) BlobData(id string) ([]byte, error) {
+	return b.s.Data(id)
+}
+
+func (b blobStore) PutBlob(tag string, data []byte) (string, error) {
+	blob, _, err := b.s.CreateBlob(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	if tag != "" {
+		if _, err := b.s.SetTag(tag, blob.ID); err != nil {
+			return "", err
+		}
+	}
+	return blob.ID, nil
+}
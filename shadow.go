@@ -0,0 +1,88 @@
+package kapacitor
+
+import (
+	"time"
+
+	"github.com/influxdata/kapacitor/alert"
+)
+
+// ShadowRecordType is the kind of effect a ShadowRecord describes.
+type ShadowRecordType string
+
+const (
+	// ShadowAlert is an alert event.
+	ShadowAlert ShadowRecordType = "alert"
+	// ShadowWrite is a write of an influxDBOut node.
+	ShadowWrite ShadowRecordType = "influxdb"
+	// ShadowPost is a request of an httpPost node.
+	ShadowPost ShadowRecordType = "httppost"
+	// ShadowLoopback is a write of a kapacitorLoopback node.
+	ShadowLoopback ShadowRecordType = "loopback"
+	// ShadowScale is a change of replicas of an autoscale node.
+	ShadowScale ShadowRecordType = "autoscale"
+)
+
+// ShadowRecord describes an alert or write of a shadow task that was recorded instead of sent.
+// The alerts of a task are recorded as well while a shadow of it is executing,
+// so that both can be compared.
+type ShadowRecord struct {
+	Type ShadowRecordType
+	Node string
+	Time time.Time
+
+	// ID of the alert or of the scaled resource.
+	ID      string
+	Level   alert.Level
+	Message string
+
+	Database        string
+	RetentionPolicy string
+	Measurement     string
+	Points          int
+
+	URL string
+
+	Replicas int
+}
+
+// IsShadowed reports whether a shadow of the task is executing.
+func (tm *TaskMaster) IsShadowed(id string) bool {
+	tm.smu.RLock()
+	defer tm.smu.RUnlock()
+	return tm.shadows[id] > 0
+}
+
+func (tm *TaskMaster) addShadow(t *Task) {
+	if t.ShadowOf == "" {
+		return
+	}
+	tm.smu.Lock()
+	defer tm.smu.Unlock()
+	tm.shadows[t.ShadowOf]++
+}
+
+func (tm *TaskMaster) removeShadow(t *Task) {
+	if t.ShadowOf == "" {
+		return
+	}
+	tm.smu.Lock()
+	defer tm.smu.Unlock()
+	if tm.shadows[t.ShadowOf]--; tm.shadows[t.ShadowOf] <= 0 {
+		delete(tm.shadows, t.ShadowOf)
+	}
+}
+
+// shadow reports whether the task records its alerts and writes instead of sending them.
+func (et *ExecutingTask) shadow() bool {
+	return et.Task.ShadowOf != ""
+}
+
+// recordShadow records r in the shadow log of the task.
+func (et *ExecutingTask) recordShadow(r ShadowRecord) {
+	if et.tm.ShadowLog == nil {
+		return
+	}
+	if err := et.tm.ShadowLog.RecordShadow(et.Task.ID, r); err != nil {
+		et.diag.Error("failed to record shadow output", err)
+	}
+}
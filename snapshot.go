@@ -0,0 +1,248 @@
+package kapacitor
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sync"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+)
+
+// snapshotGroup is a group receiver whose running state can be snapshotted and restored.
+// S is the type of the snapshotted state and must be gob encodable.
+type snapshotGroup[S any] interface {
+	edge.ForwardReceiver
+	snapshot() S
+	restore(S)
+}
+
+// groupSnapshotter tracks the groups of a node so that the node's state can be
+// snapshotted while the node is running and restored when the node is restarted.
+//
+// Snapshots are taken from a different goroutine than the one processing data,
+// as such all access to the groups is serialized via a mutex.
+type groupSnapshotter[S any, G snapshotGroup[S]] struct {
+	mu     sync.Mutex
+	groups map[models.GroupID]G
+	// State restored from a snapshot for groups that have not yet been recreated.
+	restored map[models.GroupID]S
+}
+
+// newGroup registers the group g with the snapshotter, restoring any previous state of the group.
+// The returned receiver must be used in place of g so that snapshots are consistent.
+func (s *groupSnapshotter[S, G]) newGroup(id models.GroupID, g G) edge.ForwardReceiver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.groups == nil {
+		s.groups = make(map[models.GroupID]G)
+	}
+	if state, ok := s.restored[id]; ok {
+		g.restore(state)
+		delete(s.restored, id)
+	}
+	s.groups[id] = g
+	r := &snapshotReceiver[S, G]{
+		s:  s,
+		id: id,
+		r:  g,
+	}
+	if b, ok := edge.ForwardReceiver(g).(edge.ForwardBufferedReceiver); ok {
+		return &snapshotBufferedReceiver[S, G]{
+			snapshotReceiver: r,
+			b:                b,
+		}
+	}
+	return r
+}
+
+// snapshot encodes the state of all groups.
+func (s *groupSnapshotter[S, G]) snapshot() ([]byte, error) {
+	s.mu.Lock()
+	states := make(map[models.GroupID]S, len(s.groups)+len(s.restored))
+	// Keep restored state for groups that have not been seen since the restore.
+	for id, state := range s.restored {
+		states[id] = state
+	}
+	for id, g := range s.groups {
+		states[id] = g.snapshot()
+	}
+	s.mu.Unlock()
+	if len(states) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(states)
+	return buf.Bytes(), err
+}
+
+// restore decodes a snapshot, the state of each group is restored once the group is recreated.
+func (s *groupSnapshotter[S, G]) restore(data []byte) error {
+	var states map[models.GroupID]S
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&states); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.restored = states
+	s.mu.Unlock()
+	return nil
+}
+
+// snapshotReceiver serializes calls to a group with snapshots of the group.
+type snapshotReceiver[S any, G snapshotGroup[S]] struct {
+	s  *groupSnapshotter[S, G]
+	id models.GroupID
+	r  G
+}
+
+type snapshotBufferedReceiver[S any, G snapshotGroup[S]] struct {
+	*snapshotReceiver[S, G]
+	b edge.ForwardBufferedReceiver
+}
+
+func (r *snapshotReceiver[S, G]) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.r.BeginBatch(begin)
+}
+
+func (r *snapshotReceiver[S, G]) BatchPoint(bp edge.BatchPointMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.r.BatchPoint(bp)
+}
+
+func (r *snapshotReceiver[S, G]) EndBatch(end edge.EndBatchMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.r.EndBatch(end)
+}
+
+func (r *snapshotBufferedReceiver[S, G]) BufferedBatch(batch edge.BufferedBatchMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.b.BufferedBatch(batch)
+}
+
+func (r *snapshotReceiver[S, G]) Point(p edge.PointMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.r.Point(p)
+}
+
+func (r *snapshotReceiver[S, G]) Barrier(b edge.BarrierMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.r.Barrier(b)
+}
+
+func (r *snapshotReceiver[S, G]) DeleteGroup(d edge.DeleteGroupMessage) (edge.Message, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	// The group no longer has any state to snapshot.
+	delete(r.s.groups, r.id)
+	return r.r.DeleteGroup(d)
+}
+
+func (r *snapshotReceiver[S, G]) Done() {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.r.Done()
+}
+
+//--------------------------------------------------------------------
+// The following structures are stored in task snapshots via gob encoding.
+// Changes to the structures could break existing snapshots.
+
+// pointSnapshot is the snapshotted state of a single point.
+type pointSnapshot struct {
+	Fields models.Fields
+	Tags   models.Tags
+	Time   time.Time
+}
+
+func newPointSnapshot(p edge.FieldsTagsTimeGetter) pointSnapshot {
+	return pointSnapshot{
+		Fields: p.Fields(),
+		Tags:   p.Tags(),
+		Time:   p.Time(),
+	}
+}
+
+func (p pointSnapshot) batchPoint() edge.BatchPointMessage {
+	return edge.NewBatchPointMessage(p.Fields, p.Tags, p.Time)
+}
+
+// messageSnapshot is the snapshotted state of either a point or a buffered batch message.
+type messageSnapshot struct {
+	Batch           bool
+	Name            string
+	Database        string
+	RetentionPolicy string
+	Dimensions      models.Dimensions
+	Tags            models.Tags
+	Fields          models.Fields
+	Time            time.Time
+	// Points of the batch
+	Points []pointSnapshot
+}
+
+func newMessageSnapshot(m edge.Message) messageSnapshot {
+	switch msg := m.(type) {
+	case edge.PointMessage:
+		return messageSnapshot{
+			Name:            msg.Name(),
+			Database:        msg.Database(),
+			RetentionPolicy: msg.RetentionPolicy(),
+			Dimensions:      msg.Dimensions(),
+			Tags:            msg.Tags(),
+			Fields:          msg.Fields(),
+			Time:            msg.Time(),
+		}
+	case edge.BufferedBatchMessage:
+		points := make([]pointSnapshot, len(msg.Points()))
+		for i, bp := range msg.Points() {
+			points[i] = newPointSnapshot(bp)
+		}
+		return messageSnapshot{
+			Batch:      true,
+			Name:       msg.Name(),
+			Dimensions: msg.Dimensions(),
+			Tags:       msg.Tags(),
+			Time:       msg.Time(),
+			Points:     points,
+		}
+	default:
+		panic("unsupported snapshot message type")
+	}
+}
+
+func (m messageSnapshot) message() messageMeta {
+	if !m.Batch {
+		return edge.NewPointMessage(
+			m.Name,
+			m.Database,
+			m.RetentionPolicy,
+			m.Dimensions,
+			m.Fields,
+			m.Tags,
+			m.Time,
+		)
+	}
+	points := make([]edge.BatchPointMessage, len(m.Points))
+	for i, p := range m.Points {
+		points[i] = p.batchPoint()
+	}
+	return edge.NewBufferedBatchMessage(
+		edge.NewBeginBatchMessage(
+			m.Name,
+			m.Tags,
+			m.Dimensions.ByName,
+			m.Time,
+			len(points),
+		),
+		points,
+		edge.NewEndBatchMessage(),
+	)
+}
@@ -0,0 +1,173 @@
+package kapacitor
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotTestGroup() edge.GroupInfo {
+	tags := models.Tags{"host": "serverA"}
+	dims := models.Dimensions{TagNames: []string{"host"}}
+	return edge.GroupInfo{
+		ID:         models.ToGroupID("cpu", tags, dims),
+		Tags:       tags,
+		Dimensions: dims,
+	}
+}
+
+func snapshotTestPoint(group edge.GroupInfo, i int) edge.PointMessage {
+	return edge.NewPointMessage(
+		"cpu", "db", "rp",
+		group.Dimensions,
+		models.Fields{"value": float64(i)},
+		group.Tags,
+		time.Unix(int64(i), 0).UTC(),
+	)
+}
+
+func TestWindowByTime_SnapshotRestore(t *testing.T) {
+	group := snapshotTestGroup()
+	newWindow := func() windowGroup {
+		return newWindowByTime("cpu", time.Unix(0, 0).UTC(), group, 10*time.Second, 10*time.Second, false, false, nil)
+	}
+
+	// Run the original window until it has buffered some points.
+	var s groupSnapshotter[windowState, windowGroup]
+	w := s.newGroup(group.ID, newWindow())
+	for i := 0; i < 15; i++ {
+		_, err := w.Point(snapshotTestPoint(group, i))
+		require.NoError(t, err)
+	}
+	data, err := s.snapshot()
+	require.NoError(t, err)
+	expected, err := w.Point(snapshotTestPoint(group, 20))
+	require.NoError(t, err)
+
+	// Restore a new window and check it emits the same batch.
+	var restored groupSnapshotter[windowState, windowGroup]
+	require.NoError(t, restored.restore(data))
+	w = restored.newGroup(group.ID, newWindow())
+	got, err := w.Point(snapshotTestPoint(group, 20))
+	require.NoError(t, err)
+
+	require.NotNil(t, expected)
+	assert.Equal(t, expected, got)
+	assert.Len(t, got.(edge.BufferedBatchMessage).Points(), 5)
+}
+
+func TestWindowByCount_SnapshotRestore(t *testing.T) {
+	group := snapshotTestGroup()
+	newWindow := func() windowGroup {
+		return newWindowByCount("cpu", group, 4, 3, false, nil)
+	}
+
+	var s groupSnapshotter[windowState, windowGroup]
+	w := s.newGroup(group.ID, newWindow())
+	for i := 0; i < 5; i++ {
+		_, err := w.Point(snapshotTestPoint(group, i))
+		require.NoError(t, err)
+	}
+	data, err := s.snapshot()
+	require.NoError(t, err)
+	expected, err := w.Point(snapshotTestPoint(group, 5))
+	require.NoError(t, err)
+
+	var restored groupSnapshotter[windowState, windowGroup]
+	require.NoError(t, restored.restore(data))
+	w = restored.newGroup(group.ID, newWindow())
+	got, err := w.Point(snapshotTestPoint(group, 5))
+	require.NoError(t, err)
+
+	require.NotNil(t, expected)
+	assert.Equal(t, expected, got)
+}
+
+func TestWindowBySession_SnapshotRestore(t *testing.T) {
+	group := snapshotTestGroup()
+	newWindow := func() windowGroup {
+		return newWindowBySession("cpu", group, 5*time.Second, 0, nil)
+	}
+
+	var s groupSnapshotter[windowState, windowGroup]
+	w := s.newGroup(group.ID, newWindow())
+	for i := 0; i < 3; i++ {
+		_, err := w.Point(snapshotTestPoint(group, i))
+		require.NoError(t, err)
+	}
+	data, err := s.snapshot()
+	require.NoError(t, err)
+	expected, err := w.Point(snapshotTestPoint(group, 10))
+	require.NoError(t, err)
+
+	var restored groupSnapshotter[windowState, windowGroup]
+	require.NoError(t, restored.restore(data))
+	w = restored.newGroup(group.ID, newWindow())
+	got, err := w.Point(snapshotTestPoint(group, 10))
+	require.NoError(t, err)
+
+	require.NotNil(t, expected)
+	assert.Equal(t, expected, got)
+	assert.Len(t, got.(edge.BufferedBatchMessage).Points(), 3)
+}
+
+func TestGroupSnapshotter_KeepsUnclaimedState(t *testing.T) {
+	group := snapshotTestGroup()
+
+	var s groupSnapshotter[windowState, windowGroup]
+	w := s.newGroup(group.ID, newWindowByCount("cpu", group, 4, 4, false, nil))
+	_, err := w.Point(snapshotTestPoint(group, 1))
+	require.NoError(t, err)
+	data, err := s.snapshot()
+	require.NoError(t, err)
+
+	// A snapshot taken before the group reappears must still contain its state.
+	var restored groupSnapshotter[windowState, windowGroup]
+	require.NoError(t, restored.restore(data))
+	again, err := restored.snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	// Once the group is deleted its state is no longer part of the snapshot.
+	w = restored.newGroup(group.ID, newWindowByCount("cpu", group, 4, 4, false, nil))
+	_, err = w.DeleteGroup(edge.NewDeleteGroupMessage(group))
+	require.NoError(t, err)
+	empty, err := restored.snapshot()
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+}
+
+func TestMessageSnapshot(t *testing.T) {
+	group := snapshotTestGroup()
+	point := snapshotTestPoint(group, 1)
+	batch := edge.NewBufferedBatchMessage(
+		edge.NewBeginBatchMessage("cpu", group.Tags, false, time.Unix(2, 0).UTC(), 2),
+		[]edge.BatchPointMessage{
+			edge.BatchPointFromPoint(snapshotTestPoint(group, 1)),
+			edge.BatchPointFromPoint(snapshotTestPoint(group, 2)),
+		},
+		edge.NewEndBatchMessage(),
+	)
+
+	for _, m := range []messageMeta{point, batch} {
+		var buf bytes.Buffer
+		require.NoError(t, gob.NewEncoder(&buf).Encode(newMessageSnapshot(m)))
+		var ms messageSnapshot
+		require.NoError(t, gob.NewDecoder(&buf).Decode(&ms))
+		got := ms.message()
+		assert.Equal(t, m.Type(), got.Type())
+		assert.Equal(t, m.GroupID(), got.GroupID())
+		assert.Equal(t, m.Time(), got.Time())
+		if b, ok := m.(edge.BufferedBatchMessage); ok {
+			assert.Equal(t, b.Points(), got.(edge.BufferedBatchMessage).Points())
+		} else {
+			assert.Equal(t, m.(edge.PointMessage).Fields(), got.(edge.PointMessage).Fields())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package kapacitor
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/kapacitor/edge"
+	"github.com/influxdata/kapacitor/models"
+	"github.com/influxdata/kapacitor/pipeline"
+	"github.com/pkg/errors"
+)
+
+// SQLQueryNode is a node for querying relational databases
+type SQLQueryNode struct {
+	batchQueryNode
+	b *pipeline.QuerySQLNode
+}
+
+func newQuerySQLNode(et *ExecutingTask, n *pipeline.QuerySQLNode, d NodeDiagnostic) (*SQLQueryNode, error) {
+	bn := &SQLQueryNode{
+		batchQueryNode: batchQueryNode{node: node{Node: n, et: et, diag: d}},
+		b:              n,
+	}
+
+	// Determine schedule
+	if err := bn.schedule(n.Every, n.Cron, n.AlignFlag, bn.connect); err != nil {
+		return nil, err
+	}
+
+	return bn, nil
+}
+
+// connect returns the function that queries the database for the batches of a tick.
+func (n *SQLQueryNode) connect() (batchQueryFunc, error) {
+	if n.et.tm.SQLService == nil {
+		return nil, errors.New("SQL not configured, cannot query a SQL database for batch query")
+	}
+
+	return func(now time.Time) (batches []edge.BufferedBatchMessage, err error) {
+		// Update times for query
+		stop := now.Add(-1 * n.b.Offset)
+		start := stop.Add(-1 * n.b.Period)
+		n.diag.StartingBatchQuery(n.b.QueryStr)
+
+		// Execute query
+		err = n.et.tm.SQLService.Query(n.b.Connection, n.b.QueryStr, start, stop, func(rows *sql.Rows) (err error) {
+			batches, err = rowsToBatches(rows, n.b.Measurement, n.b.TimeColumn, n.b.TagColumns, stop)
+			return err
+		})
+		return batches, err
+	}, nil
+}
+
+// sqlRows is the subset of *sql.Rows used to read a query result.
+type sqlRows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+}
+
+// Layouts accepted for time columns returned as text, in addition to RFC3339.
+var sqlTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// rowsToBatches converts the rows of a query result into batches, one per set of tag values.
+//
+// The time column sets the time of each point, rows without it use the stop time.
+// The tag columns set the tags of each point and the remaining non NULL columns become fields.
+// Rows without any fields are skipped.
+func rowsToBatches(rows sqlRows, measurement, timeColumn string, tagColumns []string, stop time.Time) ([]edge.BufferedBatchMessage, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	isTag := make(map[string]bool, len(tagColumns))
+	for _, c := range tagColumns {
+		isTag[c] = true
+	}
+
+	type group struct {
+		tags   models.Tags
+		points []edge.BatchPointMessage
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		t := stop
+		tags := make(models.Tags, len(tagColumns))
+		fields := make(models.Fields, len(columns))
+		for i, c := range columns {
+			v := values[i]
+			if v == nil {
+				continue
+			}
+			switch {
+			case c == timeColumn:
+				t, err = sqlTime(v)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid time column %q", c)
+				}
+			case isTag[c]:
+				tags[c] = sqlString(v)
+			default:
+				fields[c] = sqlField(v)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		key := models.ToGroupID(measurement, tags, models.Dimensions{TagNames: models.SortedKeys(tags)})
+		g, ok := byKey[string(key)]
+		if !ok {
+			g = &group{tags: tags}
+			byKey[string(key)] = g
+			groups = append(groups, g)
+		}
+		g.points = append(g.points, edge.NewBatchPointMessage(fields, tags, t.UTC()))
+	}
+
+	batches := make([]edge.BufferedBatchMessage, len(groups))
+	for i, g := range groups {
+		sort.SliceStable(g.points, func(i, j int) bool {
+			return g.points[i].Time().Before(g.points[j].Time())
+		})
+		batches[i] = edge.NewBufferedBatchMessage(
+			edge.NewBeginBatchMessage(measurement, g.tags, false, stop.UTC(), len(g.points)),
+			g.points,
+			edge.NewEndBatchMessage(),
+		)
+	}
+	return batches, nil
+}
+
+// sqlTime converts the value of a time column.
+// Integers are seconds since the Unix epoch, text is parsed with one of the sqlTimeLayouts.
+func sqlTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case []byte:
+		return sqlTime(string(v))
+	case string:
+		for _, layout := range sqlTimeLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unexpected time format %q", v)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected time value of type %T", v)
+	}
+}
+
+// sqlString converts the value of a tag column.
+func sqlString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sqlField converts the value of a field column to a field value.
+func sqlField(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64, int64, bool, string:
+		return v
+	default:
+		return sqlString(v)
+	}
+}
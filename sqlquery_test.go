@@ -0,0 +1,92 @@
+package kapacitor
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/influxdata/kapacitor/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSQLRows struct {
+	columns []string
+	values  [][]interface{}
+	i       int
+}
+
+func (r *fakeSQLRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func (r *fakeSQLRows) Next() bool {
+	r.i++
+	return r.i <= len(r.values)
+}
+
+func (r *fakeSQLRows) Scan(dest ...interface{}) error {
+	if r.i > len(r.values) {
+		return io.EOF
+	}
+	for i, v := range r.values[r.i-1] {
+		*dest[i].(*interface{}) = v
+	}
+	return nil
+}
+
+func TestRowsToBatches(t *testing.T) {
+	stop := time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)
+	rows := &fakeSQLRows{
+		columns: []string{"created_at", "region", "plan", "total", "note"},
+		values: [][]interface{}{
+			{time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC), "east", []byte("pro"), 10.5, nil},
+			{"2020-01-01 00:10:00", "west", nil, int64(3), []byte("late")},
+			{int64(1577836800), "east", []byte("pro"), 1.5, nil},
+			// Rows without fields are skipped
+			{nil, "east", nil, nil, nil},
+		},
+	}
+	batches, err := rowsToBatches(rows, "orders", "created_at", []string{"region", "plan"}, stop)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+
+	east := batches[0]
+	assert.Equal(t, "orders", east.Name())
+	assert.Equal(t, models.Tags{"region": "east", "plan": "pro"}, east.Tags())
+	assert.Equal(t, []string{"plan", "region"}, east.Dimensions().TagNames)
+	assert.Equal(t, stop, east.Begin().Time())
+	require.Len(t, east.Points(), 2)
+	// Points are sorted by time.
+	assert.Equal(t, time.Unix(1577836800, 0).UTC(), east.Points()[0].Time())
+	assert.Equal(t, models.Fields{"total": 1.5}, east.Points()[0].Fields())
+	assert.Equal(t, models.Fields{"total": 10.5}, east.Points()[1].Fields())
+
+	west := batches[1]
+	assert.Equal(t, models.Tags{"region": "west"}, west.Tags())
+	require.Len(t, west.Points(), 1)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 10, 0, 0, time.UTC), west.Points()[0].Time())
+	assert.Equal(t, models.Fields{"total": int64(3), "note": "late"}, west.Points()[0].Fields())
+}
+
+func TestRowsToBatches_NoTimeColumn(t *testing.T) {
+	stop := time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)
+	rows := &fakeSQLRows{
+		columns: []string{"count"},
+		values:  [][]interface{}{{int64(42)}},
+	}
+	batches, err := rowsToBatches(rows, "sql", "time", nil, stop)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, stop, batches[0].Points()[0].Time())
+	assert.Equal(t, models.Fields{"count": int64(42)}, batches[0].Points()[0].Fields())
+}
+
+func TestRowsToBatches_InvalidTime(t *testing.T) {
+	rows := &fakeSQLRows{
+		columns: []string{"time", "value"},
+		values:  [][]interface{}{{"yesterday", 1.0}},
+	}
+	_, err := rowsToBatches(rows, "sql", "time", nil, time.Now())
+	assert.Error(t, err)
+}
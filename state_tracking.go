@@ -13,6 +13,14 @@ import (
 type stateTracker interface {
 	track(t time.Time, inState bool) interface{}
 	reset()
+	snapshot() stateTrackerState
+	restore(stateTrackerState)
+}
+
+// stateTrackerState is the snapshotted state of a stateTracker.
+type stateTrackerState struct {
+	StartTime time.Time
+	Count     int64
 }
 
 type stateTrackingGroup struct {
@@ -29,9 +37,16 @@ type StateTrackingNode struct {
 	scopePool stateful.ScopePool
 
 	newTracker func() stateTracker
+
+	states groupSnapshotter[stateTrackerState, *stateTrackingGroup]
 }
 
-func (n *StateTrackingNode) runStateTracking(_ []byte) error {
+func (n *StateTrackingNode) runStateTracking(snapshot []byte) error {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore state tracking state", err)
+		}
+	}
 	consumer := edge.NewGroupedConsumer(
 		n.ins[0],
 		n,
@@ -43,10 +58,18 @@ func (n *StateTrackingNode) runStateTracking(_ []byte) error {
 func (n *StateTrackingNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.Receiver, error) {
 	return edge.NewReceiverFromForwardReceiverWithStats(
 		n.outs,
-		edge.NewTimedForwardReceiver(n.timer, n.newGroup()),
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, n.newGroup())),
 	), nil
 }
 
+func (n *StateTrackingNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *StateTrackingNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
 func (n *StateTrackingNode) newGroup() *stateTrackingGroup {
 	// Create a new tracking group
 	g := &stateTrackingGroup{
@@ -59,6 +82,14 @@ func (n *StateTrackingNode) newGroup() *stateTrackingGroup {
 	return g
 }
 
+func (g *stateTrackingGroup) snapshot() stateTrackerState {
+	return g.tracker.snapshot()
+}
+
+func (g *stateTrackingGroup) restore(state stateTrackerState) {
+	g.tracker.restore(state)
+}
+
 func (g *stateTrackingGroup) BeginBatch(begin edge.BeginBatchMessage) (edge.Message, error) {
 	g.tracker.reset()
 	return begin, nil
@@ -118,6 +149,14 @@ func (sdt *stateDurationTracker) reset() {
 	sdt.startTime = time.Time{}
 }
 
+func (sdt *stateDurationTracker) snapshot() stateTrackerState {
+	return stateTrackerState{StartTime: sdt.startTime}
+}
+
+func (sdt *stateDurationTracker) restore(state stateTrackerState) {
+	sdt.startTime = state.StartTime
+}
+
 func (sdt *stateDurationTracker) track(t time.Time, inState bool) interface{} {
 	if !inState {
 		sdt.startTime = time.Time{}
@@ -144,7 +183,7 @@ func newStateDurationNode(et *ExecutingTask, sd *pipeline.StateDurationNode, d N
 		as:         sd.As,
 		newTracker: func() stateTracker { return &stateDurationTracker{sd: sd} },
 		expr:       expr,
-		scopePool:  stateful.NewScopePool(ast.FindReferenceVariables(sd.Lambda.Expression)),
+		scopePool:  et.tm.newScopePool(ast.FindReferenceVariables(sd.Lambda.Expression)),
 	}
 	n.node.runF = n.runStateTracking
 	return n, nil
@@ -158,6 +197,14 @@ func (sct *stateCountTracker) reset() {
 	sct.count = 0
 }
 
+func (sct *stateCountTracker) snapshot() stateTrackerState {
+	return stateTrackerState{Count: sct.count}
+}
+
+func (sct *stateCountTracker) restore(state stateTrackerState) {
+	sct.count = state.Count
+}
+
 func (sct *stateCountTracker) track(t time.Time, inState bool) interface{} {
 	if !inState {
 		sct.count = 0
@@ -182,7 +229,7 @@ func newStateCountNode(et *ExecutingTask, sc *pipeline.StateCountNode, d NodeDia
 		as:         sc.As,
 		newTracker: func() stateTracker { return &stateCountTracker{} },
 		expr:       expr,
-		scopePool:  stateful.NewScopePool(ast.FindReferenceVariables(sc.Lambda.Expression)),
+		scopePool:  et.tm.newScopePool(ast.FindReferenceVariables(sc.Lambda.Expression)),
 	}
 	n.node.runF = n.runStateTracking
 	return n, nil
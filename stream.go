@@ -69,7 +69,7 @@ func newFromNode(et *ExecutingTask, n *pipeline.FromNode, d NodeDiagnostic) (*Fr
 		}
 
 		sn.expression = expr
-		sn.scopePool = stateful.NewScopePool(ast.FindReferenceVariables(n.Lambda.Expression))
+		sn.scopePool = et.tm.newScopePool(ast.FindReferenceVariables(n.Lambda.Expression))
 	}
 
 	return sn, nil
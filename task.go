@@ -79,6 +79,9 @@ type Task struct {
 	Type             TaskType
 	DBRPs            []DBRP
 	SnapshotInterval time.Duration
+	// ShadowOf is the ID of the task this task shadows.
+	// A shadow task records its alerts and writes instead of sending them.
+	ShadowOf string
 }
 
 func (t *Task) Dot() []byte {
@@ -119,17 +122,23 @@ type ExecutingTask struct {
 	// Mutex for throughput var
 	tmu        sync.RWMutex
 	throughput float64
+
+	// Mutex for breakpoints
+	bmu sync.Mutex
+	// breakpoints by node name
+	breakpoints map[string]*edge.Breakpoint
 }
 
 // Create a new  task from a defined kapacitor.
 func NewExecutingTask(tm *TaskMaster, t *Task) (*ExecutingTask, error) {
 	d := tm.diag.WithTaskContext(t.ID)
 	et := &ExecutingTask{
-		tm:      tm,
-		Task:    t,
-		outputs: make(map[string]Output),
-		lookup:  make(map[pipeline.ID]Node),
-		diag:    d,
+		tm:          tm,
+		Task:        t,
+		outputs:     make(map[string]Output),
+		lookup:      make(map[pipeline.ID]Node),
+		diag:        d,
+		breakpoints: make(map[string]*edge.Breakpoint),
 	}
 	err := et.link()
 	if err != nil {
@@ -248,6 +257,73 @@ func (et *ExecutingTask) stop() (err error) {
 
 var ErrWrongTaskType = errors.New("wrong task type")
 
+// SetBreakpoint sets a breakpoint on the edges into the named node.
+func (et *ExecutingTask) SetBreakpoint(node string, b *edge.Breakpoint) error {
+	et.bmu.Lock()
+	defer et.bmu.Unlock()
+	if _, ok := et.breakpoints[node]; ok {
+		return fmt.Errorf("node %s already has a breakpoint", node)
+	}
+	var n Node
+	for _, en := range et.nodes {
+		if en.Name() == node {
+			n = en
+			break
+		}
+	}
+	if n == nil {
+		return fmt.Errorf("unknown node %s", node)
+	}
+	for _, in := range n.parentEdges() {
+		if e, ok := in.(*Edge); ok {
+			e.debug.SetBreakpoint(b)
+		}
+	}
+	et.breakpoints[node] = b
+	return nil
+}
+
+// Breakpoint returns the breakpoint of the named node or nil.
+func (et *ExecutingTask) Breakpoint(node string) *edge.Breakpoint {
+	et.bmu.Lock()
+	defer et.bmu.Unlock()
+	return et.breakpoints[node]
+}
+
+// ClearBreakpoint removes the breakpoint of the named node and delivers its waiting messages.
+func (et *ExecutingTask) ClearBreakpoint(node string) {
+	et.bmu.Lock()
+	defer et.bmu.Unlock()
+	et.clearBreakpoint(node)
+}
+
+func (et *ExecutingTask) clearBreakpoints() {
+	et.bmu.Lock()
+	defer et.bmu.Unlock()
+	for node := range et.breakpoints {
+		et.clearBreakpoint(node)
+	}
+}
+
+func (et *ExecutingTask) clearBreakpoint(node string) {
+	b, ok := et.breakpoints[node]
+	if !ok {
+		return
+	}
+	delete(et.breakpoints, node)
+	for _, n := range et.nodes {
+		if n.Name() != node {
+			continue
+		}
+		for _, in := range n.parentEdges() {
+			if e, ok := in.(*Edge); ok {
+				e.debug.ClearBreakpoint()
+			}
+		}
+	}
+	b.Clear()
+}
+
 // Instruct source batch node to start querying and sending batches of data
 func (et *ExecutingTask) StartBatching() error {
 	if et.Task.Type != BatchTask {
@@ -456,6 +532,8 @@ func (et *ExecutingTask) createNode(p pipeline.Node, d NodeDiagnostic) (n Node,
 		n, err = newQueryNode(et, t, d)
 	case *pipeline.QueryFluxNode:
 		n, err = newQueryFluxNode(et, t, d)
+	case *pipeline.QuerySQLNode:
+		n, err = newQuerySQLNode(et, t, d)
 	case *pipeline.WindowNode:
 		n, err = newWindowNode(et, t, d)
 	case *pipeline.HTTPOutNode:
@@ -474,6 +552,8 @@ func (et *ExecutingTask) createNode(p pipeline.Node, d NodeDiagnostic) (n Node,
 		n, err = newUnionNode(et, t, d)
 	case *pipeline.JoinNode:
 		n, err = newJoinNode(et, t, d)
+	case *pipeline.LookupJoinNode:
+		n, err = newLookupJoinNode(et, t, d)
 	case *pipeline.FlattenNode:
 		n, err = newFlattenNode(et, t, d)
 	case *pipeline.EvalNode:
@@ -486,6 +566,10 @@ func (et *ExecutingTask) createNode(p pipeline.Node, d NodeDiagnostic) (n Node,
 		n, err = newDerivativeNode(et, t, d)
 	case *pipeline.ChangeDetectNode:
 		n, err = newChangeDetectNode(et, t, d)
+	case *pipeline.AnomalyNode:
+		n, err = newAnomalyNode(et, t, d)
+	case *pipeline.QuantilesNode:
+		n, err = newQuantilesNode(et, t, d)
 	case *pipeline.UDFNode:
 		n, err = newUDFNode(et, t, d)
 	case *pipeline.StatsNode:
@@ -1,10 +1,12 @@
 package kapacitor
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 
@@ -107,9 +109,15 @@ type TaskMaster struct {
 		SaveSnapshot(id string, snapshot *TaskSnapshot) error
 		HasSnapshot(id string) bool
 		LoadSnapshot(id string) (*TaskSnapshot, error)
+		LoadLibrary(id string) (string, error)
 	}
 	DeadmanService pipeline.DeadmanService
 
+	// ShadowLog records the alerts and writes of shadow tasks.
+	ShadowLog interface {
+		RecordShadow(task string, r ShadowRecord) error
+	}
+
 	UDFService UDFService
 
 	AlertService interface {
@@ -121,6 +129,9 @@ type TaskMaster struct {
 	InfluxDBService interface {
 		NewNamedClient(name string) (influxdb.Client, error)
 	}
+	SQLService interface {
+		Query(name, query string, start, stop time.Time, f func(*sql.Rows) error) error
+	}
 	SMTPService interface {
 		Global() bool
 		StateChangesOnly() bool
@@ -157,6 +168,10 @@ type TaskMaster struct {
 		Handler(httppost.HandlerConfig, ...keyvalue.T) (alert.Handler, error)
 		Endpoint(string) (*httppost.Endpoint, bool)
 	}
+	// HTTPPostClient sends the requests of httpPost nodes, http.DefaultClient is used if nil.
+	HTTPPostClient interface {
+		Do(*http.Request) (*http.Response, error)
+	}
 	DiscordService interface {
 		Global() bool
 		StateChangesOnly() bool
@@ -215,6 +230,9 @@ type TaskMaster struct {
 		Source(*httppost.Endpoint) (sideload.Source, error)
 	}
 
+	// ScheduleLookup provides the named schedules of the inSchedule lambda function.
+	ScheduleLookup stateful.ScheduleLookup
+
 	TeamsService interface {
 		Global() bool
 		StateChangesOnly() bool
@@ -259,6 +277,11 @@ type TaskMaster struct {
 	// Executing tasks
 	tasks map[string]*ExecutingTask
 
+	// Number of executing shadow tasks by the ID of the task they shadow.
+	// Alert nodes read it while the task master is locked stopping them.
+	shadows map[string]int
+	smu     sync.RWMutex
+
 	// DeleteHooks for tasks
 	deleteHooks map[string][]deleteHook
 
@@ -291,6 +314,7 @@ func NewTaskMaster(id string, info vars.Infoer, d Diagnostic) *TaskMaster {
 		taskToForkKeys: make(map[string][]forkKey),
 		batches:        make(map[string][]BatchCollector),
 		tasks:          make(map[string]*ExecutingTask),
+		shadows:        make(map[string]int),
 		deleteHooks:    make(map[string][]deleteHook),
 		ServerInfo:     info,
 		diag:           d.WithTaskMasterContext(id),
@@ -313,6 +337,7 @@ func (tm *TaskMaster) New(id string) *TaskMaster {
 	n.UDFService = tm.UDFService
 	n.AlertService = tm.AlertService
 	n.InfluxDBService = tm.InfluxDBService
+	n.SQLService = tm.SQLService
 	n.SMTPService = tm.SMTPService
 	n.MQTTService = tm.MQTTService
 	n.OpsGenieService = tm.OpsGenieService
@@ -322,6 +347,7 @@ func (tm *TaskMaster) New(id string) *TaskMaster {
 	n.PagerDuty2Service = tm.PagerDuty2Service
 	n.PushoverService = tm.PushoverService
 	n.HTTPPostService = tm.HTTPPostService
+	n.HTTPPostClient = tm.HTTPPostClient
 	n.DiscordService = tm.DiscordService
 	n.BigPandaService = tm.BigPandaService
 	n.SlackService = tm.SlackService
@@ -335,6 +361,7 @@ func (tm *TaskMaster) New(id string) *TaskMaster {
 	n.K8sService = tm.K8sService
 	n.Commander = tm.Commander
 	n.SideloadService = tm.SideloadService
+	n.ScheduleLookup = tm.ScheduleLookup
 	n.TeamsService = tm.TeamsService
 	n.ServiceNowService = tm.ServiceNowService
 	n.ZenossService = tm.ZenossService
@@ -521,9 +548,21 @@ func (tm *TaskMaster) CreateTICKScope() *stateful.Scope {
 			)
 		}
 	}
+	// Resolve imported libraries from the task store
+	if tm.TaskStore != nil {
+		scope.SetLibraryLoader(tm.TaskStore.LoadLibrary)
+	}
 	return scope
 }
 
+// newScopePool returns a pool of scopes for evaluating lambda expressions,
+// whose scopes have the functions that depend on the task master.
+func (tm *TaskMaster) newScopePool(referenceVariables []string) stateful.ScopePool {
+	return stateful.NewScopePoolWithFuncs(referenceVariables, map[string]*stateful.DynamicFunc{
+		"inSchedule": stateful.NewInScheduleFunc(tm.ScheduleLookup),
+	})
+}
+
 func (tm *TaskMaster) StartTask(t *Task) (*ExecutingTask, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
@@ -575,6 +614,7 @@ func (tm *TaskMaster) StartTask(t *Task) (*ExecutingTask, error) {
 	}
 
 	tm.tasks[et.Task.ID] = et
+	tm.addShadow(t)
 	tm.diag.StartedTask(t.ID)
 	tm.diag.TaskMasterDot(string(t.Dot()))
 
@@ -607,6 +647,9 @@ func (tm *TaskMaster) stopTask(id string) (err error) {
 	if et, ok := tm.tasks[id]; ok {
 
 		delete(tm.tasks, id)
+		tm.removeShadow(et.Task)
+		// Paused nodes would keep the task from stopping.
+		et.clearBreakpoints()
 
 		switch et.Task.Type {
 		case StreamTask:
@@ -667,6 +710,37 @@ func (tm *TaskMaster) ExecutingDot(id string, labels bool) string {
 	return ""
 }
 
+// SetBreakpoint sets a breakpoint on the edges into a node of an executing task.
+func (tm *TaskMaster) SetBreakpoint(id, node string, b *edge.Breakpoint) error {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	et, executing := tm.tasks[id]
+	if !executing {
+		return fmt.Errorf("task %s is not executing", id)
+	}
+	return et.SetBreakpoint(node, b)
+}
+
+// Breakpoint returns the breakpoint of a node of an executing task or nil.
+func (tm *TaskMaster) Breakpoint(id, node string) *edge.Breakpoint {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	et, executing := tm.tasks[id]
+	if !executing {
+		return nil
+	}
+	return et.Breakpoint(node)
+}
+
+// ClearBreakpoint removes the breakpoint of a node of an executing task.
+func (tm *TaskMaster) ClearBreakpoint(id, node string) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	if et, executing := tm.tasks[id]; executing {
+		et.ClearBreakpoint(node)
+	}
+}
+
 func (tm *TaskMaster) Stream(name string) (StreamCollector, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
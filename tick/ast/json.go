@@ -306,6 +306,10 @@ func (j JSONNode) getNode(nn interface{}) (Node, error) {
 		n = &DeclarationNode{}
 	case "typeDeclaration":
 		n = &TypeDeclarationNode{}
+	case "definition":
+		n = &DefinitionNode{}
+	case "import":
+		n = &ImportNode{}
 	case "identifier":
 		n = &IdentifierNode{}
 	case "reference":
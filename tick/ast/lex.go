@@ -39,6 +39,8 @@ const (
 	TokenRegex
 	TokenComment
 	TokenStar
+	TokenDef
+	TokenImport
 
 	// begin operator tokens
 	begin_tok_operator
@@ -112,6 +114,8 @@ const (
 	KW_Var    = "var"
 	KW_DBRP   = "dbrp"
 	KW_Lambda = "lambda"
+	KW_Def    = "def"
+	KW_Import = "import"
 )
 
 var keywords = map[string]TokenType{
@@ -122,6 +126,8 @@ var keywords = map[string]TokenType{
 	KW_Var:    TokenVar,
 	KW_DBRP:   TokenDBRP,
 	KW_Lambda: TokenLambda,
+	KW_Def:    TokenDef,
+	KW_Import: TokenImport,
 }
 
 func init() {
@@ -142,6 +148,10 @@ func (t TokenType) String() string {
 		return "var"
 	case t == TokenDBRP:
 		return "dbrp"
+	case t == TokenDef:
+		return "def"
+	case t == TokenImport:
+		return "import"
 	case t == TokenIdent:
 		return "identifier"
 	case t == TokenReference:
@@ -800,6 +800,199 @@ func (n *TypeDeclarationNode) Equal(o interface{}) bool {
 	return false
 }
 
+// Holds a named and parameterised definition.
+// The body is either a lambda expression or a chain fragment,
+// the chain of a fragment starts on an identifier with an empty name.
+type DefinitionNode struct {
+	position
+	Name    *IdentifierNode
+	Params  []*IdentifierNode
+	Body    Node
+	Comment *CommentNode
+}
+
+// MarshalJSON converts the node to JSON with an additional
+// typeOf field.
+func (n *DefinitionNode) MarshalJSON() ([]byte, error) {
+	props := JSONNode{}.
+		Type("definition").
+		Set("name", n.Name).
+		Set("params", n.Params).
+		Set("body", n.Body)
+	return json.Marshal(&props)
+}
+
+func (n *DefinitionNode) unmarshal(props JSONNode) error {
+	err := props.CheckTypeOf("definition")
+	if err != nil {
+		return err
+	}
+
+	if n.Name, err = props.IDNode("name"); err != nil {
+		return err
+	}
+
+	params, err := props.NodeList("params")
+	if err != nil {
+		return err
+	}
+	n.Params = make([]*IdentifierNode, len(params))
+	for i, p := range params {
+		ident, ok := p.(*IdentifierNode)
+		if !ok {
+			return fmt.Errorf("field params is not a list of identifier nodes but contains %T", p)
+		}
+		n.Params[i] = ident
+	}
+
+	if n.Body, err = props.Node("body"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnmarshalJSON converts JSON bytes to a DefinitionNode
+func (n *DefinitionNode) UnmarshalJSON(data []byte) error {
+	var props JSONNode
+	err := json.Unmarshal(data, &props)
+	if err != nil {
+		return err
+	}
+	return n.unmarshal(props)
+}
+
+func newDefinition(p position, name *IdentifierNode, params []*IdentifierNode, body Node, c *CommentNode) *DefinitionNode {
+	return &DefinitionNode{
+		position: p,
+		Name:     name,
+		Params:   params,
+		Body:     body,
+		Comment:  c,
+	}
+}
+
+// IsFragment reports whether the definition is a chain fragment, otherwise it is a lambda expression.
+func (n *DefinitionNode) IsFragment() bool {
+	_, ok := n.Body.(*LambdaNode)
+	return !ok
+}
+
+func (n *DefinitionNode) String() string {
+	return fmt.Sprintf("DefinitionNode@%v{%v %v %v}%v", n.position, n.Name, n.Params, n.Body, n.Comment)
+}
+
+func (n *DefinitionNode) Format(buf *bytes.Buffer, indent string, onNewLine bool) {
+	if n.Comment != nil {
+		n.Comment.Format(buf, indent, onNewLine)
+	}
+	buf.WriteString(KW_Def)
+	buf.WriteByte(' ')
+	n.Name.Format(buf, indent, false)
+	buf.WriteByte('(')
+	for i, p := range n.Params {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		p.Format(buf, indent, false)
+	}
+	buf.WriteString(") ")
+	buf.WriteString(TokenAsgn.String())
+	if !n.IsFragment() {
+		buf.WriteByte(' ')
+	}
+	n.Body.Format(buf, indent, false)
+}
+func (n *DefinitionNode) SetComment(c *CommentNode) {
+	n.Comment = c
+}
+func (n *DefinitionNode) Equal(o interface{}) bool {
+	if on, ok := o.(*DefinitionNode); ok {
+		if !n.Name.Equal(on.Name) || len(n.Params) != len(on.Params) {
+			return false
+		}
+		for i := range n.Params {
+			if !n.Params[i].Equal(on.Params[i]) {
+				return false
+			}
+		}
+		return n.Body.Equal(on.Body)
+	}
+	return false
+}
+
+// Holds an import of a library of definitions.
+type ImportNode struct {
+	position
+	Library *StringNode
+	Comment *CommentNode
+}
+
+// MarshalJSON converts the node to JSON with an additional
+// typeOf field.
+func (n *ImportNode) MarshalJSON() ([]byte, error) {
+	props := JSONNode{}.
+		Type("import").
+		Set("library", n.Library)
+	return json.Marshal(&props)
+}
+
+func (n *ImportNode) unmarshal(props JSONNode) error {
+	err := props.CheckTypeOf("import")
+	if err != nil {
+		return err
+	}
+
+	lib, err := props.Node("library")
+	if err != nil {
+		return err
+	}
+	var ok bool
+	if n.Library, ok = lib.(*StringNode); !ok {
+		return fmt.Errorf("field library is not a string node but is %T", lib)
+	}
+	return nil
+}
+
+// UnmarshalJSON converts JSON bytes to an ImportNode
+func (n *ImportNode) UnmarshalJSON(data []byte) error {
+	var props JSONNode
+	err := json.Unmarshal(data, &props)
+	if err != nil {
+		return err
+	}
+	return n.unmarshal(props)
+}
+
+func newImport(p position, lib *StringNode, c *CommentNode) *ImportNode {
+	return &ImportNode{
+		position: p,
+		Library:  lib,
+		Comment:  c,
+	}
+}
+
+func (n *ImportNode) String() string {
+	return fmt.Sprintf("ImportNode@%v{%v}%v", n.position, n.Library, n.Comment)
+}
+
+func (n *ImportNode) Format(buf *bytes.Buffer, indent string, onNewLine bool) {
+	if n.Comment != nil {
+		n.Comment.Format(buf, indent, onNewLine)
+	}
+	buf.WriteString(KW_Import)
+	buf.WriteByte(' ')
+	n.Library.Format(buf, indent, false)
+}
+func (n *ImportNode) SetComment(c *CommentNode) {
+	n.Comment = c
+}
+func (n *ImportNode) Equal(o interface{}) bool {
+	if on, ok := o.(*ImportNode); ok {
+		return n.Library.Equal(on.Library)
+	}
+	return false
+}
+
 type ChainNode struct {
 	position
 	Left     Node
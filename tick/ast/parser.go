@@ -238,6 +238,10 @@ func (p *parser) statement() Node {
 		return p.declaration()
 	case TokenDBRP:
 		return p.dbrp()
+	case TokenDef:
+		return p.definition()
+	case TokenImport:
+		return p.importStatement()
 	default:
 		return p.expression()
 	}
@@ -269,6 +273,43 @@ func (p *parser) declaration() Node {
 	}
 }
 
+// parse a definition statement
+func (p *parser) definition() Node {
+	defTok := p.expect(TokenDef)
+	defC := p.consumeComment()
+	name := p.identifier()
+	p.expect(TokenLParen)
+	var params []*IdentifierNode
+	for p.peek().typ != TokenRParen {
+		params = append(params, p.identifier())
+		if p.next().typ != TokenComma {
+			p.backup()
+			break
+		}
+	}
+	p.expect(TokenRParen)
+	p.expect(TokenAsgn)
+	var body Node
+	switch t := p.peek(); t.typ {
+	case TokenLambda:
+		body = p.lambda()
+	case TokenPipe, TokenDot, TokenAt:
+		// The body of a chain fragment is a chain on the node it is called on.
+		body = p.chain(newIdent(p.position(t.pos), "", nil))
+	default:
+		p.unexpected(t, TokenLambda, TokenPipe, TokenDot, TokenAt)
+	}
+	return newDefinition(p.position(defTok.pos), name, params, body, defC)
+}
+
+// parse an import statement
+func (p *parser) importStatement() Node {
+	importTok := p.expect(TokenImport)
+	importC := p.consumeComment()
+	lib := p.string().(*StringNode)
+	return newImport(p.position(importTok.pos), lib, importC)
+}
+
 // parse an expression
 func (p *parser) expression() Node {
 	switch p.peek().typ {
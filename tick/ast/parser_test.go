@@ -2227,6 +2227,90 @@ var x = stream
 				}},
 			},
 		},
+		{
+			script: `import 'lib'
+def above(t) = lambda: "value" > t`,
+			Root: &ProgramNode{
+				position: position{
+					pos:  0,
+					line: 1,
+					char: 1,
+				},
+				Nodes: []Node{
+					&ImportNode{
+						position: position{
+							pos:  0,
+							line: 1,
+							char: 1,
+						},
+						Library: &StringNode{
+							position: position{
+								pos:  7,
+								line: 1,
+								char: 8,
+							},
+							Literal: "lib",
+						},
+					},
+					&DefinitionNode{
+						position: position{
+							pos:  13,
+							line: 2,
+							char: 1,
+						},
+						Name: &IdentifierNode{
+							position: position{
+								pos:  17,
+								line: 2,
+								char: 5,
+							},
+							Ident: "above",
+						},
+						Params: []*IdentifierNode{
+							{
+								position: position{
+									pos:  23,
+									line: 2,
+									char: 11,
+								},
+								Ident: "t",
+							},
+						},
+						Body: &LambdaNode{
+							position: position{
+								pos:  28,
+								line: 2,
+								char: 16,
+							},
+							Expression: &BinaryNode{
+								position: position{
+									pos:  44,
+									line: 2,
+									char: 32,
+								},
+								Operator: TokenGreater,
+								Left: &ReferenceNode{
+									position: position{
+										pos:  36,
+										line: 2,
+										char: 24,
+									},
+									Reference: "value",
+								},
+								Right: &IdentifierNode{
+									position: position{
+										pos:  46,
+										line: 2,
+										char: 34,
+									},
+									Ident: "t",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
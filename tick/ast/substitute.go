@@ -0,0 +1,55 @@
+package ast
+
+// Substitute returns a copy of the tree rooted at n,
+// where each identifier named in nodes is replaced with a copy of the node of that name.
+// Literal nodes are shared between the tree and its copy.
+func Substitute(n Node, nodes map[string]Node) Node {
+	switch node := n.(type) {
+	case *IdentifierNode:
+		if s, ok := nodes[node.Ident]; ok {
+			return Substitute(s, nil)
+		}
+		c := *node
+		return &c
+	case *UnaryNode:
+		c := *node
+		c.Node = Substitute(node.Node, nodes)
+		return &c
+	case *BinaryNode:
+		c := *node
+		c.Left = Substitute(node.Left, nodes)
+		c.Right = Substitute(node.Right, nodes)
+		return &c
+	case *ChainNode:
+		c := *node
+		c.Left = Substitute(node.Left, nodes)
+		// The identifier on the right of a chain is a property name, not a value.
+		if _, ok := node.Right.(*IdentifierNode); !ok {
+			c.Right = Substitute(node.Right, nodes)
+		}
+		return &c
+	case *FunctionNode:
+		c := *node
+		c.Args = make([]Node, len(node.Args))
+		for i, arg := range node.Args {
+			c.Args[i] = Substitute(arg, nodes)
+		}
+		return &c
+	case *LambdaNode:
+		c := *node
+		c.Expression = Substitute(node.Expression, nodes)
+		return &c
+	case *ListNode:
+		c := *node
+		c.Nodes = make([]Node, len(node.Nodes))
+		for i, item := range node.Nodes {
+			c.Nodes[i] = Substitute(item, nodes)
+		}
+		return &c
+	case *DeclarationNode:
+		c := *node
+		c.Right = Substitute(node.Right, nodes)
+		return &c
+	}
+	return n
+}
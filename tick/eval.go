@@ -155,6 +155,16 @@ func eval(n ast.Node, scope *stateful.Scope, stck *stack, predefinedVars, defaul
 			nodes[i] = a
 		}
 		stck.Push(nodes)
+	case *ast.DefinitionNode:
+		err = evalDefinition(node, scope)
+		if err != nil {
+			return
+		}
+	case *ast.ImportNode:
+		err = evalImport(node, scope)
+		if err != nil {
+			return
+		}
 	case *ast.TypeDeclarationNode:
 		err = evalTypeDeclaration(node, scope, predefinedVars, defaultVars, ignoreMissingVars)
 		if err != nil {
@@ -213,6 +223,11 @@ func eval(n ast.Node, scope *stateful.Scope, stck *stack, predefinedVars, defaul
 		}
 	case *ast.ProgramNode:
 		for _, n := range node.Nodes {
+			// Definitions are only available to the statements that follow them.
+			n, err = expandDefinitions(n, scope, 0, false)
+			if err != nil {
+				return
+			}
 			err = eval(n, scope, stck, predefinedVars, defaultVars, ignoreMissingVars)
 			if err != nil {
 				return
@@ -412,6 +427,174 @@ func evalDeclaration(node *ast.DeclarationNode, scope *stateful.Scope, stck *sta
 	return nil
 }
 
+// EvaluateLibrary parses and evaluates a library script for the scope,
+// its definitions are available to scripts evaluated afterwards for the same scope.
+// A library may only contain definitions and imports.
+func EvaluateLibrary(script string, scope *stateful.Scope) error {
+	root, err := ast.Parse(script)
+	if err != nil {
+		return err
+	}
+	for _, n := range root.(*ast.ProgramNode).Nodes {
+		switch node := n.(type) {
+		case *ast.DefinitionNode:
+			err = evalDefinition(node, scope)
+		case *ast.ImportNode:
+			err = evalImport(node, scope)
+		case *ast.CommentNode:
+		default:
+			err = errorf(n, "a library may only contain def and import statements")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func evalDefinition(node *ast.DefinitionNode, scope *stateful.Scope) error {
+	name := node.Name.Ident
+	if v, _ := scope.Get(name); v != nil {
+		return errorf(node, "attempted to redefine %s", name)
+	}
+	if scope.DynamicMethod(name) != nil {
+		return errorf(node, "cannot define %s, a UDF with the same name exists", name)
+	}
+	params := make(map[string]bool, len(node.Params))
+	for _, p := range node.Params {
+		if params[p.Ident] {
+			return errorf(p, "duplicate parameter %s of %s", p.Ident, name)
+		}
+		params[p.Ident] = true
+	}
+	scope.Set(name, node)
+	return nil
+}
+
+func evalImport(node *ast.ImportNode, scope *stateful.Scope) error {
+	name := node.Library.Literal
+	script, loaded, err := scope.LoadLibrary(name)
+	if err != nil {
+		return wrapError(node, err)
+	}
+	if loaded {
+		return nil
+	}
+	if err := EvaluateLibrary(script, scope); err != nil {
+		return wrapError(node, fmt.Errorf("library %q: %v", name, err))
+	}
+	return nil
+}
+
+// maxDefinitionDepth limits how deeply definitions are expanded within each other,
+// so that recursive definitions fail instead of expanding forever.
+const maxDefinitionDepth = 32
+
+// expandDefinitions replaces the calls of definitions in the tree with their bodies.
+// Lambda definitions are called like functions, chain fragments are called with the '@' operator.
+// A lambda definition called within a lambda expression is expanded to its expression,
+// otherwise it is expanded to the whole lambda.
+func expandDefinitions(n ast.Node, scope *stateful.Scope, depth int, inLambda bool) (_ ast.Node, err error) {
+	switch node := n.(type) {
+	case *ast.DeclarationNode:
+		node.Right, err = expandDefinitions(node.Right, scope, depth, inLambda)
+	case *ast.ChainNode:
+		node.Left, err = expandDefinitions(node.Left, scope, depth, inLambda)
+		if err != nil {
+			return nil, err
+		}
+		if f, ok := node.Right.(*ast.FunctionNode); ok && node.Operator == ast.TokenAt {
+			if def := lookupDefinition(f.Func, scope); def != nil {
+				if !def.IsFragment() {
+					return nil, errorf(f, "%s is a lambda definition, call it as a function: '%s(..)'", f.Func, f.Func)
+				}
+				expanded, err := expandDefinition(def, f, node.Left, depth, inLambda)
+				if err != nil {
+					return nil, err
+				}
+				return expandDefinitions(expanded, scope, depth+1, inLambda)
+			}
+		}
+		node.Right, err = expandDefinitions(node.Right, scope, depth, inLambda)
+	case *ast.LambdaNode:
+		node.Expression, err = expandDefinitions(node.Expression, scope, depth, true)
+	case *ast.UnaryNode:
+		node.Node, err = expandDefinitions(node.Node, scope, depth, inLambda)
+	case *ast.BinaryNode:
+		node.Left, err = expandDefinitions(node.Left, scope, depth, inLambda)
+		if err != nil {
+			return nil, err
+		}
+		node.Right, err = expandDefinitions(node.Right, scope, depth, inLambda)
+	case *ast.ListNode:
+		for i := range node.Nodes {
+			node.Nodes[i], err = expandDefinitions(node.Nodes[i], scope, depth, inLambda)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case *ast.FunctionNode:
+		for i := range node.Args {
+			node.Args[i], err = expandDefinitions(node.Args[i], scope, depth, inLambda)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if node.Type != ast.GlobalFunc {
+			break
+		}
+		if def := lookupDefinition(node.Func, scope); def != nil {
+			if def.IsFragment() {
+				return nil, errorf(node, "%s is a chain fragment, call it with the '@' operator: 'node@%s(..)'", node.Func, node.Func)
+			}
+			expanded, err := expandDefinition(def, node, nil, depth, inLambda)
+			if err != nil {
+				return nil, err
+			}
+			return expandDefinitions(expanded, scope, depth+1, inLambda)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// lookupDefinition returns the definition of the name, or nil if the name is not a definition.
+func lookupDefinition(name string, scope *stateful.Scope) *ast.DefinitionNode {
+	v, _ := scope.Get(name)
+	def, _ := v.(*ast.DefinitionNode)
+	return def
+}
+
+// expandDefinition returns the body of the definition called by f, with its parameters replaced by the arguments.
+// A chain fragment is expanded onto the node it is called on.
+func expandDefinition(def *ast.DefinitionNode, f *ast.FunctionNode, on ast.Node, depth int, inLambda bool) (ast.Node, error) {
+	if depth >= maxDefinitionDepth {
+		return nil, errorf(f, "definitions are nested too deeply when calling %s, is it recursive?", f.Func)
+	}
+	if len(f.Args) != len(def.Params) {
+		return nil, errorf(f, "%s expects %d arguments, got %d", f.Func, len(def.Params), len(f.Args))
+	}
+	args := make(map[string]ast.Node, len(f.Args)+1)
+	for i, p := range def.Params {
+		args[p.Ident] = f.Args[i]
+	}
+	if def.IsFragment() {
+		// The chain of a fragment starts on the identifier with the empty name.
+		args[""] = on
+		return ast.Substitute(def.Body, args), nil
+	}
+	if !inLambda {
+		return ast.Substitute(def.Body, args), nil
+	}
+	expr := ast.Substitute(def.Body.(*ast.LambdaNode).Expression, args)
+	if b, ok := expr.(*ast.BinaryNode); ok {
+		b.Parens = true
+	}
+	return expr, nil
+}
+
 func evalChain(p ast.Position, scope *stateful.Scope, stck *stack) error {
 	r := stck.Pop()
 	l := stck.Pop()
@@ -1484,3 +1484,153 @@ func TestReflectionDescriberErrors(t *testing.T) {
 	}
 
 }
+
+func TestEvaluate_Definitions(t *testing.T) {
+	script := `
+def above(threshold) = lambda: "value" > threshold
+
+def setup(f2) =
+    |structB()
+        .field1('f1')
+        .field2(f2)
+
+var s2 = a@setup(42)
+
+var l = lambda: above(10) AND "ok"
+
+var l2 = above(5)
+`
+	scope := stateful.NewScope()
+	scope.Set("a", &structA{})
+
+	if _, err := tick.Evaluate(script, scope, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	s2I, err := scope.Get("s2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := (structB{Field1: "f1", Field2: 42}), *s2I.(*structB); !reflect.DeepEqual(exp, got) {
+		t.Errorf("unexpected s2 exp:%v got:%v", exp, got)
+	}
+
+	lI, err := scope.Get("l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := `lambda: ("value" > 10) AND "ok"`, ast.Format(lI.(*ast.LambdaNode)); got != exp {
+		t.Errorf("unexpected lambda exp:%s got:%s", exp, got)
+	}
+
+	l2I, err := scope.Get("l2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := `lambda: "value" > 5`, ast.Format(l2I.(*ast.LambdaNode)); got != exp {
+		t.Errorf("unexpected lambda exp:%s got:%s", exp, got)
+	}
+}
+
+func TestEvaluate_Import(t *testing.T) {
+	libraries := map[string]string{
+		"structs": `
+import 'common'
+
+def setup() =
+    |structB()
+        @named()
+        .field2(42)
+`,
+		"common": `def named() = .field1('f1')`,
+	}
+	scope := stateful.NewScope()
+	scope.Set("a", &structA{})
+	scope.SetLibraryLoader(func(name string) (string, error) {
+		script, ok := libraries[name]
+		if !ok {
+			return "", fmt.Errorf("no library %s", name)
+		}
+		return script, nil
+	})
+
+	script := `
+import 'structs'
+import 'common'
+
+var s2 = a@setup()
+`
+	if _, err := tick.Evaluate(script, scope, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	s2I, err := scope.Get("s2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := (structB{Field1: "f1", Field2: 42}), *s2I.(*structB); !reflect.DeepEqual(exp, got) {
+		t.Errorf("unexpected s2 exp:%v got:%v", exp, got)
+	}
+}
+
+func TestEvaluate_DefinitionErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		script    string
+		libraries map[string]string
+		err       string
+	}{
+		{
+			name: "wrong number of arguments",
+			script: `def above(threshold) = lambda: "value" > threshold
+var l = lambda: above()`,
+			err: "above expects 1 arguments, got 0",
+		},
+		{
+			name: "recursive",
+			script: `def loop() = @loop()
+var x = a@loop()`,
+			err: "is it recursive?",
+		},
+		{
+			name: "fragment as function",
+			script: `def setup() = |structB()
+var l = lambda: setup()`,
+			err: "setup is a chain fragment",
+		},
+		{
+			name: "redefined",
+			script: `def f() = lambda: 1
+def f() = lambda: 2`,
+			err: "attempted to redefine f",
+		},
+		{
+			name:   "no library loader",
+			script: `import 'lib'`,
+			err:    `cannot import library "lib"`,
+		},
+		{
+			name:      "library with statements",
+			script:    `import 'lib'`,
+			libraries: map[string]string{"lib": `var x = 1`},
+			err:       "a library may only contain def and import statements",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scope := stateful.NewScope()
+			scope.Set("a", &structA{})
+			if tc.libraries != nil {
+				scope.SetLibraryLoader(func(name string) (string, error) {
+					return tc.libraries[name], nil
+				})
+			}
+			_, err := tick.Evaluate(tc.script, scope, nil, false)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("unexpected error exp to contain:%q got:%q", tc.err, err)
+			}
+		})
+	}
+}
@@ -366,6 +366,28 @@ max
     |influxDBOut()
         .database('game')
         .measurement('top_scores_gap')
+`,
+		},
+		{
+			script: `import 'alerts'
+// Critical threshold
+def crit(f,t)=lambda: f  > t
+def  threshold(field, level) = |alert().crit(crit(field, level))|log()
+stream|from()@threshold("cpu", 90)
+`,
+			exp: `import 'alerts'
+
+// Critical threshold
+def crit(f, t) = lambda: f > t
+
+def threshold(field, level) =
+    |alert()
+        .crit(crit(field, level))
+    |log()
+
+stream
+    |from()
+    @threshold("cpu", 90)
 `,
 		},
 	}
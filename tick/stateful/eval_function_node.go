@@ -50,6 +50,10 @@ func (n *EvalFunctionNode) Type(scope ReadOnlyScope) (ast.ValueType, error) {
 	signature := f.Signature()
 
 	domain := Domain{}
+	if gotLen, expLen := len(n.argsEvaluators), len(domain); gotLen > expLen {
+		err := ErrWrongFuncSignature{Name: n.funcName, DomainProvided: domain, Func: f}
+		return ast.InvalidType, errors.Wrapf(err, "too many arguments provided")
+	}
 	for i, argEvaluator := range n.argsEvaluators {
 		t, err := argEvaluator.Type(scope)
 		if err != nil {
@@ -58,11 +62,6 @@ func (n *EvalFunctionNode) Type(scope ReadOnlyScope) (ast.ValueType, error) {
 		domain[i] = t
 	}
 
-	if gotLen, expLen := len(n.argsEvaluators), len(domain); gotLen > expLen {
-		err := ErrWrongFuncSignature{Name: n.funcName, DomainProvided: domain, Func: f}
-		return ast.InvalidType, errors.Wrapf(err, "too many arguments provided")
-	}
-
 	retType, ok := signature[domain]
 	if !ok {
 		args := []string{}
@@ -112,7 +111,22 @@ func (n *EvalFunctionNode) callFunction(scope *Scope, executionState ExecutionSt
 		return ast.InvalidType, fmt.Errorf("undefined function: %q", n.funcName)
 	}
 
-	ret, err := f.Call(args...)
+	var ret interface{}
+	var err error
+	if tf, ok := f.(timeFunc); ok {
+		// The time of the point is always in the scope of a lambda expression.
+		t, terr := scope.Get("time")
+		if terr != nil {
+			return nil, fmt.Errorf("error calling %q: %s", n.funcName, terr)
+		}
+		tt, ok := t.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("error calling %q: time is %T, must be a time", n.funcName, t)
+		}
+		ret, err = tf.CallAt(tt, args...)
+	} else {
+		ret, err = f.Call(args...)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error calling %q: %s", n.funcName, err)
 	}
@@ -48,6 +48,34 @@ func TestExpression_EvalNum_KeepsFunctionsState(t *testing.T) {
 
 }
 
+func TestExpression_EvalNum_RateUsesPointTime(t *testing.T) {
+	se := mustCompileExpression(&ast.FunctionNode{
+		Func: "rate",
+		Args: []ast.Node{
+			&ast.ReferenceNode{Reference: "value"},
+			&ast.DurationNode{Dur: time.Second},
+		},
+	})
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	scope := stateful.NewScope()
+	scope.Set("value", float64(10))
+	scope.Set("time", start)
+	if _, err := se.Eval(scope); err != nil {
+		t.Fatal(err)
+	}
+
+	scope.Set("value", float64(40))
+	scope.Set("time", start.Add(10*time.Second))
+	result, err := se.Eval(scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != float64(3) {
+		t.Errorf("expected rate to be float64(3) but got %v", result)
+	}
+}
+
 func TestExpression_EvalBool_BinaryNodeWithDurationNode(t *testing.T) {
 	leftValues := []interface{}{time.Duration(5), time.Duration(10)}
 	rightValues := []interface{}{time.Duration(5), time.Duration(10), int64(5)}
@@ -1024,6 +1052,51 @@ func TestExpression_EvalString_StringConcatReferenceNode(t *testing.T) {
 	}
 }
 
+func TestExpression_EvalString_Sprintf(t *testing.T) {
+	se := mustCompileExpression(&ast.FunctionNode{
+		Func: "sprintf",
+		Args: []ast.Node{
+			&ast.StringNode{Literal: "%s/%d/%v/%s"},
+			&ast.ReferenceNode{Reference: "host"},
+			&ast.ReferenceNode{Reference: "count"},
+			&ast.DurationNode{Dur: time.Minute},
+			&ast.FunctionNode{
+				Func: "jsonExtract",
+				Args: []ast.Node{
+					&ast.ReferenceNode{Reference: "payload"},
+					&ast.StringNode{Literal: "region"},
+				},
+			},
+		},
+	})
+	scope := stateful.NewScope()
+	scope.Set("host", "serverA")
+	scope.Set("count", int64(3))
+	scope.Set("payload", `{"region":"eu"}`)
+
+	if typ, err := se.Type(scope); err != nil {
+		t.Fatal("unexpected error checking type:", err)
+	} else if typ != ast.TString {
+		t.Errorf("unexpected type: got %v exp %v", typ, ast.TString)
+	}
+	result, err := se.EvalString(scope)
+	if err != nil {
+		t.Fatal("unexpected error EvalString:", err)
+	}
+	if exp := "serverA/3/1m0s/eu"; exp != result {
+		t.Errorf("unexpected EvalString results: got %s exp %s", result, exp)
+	}
+
+	// The format must be a string.
+	se = mustCompileExpression(&ast.FunctionNode{
+		Func: "sprintf",
+		Args: []ast.Node{&ast.ReferenceNode{Reference: "count"}},
+	})
+	if _, err := se.Type(scope); err == nil {
+		t.Error("expected error checking type of sprintf with an int format")
+	}
+}
+
 func TestExpression_EvalNum_BinaryNodeWithUnary(t *testing.T) {
 
 	// -"value" < 0 , yes, of course, this is always true..
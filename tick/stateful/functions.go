@@ -1,13 +1,16 @@
 package stateful
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
@@ -23,7 +26,7 @@ var ErrNotInt = errors.New("value is not an int")
 // Increment this value if you create a builtin function with more than
 // the current value of maxArgs.
 const (
-	maxArgs = 4
+	maxArgs = 5
 )
 
 type ErrMissingType struct {
@@ -213,6 +216,19 @@ func init() {
 	statelessFuncs["strTrimRight"] = newString2String("strTrimRight", strings.TrimRight)
 	statelessFuncs["strTrimSpace"] = newString1String("strTrimSpace", strings.TrimSpace)
 	statelessFuncs["strTrimSuffix"] = newString2String("strTrimSuffix", strings.TrimSuffix)
+	statelessFuncs["strSplit"] = strSplit{}
+	statelessFuncs["strJoin"] = strJoin{}
+	statelessFuncs["sprintf"] = sprintf{}
+
+	// JSON functions
+	statelessFuncs["jsonExtract"] = jsonExtract{name: "jsonExtract", ret: ast.TString}
+	statelessFuncs["jsonExtractFloat"] = jsonExtract{name: "jsonExtractFloat", ret: ast.TFloat}
+	statelessFuncs["jsonExtractInt"] = jsonExtract{name: "jsonExtractInt", ret: ast.TInt}
+	statelessFuncs["jsonExtractBool"] = jsonExtract{name: "jsonExtractBool", ret: ast.TBool}
+
+	// Hash functions
+	statelessFuncs["fnv"] = fnvHash{}
+	statelessFuncs["hash"] = hashBucket{}
 
 	// Regex functions
 	statelessFuncs["regexReplace"] = regexReplace{}
@@ -229,6 +245,7 @@ func init() {
 	statelessFuncs["month"] = month{}
 	statelessFuncs["year"] = year{}
 	statelessFuncs["now"] = now{}
+	// inSchedule depends on the schedules of a task master, see NewInScheduleFunc.
 
 	// Humanize functions
 	statelessFuncs["humanBytes"] = humanBytes{}
@@ -242,7 +259,7 @@ func init() {
 
 // Return set of built-in Funcs
 func NewFunctions() Funcs {
-	funcs := make(Funcs, len(statelessFuncs)+3)
+	funcs := make(Funcs, len(statelessFuncs)+11)
 	for n, f := range statelessFuncs {
 		funcs[n] = f
 	}
@@ -252,6 +269,13 @@ func NewFunctions() Funcs {
 	funcs["count"] = &count{}
 	funcs["spread"] = &spread{min: math.Inf(+1), max: math.Inf(-1)}
 	funcs["rand"] = NewRand()
+	funcs["ewma"] = &ewma{}
+	funcs["delta"] = &delta{}
+	funcs["rate"] = &rate{}
+	funcs["lag"] = &lag{}
+	funcs["minSoFar"] = &soFar{name: "minSoFar", f: math.Min}
+	funcs["maxSoFar"] = &soFar{name: "maxSoFar", f: math.Max}
+	funcs["cumsum"] = &cumsum{}
 
 	return funcs
 }
@@ -790,6 +814,344 @@ func (regexReplace) Signature() map[Domain]ast.ValueType {
 
 func (m regexReplace) Reset() {}
 
+// scalarTypes are the types of values that can be passed to functions accepting any value.
+var scalarTypes = []ast.ValueType{
+	ast.TFloat,
+	ast.TInt,
+	ast.TString,
+	ast.TBool,
+	ast.TDuration,
+	ast.TTime,
+}
+
+// addVariadicSignatures adds to sig a domain for every combination of the fixed argument types
+// followed by between min and maxArgs-len(fixed) arguments of the variadic types.
+func addVariadicSignatures(sig map[Domain]ast.ValueType, fixed, variadic []ast.ValueType, min int, ret ast.ValueType) {
+	d := Domain{}
+	copy(d[:], fixed)
+	var fill func(i int)
+	fill = func(i int) {
+		if i-len(fixed) >= min {
+			sig[d] = ret
+		}
+		if i == maxArgs {
+			return
+		}
+		for _, t := range variadic {
+			d[i] = t
+			fill(i + 1)
+		}
+		d[i] = ast.InvalidType
+	}
+	fill(len(fixed))
+}
+
+type strSplit struct {
+}
+
+// Return the element at index of the string split by the separator.
+// A negative index counts back from the last element.
+func (strSplit) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) != 3 {
+		return nil, errors.New("strSplit expects exactly three arguments")
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as first arg to strSplit, must be string", args[0])
+	}
+	sep, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as second arg to strSplit, must be string", args[1])
+	}
+	index, ok := args[2].(int64)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as third arg to strSplit, must be int", args[2])
+	}
+	parts := strings.Split(str, sep)
+	i := int(index)
+	if i < 0 {
+		i += len(parts)
+	}
+	if i < 0 || i >= len(parts) {
+		return nil, fmt.Errorf("index %d out of range for strSplit, string has %d elements", index, len(parts))
+	}
+	return parts[i], nil
+}
+
+var strSplitFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize String Split Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TString
+	d[1] = ast.TString
+	d[2] = ast.TInt
+	strSplitFuncSignature[d] = ast.TString
+}
+
+func (strSplit) Signature() map[Domain]ast.ValueType {
+	return strSplitFuncSignature
+}
+
+func (strSplit) Reset() {}
+
+type strJoin struct {
+}
+
+// Return the strings joined by the separator given as the first argument.
+func (strJoin) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) < 2 {
+		return nil, errors.New("strJoin expects at least two arguments")
+	}
+	strs := make([]string, len(args))
+	for i, a := range args {
+		s, ok := a.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot pass %T as arg %d to strJoin, must be string", a, i+1)
+		}
+		strs[i] = s
+	}
+	return strings.Join(strs[1:], strs[0]), nil
+}
+
+var strJoinFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize String Join Function Signature
+func init() {
+	addVariadicSignatures(strJoinFuncSignature, []ast.ValueType{ast.TString}, []ast.ValueType{ast.TString}, 1, ast.TString)
+}
+
+func (strJoin) Signature() map[Domain]ast.ValueType {
+	return strJoinFuncSignature
+}
+
+func (strJoin) Reset() {}
+
+type sprintf struct {
+}
+
+// Return the arguments formatted according to the format given as the first argument,
+// see https://golang.org/pkg/fmt/ for the format verbs.
+func (sprintf) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) < 1 {
+		return nil, errors.New("sprintf expects at least one argument")
+	}
+	format, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as first arg to sprintf, must be string", args[0])
+	}
+	return fmt.Sprintf(format, args[1:]...), nil
+}
+
+var sprintfFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Sprintf Function Signature
+func init() {
+	addVariadicSignatures(sprintfFuncSignature, []ast.ValueType{ast.TString}, scalarTypes, 0, ast.TString)
+}
+
+func (sprintf) Signature() map[Domain]ast.ValueType {
+	return sprintfFuncSignature
+}
+
+func (sprintf) Reset() {}
+
+type jsonExtract struct {
+	name string
+	ret  ast.ValueType
+}
+
+// Return the value at the path within the JSON document.
+// The path is a list of object keys and array indexes separated by '.', array indexes may also be written as [n],
+// for example 'items[0].name' or 'items.0.name'. An empty path returns the whole document.
+// jsonExtract returns strings as is and any other value as JSON,
+// the typed variants fail if the value is not of their type.
+func (f jsonExtract) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) != 2 {
+		return nil, errors.New(f.name + " expects exactly two arguments")
+	}
+	doc, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as first arg to %s, must be string", args[0], f.name)
+	}
+	p, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as second arg to %s, must be string", args[1], f.name)
+	}
+	value, err := extractJSON(doc, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", f.name, err)
+	}
+	switch f.ret {
+	case ast.TFloat, ast.TInt:
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s: value at path %q is not a number", f.name, p)
+		}
+		if f.ret == ast.TInt {
+			i, err := n.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("%s: value at path %q is not an integer", f.name, p)
+			}
+			return i, nil
+		}
+		return n.Float64()
+	case ast.TBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: value at path %q is not a boolean", f.name, p)
+		}
+		return b, nil
+	default:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.name, err)
+		}
+		return string(data), nil
+	}
+}
+
+var jsonExtractFuncSignatures = map[ast.ValueType]map[Domain]ast.ValueType{}
+
+// Initialize JSON Extract Function Signatures
+func init() {
+	for _, ret := range []ast.ValueType{ast.TString, ast.TFloat, ast.TInt, ast.TBool} {
+		d := Domain{}
+		d[0] = ast.TString
+		d[1] = ast.TString
+		jsonExtractFuncSignatures[ret] = map[Domain]ast.ValueType{d: ret}
+	}
+}
+
+func (f jsonExtract) Signature() map[Domain]ast.ValueType {
+	return jsonExtractFuncSignatures[f.ret]
+}
+
+func (jsonExtract) Reset() {}
+
+// extractJSON decodes the JSON document and returns the value at the path.
+// Numbers are returned as json.Number so integers keep their precision.
+func extractJSON(doc, p string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if p == "" {
+		return v, nil
+	}
+	// Normalize [n] array indexes to .n
+	p = strings.NewReplacer("[", ".", "]", "").Replace(p)
+	for _, key := range strings.Split(strings.TrimPrefix(p, "."), ".") {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			elem, ok := value[key]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found, no key %q", p, key)
+			}
+			v = elem
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("path %q not found, %q is not an array index", p, key)
+			}
+			if i < 0 || i >= len(value) {
+				return nil, fmt.Errorf("path %q not found, index %d out of range", p, i)
+			}
+			v = value[i]
+		default:
+			return nil, fmt.Errorf("path %q not found, cannot get %q of a scalar value", p, key)
+		}
+	}
+	return v, nil
+}
+
+type fnvHash struct {
+}
+
+// Return the 64-bit FNV-1a hash of the string.
+func (fnvHash) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) != 1 {
+		return nil, errors.New("fnv expects exactly one argument")
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T to fnv, must be string", args[0])
+	}
+	h := fnv.New64a()
+	h.Write([]byte(str))
+	return int64(h.Sum64()), nil
+}
+
+var fnvFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize FNV Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TString
+	fnvFuncSignature[d] = ast.TInt
+}
+
+func (fnvHash) Signature() map[Domain]ast.ValueType {
+	return fnvFuncSignature
+}
+
+func (fnvHash) Reset() {}
+
+type hashBucket struct {
+}
+
+// Return a stable non-negative hash of the string.
+// If a number of buckets is given the hash is within the range [0,buckets),
+// so the same string is always assigned to the same bucket.
+func (hashBucket) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.New("hash expects one or two arguments")
+	}
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as first arg to hash, must be string", args[0])
+	}
+	h := fnv.New64a()
+	h.Write([]byte(str))
+	sum := h.Sum64()
+	if len(args) == 1 {
+		return int64(sum >> 1), nil
+	}
+	buckets, ok := args[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as second arg to hash, must be int", args[1])
+	}
+	if buckets <= 0 {
+		return nil, fmt.Errorf("hash buckets must be positive, got %d", buckets)
+	}
+	return int64(sum % uint64(buckets)), nil
+}
+
+var hashFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Hash Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TString
+	hashFuncSignature[d] = ast.TInt
+
+	d = Domain{}
+	d[0] = ast.TString
+	d[1] = ast.TInt
+	hashFuncSignature[d] = ast.TInt
+}
+
+func (hashBucket) Signature() map[Domain]ast.ValueType {
+	return hashFuncSignature
+}
+
+func (hashBucket) Reset() {}
+
 type boolean struct {
 }
 
@@ -1220,6 +1582,307 @@ func (s *spread) Signature() map[Domain]ast.ValueType {
 	return spreadFuncSignature
 }
 
+// A timeFunc is a Func that also needs the time of the point being evaluated.
+type timeFunc interface {
+	Func
+	CallAt(t time.Time, args ...interface{}) (interface{}, error)
+}
+
+type ewma struct {
+	avg float64
+	n   int64
+}
+
+func (e *ewma) Reset() {
+	e.avg = 0
+	e.n = 0
+}
+
+// Computes the exponentially weighted moving average of the values,
+// where alpha is the weight of the latest value in the range (0, 1].
+func (e *ewma) Call(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return 0, errors.New("ewma expects exactly two arguments")
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	alpha, ok := args[1].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("ewma alpha must be in the range (0, 1], got %v", alpha)
+	}
+	if e.n == 0 {
+		e.avg = x
+	} else {
+		e.avg = alpha*x + (1-alpha)*e.avg
+	}
+	e.n++
+	return e.avg, nil
+}
+
+var ewmaFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize EWMA Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TFloat
+	d[1] = ast.TFloat
+	ewmaFuncSignature[d] = ast.TFloat
+}
+
+func (e *ewma) Signature() map[Domain]ast.ValueType {
+	return ewmaFuncSignature
+}
+
+type delta struct {
+	previous float64
+	n        int64
+}
+
+func (d *delta) Reset() {
+	d.previous = 0
+	d.n = 0
+}
+
+// Computes the difference between the value and the previous value.
+// The difference of the first value is zero.
+func (d *delta) Call(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return 0, errors.New("delta expects exactly one argument")
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	diff := 0.0
+	if d.n > 0 {
+		diff = x - d.previous
+	}
+	d.previous = x
+	d.n++
+	return diff, nil
+}
+
+var deltaFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Delta Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TFloat
+	deltaFuncSignature[d] = ast.TFloat
+}
+
+func (d *delta) Signature() map[Domain]ast.ValueType {
+	return deltaFuncSignature
+}
+
+type rate struct {
+	previous     float64
+	previousTime time.Time
+	last         float64
+	n            int64
+}
+
+func (r *rate) Reset() {
+	r.previous = 0
+	r.previousTime = time.Time{}
+	r.last = 0
+	r.n = 0
+}
+
+func (r *rate) Call(args ...interface{}) (interface{}, error) {
+	return nil, errors.New("rate needs the time of the point")
+}
+
+// Computes the rate of change of the value per unit, using the time of the point.
+// The rate of the first value is zero, and a point that is not later than the previous point
+// gets the rate of the previous point.
+func (r *rate) CallAt(t time.Time, args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return 0, errors.New("rate expects exactly two arguments")
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	unit, ok := args[1].(time.Duration)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as the unit of rate, must be a duration", args[1])
+	}
+	if unit <= 0 {
+		return nil, fmt.Errorf("rate unit must be positive, got %v", unit)
+	}
+	if r.n > 0 {
+		if !t.After(r.previousTime) {
+			return r.last, nil
+		}
+		r.last = (x - r.previous) / (float64(t.Sub(r.previousTime)) / float64(unit))
+	}
+	r.previous = x
+	r.previousTime = t
+	r.n++
+	return r.last, nil
+}
+
+var rateFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Rate Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TFloat
+	d[1] = ast.TDuration
+	rateFuncSignature[d] = ast.TFloat
+}
+
+func (r *rate) Signature() map[Domain]ast.ValueType {
+	return rateFuncSignature
+}
+
+// maxLag is the largest n of lag, the values kept by lag grow with n.
+const maxLag = 100000
+
+type lag struct {
+	n      int64
+	values []interface{}
+	// The index of the oldest value in the ring of values.
+	i int
+}
+
+func (l *lag) Reset() {
+	l.n = 0
+	l.values = nil
+	l.i = 0
+}
+
+// Returns the value from n values ago.
+// While fewer than n values were seen, the first value is returned.
+func (l *lag) Call(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return 0, errors.New("lag expects exactly two arguments")
+	}
+	n, ok := args[1].(int64)
+	if !ok {
+		return nil, ErrNotInt
+	}
+	if n < 1 || n > maxLag {
+		return nil, fmt.Errorf("lag n must be between 1 and %d, got %d", maxLag, n)
+	}
+	x := args[0]
+	if l.n == 0 {
+		l.n = n
+	}
+	if n != l.n {
+		return nil, fmt.Errorf("lag n must not change, got %d after %d", n, l.n)
+	}
+	// The ring grows with the values seen instead of being allocated for n values up front.
+	if int64(len(l.values)) < n {
+		l.values = append(l.values, x)
+		return l.values[0], nil
+	}
+	v := l.values[l.i]
+	l.values[l.i] = x
+	l.i = (l.i + 1) % len(l.values)
+	return v, nil
+}
+
+var lagFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Lag Function Signature
+func init() {
+	for _, t := range []ast.ValueType{ast.TFloat, ast.TInt, ast.TString, ast.TBool} {
+		d := Domain{}
+		d[0] = t
+		d[1] = ast.TInt
+		lagFuncSignature[d] = t
+	}
+}
+
+func (l *lag) Signature() map[Domain]ast.ValueType {
+	return lagFuncSignature
+}
+
+// soFar keeps the running result of f over all values, used for minSoFar and maxSoFar.
+type soFar struct {
+	name  string
+	f     func(float64, float64) float64
+	value float64
+	n     int64
+}
+
+func (s *soFar) Reset() {
+	s.value = 0
+	s.n = 0
+}
+
+// Computes the running minimum or maximum of all values.
+func (s *soFar) Call(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return 0, errors.New(s.name + " expects exactly one argument")
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	if s.n == 0 {
+		s.value = x
+	} else {
+		s.value = s.f(s.value, x)
+	}
+	s.n++
+	return s.value, nil
+}
+
+var soFarFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize SoFar Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TFloat
+	soFarFuncSignature[d] = ast.TFloat
+}
+
+func (s *soFar) Signature() map[Domain]ast.ValueType {
+	return soFarFuncSignature
+}
+
+type cumsum struct {
+	sum float64
+}
+
+func (c *cumsum) Reset() {
+	c.sum = 0
+}
+
+// Computes the running sum of all values.
+func (c *cumsum) Call(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return 0, errors.New("cumsum expects exactly one argument")
+	}
+	x, ok := args[0].(float64)
+	if !ok {
+		return nil, ErrNotFloat
+	}
+	c.sum += x
+	return c.sum, nil
+}
+
+var cumsumFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Cumsum Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TFloat
+	cumsumFuncSignature[d] = ast.TFloat
+}
+
+func (c *cumsum) Signature() map[Domain]ast.ValueType {
+	return cumsumFuncSignature
+}
+
 // Time function signatures
 var timeFuncSignature = map[Domain]ast.ValueType{}
 
@@ -1254,28 +1917,74 @@ func (unixNano) Signature() map[Domain]ast.ValueType {
 	return timeFuncSignature
 }
 
+// Zoned time function signatures, the optional second argument is the name of a timezone.
+var zonedTimeFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize Zoned Time Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TTime
+	zonedTimeFuncSignature[d] = ast.TInt
+	d[1] = ast.TString
+	zonedTimeFuncSignature[d] = ast.TInt
+}
+
+// locations caches the loaded timezones by name.
+var locations sync.Map
+
+// zonedTime returns the time argument of the named function,
+// in the timezone of the optional second argument, such as 'Europe/Berlin'.
+func zonedTime(name string, args []interface{}) (time.Time, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return time.Time{}, errors.New(name + " expects one or two arguments")
+	}
+	t, ok := args[0].(time.Time)
+	if !ok {
+		return time.Time{}, fmt.Errorf("cannot convert %T to time.Time", args[0])
+	}
+	if len(args) == 1 {
+		return t, nil
+	}
+	tz, ok := args[1].(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("cannot pass %T as the timezone of %s, must be a string", args[1], name)
+	}
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if l, ok := locations.Load(name); ok {
+		return l.(*time.Location), nil
+	}
+	l, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	locations.Store(name, l)
+	return l, nil
+}
+
 type minute struct {
 }
 
 func (minute) Reset() {
 }
 
-// Return the minute within the hour for the given time, within the range [0,59].
+// Return the minute within the hour for the given time, optionally in the given timezone, within the range [0,59].
 func (minute) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("minute expects exactly one argument")
+	a, err := zonedTime("minute", args)
+	if err != nil {
+		return 0, err
 	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Minute())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
-	}
-	return
+	return int64(a.Minute()), nil
 }
 
 func (minute) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
 }
 
 type hour struct {
@@ -1284,22 +1993,17 @@ type hour struct {
 func (hour) Reset() {
 }
 
-// Return the hour within the day for the given time, within the range [0,23].
+// Return the hour within the day for the given time, optionally in the given timezone, within the range [0,23].
 func (hour) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("hour expects exactly one argument")
-	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Hour())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
+	a, err := zonedTime("hour", args)
+	if err != nil {
+		return 0, err
 	}
-	return
+	return int64(a.Hour()), nil
 }
 
 func (hour) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
 }
 
 type weekday struct {
@@ -1308,22 +2012,17 @@ type weekday struct {
 func (weekday) Reset() {
 }
 
-// Return the weekday within the week for the given time, within the range [0,6] where 0 is Sunday.
+// Return the weekday within the week for the given time, optionally in the given timezone, within the range [0,6] where 0 is Sunday.
 func (weekday) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("weekday expects exactly one argument")
-	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Weekday())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
+	a, err := zonedTime("weekday", args)
+	if err != nil {
+		return 0, err
 	}
-	return
+	return int64(a.Weekday()), nil
 }
 
 func (weekday) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
 }
 
 type day struct {
@@ -1332,22 +2031,17 @@ type day struct {
 func (day) Reset() {
 }
 
-// Return the day within the month for the given time, within the range [1,31] depending on the month.
+// Return the day within the month for the given time, optionally in the given timezone, within the range [1,31] depending on the month.
 func (day) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("day expects exactly one argument")
-	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Day())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
+	a, err := zonedTime("day", args)
+	if err != nil {
+		return 0, err
 	}
-	return
+	return int64(a.Day()), nil
 }
 
 func (day) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
 }
 
 type month struct {
@@ -1356,22 +2050,17 @@ type month struct {
 func (month) Reset() {
 }
 
-// Return the month within the year for the given time, within the range [1,12].
+// Return the month within the year for the given time, optionally in the given timezone, within the range [1,12].
 func (month) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("month expects exactly one argument")
+	a, err := zonedTime("month", args)
+	if err != nil {
+		return 0, err
 	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Month())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
-	}
-	return
+	return int64(a.Month()), nil
 }
 
 func (month) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
 }
 
 type year struct {
@@ -1380,22 +2069,72 @@ type year struct {
 func (year) Reset() {
 }
 
-// Return the year for the given time.
+// Return the year for the given time, optionally in the given timezone.
 func (year) Call(args ...interface{}) (v interface{}, err error) {
-	if len(args) != 1 {
-		return 0, errors.New("year expects exactly one argument")
-	}
-	switch a := args[0].(type) {
-	case time.Time:
-		v = int64(a.Year())
-	default:
-		err = fmt.Errorf("cannot convert %T to time.Time", a)
+	a, err := zonedTime("year", args)
+	if err != nil {
+		return 0, err
 	}
-	return
+	return int64(a.Year()), nil
 }
 
 func (year) Signature() map[Domain]ast.ValueType {
-	return timeFuncSignature
+	return zonedTimeFuncSignature
+}
+
+// A ScheduleLookup reports whether a time is within a named schedule.
+type ScheduleLookup interface {
+	InSchedule(name string, t time.Time) (bool, error)
+}
+
+// NewInScheduleFunc returns the inSchedule function for the schedules of the lookup.
+// A nil lookup has no schedules.
+func NewInScheduleFunc(l ScheduleLookup) *DynamicFunc {
+	f := inSchedule{lookup: l}
+	return &DynamicFunc{
+		F:   f.Call,
+		Sig: f.Signature(),
+	}
+}
+
+var inScheduleFuncSignature = map[Domain]ast.ValueType{}
+
+// Initialize In Schedule Function Signature
+func init() {
+	d := Domain{}
+	d[0] = ast.TString
+	d[1] = ast.TTime
+	inScheduleFuncSignature[d] = ast.TBool
+}
+
+type inSchedule struct {
+	lookup ScheduleLookup
+}
+
+func (inSchedule) Reset() {
+}
+
+// Return whether the given time is within the named schedule.
+func (f inSchedule) Call(args ...interface{}) (v interface{}, err error) {
+	if len(args) != 2 {
+		return nil, errors.New("inSchedule expects exactly two arguments")
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot pass %T as the schedule name, must be a string", args[0])
+	}
+	t, ok := args[1].(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %T to time.Time", args[1])
+	}
+	if f.lookup == nil {
+		return nil, errors.New("no schedules are available")
+	}
+	return f.lookup.InSchedule(name, t)
+}
+
+func (inSchedule) Signature() map[Domain]ast.ValueType {
+	return inScheduleFuncSignature
 }
 
 var nowFuncSignature = map[Domain]ast.ValueType{}
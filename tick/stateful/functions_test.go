@@ -888,6 +888,151 @@ func Test_StatelessFuncs(t *testing.T) {
 			args: []interface{}{""},
 			err:  errors.New("regexReplace expects exactly three arguments"),
 		},
+		{
+			name: "strSplit",
+			args: []interface{}{"a,b,c", ",", int64(1)},
+			exp:  "b",
+		},
+		{
+			name: "strSplit",
+			args: []interface{}{"a,b,c", ",", int64(-1)},
+			exp:  "c",
+		},
+		{
+			name: "strSplit",
+			args: []interface{}{"a,b,c", ",", int64(3)},
+			err:  errors.New("index 3 out of range for strSplit, string has 3 elements"),
+		},
+		{
+			name: "strJoin",
+			args: []interface{}{"/", "a", "b", "c"},
+			exp:  "a/b/c",
+		},
+		{
+			name: "strJoin",
+			args: []interface{}{"/"},
+			err:  errors.New("strJoin expects at least two arguments"),
+		},
+		{
+			name: "sprintf",
+			args: []interface{}{"%s=%d %.1f %v", "host", int64(3), 1.25, true},
+			exp:  "host=3 1.2 true",
+		},
+		{
+			name: "sprintf",
+			args: []interface{}{"static"},
+			exp:  "static",
+		},
+		{
+			name: "jsonExtract",
+			args: []interface{}{`{"a":{"b":[{"c":"x"},{"c":"y"}]}}`, "a.b[1].c"},
+			exp:  "y",
+		},
+		{
+			name: "jsonExtract",
+			args: []interface{}{`{"a":{"b":[{"c":"x"},{"c":"y"}]}}`, "a.b.0"},
+			exp:  `{"c":"x"}`,
+		},
+		{
+			name: "jsonExtract",
+			args: []interface{}{`{"a":1}`, "b"},
+			err:  errors.New(`jsonExtract: path "b" not found, no key "b"`),
+		},
+		{
+			name: "jsonExtract",
+			args: []interface{}{`{"a":`, "a"},
+			err:  errors.New("jsonExtract: invalid JSON: unexpected EOF"),
+		},
+		{
+			name: "jsonExtractFloat",
+			args: []interface{}{`{"load":[0.5,1.5]}`, "load[1]"},
+			exp:  1.5,
+		},
+		{
+			name: "jsonExtractInt",
+			args: []interface{}{`{"id":9007199254740993}`, "id"},
+			exp:  int64(9007199254740993),
+		},
+		{
+			name: "jsonExtractInt",
+			args: []interface{}{`{"id":1.5}`, "id"},
+			err:  errors.New(`jsonExtractInt: value at path "id" is not an integer`),
+		},
+		{
+			name: "jsonExtractBool",
+			args: []interface{}{`{"ok":true}`, "ok"},
+			exp:  true,
+		},
+		{
+			name: "jsonExtractBool",
+			args: []interface{}{`{"ok":"true"}`, "ok"},
+			err:  errors.New(`jsonExtractBool: value at path "ok" is not a boolean`),
+		},
+		{
+			name: "fnv",
+			args: []interface{}{"hello"},
+			exp:  int64(-6615550055289275125),
+		},
+		{
+			name: "hash",
+			args: []interface{}{"hello"},
+			exp:  int64(5915597009210138245),
+		},
+		{
+			name: "hash",
+			args: []interface{}{"hello", int64(10)},
+			exp:  int64(1),
+		},
+		{
+			name: "hash",
+			args: []interface{}{"hello", int64(0)},
+			err:  errors.New("hash buckets must be positive, got 0"),
+		},
+		{
+			name: "hour",
+			args: []interface{}{time.Date(2026, 3, 6, 23, 30, 0, 0, time.UTC)},
+			exp:  int64(23),
+		},
+		{
+			name: "hour",
+			args: []interface{}{time.Date(2026, 3, 6, 23, 30, 0, 0, time.UTC), "Europe/Berlin"},
+			exp:  int64(0),
+		},
+		{
+			name: "minute",
+			args: []interface{}{time.Date(2026, 3, 6, 23, 30, 0, 0, time.UTC), "Asia/Kolkata"},
+			exp:  int64(0),
+		},
+		{
+			name: "weekday",
+			args: []interface{}{time.Date(2026, 3, 6, 23, 30, 0, 0, time.UTC), "Europe/Berlin"},
+			exp:  int64(time.Saturday),
+		},
+		{
+			name: "day",
+			args: []interface{}{time.Date(2026, 12, 31, 23, 30, 0, 0, time.UTC), "Europe/Berlin"},
+			exp:  int64(1),
+		},
+		{
+			name: "month",
+			args: []interface{}{time.Date(2026, 12, 31, 23, 30, 0, 0, time.UTC), "Europe/Berlin"},
+			exp:  int64(1),
+		},
+		{
+			name: "year",
+			args: []interface{}{time.Date(2026, 12, 31, 23, 30, 0, 0, time.UTC), "Europe/Berlin"},
+			exp:  int64(2027),
+		},
+		{
+			name: "hour",
+			args: []interface{}{time.Date(2026, 3, 6, 23, 30, 0, 0, time.UTC), "Mars/Olympus_Mons"},
+			err:  errors.New(`unknown timezone "Mars/Olympus_Mons"`),
+		},
+		{
+			name: "hour",
+			args: []interface{}{},
+			err:  errors.New("hour expects one or two arguments"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -916,6 +1061,53 @@ func Test_StatelessFuncs(t *testing.T) {
 
 }
 
+type scheduleLookup map[string]bool
+
+func (l scheduleLookup) InSchedule(name string, t time.Time) (bool, error) {
+	in, ok := l[name]
+	if !ok {
+		return false, errors.New("unknown schedule")
+	}
+	return in && t.Hour() >= 9 && t.Hour() < 17, nil
+}
+
+func Test_InSchedule(t *testing.T) {
+	ts := time.Date(2026, 3, 6, 10, 0, 0, 0, time.UTC)
+
+	if _, err := NewInScheduleFunc(nil).Call("business-hours", ts); err == nil || err.Error() != "no schedules are available" {
+		t.Fatalf("unexpected error without schedules: %v", err)
+	}
+
+	f := NewInScheduleFunc(scheduleLookup{"business-hours": true, "maintenance": false})
+
+	testCases := []struct {
+		schedule string
+		time     time.Time
+		exp      bool
+		err      string
+	}{
+		{schedule: "business-hours", time: ts, exp: true},
+		{schedule: "business-hours", time: ts.Add(8 * time.Hour), exp: false},
+		{schedule: "maintenance", time: ts, exp: false},
+		{schedule: "missing", time: ts, err: "unknown schedule"},
+	}
+	for _, tc := range testCases {
+		result, err := f.Call(tc.schedule, tc.time)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: unexpected error got: %v exp: %s", tc.schedule, err, tc.err)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.schedule, err)
+			continue
+		}
+		if result != tc.exp {
+			t.Errorf("%s: unexpected result at %v got: %v exp: %v", tc.schedule, tc.time, result, tc.exp)
+		}
+	}
+}
+
 func Test_Rand_zeros(t *testing.T) {
 	f := NewRand()
 	// seed with a known value to force determinism.
@@ -958,3 +1150,145 @@ func Test_Rand_zeros(t *testing.T) {
 		}
 	}
 }
+
+func Test_StatefulFuncs(t *testing.T) {
+	type call struct {
+		args []interface{}
+		exp  interface{}
+		err  error
+	}
+	testCases := []struct {
+		name  string
+		calls []call
+	}{
+		{
+			name: "ewma",
+			calls: []call{
+				{args: []interface{}{10.0, 0.5}, exp: 10.0},
+				{args: []interface{}{20.0, 0.5}, exp: 15.0},
+				{args: []interface{}{5.0, 0.5}, exp: 10.0},
+				{args: []interface{}{5.0, 1.5}, err: errors.New("ewma alpha must be in the range (0, 1], got 1.5")},
+			},
+		},
+		{
+			name: "delta",
+			calls: []call{
+				{args: []interface{}{10.0}, exp: 0.0},
+				{args: []interface{}{12.5}, exp: 2.5},
+				{args: []interface{}{11.5}, exp: -1.0},
+				{args: []interface{}{int64(1)}, err: ErrNotFloat},
+			},
+		},
+		{
+			name: "lag",
+			calls: []call{
+				{args: []interface{}{"a", int64(2)}, exp: "a"},
+				{args: []interface{}{"b", int64(2)}, exp: "a"},
+				{args: []interface{}{"c", int64(2)}, exp: "a"},
+				{args: []interface{}{"d", int64(2)}, exp: "b"},
+				{args: []interface{}{"e", int64(3)}, err: errors.New("lag n must not change, got 3 after 2")},
+			},
+		},
+		{
+			name: "lag",
+			calls: []call{
+				{args: []interface{}{"a", int64(maxLag)}, exp: "a"},
+				{args: []interface{}{"b", int64(maxLag)}, exp: "a"},
+				{args: []interface{}{"c", int64(100000000000)}, err: errors.New("lag n must be between 1 and 100000, got 100000000000")},
+				{args: []interface{}{"d", int64(0)}, err: errors.New("lag n must be between 1 and 100000, got 0")},
+			},
+		},
+		{
+			name: "minSoFar",
+			calls: []call{
+				{args: []interface{}{3.0}, exp: 3.0},
+				{args: []interface{}{5.0}, exp: 3.0},
+				{args: []interface{}{-1.0}, exp: -1.0},
+			},
+		},
+		{
+			name: "maxSoFar",
+			calls: []call{
+				{args: []interface{}{3.0}, exp: 3.0},
+				{args: []interface{}{5.0}, exp: 5.0},
+				{args: []interface{}{-1.0}, exp: 5.0},
+			},
+		},
+		{
+			name: "cumsum",
+			calls: []call{
+				{args: []interface{}{1.5}, exp: 1.5},
+				{args: []interface{}{2.0}, exp: 3.5},
+				{args: []interface{}{-0.5}, exp: 3.0},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		f := NewFunctions()[tc.name]
+		for i, c := range tc.calls {
+			result, err := f.Call(c.args...)
+			if c.err != nil {
+				if err == nil {
+					t.Errorf("%s %d: expected error got: nil exp: %s", tc.name, i, c.err)
+				} else if got, exp := err.Error(), c.err.Error(); got != exp {
+					t.Errorf("%s %d: unexpected error\ngot:\n%s\nexp:\n%s", tc.name, i, got, exp)
+				}
+				continue
+			} else if err != nil {
+				t.Errorf("%s %d: unexpected error: %s", tc.name, i, err)
+				continue
+			}
+			if result != c.exp {
+				t.Errorf("%s %d: unexpected result\ngot: %+v\nexp: %+v", tc.name, i, result, c.exp)
+			}
+		}
+
+		// After a reset the function starts over.
+		f.Reset()
+		c := tc.calls[0]
+		if result, err := f.Call(c.args...); err != nil || result != c.exp {
+			t.Errorf("%s: unexpected result after reset\ngot: %+v %v\nexp: %+v", tc.name, result, err, c.exp)
+		}
+	}
+}
+
+func Test_LagGrowsLazily(t *testing.T) {
+	l := &lag{}
+	for i := 0; i < 3; i++ {
+		if _, err := l.Call(int64(i), int64(maxLag)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := cap(l.values); got >= maxLag {
+		t.Errorf("unexpected capacity of lag values after 3 values: %d", got)
+	}
+}
+
+func Test_Rate(t *testing.T) {
+	f := &rate{}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		t    time.Time
+		args []interface{}
+		exp  float64
+	}{
+		{t: start, args: []interface{}{10.0, time.Second}, exp: 0},
+		{t: start.Add(2 * time.Second), args: []interface{}{20.0, time.Second}, exp: 5},
+		// Points that are not later than the previous point keep the previous rate.
+		{t: start.Add(2 * time.Second), args: []interface{}{30.0, time.Second}, exp: 5},
+		{t: start.Add(4 * time.Second), args: []interface{}{10.0, time.Minute}, exp: -300},
+	}
+	for i, tc := range testCases {
+		result, err := f.CallAt(tc.t, tc.args...)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		if result != tc.exp {
+			t.Errorf("%d: unexpected result\ngot: %+v\nexp: %+v", i, result, tc.exp)
+		}
+	}
+	if _, err := f.Call(10.0, time.Second); err == nil {
+		t.Error("expected error calling rate without the time of the point")
+	}
+}
@@ -24,6 +24,9 @@ func (df DynamicFunc) Signature() map[Domain]ast.ValueType {
 	return df.Sig
 }
 
+// LibraryLoader returns the TICKscript of a library by name.
+type LibraryLoader func(name string) (string, error)
+
 // Special marker that a value is empty
 var empty = new(interface{})
 
@@ -33,6 +36,9 @@ type Scope struct {
 
 	dynamicMethods map[string]DynamicMethod
 	dynamicFuncs   map[string]*DynamicFunc
+
+	libraryLoader LibraryLoader
+	libraries     map[string]bool
 }
 
 // Initialize a new Scope object.
@@ -41,6 +47,7 @@ func NewScope() *Scope {
 		variables:      make(map[string]interface{}),
 		dynamicMethods: make(map[string]DynamicMethod),
 		dynamicFuncs:   make(map[string]*DynamicFunc),
+		libraries:      make(map[string]bool),
 	}
 }
 
@@ -98,3 +105,24 @@ func (s *Scope) SetDynamicFunc(name string, f *DynamicFunc) {
 func (s *Scope) DynamicFunc(name string) *DynamicFunc {
 	return s.dynamicFuncs[name]
 }
+
+func (s *Scope) SetLibraryLoader(l LibraryLoader) {
+	s.libraryLoader = l
+}
+
+// LoadLibrary returns the TICKscript of a library.
+// A library is only loaded once into a scope, loaded reports whether it already was.
+func (s *Scope) LoadLibrary(name string) (script string, loaded bool, err error) {
+	if s.libraries[name] {
+		return "", true, nil
+	}
+	if s.libraryLoader == nil {
+		return "", false, fmt.Errorf("cannot import library %q, no libraries are available", name)
+	}
+	script, err = s.libraryLoader(name)
+	if err != nil {
+		return "", false, err
+	}
+	s.libraries[name] = true
+	return script, false, nil
+}
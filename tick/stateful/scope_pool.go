@@ -19,6 +19,12 @@ type scopePool struct {
 
 // NewScopePool - creates new ScopePool for the given Node
 func NewScopePool(referenceVariables []string) ScopePool {
+	return NewScopePoolWithFuncs(referenceVariables, nil)
+}
+
+// NewScopePoolWithFuncs - creates new ScopePool for the given Node
+// whose scopes have the given dynamic functions
+func NewScopePoolWithFuncs(referenceVariables []string, funcs map[string]*DynamicFunc) ScopePool {
 	scopePool := &scopePool{
 		referenceVariables: referenceVariables,
 	}
@@ -29,6 +35,9 @@ func NewScopePool(referenceVariables []string) ScopePool {
 			for _, refVariable := range scopePool.referenceVariables {
 				scope.Set(refVariable, empty)
 			}
+			for name, f := range funcs {
+				scope.SetDynamicFunc(name, f)
+			}
 
 			return scope
 		},
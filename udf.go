@@ -163,6 +163,9 @@ type UDFProcess struct {
 	diag          udf.Diagnostic
 	timeout       time.Duration
 	abortCallback func()
+
+	// Optional store for blobs requested by the process.
+	BlobStore udf.BlobStore
 }
 
 func NewUDFProcess(
@@ -224,6 +227,7 @@ func (p *UDFProcess) Open() error {
 		p.abortCallback,
 		cmd.Kill,
 	)
+	p.server.BlobStore = p.BlobStore
 	if err := p.server.Start(); err != nil {
 		return err
 	}
@@ -287,6 +291,9 @@ type UDFSocket struct {
 	diag          udf.Diagnostic
 	timeout       time.Duration
 	abortCallback func()
+
+	// Optional store for blobs requested by the socket.
+	BlobStore udf.BlobStore
 }
 
 type Socket interface {
@@ -332,6 +339,7 @@ func (s *UDFSocket) Open() error {
 		s.abortCallback,
 		func() { s.socket.Close() },
 	)
+	s.server.BlobStore = s.BlobStore
 	return s.server.Start()
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
 )
 
@@ -17,6 +18,9 @@ import (
 // The Handler is called from a single goroutine, meaning methods will not be called concurrently.
 //
 // To write Points/Batches back to the Agent/Kapacitor use the Agent.Responses channel.
+//
+// Handler methods may load and store blobs in Kapacitor, for example a trained model,
+// using the Agent.GetBlob and Agent.PutBlob methods.
 type Handler interface {
 	// Return the InfoResponse. Describing the properties of this Handler
 	Info() (*InfoResponse, error)
@@ -58,6 +62,12 @@ type Agent struct {
 	writeErrC chan error
 	readErrC  chan error
 
+	// State for reading requests, requests are also read while waiting for a blob response.
+	reader  *bufio.Reader
+	buf     []byte
+	pending []*Request
+	blobID  uint64
+
 	// The handler for requests.
 	Handler Handler
 }
@@ -136,16 +146,20 @@ func (a *Agent) Wait() error {
 func (a *Agent) readLoop() error {
 	defer a.Handler.Stop()
 	defer a.in.Close()
-	in := bufio.NewReader(a.in)
-	var buf []byte
+	a.reader = bufio.NewReader(a.in)
 	request := &Request{}
 	for {
-		err := ReadMessage(&buf, in, request)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
+		// Handle any requests read while waiting for a blob response first.
+		if len(a.pending) > 0 {
+			request, a.pending = a.pending[0], a.pending[1:]
+		} else {
+			err := ReadMessage(&a.buf, a.reader, request)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
 		}
 
 		// Hand message to handler
@@ -234,3 +248,100 @@ func (a *Agent) forwardResponses() {
 		a.outResponses <- r
 	}
 }
+
+// GetBlob requests the content of a blob from Kapacitor.
+// If tag is not empty the blob the tag currently refers to is returned,
+// otherwise the blob with the given blobID is returned.
+// The ID of the returned blob is returned along with its content.
+//
+// GetBlob must only be called from within a Handler method.
+func (a *Agent) GetBlob(tag, blobID string) (string, []byte, error) {
+	id := a.nextBlobRequestID()
+	req, err := a.blobRequest(&Response{
+		Message: &Response_BlobGet{
+			BlobGet: &BlobGetRequest{
+				Id:     id,
+				Tag:    tag,
+				BlobID: blobID,
+			},
+		},
+	}, id)
+	if err != nil {
+		return "", nil, err
+	}
+	get := req.Message.(*Request_BlobGet).BlobGet
+	if get.Error != "" {
+		return "", nil, errors.New(get.Error)
+	}
+	return get.BlobID, get.Data, nil
+}
+
+// PutBlob stores data as a blob in Kapacitor and returns the ID of the blob.
+// If tag is not empty the tag is updated to refer to the stored blob.
+//
+// PutBlob must only be called from within a Handler method.
+func (a *Agent) PutBlob(tag string, data []byte) (string, error) {
+	id := a.nextBlobRequestID()
+	req, err := a.blobRequest(&Response{
+		Message: &Response_BlobPut{
+			BlobPut: &BlobPutRequest{
+				Id:   id,
+				Tag:  tag,
+				Data: data,
+			},
+		},
+	}, id)
+	if err != nil {
+		return "", err
+	}
+	put := req.Message.(*Request_BlobPut).BlobPut
+	if put.Error != "" {
+		return "", errors.New(put.Error)
+	}
+	return put.BlobID, nil
+}
+
+func (a *Agent) nextBlobRequestID() string {
+	a.blobID++
+	return strconv.FormatUint(a.blobID, 10)
+}
+
+// Send a blob request and read requests until the matching response arrives.
+// Keepalive requests are answered immediately, all other requests are
+// queued to be handled once the current Handler method returns.
+func (a *Agent) blobRequest(res *Response, id string) (*Request, error) {
+	if a.reader == nil {
+		return nil, errors.New("blob requests can only be made from within a Handler method")
+	}
+	a.outResponses <- res
+	for {
+		req := &Request{}
+		err := ReadMessage(&a.buf, a.reader, req)
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch msg := req.Message.(type) {
+		case *Request_Keepalive:
+			a.outResponses <- &Response{
+				Message: &Response_Keepalive{
+					Keepalive: &KeepaliveResponse{
+						Time: msg.Keepalive.Time,
+					},
+				},
+			}
+		case *Request_BlobGet:
+			if msg.BlobGet.Id == id {
+				return req, nil
+			}
+		case *Request_BlobPut:
+			if msg.BlobPut.Id == id {
+				return req, nil
+			}
+		default:
+			a.pending = append(a.pending, req)
+		}
+	}
+}
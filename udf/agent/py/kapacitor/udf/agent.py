@@ -43,6 +43,9 @@ logger = logging.getLogger()
 # The Handler is called from a single thread, meaning methods will not be called concurrently.
 #
 # To write Points/Batches back to the Agent/Kapacitor use the Agent.write_response method, which is thread safe.
+#
+# Handler methods may load and store blobs in Kapacitor, for example a trained model,
+# using the Agent.get_blob and Agent.put_blob methods.
 class Handler(object):
     def info(self):
         pass
@@ -75,6 +78,10 @@ class Agent(object):
         self.handler = handler
         self._write_lock = Lock()
 
+        # Requests read while waiting for a blob response
+        self._pending = []
+        self._blob_id = 0
+
     # Start the agent.
     # This method returns immediately
     def start(self):
@@ -107,16 +114,76 @@ class Agent(object):
         finally:
             self._write_lock.release()
 
+    # Request the content of a blob from Kapacitor.
+    # If tag is given the blob the tag currently refers to is returned,
+    # otherwise the blob with the given blob_id is returned.
+    # Returns a tuple of the ID of the returned blob and its content.
+    #
+    # This method must only be called from within a Handler method.
+    def get_blob(self, tag='', blob_id=''):
+        response = udf_pb2.Response()
+        response.blobGet.id = self._next_blob_id()
+        response.blobGet.tag = tag
+        response.blobGet.blobID = blob_id
+        request = self._blob_request(response, response.blobGet.id)
+        if request.blobGet.error:
+            raise Exception(request.blobGet.error)
+        return request.blobGet.blobID, request.blobGet.data
+
+    # Store data as a blob in Kapacitor and return the ID of the blob.
+    # If tag is given the tag is updated to refer to the stored blob.
+    #
+    # This method must only be called from within a Handler method.
+    def put_blob(self, data, tag=''):
+        response = udf_pb2.Response()
+        response.blobPut.id = self._next_blob_id()
+        response.blobPut.tag = tag
+        response.blobPut.data = data
+        request = self._blob_request(response, response.blobPut.id)
+        if request.blobPut.error:
+            raise Exception(request.blobPut.error)
+        return request.blobPut.blobID
+
+    def _next_blob_id(self):
+        self._blob_id += 1
+        return str(self._blob_id)
+
+    # Send a blob request and read requests until the matching response arrives.
+    # Keepalive requests are answered immediately, all other requests are
+    # queued to be handled once the current Handler method returns.
+    def _blob_request(self, response, id):
+        self.write_response(response, flush=True)
+        while True:
+            request = self._read_request(udf_pb2.Request())
+            msg = request.WhichOneof("message")
+            if msg == "keepalive":
+                response = udf_pb2.Response()
+                response.keepalive.time = request.keepalive.time
+                self.write_response(response, flush=True)
+            elif msg == "blobGet" or msg == "blobPut":
+                if getattr(request, msg).id == id:
+                    return request
+            else:
+                self._pending.append(request)
+
+    # Read a single request off the input stream
+    def _read_request(self, request):
+        size = decodeUvarint32(self._in)
+        data = self._in.read(size)
+        request.ParseFromString(data)
+        return request
+
     # Read requests off input stream
     def _read_loop(self):
         request = udf_pb2.Request()
         while True:
             msg = 'unknown'
             try:
-                size = decodeUvarint32(self._in)
-                data = self._in.read(size)
-
-                request.ParseFromString(data)
+                # Handle any requests read while waiting for a blob response first.
+                if self._pending:
+                    request = self._pending.pop(0)
+                else:
+                    self._read_request(request)
 
                 # use parsed message
                 msg = request.WhichOneof("message")
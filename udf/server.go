@@ -13,7 +13,10 @@ import (
 	"github.com/influxdata/kapacitor/udf/agent"
 )
 
-var ErrServerStopped = errors.New("server already stopped")
+var (
+	ErrServerStopped = errors.New("server already stopped")
+	ErrNoBlobStore   = errors.New("no blob store available")
+)
 
 type Diagnostic interface {
 	Error(msg string, err error, ctx ...keyvalue.T)
@@ -21,6 +24,17 @@ type Diagnostic interface {
 	UDFLog(msg string)
 }
 
+// BlobStore stores blobs on behalf of UDFs.
+type BlobStore interface {
+	// TagBlob returns the ID of the blob the tag currently refers to.
+	TagBlob(tag string) (string, error)
+	// BlobData returns the content of the blob with the given ID.
+	BlobData(id string) ([]byte, error)
+	// PutBlob stores data as a blob and returns its ID.
+	// If tag is not empty the tag is updated to refer to the blob.
+	PutBlob(tag string, data []byte) (string, error)
+}
+
 // Server provides an implementation for the core communication with UDFs.
 // The Server provides only a partial implementation of udf.Interface as
 // it is expected that setup and teardown will be necessary to create a Server.
@@ -41,7 +55,13 @@ type Diagnostic interface {
 //
 // Calling Init is required to process data.
 // The behavior is undefined if you send points/batches to the Server without calling Init.
+//
+// The UDF may request blobs from the Server at anytime,
+// these requests are served from the BlobStore if one is set before calling Start.
 type Server struct {
+	// Optional store for blobs requested by the UDF.
+	// If nil all blob requests fail.
+	BlobStore BlobStore
 
 	// If the processes is Aborted (via Keepalive timeout, etc.)
 	// then no more data will be read off the *In channels.
@@ -69,6 +89,11 @@ type Server struct {
 	requests      chan *agent.Request
 	requestsGroup sync.WaitGroup
 
+	// Responses to blob requests made by the UDF.
+	// These are not tracked by the requestsGroup since they are sent from the read goroutine.
+	blobResponses chan *agent.Request
+	writeDone     chan struct{}
+
 	keepalive        chan int64
 	keepaliveTimeout time.Duration
 
@@ -112,6 +137,7 @@ func NewServer(
 		out:              out,
 		diag:             d,
 		requests:         make(chan *agent.Request),
+		blobResponses:    make(chan *agent.Request),
 		keepalive:        make(chan int64, 1),
 		keepaliveTimeout: timeout,
 		abortCallback:    abortCallback,
@@ -150,9 +176,11 @@ func (s *Server) Start() error {
 	s.stopping = make(chan struct{})
 	s.aborted = false
 	s.aborting = make(chan struct{})
+	s.writeDone = make(chan struct{})
 
 	s.ioGroup.Add(1)
 	go func() {
+		defer close(s.writeDone)
 		err := s.writeData()
 		if err != nil {
 			s.setError(err)
@@ -486,6 +514,11 @@ func (s *Server) writeData() error {
 			} else {
 				s.requests = nil
 			}
+		case req := <-s.blobResponses:
+			err := s.writeRequest(req)
+			if err != nil {
+				return err
+			}
 		case <-s.aborting:
 			return s.err
 		}
@@ -696,6 +729,10 @@ func (s *Server) handleResponse(response *agent.Response) error {
 	case *agent.Response_Error:
 		s.diag.Error("received error message", errors.New(msg.Error.Error))
 		return errors.New(msg.Error.Error)
+	case *agent.Response_BlobGet:
+		return s.handleBlobGet(msg.BlobGet)
+	case *agent.Response_BlobPut:
+		return s.handleBlobPut(msg.BlobPut)
 	case *agent.Response_Begin:
 		s.begin = msg.Begin
 		s.points = make([]edge.BatchPointMessage, 0, msg.Begin.Size)
@@ -758,3 +795,72 @@ func (s *Server) handleResponse(response *agent.Response) error {
 	}
 	return nil
 }
+
+func (s *Server) handleBlobGet(get *agent.BlobGetRequest) error {
+	res := &agent.BlobGetResponse{
+		Id: get.Id,
+	}
+	id, data, err := s.getBlob(get.Tag, get.BlobID)
+	if err != nil {
+		s.diag.Error("failed to get blob", err, keyvalue.KV("tag", get.Tag), keyvalue.KV("blob", get.BlobID))
+		res.Error = err.Error()
+	} else {
+		res.BlobID = id
+		res.Data = data
+	}
+	return s.writeBlobResponse(&agent.Request{
+		Message: &agent.Request_BlobGet{BlobGet: res},
+	})
+}
+
+func (s *Server) getBlob(tag, id string) (string, []byte, error) {
+	if s.BlobStore == nil {
+		return "", nil, ErrNoBlobStore
+	}
+	if tag != "" {
+		var err error
+		id, err = s.BlobStore.TagBlob(tag)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if id == "" {
+		return "", nil, errors.New("must provide a tag or blob ID")
+	}
+	data, err := s.BlobStore.BlobData(id)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, data, nil
+}
+
+func (s *Server) handleBlobPut(put *agent.BlobPutRequest) error {
+	res := &agent.BlobPutResponse{
+		Id: put.Id,
+	}
+	var err error
+	if s.BlobStore == nil {
+		err = ErrNoBlobStore
+	} else {
+		res.BlobID, err = s.BlobStore.PutBlob(put.Tag, put.Data)
+	}
+	if err != nil {
+		s.diag.Error("failed to put blob", err, keyvalue.KV("tag", put.Tag))
+		res.Error = err.Error()
+	}
+	return s.writeBlobResponse(&agent.Request{
+		Message: &agent.Request_BlobPut{BlobPut: res},
+	})
+}
+
+// Send a response to a blob request made by the UDF.
+// If the Server is no longer writing to the UDF the response is dropped.
+func (s *Server) writeBlobResponse(req *agent.Request) error {
+	select {
+	case s.blobResponses <- req:
+	case <-s.writeDone:
+	case <-s.aborting:
+		return s.err
+	}
+	return nil
+}
@@ -1,9 +1,12 @@
 package udf_test
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -547,3 +550,160 @@ func TestUDF_StartInitBatchStop(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+type testBlobStore struct {
+	blobs map[string][]byte
+	tags  map[string]string
+}
+
+func (b *testBlobStore) TagBlob(tag string) (string, error) {
+	id, ok := b.tags[tag]
+	if !ok {
+		return "", fmt.Errorf("no tag %q", tag)
+	}
+	return id, nil
+}
+
+func (b *testBlobStore) BlobData(id string) ([]byte, error) {
+	data, ok := b.blobs[id]
+	if !ok {
+		return nil, fmt.Errorf("no blob %q", id)
+	}
+	return data, nil
+}
+
+func (b *testBlobStore) PutBlob(tag string, data []byte) (string, error) {
+	id := fmt.Sprintf("blob%d", len(b.blobs))
+	b.blobs[id] = data
+	if tag != "" {
+		b.tags[tag] = id
+	}
+	return id, nil
+}
+
+// blobHandler loads a model at Init and stores a retrained model on the first point.
+type blobHandler struct {
+	agent *agent.Agent
+	model []byte
+	err   error
+}
+
+func (h *blobHandler) Info() (*agent.InfoResponse, error) {
+	return &agent.InfoResponse{Wants: agent.EdgeType_STREAM, Provides: agent.EdgeType_STREAM}, nil
+}
+
+func (h *blobHandler) Init(r *agent.InitRequest) (*agent.InitResponse, error) {
+	_, model, err := h.agent.GetBlob("model", "")
+	if err != nil {
+		return &agent.InitResponse{Success: false, Error: err.Error()}, nil
+	}
+	h.model = model
+	return &agent.InitResponse{Success: true}, nil
+}
+
+func (h *blobHandler) Snapshot() (*agent.SnapshotResponse, error) {
+	return &agent.SnapshotResponse{}, nil
+}
+
+func (h *blobHandler) Restore(*agent.RestoreRequest) (*agent.RestoreResponse, error) {
+	return &agent.RestoreResponse{Success: true}, nil
+}
+
+func (h *blobHandler) BeginBatch(*agent.BeginBatch) error { return nil }
+
+func (h *blobHandler) Point(p *agent.Point) error {
+	h.model = append(h.model, " retrained"...)
+	if _, err := h.agent.PutBlob("model", h.model); err != nil {
+		h.err = err
+	}
+	h.agent.Responses <- &agent.Response{
+		Message: &agent.Response_Point{Point: p},
+	}
+	return nil
+}
+
+func (h *blobHandler) EndBatch(*agent.EndBatch) error { return nil }
+
+func (h *blobHandler) Stop() {
+	close(h.agent.Responses)
+}
+
+// newBlobAgent connects a Server to an Agent using a blobHandler.
+// The returned channel receives the result of waiting for the Agent.
+func newBlobAgent(t *testing.T, blobs udf.BlobStore) (*udf.Server, *blobHandler, <-chan error) {
+	inr, inw := io.Pipe()
+	outr, outw := io.Pipe()
+	a := agent.New(inr, outw)
+	h := &blobHandler{agent: a}
+	a.Handler = h
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitC := make(chan error, 1)
+	go func() {
+		waitC <- a.Wait()
+	}()
+	d := kapacitorDiag.WithNodeContext("TestUDF_Blob")
+	s := udf.NewServer("testTask", "testNode", bufio.NewReader(outr), inw, d, 0, nil, nil)
+	s.BlobStore = blobs
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return s, h, waitC
+}
+
+func TestUDF_Blob(t *testing.T) {
+	blobs := &testBlobStore{
+		blobs: map[string][]byte{"blob0": []byte("model v1")},
+		tags:  map[string]string{"model": "blob0"},
+	}
+	s, h, waitC := newBlobAgent(t, blobs)
+	if err := s.Init(nil); err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := "model v1", string(h.model); got != exp {
+		t.Errorf("unexpected model loaded at init got %q exp %q", got, exp)
+	}
+
+	p := edge.NewPointMessage(
+		"test",
+		"db",
+		"rp",
+		models.Dimensions{},
+		models.Fields{"f1": 1.0},
+		models.Tags{"t1": "v1"},
+		time.Date(1971, 1, 1, 0, 0, 0, 0, time.UTC),
+	)
+	s.In() <- p
+	<-s.Out()
+	if h.err != nil {
+		t.Fatal(h.err)
+	}
+	if exp, got := "model v1 retrained", string(blobs.blobs[blobs.tags["model"]]); got != exp {
+		t.Errorf("unexpected stored model got %q exp %q", got, exp)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Error(err)
+	}
+	if err := <-waitC; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUDF_BlobNoStore(t *testing.T) {
+	s, _, waitC := newBlobAgent(t, nil)
+	err := s.Init(nil)
+	if err == nil {
+		t.Fatal("expected init error without a blob store")
+	}
+	if exp := udf.ErrNoBlobStore.Error(); !strings.Contains(err.Error(), exp) {
+		t.Errorf("unexpected init error got %q exp %q", err, exp)
+	}
+	if err := s.Stop(); err != nil {
+		t.Error(err)
+	}
+	if err := <-waitC; err != nil {
+		t.Error(err)
+	}
+}
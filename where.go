@@ -30,7 +30,7 @@ func newWhereNode(et *ExecutingTask, n *pipeline.WhereNode, d NodeDiagnostic) (w
 		return nil, fmt.Errorf("Failed to compile expression in where clause: %v", err)
 	}
 	wn.expression = expr
-	wn.scopePool = stateful.NewScopePool(ast.FindReferenceVariables(n.Lambda.Expression))
+	wn.scopePool = et.tm.newScopePool(ast.FindReferenceVariables(n.Lambda.Expression))
 
 	wn.runF = wn.runWhere
 	if n.Lambda == nil {
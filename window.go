@@ -13,12 +13,32 @@ import (
 type WindowNode struct {
 	node
 	w *pipeline.WindowNode
+
+	states groupSnapshotter[windowState, windowGroup]
+}
+
+// windowGroup is a window of a single group.
+type windowGroup interface {
+	edge.ForwardReceiver
+	snapshot() windowState
+	restore(windowState)
+}
+
+// windowState is the snapshotted state of a window.
+type windowState struct {
+	// NextEmit is used by windows by time.
+	NextEmit time.Time
+	// NextEmitCount and Count are used by windows by count.
+	NextEmitCount int
+	Count         int
+
+	Points []pointSnapshot
 }
 
 // Create a new  WindowNode, which windows data for a period of time and emits the window.
 func newWindowNode(et *ExecutingTask, n *pipeline.WindowNode, d NodeDiagnostic) (*WindowNode, error) {
-	if n.Period == 0 && n.PeriodCount == 0 {
-		return nil, errors.New("window node must have either a non zero period or non zero period count")
+	if n.Period == 0 && n.PeriodCount == 0 && n.Gap == 0 {
+		return nil, errors.New("window node must have either a non zero period, non zero period count or non zero gap")
 	}
 	wn := &WindowNode{
 		w:    n,
@@ -28,7 +48,12 @@ func newWindowNode(et *ExecutingTask, n *pipeline.WindowNode, d NodeDiagnostic)
 	return wn, nil
 }
 
-func (n *WindowNode) runWindow([]byte) (err error) {
+func (n *WindowNode) runWindow(snapshot []byte) (err error) {
+	if snapshot != nil {
+		if err := n.restore(snapshot); err != nil {
+			n.diag.Error("failed to restore window state", err)
+		}
+	}
 	consumer := edge.NewGroupedConsumer(n.ins[0], n)
 	n.statMap.Set(statCardinalityGauge, consumer.CardinalityVar())
 	err = consumer.Consume()
@@ -42,15 +67,23 @@ func (n *WindowNode) NewGroup(group edge.GroupInfo, first edge.PointMeta) (edge.
 	}
 	return edge.NewReceiverFromForwardReceiverWithStats(
 		n.outs,
-		edge.NewTimedForwardReceiver(n.timer, r),
+		edge.NewTimedForwardReceiver(n.timer, n.states.newGroup(group.ID, r)),
 	), nil
 }
 
+func (n *WindowNode) snapshot() ([]byte, error) {
+	return n.states.snapshot()
+}
+
+func (n *WindowNode) restore(snapshot []byte) error {
+	return n.states.restore(snapshot)
+}
+
 func (n *WindowNode) DeleteGroup(group models.GroupID) {
 	// Nothing to do
 }
 
-func (n *WindowNode) newWindow(group edge.GroupInfo, first edge.PointMeta) (edge.ForwardReceiver, error) {
+func (n *WindowNode) newWindow(group edge.GroupInfo, first edge.PointMeta) (windowGroup, error) {
 	switch {
 	case n.w.Period != 0:
 		return newWindowByTime(
@@ -72,8 +105,16 @@ func (n *WindowNode) newWindow(group edge.GroupInfo, first edge.PointMeta) (edge
 			n.w.FillPeriodFlag,
 			n.diag,
 		), nil
+	case n.w.Gap != 0:
+		return newWindowBySession(
+			first.Name(),
+			group,
+			n.w.Gap,
+			n.w.MaxDuration,
+			n.diag,
+		), nil
 	default:
-		return nil, errors.New("unreachable code, window node should have a non-zero period or period count")
+		return nil, errors.New("unreachable code, window node should have a non-zero period, period count or gap")
 	}
 }
 
@@ -137,6 +178,34 @@ func newWindowByTime(
 	}
 }
 
+func (w *windowByTime) snapshot() windowState {
+	points := w.buf.points()
+	state := windowState{
+		NextEmit: w.nextEmit,
+		Points:   make([]pointSnapshot, len(points)),
+	}
+	for i, p := range points {
+		state.Points[i] = newPointSnapshot(p)
+	}
+	return state
+}
+
+func (w *windowByTime) restore(state windowState) {
+	w.nextEmit = state.NextEmit
+	w.buf = &windowTimeBuffer{diag: w.diag}
+	for _, p := range state.Points {
+		w.buf.insert(edge.NewPointMessage(
+			w.name,
+			"",
+			"",
+			w.group.Dimensions,
+			p.Fields,
+			p.Tags,
+			p.Time,
+		))
+	}
+}
+
 func (w *windowByTime) BeginBatch(edge.BeginBatchMessage) (edge.Message, error) {
 	return nil, errors.New("window does not support batch data")
 }
@@ -396,6 +465,36 @@ func newWindowByCount(
 		diag:     d,
 	}
 }
+func (w *windowByCount) snapshot() windowState {
+	points := w.points()
+	state := windowState{
+		NextEmitCount: w.nextEmit,
+		Count:         w.count,
+		Points:        make([]pointSnapshot, len(points)),
+	}
+	for i, p := range points {
+		state.Points[i] = newPointSnapshot(p)
+	}
+	return state
+}
+
+func (w *windowByCount) restore(state windowState) {
+	points := state.Points
+	// Only keep the most recent points if the period has shrunk.
+	if len(points) > w.period {
+		points = points[len(points)-w.period:]
+	}
+	w.buf = make([]edge.BatchPointMessage, w.period)
+	for i, p := range points {
+		w.buf[i] = p.batchPoint()
+	}
+	w.start = 0
+	w.size = len(points)
+	w.stop = w.size % w.period
+	w.count = state.Count
+	w.nextEmit = state.NextEmitCount
+}
+
 func (w *windowByCount) BeginBatch(edge.BeginBatchMessage) (edge.Message, error) {
 	return nil, errors.New("window does not support batch data")
 }
@@ -468,3 +567,136 @@ func (w *windowByCount) points() []edge.BatchPointMessage {
 	}
 	return points
 }
+
+// Names of the fields with the start and end times of the session of a point.
+const (
+	sessionStartField = "session_start"
+	sessionEndField   = "session_end"
+)
+
+// windowBySession windows the points of a group into sessions
+// that end after a gap without points or after a maximum duration.
+type windowBySession struct {
+	name  string
+	group edge.GroupInfo
+
+	buf []edge.BatchPointMessage
+	// start and end are the times of the first and last point of the session.
+	start time.Time
+	end   time.Time
+
+	gap         time.Duration
+	maxDuration time.Duration
+
+	diag NodeDiagnostic
+}
+
+func newWindowBySession(
+	name string,
+	group edge.GroupInfo,
+	gap,
+	maxDuration time.Duration,
+	d NodeDiagnostic,
+) *windowBySession {
+	return &windowBySession{
+		name:        name,
+		group:       group,
+		gap:         gap,
+		maxDuration: maxDuration,
+		diag:        d,
+	}
+}
+
+func (w *windowBySession) snapshot() windowState {
+	state := windowState{
+		Points: make([]pointSnapshot, len(w.buf)),
+	}
+	for i, p := range w.buf {
+		state.Points[i] = newPointSnapshot(p)
+	}
+	return state
+}
+
+func (w *windowBySession) restore(state windowState) {
+	w.buf = nil
+	for _, p := range state.Points {
+		w.insert(p.batchPoint())
+	}
+}
+
+func (w *windowBySession) BeginBatch(edge.BeginBatchMessage) (edge.Message, error) {
+	return nil, errors.New("window does not support batch data")
+}
+func (w *windowBySession) BatchPoint(edge.BatchPointMessage) (edge.Message, error) {
+	return nil, errors.New("window does not support batch data")
+}
+func (w *windowBySession) EndBatch(edge.EndBatchMessage) (edge.Message, error) {
+	return nil, errors.New("window does not support batch data")
+}
+
+// Barrier ends the session if the group has been idle for longer than the gap,
+// or if the session has lasted for the maximum duration.
+func (w *windowBySession) Barrier(b edge.BarrierMessage) (edge.Message, error) {
+	if w.ended(b.Time()) {
+		return w.batch(), nil
+	}
+	return b, nil
+}
+func (w *windowBySession) DeleteGroup(d edge.DeleteGroupMessage) (edge.Message, error) {
+	return d, nil
+}
+func (w *windowBySession) Done() {}
+
+func (w *windowBySession) Point(p edge.PointMessage) (msg edge.Message, err error) {
+	if w.ended(p.Time()) {
+		msg = w.batch()
+	}
+	w.insert(edge.BatchPointFromPoint(p))
+	return
+}
+
+// ended reports whether the current session has ended at time t.
+func (w *windowBySession) ended(t time.Time) bool {
+	if len(w.buf) == 0 {
+		return false
+	}
+	if t.Sub(w.end) > w.gap {
+		return true
+	}
+	return w.maxDuration != 0 && t.Sub(w.start) >= w.maxDuration
+}
+
+func (w *windowBySession) insert(p edge.BatchPointMessage) {
+	if len(w.buf) == 0 {
+		w.start = p.Time()
+		w.end = p.Time()
+	} else if p.Time().After(w.end) {
+		w.end = p.Time()
+	}
+	w.buf = append(w.buf, p)
+}
+
+// batch returns the current session as a batch message and starts a new session.
+func (w *windowBySession) batch() edge.BufferedBatchMessage {
+	start := w.start.UnixNano()
+	end := w.end.UnixNano()
+	points := make([]edge.BatchPointMessage, len(w.buf))
+	for i, p := range w.buf {
+		fields := p.Fields().Copy()
+		fields[sessionStartField] = start
+		fields[sessionEndField] = end
+		points[i] = edge.NewBatchPointMessage(fields, p.Tags(), p.Time())
+	}
+	w.buf = nil
+	return edge.NewBufferedBatchMessage(
+		edge.NewBeginBatchMessage(
+			w.name,
+			w.group.Tags,
+			w.group.Dimensions.ByName,
+			w.end,
+			len(points),
+		),
+		points,
+		edge.NewEndBatchMessage(),
+	)
+}
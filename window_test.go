@@ -195,3 +195,98 @@ func TestWindowBufferByCount(t *testing.T) {
 		}
 	}
 }
+
+func TestWindowBySession(t *testing.T) {
+	group := edge.GroupInfo{
+		Tags:       models.Tags{"device": "d1"},
+		Dimensions: models.Dimensions{TagNames: []string{"device"}},
+	}
+	point := func(sec int64) edge.PointMessage {
+		return edge.NewPointMessage(
+			"activity", "db", "rp",
+			group.Dimensions,
+			models.Fields{"value": 1.0},
+			group.Tags,
+			time.Unix(sec, 0),
+		)
+	}
+	barrier := func(sec int64) edge.BarrierMessage {
+		return edge.NewBarrierMessage(group, time.Unix(sec, 0))
+	}
+	// session checks that msg is a session of the points with the times.
+	session := func(t *testing.T, msg edge.Message, times ...int64) {
+		t.Helper()
+		b, ok := msg.(edge.BufferedBatchMessage)
+		if !ok {
+			t.Fatalf("expected a batch, got %v", msg)
+		}
+		points := b.Points()
+		if len(points) != len(times) {
+			t.Fatalf("unexpected number of points: got %d exp %d", len(points), len(times))
+		}
+		start, end := time.Unix(times[0], 0), time.Unix(times[len(times)-1], 0)
+		if !b.Begin().Time().Equal(end) {
+			t.Errorf("unexpected batch time: got %v exp %v", b.Begin().Time(), end)
+		}
+		for i, p := range points {
+			if !p.Time().Equal(time.Unix(times[i], 0)) {
+				t.Errorf("unexpected time of point %d: %v", i, p.Time())
+			}
+			if got := p.Fields()["session_start"]; got != start.UnixNano() {
+				t.Errorf("unexpected session start of point %d: %v", i, got)
+			}
+			if got := p.Fields()["session_end"]; got != end.UnixNano() {
+				t.Errorf("unexpected session end of point %d: %v", i, got)
+			}
+		}
+	}
+
+	t.Run("gap", func(t *testing.T) {
+		w := newWindowBySession("activity", group, 10*time.Second, 0, nil)
+		for _, sec := range []int64{0, 5, 15} {
+			msg, err := w.Point(point(sec))
+			if err != nil || msg != nil {
+				t.Fatalf("unexpected emit at %d: %v %v", sec, msg, err)
+			}
+		}
+		msg, err := w.Point(point(30))
+		if err != nil {
+			t.Fatal(err)
+		}
+		session(t, msg, 0, 5, 15)
+
+		// An idle barrier ends the session once the gap has passed.
+		if msg, _ := w.Barrier(barrier(40)); msg.Type() != edge.Barrier {
+			t.Fatalf("unexpected emit before the gap passed: %v", msg)
+		}
+		msg, err = w.Barrier(barrier(41))
+		if err != nil {
+			t.Fatal(err)
+		}
+		session(t, msg, 30)
+
+		// Barriers without a session are forwarded.
+		if msg, _ := w.Barrier(barrier(100)); msg.Type() != edge.Barrier {
+			t.Errorf("expected barrier, got %v", msg)
+		}
+	})
+	t.Run("max duration", func(t *testing.T) {
+		w := newWindowBySession("activity", group, 10*time.Second, 20*time.Second, nil)
+		for _, sec := range []int64{0, 8, 16} {
+			if msg, _ := w.Point(point(sec)); msg != nil {
+				t.Fatalf("unexpected emit at %d: %v", sec, msg)
+			}
+		}
+		msg, err := w.Point(point(20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		session(t, msg, 0, 8, 16)
+
+		msg, err = w.Barrier(barrier(40))
+		if err != nil {
+			t.Fatal(err)
+		}
+		session(t, msg, 20)
+	})
+}